@@ -56,7 +56,9 @@ var testFlags = map[string]interface{}{
 	ADUserFlag:                       "ad-user",
 	ADWebhookPasswordFlag:            "ad-wh-pass",
 	ADWebhookUserFlag:                "ad-wh-user",
+	APIJobRetentionFlag:              "72h",
 	AtlantisURLFlag:                  "url",
+	AuditLogFileFlag:                 "/path/to/audit.jsonl",
 	AllowCommandsFlag:                "version,plan,unlock,import,approve_policies", // apply is disabled by DisableApply
 	AllowForkPRsFlag:                 true,
 	AllowRepoConfigFlag:              true,
@@ -67,6 +69,7 @@ var testFlags = map[string]interface{}{
 	BitbucketUserFlag:                "bitbucket-user",
 	BitbucketWebhookSecretFlag:       "bitbucket-secret",
 	CheckoutStrategyFlag:             CheckoutStrategyMerge,
+	CostEstimateCommandFlag:          "infracost breakdown --path $SHOWFILE --format json",
 	DataDirFlag:                      "/path",
 	DefaultTFVersionFlag:             "v0.11.0",
 	DisableApplyAllFlag:              true,
@@ -74,6 +77,11 @@ var testFlags = map[string]interface{}{
 	DisableMarkdownFoldingFlag:       true,
 	DisableRepoLockingFlag:           true,
 	DiscardApprovalOnPlanFlag:        true,
+	GiteaBaseURLFlag:                 "https://gitea-base-url.com",
+	GiteaTokenFlag:                   "gitea-token",
+	GiteaUserFlag:                    "gitea-user",
+	GiteaWebhookSecretFlag:           "gitea-secret",
+	GHChecksFlag:                     false,
 	GHHostnameFlag:                   "ghhostname",
 	GHTokenFlag:                      "token",
 	GHUserFlag:                       "user",
@@ -83,17 +91,37 @@ var testFlags = map[string]interface{}{
 	GHAppSlugFlag:                    "atlantis",
 	GHOrganizationFlag:               "",
 	GHWebhookSecretFlag:              "secret",
+	EnableEventQueueFlag:             true,
+	EventQueueRecoveryFlag:           "replay",
+	EventQueueWorkersFlag:            5,
 	GitlabHostnameFlag:               "gitlab-hostname",
 	GitlabTokenFlag:                  "gitlab-token",
 	GitlabUserFlag:                   "gitlab-user",
 	GitlabWebhookSecretFlag:          "gitlab-secret",
+	JobOutputMaxSizeMBFlag:           512,
+	JobOutputRetentionDaysFlag:       14,
+	JobOutputStoreFlag:               "disk",
 	LockingDBType:                    "boltdb",
 	LogLevelFlag:                     "debug",
 	MarkdownTemplateOverridesDirFlag: "/path2",
 	StatsNamespace:                   "atlantis",
 	AllowDraftPRs:                    true,
 	PortFlag:                         8181,
+	PostgresDSNFlag:                  "postgres://localhost:5432/atlantis",
+	MaxConcurrentRunsFlag:            4,
+	MaxConcurrentRunsPerRepoFlag:     2,
 	ParallelPoolSize:                 100,
+	PlanTTLFlag:                      "24h",
+	PlanStoreFlag:                    "s3",
+	PlanStoreDirFlag:                 "/path/to/plans",
+	PlanStoreS3BucketFlag:            "atlantis-plans",
+	PlanStoreS3PrefixFlag:            "atlantis/",
+	PlanStoreS3RegionFlag:            "eu-west-1",
+	PlanStoreS3EndpointFlag:          "https://minio.example.com",
+	PlanStoreS3AccessKeyIDFlag:       "access-key-id",
+	PlanStoreS3SecretAccessKeyFlag:   "secret-access-key",
+	PlanStoreS3UsePathStyleFlag:      true,
+	PolicyExecutorFlag:               "rego",
 	RepoAllowlistFlag:                "github.com/runatlantis/atlantis",
 	RequireApprovalFlag:              true,
 	RequireMergeableFlag:             true,
@@ -106,11 +134,23 @@ var testFlags = map[string]interface{}{
 	SSLCertFileFlag:                  "cert-file",
 	SSLKeyFileFlag:                   "key-file",
 	RestrictFileList:                 false,
+	RestrictTargetedApplies:          true,
+	TargetedApplyAllowlistFlag:       "alice,bob",
 	TFDownloadURLFlag:                "https://my-hostname.com",
 	TFEHostnameFlag:                  "my-hostname",
 	TFELocalExecutionModeFlag:        true,
 	TFETokenFlag:                     "my-token",
 	VCSStatusName:                    "my-status",
+	WebOIDCIssuerURLFlag:             "https://accounts.example.com",
+	WebOIDCClientIDFlag:              "oidc-client-id",
+	WebOIDCClientSecretFlag:          "oidc-client-secret",
+	WebOIDCScopesFlag:                "openid,email,groups",
+	WebOIDCUsernameClaimFlag:         "preferred_username",
+	WebOIDCGroupsClaimFlag:           "roles",
+	WebOIDCViewGroupsFlag:            "engineering",
+	WebOIDCDiscardLockGroupsFlag:     "platform",
+	WebOIDCApplyLockGroupsFlag:       "sre",
+	WebOIDCSessionSecretFlag:         "session-secret",
 	WriteGitCredsFlag:                true,
 	DisableAutoplanFlag:              true,
 	EnablePolicyChecksFlag:           false,
@@ -315,6 +355,236 @@ func TestExecute_ValidateCheckoutStrategy(t *testing.T) {
 	ErrEquals(t, "invalid checkout strategy: not one of branch or merge", err)
 }
 
+func TestExecute_ValidateJobOutputStore(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		JobOutputStoreFlag: "invalid",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "invalid job output store: not one of memory, disk or redis", err)
+}
+
+func TestExecute_ValidatePlanStore(t *testing.T) {
+	cases := []struct {
+		description string
+		flags       map[string]interface{}
+		expErr      string
+	}{
+		{
+			"invalid",
+			map[string]interface{}{
+				PlanStoreFlag: "invalid",
+			},
+			"invalid plan store: not one of local, filesystem or s3",
+		},
+		{
+			"filesystem without dir",
+			map[string]interface{}{
+				PlanStoreFlag: "filesystem",
+			},
+			"--plan-store-dir must be set when --plan-store is filesystem",
+		},
+		{
+			"s3 without bucket",
+			map[string]interface{}{
+				PlanStoreFlag: "s3",
+			},
+			"--plan-store-s3-bucket must be set when --plan-store is s3",
+		},
+		{
+			"s3 with access key ID but no secret access key",
+			map[string]interface{}{
+				PlanStoreFlag:              "s3",
+				PlanStoreS3BucketFlag:      "plans",
+				PlanStoreS3AccessKeyIDFlag: "id",
+			},
+			"--plan-store-s3-access-key-id and --plan-store-s3-secret-access-key must be set together",
+		},
+		{
+			"s3 with invalid endpoint",
+			map[string]interface{}{
+				PlanStoreFlag:                  "s3",
+				PlanStoreS3BucketFlag:          "plans",
+				PlanStoreS3AccessKeyIDFlag:     "id",
+				PlanStoreS3SecretAccessKeyFlag: "secret",
+				PlanStoreS3EndpointFlag:        "localhost:9000",
+			},
+			`--plan-store-s3-endpoint must have http:// or https://, got "localhost:9000"`,
+		},
+		{
+			"valid s3",
+			map[string]interface{}{
+				PlanStoreFlag:                  "s3",
+				PlanStoreS3BucketFlag:          "plans",
+				PlanStoreS3AccessKeyIDFlag:     "id",
+				PlanStoreS3SecretAccessKeyFlag: "secret",
+				PlanStoreS3EndpointFlag:        "http://localhost:9000",
+			},
+			"",
+		},
+		{
+			"valid s3 with default credentials",
+			map[string]interface{}{
+				PlanStoreFlag:         "s3",
+				PlanStoreS3BucketFlag: "plans",
+			},
+			"",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := setupWithDefaults(c.flags, t).Execute()
+			if c.expErr == "" {
+				Ok(t, err)
+				return
+			}
+			ErrEquals(t, c.expErr, err)
+		})
+	}
+}
+
+func TestExecute_ValidateEventQueueRecovery(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		EventQueueRecoveryFlag: "invalid",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "invalid event queue recovery: not one of fail or replay", err)
+}
+
+func TestExecute_ValidatePolicyExecutor(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		PolicyExecutorFlag: "invalid",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "invalid policy executor: not one of conftest or rego", err)
+}
+
+func TestExecute_ValidateGithubChecks(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		GHChecksFlag: true,
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--gh-checks requires --gh-app-id to be set", err)
+}
+
+func TestExecute_ValidateMaxConcurrentRuns(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		MaxConcurrentRunsFlag: -1,
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--max-concurrent-runs can't be negative", err)
+}
+
+func TestExecute_ValidateAPIJobRetention(t *testing.T) {
+	cases := map[string]string{
+		"7d":    "invalid --api-job-retention: time: unknown unit \"d\" in duration \"7d\"",
+		"-168h": "--api-job-retention can't be negative",
+	}
+	for retention, expErr := range cases {
+		t.Run(retention, func(t *testing.T) {
+			c := setupWithDefaults(map[string]interface{}{
+				APIJobRetentionFlag: retention,
+			}, t)
+			err := c.Execute()
+			ErrEquals(t, expErr, err)
+		})
+	}
+}
+
+func TestExecute_ValidatePlanTTL(t *testing.T) {
+	cases := map[string]string{
+		"1d":   "invalid --plan-ttl: time: unknown unit \"d\" in duration \"1d\"",
+		"-24h": "--plan-ttl can't be negative",
+	}
+	for planTTL, expErr := range cases {
+		t.Run(planTTL, func(t *testing.T) {
+			c := setupWithDefaults(map[string]interface{}{
+				PlanTTLFlag: planTTL,
+			}, t)
+			err := c.Execute()
+			ErrEquals(t, expErr, err)
+		})
+	}
+}
+
+func TestExecute_ValidateTargetedApplyAllowlist(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		TargetedApplyAllowlistFlag: "alice",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--targeted-apply-allowlist can only be used with --restrict-targeted-applies", err)
+}
+
+func TestExecute_ValidatePostgresDSN(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		LockingDBType: "postgres",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--postgres-dsn must be set when --locking-db-type is postgres", err)
+}
+
+func TestExecute_ValidateWebOIDC(t *testing.T) {
+	cases := []struct {
+		description string
+		flags       map[string]interface{}
+		expErr      string
+	}{
+		{
+			"with basic auth",
+			map[string]interface{}{
+				WebOIDCIssuerURLFlag:    "https://accounts.example.com",
+				WebOIDCClientIDFlag:     "id",
+				WebOIDCClientSecretFlag: "secret",
+				WebBasicAuthFlag:        true,
+			},
+			"--web-oidc-issuer-url and --web-basic-auth cannot both be set",
+		},
+		{
+			"without client",
+			map[string]interface{}{
+				WebOIDCIssuerURLFlag: "https://accounts.example.com",
+				WebOIDCClientIDFlag:  "id",
+			},
+			"--web-oidc-client-id and --web-oidc-client-secret must be set with --web-oidc-issuer-url",
+		},
+		{
+			"invalid issuer",
+			map[string]interface{}{
+				WebOIDCIssuerURLFlag:    "accounts.example.com",
+				WebOIDCClientIDFlag:     "id",
+				WebOIDCClientSecretFlag: "secret",
+			},
+			`--web-oidc-issuer-url must have http:// or https://, got "accounts.example.com"`,
+		},
+		{
+			"groups without issuer",
+			map[string]interface{}{
+				WebOIDCApplyLockGroupsFlag: "sre",
+			},
+			"--web-oidc-view-groups, --web-oidc-discard-lock-groups and --web-oidc-apply-lock-groups can only be used with --web-oidc-issuer-url",
+		},
+		{
+			"valid",
+			map[string]interface{}{
+				WebOIDCIssuerURLFlag:       "https://accounts.example.com",
+				WebOIDCClientIDFlag:        "id",
+				WebOIDCClientSecretFlag:    "secret",
+				WebOIDCApplyLockGroupsFlag: "sre",
+			},
+			"",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := setupWithDefaults(c.flags, t).Execute()
+			if c.expErr == "" {
+				Ok(t, err)
+			} else {
+				ErrEquals(t, c.expErr, err)
+			}
+		})
+	}
+}
+
 func TestExecute_ValidateSSLConfig(t *testing.T) {
 	expErr := "--ssl-key-file and --ssl-cert-file are both required for ssl"
 	cases := []struct {
@@ -364,7 +634,7 @@ func TestExecute_ValidateSSLConfig(t *testing.T) {
 }
 
 func TestExecute_ValidateVCSConfig(t *testing.T) {
-	expErr := "--gh-user/--gh-token or --gh-app-id/--gh-app-key-file or --gh-app-id/--gh-app-key or --gitlab-user/--gitlab-token or --bitbucket-user/--bitbucket-token or --azuredevops-user/--azuredevops-token must be set"
+	expErr := "--gh-user/--gh-token or --gh-app-id/--gh-app-key-file or --gh-app-id/--gh-app-key or --gitlab-user/--gitlab-token or --bitbucket-user/--bitbucket-token or --azuredevops-user/--azuredevops-token or --gitea-user/--gitea-token must be set"
 	cases := []struct {
 		description string
 		flags       map[string]interface{}
@@ -452,6 +722,13 @@ func TestExecute_ValidateVCSConfig(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"just gitea user set",
+			map[string]interface{}{
+				GiteaUserFlag: "user",
+			},
+			true,
+		},
 		{
 			"github user and gitlab token set",
 			map[string]interface{}{
@@ -524,6 +801,14 @@ func TestExecute_ValidateVCSConfig(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"gitea user and gitea token set and should be successful",
+			map[string]interface{}{
+				GiteaUserFlag:  "user",
+				GiteaTokenFlag: "token",
+			},
+			false,
+		},
 		{
 			"all set should be successful",
 			map[string]interface{}{
@@ -535,6 +820,8 @@ func TestExecute_ValidateVCSConfig(t *testing.T) {
 				BitbucketTokenFlag: "token",
 				ADUserFlag:         "user",
 				ADTokenFlag:        "token",
+				GiteaUserFlag:      "user",
+				GiteaTokenFlag:     "token",
 			},
 			false,
 		},
@@ -711,6 +998,16 @@ func TestExecute_BitbucketServerBaseURLScheme(t *testing.T) {
 	ErrEquals(t, "error parsing --bitbucket-webhook-secret flag value \"://mydomain.com\": parse \"://mydomain.com\": missing protocol scheme", c.Execute())
 }
 
+func TestExecute_GiteaBaseURLScheme(t *testing.T) {
+	c := setup(map[string]interface{}{
+		GiteaUserFlag:     "user",
+		GiteaTokenFlag:    "token",
+		RepoAllowlistFlag: "*",
+		GiteaBaseURLFlag:  "mydomain.com",
+	}, t)
+	ErrEquals(t, "--gitea-base-url must have http:// or https://, got \"mydomain.com\"", c.Execute())
+}
+
 // Port should be retained on base url.
 func TestExecute_BitbucketServerBaseURLPort(t *testing.T) {
 	c := setup(map[string]interface{}{
@@ -138,6 +138,9 @@ export default defineUserConfig({
                         'terraform-versions',
                         'terraform-cloud',
                         'using-slack-hooks',
+                        'using-http-hooks',
+                        'audit-log',
+                        'plan-store',
                         'stats',
                         'faq',
                     ]
@@ -151,6 +154,7 @@ export default defineUserConfig({
                             link: 'using-atlantis',
                         },
                         'api-endpoints',
+                        'dashboard',
                     ]
                 },
                 {
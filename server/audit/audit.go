@@ -0,0 +1,224 @@
+// Package audit records an append-only trail of the commands run by Atlantis
+// and the locks deleted through it: who ran what, where, when and with which
+// result.
+package audit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// Sources of events.
+const (
+	// CommentSource is a command commented on a pull request.
+	CommentSource = "comment"
+	// AutoplanSource is a plan run automatically when a pull request was
+	// opened or updated.
+	AutoplanSource = "autoplan"
+	// APISource is a request to the /api endpoints.
+	APISource = "api"
+	// UISource is an action taken in the Atlantis UI.
+	UISource = "ui"
+)
+
+// Results of events.
+const (
+	SuccessResult = "success"
+	// FailureResult means the command failed because of a problem with the
+	// pull request, ex. it wasn't approved.
+	FailureResult = "failure"
+	// ErrorResult means the command errored, ex. terraform failed.
+	ErrorResult = "error"
+	// SkippedResult means the command didn't run, ex. because apply is
+	// disabled.
+	SkippedResult = "skipped"
+)
+
+// Event is a single entry in the audit log.
+type Event struct {
+	// Time is when the command started.
+	Time   time.Time
+	Source string
+	// User is the VCS username of the user that ran the command. It's empty
+	// for API requests and for UI actions when basic auth is disabled.
+	User string
+	// Command is the command, ex. "plan", "state rm" or "unlock".
+	Command   string
+	Repo      string
+	PullNum   int
+	CommitSHA string `json:",omitempty"`
+	Project   string `json:",omitempty"`
+	Directory string `json:",omitempty"`
+	Workspace string `json:",omitempty"`
+	Result    string
+	// Message is the error or failure if the command didn't succeed.
+	Message string `json:",omitempty"`
+	// Duration is how long the command took. When a command runs multiple
+	// projects it's the duration of the whole command.
+	Duration time.Duration
+}
+
+//go:generate pegomock generate --package mocks -o mocks/mock_sink.go Sink
+
+// Sink records events.
+type Sink interface {
+	// Record appends event to the audit log.
+	Record(event Event) error
+}
+
+// Store is a Sink whose events can be queried.
+type Store interface {
+	Sink
+	// List returns the events matching query, newest first.
+	List(query Query) ([]Event, error)
+}
+
+// Query filters the events returned by a Store. Zero values match all events.
+type Query struct {
+	Repo    string
+	PullNum int
+	User    string
+	Command string
+	// Since and Until bound the events' Time.
+	Since time.Time
+	Until time.Time
+	// Limit is the max number of events returned.
+	Limit int
+}
+
+// Matches returns true if e matches the query's filters. It ignores Limit.
+func (q Query) Matches(e Event) bool {
+	switch {
+	case q.Repo != "" && q.Repo != e.Repo:
+		return false
+	case q.PullNum != 0 && q.PullNum != e.PullNum:
+		return false
+	case q.User != "" && q.User != e.User:
+		return false
+	case q.Command != "" && q.Command != e.Command:
+		return false
+	case !q.Since.IsZero() && e.Time.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && e.Time.After(q.Until):
+		return false
+	}
+	return true
+}
+
+// MultiSink records events with each of its Sinks.
+type MultiSink struct {
+	Sinks []Sink
+}
+
+// Record records event with every sink, even if one of them fails.
+func (m *MultiSink) Record(event Event) error {
+	var errs error
+	for _, s := range m.Sinks {
+		if err := s.Record(event); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Record records events with sink and logs any errors so that a failure to
+// audit doesn't fail the command. It's a no-op if sink is nil.
+func Record(log logging.SimpleLogging, sink Sink, events ...Event) {
+	if sink == nil {
+		return
+	}
+	for _, e := range events {
+		if err := sink.Record(e); err != nil {
+			log.Err("unable to record %s audit event: %s", e.Command, err)
+		}
+	}
+}
+
+// NewCommandEvents returns the events for the commands run for ctx, which
+// were started at start by source. There's an event for each project a command
+// ran in or a single event if a command didn't run any projects. If no
+// commands were run, a single skipped event for cmdName is returned.
+func NewCommandEvents(source string, ctx *command.Context, cmdName command.Name, start time.Time) []Event {
+	base := Event{
+		Time:      start,
+		Source:    source,
+		User:      ctx.User.Username,
+		Repo:      ctx.Pull.BaseRepo.FullName,
+		PullNum:   ctx.Pull.Num,
+		CommitSHA: ctx.Pull.HeadCommit,
+		Duration:  time.Since(start),
+	}
+	if len(ctx.RunResults) == 0 {
+		e := base
+		e.Command = cmdName.String()
+		e.Result = SkippedResult
+		return []Event{e}
+	}
+
+	var events []Event
+	for _, r := range ctx.RunResults {
+		base.Command = CommandString(r.Name, r.SubCommand)
+		if len(r.ProjectResults) == 0 {
+			e := base
+			e.Result, e.Message = result(r.Error, r.Failure)
+			events = append(events, e)
+			continue
+		}
+		for _, p := range r.ProjectResults {
+			events = append(events, NewProjectEvent(base, p))
+		}
+	}
+	return events
+}
+
+// NewProjectEvent returns base with the project and result of res.
+func NewProjectEvent(base Event, res command.ProjectResult) Event {
+	base.Project = res.ProjectName
+	base.Directory = res.RepoRelDir
+	base.Workspace = res.Workspace
+	base.Result, base.Message = result(res.Error, res.Failure)
+	return base
+}
+
+// NewLockEvent returns the event for user deleting lock via source. The
+// deletion started at start.
+func NewLockEvent(source string, user string, lock models.ProjectLock, start time.Time) Event {
+	return Event{
+		Time:      start,
+		Source:    source,
+		User:      user,
+		Command:   command.Unlock.String(),
+		Repo:      lock.Project.RepoFullName,
+		PullNum:   lock.Pull.Num,
+		CommitSHA: lock.Pull.HeadCommit,
+		Directory: lock.Project.Path,
+		Workspace: lock.Workspace,
+		Result:    SuccessResult,
+		Duration:  time.Since(start),
+	}
+}
+
+// CommandString returns the name of the command as it's recorded in events,
+// ex. "state rm".
+func CommandString(name command.Name, subCommand string) string {
+	if subCommand == "" {
+		return name.String()
+	}
+	return fmt.Sprintf("%s %s", name, subCommand)
+}
+
+func result(err error, failure string) (string, string) {
+	switch {
+	case err != nil:
+		return ErrorResult, err.Error()
+	case failure != "":
+		return FailureResult, failure
+	default:
+		return SuccessResult, ""
+	}
+}
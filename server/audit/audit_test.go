@@ -0,0 +1,149 @@
+package audit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/audit"
+	"github.com/runatlantis/atlantis/server/audit/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestQuery_Matches(t *testing.T) {
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	event := audit.Event{
+		Time:    now,
+		User:    "lkysow",
+		Command: "apply",
+		Repo:    "runatlantis/atlantis",
+		PullNum: 1,
+	}
+	cases := []struct {
+		description string
+		query       audit.Query
+		exp         bool
+	}{
+		{"empty query", audit.Query{}, true},
+		{"all filters match", audit.Query{Repo: "runatlantis/atlantis", PullNum: 1, User: "lkysow", Command: "apply", Since: now, Until: now}, true},
+		{"different repo", audit.Query{Repo: "runatlantis/other"}, false},
+		{"different pull", audit.Query{PullNum: 2}, false},
+		{"different user", audit.Query{User: "other"}, false},
+		{"different command", audit.Query{Command: "plan"}, false},
+		{"before since", audit.Query{Since: now.Add(time.Second)}, false},
+		{"after until", audit.Query{Until: now.Add(-time.Second)}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			Equals(t, c.exp, c.query.Matches(event))
+		})
+	}
+}
+
+func TestNewCommandEvents(t *testing.T) {
+	ctx := &command.Context{
+		User: models.User{Username: "lkysow"},
+		Pull: models.PullRequest{
+			Num:        1,
+			HeadCommit: "abc123",
+			BaseRepo:   models.Repo{FullName: "runatlantis/atlantis"},
+		},
+	}
+	start := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	base := audit.Event{
+		Time:      start,
+		Source:    audit.CommentSource,
+		User:      "lkysow",
+		Repo:      "runatlantis/atlantis",
+		PullNum:   1,
+		CommitSHA: "abc123",
+	}
+
+	t.Log("if no commands ran, a skipped event should be returned")
+	events := audit.NewCommandEvents(audit.CommentSource, ctx, command.Apply, start)
+	Equals(t, 1, len(events))
+	skipped := base
+	skipped.Command, skipped.Result = "apply", audit.SkippedResult
+	events[0].Duration = 0
+	Equals(t, skipped, events[0])
+
+	t.Log("otherwise there should be an event per project or per command without projects")
+	ctx.RunResults = []command.RunResult{
+		{
+			Name: command.Plan,
+			Result: command.Result{ProjectResults: []command.ProjectResult{
+				{ProjectName: "one", RepoRelDir: "one", Workspace: "default"},
+				{ProjectName: "two", RepoRelDir: "two", Workspace: "default", Failure: "failed"},
+			}},
+		},
+		{
+			Name:       command.State,
+			SubCommand: "rm",
+			Result:     command.Result{Error: errors.New("err")},
+		},
+	}
+	events = audit.NewCommandEvents(audit.CommentSource, ctx, command.Plan, start)
+	for i := range events {
+		events[i].Duration = 0
+	}
+	one, two, stateRm := base, base, base
+	one.Command, one.Project, one.Directory, one.Workspace, one.Result = "plan", "one", "one", "default", audit.SuccessResult
+	two.Command, two.Project, two.Directory, two.Workspace, two.Result, two.Message = "plan", "two", "two", "default", audit.FailureResult, "failed"
+	stateRm.Command, stateRm.Result, stateRm.Message = "state rm", audit.ErrorResult, "err"
+	Equals(t, []audit.Event{one, two, stateRm}, events)
+}
+
+func TestNewLockEvent(t *testing.T) {
+	start := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	lock := models.ProjectLock{
+		Project:   models.NewProject("runatlantis/atlantis", "dir"),
+		Workspace: "default",
+		Pull:      models.PullRequest{Num: 1, HeadCommit: "abc123"},
+	}
+	event := audit.NewLockEvent(audit.UISource, "lkysow", lock, start)
+	event.Duration = 0
+	Equals(t, audit.Event{
+		Time:      start,
+		Source:    audit.UISource,
+		User:      "lkysow",
+		Command:   "unlock",
+		Repo:      "runatlantis/atlantis",
+		PullNum:   1,
+		CommitSHA: "abc123",
+		Directory: "dir",
+		Workspace: "default",
+		Result:    audit.SuccessResult,
+	}, event)
+}
+
+func TestMultiSink_Record(t *testing.T) {
+	RegisterMockTestingT(t)
+	failing := mocks.NewMockSink()
+	succeeding := mocks.NewMockSink()
+	event := audit.Event{Command: "plan"}
+	When(failing.Record(event)).ThenReturn(errors.New("err"))
+
+	sink := audit.MultiSink{Sinks: []audit.Sink{failing, succeeding}}
+	ErrContains(t, "err", sink.Record(event))
+	succeeding.VerifyWasCalledOnce().Record(event)
+}
+
+func TestRecord(t *testing.T) {
+	RegisterMockTestingT(t)
+	logger := logging.NewNoopLogger(t)
+
+	t.Log("a nil sink should be a no-op")
+	audit.Record(logger, nil, audit.Event{})
+
+	t.Log("every event should be recorded even if one fails")
+	sink := mocks.NewMockSink()
+	first, second := audit.Event{Command: "plan"}, audit.Event{Command: "apply"}
+	When(sink.Record(first)).ThenReturn(errors.New("err"))
+	audit.Record(logger, sink, first, second)
+	sink.VerifyWasCalledOnce().Record(first)
+	sink.VerifyWasCalledOnce().Record(second)
+}
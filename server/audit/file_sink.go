@@ -0,0 +1,99 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// FileSink appends events as JSON lines to a file. It implements Store.
+type FileSink struct {
+	path string
+	// mu serializes writes so lines aren't interleaved.
+	mu sync.Mutex
+}
+
+// NewFileSink returns a FileSink that appends to the file at path, creating it
+// and its directory if they don't exist.
+func NewFileSink(path string) (*FileSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, errors.Wrapf(err, "creating directory for %s", path)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s", path)
+	}
+	if err := f.Close(); err != nil {
+		return nil, errors.Wrapf(err, "closing %s", path)
+	}
+	return &FileSink{path: path}, nil
+}
+
+func (s *FileSink) Record(event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The file is opened for each event so it can be rotated by external
+	// tools.
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "opening %s", s.path)
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close() // nolint: errcheck
+		return errors.Wrapf(err, "writing %s", s.path)
+	}
+	return errors.Wrapf(f.Close(), "closing %s", s.path)
+}
+
+func (s *FileSink) List(query Query) ([]Event, error) {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "opening %s", s.path)
+	}
+	defer f.Close() // nolint: errcheck
+
+	var events []Event
+	r := bufio.NewReader(f)
+	for lineNum := 1; ; lineNum++ {
+		line, err := r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, errors.Wrapf(err, "reading %s", s.path)
+		}
+		// A partial last line is an event that's still being written.
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			var e Event
+			if jsonErr := json.Unmarshal(line, &e); jsonErr != nil {
+				return nil, errors.Wrapf(jsonErr, "deserializing line %d of %s", lineNum, s.path)
+			}
+			if query.Matches(e) {
+				events = append(events, e)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+	}
+
+	// Events are appended so the newest are last.
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	if query.Limit > 0 && len(events) > query.Limit {
+		events = events[:query.Limit]
+	}
+	return events, nil
+}
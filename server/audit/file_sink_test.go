@@ -0,0 +1,69 @@
+package audit_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/audit"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestFileSink_RecordList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+	sink, err := audit.NewFileSink(path)
+	Ok(t, err)
+
+	t.Log("an empty log should return no events")
+	events, err := sink.List(audit.Query{})
+	Ok(t, err)
+	Equals(t, 0, len(events))
+
+	start := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	plan := audit.Event{Time: start, Command: "plan", Repo: "owner/repo", PullNum: 1, Result: audit.SuccessResult, Duration: time.Minute}
+	apply := audit.Event{Time: start.Add(time.Hour), Command: "apply", Repo: "owner/repo", PullNum: 1, Result: audit.ErrorResult, Message: "err"}
+	other := audit.Event{Time: start.Add(2 * time.Hour), Command: "plan", Repo: "owner/other", PullNum: 2, Result: audit.SuccessResult}
+	for _, e := range []audit.Event{plan, apply, other} {
+		Ok(t, sink.Record(e))
+	}
+
+	t.Log("events should be listed newest first")
+	events, err = sink.List(audit.Query{})
+	Ok(t, err)
+	Equals(t, []audit.Event{other, apply, plan}, events)
+
+	t.Log("events should be filtered and limited")
+	events, err = sink.List(audit.Query{Repo: "owner/repo"})
+	Ok(t, err)
+	Equals(t, []audit.Event{apply, plan}, events)
+	events, err = sink.List(audit.Query{Limit: 1})
+	Ok(t, err)
+	Equals(t, []audit.Event{other}, events)
+
+	t.Log("a new sink should append to the existing log")
+	sink, err = audit.NewFileSink(path)
+	Ok(t, err)
+	Ok(t, sink.Record(plan))
+	events, err = sink.List(audit.Query{Command: "plan"})
+	Ok(t, err)
+	Equals(t, []audit.Event{plan, other, plan}, events)
+}
+
+func TestFileSink_ListIgnoresPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := audit.NewFileSink(path)
+	Ok(t, err)
+	event := audit.Event{Command: "plan", Time: time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)}
+	Ok(t, sink.Record(event))
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	Ok(t, err)
+	_, err = f.WriteString(`{"Command":"ap`)
+	Ok(t, err)
+	Ok(t, f.Close())
+
+	events, err := sink.List(audit.Query{})
+	Ok(t, err)
+	Equals(t, []audit.Event{event}, events)
+}
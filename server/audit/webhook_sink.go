@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"github.com/hashicorp/go-multierror"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// WebhookSink POSTs events to http webhooks configured with "event: audit".
+// The events are sent in the background so recording them doesn't wait for
+// the webhooks to respond.
+type WebhookSink struct {
+	Webhooks []*webhooks.HTTPWebhook
+	Logger   logging.SimpleLogging
+}
+
+// Record queues event to be POSTed to each webhook whose workspace regex
+// matches the event's workspace.
+func (s *WebhookSink) Record(event Event) error {
+	var errs error
+	for _, w := range s.Webhooks {
+		if !w.WorkspaceRegex.MatchString(event.Workspace) {
+			continue
+		}
+		if err := w.Enqueue(s.Logger, webhooks.AuditEvent, event); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,64 @@
+package audit_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/audit"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestWebhookSink_Record(t *testing.T) {
+	type request struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan request, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{header: r.Header, body: body}
+	}))
+	defer server.Close()
+
+	sink := audit.WebhookSink{
+		Webhooks: []*webhooks.HTTPWebhook{
+			webhooks.NewHTTP(webhooks.AuditEvent, regexp.MustCompile("prod.*"), server.URL, "secret"),
+		},
+		Logger: logging.NewNoopLogger(t),
+	}
+	event := audit.Event{
+		Time:      time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+		Command:   "apply",
+		Workspace: "production",
+		Result:    audit.SuccessResult,
+	}
+	Ok(t, sink.Record(event))
+	var r request
+	select {
+	case r = <-requests:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the webhook")
+	}
+	Equals(t, webhooks.AuditEvent, r.header.Get(webhooks.EventHeader))
+	Equals(t, "sha256="+webhooks.Sign("secret", r.body), r.header.Get(webhooks.SignatureHeader))
+	var sent audit.Event
+	Ok(t, json.Unmarshal(r.body, &sent))
+	Equals(t, event, sent)
+
+	t.Log("events whose workspace doesn't match shouldn't be sent")
+	event.Workspace = "staging"
+	Ok(t, sink.Record(event))
+	event.Workspace = "production"
+	Ok(t, sink.Record(event))
+	r = <-requests
+	Ok(t, json.Unmarshal(r.body, &sent))
+	Equals(t, "production", sent.Workspace)
+	Equals(t, 0, len(requests))
+}
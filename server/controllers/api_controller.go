@@ -1,18 +1,27 @@
 package controllers
 
 import (
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/runatlantis/atlantis/server/audit"
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/logging"
 	tally "github.com/uber-go/tally/v4"
 )
@@ -21,7 +30,13 @@ const atlantisTokenHeader = "X-Atlantis-Token"
 
 type APIController struct {
 	APISecret                 []byte
+	Drainer                   *events.Drainer
 	Locker                    locking.Locker
+	ApplyLocker               locking.ApplyLocker
+	Backend                   locking.Backend
+	DeleteLockCommand         events.DeleteLockCommand
+	DriftDetector             *events.DriftDetector
+	ProjectCmdOutputHandler   jobs.ProjectCommandOutputHandler
 	Logger                    logging.SimpleLogging
 	Parser                    events.EventParsing
 	ProjectCommandBuilder     events.ProjectCommandBuilder
@@ -30,6 +45,14 @@ type APIController struct {
 	RepoAllowlistChecker      *events.RepoAllowlistChecker
 	Scope                     tally.Scope
 	VCSClient                 vcs.Client
+	// AuditSink records the commands run and locks deleted via the API. If
+	// nil, they aren't audited.
+	AuditSink audit.Sink
+	// AuditStore is queried by /api/audit. If nil, the endpoint is disabled.
+	AuditStore audit.Store
+	// AsyncJobRetention is how long finished async jobs are kept. If zero,
+	// they're kept forever.
+	AsyncJobRetention time.Duration
 }
 
 type APIRequest struct {
@@ -44,6 +67,48 @@ type APIRequest struct {
 	}
 }
 
+// APILock is the JSON representation of a project lock.
+type APILock struct {
+	ID           string
+	RepoFullName string
+	Path         string
+	Workspace    string
+	PullNum      int
+	PullURL      string
+	LockedBy     string
+	Time         time.Time
+}
+
+// APIPullStatus is the JSON representation of a pull request's status.
+type APIPullStatus struct {
+	Repository string
+	PR         int
+	HeadCommit string
+	Projects   []APIProjectStatus
+}
+
+// APIProjectStatus is the JSON representation of a project's status.
+type APIProjectStatus struct {
+	ProjectName  string
+	RepoRelDir   string
+	Workspace    string
+	Status       string
+	PolicyStatus []models.PolicySetStatus
+}
+
+func newAPILock(id string, lock models.ProjectLock) APILock {
+	return APILock{
+		ID:           id,
+		RepoFullName: lock.Project.RepoFullName,
+		Path:         lock.Project.Path,
+		Workspace:    lock.Workspace,
+		PullNum:      lock.Pull.Num,
+		PullURL:      lock.Pull.URL,
+		LockedBy:     lock.User.Username,
+		Time:         lock.Time,
+	}
+}
+
 func (a *APIRequest) getCommands(ctx *command.Context, cmdBuilder func(*command.Context, *events.CommentCommand) ([]command.ProjectContext, error)) ([]command.ProjectContext, error) {
 	cc := make([]*events.CommentCommand, 0)
 
@@ -87,7 +152,7 @@ func (a *APIController) Plan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := a.apiPlan(request, ctx)
+	result, err := a.apiPlan(request, ctx, nil)
 	if err != nil {
 		a.apiReportError(w, http.StatusInternalServerError, err)
 		return
@@ -116,7 +181,7 @@ func (a *APIController) Apply(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// We must first make the plan for all projects
-	_, err = a.apiPlan(request, ctx)
+	_, err = a.apiPlan(request, ctx, nil)
 	if err != nil {
 		a.apiReportError(w, http.StatusInternalServerError, err)
 		return
@@ -124,7 +189,7 @@ func (a *APIController) Apply(w http.ResponseWriter, r *http.Request) {
 	defer a.Locker.UnlockByPull(ctx.HeadRepo.FullName, 0) // nolint: errcheck
 
 	// We can now prepare and run the apply step
-	result, err := a.apiApply(request, ctx)
+	result, err := a.apiApply(request, ctx, nil)
 	if err != nil {
 		a.apiReportError(w, http.StatusInternalServerError, err)
 		return
@@ -141,43 +206,571 @@ func (a *APIController) Apply(w http.ResponseWriter, r *http.Request) {
 	a.respond(w, logging.Debug, code, string(response))
 }
 
-func (a *APIController) apiPlan(request *APIRequest, ctx *command.Context) (*command.Result, error) {
+// PlanAsync is the POST /api/plan/async route. It runs plan in the background
+// and responds right away with the job that tracks it.
+func (a *APIController) PlanAsync(w http.ResponseWriter, r *http.Request) {
+	a.apiRunAsync(w, r, command.Plan, func(request *APIRequest, ctx *command.Context, onBuild func([]command.ProjectContext)) (*command.Result, error) {
+		return a.apiPlan(request, ctx, onBuild)
+	})
+}
+
+// ApplyAsync is the POST /api/apply/async route. It runs plan and then apply
+// in the background and responds right away with the job that tracks them.
+func (a *APIController) ApplyAsync(w http.ResponseWriter, r *http.Request) {
+	a.apiRunAsync(w, r, command.Apply, func(request *APIRequest, ctx *command.Context, onBuild func([]command.ProjectContext)) (*command.Result, error) {
+		// We must first make the plan for all projects
+		if _, err := a.apiPlan(request, ctx, onBuild); err != nil {
+			return nil, err
+		}
+		return a.apiApply(request, ctx, onBuild)
+	})
+}
+
+// GetAsyncJob is the GET /api/async/{id} route. It responds with the status of
+// the job without its result.
+func (a *APIController) GetAsyncJob(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	job, code, err := a.apiGetAsyncJob(r)
+	if err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+	job.Result = nil
+	a.apiRespondJSON(w, http.StatusOK, job)
+}
+
+// GetAsyncJobResult is the GET /api/async/{id}/result route. It responds with
+// the same command result as /api/plan or /api/apply once the job has
+// finished.
+func (a *APIController) GetAsyncJobResult(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	job, code, err := a.apiGetAsyncJob(r)
+	if err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+	if !job.Status.IsFinished() {
+		a.apiReportError(w, http.StatusConflict, fmt.Errorf("job %q is %s", job.ID, job.Status))
+		return
+	}
+	if job.Result == nil {
+		a.apiReportError(w, http.StatusInternalServerError, errors.New(job.Error))
+		return
+	}
+
+	code = http.StatusOK
+	if job.Status == models.FailedAPIJobStatus {
+		code = http.StatusInternalServerError
+	}
+	a.respond(w, logging.Debug, code, "%s", string(job.Result))
+}
+
+func (a *APIController) apiGetAsyncJob(r *http.Request) (*models.APIJob, int, error) {
+	if code, err := a.apiAuthenticate(r); err != nil {
+		return nil, code, err
+	}
+
+	id, ok := mux.Vars(r)["id"]
+	if !ok || id == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("no job id in request")
+	}
+	job, err := a.Backend.GetAPIJob(id)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed getting job: %s", err)
+	}
+	if job == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("no job found at id %q", id)
+	}
+	return job, http.StatusOK, nil
+}
+
+type apiRunFunc func(request *APIRequest, ctx *command.Context, onBuild func([]command.ProjectContext)) (*command.Result, error)
+
+// apiRunAsync validates the request, saves a pending job and then calls run
+// in the background.
+func (a *APIController) apiRunAsync(w http.ResponseWriter, r *http.Request, cmdName command.Name, run apiRunFunc) {
+	w.Header().Set("Content-Type", "application/json")
+
+	request, ctx, code, err := a.apiParseAndValidate(r)
+	if err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	if opStarted := a.Drainer.StartOp(); !opStarted {
+		a.apiReportError(w, http.StatusServiceUnavailable, fmt.Errorf("atlantis is shutting down, cannot process request"))
+		return
+	}
+
+	id := uuid.New()
+	// Jobs that aren't for a pull request each get their own pull request
+	// number so that they don't share working directories and locks.
+	if ctx.Pull.Num == 0 {
+		ctx.Pull.Num = asyncJobPullNum(id)
+	}
+	now := time.Now()
+	job := models.APIJob{
+		ID:         id.String(),
+		Command:    cmdName.String(),
+		Status:     models.PendingAPIJobStatus,
+		Repository: ctx.Pull.BaseRepo.FullName,
+		PullNum:    ctx.Pull.Num,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := a.Backend.UpdateAPIJob(job); err != nil {
+		a.Drainer.OpDone()
+		a.apiReportError(w, http.StatusInternalServerError, fmt.Errorf("failed saving job: %s", err))
+		return
+	}
+
+	go func() {
+		defer a.Drainer.OpDone()
+		a.runAsyncJob(job, request, ctx, run)
+	}()
+
+	a.apiRespondJSON(w, http.StatusAccepted, job)
+}
+
+// asyncJobPullNum returns the negative pull request number of the job with
+// id. Negative numbers can't clash with real pull requests, and numbers below
+// models.DriftDetectionPullNum can't clash with drift detection.
+func asyncJobPullNum(id uuid.UUID) int {
+	return models.DriftDetectionPullNum - 1 - int(binary.BigEndian.Uint32(id[:4])>>1)
+}
+
+func (a *APIController) runAsyncJob(job models.APIJob, request *APIRequest, ctx *command.Context, run apiRunFunc) {
+	update := func() {
+		job.UpdatedAt = time.Now()
+		if err := a.Backend.UpdateAPIJob(job); err != nil {
+			a.Logger.Err("failed saving API job %q: %s", job.ID, err)
+		}
+	}
+
+	defer a.pruneAsyncJobs()
+	defer a.releaseAsyncJob(job)
+	result, err := run(request, ctx, func(cmds []command.ProjectContext) {
+		for _, cmd := range cmds {
+			job.ProjectJobIDs = append(job.ProjectJobIDs, cmd.JobID)
+		}
+		job.Status = models.RunningAPIJobStatus
+		update()
+	})
+	if err != nil {
+		job.Status = models.FailedAPIJobStatus
+		job.Error = err.Error()
+		update()
+		return
+	}
+
+	job.Status = models.SucceededAPIJobStatus
+	if result.HasErrors() {
+		job.Status = models.FailedAPIJobStatus
+	}
+	if job.Result, err = json.Marshal(result); err != nil {
+		job.Status = models.FailedAPIJobStatus
+		job.Error = err.Error()
+	}
+	update()
+}
+
+// releaseAsyncJob deletes the locks and working directories of a job that
+// isn't for a pull request. The locks of jobs for pull requests are kept
+// until the pull request is closed, like those of comment commands.
+func (a *APIController) releaseAsyncJob(job models.APIJob) {
+	if job.PullNum >= 0 {
+		return
+	}
+	if _, err := a.DeleteLockCommand.DeleteLocksByPull(job.Repository, job.PullNum); err != nil {
+		a.Logger.Err("failed deleting locks of API job %q: %s", job.ID, err)
+	}
+}
+
+// RecoverAsyncJobs fails the async jobs that were left unfinished by the last
+// time Atlantis ran, releases their locks and deletes the expired jobs. It
+// should be called before the server starts accepting requests.
+func (a *APIController) RecoverAsyncJobs() error {
+	jobs, err := a.Backend.ListAPIJobs()
+	if err != nil {
+		return fmt.Errorf("listing API jobs: %w", err)
+	}
+	for _, job := range jobs {
+		if job.Status.IsFinished() {
+			continue
+		}
+		a.Logger.Warn("failing API job %q since Atlantis restarted while it was %s", job.ID, job.Status)
+		a.releaseAsyncJob(job)
+		job.Status = models.FailedAPIJobStatus
+		job.Error = "Atlantis restarted before the job finished"
+		job.UpdatedAt = time.Now()
+		if err := a.Backend.UpdateAPIJob(job); err != nil {
+			return fmt.Errorf("saving API job %q: %w", job.ID, err)
+		}
+	}
+	a.pruneAsyncJobs()
+	return nil
+}
+
+// pruneAsyncJobs deletes the finished jobs older than AsyncJobRetention.
+func (a *APIController) pruneAsyncJobs() {
+	if a.AsyncJobRetention <= 0 {
+		return
+	}
+	jobs, err := a.Backend.ListAPIJobs()
+	if err != nil {
+		a.Logger.Err("failed listing API jobs: %s", err)
+		return
+	}
+	cutoff := time.Now().Add(-a.AsyncJobRetention)
+	for _, job := range jobs {
+		if !job.Status.IsFinished() || job.UpdatedAt.After(cutoff) {
+			continue
+		}
+		if err := a.Backend.DeleteAPIJob(job.ID); err != nil {
+			a.Logger.Err("failed deleting API job %q: %s", job.ID, err)
+		}
+	}
+}
+
+// ListLocks is the GET /api/locks route. It responds with all project locks.
+func (a *APIController) ListLocks(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	locks, err := a.Locker.List()
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, fmt.Errorf("failed listing locks: %s", err))
+		return
+	}
+	apiLocks := make([]APILock, 0, len(locks))
+	for id, lock := range locks {
+		apiLocks = append(apiLocks, newAPILock(id, lock))
+	}
+	sort.Slice(apiLocks, func(i, j int) bool { return apiLocks[i].ID < apiLocks[j].ID })
+	a.apiRespondJSON(w, http.StatusOK, apiLocks)
+}
+
+// GetLock is the GET /api/locks?id={id} route. It responds with the lock at id.
+func (a *APIController) GetLock(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, code, err := a.apiParseLockID(r)
+	if err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	lock, err := a.Locker.GetLock(id)
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, fmt.Errorf("failed getting lock: %s", err))
+		return
+	}
+	if lock == nil {
+		a.apiReportError(w, http.StatusNotFound, fmt.Errorf("no lock found at id %q", id))
+		return
+	}
+	a.apiRespondJSON(w, http.StatusOK, newAPILock(id, *lock))
+}
+
+// DeleteLock is the DELETE /api/locks?id={id} route. It deletes the lock at id,
+// discards its plan and responds with the deleted lock.
+func (a *APIController) DeleteLock(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id, code, err := a.apiParseLockID(r)
+	if err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	start := time.Now()
+	lock, err := a.DeleteLockCommand.DeleteLock(id)
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, fmt.Errorf("failed deleting lock: %s", err))
+		return
+	}
+	if lock == nil {
+		a.apiReportError(w, http.StatusNotFound, fmt.Errorf("no lock found at id %q", id))
+		return
+	}
+	a.Logger.Info("deleted lock id %q via API", id)
+	audit.Record(a.Logger, a.AuditSink, audit.NewLockEvent(audit.APISource, "", *lock, start))
+	a.apiRespondJSON(w, http.StatusOK, newAPILock(id, *lock))
+}
+
+// GetPullStatus is the GET /api/pull?repository={repo}&type={vcs}&pr={num}
+// route. It responds with the status of each project in the pull request.
+func (a *APIController) GetPullStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	query := r.URL.Query()
+	repository, vcsType := query.Get("repository"), query.Get("type")
+	if repository == "" || vcsType == "" {
+		a.apiReportError(w, http.StatusBadRequest, fmt.Errorf("repository and type query parameters are required"))
+		return
+	}
+	num, err := strconv.Atoi(query.Get("pr"))
+	if err != nil {
+		a.apiReportError(w, http.StatusBadRequest, fmt.Errorf("invalid pr query parameter: %s", err))
+		return
+	}
+	baseRepo, code, err := a.apiParseRepo(vcsType, repository)
+	if err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	status, err := a.Backend.GetPullStatus(models.PullRequest{Num: num, BaseRepo: baseRepo})
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, fmt.Errorf("failed getting pull status: %s", err))
+		return
+	}
+	if status == nil {
+		a.apiReportError(w, http.StatusNotFound, fmt.Errorf("no status found for %s#%d", baseRepo.FullName, num))
+		return
+	}
+
+	apiStatus := APIPullStatus{
+		Repository: baseRepo.FullName,
+		PR:         num,
+		HeadCommit: status.Pull.HeadCommit,
+		Projects:   make([]APIProjectStatus, 0, len(status.Projects)),
+	}
+	for _, p := range status.Projects {
+		apiStatus.Projects = append(apiStatus.Projects, APIProjectStatus{
+			ProjectName:  p.ProjectName,
+			RepoRelDir:   p.RepoRelDir,
+			Workspace:    p.Workspace,
+			Status:       p.Status.String(),
+			PolicyStatus: p.PolicyStatus,
+		})
+	}
+	a.apiRespondJSON(w, http.StatusOK, apiStatus)
+}
+
+// ListJobs is the GET /api/jobs route. It responds with all active and
+// completed jobs that haven't been cleaned up yet.
+func (a *APIController) ListJobs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	jobList := a.ProjectCmdOutputHandler.Jobs()
+	if jobList == nil {
+		jobList = []jobs.Job{}
+	}
+	sort.Slice(jobList, func(i, j int) bool { return jobList[i].JobID < jobList[j].JobID })
+	a.apiRespondJSON(w, http.StatusOK, jobList)
+}
+
+// ListDrift is the GET /api/drift route. It responds with the result of the
+// last drift detection run for each repo.
+func (a *APIController) ListDrift(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+	results, err := a.DriftDetector.Results()
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, fmt.Errorf("failed listing drift: %s", err))
+		return
+	}
+	a.apiRespondJSON(w, http.StatusOK, results)
+}
+
+// ListAuditEvents is the GET /api/audit route. It responds with the audit
+// events, newest first, optionally filtered by the repository, pr, user,
+// command, since and until query parameters. At most limit events are
+// returned, which defaults to 100.
+func (a *APIController) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+	if a.AuditStore == nil {
+		a.apiReportError(w, http.StatusNotFound, fmt.Errorf("audit log is not enabled"))
+		return
+	}
+
+	query, err := parseAuditQuery(r)
+	if err != nil {
+		a.apiReportError(w, http.StatusBadRequest, err)
+		return
+	}
+	events, err := a.AuditStore.List(query)
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, fmt.Errorf("failed listing audit events: %s", err))
+		return
+	}
+	if events == nil {
+		events = []audit.Event{}
+	}
+	a.apiRespondJSON(w, http.StatusOK, events)
+}
+
+func parseAuditQuery(r *http.Request) (audit.Query, error) {
+	values := r.URL.Query()
+	query := audit.Query{
+		Repo:    values.Get("repository"),
+		User:    values.Get("user"),
+		Command: values.Get("command"),
+		Limit:   100,
+	}
+
+	var err error
+	if v := values.Get("pr"); v != "" {
+		if query.PullNum, err = strconv.Atoi(v); err != nil {
+			return query, fmt.Errorf("invalid pr query parameter: %s", err)
+		}
+	}
+	if v := values.Get("limit"); v != "" {
+		if query.Limit, err = strconv.Atoi(v); err != nil || query.Limit <= 0 {
+			return query, fmt.Errorf("invalid limit query parameter %q: must be a positive integer", v)
+		}
+	}
+	if v := values.Get("since"); v != "" {
+		if query.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			return query, fmt.Errorf("invalid since query parameter: %s", err)
+		}
+	}
+	if v := values.Get("until"); v != "" {
+		if query.Until, err = time.Parse(time.RFC3339, v); err != nil {
+			return query, fmt.Errorf("invalid until query parameter: %s", err)
+		}
+	}
+	return query, nil
+}
+
+// GetApplyLock is the GET /api/apply/lock route. It responds with the status
+// of the global apply lock.
+func (a *APIController) GetApplyLock(w http.ResponseWriter, r *http.Request) {
+	a.apiApplyLock(w, r, a.ApplyLocker.CheckApplyLock)
+}
+
+// LockApply is the POST /api/apply/lock route. It acquires the global apply
+// lock.
+func (a *APIController) LockApply(w http.ResponseWriter, r *http.Request) {
+	a.apiApplyLock(w, r, a.ApplyLocker.LockApply)
+}
+
+// UnlockApply is the DELETE /api/apply/lock route. It releases the global
+// apply lock.
+func (a *APIController) UnlockApply(w http.ResponseWriter, r *http.Request) {
+	a.apiApplyLock(w, r, func() (locking.ApplyCommandLock, error) {
+		if err := a.ApplyLocker.UnlockApply(); err != nil {
+			return locking.ApplyCommandLock{}, err
+		}
+		return a.ApplyLocker.CheckApplyLock()
+	})
+}
+
+func (a *APIController) apiApplyLock(w http.ResponseWriter, r *http.Request, f func() (locking.ApplyCommandLock, error)) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if code, err := a.apiAuthenticate(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	lock, err := f()
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, err)
+		return
+	}
+	a.apiRespondJSON(w, http.StatusOK, lock)
+}
+
+func (a *APIController) apiParseLockID(r *http.Request) (string, int, error) {
+	if code, err := a.apiAuthenticate(r); err != nil {
+		return "", code, err
+	}
+
+	// The query is already unescaped.
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		return "", http.StatusBadRequest, fmt.Errorf("no lock id in request")
+	}
+	return id, http.StatusOK, nil
+}
+
+// apiPlan builds and runs plan for each project in request. If onBuild isn't
+// nil it's called with the project commands before they're run.
+func (a *APIController) apiPlan(request *APIRequest, ctx *command.Context, onBuild func([]command.ProjectContext)) (*command.Result, error) {
 	cmds, err := request.getCommands(ctx, a.ProjectCommandBuilder.BuildPlanCommands)
 	if err != nil {
 		return nil, err
 	}
+	if onBuild != nil {
+		onBuild(cmds)
+	}
 
 	var projectResults []command.ProjectResult
 	for _, cmd := range cmds {
+		start := time.Now()
 		res := a.ProjectPlanCommandRunner.Plan(cmd)
+		a.auditProjectResult(ctx, start, res)
 		projectResults = append(projectResults, res)
 	}
 	return &command.Result{ProjectResults: projectResults}, nil
 }
 
-func (a *APIController) apiApply(request *APIRequest, ctx *command.Context) (*command.Result, error) {
+// apiApply builds and runs apply for each project in request. If onBuild isn't
+// nil it's called with the project commands before they're run.
+func (a *APIController) apiApply(request *APIRequest, ctx *command.Context, onBuild func([]command.ProjectContext)) (*command.Result, error) {
 	cmds, err := request.getCommands(ctx, a.ProjectCommandBuilder.BuildApplyCommands)
 	if err != nil {
 		return nil, err
 	}
+	if onBuild != nil {
+		onBuild(cmds)
+	}
 
 	var projectResults []command.ProjectResult
 	for _, cmd := range cmds {
+		start := time.Now()
 		res := a.ProjectApplyCommandRunner.Apply(cmd)
+		a.auditProjectResult(ctx, start, res)
 		projectResults = append(projectResults, res)
 	}
 	return &command.Result{ProjectResults: projectResults}, nil
 }
 
-func (a *APIController) apiParseAndValidate(r *http.Request) (*APIRequest, *command.Context, int, error) {
-	if len(a.APISecret) == 0 {
-		return nil, nil, http.StatusBadRequest, fmt.Errorf("ignoring request since API is disabled")
-	}
+// auditProjectResult records the result of a project command that started at
+// start.
+func (a *APIController) auditProjectResult(ctx *command.Context, start time.Time, res command.ProjectResult) {
+	e := audit.NewProjectEvent(audit.Event{
+		Time:      start,
+		Source:    audit.APISource,
+		Command:   audit.CommandString(res.Command, res.SubCommand),
+		Repo:      ctx.Pull.BaseRepo.FullName,
+		PullNum:   ctx.Pull.Num,
+		CommitSHA: ctx.Pull.HeadCommit,
+		Duration:  time.Since(start),
+	}, res)
+	audit.Record(a.Logger, a.AuditSink, e)
+}
 
-	// Validate the secret token
-	secret := r.Header.Get(atlantisTokenHeader)
-	if secret != string(a.APISecret) {
-		return nil, nil, http.StatusUnauthorized, fmt.Errorf("header %s did not match expected secret", atlantisTokenHeader)
+func (a *APIController) apiParseAndValidate(r *http.Request) (*APIRequest, *command.Context, int, error) {
+	if code, err := a.apiAuthenticate(r); err != nil {
+		return nil, nil, code, err
 	}
 
 	// Parse the JSON payload
@@ -193,23 +786,9 @@ func (a *APIController) apiParseAndValidate(r *http.Request) (*APIRequest, *comm
 		return nil, nil, http.StatusBadRequest, fmt.Errorf("request %q is missing fields", string(bytes))
 	}
 
-	VCSHostType, err := models.NewVCSHostType(request.Type)
+	baseRepo, code, err := a.apiParseRepo(request.Type, request.Repository)
 	if err != nil {
-		return nil, nil, http.StatusBadRequest, err
-	}
-	cloneURL, err := a.VCSClient.GetCloneURL(VCSHostType, request.Repository)
-	if err != nil {
-		return nil, nil, http.StatusInternalServerError, err
-	}
-
-	baseRepo, err := a.Parser.ParseAPIPlanRequest(VCSHostType, request.Repository, cloneURL)
-	if err != nil {
-		return nil, nil, http.StatusBadRequest, fmt.Errorf("failed to parse request: %v", err)
-	}
-
-	// Check if the repo is allowlisted
-	if !a.RepoAllowlistChecker.IsAllowlisted(baseRepo.FullName, baseRepo.VCSHost.Hostname) {
-		return nil, nil, http.StatusForbidden, fmt.Errorf("repo not allowlisted")
+		return nil, nil, code, err
 	}
 
 	return &request, &command.Context{
@@ -226,6 +805,55 @@ func (a *APIController) apiParseAndValidate(r *http.Request) (*APIRequest, *comm
 	}, http.StatusOK, nil
 }
 
+// apiAuthenticate checks that the API is enabled and that the request has the
+// API secret token.
+func (a *APIController) apiAuthenticate(r *http.Request) (int, error) {
+	if len(a.APISecret) == 0 {
+		return http.StatusBadRequest, fmt.Errorf("ignoring request since API is disabled")
+	}
+
+	// Validate the secret token
+	secret := r.Header.Get(atlantisTokenHeader)
+	if secret != string(a.APISecret) {
+		return http.StatusUnauthorized, fmt.Errorf("header %s did not match expected secret", atlantisTokenHeader)
+	}
+	return http.StatusOK, nil
+}
+
+// apiParseRepo parses the repo with the given VCS type and full name and
+// checks that it is allowlisted.
+func (a *APIController) apiParseRepo(vcsType string, repoFullName string) (models.Repo, int, error) {
+	VCSHostType, err := models.NewVCSHostType(vcsType)
+	if err != nil {
+		return models.Repo{}, http.StatusBadRequest, err
+	}
+	cloneURL, err := a.VCSClient.GetCloneURL(VCSHostType, repoFullName)
+	if err != nil {
+		return models.Repo{}, http.StatusInternalServerError, err
+	}
+
+	baseRepo, err := a.Parser.ParseAPIPlanRequest(VCSHostType, repoFullName, cloneURL)
+	if err != nil {
+		return models.Repo{}, http.StatusBadRequest, fmt.Errorf("failed to parse request: %v", err)
+	}
+
+	// Check if the repo is allowlisted
+	if !a.RepoAllowlistChecker.IsAllowlisted(baseRepo.FullName, baseRepo.VCSHost.Hostname) {
+		return models.Repo{}, http.StatusForbidden, fmt.Errorf("repo not allowlisted")
+	}
+	return baseRepo, http.StatusOK, nil
+}
+
+// apiRespondJSON responds with v encoded as JSON.
+func (a *APIController) apiRespondJSON(w http.ResponseWriter, code int, v interface{}) {
+	response, err := json.Marshal(v)
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, err)
+		return
+	}
+	a.respond(w, logging.Debug, code, "%s", string(response))
+}
+
 func (a *APIController) respond(w http.ResponseWriter, lvl logging.LogLevel, responseCode int, format string, args ...interface{}) {
 	response := fmt.Sprintf(format, args...)
 	a.Logger.Log(lvl, response)
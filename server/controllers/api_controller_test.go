@@ -3,18 +3,28 @@ package controllers_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/gorilla/mux"
 	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/audit"
 	"github.com/runatlantis/atlantis/server/controllers"
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/core/locking"
 	. "github.com/runatlantis/atlantis/server/core/locking/mocks"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/command"
 	. "github.com/runatlantis/atlantis/server/events/mocks"
 	"github.com/runatlantis/atlantis/server/events/models"
 	. "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	"github.com/runatlantis/atlantis/server/jobs"
+	jobmocks "github.com/runatlantis/atlantis/server/jobs/mocks"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/metrics"
 	. "github.com/runatlantis/atlantis/testing"
@@ -58,6 +68,489 @@ func TestAPIController_Apply(t *testing.T) {
 	projectCommandRunner.VerifyWasCalledOnce().Apply(Any[command.ProjectContext]())
 }
 
+func TestAPIController_PlanAsync(t *testing.T) {
+	ac, projectCommandBuilder, projectCommandRunner := setup(t)
+	backend, err := db.New(t.TempDir())
+	Ok(t, err)
+	ac.Backend = backend
+	When(projectCommandBuilder.BuildPlanCommands(Any[*command.Context](), Any[*events.CommentCommand]())).
+		ThenReturn([]command.ProjectContext{{
+			CommandName: command.Plan,
+			JobID:       "project-job",
+		}}, nil)
+
+	body, _ := json.Marshal(controllers.APIRequest{
+		Repository: "Repo",
+		Ref:        "main",
+		Type:       "Gitlab",
+		Projects:   []string{"default"},
+	})
+	req, _ := http.NewRequest("POST", "", bytes.NewBuffer(body))
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	w := httptest.NewRecorder()
+	ac.PlanAsync(w, req)
+	ResponseContains(t, w, http.StatusAccepted, `"Status":"pending"`)
+
+	var job models.APIJob
+	Ok(t, json.Unmarshal(w.Body.Bytes(), &job))
+	Equals(t, "plan", job.Command)
+	Assert(t, job.PullNum < 0, "exp job to get its own pull request number but got %d", job.PullNum)
+
+	// Wait for the job to finish.
+	ac.Drainer.ShutdownBlocking()
+	projectCommandRunner.VerifyWasCalledOnce().Plan(Any[command.ProjectContext]())
+	ctx, _ := projectCommandBuilder.VerifyWasCalledOnce().BuildPlanCommands(Any[*command.Context](), Any[*events.CommentCommand]()).GetCapturedArguments()
+	Equals(t, job.PullNum, ctx.Pull.Num)
+	// Only the job's own locks are released.
+	ac.Locker.(*MockLocker).VerifyWasCalledOnce().UnlockByPull(job.Repository, job.PullNum)
+
+	req, _ = http.NewRequest("GET", "", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": job.ID})
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	w = httptest.NewRecorder()
+	ac.GetAsyncJob(w, req)
+	ResponseContains(t, w, http.StatusOK, `"Status":"succeeded"`)
+	saved, err := backend.GetAPIJob(job.ID)
+	Ok(t, err)
+	Equals(t, []string{"project-job"}, saved.ProjectJobIDs)
+
+	w = httptest.NewRecorder()
+	ac.GetAsyncJobResult(w, req)
+	ResponseContains(t, w, http.StatusOK, `"ProjectResults"`)
+}
+
+// Test that finished jobs older than the retention are deleted once a job
+// finishes.
+func TestAPIController_PlanAsync_PrunesExpiredJobs(t *testing.T) {
+	ac, projectCommandBuilder, _ := setup(t)
+	backend, err := db.New(t.TempDir())
+	Ok(t, err)
+	ac.Backend = backend
+	ac.AsyncJobRetention = 24 * time.Hour
+	Ok(t, backend.UpdateAPIJob(models.APIJob{ID: "expired", Status: models.SucceededAPIJobStatus, UpdatedAt: time.Now().Add(-48 * time.Hour)}))
+	When(projectCommandBuilder.BuildPlanCommands(Any[*command.Context](), Any[*events.CommentCommand]())).
+		ThenReturn([]command.ProjectContext{{CommandName: command.Plan}}, nil)
+
+	body, _ := json.Marshal(controllers.APIRequest{
+		Repository: "Repo",
+		Ref:        "main",
+		Type:       "Gitlab",
+		Projects:   []string{"default"},
+	})
+	req, _ := http.NewRequest("POST", "", bytes.NewBuffer(body))
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	w := httptest.NewRecorder()
+	ac.PlanAsync(w, req)
+	Equals(t, http.StatusAccepted, w.Code)
+	var job models.APIJob
+	Ok(t, json.Unmarshal(w.Body.Bytes(), &job))
+	ac.Drainer.ShutdownBlocking()
+
+	expired, err := backend.GetAPIJob("expired")
+	Ok(t, err)
+	Assert(t, expired == nil, "exp expired job to be deleted")
+	finished, err := backend.GetAPIJob(job.ID)
+	Ok(t, err)
+	Assert(t, finished != nil, "exp finished job to be kept")
+}
+
+func TestAPIController_PlanAsync_ShuttingDown(t *testing.T) {
+	ac, _, _ := setup(t)
+	backend, err := db.New(t.TempDir())
+	Ok(t, err)
+	ac.Backend = backend
+	ac.Drainer.ShutdownBlocking()
+
+	body, _ := json.Marshal(controllers.APIRequest{
+		Repository: "Repo",
+		Ref:        "main",
+		Type:       "Gitlab",
+	})
+	req, _ := http.NewRequest("POST", "", bytes.NewBuffer(body))
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	w := httptest.NewRecorder()
+	ac.PlanAsync(w, req)
+	ResponseContains(t, w, http.StatusServiceUnavailable, "atlantis is shutting down")
+
+	jobs, err := backend.ListAPIJobs()
+	Ok(t, err)
+	Equals(t, 0, len(jobs))
+}
+
+func TestAPIController_RecoverAsyncJobs(t *testing.T) {
+	ac, _, _ := setup(t)
+	backend, err := db.New(t.TempDir())
+	Ok(t, err)
+	ac.Backend = backend
+	ac.AsyncJobRetention = 24 * time.Hour
+	now := time.Now()
+	Ok(t, backend.UpdateAPIJob(models.APIJob{ID: "running", Status: models.RunningAPIJobStatus, Repository: "owner/repo", PullNum: -5, UpdatedAt: now.Add(-48 * time.Hour)}))
+	Ok(t, backend.UpdateAPIJob(models.APIJob{ID: "expired", Status: models.SucceededAPIJobStatus, UpdatedAt: now.Add(-48 * time.Hour)}))
+	Ok(t, backend.UpdateAPIJob(models.APIJob{ID: "recent", Status: models.SucceededAPIJobStatus, UpdatedAt: now.Add(-time.Hour)}))
+
+	Ok(t, ac.RecoverAsyncJobs())
+
+	running, err := backend.GetAPIJob("running")
+	Ok(t, err)
+	Equals(t, models.FailedAPIJobStatus, running.Status)
+	Equals(t, "Atlantis restarted before the job finished", running.Error)
+	ac.Locker.(*MockLocker).VerifyWasCalledOnce().UnlockByPull("owner/repo", -5)
+
+	expired, err := backend.GetAPIJob("expired")
+	Ok(t, err)
+	Assert(t, expired == nil, "exp expired job to be deleted")
+	recent, err := backend.GetAPIJob("recent")
+	Ok(t, err)
+	Assert(t, recent != nil, "exp recent job to be kept")
+}
+
+func TestAPIController_GetAsyncJobResult(t *testing.T) {
+	ac, _, _ := setup(t)
+	backend, err := db.New(t.TempDir())
+	Ok(t, err)
+	ac.Backend = backend
+	Ok(t, backend.UpdateAPIJob(models.APIJob{ID: "running", Status: models.RunningAPIJobStatus}))
+	Ok(t, backend.UpdateAPIJob(models.APIJob{ID: "errored", Status: models.FailedAPIJobStatus, Error: "failed to build command"}))
+
+	cases := map[string]struct {
+		id      string
+		expCode int
+		expBody string
+	}{
+		"not finished": {"running", http.StatusConflict, `job \"running\" is running`},
+		"errored":      {"errored", http.StatusInternalServerError, "failed to build command"},
+		"missing":      {"missing", http.StatusNotFound, `no job found at id \"missing\"`},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": c.id})
+			req.Header.Set(atlantisTokenHeader, atlantisToken)
+			w := httptest.NewRecorder()
+			ac.GetAsyncJobResult(w, req)
+			ResponseContains(t, w, c.expCode, c.expBody)
+		})
+	}
+}
+
+func TestAPIController_Unauthorized(t *testing.T) {
+	ac, _, _ := setup(t)
+	req, _ := http.NewRequest("GET", "/api/locks", nil)
+	req.Header.Set(atlantisTokenHeader, "wrong")
+	w := httptest.NewRecorder()
+	ac.ListLocks(w, req)
+	ResponseContains(t, w, http.StatusUnauthorized, "did not match expected secret")
+}
+
+func TestAPIController_ListLocks(t *testing.T) {
+	ac, _, _ := setup(t)
+	locker := NewMockLocker()
+	ac.Locker = locker
+	lockTime := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	When(locker.List()).ThenReturn(map[string]models.ProjectLock{
+		"owner/repo/path/default": {
+			Project:   models.Project{RepoFullName: "owner/repo", Path: "path"},
+			Pull:      models.PullRequest{Num: 1, URL: "url"},
+			User:      models.User{Username: "user"},
+			Workspace: "default",
+			Time:      lockTime,
+		},
+	}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/locks", nil)
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	w := httptest.NewRecorder()
+	ac.ListLocks(w, req)
+	ResponseContains(t, w, http.StatusOK, "")
+
+	var locks []controllers.APILock
+	Ok(t, json.Unmarshal(w.Body.Bytes(), &locks))
+	Equals(t, []controllers.APILock{
+		{
+			ID:           "owner/repo/path/default",
+			RepoFullName: "owner/repo",
+			Path:         "path",
+			Workspace:    "default",
+			PullNum:      1,
+			PullURL:      "url",
+			LockedBy:     "user",
+			Time:         lockTime,
+		},
+	}, locks)
+}
+
+func TestAPIController_GetLock(t *testing.T) {
+	ac, _, _ := setup(t)
+	locker := NewMockLocker()
+	ac.Locker = locker
+	When(locker.GetLock("owner/repo/path/default")).ThenReturn(&models.ProjectLock{Workspace: "default"}, nil)
+
+	t.Run("found", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/locks?id=owner%2Frepo%2Fpath%2Fdefault", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.GetLock(w, req)
+		ResponseContains(t, w, http.StatusOK, `"ID":"owner/repo/path/default"`)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/locks?id=missing", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.GetLock(w, req)
+		ResponseContains(t, w, http.StatusNotFound, `no lock found at id \"missing\"`)
+	})
+
+	t.Run("no id", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/locks?id=", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.GetLock(w, req)
+		ResponseContains(t, w, http.StatusBadRequest, "no lock id in request")
+	})
+}
+
+func TestAPIController_DeleteLock(t *testing.T) {
+	ac, _, _ := setup(t)
+	dataDir := t.TempDir()
+	backend, err := db.New(dataDir)
+	Ok(t, err)
+	workingDir := &events.FileWorkspace{DataDir: dataDir}
+	locker := NewMockLocker()
+	ac.DeleteLockCommand = &events.DefaultDeleteLockCommand{
+		Locker:           locker,
+		Logger:           ac.Logger,
+		WorkingDir:       workingDir,
+		WorkingDirLocker: events.NewDefaultWorkingDirLocker(),
+		Backend:          backend,
+	}
+	repo := models.Repo{FullName: "owner/repo"}
+	pull := models.PullRequest{Num: 1, BaseRepo: repo}
+	When(locker.Unlock("owner/repo/path/default")).ThenReturn(&models.ProjectLock{
+		Project:   models.NewProject("owner/repo", "path"),
+		Workspace: "default",
+		Pull:      pull,
+	}, nil)
+
+	planFile := filepath.Join(dataDir, "repos", "owner", "repo", "1", "default", "path", "default.tfplan")
+	Ok(t, os.MkdirAll(filepath.Dir(planFile), 0700))
+	Ok(t, os.WriteFile(planFile, []byte("plan"), 0600))
+
+	req, _ := http.NewRequest("DELETE", "/api/locks?id=owner%2Frepo%2Fpath%2Fdefault", nil)
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	w := httptest.NewRecorder()
+	ac.DeleteLock(w, req)
+	ResponseContains(t, w, http.StatusOK, `"ID":"owner/repo/path/default"`)
+	locker.VerifyWasCalledOnce().Unlock("owner/repo/path/default")
+	_, err = os.Stat(planFile)
+	Assert(t, os.IsNotExist(err), "exp plan file to be deleted, got %v", err)
+}
+
+func TestAPIController_ListAuditEvents(t *testing.T) {
+	ac, _, _ := setup(t)
+
+	t.Run("disabled", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/audit", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.ListAuditEvents(w, req)
+		ResponseContains(t, w, http.StatusNotFound, "audit log is not enabled")
+	})
+
+	sink, err := audit.NewFileSink(filepath.Join(t.TempDir(), "audit.jsonl"))
+	Ok(t, err)
+	ac.AuditSink = sink
+	ac.AuditStore = sink
+	locker := NewMockLocker()
+	ac.DeleteLockCommand = &events.DefaultDeleteLockCommand{Locker: locker, Logger: ac.Logger}
+	When(locker.Unlock("owner/repo/path/default")).ThenReturn(&models.ProjectLock{
+		Project:   models.NewProject("owner/repo", "path"),
+		Workspace: "default",
+		Pull:      models.PullRequest{Num: 1},
+	}, nil)
+	Ok(t, sink.Record(audit.Event{Command: "plan", Repo: "owner/other", Time: time.Now().Add(-time.Hour)}))
+
+	req, _ := http.NewRequest("DELETE", "/api/locks?id=owner%2Frepo%2Fpath%2Fdefault", nil)
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	ac.DeleteLock(httptest.NewRecorder(), req)
+
+	t.Run("filtered", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/audit?repository=owner/repo&pr=1", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.ListAuditEvents(w, req)
+		ResponseContains(t, w, http.StatusOK, "")
+
+		var events []audit.Event
+		Ok(t, json.Unmarshal(w.Body.Bytes(), &events))
+		Equals(t, 1, len(events))
+		Equals(t, audit.APISource, events[0].Source)
+		Equals(t, "unlock", events[0].Command)
+		Equals(t, "path", events[0].Directory)
+		Equals(t, "default", events[0].Workspace)
+	})
+
+	t.Run("all", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/audit?limit=10", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.ListAuditEvents(w, req)
+		var events []audit.Event
+		Ok(t, json.Unmarshal(w.Body.Bytes(), &events))
+		Equals(t, 2, len(events))
+		Equals(t, "owner/other", events[1].Repo)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		for _, query := range []string{"pr=one", "limit=0", "since=yesterday", "until=tomorrow"} {
+			req, _ := http.NewRequest("GET", "/api/audit?"+query, nil)
+			req.Header.Set(atlantisTokenHeader, atlantisToken)
+			w := httptest.NewRecorder()
+			ac.ListAuditEvents(w, req)
+			Equals(t, http.StatusBadRequest, w.Code)
+		}
+	})
+}
+
+func TestAPIController_GetPullStatus(t *testing.T) {
+	ac, _, _ := setup(t)
+	backend := NewMockBackend()
+	ac.Backend = backend
+	parser := NewMockEventParsing()
+	ac.Parser = parser
+	repo := models.Repo{
+		FullName: "owner/repo",
+		VCSHost:  models.VCSHost{Hostname: "github.com", Type: models.Github},
+	}
+	When(parser.ParseAPIPlanRequest(Eq(models.Github), Eq("owner/repo"), Any[string]())).ThenReturn(repo, nil)
+	When(backend.GetPullStatus(models.PullRequest{Num: 1, BaseRepo: repo})).ThenReturn(&models.PullStatus{
+		Pull: models.PullRequest{HeadCommit: "sha"},
+		Projects: []models.ProjectStatus{
+			{RepoRelDir: ".", Workspace: "default", Status: models.PlannedPlanStatus},
+		},
+	}, nil)
+
+	t.Run("found", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/pull?repository=owner/repo&type=Github&pr=1", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.GetPullStatus(w, req)
+		ResponseContains(t, w, http.StatusOK, "")
+
+		var status controllers.APIPullStatus
+		Ok(t, json.Unmarshal(w.Body.Bytes(), &status))
+		Equals(t, controllers.APIPullStatus{
+			Repository: "owner/repo",
+			PR:         1,
+			HeadCommit: "sha",
+			Projects: []controllers.APIProjectStatus{
+				{RepoRelDir: ".", Workspace: "default", Status: "planned"},
+			},
+		}, status)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/pull?repository=owner/repo&type=Github&pr=2", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.GetPullStatus(w, req)
+		ResponseContains(t, w, http.StatusNotFound, "no status found for owner/repo#2")
+	})
+
+	t.Run("invalid pr", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/pull?repository=owner/repo&type=Github&pr=abc", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.GetPullStatus(w, req)
+		ResponseContains(t, w, http.StatusBadRequest, "invalid pr query parameter")
+	})
+}
+
+func TestAPIController_ListJobs(t *testing.T) {
+	ac, _, _ := setup(t)
+	outputHandler := jobmocks.NewMockProjectCommandOutputHandler()
+	ac.ProjectCmdOutputHandler = outputHandler
+	When(outputHandler.Jobs()).ThenReturn([]jobs.Job{
+		{JobID: "2", PullInfo: jobs.PullInfo{PullNum: 1, Repo: "repo"}, Complete: true},
+		{JobID: "1", PullInfo: jobs.PullInfo{PullNum: 1, Repo: "repo"}},
+	})
+
+	req, _ := http.NewRequest("GET", "/api/jobs", nil)
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	w := httptest.NewRecorder()
+	ac.ListJobs(w, req)
+	ResponseContains(t, w, http.StatusOK, "")
+
+	var jobList []jobs.Job
+	Ok(t, json.Unmarshal(w.Body.Bytes(), &jobList))
+	Equals(t, []jobs.Job{
+		{JobID: "1", PullInfo: jobs.PullInfo{PullNum: 1, Repo: "repo"}},
+		{JobID: "2", PullInfo: jobs.PullInfo{PullNum: 1, Repo: "repo"}, Complete: true},
+	}, jobList)
+}
+
+func TestAPIController_ListDrift(t *testing.T) {
+	ac, _, _ := setup(t)
+	vcsClient := NewMockClient()
+	When(vcsClient.GetCloneURL(models.Github, "runatlantis/atlantis")).ThenReturn("", errors.New("not found"))
+	ac.DriftDetector = &events.DriftDetector{
+		VCSClient: vcsClient,
+		Logger:    ac.Logger,
+	}
+
+	req, _ := http.NewRequest("GET", "/api/drift", nil)
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	w := httptest.NewRecorder()
+	ac.ListDrift(w, req)
+	ResponseContains(t, w, http.StatusOK, "[]")
+
+	drift := ac.DriftDetector.Detect(models.Github, "runatlantis/atlantis", "main")
+	w = httptest.NewRecorder()
+	ac.ListDrift(w, req)
+	Equals(t, http.StatusOK, w.Code)
+
+	var results []models.RepoDrift
+	Ok(t, json.Unmarshal(w.Body.Bytes(), &results))
+	Equals(t, 1, len(results))
+	Equals(t, "runatlantis/atlantis", results[0].Repository)
+	Equals(t, "main", results[0].Branch)
+	Equals(t, drift.Error, results[0].Error)
+}
+
+func TestAPIController_ApplyLock(t *testing.T) {
+	ac, _, _ := setup(t)
+	applyLocker := NewMockApplyLocker()
+	ac.ApplyLocker = applyLocker
+
+	t.Run("lock", func(t *testing.T) {
+		When(applyLocker.LockApply()).ThenReturn(locking.ApplyCommandLock{Locked: true}, nil)
+		req, _ := http.NewRequest("POST", "/api/apply/lock", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.LockApply(w, req)
+		ResponseContains(t, w, http.StatusOK, `"Locked":true`)
+	})
+
+	t.Run("unlock", func(t *testing.T) {
+		When(applyLocker.CheckApplyLock()).ThenReturn(locking.ApplyCommandLock{Locked: false}, nil)
+		req, _ := http.NewRequest("DELETE", "/api/apply/lock", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.UnlockApply(w, req)
+		ResponseContains(t, w, http.StatusOK, `"Locked":false`)
+		applyLocker.VerifyWasCalledOnce().UnlockApply()
+	})
+
+	t.Run("error", func(t *testing.T) {
+		When(applyLocker.LockApply()).ThenReturn(locking.ApplyCommandLock{}, errors.New("DisableApplyFlag is set"))
+		req, _ := http.NewRequest("POST", "/api/apply/lock", nil)
+		req.Header.Set(atlantisTokenHeader, atlantisToken)
+		w := httptest.NewRecorder()
+		ac.LockApply(w, req)
+		ResponseContains(t, w, http.StatusInternalServerError, "DisableApplyFlag is set")
+	})
+}
+
 func setup(t *testing.T) (controllers.APIController, *MockProjectCommandBuilder, *MockProjectCommandRunner) {
 	RegisterMockTestingT(t)
 	locker := NewMockLocker()
@@ -88,7 +581,9 @@ func setup(t *testing.T) (controllers.APIController, *MockProjectCommandBuilder,
 
 	ac := controllers.APIController{
 		APISecret:                 []byte(atlantisToken),
+		Drainer:                   &events.Drainer{},
 		Locker:                    locker,
+		DeleteLockCommand:         &events.DefaultDeleteLockCommand{Locker: locker, Logger: logger},
 		Logger:                    logger,
 		Scope:                     scope,
 		Parser:                    parser,
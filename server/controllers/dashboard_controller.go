@@ -0,0 +1,244 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/runatlantis/atlantis/server/controllers/templates"
+	"github.com/runatlantis/atlantis/server/controllers/websocket"
+	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/metrics"
+	tally "github.com/uber-go/tally/v4"
+)
+
+// dashboardPartitionKey is the websocket partition that all dashboards
+// receive updates on.
+const dashboardPartitionKey = "pulls"
+
+// dashboardStatuses are the project statuses the dashboard can be filtered
+// by and their labels.
+var dashboardStatuses = []struct {
+	Status models.ProjectPlanStatus
+	Label  string
+}{
+	{models.PlannedPlanStatus, "Planned, pending apply"},
+	{models.AppliedPlanStatus, "Applied"},
+	{models.ErroredPlanStatus, "Plan errored"},
+	{models.ErroredApplyStatus, "Apply errored"},
+	{models.PassedPolicyCheckStatus, "Policy check passed"},
+	{models.ErroredPolicyCheckStatus, "Policy check errored"},
+	{models.DiscardedPlanStatus, "Plan discarded"},
+}
+
+// DashboardKeyGenerator puts every dashboard websocket in the same
+// partition.
+type DashboardKeyGenerator struct{}
+
+func (g DashboardKeyGenerator) Generate(_ *http.Request) (string, error) {
+	return dashboardPartitionKey, nil
+}
+
+// DashboardUpdates tells the open dashboards when the status of a pull
+// request changes so that they can be refreshed. It's the registry of the
+// dashboard's websocket multiplexor.
+type DashboardUpdates struct {
+	mu        sync.Mutex
+	receivers map[chan string]bool
+}
+
+// NewDashboardUpdates returns a DashboardUpdates without any receivers.
+func NewDashboardUpdates() *DashboardUpdates {
+	return &DashboardUpdates{
+		receivers: make(map[chan string]bool),
+	}
+}
+
+func (d *DashboardUpdates) Register(_ string, buffer chan string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.receivers[buffer] = true
+}
+
+func (d *DashboardUpdates) Deregister(_ string, buffer chan string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.receivers, buffer)
+}
+
+func (d *DashboardUpdates) IsKeyExists(key string) bool {
+	return key == dashboardPartitionKey
+}
+
+// PullStatusChanged sends the ID of pull to every open dashboard. Dashboards
+// that are behind on reading their updates miss it.
+func (d *DashboardUpdates) PullStatusChanged(pull models.PullRequest) {
+	msg := fmt.Sprintf("%s#%d", pull.BaseRepo.FullName, pull.Num)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for buffer := range d.receivers {
+		select {
+		case buffer <- msg:
+		default:
+		}
+	}
+}
+
+// DashboardController serves the dashboard of the pull requests that
+// Atlantis has run commands on.
+type DashboardController struct {
+	AtlantisVersion   string
+	AtlantisURL       *url.URL
+	Logger            logging.SimpleLogging
+	DashboardTemplate templates.TemplateWriter
+	Backend           locking.Backend
+	// WsMux streams DashboardUpdates to the dashboards.
+	WsMux      *websocket.Multiplexor
+	StatsScope tally.Scope
+}
+
+// Get renders the dashboard, filtered by the repo, status and user query
+// params.
+func (d *DashboardController) Get(w http.ResponseWriter, r *http.Request) {
+	errorCounter := d.StatsScope.SubScope("getdashboard").Counter(metrics.ExecutionErrorMetric)
+	statuses, err := d.Backend.ListPullStatuses()
+	if err != nil {
+		errorCounter.Inc(1)
+		d.respond(w, logging.Error, http.StatusServiceUnavailable, "Could not retrieve pull requests: %s", err)
+		return
+	}
+
+	query := r.URL.Query()
+	filters := templates.DashboardFilters{
+		Repo:   strings.TrimSpace(query.Get("repo")),
+		Status: query.Get("status"),
+		User:   strings.TrimSpace(query.Get("user")),
+	}
+	data := templates.DashboardData{
+		Repos:           dashboardRepos(statuses, filters),
+		Filters:         filters,
+		AtlantisVersion: d.AtlantisVersion,
+		CleanedBasePath: d.AtlantisURL.Path,
+	}
+	for _, s := range dashboardStatuses {
+		data.Statuses = append(data.Statuses, templates.DashboardStatusOption{
+			Value:    s.Status.String(),
+			Label:    s.Label,
+			Selected: s.Status.String() == filters.Status,
+		})
+	}
+	if err := d.DashboardTemplate.Execute(w, data); err != nil {
+		errorCounter.Inc(1)
+		d.Logger.Err(err.Error())
+	}
+}
+
+// GetUpdatesWS streams a message to the dashboard whenever the status of a
+// pull request changes.
+func (d *DashboardController) GetUpdatesWS(w http.ResponseWriter, r *http.Request) {
+	errorCounter := d.StatsScope.SubScope("getdashboardws").Counter(metrics.ExecutionErrorMetric)
+	if err := d.WsMux.Handle(w, r); err != nil {
+		errorCounter.Inc(1)
+		d.respond(w, logging.Error, http.StatusInternalServerError, err.Error())
+	}
+}
+
+func (d *DashboardController) respond(w http.ResponseWriter, lvl logging.LogLevel, responseCode int, format string, args ...interface{}) {
+	response := fmt.Sprintf(format, args...)
+	d.Logger.Log(lvl, response)
+	w.WriteHeader(responseCode)
+	fmt.Fprintln(w, response)
+}
+
+// dashboardRepos returns the open pull requests in statuses that match
+// filters, grouped by repo. Repos are sorted by name and pull requests
+// newest first.
+func dashboardRepos(statuses []models.PullStatus, filters templates.DashboardFilters) []templates.DashboardRepoData {
+	byRepo := make(map[string][]templates.DashboardPullData)
+	for _, s := range statuses {
+		if s.Pull.State == models.ClosedPullState {
+			continue
+		}
+		if filters.Repo != "" && !strings.Contains(strings.ToLower(s.Pull.BaseRepo.FullName), strings.ToLower(filters.Repo)) {
+			continue
+		}
+		// The user filter matches the pull request if the user is its
+		// author, otherwise it matches the projects they planned.
+		isAuthor := filters.User == "" || strings.EqualFold(s.Pull.Author, filters.User)
+
+		var projects []templates.DashboardProjectData
+		for _, p := range s.Projects {
+			if filters.Status != "" && p.Status.String() != filters.Status {
+				continue
+			}
+			lastPlan := p.LastPlan()
+			if !isAuthor && (lastPlan == nil || !strings.EqualFold(lastPlan.User, filters.User)) {
+				continue
+			}
+			projects = append(projects, dashboardProject(p))
+		}
+		if len(projects) == 0 {
+			continue
+		}
+		repo := s.Pull.BaseRepo.FullName
+		byRepo[repo] = append(byRepo[repo], templates.DashboardPullData{
+			Num:        s.Pull.Num,
+			URL:        s.Pull.URL,
+			Author:     s.Pull.Author,
+			HeadBranch: s.Pull.HeadBranch,
+			BaseBranch: s.Pull.BaseBranch,
+			Projects:   projects,
+		})
+	}
+
+	var repos []templates.DashboardRepoData
+	for name, pulls := range byRepo {
+		sort.Slice(pulls, func(i, j int) bool { return pulls[i].Num > pulls[j].Num })
+		repos = append(repos, templates.DashboardRepoData{
+			RepoFullName: name,
+			Pulls:        pulls,
+		})
+	}
+	sort.Slice(repos, func(i, j int) bool { return repos[i].RepoFullName < repos[j].RepoFullName })
+	return repos
+}
+
+func dashboardProject(p models.ProjectStatus) templates.DashboardProjectData {
+	name := p.ProjectName
+	if name == "" {
+		name = filepath.Clean(p.RepoRelDir)
+	}
+	project := templates.DashboardProjectData{
+		Name:      name,
+		Workspace: p.Workspace,
+		Status:    p.Status.String(),
+		JobID:     p.JobID,
+	}
+	for i := len(p.PlanHistory) - 1; i >= 0; i-- {
+		plan := dashboardPlan(p.PlanHistory[i])
+		if project.LastPlan == nil {
+			project.LastPlan = &plan
+			continue
+		}
+		project.PlanHistory = append(project.PlanHistory, plan)
+	}
+	return project
+}
+
+func dashboardPlan(p models.PlanRecord) templates.DashboardPlanData {
+	return templates.DashboardPlanData{
+		User:               p.User,
+		JobID:              p.JobID,
+		PlannedAtFormatted: p.PlannedAt.Format("02-01-2006 15:04:05"),
+		Add:                p.Stats.Add,
+		Change:             p.Stats.Change,
+		Destroy:            p.Stats.Destroy,
+		Changes:            p.Stats.Changes,
+	}
+}
@@ -0,0 +1,210 @@
+package controllers_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/controllers"
+	"github.com/runatlantis/atlantis/server/controllers/templates"
+	tMocks "github.com/runatlantis/atlantis/server/controllers/templates/mocks"
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/core/locking/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+	tally "github.com/uber-go/tally/v4"
+)
+
+func TestDashboardController_Get(t *testing.T) {
+	backend, err := db.New(t.TempDir())
+	Ok(t, err)
+	plannedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	infra := models.Repo{FullName: "acme/infra", VCSHost: models.VCSHost{Hostname: "github.com"}}
+	app := models.Repo{FullName: "acme/app", VCSHost: models.VCSHost{Hostname: "github.com"}}
+
+	_, err = backend.UpdatePullWithResults(models.PullRequest{Num: 1, Author: "alice", URL: "url1", HeadBranch: "feature", BaseBranch: "main", BaseRepo: infra}, []command.ProjectResult{
+		{
+			Command:     command.Plan,
+			RepoRelDir:  "staging",
+			Workspace:   "default",
+			ProjectName: "staging",
+			User:        "alice",
+			JobID:       "job1",
+			PlanSuccess: &models.PlanSuccess{PlannedAt: plannedAt},
+		},
+		{
+			Command:    command.Plan,
+			RepoRelDir: "./prod",
+			Workspace:  "default",
+			User:       "alice",
+			JobID:      "job2",
+			Error:      errors.New("err"),
+		},
+	})
+	Ok(t, err)
+	_, err = backend.UpdatePullWithResults(models.PullRequest{Num: 2, Author: "bob", BaseRepo: infra}, []command.ProjectResult{
+		{
+			Command:     command.Plan,
+			RepoRelDir:  ".",
+			Workspace:   "default",
+			User:        "carol",
+			JobID:       "job3",
+			PlanSuccess: &models.PlanSuccess{PlannedAt: plannedAt},
+		},
+	})
+	Ok(t, err)
+	_, err = backend.UpdatePullWithResults(models.PullRequest{Num: 3, Author: "bob", BaseRepo: app}, []command.ProjectResult{
+		{
+			Command:      command.Apply,
+			RepoRelDir:   ".",
+			Workspace:    "default",
+			JobID:        "job4",
+			ApplySuccess: "applied",
+		},
+	})
+	Ok(t, err)
+
+	alicePlan := &templates.DashboardPlanData{User: "alice", JobID: "job1", PlannedAtFormatted: "02-01-2024 03:04:05"}
+	carolPlan := &templates.DashboardPlanData{User: "carol", JobID: "job3", PlannedAtFormatted: "02-01-2024 03:04:05"}
+	pull1 := templates.DashboardPullData{Num: 1, URL: "url1", Author: "alice", HeadBranch: "feature", BaseBranch: "main"}
+	staging := templates.DashboardProjectData{Name: "staging", Workspace: "default", Status: "planned", JobID: "job1", LastPlan: alicePlan}
+	prod := templates.DashboardProjectData{Name: "prod", Workspace: "default", Status: "plan_errored", JobID: "job2"}
+	pull2 := templates.DashboardPullData{Num: 2, Author: "bob", Projects: []templates.DashboardProjectData{
+		{Name: ".", Workspace: "default", Status: "planned", JobID: "job3", LastPlan: carolPlan},
+	}}
+	pull3 := templates.DashboardPullData{Num: 3, Author: "bob", Projects: []templates.DashboardProjectData{
+		{Name: ".", Workspace: "default", Status: "applied", JobID: "job4"},
+	}}
+	withProjects := func(pull templates.DashboardPullData, projects ...templates.DashboardProjectData) templates.DashboardPullData {
+		pull.Projects = projects
+		return pull
+	}
+
+	cases := []struct {
+		description string
+		query       string
+		filters     templates.DashboardFilters
+		expRepos    []templates.DashboardRepoData
+	}{
+		{
+			description: "no filters",
+			expRepos: []templates.DashboardRepoData{
+				{RepoFullName: "acme/app", Pulls: []templates.DashboardPullData{pull3}},
+				{RepoFullName: "acme/infra", Pulls: []templates.DashboardPullData{pull2, withProjects(pull1, staging, prod)}},
+			},
+		},
+		{
+			description: "repo",
+			query:       "repo=INFRA",
+			filters:     templates.DashboardFilters{Repo: "INFRA"},
+			expRepos: []templates.DashboardRepoData{
+				{RepoFullName: "acme/infra", Pulls: []templates.DashboardPullData{pull2, withProjects(pull1, staging, prod)}},
+			},
+		},
+		{
+			description: "status",
+			query:       "status=planned",
+			filters:     templates.DashboardFilters{Status: "planned"},
+			expRepos: []templates.DashboardRepoData{
+				{RepoFullName: "acme/infra", Pulls: []templates.DashboardPullData{pull2, withProjects(pull1, staging)}},
+			},
+		},
+		{
+			description: "author",
+			query:       "user=bob",
+			filters:     templates.DashboardFilters{User: "bob"},
+			expRepos: []templates.DashboardRepoData{
+				{RepoFullName: "acme/app", Pulls: []templates.DashboardPullData{pull3}},
+				{RepoFullName: "acme/infra", Pulls: []templates.DashboardPullData{pull2}},
+			},
+		},
+		{
+			description: "planned by",
+			query:       "user=carol",
+			filters:     templates.DashboardFilters{User: "carol"},
+			expRepos: []templates.DashboardRepoData{
+				{RepoFullName: "acme/infra", Pulls: []templates.DashboardPullData{pull2}},
+			},
+		},
+		{
+			description: "no matches",
+			query:       "repo=infra&status=applied",
+			filters:     templates.DashboardFilters{Repo: "infra", Status: "applied"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpl := tMocks.NewMockTemplateWriter()
+			atlantisURL, _ := url.Parse("https://example.com/basepath")
+			dc := controllers.DashboardController{
+				AtlantisVersion:   "1300135",
+				AtlantisURL:       atlantisURL,
+				Logger:            logging.NewNoopLogger(t),
+				DashboardTemplate: tmpl,
+				Backend:           backend,
+				StatsScope:        tally.NewTestScope("test", nil),
+			}
+			req, _ := http.NewRequest("GET", "/dashboard?"+c.query, nil)
+			w := httptest.NewRecorder()
+			dc.Get(w, req)
+
+			_, data := tmpl.VerifyWasCalledOnce().Execute(Any[io.Writer](), Any[interface{}]()).GetCapturedArguments()
+			dashboard := data.(templates.DashboardData)
+			Equals(t, c.expRepos, dashboard.Repos)
+			Equals(t, c.filters, dashboard.Filters)
+			Equals(t, "1300135", dashboard.AtlantisVersion)
+			Equals(t, "/basepath", dashboard.CleanedBasePath)
+			for _, s := range dashboard.Statuses {
+				Equals(t, c.filters.Status != "" && s.Value == c.filters.Status, s.Selected)
+			}
+		})
+	}
+}
+
+func TestDashboardController_GetBackendErr(t *testing.T) {
+	RegisterMockTestingT(t)
+	backend := mocks.NewMockBackend()
+	When(backend.ListPullStatuses()).ThenReturn(nil, errors.New("err"))
+	atlantisURL, _ := url.Parse("https://example.com")
+	dc := controllers.DashboardController{
+		AtlantisURL:       atlantisURL,
+		Logger:            logging.NewNoopLogger(t),
+		DashboardTemplate: tMocks.NewMockTemplateWriter(),
+		Backend:           backend,
+		StatsScope:        tally.NewTestScope("test", nil),
+	}
+	req, _ := http.NewRequest("GET", "/dashboard", nil)
+	w := httptest.NewRecorder()
+	dc.Get(w, req)
+	ResponseContains(t, w, http.StatusServiceUnavailable, "Could not retrieve pull requests: err")
+}
+
+func TestDashboardUpdates(t *testing.T) {
+	updates := controllers.NewDashboardUpdates()
+	key, err := controllers.DashboardKeyGenerator{}.Generate(nil)
+	Ok(t, err)
+	Equals(t, true, updates.IsKeyExists(key))
+	Equals(t, false, updates.IsKeyExists("job-id"))
+
+	pull := models.PullRequest{Num: 1, BaseRepo: models.Repo{FullName: "acme/infra"}}
+	buffer := make(chan string, 1)
+	full := make(chan string)
+	updates.Register(key, buffer)
+	updates.Register(key, full)
+
+	// Receivers that aren't keeping up don't block the others.
+	updates.PullStatusChanged(pull)
+	Equals(t, "acme/infra#1", <-buffer)
+
+	updates.Deregister(key, buffer)
+	updates.PullStatusChanged(pull)
+	Equals(t, 0, len(buffer))
+}
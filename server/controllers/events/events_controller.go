@@ -14,6 +14,7 @@
 package events
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -28,6 +29,7 @@ import (
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketcloud"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketserver"
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
 	"github.com/runatlantis/atlantis/server/logging"
 	tally "github.com/uber-go/tally/v4"
 	gitlab "github.com/xanzy/go-gitlab"
@@ -49,7 +51,10 @@ const azuredevopsTestURL = "https://fabrikam.visualstudio.com/DefaultCollection/
 // VCSEventsController handles all webhook requests which signify 'events' in the
 // VCS host, ex. GitHub.
 type VCSEventsController struct {
-	CommandRunner  events.CommandRunner
+	CommandRunner events.CommandRunner
+	// CommandQueue persists commands before the event is acknowledged. If
+	// nil, commands are run directly.
+	CommandQueue   events.CommandQueue
 	PullCleaner    events.PullCleaner
 	Logger         logging.SimpleLogging
 	Scope          tally.Scope
@@ -91,11 +96,25 @@ type VCSEventsController struct {
 	// Azure DevOps Team Project. If empty, no request validation is done.
 	AzureDevopsWebhookBasicPassword []byte
 	AzureDevopsRequestValidator     AzureDevopsRequestValidator
+	// GiteaWebhookSecret is the secret added to this webhook via the Gitea
+	// UI that identifies this call as coming from Gitea. If empty, no
+	// request validation is done.
+	GiteaWebhookSecret []byte
 }
 
 // Post handles POST webhook requests.
 func (e *VCSEventsController) Post(w http.ResponseWriter, r *http.Request) {
-	if r.Header.Get(githubHeader) != "" {
+	// Gitea and Forgejo also send the GitHub event header for compatibility so
+	// they must be checked first.
+	if r.Header.Get(gitea.EventHeader) != "" || r.Header.Get(gitea.ForgejoEventHeader) != "" {
+		if !e.supportsHost(models.Gitea) {
+			e.respond(w, logging.Debug, http.StatusBadRequest, "Ignoring request since not configured to support Gitea")
+			return
+		}
+		e.Logger.Debug("handling Gitea post")
+		e.handleGiteaPost(w, r)
+		return
+	} else if r.Header.Get(githubHeader) != "" {
 		if !e.supportsHost(models.Github) {
 			e.respond(w, logging.Debug, http.StatusBadRequest, "Ignoring request since not configured to support GitHub")
 			return
@@ -181,6 +200,14 @@ func (e *VCSEventsController) handleGithubPost(w http.ResponseWriter, r *http.Re
 		resp = e.HandleGithubPullRequestEvent(logger, event, githubReqID)
 		scope = scope.SubScope(fmt.Sprintf("pr_%s", *event.Action))
 		scope = vcs.SetGitScopeTags(scope, event.GetRepo().GetFullName(), event.GetNumber())
+	case *github.CheckRunEvent:
+		resp = e.HandleGithubCheckRunEvent(logger, event, githubReqID)
+		scope = scope.SubScope(fmt.Sprintf("check_run_%s", event.GetAction()))
+		var pullNum int
+		if checkRun := event.GetCheckRun(); checkRun != nil && len(checkRun.PullRequests) > 0 {
+			pullNum = checkRun.PullRequests[0].GetNumber()
+		}
+		scope = vcs.SetGitScopeTags(scope, event.GetRepo().GetFullName(), pullNum)
 	default:
 		resp = HTTPResponse{
 			body: fmt.Sprintf("Ignoring unsupported event %s", githubReqID),
@@ -261,6 +288,37 @@ func (e *VCSEventsController) handleBitbucketServerPost(w http.ResponseWriter, r
 	}
 }
 
+func (e *VCSEventsController) handleGiteaPost(w http.ResponseWriter, r *http.Request) {
+	eventType := r.Header.Get(gitea.EventHeader)
+	if eventType == "" {
+		eventType = r.Header.Get(gitea.ForgejoEventHeader)
+	}
+	reqID := r.Header.Get(gitea.DeliveryHeader)
+	sig := r.Header.Get(gitea.SignatureHeader)
+	defer r.Body.Close() // nolint: errcheck
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		e.respond(w, logging.Error, http.StatusBadRequest, "Unable to read body: %s %s=%s", err, gitea.DeliveryHeader, reqID)
+		return
+	}
+	if len(e.GiteaWebhookSecret) > 0 {
+		if err := gitea.ValidateSignature(body, sig, e.GiteaWebhookSecret); err != nil {
+			e.respond(w, logging.Warn, http.StatusBadRequest, errors.Wrap(err, "request did not pass validation").Error())
+			return
+		}
+	}
+	switch eventType {
+	case gitea.PullRequestEventType:
+		e.Logger.Debug("handling as pull request event")
+		e.HandleGiteaPullRequestEvent(w, body, reqID)
+	case gitea.IssueCommentEventType:
+		e.Logger.Debug("handling as comment event")
+		e.HandleGiteaCommentEvent(w, body, reqID)
+	default:
+		e.respond(w, logging.Debug, http.StatusOK, "Ignoring unsupported event type %s %s=%s", eventType, gitea.DeliveryHeader, reqID)
+	}
+}
+
 func (e *VCSEventsController) handleAzureDevopsPost(w http.ResponseWriter, r *http.Request) {
 	// Validate the request against the optional basic auth username and password.
 	payload, err := e.AzureDevopsRequestValidator.Validate(r, e.AzureDevopsWebhookBasicUser, e.AzureDevopsWebhookBasicPassword)
@@ -318,6 +376,50 @@ func (e *VCSEventsController) HandleGithubCommentEvent(event *github.IssueCommen
 	return e.handleCommentEvent(logger, baseRepo, nil, nil, user, pullNum, comment.GetBody(), comment.GetID(), models.Github)
 }
 
+// HandleGithubCheckRunEvent handles buttons being clicked on the check runs
+// created when GitHub Checks are enabled. It's exported to make testing easier.
+func (e *VCSEventsController) HandleGithubCheckRunEvent(logger logging.SimpleLogging, event *github.CheckRunEvent, githubReqID string) HTTPResponse {
+	if event.GetAction() != "requested_action" {
+		return HTTPResponse{
+			body: fmt.Sprintf("Ignoring check run event since action was not requested_action %s", githubReqID),
+		}
+	}
+
+	baseRepo, user, pullNum, cmd, err := e.Parser.ParseGithubCheckRunEvent(event)
+	if err != nil {
+		wrapped := errors.Wrapf(err, "Failed parsing event: %s", githubReqID)
+		return HTTPResponse{
+			body: wrapped.Error(),
+			err: HTTPError{
+				code:       http.StatusBadRequest,
+				err:        wrapped,
+				isSilenced: false,
+			},
+		}
+	}
+	logger.Info("parsed check run action as %s", cmd)
+
+	if !e.RepoAllowlistChecker.IsAllowlisted(baseRepo.FullName, baseRepo.VCSHost.Hostname) {
+		err := errors.New("Repo not allowlisted")
+		return HTTPResponse{
+			body: err.Error(),
+			err: HTTPError{
+				err:        err,
+				code:       http.StatusForbidden,
+				isSilenced: e.SilenceAllowlistErrors,
+			},
+		}
+	}
+	if e.ApplyDisabled {
+		return HTTPResponse{
+			body: "Ignoring check run action since apply is disabled",
+		}
+	}
+
+	logger.Debug("executing command")
+	return e.runCommentCommand(baseRepo, nil, nil, user, pullNum, cmd)
+}
+
 // HandleBitbucketCloudCommentEvent handles comment events from Bitbucket.
 func (e *VCSEventsController) HandleBitbucketCloudCommentEvent(w http.ResponseWriter, body []byte, reqID string) {
 	pull, baseRepo, headRepo, user, comment, err := e.Parser.ParseBitbucketCloudPullCommentEvent(body)
@@ -339,6 +441,70 @@ func (e *VCSEventsController) HandleBitbucketCloudCommentEvent(w http.ResponseWr
 	e.respond(w, lvl, code, msg)
 }
 
+// HandleGiteaCommentEvent handles comment events from Gitea where Atlantis
+// commands can come from. It's exported to make testing easier.
+func (e *VCSEventsController) HandleGiteaCommentEvent(w http.ResponseWriter, body []byte, reqID string) {
+	var event struct {
+		Action string `json:"action"`
+		IsPull bool   `json:"is_pull"`
+		Issue  struct {
+			PullRequest *gitea.PullRequestMeta `json:"pull_request"`
+		} `json:"issue"`
+	}
+	if err := json.Unmarshal(body, &event); err == nil {
+		if event.Action != gitea.CommentCreatedAction {
+			e.respond(w, logging.Debug, http.StatusOK, "Ignoring comment event since action was not created %s=%s", gitea.DeliveryHeader, reqID)
+			return
+		}
+		// Gitea sends the same event for comments on issues and pull requests.
+		if !event.IsPull && event.Issue.PullRequest == nil {
+			e.respond(w, logging.Debug, http.StatusOK, "Ignoring comment event since it isn't on a pull request %s=%s", gitea.DeliveryHeader, reqID)
+			return
+		}
+	}
+	baseRepo, user, pullNum, commentID, comment, err := e.Parser.ParseGiteaIssueCommentEvent(body)
+	if err != nil {
+		e.respond(w, logging.Error, http.StatusBadRequest, "Error parsing comment data: %s %s=%s", err, gitea.DeliveryHeader, reqID)
+		return
+	}
+	// We pass in nil for maybeHeadRepo and maybePull because the pull request
+	// data isn't available in the Gitea comment event.
+	resp := e.handleCommentEvent(e.Logger, baseRepo, nil, nil, user, pullNum, comment, commentID, models.Gitea)
+
+	lvl := logging.Debug
+	code := http.StatusOK
+	msg := resp.body
+	if resp.err.code != 0 {
+		lvl = logging.Error
+		code = resp.err.code
+		msg = resp.err.err.Error()
+	}
+	e.respond(w, lvl, code, msg)
+}
+
+// HandleGiteaPullRequestEvent will autoplan or delete any locks associated
+// with the pull request depending on the event. It's exported to make testing
+// easier.
+func (e *VCSEventsController) HandleGiteaPullRequestEvent(w http.ResponseWriter, body []byte, reqID string) {
+	pull, pullEventType, baseRepo, headRepo, user, err := e.Parser.ParseGiteaPullEvent(body)
+	if err != nil {
+		e.respond(w, logging.Error, http.StatusBadRequest, "Error parsing pull data: %s %s=%s", err, gitea.DeliveryHeader, reqID)
+		return
+	}
+	e.Logger.Info("identified event as type %q", pullEventType.String())
+	resp := e.handlePullRequestEvent(e.Logger, baseRepo, headRepo, pull, user, pullEventType)
+
+	lvl := logging.Debug
+	code := http.StatusOK
+	msg := resp.body
+	if resp.err.code != 0 {
+		lvl = logging.Error
+		code = resp.err.code
+		msg = resp.err.err.Error()
+	}
+	e.respond(w, lvl, code, msg)
+}
+
 // HandleBitbucketServerCommentEvent handles comment events from Bitbucket.
 func (e *VCSEventsController) HandleBitbucketServerCommentEvent(w http.ResponseWriter, body []byte, reqID string) {
 	pull, baseRepo, headRepo, user, comment, err := e.Parser.ParseBitbucketServerPullCommentEvent(body)
@@ -453,7 +619,11 @@ func (e *VCSEventsController) handlePullRequestEvent(logger logging.SimpleLoggin
 		// Respond with success and then actually execute the command asynchronously.
 		// We use a goroutine so that this function returns and the connection is
 		// closed.
-		if !e.TestingMode {
+		if e.CommandQueue != nil && !e.TestingMode {
+			if err := e.CommandQueue.EnqueueAutoplanCommand(baseRepo, headRepo, pull, user); err != nil {
+				return queueErrResponse(err)
+			}
+		} else if !e.TestingMode {
 			go e.CommandRunner.RunAutoplanCommand(baseRepo, headRepo, pull, user)
 		} else {
 			// When testing we want to wait for everything to complete.
@@ -587,14 +757,25 @@ func (e *VCSEventsController) handleCommentEvent(logger logging.SimpleLogging, b
 	}
 
 	logger.Debug("executing command")
-	if !e.TestingMode {
+	return e.runCommentCommand(baseRepo, maybeHeadRepo, maybePull, user, pullNum, parseResult.Command)
+}
+
+// runCommentCommand queues or runs cmd.
+func (e *VCSEventsController) runCommentCommand(baseRepo models.Repo, maybeHeadRepo *models.Repo, maybePull *models.PullRequest, user models.User, pullNum int, cmd *events.CommentCommand) HTTPResponse {
+	if e.CommandQueue != nil && !e.TestingMode {
+		// Persist the command before responding so it isn't lost if Atlantis
+		// stops before running it.
+		if err := e.CommandQueue.EnqueueCommentCommand(baseRepo, maybeHeadRepo, maybePull, user, pullNum, cmd); err != nil {
+			return queueErrResponse(err)
+		}
+	} else if !e.TestingMode {
 		// Respond with success and then actually execute the command asynchronously.
 		// We use a goroutine so that this function returns and the connection is
 		// closed.
-		go e.CommandRunner.RunCommentCommand(baseRepo, maybeHeadRepo, maybePull, user, pullNum, parseResult.Command)
+		go e.CommandRunner.RunCommentCommand(baseRepo, maybeHeadRepo, maybePull, user, pullNum, cmd)
 	} else {
 		// When testing we want to wait for everything to complete.
-		e.CommandRunner.RunCommentCommand(baseRepo, maybeHeadRepo, maybePull, user, pullNum, parseResult.Command)
+		e.CommandRunner.RunCommentCommand(baseRepo, maybeHeadRepo, maybePull, user, pullNum, cmd)
 	}
 
 	return HTTPResponse{
@@ -731,6 +912,20 @@ func (e *VCSEventsController) HandleAzureDevopsPullRequestEvent(w http.ResponseW
 	e.respond(w, lvl, code, msg)
 }
 
+// queueErrResponse is the response when a command couldn't be persisted.
+// It's an error so the VCS host shows that the event wasn't processed.
+func queueErrResponse(err error) HTTPResponse {
+	wrapped := errors.Wrap(err, "queueing command")
+	return HTTPResponse{
+		body: wrapped.Error(),
+		err: HTTPError{
+			code:       http.StatusInternalServerError,
+			err:        wrapped,
+			isSilenced: false,
+		},
+	}
+}
+
 // supportsHost returns true if h is in e.SupportedVCSHosts and false otherwise.
 func (e *VCSEventsController) supportsHost(h models.VCSHostType) bool {
 	for _, supported := range e.SupportedVCSHosts {
@@ -1203,6 +1203,7 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 		locker,
 		globalCfg,
 		&events.DefaultPendingPlanFinder{},
+		false,
 		commentParser,
 		false,
 		false,
@@ -1219,7 +1220,7 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 
 	Ok(t, err)
 
-	conftextExec := policy.NewConfTestExecutorWorkflow(logger, binDir, &NoopTFDownloader{})
+	conftextExec := policy.NewConfTestExecutorWorkflow(logger, binDir, &NoopTFDownloader{}, policy.NewSourceResolverProxy(nil))
 
 	// swapping out version cache to something that always returns local contest
 	// binary
@@ -1245,6 +1246,7 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 			defaultTFVersion,
 			statusUpdater,
 			asyncTfExec,
+			nil,
 		),
 		ShowStepRunner:        showStepRunner,
 		PolicyCheckStepRunner: policyCheckRunner,
@@ -1263,6 +1265,10 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 		WorkingDirLocker: locker,
 		CommandRequirementHandler: &events.DefaultCommandRequirementHandler{
 			WorkingDir: workingDir,
+			VCSClient:  e2eVCSClient,
+		},
+		PlanStalenessChecker: &events.DefaultPlanStalenessChecker{
+			WorkingDir: workingDir,
 		},
 	}
 
@@ -1329,6 +1335,8 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 		silenceNoProjects,
 		false,
 		e2ePullReqStatusFetcher,
+		&events.DefaultPlanStalenessChecker{WorkingDir: workingDir},
+		locker,
 	)
 
 	approvePoliciesCommandRunner := events.NewApprovePoliciesCommandRunner(
@@ -1346,6 +1354,7 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 		mocks.NewMockDeleteLockCommand(),
 		e2eVCSClient,
 		silenceNoProjects,
+		nil,
 	)
 
 	versionCommandRunner := events.NewVersionCommandRunner(
@@ -1438,6 +1447,14 @@ func (w *mockWebhookSender) Send(log logging.SimpleLogging, result webhooks.Appl
 	return nil
 }
 
+func (w *mockWebhookSender) SendDrift(log logging.SimpleLogging, result webhooks.DriftResult) error {
+	return nil
+}
+
+func (w *mockWebhookSender) SendEvent(log logging.SimpleLogging, result webhooks.EventResult) error {
+	return nil
+}
+
 func GitHubCommentEvent(t *testing.T, comment string) *http.Request {
 	requestJSON, err := os.ReadFile(filepath.Join("testdata", "githubIssueCommentEvent.json"))
 	Ok(t, err)
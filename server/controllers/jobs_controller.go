@@ -9,8 +9,10 @@ import (
 	"github.com/runatlantis/atlantis/server/controllers/templates"
 	"github.com/runatlantis/atlantis/server/controllers/websocket"
 	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/metrics"
+	"github.com/runatlantis/atlantis/server/webauth"
 	tally "github.com/uber-go/tally/v4"
 )
 
@@ -35,6 +37,9 @@ type JobsController struct {
 	WsMux                    *websocket.Multiplexor
 	KeyGenerator             JobIDKeyGenerator
 	StatsScope               tally.Scope
+	// JobCanceller cancels jobs from the job page. If nil, jobs can't be
+	// cancelled.
+	JobCanceller *jobs.JobCanceller
 }
 
 func (j *JobsController) getProjectJobs(w http.ResponseWriter, r *http.Request) error {
@@ -87,6 +92,30 @@ func (j *JobsController) GetProjectJobsWS(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// CancelProjectJob cancels the running job with the job-id in the route.
+// It responds with 404 if the job isn't running.
+func (j *JobsController) CancelProjectJob(w http.ResponseWriter, r *http.Request) {
+	jobID, err := j.KeyGenerator.Generate(r)
+	if err != nil {
+		j.respond(w, logging.Error, http.StatusBadRequest, err.Error())
+		return
+	}
+	if j.JobCanceller == nil {
+		j.respond(w, logging.Warn, http.StatusNotFound, "Job %s isn't running", jobID)
+		return
+	}
+
+	user := webauth.Username(r)
+	if user == "" {
+		user = "the Atlantis UI"
+	}
+	if !j.JobCanceller.CancelJob(jobID, user) {
+		j.respond(w, logging.Warn, http.StatusNotFound, "Job %s isn't running", jobID)
+		return
+	}
+	j.respond(w, logging.Info, http.StatusOK, "Cancelled job %s", jobID)
+}
+
 func (j *JobsController) respond(w http.ResponseWriter, lvl logging.LogLevel, responseCode int, format string, args ...interface{}) {
 	response := fmt.Sprintf(format, args...)
 	j.Logger.Log(lvl, response)
@@ -0,0 +1,45 @@
+package controllers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/runatlantis/atlantis/server/controllers"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/jobs"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestCancelProjectJob(t *testing.T) {
+	t.Run("job not running", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "", bytes.NewBuffer(nil))
+		req = mux.SetURLVars(req, map[string]string{"job-id": "1234"})
+		w := httptest.NewRecorder()
+		jc := controllers.JobsController{
+			Logger:       logging.NewNoopLogger(t),
+			JobCanceller: jobs.NewJobCanceller(),
+		}
+		jc.CancelProjectJob(w, req)
+		ResponseContains(t, w, http.StatusNotFound, "Job 1234 isn't running")
+	})
+
+	t.Run("job cancelled", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "", bytes.NewBuffer(nil))
+		req = mux.SetURLVars(req, map[string]string{"job-id": "1234"})
+		req.SetBasicAuth("alice", "password")
+		w := httptest.NewRecorder()
+		canceller := jobs.NewJobCanceller()
+		defer canceller.StartJob(command.ProjectContext{JobID: "1234"})()
+		jc := controllers.JobsController{
+			Logger:       logging.NewNoopLogger(t),
+			JobCanceller: canceller,
+		}
+		jc.CancelProjectJob(w, req)
+		ResponseContains(t, w, http.StatusOK, "Cancelled job 1234")
+		Equals(t, "alice", canceller.CancelledBy("1234"))
+	})
+}
@@ -4,15 +4,18 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/runatlantis/atlantis/server/controllers/templates"
 
 	"github.com/gorilla/mux"
+	"github.com/runatlantis/atlantis/server/audit"
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/webauth"
 )
 
 // LocksController handles all requests relating to Atlantis locks.
@@ -28,6 +31,11 @@ type LocksController struct {
 	WorkingDirLocker   events.WorkingDirLocker
 	Backend            locking.Backend
 	DeleteLockCommand  events.DeleteLockCommand
+	// AuditSink records the locks deleted via the UI. If nil, deletions aren't
+	// audited.
+	AuditSink audit.Sink
+	// PullStatusNotifier, if set, is told when a pull's status is updated.
+	PullStatusNotifier events.PullStatusNotifier
 }
 
 // LockApply handles creating a global apply lock.
@@ -111,6 +119,7 @@ func (l *LocksController) DeleteLock(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	start := time.Now()
 	lock, err := l.DeleteLockCommand.DeleteLock(idUnencoded)
 	if err != nil {
 		l.respond(w, logging.Error, http.StatusInternalServerError, "deleting lock failed with: %s", err)
@@ -138,6 +147,8 @@ func (l *LocksController) DeleteLock(w http.ResponseWriter, r *http.Request) {
 		}
 		if err := l.Backend.UpdateProjectStatus(lock.Pull, lock.Workspace, lock.Project.Path, models.DiscardedPlanStatus); err != nil {
 			l.Logger.Err("unable to update project status: %s", err)
+		} else if l.PullStatusNotifier != nil {
+			l.PullStatusNotifier.PullStatusChanged(lock.Pull)
 		}
 
 		// Once the lock has been deleted, comment back on the pull request.
@@ -149,6 +160,8 @@ func (l *LocksController) DeleteLock(w http.ResponseWriter, r *http.Request) {
 	} else {
 		l.Logger.Debug("skipping commenting on pull request and deleting workspace because BaseRepo field is empty")
 	}
+	// The user is only known if the UI requires logging in.
+	audit.Record(l.Logger, l.AuditSink, audit.NewLockEvent(audit.UISource, webauth.Username(r), *lock, start))
 	l.respond(w, logging.Info, http.StatusOK, "Deleted lock id %q", id)
 }
 
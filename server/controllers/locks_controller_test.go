@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/runatlantis/atlantis/server/audit"
+	auditmocks "github.com/runatlantis/atlantis/server/audit/mocks"
 	"github.com/runatlantis/atlantis/server/controllers"
 	"github.com/runatlantis/atlantis/server/controllers/templates"
 	tMocks "github.com/runatlantis/atlantis/server/controllers/templates/mocks"
@@ -327,9 +329,10 @@ func TestDeleteLock_UpdateProjectStatus(t *testing.T) {
 	Assert(t, status.Projects != nil, "status projects was nil")
 	Equals(t, []models.ProjectStatus{
 		{
-			Workspace:  workspaceName,
-			RepoRelDir: projectPath,
-			Status:     models.DiscardedPlanStatus,
+			Workspace:   workspaceName,
+			RepoRelDir:  projectPath,
+			Status:      models.DiscardedPlanStatus,
+			PlanHistory: []models.PlanRecord{{}},
 		},
 	}, status.Projects)
 }
@@ -405,3 +408,35 @@ func TestDeleteLock_CommentSuccess(t *testing.T) {
 		"**Warning**: The plan for dir: `path` workspace: `workspace` was **discarded** via the Atlantis UI.\n\n"+
 			"To `apply` this plan you must run `plan` again.", "")
 }
+
+func TestDeleteLock_Audit(t *testing.T) {
+	t.Log("We should record who deleted the lock in the audit log")
+	RegisterMockTestingT(t)
+	dlc := mocks2.NewMockDeleteLockCommand()
+	sink := auditmocks.NewMockSink()
+	When(dlc.DeleteLock("id")).ThenReturn(&models.ProjectLock{
+		Pull:      models.PullRequest{Num: 1},
+		Workspace: "workspace",
+		Project:   models.NewProject("owner/repo", "path"),
+	}, nil)
+	lc := controllers.LocksController{
+		DeleteLockCommand: dlc,
+		Logger:            logging.NewNoopLogger(t),
+		AuditSink:         sink,
+	}
+	req, _ := http.NewRequest("GET", "", bytes.NewBuffer(nil))
+	req = mux.SetURLVars(req, map[string]string{"id": "id"})
+	req.SetBasicAuth("lkysow", "password")
+	w := httptest.NewRecorder()
+	lc.DeleteLock(w, req)
+	ResponseContains(t, w, http.StatusOK, "Deleted lock id \"id\"")
+
+	event := sink.VerifyWasCalledOnce().Record(Any[audit.Event]()).GetCapturedArguments()
+	Equals(t, audit.UISource, event.Source)
+	Equals(t, "lkysow", event.User)
+	Equals(t, "unlock", event.Command)
+	Equals(t, "owner/repo", event.Repo)
+	Equals(t, 1, event.PullNum)
+	Equals(t, "path", event.Directory)
+	Equals(t, "workspace", event.Workspace)
+}
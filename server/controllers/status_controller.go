@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/runatlantis/atlantis/server/core/terraform"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/logging"
 )
@@ -13,6 +14,7 @@ import (
 type StatusController struct {
 	Logger          logging.SimpleLogging
 	Drainer         *events.Drainer
+	RunScheduler    *terraform.RunScheduler
 	AtlantisVersion string
 }
 
@@ -20,16 +22,23 @@ type StatusResponse struct {
 	ShuttingDown    bool   `json:"shutting_down"`
 	InProgressOps   int    `json:"in_progress_operations"`
 	AtlantisVersion string `json:"version"`
+	// Runs are the Terraform runs in progress and waiting for a slot.
+	Runs *terraform.RunSchedulerStatus `json:"runs,omitempty"`
 }
 
 // Get is the GET /status route.
 func (d *StatusController) Get(w http.ResponseWriter, r *http.Request) {
 	status := d.Drainer.GetStatus()
-	data, err := json.MarshalIndent(&StatusResponse{
+	resp := &StatusResponse{
 		ShuttingDown:    status.ShuttingDown,
 		InProgressOps:   status.InProgressOps,
 		AtlantisVersion: d.AtlantisVersion,
-	}, "", "  ")
+	}
+	if d.RunScheduler != nil {
+		runs := d.RunScheduler.Status()
+		resp.Runs = &runs
+	}
+	data, err := json.MarshalIndent(resp, "", "  ")
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "Error creating status json response: %s", err)
@@ -9,7 +9,9 @@ import (
 	"testing"
 
 	"github.com/runatlantis/atlantis/server/controllers"
+	"github.com/runatlantis/atlantis/server/core/terraform"
 	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/logging"
 	. "github.com/runatlantis/atlantis/testing"
 )
@@ -83,3 +85,33 @@ func TestStatusController_Shutdown(t *testing.T) {
 	Equals(t, true, result.ShuttingDown)
 	Equals(t, 0, result.InProgressOps)
 }
+
+func TestStatusController_Runs(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	r, _ := http.NewRequest("GET", "/status", bytes.NewBuffer(nil))
+	w := httptest.NewRecorder()
+	scheduler := terraform.NewRunScheduler(4, 2, nil)
+	release := scheduler.Acquire(command.ProjectContext{})
+	defer release()
+
+	d := &controllers.StatusController{
+		Logger:          logger,
+		Drainer:         &events.Drainer{},
+		RunScheduler:    scheduler,
+		AtlantisVersion: "1.0.0",
+	}
+	d.Get(w, r)
+
+	var result controllers.StatusResponse
+	body, err := io.ReadAll(w.Result().Body)
+	Ok(t, err)
+	Equals(t, 200, w.Result().StatusCode)
+	err = json.Unmarshal(body, &result)
+	Ok(t, err)
+	Equals(t, &terraform.RunSchedulerStatus{
+		MaxRuns:        4,
+		MaxRunsPerRepo: 2,
+		Running:        1,
+		Queued:         []terraform.QueuedRun{},
+	}, result.Runs)
+}
@@ -86,6 +86,7 @@ var IndexTemplate = template.Must(template.New("index.html.tmpl").Parse(`
   <section class="header">
     <a title="atlantis" href="{{ .CleanedBasePath }}/"><img class="hero" src="{{ .CleanedBasePath }}/static/images/atlantis-icon_512.png"/></a>
     <p class="title-heading">atlantis</p>
+    <p class="title-heading small"><strong>Locks</strong> | <a href="{{ .CleanedBasePath }}/dashboard">Pull Requests</a></p>
     <p class="js-discard-success"><strong>Plan discarded and unlocked!</strong></p>
   </section>
   <section>
@@ -252,6 +253,214 @@ var IndexTemplate = template.Must(template.New("index.html.tmpl").Parse(`
 </html>
 `))
 
+// DashboardData holds the data for rendering the dashboard page.
+type DashboardData struct {
+	Repos []DashboardRepoData
+	// Filters are the filters the pull requests were filtered by.
+	Filters DashboardFilters
+	// Statuses are the project statuses that can be filtered by.
+	Statuses        []DashboardStatusOption
+	AtlantisVersion string
+	// CleanedBasePath is the path Atlantis is accessible at externally. If
+	// not using a path-based proxy, this will be an empty string. Never ends
+	// in a '/' (hence "cleaned").
+	CleanedBasePath string
+}
+
+// DashboardFilters are the filters of the dashboard. Empty filters match
+// everything.
+type DashboardFilters struct {
+	// Repo matches repos whose full name contains it.
+	Repo string
+	// Status matches projects with this status, ex. planned.
+	Status string
+	// User matches pull requests by this author or planned by this user.
+	User string
+}
+
+// DashboardStatusOption is a project status that can be filtered by.
+type DashboardStatusOption struct {
+	Value    string
+	Label    string
+	Selected bool
+}
+
+// DashboardRepoData holds the pull requests of a repo on the dashboard.
+type DashboardRepoData struct {
+	RepoFullName string
+	Pulls        []DashboardPullData
+}
+
+// DashboardPullData holds the fields needed to display a pull request on the
+// dashboard.
+type DashboardPullData struct {
+	Num        int
+	URL        string
+	Author     string
+	HeadBranch string
+	BaseBranch string
+	Projects   []DashboardProjectData
+}
+
+// DashboardProjectData holds the fields needed to display a project on the
+// dashboard.
+type DashboardProjectData struct {
+	// Name is the project name or, if it doesn't have one, its dir.
+	Name      string
+	Workspace string
+	Status    string
+	// JobID identifies the job of the last command run for the project.
+	JobID string
+	// LastPlan is nil if the project hasn't been planned successfully.
+	LastPlan *DashboardPlanData
+	// PlanHistory are the plans before LastPlan, newest first.
+	PlanHistory []DashboardPlanData
+}
+
+// DashboardPlanData holds the fields needed to display a plan on the
+// dashboard.
+type DashboardPlanData struct {
+	User                 string
+	JobID                string
+	PlannedAtFormatted   string
+	Add, Change, Destroy int
+	Changes              bool
+}
+
+var DashboardTemplate = template.Must(template.New("dashboard.html.tmpl").Parse(`
+<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>atlantis</title>
+  <meta name="description" content="">
+  <meta name="author" content="">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <script src="{{ .CleanedBasePath }}/static/js/jquery-3.5.1.min.js"></script>
+  <link rel="stylesheet" href="{{ .CleanedBasePath }}/static/css/normalize.css">
+  <link rel="stylesheet" href="{{ .CleanedBasePath }}/static/css/skeleton.css">
+  <link rel="stylesheet" href="{{ .CleanedBasePath }}/static/css/custom.css">
+  <link rel="icon" type="image/png" href="{{ .CleanedBasePath }}/static/images/atlantis-icon.png">
+</head>
+<body>
+<div class="container">
+  <section class="header">
+    <a title="atlantis" href="{{ .CleanedBasePath }}/"><img class="hero" src="{{ .CleanedBasePath }}/static/images/atlantis-icon_512.png"/></a>
+    <p class="title-heading">atlantis</p>
+    <p class="title-heading small"><a href="{{ .CleanedBasePath }}/">Locks</a> | <strong>Pull Requests</strong></p>
+  </section>
+  <section>
+    <form class="dashboard-filters" method="GET" action="{{ .CleanedBasePath }}/dashboard">
+      <input type="text" name="repo" placeholder="Repository" value="{{ .Filters.Repo }}">
+      <select name="status">
+        <option value="">Any status</option>
+        {{ range .Statuses }}
+        <option value="{{ .Value }}"{{ if .Selected }} selected{{ end }}>{{ .Label }}</option>
+        {{ end }}
+      </select>
+      <input type="text" name="user" placeholder="User" value="{{ .Filters.User }}">
+      <input class="button-primary" type="submit" value="Filter">
+      <a class="button" href="{{ .CleanedBasePath }}/dashboard">Clear</a>
+    </form>
+  </section>
+  <section id="pulls">
+    {{ $basePath := .CleanedBasePath }}
+    {{ range .Repos }}
+    <p class="title-heading small"><strong>{{ .RepoFullName }}</strong></p>
+    {{ range .Pulls }}
+    <div class="dashboard-pull">
+      <p>
+        <a href="{{ .URL }}" target="_blank"><strong>#{{ .Num }}</strong></a>
+        by <strong>{{ .Author }}</strong>
+        <code>{{ .HeadBranch }}</code> &rarr; <code>{{ .BaseBranch }}</code>
+      </p>
+      <div class="dashboard-grid">
+        <div class="lock-header">
+          <span>Project</span>
+          <span>Workspace</span>
+          <span>Status</span>
+          <span>Last Plan</span>
+          <span>Planned By</span>
+          <span>Job</span>
+        </div>
+        {{ range .Projects }}
+        <div class="dashboard-row">
+          <span>{{ .Name }}</span>
+          <span><code>{{ .Workspace }}</code></span>
+          <span><code class="status-{{ .Status }}">{{ .Status }}</code></span>
+          {{ if .LastPlan }}
+          <span>
+            {{ if .LastPlan.Changes }}<strong>+{{ .LastPlan.Add }} ~{{ .LastPlan.Change }} -{{ .LastPlan.Destroy }}</strong>{{ else }}No changes{{ end }}
+            at {{ .LastPlan.PlannedAtFormatted }}
+            {{ if .PlanHistory }}
+            <details>
+              <summary>{{ len .PlanHistory }} earlier plan(s)</summary>
+              {{ range .PlanHistory }}
+              <div>
+                {{ if .Changes }}+{{ .Add }} ~{{ .Change }} -{{ .Destroy }}{{ else }}No changes{{ end }}
+                at {{ .PlannedAtFormatted }} by {{ .User }}
+                {{ if .JobID }}(<a href="{{ $basePath }}/jobs/{{ .JobID }}">job</a>){{ end }}
+              </div>
+              {{ end }}
+            </details>
+            {{ end }}
+          </span>
+          <span>{{ .LastPlan.User }}</span>
+          {{ else }}
+          <span>-</span>
+          <span>-</span>
+          {{ end }}
+          <span>{{ if .JobID }}<a href="{{ $basePath }}/jobs/{{ .JobID }}">View</a>{{ else }}-{{ end }}</span>
+        </div>
+        {{ end }}
+      </div>
+    </div>
+    {{ end }}
+    {{ else }}
+    <p class="placeholder">No pull requests found.</p>
+    {{ end }}
+  </section>
+</div>
+<footer>
+{{ .AtlantisVersion }}
+</footer>
+<script>
+  // The server tells us when a pull request's status changes. We then
+  // re-render the pull requests with the current filters.
+  var refreshTimeout = null;
+  function refreshPulls() {
+    if (refreshTimeout !== null) {
+      return;
+    }
+    refreshTimeout = setTimeout(function() {
+      refreshTimeout = null;
+      $.get(document.location.href, function(html) {
+        var doc = new DOMParser().parseFromString(html, "text/html");
+        $("#pulls").replaceWith(doc.getElementById("pulls"));
+      });
+    }, 1000);
+  }
+  function watchPulls() {
+    var socket = new WebSocket(
+      (document.location.protocol === "http:" ? "ws://" : "wss://") +
+      document.location.host +
+      "{{ .CleanedBasePath }}/dashboard/ws");
+    socket.onmessage = refreshPulls;
+    socket.onclose = function(event) {
+      // Reconnect, ex. after Atlantis was restarted, and catch up on what we
+      // missed.
+      setTimeout(function() {
+        refreshPulls();
+        watchPulls();
+      }, 5000);
+    };
+  }
+  watchPulls();
+</script>
+</body>
+</html>
+`))
+
 // LockDetailData holds the fields needed to display the lock detail view.
 type LockDetailData struct {
 	LockKeyEncoded  string
@@ -414,7 +623,9 @@ var ProjectJobsTemplate = template.Must(template.New("blank.html.tmpl").Parse(`
     <p class="title-heading"><strong></strong></p>
     </section>
     <div class="spacer"></div>
-    <br>
+    <section>
+      <button id="cancelJob" class="button">Cancel Job</button>
+    </section>
     <section>
       <div id="terminal"></div>
     </section>
@@ -450,6 +661,22 @@ var ProjectJobsTemplate = template.Must(template.New("blank.html.tmpl").Parse(`
       window.addEventListener("unload", function(event) {
         websocket.close();
       })
+      $("#cancelJob").click(function() {
+        if (!confirm("Cancel this job? Its running Terraform and custom steps will be stopped.")) {
+          return;
+        }
+        $.ajax({
+          url: document.location.pathname + "/cancel",
+          type: "POST",
+          success: function(result) {
+            updateTerminalStatus("Cancelling...");
+            $("#cancelJob").prop("disabled", true);
+          },
+          error: function(xhr) {
+            alert(xhr.status === 404 ? "This job isn't running." : "Unable to cancel this job: " + xhr.responseText);
+          }
+        });
+      });
       var attachAddon = new AttachAddon.AttachAddon(socket);
       var fitAddon = new FitAddon.FitAddon();
       var searchAddon = new SearchAddon.SearchAddon();
@@ -32,6 +32,50 @@ func TestIndexTemplate(t *testing.T) {
 	Ok(t, err)
 }
 
+func TestDashboardTemplate(t *testing.T) {
+	plan := DashboardPlanData{
+		User:               "user",
+		JobID:              "job",
+		PlannedAtFormatted: "02-01-2006 15:04:05",
+		Add:                1,
+		Changes:            true,
+	}
+	err := DashboardTemplate.Execute(io.Discard, DashboardData{
+		Repos: []DashboardRepoData{
+			{
+				RepoFullName: "repo full name",
+				Pulls: []DashboardPullData{
+					{
+						Num:    1,
+						URL:    "url",
+						Author: "author",
+						Projects: []DashboardProjectData{
+							{
+								Name:        "project",
+								Workspace:   "workspace",
+								Status:      "planned",
+								JobID:       "job",
+								LastPlan:    &plan,
+								PlanHistory: []DashboardPlanData{plan},
+							},
+							{
+								Name:      "unplanned",
+								Workspace: "workspace",
+								Status:    "plan_errored",
+							},
+						},
+					},
+				},
+			},
+		},
+		Filters:         DashboardFilters{Status: "planned"},
+		Statuses:        []DashboardStatusOption{{Value: "planned", Label: "Planned", Selected: true}},
+		AtlantisVersion: "v0.0.0",
+		CleanedBasePath: "/path",
+	})
+	Ok(t, err)
+}
+
 func TestLockTemplate(t *testing.T) {
 	err := LockTemplate.Execute(io.Discard, LockDetailData{
 		LockKeyEncoded:  "lock key encoded",
@@ -90,6 +90,9 @@ func (p *ParserValidator) ParseRepoCfgData(repoCfgData []byte, globalCfg valid.G
 	if err := p.validateProjectNames(validConfig); err != nil {
 		return valid.RepoCfg{}, err
 	}
+	if err := p.validateProjectDependencies(validConfig); err != nil {
+		return valid.RepoCfg{}, err
+	}
 	if validConfig.Version == 2 {
 		// The only difference between v2 and v3 is how we parse custom run
 		// commands.
@@ -185,6 +188,76 @@ func (p *ParserValidator) validateProjectNames(config valid.RepoCfg) error {
 	return nil
 }
 
+// validateProjectDependencies validates that projects only depend on named
+// projects that exist and that the dependencies don't contain a cycle.
+func (p *ParserValidator) validateProjectDependencies(config valid.RepoCfg) error {
+	dependsOn := make(map[string][]string)
+	for _, project := range config.Projects {
+		if project.Name != nil {
+			dependsOn[*project.Name] = project.DependsOn
+		}
+	}
+	for _, project := range config.Projects {
+		for _, dep := range project.DependsOn {
+			if _, ok := dependsOn[dep]; !ok {
+				return fmt.Errorf("project %q depends on %q but there is no project with that name", projectDisplayName(project), dep)
+			}
+		}
+	}
+
+	// Depth first search from every project, keeping track of the path so
+	// we can show the cycle.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			start := 0
+			for i, n := range path {
+				if n == name {
+					start = i
+				}
+			}
+			return fmt.Errorf("project dependencies contain a cycle: %s", strings.Join(append(path[start:], name), " -> "))
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range dependsOn[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, project := range config.Projects {
+		if project.Name == nil {
+			continue
+		}
+		if err := visit(*project.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// projectDisplayName returns the project's name or its dir and workspace if
+// it doesn't have one.
+func projectDisplayName(project valid.Project) string {
+	if project.Name != nil {
+		return *project.Name
+	}
+	return fmt.Sprintf("%s/%s", project.Dir, project.Workspace)
+}
+
 // applyLegacyShellParsing changes any custom run commands in cfg to use the old
 // parsing method with shlex.Split().
 func (p *ParserValidator) applyLegacyShellParsing(cfg *valid.RepoCfg) error {
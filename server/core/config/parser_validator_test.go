@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/core/config"
@@ -733,6 +734,85 @@ projects:
 				Workflows: map[string]valid.Workflow{},
 			},
 		},
+		{
+			description: "projects with dependencies",
+			input: `
+version: 3
+projects:
+- name: staging
+  dir: staging
+- name: production
+  dir: production
+  depends_on: [staging]`,
+			exp: valid.RepoCfg{
+				Version: 3,
+				Projects: []valid.Project{
+					{
+						Name:      String("staging"),
+						Dir:       "staging",
+						Workspace: "default",
+						Autoplan: valid.Autoplan{
+							WhenModified: []string{"**/*.tf*", "**/terragrunt.hcl"},
+							Enabled:      true,
+						},
+					},
+					{
+						Name:      String("production"),
+						Dir:       "production",
+						Workspace: "default",
+						Autoplan: valid.Autoplan{
+							WhenModified: []string{"**/*.tf*", "**/terragrunt.hcl"},
+							Enabled:      true,
+						},
+						DependsOn: []string{"staging"},
+					},
+				},
+				Workflows: map[string]valid.Workflow{},
+			},
+		},
+		{
+			description: "depending on a project that doesn't exist",
+			input: `
+version: 3
+projects:
+- dir: production
+  depends_on: [staging]`,
+			expErr: "project \"production/default\" depends on \"staging\" but there is no project with that name",
+		},
+		{
+			description: "depending on an empty project name",
+			input: `
+version: 3
+projects:
+- dir: production
+  depends_on: [""]`,
+			expErr: "projects: (0: (depends_on: project names cannot be empty.).).",
+		},
+		{
+			description: "projects with a dependency cycle",
+			input: `
+version: 3
+projects:
+- name: dev
+  dir: dev
+- name: staging
+  dir: staging
+  depends_on: [dev, production]
+- name: production
+  dir: production
+  depends_on: [staging]`,
+			expErr: "project dependencies contain a cycle: staging -> production -> staging",
+		},
+		{
+			description: "project depending on itself",
+			input: `
+version: 3
+projects:
+- name: staging
+  dir: staging
+  depends_on: [staging]`,
+			expErr: "project dependencies contain a cycle: staging -> staging",
+		},
 		{
 			description: "if steps are set then we parse them properly",
 			input: `
@@ -1320,7 +1400,7 @@ func TestParseGlobalCfg(t *testing.T) {
 			input: `repos:
 - id: /.*/
   apply_requirements: [invalid]`,
-			expErr: "repos: (0: (apply_requirements: \"invalid\" is not a valid apply_requirement, only \"approved\", \"mergeable\" and \"undiverged\" are supported.).).",
+			expErr: "repos: (0: (apply_requirements: \"invalid\" is not a valid apply_requirement, only \"approved\", \"mergeable\", \"undiverged\", \"destroy_approved\", \"approval_policy\" and \"cost_under:<amount>\" are supported.).).",
 		},
 		"invalid import_requirement": {
 			input: `repos:
@@ -1328,6 +1408,48 @@ func TestParseGlobalCfg(t *testing.T) {
   import_requirements: [invalid]`,
 			expErr: "repos: (0: (import_requirements: \"invalid\" is not a valid import_requirement, only \"approved\", \"mergeable\" and \"undiverged\" are supported.).).",
 		},
+		"drift_detection with regex id": {
+			input: `repos:
+- id: /.*/
+  drift_detection:
+    enabled: true`,
+			expErr: "repos: (0: (drift_detection: only supported for repos with an exact match id.).).",
+		},
+		"invalid drift_detection interval": {
+			input: `repos:
+- id: github.com/owner/repo
+  drift_detection:
+    enabled: true
+    interval: daily`,
+			expErr: "repos: (0: (drift_detection: (interval: time: invalid duration \"daily\".).).).",
+		},
+		"drift_detection interval too short": {
+			input: `repos:
+- id: github.com/owner/repo
+  drift_detection:
+    enabled: true
+    interval: 30s`,
+			expErr: "repos: (0: (drift_detection: (interval: must be at least 1m.).).).",
+		},
+		"invalid plan_ttl": {
+			input: `repos:
+- id: github.com/owner/repo
+  plan_ttl: 1d`,
+			expErr: "repos: (0: (plan_ttl: time: unknown unit \"d\" in duration \"1d\".).).",
+		},
+		"negative plan_ttl": {
+			input: `repos:
+- id: github.com/owner/repo
+  plan_ttl: -1h`,
+			expErr: "repos: (0: (plan_ttl: must not be negative.).).",
+		},
+		"negative approval_policy count": {
+			input: `repos:
+- id: github.com/owner/repo
+  approval_policy:
+    count: -1`,
+			expErr: "repos: (0: (approval_policy: (count: must not be negative.).).).",
+		},
 		"no workflows key": {
 			input: `repos: []`,
 			exp:   defaultCfg,
@@ -1515,6 +1637,115 @@ repos:
 				},
 			},
 		},
+		"drift_detection": {
+			input: `
+repos:
+- id: github.com/owner/repo
+  drift_detection:
+    enabled: true
+- id: github.com/owner/repo2
+  drift_detection:
+    enabled: true
+    branch: develop
+    interval: 6h
+- id: github.com/owner/repo3
+  drift_detection:
+    enabled: false
+    interval: 6h
+`,
+			exp: valid.GlobalCfg{
+				Repos: []valid.Repo{
+					defaultCfg.Repos[0],
+					{
+						ID: "github.com/owner/repo",
+						DriftDetection: &valid.DriftDetection{
+							Interval: 24 * time.Hour,
+						},
+					},
+					{
+						ID: "github.com/owner/repo2",
+						DriftDetection: &valid.DriftDetection{
+							Branch:   "develop",
+							Interval: 6 * time.Hour,
+						},
+					},
+					{
+						ID: "github.com/owner/repo3",
+					},
+				},
+				Workflows: map[string]valid.Workflow{
+					"default": defaultCfg.Workflows["default"],
+				},
+			},
+		},
+		"plan_ttl": {
+			input: `
+repos:
+- id: github.com/owner/repo
+  plan_ttl: 12h
+- id: github.com/owner/repo2
+  plan_ttl: ""
+`,
+			exp: valid.GlobalCfg{
+				Repos: []valid.Repo{
+					defaultCfg.Repos[0],
+					{
+						ID:      "github.com/owner/repo",
+						PlanTTL: Duration(12 * time.Hour),
+					},
+					{
+						ID:      "github.com/owner/repo2",
+						PlanTTL: Duration(0),
+					},
+				},
+				Workflows: map[string]valid.Workflow{
+					"default": defaultCfg.Workflows["default"],
+				},
+			},
+		},
+		"approval_policy": {
+			input: `
+repos:
+- id: github.com/owner/repo
+  apply_requirements: [approval_policy]
+  approval_policy:
+    count: 2
+    owners:
+      users: [alice]
+      teams: [platform]
+    codeowners: true
+- id: github.com/owner/repo2
+  approval_policy:
+    codeowners: true
+`,
+			exp: valid.GlobalCfg{
+				Repos: []valid.Repo{
+					defaultCfg.Repos[0],
+					{
+						ID:                "github.com/owner/repo",
+						ApplyRequirements: []string{"approval_policy"},
+						ApprovalPolicy: &valid.ApprovalPolicy{
+							Count: 2,
+							Owners: valid.PolicyOwners{
+								Users: []string{"alice"},
+								Teams: []string{"platform"},
+							},
+							CodeOwners: true,
+						},
+					},
+					{
+						ID: "github.com/owner/repo2",
+						ApprovalPolicy: &valid.ApprovalPolicy{
+							Count:      1,
+							CodeOwners: true,
+						},
+					},
+				},
+				Workflows: map[string]valid.Workflow{
+					"default": defaultCfg.Workflows["default"],
+				},
+			},
+		},
 		"redefine default workflow": {
 			input: `
 workflows:
@@ -1921,6 +2152,10 @@ func String(v string) *string { return &v }
 // to store v and returns a pointer to it.
 func Bool(v bool) *bool { return &v }
 
+// Duration is a helper routine that allocates a new time.Duration value
+// to store v and returns a pointer to it.
+func Duration(v time.Duration) *time.Duration { return &v }
+
 func defaultWorkflow(name string) valid.Workflow {
 	return valid.Workflow{
 		Name:        name,
@@ -0,0 +1,46 @@
+package raw
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+// DefaultApprovalPolicyCount is how many approvals the approval_policy apply
+// requirement needs if no count is configured.
+const DefaultApprovalPolicyCount = 1
+
+// ApprovalPolicy is the raw schema for the approval_policy section of a repo
+// in the server-side repo config.
+type ApprovalPolicy struct {
+	Count      *int         `yaml:"count,omitempty" json:"count,omitempty"`
+	Owners     PolicyOwners `yaml:"owners,omitempty" json:"owners,omitempty"`
+	CodeOwners bool         `yaml:"codeowners,omitempty" json:"codeowners,omitempty"`
+}
+
+func (a ApprovalPolicy) Validate() error {
+	countValid := func(value interface{}) error {
+		count := value.(*int)
+		if count != nil && *count < 0 {
+			return errors.New("must not be negative")
+		}
+		return nil
+	}
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.Count, validation.By(countValid)),
+	)
+}
+
+// ToValid returns the valid representation of a.
+func (a ApprovalPolicy) ToValid() *valid.ApprovalPolicy {
+	count := DefaultApprovalPolicyCount
+	if a.Count != nil {
+		count = *a.Count
+	}
+	return &valid.ApprovalPolicy{
+		Count:      count,
+		Owners:     a.Owners.ToValid(),
+		CodeOwners: a.CodeOwners,
+	}
+}
@@ -0,0 +1,43 @@
+package raw
+
+import (
+	"fmt"
+	"path"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+// DestroyProtection is the raw schema for the destroy_protection section of
+// the server-side repo config.
+type DestroyProtection struct {
+	Resources []string     `yaml:"resources,omitempty" json:"resources,omitempty"`
+	Owners    PolicyOwners `yaml:"owners,omitempty" json:"owners,omitempty"`
+}
+
+func (d DestroyProtection) Validate() error {
+	resourcesValid := func(value interface{}) error {
+		for _, pattern := range value.([]string) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%q is not a valid resource address pattern: %s", pattern, err)
+			}
+		}
+		return nil
+	}
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Resources, validation.By(resourcesValid)),
+	)
+}
+
+// ToValid returns the valid representation of d. If no owners are configured
+// the policy owners are used.
+func (d DestroyProtection) ToValid(policyOwners valid.PolicyOwners) valid.DestroyProtection {
+	owners := d.Owners.ToValid()
+	if len(owners.Users) == 0 && len(owners.Teams) == 0 {
+		owners = policyOwners
+	}
+	return valid.DestroyProtection{
+		Resources: d.Resources,
+		Owners:    owners,
+	}
+}
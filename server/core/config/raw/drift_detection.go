@@ -0,0 +1,58 @@
+package raw
+
+import (
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+// DefaultDriftDetectionInterval is how often drift detection runs if no
+// interval is configured.
+const DefaultDriftDetectionInterval = 24 * time.Hour
+
+// DriftDetection is the raw schema for the drift_detection section of a repo
+// in the server-side repo config.
+type DriftDetection struct {
+	Enabled  bool   `yaml:"enabled" json:"enabled"`
+	Branch   string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	Interval string `yaml:"interval,omitempty" json:"interval,omitempty"`
+}
+
+func (d DriftDetection) Validate() error {
+	intervalValid := func(value interface{}) error {
+		interval := value.(string)
+		if interval == "" {
+			return nil
+		}
+		duration, err := time.ParseDuration(interval)
+		if err != nil {
+			return err
+		}
+		if duration < time.Minute {
+			return errors.New("must be at least 1m")
+		}
+		return nil
+	}
+	return validation.ValidateStruct(&d,
+		validation.Field(&d.Interval, validation.By(intervalValid)),
+	)
+}
+
+// ToValid returns the valid representation of d or nil if drift detection
+// isn't enabled.
+func (d DriftDetection) ToValid() *valid.DriftDetection {
+	if !d.Enabled {
+		return nil
+	}
+	interval := DefaultDriftDetectionInterval
+	if d.Interval != "" {
+		// Safe to ignore the error because we test it in Validate().
+		interval, _ = time.ParseDuration(d.Interval)
+	}
+	return &valid.DriftDetection{
+		Branch:   d.Branch,
+		Interval: interval,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/pkg/errors"
@@ -12,28 +13,33 @@ import (
 
 // GlobalCfg is the raw schema for server-side repo config.
 type GlobalCfg struct {
-	Repos      []Repo              `yaml:"repos" json:"repos"`
-	Workflows  map[string]Workflow `yaml:"workflows" json:"workflows"`
-	PolicySets PolicySets          `yaml:"policies" json:"policies"`
-	Metrics    Metrics             `yaml:"metrics" json:"metrics"`
+	Repos             []Repo              `yaml:"repos" json:"repos"`
+	Workflows         map[string]Workflow `yaml:"workflows" json:"workflows"`
+	PolicySets        PolicySets          `yaml:"policies" json:"policies"`
+	Metrics           Metrics             `yaml:"metrics" json:"metrics"`
+	DestroyProtection DestroyProtection   `yaml:"destroy_protection" json:"destroy_protection"`
+	Permissions       []Permission        `yaml:"permissions" json:"permissions"`
 }
 
 // Repo is the raw schema for repos in the server-side repo config.
 type Repo struct {
-	ID                        string         `yaml:"id" json:"id"`
-	Branch                    string         `yaml:"branch" json:"branch"`
-	RepoConfigFile            string         `yaml:"repo_config_file" json:"repo_config_file"`
-	PlanRequirements          []string       `yaml:"plan_requirements" json:"plan_requirements"`
-	ApplyRequirements         []string       `yaml:"apply_requirements" json:"apply_requirements"`
-	ImportRequirements        []string       `yaml:"import_requirements" json:"import_requirements"`
-	PreWorkflowHooks          []WorkflowHook `yaml:"pre_workflow_hooks" json:"pre_workflow_hooks"`
-	Workflow                  *string        `yaml:"workflow,omitempty" json:"workflow,omitempty"`
-	PostWorkflowHooks         []WorkflowHook `yaml:"post_workflow_hooks" json:"post_workflow_hooks"`
-	AllowedWorkflows          []string       `yaml:"allowed_workflows,omitempty" json:"allowed_workflows,omitempty"`
-	AllowedOverrides          []string       `yaml:"allowed_overrides" json:"allowed_overrides"`
-	AllowCustomWorkflows      *bool          `yaml:"allow_custom_workflows,omitempty" json:"allow_custom_workflows,omitempty"`
-	DeleteSourceBranchOnMerge *bool          `yaml:"delete_source_branch_on_merge,omitempty" json:"delete_source_branch_on_merge,omitempty"`
-	RepoLocking               *bool          `yaml:"repo_locking,omitempty" json:"repo_locking,omitempty"`
+	ID                        string          `yaml:"id" json:"id"`
+	Branch                    string          `yaml:"branch" json:"branch"`
+	RepoConfigFile            string          `yaml:"repo_config_file" json:"repo_config_file"`
+	PlanRequirements          []string        `yaml:"plan_requirements" json:"plan_requirements"`
+	ApplyRequirements         []string        `yaml:"apply_requirements" json:"apply_requirements"`
+	ImportRequirements        []string        `yaml:"import_requirements" json:"import_requirements"`
+	PreWorkflowHooks          []WorkflowHook  `yaml:"pre_workflow_hooks" json:"pre_workflow_hooks"`
+	Workflow                  *string         `yaml:"workflow,omitempty" json:"workflow,omitempty"`
+	PostWorkflowHooks         []WorkflowHook  `yaml:"post_workflow_hooks" json:"post_workflow_hooks"`
+	AllowedWorkflows          []string        `yaml:"allowed_workflows,omitempty" json:"allowed_workflows,omitempty"`
+	AllowedOverrides          []string        `yaml:"allowed_overrides" json:"allowed_overrides"`
+	AllowCustomWorkflows      *bool           `yaml:"allow_custom_workflows,omitempty" json:"allow_custom_workflows,omitempty"`
+	DeleteSourceBranchOnMerge *bool           `yaml:"delete_source_branch_on_merge,omitempty" json:"delete_source_branch_on_merge,omitempty"`
+	RepoLocking               *bool           `yaml:"repo_locking,omitempty" json:"repo_locking,omitempty"`
+	DriftDetection            *DriftDetection `yaml:"drift_detection,omitempty" json:"drift_detection,omitempty"`
+	PlanTTL                   *string         `yaml:"plan_ttl,omitempty" json:"plan_ttl,omitempty"`
+	ApprovalPolicy            *ApprovalPolicy `yaml:"approval_policy,omitempty" json:"approval_policy,omitempty"`
 }
 
 func (g GlobalCfg) Validate() error {
@@ -41,6 +47,8 @@ func (g GlobalCfg) Validate() error {
 		validation.Field(&g.Repos),
 		validation.Field(&g.Workflows),
 		validation.Field(&g.Metrics),
+		validation.Field(&g.DestroyProtection),
+		validation.Field(&g.Permissions),
 	)
 	if err != nil {
 		return err
@@ -132,11 +140,19 @@ func (g GlobalCfg) ToValid(defaultCfg valid.GlobalCfg) valid.GlobalCfg {
 	}
 	repos = append(defaultCfg.Repos, repos...)
 
+	var permissions valid.Permissions
+	for _, p := range g.Permissions {
+		permissions = append(permissions, p.ToValid())
+	}
+
+	policySets := g.PolicySets.ToValid()
 	return valid.GlobalCfg{
-		Repos:      repos,
-		Workflows:  workflows,
-		PolicySets: g.PolicySets.ToValid(),
-		Metrics:    g.Metrics.ToValid(),
+		Repos:             repos,
+		Workflows:         workflows,
+		PolicySets:        policySets,
+		Metrics:           g.Metrics.ToValid(),
+		DestroyProtection: g.DestroyProtection.ToValid(policySets.Owners),
+		Permissions:       permissions,
 	}
 }
 
@@ -209,6 +225,33 @@ func (r Repo) Validate() error {
 		return nil
 	}
 
+	driftDetectionValid := func(value interface{}) error {
+		driftDetection := value.(*DriftDetection)
+		if driftDetection == nil || !driftDetection.Enabled {
+			return nil
+		}
+		// We can only plan repos we know the name of.
+		if r.HasRegexID() {
+			return errors.New("only supported for repos with an exact match id")
+		}
+		return driftDetection.Validate()
+	}
+
+	planTTLValid := func(value interface{}) error {
+		planTTL := value.(*string)
+		if planTTL == nil || *planTTL == "" {
+			return nil
+		}
+		duration, err := time.ParseDuration(*planTTL)
+		if err != nil {
+			return err
+		}
+		if duration < 0 {
+			return errors.New("must not be negative")
+		}
+		return nil
+	}
+
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.ID, validation.Required, validation.By(idValid)),
 		validation.Field(&r.Branch, validation.By(branchValid)),
@@ -219,6 +262,9 @@ func (r Repo) Validate() error {
 		validation.Field(&r.ImportRequirements, validation.By(validImportReq)),
 		validation.Field(&r.Workflow, validation.By(workflowExists)),
 		validation.Field(&r.DeleteSourceBranchOnMerge, validation.By(deleteSourceBranchOnMergeValid)),
+		validation.Field(&r.DriftDetection, validation.By(driftDetectionValid)),
+		validation.Field(&r.PlanTTL, validation.By(planTTLValid)),
+		validation.Field(&r.ApprovalPolicy),
 	)
 }
 
@@ -298,6 +344,24 @@ OuterGlobalImportReqs:
 		mergedImportReqs = append(mergedImportReqs, globalReq)
 	}
 
+	var driftDetection *valid.DriftDetection
+	if r.DriftDetection != nil {
+		driftDetection = r.DriftDetection.ToValid()
+	}
+
+	var approvalPolicy *valid.ApprovalPolicy
+	if r.ApprovalPolicy != nil {
+		approvalPolicy = r.ApprovalPolicy.ToValid()
+	}
+
+	var planTTL *time.Duration
+	if r.PlanTTL != nil {
+		// Safe to ignore the error because we test it in Validate(). An
+		// empty plan_ttl disables the TTL set by --plan-ttl.
+		ttl, _ := time.ParseDuration(*r.PlanTTL)
+		planTTL = &ttl
+	}
+
 	return valid.Repo{
 		ID:                        id,
 		IDRegex:                   idRegex,
@@ -314,5 +378,8 @@ OuterGlobalImportReqs:
 		AllowCustomWorkflows:      r.AllowCustomWorkflows,
 		DeleteSourceBranchOnMerge: r.DeleteSourceBranchOnMerge,
 		RepoLocking:               r.RepoLocking,
+		DriftDetection:            driftDetection,
+		PlanTTL:                   planTTL,
+		ApprovalPolicy:            approvalPolicy,
 	}
 }
@@ -0,0 +1,100 @@
+package raw
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// Permission is the raw schema for a rule in the permissions section of the
+// server-side repo config.
+type Permission struct {
+	// Repos is a repo ID or a regex, surrounded by slashes, matching repo
+	// IDs. If empty, the rule applies to all repos.
+	Repos      string   `yaml:"repos,omitempty" json:"repos,omitempty"`
+	Projects   []string `yaml:"projects,omitempty" json:"projects,omitempty"`
+	Workspaces []string `yaml:"workspaces,omitempty" json:"workspaces,omitempty"`
+	Users      []string `yaml:"users,omitempty" json:"users,omitempty"`
+	Teams      []string `yaml:"teams,omitempty" json:"teams,omitempty"`
+	Commands   []string `yaml:"commands,omitempty" json:"commands,omitempty"`
+}
+
+func (p Permission) hasRegexRepos() bool {
+	return strings.HasPrefix(p.Repos, "/") && strings.HasSuffix(p.Repos, "/") && len(p.Repos) > 1
+}
+
+func (p Permission) Validate() error {
+	reposValid := func(value interface{}) error {
+		if !p.hasRegexRepos() {
+			return nil
+		}
+		_, err := regexp.Compile(p.Repos[1 : len(p.Repos)-1])
+		if err != nil {
+			return fmt.Errorf("parsing: %s: %w", p.Repos, err)
+		}
+		return nil
+	}
+	patternsValid := func(value interface{}) error {
+		for _, pattern := range value.([]string) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%q is not a valid pattern: %s", pattern, err)
+			}
+		}
+		return nil
+	}
+	whoValid := func(value interface{}) error {
+		if len(p.Users) == 0 && len(p.Teams) == 0 {
+			return errors.New("at least one of users or teams must be set")
+		}
+		return nil
+	}
+	commandsValid := func(value interface{}) error {
+		for _, c := range value.([]string) {
+			if c == valid.PermissionWildcard {
+				continue
+			}
+			found := false
+			var names []string
+			for _, name := range command.AllCommentCommands {
+				names = append(names, name.String())
+				if strings.EqualFold(c, name.String()) {
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("%q is not a valid command, only %s and %q are supported", c, strings.Join(names, ", "), valid.PermissionWildcard)
+			}
+		}
+		return nil
+	}
+	return validation.ValidateStruct(&p,
+		validation.Field(&p.Repos, validation.By(reposValid)),
+		validation.Field(&p.Projects, validation.By(patternsValid)),
+		validation.Field(&p.Workspaces, validation.By(patternsValid)),
+		validation.Field(&p.Users, validation.By(whoValid)),
+		validation.Field(&p.Commands, validation.Required, validation.By(commandsValid)),
+	)
+}
+
+func (p Permission) ToValid() valid.Permission {
+	v := valid.Permission{
+		Projects:   p.Projects,
+		Workspaces: p.Workspaces,
+		Users:      p.Users,
+		Teams:      p.Teams,
+		Commands:   p.Commands,
+	}
+	if p.hasRegexRepos() {
+		// Safe to use MustCompile because we've already validated the regex.
+		v.RepoIDRegex = regexp.MustCompile(p.Repos[1 : len(p.Repos)-1])
+	} else {
+		v.RepoID = p.Repos
+	}
+	return v
+}
@@ -0,0 +1,129 @@
+package raw_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+	"gopkg.in/yaml.v2"
+)
+
+func TestPermission_Unmarshal(t *testing.T) {
+	rawYaml := `
+repos: /github.com\/acme\/.*/
+projects: [prod-*]
+workspaces: [default]
+users: [alice]
+teams: [platform]
+commands: [plan, apply]
+`
+	var result raw.Permission
+	Ok(t, yaml.UnmarshalStrict([]byte(rawYaml), &result))
+	Equals(t, raw.Permission{
+		Repos:      `/github.com\/acme\/.*/`,
+		Projects:   []string{"prod-*"},
+		Workspaces: []string{"default"},
+		Users:      []string{"alice"},
+		Teams:      []string{"platform"},
+		Commands:   []string{"plan", "apply"},
+	}, result)
+}
+
+func TestPermission_Validate(t *testing.T) {
+	cases := []struct {
+		description string
+		input       raw.Permission
+		expErr      string
+	}{
+		{
+			description: "valid",
+			input: raw.Permission{
+				Repos:    "/.*/",
+				Projects: []string{"prod-*"},
+				Teams:    []string{"platform"},
+				Commands: []string{"plan", "apply"},
+			},
+		},
+		{
+			description: "wildcards",
+			input: raw.Permission{
+				Users:    []string{"*"},
+				Commands: []string{"*"},
+			},
+		},
+		{
+			description: "no users or teams",
+			input: raw.Permission{
+				Commands: []string{"plan"},
+			},
+			expErr: "users: at least one of users or teams must be set.",
+		},
+		{
+			description: "no commands",
+			input: raw.Permission{
+				Users: []string{"alice"},
+			},
+			expErr: "commands: cannot be blank.",
+		},
+		{
+			description: "invalid command",
+			input: raw.Permission{
+				Users:    []string{"alice"},
+				Commands: []string{"destroy"},
+			},
+			expErr: `commands: "destroy" is not a valid command, only version, plan, apply, unlock, approve_policies, approve_destroy, import, state, cancel and "*" are supported.`,
+		},
+		{
+			description: "invalid repos regex",
+			input: raw.Permission{
+				Repos:    "/(/",
+				Users:    []string{"alice"},
+				Commands: []string{"plan"},
+			},
+			expErr: "repos: parsing: /(/: error parsing regexp: missing closing ): `(`.",
+		},
+		{
+			description: "invalid project pattern",
+			input: raw.Permission{
+				Projects: []string{"prod-["},
+				Users:    []string{"alice"},
+				Commands: []string{"plan"},
+			},
+			expErr: `projects: "prod-[" is not a valid pattern: syntax error in pattern.`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := c.input.Validate()
+			if c.expErr == "" {
+				Ok(t, err)
+				return
+			}
+			ErrEquals(t, c.expErr, err)
+		})
+	}
+}
+
+func TestPermission_ToValid(t *testing.T) {
+	Equals(t, valid.Permission{
+		RepoIDRegex: regexp.MustCompile(`github.com/acme/.*`),
+		Users:       []string{"alice"},
+		Commands:    []string{"plan"},
+	}, raw.Permission{
+		Repos:    "/github.com/acme/.*/",
+		Users:    []string{"alice"},
+		Commands: []string{"plan"},
+	}.ToValid())
+
+	Equals(t, valid.Permission{
+		RepoID:   "github.com/acme/infra",
+		Teams:    []string{"platform"},
+		Commands: []string{"*"},
+	}, raw.Permission{
+		Repos:    "github.com/acme/infra",
+		Teams:    []string{"platform"},
+		Commands: []string{"*"},
+	}.ToValid())
+}
@@ -1,6 +1,10 @@
 package raw
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	validation "github.com/go-ozzo/ozzo-validation"
 	version "github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -69,21 +73,56 @@ func (o PolicyOwners) ToValid() valid.PolicyOwners {
 	return policyOwners
 }
 
+// DefaultPolicySetRefreshInterval is how often remote policy sets are fetched
+// again if no interval is configured.
+const DefaultPolicySetRefreshInterval = time.Hour
+
 type PolicySet struct {
-	Path         string       `yaml:"path" json:"path"`
-	Source       string       `yaml:"source" json:"source"`
-	Name         string       `yaml:"name" json:"name"`
-	Owners       PolicyOwners `yaml:"owners,omitempty" json:"owners,omitempty"`
-	ApproveCount int          `yaml:"approve_count,omitempty" json:"approve_count,omitempty"`
+	Path            string       `yaml:"path" json:"path"`
+	Source          string       `yaml:"source" json:"source"`
+	Name            string       `yaml:"name" json:"name"`
+	Owners          PolicyOwners `yaml:"owners,omitempty" json:"owners,omitempty"`
+	ApproveCount    int          `yaml:"approve_count,omitempty" json:"approve_count,omitempty"`
+	Ref             string       `yaml:"ref,omitempty" json:"ref,omitempty"`
+	Checksum        string       `yaml:"checksum,omitempty" json:"checksum,omitempty"`
+	RefreshInterval string       `yaml:"refresh_interval,omitempty" json:"refresh_interval,omitempty"`
 }
 
 func (p PolicySet) Validate() error {
+	onlyForSource := func(source string) validation.RuleFunc {
+		return func(value interface{}) error {
+			if value.(string) != "" && p.Source != source {
+				return fmt.Errorf("is only supported for '%s' sources", source)
+			}
+			return nil
+		}
+	}
+	refreshIntervalValid := func(value interface{}) error {
+		interval := value.(string)
+		if interval == "" {
+			return nil
+		}
+		if p.Source != valid.GitPolicySet && p.Source != valid.HTTPPolicySet {
+			return errors.New("is only supported for 'git' and 'http' sources")
+		}
+		duration, err := time.ParseDuration(interval)
+		if err != nil {
+			return err
+		}
+		if duration < time.Minute {
+			return errors.New("must be at least 1m")
+		}
+		return nil
+	}
 	return validation.ValidateStruct(&p,
 		validation.Field(&p.Name, validation.Required.Error("is required")),
 		validation.Field(&p.Owners),
 		validation.Field(&p.ApproveCount),
 		validation.Field(&p.Path, validation.Required.Error("is required")),
-		validation.Field(&p.Source, validation.In(valid.LocalPolicySet, valid.GithubPolicySet).Error("only 'local' and 'github' source types are supported")),
+		validation.Field(&p.Source, validation.In(valid.LocalPolicySet, valid.GithubPolicySet, valid.GitPolicySet, valid.HTTPPolicySet).Error("only 'local', 'github', 'git' and 'http' source types are supported")),
+		validation.Field(&p.Ref, validation.By(onlyForSource(valid.GitPolicySet))),
+		validation.Field(&p.Checksum, validation.By(onlyForSource(valid.HTTPPolicySet))),
+		validation.Field(&p.RefreshInterval, validation.By(refreshIntervalValid)),
 	)
 }
 
@@ -95,6 +134,16 @@ func (p PolicySet) ToValid() valid.PolicySet {
 	policySet.Source = p.Source
 	policySet.ApproveCount = p.ApproveCount
 	policySet.Owners = p.Owners.ToValid()
+	policySet.Ref = p.Ref
+	policySet.Checksum = p.Checksum
+
+	if policySet.IsRemote() {
+		policySet.RefreshInterval = DefaultPolicySetRefreshInterval
+		if p.RefreshInterval != "" {
+			// Safe to ignore the error because we test it in Validate().
+			policySet.RefreshInterval, _ = time.ParseDuration(p.RefreshInterval)
+		}
+	}
 
 	return policySet
 }
@@ -2,6 +2,7 @@ package raw_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/core/config/raw"
@@ -89,6 +90,19 @@ func TestPolicySets_Validate(t *testing.T) {
 						Path:   "rel/path/to/source",
 						Source: valid.GithubPolicySet,
 					},
+					{
+						Name:            "policy-name-3",
+						Path:            "https://github.com/org/policies.git//terraform",
+						Source:          valid.GitPolicySet,
+						Ref:             "v1.2.0",
+						RefreshInterval: "30m",
+					},
+					{
+						Name:     "policy-name-4",
+						Path:     "https://example.com/policies.tar.gz",
+						Source:   valid.HTTPPolicySet,
+						Checksum: "sha256:6a4c2ce9d4a5e8a5a2e3b3c1a1a0b5e2a9f1b7c3d5e6f7a8b9c0d1e2f3a4b5c6",
+					},
 				},
 			},
 			expErr: "",
@@ -121,7 +135,63 @@ func TestPolicySets_Validate(t *testing.T) {
 					},
 				},
 			},
-			expErr: "policy_sets: (0: (source: only 'local' and 'github' source types are supported.).).",
+			expErr: "policy_sets: (0: (source: only 'local', 'github', 'git' and 'http' source types are supported.).).",
+		},
+		{
+			description: "ref on http source",
+			input: raw.PolicySets{
+				PolicySets: []raw.PolicySet{
+					{
+						Name:   "good-policy",
+						Source: valid.HTTPPolicySet,
+						Path:   "https://example.com/policies.tar.gz",
+						Ref:    "main",
+					},
+				},
+			},
+			expErr: "policy_sets: (0: (ref: is only supported for 'git' sources.).).",
+		},
+		{
+			description: "checksum on git source",
+			input: raw.PolicySets{
+				PolicySets: []raw.PolicySet{
+					{
+						Name:     "good-policy",
+						Source:   valid.GitPolicySet,
+						Path:     "https://github.com/org/policies.git",
+						Checksum: "sha256:abc",
+					},
+				},
+			},
+			expErr: "policy_sets: (0: (checksum: is only supported for 'http' sources.).).",
+		},
+		{
+			description: "refresh interval on local source",
+			input: raw.PolicySets{
+				PolicySets: []raw.PolicySet{
+					{
+						Name:            "good-policy",
+						Source:          valid.LocalPolicySet,
+						Path:            "rel/path/to/source",
+						RefreshInterval: "1h",
+					},
+				},
+			},
+			expErr: "policy_sets: (0: (refresh_interval: is only supported for 'git' and 'http' sources.).).",
+		},
+		{
+			description: "refresh interval too short",
+			input: raw.PolicySets{
+				PolicySets: []raw.PolicySet{
+					{
+						Name:            "good-policy",
+						Source:          valid.GitPolicySet,
+						Path:            "https://github.com/org/policies.git",
+						RefreshInterval: "10s",
+					},
+				},
+			},
+			expErr: "policy_sets: (0: (refresh_interval: must be at least 1m.).).",
 		},
 		{
 			description: "empty string version",
@@ -258,6 +328,47 @@ func TestPolicySets_ToValid(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "remote policies",
+			input: raw.PolicySets{
+				PolicySets: []raw.PolicySet{
+					{
+						Name:   "git-policy",
+						Path:   "https://github.com/org/policies.git",
+						Source: valid.GitPolicySet,
+						Ref:    "v1.2.0",
+					},
+					{
+						Name:            "http-policy",
+						Path:            "https://example.com/policies.tar.gz",
+						Source:          valid.HTTPPolicySet,
+						Checksum:        "sha256:abc",
+						RefreshInterval: "15m",
+					},
+				},
+			},
+			exp: valid.PolicySets{
+				ApproveCount: 1,
+				PolicySets: []valid.PolicySet{
+					{
+						Name:            "git-policy",
+						Path:            "https://github.com/org/policies.git",
+						Source:          "git",
+						Ref:             "v1.2.0",
+						ApproveCount:    1,
+						RefreshInterval: time.Hour,
+					},
+					{
+						Name:            "http-policy",
+						Path:            "https://example.com/policies.tar.gz",
+						Source:          "http",
+						Checksum:        "sha256:abc",
+						ApproveCount:    1,
+						RefreshInterval: 15 * time.Minute,
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -5,6 +5,7 @@ import (
 	"net/url"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation"
@@ -18,6 +19,13 @@ const (
 	ApprovedRequirement   = "approved"
 	MergeableRequirement  = "mergeable"
 	UnDivergedRequirement = "undiverged"
+	// DestroyApprovedRequirement is only supported as an apply requirement.
+	DestroyApprovedRequirement = "destroy_approved"
+	// ApprovalPolicyRequirement is only supported as an apply requirement.
+	ApprovalPolicyRequirement = "approval_policy"
+	// CostUnderRequirement is only supported as an apply requirement. It's
+	// written with the maximum increase in monthly cost, ex. cost_under:500.
+	CostUnderRequirement = "cost_under"
 )
 
 type Project struct {
@@ -34,6 +42,7 @@ type Project struct {
 	DeleteSourceBranchOnMerge *bool     `yaml:"delete_source_branch_on_merge,omitempty"`
 	RepoLocking               *bool     `yaml:"repo_locking,omitempty"`
 	ExecutionOrderGroup       *int      `yaml:"execution_order_group,omitempty"`
+	DependsOn                 []string  `yaml:"depends_on,omitempty"`
 }
 
 func (p Project) Validate() error {
@@ -72,6 +81,15 @@ func (p Project) Validate() error {
 		return errors.Wrapf(err, "parsing: %s", branch)
 	}
 
+	dependsOnValid := func(value interface{}) error {
+		for _, name := range value.([]string) {
+			if name == "" {
+				return errors.New("project names cannot be empty")
+			}
+		}
+		return nil
+	}
+
 	return validation.ValidateStruct(&p,
 		validation.Field(&p.Dir, validation.Required, validation.By(hasDotDot)),
 		validation.Field(&p.PlanRequirements, validation.By(validPlanReq)),
@@ -80,6 +98,7 @@ func (p Project) Validate() error {
 		validation.Field(&p.TerraformVersion, validation.By(VersionValidator)),
 		validation.Field(&p.Name, validation.By(validName)),
 		validation.Field(&p.Branch, validation.By(branchValid)),
+		validation.Field(&p.DependsOn, validation.By(dependsOnValid)),
 	)
 }
 
@@ -133,6 +152,8 @@ func (p Project) ToValid() valid.Project {
 		v.ExecutionOrderGroup = *p.ExecutionOrderGroup
 	}
 
+	v.DependsOn = p.DependsOn
+
 	return v
 }
 
@@ -158,13 +179,37 @@ func validPlanReq(value interface{}) error {
 func validApplyReq(value interface{}) error {
 	reqs := value.([]string)
 	for _, r := range reqs {
-		if r != ApprovedRequirement && r != MergeableRequirement && r != UnDivergedRequirement {
-			return fmt.Errorf("%q is not a valid apply_requirement, only %q, %q and %q are supported", r, ApprovedRequirement, MergeableRequirement, UnDivergedRequirement)
+		if _, ok, err := ParseCostUnderRequirement(r); ok {
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if r != ApprovedRequirement && r != MergeableRequirement && r != UnDivergedRequirement && r != DestroyApprovedRequirement && r != ApprovalPolicyRequirement {
+			return fmt.Errorf("%q is not a valid apply_requirement, only %q, %q, %q, %q, %q and %q are supported", r, ApprovedRequirement, MergeableRequirement, UnDivergedRequirement, DestroyApprovedRequirement, ApprovalPolicyRequirement, CostUnderRequirement+":<amount>")
 		}
 	}
 	return nil
 }
 
+// ParseCostUnderRequirement parses a cost_under apply requirement, ex.
+// "cost_under:500" or "cost_under: 500". ok is false if req isn't a cost_under
+// requirement.
+func ParseCostUnderRequirement(req string) (maxDiff float64, ok bool, err error) {
+	if req == CostUnderRequirement {
+		return 0, true, fmt.Errorf("%q is not a valid apply_requirement, it must be written with the maximum increase in monthly cost, ex. %s:500", req, CostUnderRequirement)
+	}
+	amount, found := strings.CutPrefix(req, CostUnderRequirement+":")
+	if !found {
+		return 0, false, nil
+	}
+	maxDiff, err = strconv.ParseFloat(strings.TrimSpace(amount), 64)
+	if err != nil {
+		return 0, true, fmt.Errorf("%q is not a valid apply_requirement, the amount must be a number", req)
+	}
+	return maxDiff, true, nil
+}
+
 func validImportReq(value interface{}) error {
 	reqs := value.([]string)
 	for _, r := range reqs {
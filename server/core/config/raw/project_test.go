@@ -170,7 +170,7 @@ func TestProject_Validate(t *testing.T) {
 				Dir:               String("."),
 				ApplyRequirements: []string{"unsupported"},
 			},
-			expErr: "apply_requirements: \"unsupported\" is not a valid apply_requirement, only \"approved\", \"mergeable\" and \"undiverged\" are supported.",
+			expErr: "apply_requirements: \"unsupported\" is not a valid apply_requirement, only \"approved\", \"mergeable\", \"undiverged\", \"destroy_approved\", \"approval_policy\" and \"cost_under:<amount>\" are supported.",
 		},
 		{
 			description: "apply reqs with approved requirement",
@@ -228,6 +228,30 @@ func TestProject_Validate(t *testing.T) {
 			},
 			expErr: "",
 		},
+		{
+			description: "apply reqs with cost_under requirement",
+			input: raw.Project{
+				Dir:               String("."),
+				ApplyRequirements: []string{"approved", "cost_under:500", "cost_under: 12.5"},
+			},
+			expErr: "",
+		},
+		{
+			description: "apply reqs with cost_under requirement without amount",
+			input: raw.Project{
+				Dir:               String("."),
+				ApplyRequirements: []string{"cost_under"},
+			},
+			expErr: "apply_requirements: \"cost_under\" is not a valid apply_requirement, it must be written with the maximum increase in monthly cost, ex. cost_under:500.",
+		},
+		{
+			description: "apply reqs with cost_under requirement with invalid amount",
+			input: raw.Project{
+				Dir:               String("."),
+				ApplyRequirements: []string{"cost_under:$500"},
+			},
+			expErr: "apply_requirements: \"cost_under:$500\" is not a valid apply_requirement, the amount must be a number.",
+		},
 		{
 			description: "import reqs with unsupported",
 			input: raw.Project{
@@ -1,17 +1,23 @@
 package raw
 
 import (
+	"errors"
+	"time"
+
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 )
 
 type Stage struct {
 	Steps []Step `yaml:"steps,omitempty" json:"steps,omitempty"`
+	// Timeout is how long all of the stage's steps can take, ex. 1h.
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
 func (s Stage) Validate() error {
 	return validation.ValidateStruct(&s,
 		validation.Field(&s.Steps),
+		validation.Field(&s.Timeout, validation.By(timeoutValid)),
 	)
 }
 
@@ -20,7 +26,36 @@ func (s Stage) ToValid() valid.Stage {
 	for _, s := range s.Steps {
 		validSteps = append(validSteps, s.ToValid())
 	}
+	// Timeout has already been validated.
+	timeout, _ := time.ParseDuration(s.Timeout)
 	return valid.Stage{
-		Steps: validSteps,
+		Steps:   validSteps,
+		Timeout: timeout,
+	}
+}
+
+// timeoutValid validates stage and step timeouts, which must be positive
+// durations if they're set.
+func timeoutValid(value interface{}) error {
+	var timeout string
+	switch v := value.(type) {
+	case string:
+		timeout = v
+	case *string:
+		if v == nil {
+			return nil
+		}
+		timeout = *v
+	}
+	if timeout == "" {
+		return nil
+	}
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return err
+	}
+	if duration <= 0 {
+		return errors.New("must be positive")
 	}
+	return nil
 }
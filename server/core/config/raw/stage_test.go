@@ -2,6 +2,7 @@ package raw_test
 
 import (
 	"testing"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/runatlantis/atlantis/server/core/config/raw"
@@ -27,6 +28,7 @@ func TestStage_UnmarshalYAML(t *testing.T) {
 			description: "all fields set",
 			input: `
 steps: [step1]
+timeout: 1h
 `,
 			exp: raw.Stage{
 				Steps: []raw.Step{
@@ -34,6 +36,7 @@ steps: [step1]
 						Key: String("step1"),
 					},
 				},
+				Timeout: "1h",
 			},
 		},
 	}
@@ -60,6 +63,9 @@ func TestStage_Validate(t *testing.T) {
 	validation.ErrorTag = "yaml"
 	ErrEquals(t, "steps: (0: \"invalid\" is not a valid step type, maybe you omitted the 'run' key.).", s.Validate())
 
+	// Should validate the timeout.
+	ErrEquals(t, "timeout: must be positive.", (raw.Stage{Timeout: "-1h"}).Validate())
+
 	// Empty steps should validate.
 	Ok(t, (raw.Stage{}).Validate())
 }
@@ -85,6 +91,7 @@ func TestStage_ToValid(t *testing.T) {
 						Key: String("init"),
 					},
 				},
+				Timeout: "30m",
 			},
 			exp: valid.Stage{
 				Steps: []valid.Step{
@@ -92,6 +99,7 @@ func TestStage_ToValid(t *testing.T) {
 						StepName: "init",
 					},
 				},
+				Timeout: 30 * time.Minute,
 			},
 		},
 	}
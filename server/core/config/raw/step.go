@@ -6,26 +6,29 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 )
 
 const (
-	ExtraArgsKey        = "extra_args"
-	NameArgKey          = "name"
-	CommandArgKey       = "command"
-	ValueArgKey         = "value"
-	RunStepName         = "run"
-	PlanStepName        = "plan"
-	ShowStepName        = "show"
-	PolicyCheckStepName = "policy_check"
-	ApplyStepName       = "apply"
-	InitStepName        = "init"
-	EnvStepName         = "env"
-	MultiEnvStepName    = "multienv"
-	ImportStepName      = "import"
-	StateRmStepName     = "state_rm"
+	ExtraArgsKey         = "extra_args"
+	NameArgKey           = "name"
+	CommandArgKey        = "command"
+	ValueArgKey          = "value"
+	RunStepName          = "run"
+	PlanStepName         = "plan"
+	ShowStepName         = "show"
+	PolicyCheckStepName  = "policy_check"
+	CostEstimateStepName = "cost_estimate"
+	ApplyStepName        = "apply"
+	InitStepName         = "init"
+	EnvStepName          = "env"
+	MultiEnvStepName     = "multienv"
+	ImportStepName       = "import"
+	StateRmStepName      = "state_rm"
+	TimeoutKey           = "timeout"
 )
 
 // Step represents a single action/command to perform. In YAML, it can be set as
@@ -47,6 +50,16 @@ const (
 // 4. A map for a custom run command:
 //   - run: my custom command
 //
+// Any step can also set a timeout. Custom run and multienv steps set it next
+// to the command:
+//   - run: my custom command
+//     timeout: 10m
+//
+// Other steps set it with their args:
+//   - plan:
+//     extra_args: [-var-file=staging.tfvars]
+//     timeout: 1h
+//
 // Here we parse step in the most generic fashion possible. See fields for more
 // details.
 type Step struct {
@@ -59,6 +72,8 @@ type Step struct {
 	Map map[string]map[string][]string
 	// StringVal will be set in case #4 above.
 	StringVal map[string]string
+	// Timeout is set if the step has a timeout, in any case above.
+	Timeout *string
 }
 
 func (s *Step) UnmarshalYAML(unmarshal func(interface{}) error) error {
@@ -91,6 +106,7 @@ func (s Step) validStepName(stepName string) bool {
 		stepName == MultiEnvStepName ||
 		stepName == ShowStepName ||
 		stepName == PolicyCheckStepName ||
+		stepName == CostEstimateStepName ||
 		stepName == ImportStepName ||
 		stepName == StateRmStepName
 }
@@ -208,6 +224,9 @@ func (s Step) Validate() error {
 		return nil
 	}
 
+	if err := validation.Validate(s.Timeout, validation.By(timeoutValid)); err != nil {
+		return fmt.Errorf("%s: %w", TimeoutKey, err)
+	}
 	if s.Key != nil {
 		return validation.Validate(s.Key, validation.By(validStep))
 	}
@@ -224,6 +243,15 @@ func (s Step) Validate() error {
 }
 
 func (s Step) ToValid() valid.Step {
+	step := s.toValidWithoutTimeout()
+	if s.Timeout != nil {
+		// Timeout has already been validated.
+		step.Timeout, _ = time.ParseDuration(*s.Timeout)
+	}
+	return step
+}
+
+func (s Step) toValidWithoutTimeout() valid.Step {
 	// This will trigger in case #1 (see Step docs).
 	if s.Key != nil {
 		return valid.Step{
@@ -306,6 +334,15 @@ func (s *Step) unmarshalGeneric(unmarshal func(interface{}) error) error {
 		return nil
 	}
 
+	// This represents a step with extra_args and a timeout, ex:
+	//   plan:
+	//     extra_args: [a, b]
+	//     timeout: 1h
+	var stepWithTimeout map[string]map[string]interface{}
+	if unmarshal(&stepWithTimeout) == nil && s.unmarshalStepWithTimeout(stepWithTimeout) {
+		return nil
+	}
+
 	// This represents an env step, ex:
 	//   env:
 	//     name: k
@@ -315,6 +352,19 @@ func (s *Step) unmarshalGeneric(unmarshal func(interface{}) error) error {
 	err = unmarshal(&envStep)
 	if err == nil {
 		s.Env = envStep
+		for stepName, args := range envStep {
+			if timeout, ok := args[TimeoutKey]; ok {
+				s.Timeout = &timeout
+				delete(args, TimeoutKey)
+			}
+			// A built-in step with only a timeout, ex:
+			//   init:
+			//     timeout: 5m
+			if stepName != EnvStepName && len(args) == 0 {
+				s.Env = nil
+				s.Map = map[string]map[string][]string{stepName: {}}
+			}
+		}
 		return nil
 	}
 
@@ -325,6 +375,10 @@ func (s *Step) unmarshalGeneric(unmarshal func(interface{}) error) error {
 	var runStep map[string]string
 	err = unmarshal(&runStep)
 	if err == nil {
+		if timeout, ok := runStep[TimeoutKey]; ok && len(runStep) > 1 {
+			s.Timeout = &timeout
+			delete(runStep, TimeoutKey)
+		}
 		s.StringVal = runStep
 		return nil
 	}
@@ -332,7 +386,45 @@ func (s *Step) unmarshalGeneric(unmarshal func(interface{}) error) error {
 	return err
 }
 
+// unmarshalStepWithTimeout sets the step from a built-in step whose args
+// include a timeout as well as extra_args. It returns false if step isn't
+// one.
+func (s *Step) unmarshalStepWithTimeout(step map[string]map[string]interface{}) bool {
+	if len(step) != 1 {
+		return false
+	}
+	for stepName, args := range step {
+		timeout, ok := args[TimeoutKey].(string)
+		if !ok {
+			return false
+		}
+		stepArgs := make(map[string][]string)
+		for k, v := range args {
+			if k == TimeoutKey {
+				continue
+			}
+			list, ok := v.([]interface{})
+			if !ok {
+				return false
+			}
+			for _, elem := range list {
+				str, ok := elem.(string)
+				if !ok {
+					return false
+				}
+				stepArgs[k] = append(stepArgs[k], str)
+			}
+		}
+		s.Map = map[string]map[string][]string{stepName: stepArgs}
+		s.Timeout = &timeout
+	}
+	return true
+}
+
 func (s Step) marshalGeneric() (interface{}, error) {
+	if s.Timeout != nil {
+		return s.marshalWithTimeout(), nil
+	}
 	if len(s.StringVal) != 0 {
 		return s.StringVal, nil
 	} else if len(s.Map) != 0 {
@@ -347,3 +439,35 @@ func (s Step) marshalGeneric() (interface{}, error) {
 	// unexpected behavior.
 	return nil, nil
 }
+
+// marshalWithTimeout marshals a step that has a timeout in the same forms
+// that unmarshalGeneric accepts.
+func (s Step) marshalWithTimeout() interface{} {
+	if len(s.StringVal) != 0 {
+		out := map[string]string{TimeoutKey: *s.Timeout}
+		for k, v := range s.StringVal {
+			out[k] = v
+		}
+		return out
+	}
+	out := make(map[string]map[string]interface{})
+	if s.Key != nil {
+		out[*s.Key] = map[string]interface{}{}
+	}
+	for stepName, args := range s.Map {
+		out[stepName] = map[string]interface{}{}
+		for k, v := range args {
+			out[stepName][k] = v
+		}
+	}
+	for stepName, args := range s.Env {
+		out[stepName] = map[string]interface{}{}
+		for k, v := range args {
+			out[stepName][k] = v
+		}
+	}
+	for stepName := range out {
+		out[stepName][TimeoutKey] = *s.Timeout
+	}
+	return out
+}
@@ -2,6 +2,7 @@ package raw_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/runatlantis/atlantis/server/core/config/raw"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -129,6 +130,64 @@ key: value`,
 			},
 		},
 
+		// Timeouts
+		{
+			description: "built-in step with timeout",
+			input: `
+init:
+  timeout: 5m`,
+			exp: raw.Step{
+				Map: MapType{
+					"init": {},
+				},
+				Timeout: String("5m"),
+			},
+		},
+		{
+			description: "extra_args style with timeout",
+			input: `
+plan:
+  extra_args: [arg1, arg2]
+  timeout: 1h`,
+			exp: raw.Step{
+				Map: MapType{
+					"plan": {
+						"extra_args": {"arg1", "arg2"},
+					},
+				},
+				Timeout: String("1h"),
+			},
+		},
+		{
+			description: "env step with timeout",
+			input: `
+env:
+  name: test
+  command: echo 312
+  timeout: 1m`,
+			exp: raw.Step{
+				Env: EnvType{
+					"env": {
+						"name":    "test",
+						"command": "echo 312",
+					},
+				},
+				Timeout: String("1m"),
+			},
+		},
+		{
+			description: "run step with timeout",
+			input: `
+run: my command
+timeout: 10m`,
+			exp: raw.Step{
+				StringVal: map[string]string{
+					"run": "my command",
+				},
+				Timeout: String("10m"),
+			},
+		},
+
 		// Empty
 		{
 			description: "empty",
@@ -174,6 +233,48 @@ key:
 	}
 }
 
+// Test that steps with timeouts are marshalled in a form that unmarshals to
+// the same step.
+func TestStepConfig_YAMLMarshallingTimeout(t *testing.T) {
+	cases := []raw.Step{
+		{
+			Key:     String("init"),
+			Timeout: String("5m"),
+		},
+		{
+			Map: MapType{
+				"plan": {
+					"extra_args": {"arg1", "arg2"},
+				},
+			},
+			Timeout: String("1h"),
+		},
+		{
+			Env: EnvType{
+				"env": {
+					"name":  "test",
+					"value": "value",
+				},
+			},
+			Timeout: String("1m"),
+		},
+		{
+			StringVal: map[string]string{
+				"run": "my command",
+			},
+			Timeout: String("10m"),
+		},
+	}
+	for _, c := range cases {
+		out, err := yaml.Marshal(c)
+		Ok(t, err)
+
+		var got raw.Step
+		Ok(t, yaml.UnmarshalStrict(out, &got))
+		Equals(t, c.ToValid(), got.ToValid())
+	}
+}
+
 func TestStep_Validate(t *testing.T) {
 	cases := []struct {
 		description string
@@ -202,6 +303,13 @@ func TestStep_Validate(t *testing.T) {
 			},
 			expErr: "",
 		},
+		{
+			description: "cost_estimate step",
+			input: raw.Step{
+				Key: String("cost_estimate"),
+			},
+			expErr: "",
+		},
 		{
 			description: "init extra_args",
 			input: raw.Step{
@@ -256,8 +364,34 @@ func TestStep_Validate(t *testing.T) {
 			},
 			expErr: "",
 		},
+		{
+			description: "step with timeout",
+			input: raw.Step{
+				Key:     String("plan"),
+				Timeout: String("1h"),
+			},
+			expErr: "",
+		},
 
 		// Invalid inputs.
+		{
+			description: "invalid timeout",
+			input: raw.Step{
+				Key:     String("plan"),
+				Timeout: String("soon"),
+			},
+			expErr: "timeout: time: invalid duration \"soon\"",
+		},
+		{
+			description: "zero timeout",
+			input: raw.Step{
+				StringVal: map[string]string{
+					"run": "my command",
+				},
+				Timeout: String("0s"),
+			},
+			expErr: "timeout: must be positive",
+		},
 		{
 			description: "empty elem",
 			input:       raw.Step{},
@@ -518,6 +652,20 @@ func TestStep_ToValid(t *testing.T) {
 				ExtraArgs: []string{"arg1", "arg2"},
 			},
 		},
+		{
+			description: "cost_estimate extra_args",
+			input: raw.Step{
+				Map: MapType{
+					"cost_estimate": {
+						"extra_args": []string{"--show-skipped"},
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:  "cost_estimate",
+				ExtraArgs: []string{"--show-skipped"},
+			},
+		},
 		{
 			description: "apply extra_args",
 			input: raw.Step{
@@ -558,6 +706,20 @@ func TestStep_ToValid(t *testing.T) {
 				RunCommand: "my 'run command'",
 			},
 		},
+		{
+			description: "step with timeout",
+			input: raw.Step{
+				StringVal: map[string]string{
+					"run": "my command",
+				},
+				Timeout: String("10m"),
+			},
+			exp: valid.Step{
+				StepName:   "run",
+				RunCommand: "my command",
+				Timeout:    10 * time.Minute,
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.description, func(t *testing.T) {
@@ -0,0 +1,25 @@
+package valid
+
+// ApprovalPolicy configures which approvals the approval_policy apply
+// requirement needs.
+type ApprovalPolicy struct {
+	// Count is how many users other than the author must approve the pull
+	// request.
+	Count int
+	// Owners are the users and teams that at least one approver must be
+	// part of. If empty, anyone can approve.
+	Owners PolicyOwners
+	// CodeOwners requires that every file the pull request modifies in the
+	// project is approved by one of its owners in the CODEOWNERS file.
+	CodeOwners bool
+}
+
+// HasOwners returns true if approvals must come from specific users or teams.
+func (a *ApprovalPolicy) HasOwners() bool {
+	return len(a.Owners.Users) > 0 || len(a.Owners.Teams) > 0
+}
+
+// HasTeamOwners returns true if approvals can come from members of teams.
+func (a *ApprovalPolicy) HasTeamOwners() bool {
+	return len(a.Owners.Teams) > 0
+}
@@ -0,0 +1,38 @@
+package valid
+
+import (
+	"path"
+)
+
+// DestroyProtection configures which resources the destroy_approved apply
+// requirement protects and who can approve destroying them.
+type DestroyProtection struct {
+	// Resources are glob patterns matched against resource addresses, ex.
+	// aws_db_instance.* or module.*.aws_db_instance.*. If empty, all
+	// resources are protected.
+	Resources []string
+	// Owners are the users and teams that can approve destroying protected
+	// resources.
+	Owners PolicyOwners
+}
+
+// IsProtected returns true if the resource at address is protected.
+func (d *DestroyProtection) IsProtected(address string) bool {
+	if len(d.Resources) == 0 {
+		return true
+	}
+	for _, pattern := range d.Resources {
+		// We validate the patterns when parsing the config so we can ignore
+		// the error.
+		if match, _ := path.Match(pattern, address); match {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTeamOwners returns true if any teams can approve destroying protected
+// resources.
+func (d *DestroyProtection) HasTeamOwners() bool {
+	return len(d.Owners.Teams) > 0
+}
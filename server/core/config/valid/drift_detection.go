@@ -0,0 +1,15 @@
+package valid
+
+import (
+	"time"
+)
+
+// DriftDetection configures periodically planning a repo's projects to detect
+// infrastructure that no longer matches its configuration.
+type DriftDetection struct {
+	// Branch is the branch that's planned. If empty, the repo's default
+	// branch is planned.
+	Branch string
+	// Interval is how often the projects are planned.
+	Interval time.Duration
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	version "github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/logging"
@@ -14,6 +15,7 @@ const MergeableCommandReq = "mergeable"
 const ApprovedCommandReq = "approved"
 const UnDivergedCommandReq = "undiverged"
 const PoliciesPassedCommandReq = "policies_passed"
+const DestroyApprovedCommandReq = "destroy_approved"
 const PlanRequirementsKey = "plan_requirements"
 const ApplyRequirementsKey = "apply_requirements"
 const ImportRequirementsKey = "import_requirements"
@@ -39,10 +41,12 @@ var NonOverrideableApplyReqs = []string{PoliciesPassedCommandReq}
 
 // GlobalCfg is the final parsed version of server-side repo config.
 type GlobalCfg struct {
-	Repos      []Repo
-	Workflows  map[string]Workflow
-	PolicySets PolicySets
-	Metrics    Metrics
+	Repos             []Repo
+	Workflows         map[string]Workflow
+	PolicySets        PolicySets
+	Metrics           Metrics
+	DestroyProtection DestroyProtection
+	Permissions       Permissions
 }
 
 type Metrics struct {
@@ -80,6 +84,13 @@ type Repo struct {
 	AllowCustomWorkflows      *bool
 	DeleteSourceBranchOnMerge *bool
 	RepoLocking               *bool
+	// DriftDetection is nil if drift detection isn't enabled for this repo.
+	DriftDetection *DriftDetection
+	// PlanTTL is how long plans can be applied for. It's nil if it isn't set
+	// for this repo and 0 if plans don't expire.
+	PlanTTL *time.Duration
+	// ApprovalPolicy is nil if it isn't set for this repo.
+	ApprovalPolicy *ApprovalPolicy
 }
 
 type MergedProjectCfg struct {
@@ -96,9 +107,22 @@ type MergedProjectCfg struct {
 	TerraformVersion          *version.Version
 	RepoCfgVersion            int
 	PolicySets                PolicySets
+	DestroyProtection         DestroyProtection
 	DeleteSourceBranchOnMerge bool
 	ExecutionOrderGroup       int
 	RepoLocking               bool
+	// PlanTTL is how long plans can be applied for. Plans don't expire if
+	// it's 0.
+	PlanTTL time.Duration
+	// WhenModified are the patterns, relative to the project dir, of the
+	// files that affect the project.
+	WhenModified []string
+	// ApprovalPolicy configures the approval_policy apply requirement. It's
+	// nil if it isn't set for the repo.
+	ApprovalPolicy *ApprovalPolicy
+	// DependsOn are the names of the projects that must be applied before
+	// this project can be applied.
+	DependsOn []string
 }
 
 // WorkflowHook is a map of custom run commands to run before or after workflows.
@@ -199,6 +223,7 @@ type GlobalCfgArgs struct {
 	ApprovedReq        bool
 	UnDivergedReq      bool
 	PolicyCheckEnabled bool
+	PlanTTL            time.Duration
 	PreWorkflowHooks   []*WorkflowHook
 	PostWorkflowHooks  []*WorkflowHook
 }
@@ -233,6 +258,10 @@ func NewGlobalCfgFromArgs(args GlobalCfgArgs) GlobalCfg {
 	allowCustomWorkflows := false
 	deleteSourceBranchOnMerge := false
 	repoLockingKey := true
+	var planTTL *time.Duration
+	if args.PlanTTL > 0 {
+		planTTL = &args.PlanTTL
+	}
 	if args.AllowRepoCfg {
 		allowedOverrides = []string{PlanRequirementsKey, ApplyRequirementsKey, ImportRequirementsKey, WorkflowKey, DeleteSourceBranchOnMergeKey, RepoLockingKey}
 		allowCustomWorkflows = true
@@ -255,6 +284,7 @@ func NewGlobalCfgFromArgs(args GlobalCfgArgs) GlobalCfg {
 				AllowCustomWorkflows:      &allowCustomWorkflows,
 				DeleteSourceBranchOnMerge: &deleteSourceBranchOnMerge,
 				RepoLocking:               &repoLockingKey,
+				PlanTTL:                   planTTL,
 			},
 		},
 		Workflows: map[string]Workflow{
@@ -371,9 +401,14 @@ func (g GlobalCfg) MergeProjectCfg(log logging.SimpleLogging, repoID string, pro
 		TerraformVersion:          proj.TerraformVersion,
 		RepoCfgVersion:            rCfg.Version,
 		PolicySets:                g.PolicySets,
+		DestroyProtection:         g.DestroyProtection,
 		DeleteSourceBranchOnMerge: deleteSourceBranchOnMerge,
 		ExecutionOrderGroup:       proj.ExecutionOrderGroup,
 		RepoLocking:               repoLocking,
+		PlanTTL:                   g.planTTL(repoID),
+		ApprovalPolicy:            g.approvalPolicy(repoID),
+		WhenModified:              proj.Autoplan.WhenModified,
+		DependsOn:                 proj.DependsOn,
 	}
 }
 
@@ -393,9 +428,36 @@ func (g GlobalCfg) DefaultProjCfg(log logging.SimpleLogging, repoID string, repo
 		AutoplanEnabled:           DefaultAutoPlanEnabled,
 		TerraformVersion:          nil,
 		PolicySets:                g.PolicySets,
+		DestroyProtection:         g.DestroyProtection,
 		DeleteSourceBranchOnMerge: deleteSourceBranchOnMerge,
 		RepoLocking:               repoLocking,
+		PlanTTL:                   g.planTTL(repoID),
+		ApprovalPolicy:            g.approvalPolicy(repoID),
+	}
+}
+
+// planTTL returns the plan TTL of the last repo config matching repoID that
+// sets one, like getMatchingCfg.
+func (g GlobalCfg) planTTL(repoID string) time.Duration {
+	var planTTL time.Duration
+	for _, repo := range g.Repos {
+		if repo.IDMatches(repoID) && repo.PlanTTL != nil {
+			planTTL = *repo.PlanTTL
+		}
+	}
+	return planTTL
+}
+
+// approvalPolicy returns the approval policy of the last repo config matching
+// repoID that sets one, like planTTL.
+func (g GlobalCfg) approvalPolicy(repoID string) *ApprovalPolicy {
+	var approvalPolicy *ApprovalPolicy
+	for _, repo := range g.Repos {
+		if repo.IDMatches(repoID) && repo.ApprovalPolicy != nil {
+			approvalPolicy = repo.ApprovalPolicy
+		}
 	}
+	return approvalPolicy
 }
 
 // ValidateRepoCfg validates that rCfg for repo with id repoID is valid based
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/mohae/deepcopy"
@@ -1004,6 +1005,7 @@ repos:
 				AutoplanEnabled:    true,
 				PolicySets:         emptyPolicySets,
 				RepoLocking:        true,
+				WhenModified:       []string{".tf"},
 			},
 		},
 		"execution order group is set": {
@@ -1032,6 +1034,37 @@ repos:
 				PolicySets:          emptyPolicySets,
 				ExecutionOrderGroup: 10,
 				RepoLocking:         true,
+				WhenModified:        []string{".tf"},
+			},
+		},
+		"last server-side plan ttl wins": {
+			gCfg: `
+repos:
+- id: /.*/
+  plan_ttl: 24h
+- id: github.com/owner/repo
+  plan_ttl: 2h
+- id: /github.com/.*/
+`,
+			repoID: "github.com/owner/repo",
+			proj: valid.Project{
+				Dir:       "mydir",
+				Workspace: "myworkspace",
+				Name:      String("myname"),
+			},
+			repoWorkflows: nil,
+			exp: valid.MergedProjectCfg{
+				PlanRequirements:   []string{},
+				ApplyRequirements:  []string{},
+				ImportRequirements: []string{},
+				Workflow:           defaultWorkflow,
+				RepoRelDir:         "mydir",
+				Workspace:          "myworkspace",
+				Name:               "myname",
+				AutoplanEnabled:    false,
+				PolicySets:         emptyPolicySets,
+				RepoLocking:        true,
+				PlanTTL:            2 * time.Hour,
 			},
 		},
 	}
@@ -0,0 +1,172 @@
+package valid
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// PermissionWildcard matches all users or all commands in a permission rule.
+const PermissionWildcard = "*"
+
+// Permission is a rule in the permissions section of the server-side repo
+// config. It allows users and teams to run comment commands on repos and,
+// optionally, only for some projects and workspaces.
+type Permission struct {
+	// RepoID is the ID of the repo the rule applies to. If RepoIDRegex is set
+	// this will be empty. If both are empty, the rule applies to all repos.
+	RepoID string
+	// RepoIDRegex matches the IDs of the repos the rule applies to.
+	RepoIDRegex *regexp.Regexp
+	// Projects are glob patterns matched against project names. If empty,
+	// the rule applies to all projects.
+	Projects []string
+	// Workspaces are glob patterns matched against workspaces. If empty, the
+	// rule applies to all workspaces.
+	Workspaces []string
+	// Users are the users the rule applies to. PermissionWildcard matches
+	// all users.
+	Users []string
+	// Teams are the teams or groups the rule applies to, named like
+	// GetTeamNamesForUser names them, ex. GitHub team names or slugs and
+	// GitLab full group paths like acme/platform.
+	Teams []string
+	// Commands are the names of the commands the rule allows, ex. plan.
+	// PermissionWildcard allows all commands.
+	Commands []string
+}
+
+// Permissions are the rules for who can run which commands. If there are no
+// rules, everyone can run every command.
+type Permissions []Permission
+
+// PermissionRequest is a command that someone wants to run.
+type PermissionRequest struct {
+	RepoID string
+	User   string
+	// Teams are the teams or groups that User is a member of.
+	Teams   []string
+	Command string
+	// Project is the project the command is limited to or "" if it isn't
+	// limited to one.
+	Project string
+	// Workspace is the workspace the command is limited to or "" if it isn't
+	// known, ex. because the command selects a project.
+	Workspace string
+}
+
+// Allows returns true if any of the rules allows req.
+func (p Permissions) Allows(req PermissionRequest) bool {
+	for _, rule := range p.RulesFor(req.RepoID, req.User, req.Teams) {
+		if rule.AllowsCommand(req.Command) &&
+			matchesAny(rule.Projects, req.Project) &&
+			matchesAny(rule.Workspaces, req.Workspace) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTeamRules returns true if any of the rules apply to teams.
+func (p Permissions) HasTeamRules() bool {
+	for _, rule := range p {
+		if len(rule.Teams) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RulesFor returns the rules that apply to user, who is a member of teams,
+// in the repo with repoID.
+func (p Permissions) RulesFor(repoID string, user string, teams []string) Permissions {
+	var rules Permissions
+	for _, rule := range p {
+		if rule.matchesRepo(repoID) && rule.matchesUser(user, teams) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// String describes what the rule allows, ex. "`plan`, `apply` for projects
+// `prod-*`".
+func (p Permission) String() string {
+	var cmds []string
+	for _, c := range p.Commands {
+		if c == PermissionWildcard {
+			cmds = []string{"all commands"}
+			break
+		}
+		cmds = append(cmds, fmt.Sprintf("`%s`", c))
+	}
+	desc := strings.Join(cmds, ", ")
+	if len(p.Projects) > 0 {
+		desc += fmt.Sprintf(" for projects %s", quoteAll(p.Projects))
+	}
+	if len(p.Workspaces) > 0 {
+		desc += fmt.Sprintf(" in workspaces %s", quoteAll(p.Workspaces))
+	}
+	return desc
+}
+
+func (p Permission) matchesRepo(repoID string) bool {
+	if p.RepoIDRegex != nil {
+		return p.RepoIDRegex.MatchString(repoID)
+	}
+	return p.RepoID == "" || p.RepoID == repoID
+}
+
+func (p Permission) matchesUser(user string, teams []string) bool {
+	for _, u := range p.Users {
+		if u == PermissionWildcard || strings.EqualFold(u, user) {
+			return true
+		}
+	}
+	for _, t := range p.Teams {
+		for _, team := range teams {
+			if strings.EqualFold(t, team) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AllowsCommand returns true if the rule allows the command named cmd.
+func (p Permission) AllowsCommand(cmd string) bool {
+	for _, c := range p.Commands {
+		if c == PermissionWildcard || strings.EqualFold(c, cmd) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAny returns true if there are no patterns or value matches one of
+// them. An empty value only matches if there are no patterns.
+func matchesAny(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	if value == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		// We validate the patterns when parsing the config so we can ignore
+		// the error.
+		if match, _ := path.Match(pattern, value); match {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteAll(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("`%s`", v)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,90 @@
+package valid_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestPermissions_Allows(t *testing.T) {
+	permissions := valid.Permissions{
+		{
+			Users:    []string{"*"},
+			Commands: []string{"plan", "version"},
+		},
+		{
+			RepoIDRegex: regexp.MustCompile("github.com/acme/.*"),
+			Teams:       []string{"Platform"},
+			Commands:    []string{"*"},
+		},
+		{
+			RepoID:     "github.com/acme/infra",
+			Projects:   []string{"staging-*"},
+			Workspaces: []string{"default"},
+			Users:      []string{"alice"},
+			Commands:   []string{"apply"},
+		},
+	}
+	cases := []struct {
+		description string
+		req         valid.PermissionRequest
+		exp         bool
+	}{
+		{
+			description: "everyone can plan",
+			req:         valid.PermissionRequest{RepoID: "github.com/other/repo", User: "bob", Command: "plan"},
+			exp:         true,
+		},
+		{
+			description: "not everyone can apply",
+			req:         valid.PermissionRequest{RepoID: "github.com/acme/infra", User: "bob", Command: "apply"},
+			exp:         false,
+		},
+		{
+			description: "team can run everything in matching repos",
+			req:         valid.PermissionRequest{RepoID: "github.com/acme/infra", User: "bob", Teams: []string{"platform"}, Command: "apply"},
+			exp:         true,
+		},
+		{
+			description: "team can't run everything in other repos",
+			req:         valid.PermissionRequest{RepoID: "github.com/other/repo", User: "bob", Teams: []string{"platform"}, Command: "apply"},
+			exp:         false,
+		},
+		{
+			description: "user can apply matching project and workspace",
+			req:         valid.PermissionRequest{RepoID: "github.com/acme/infra", User: "alice", Command: "apply", Project: "staging-eu", Workspace: "default"},
+			exp:         true,
+		},
+		{
+			description: "user can't apply other projects",
+			req:         valid.PermissionRequest{RepoID: "github.com/acme/infra", User: "alice", Command: "apply", Project: "prod-eu", Workspace: "default"},
+			exp:         false,
+		},
+		{
+			description: "user can't apply without selecting a project",
+			req:         valid.PermissionRequest{RepoID: "github.com/acme/infra", User: "alice", Command: "apply", Workspace: "default"},
+			exp:         false,
+		},
+		{
+			description: "user can't apply unknown workspace",
+			req:         valid.PermissionRequest{RepoID: "github.com/acme/infra", User: "alice", Command: "apply", Project: "staging-eu"},
+			exp:         false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			Equals(t, c.exp, permissions.Allows(c.req))
+		})
+	}
+}
+
+func TestPermission_String(t *testing.T) {
+	Equals(t, "`plan`, `apply` for projects `staging-*`, `dev` in workspaces `default`", valid.Permission{
+		Projects:   []string{"staging-*", "dev"},
+		Workspaces: []string{"default"},
+		Commands:   []string{"plan", "apply"},
+	}.String())
+	Equals(t, "all commands", valid.Permission{Commands: []string{"plan", "*"}}.String())
+}
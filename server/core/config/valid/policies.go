@@ -2,6 +2,7 @@ package valid
 
 import (
 	"strings"
+	"time"
 
 	version "github.com/hashicorp/go-version"
 )
@@ -9,6 +10,8 @@ import (
 const (
 	LocalPolicySet  string = "local"
 	GithubPolicySet string = "github"
+	GitPolicySet    string = "git"
+	HTTPPolicySet   string = "http"
 )
 
 // PolicySets defines version of policy checker binary(conftest) and a list of
@@ -32,6 +35,20 @@ type PolicySet struct {
 	Name         string
 	ApproveCount int
 	Owners       PolicyOwners
+	// Ref is the git ref, ex. a tag or commit, that git policy sets are
+	// fetched at.
+	Ref string
+	// Checksum is the checksum, ex. sha256:abc123, that http policy set
+	// bundles must match.
+	Checksum string
+	// RefreshInterval is how often remote policy sets are fetched again.
+	RefreshInterval time.Duration
+}
+
+// IsRemote returns true if the policy set is fetched from a git repo or an
+// http bundle rather than read from the Atlantis server's filesystem.
+func (p PolicySet) IsRemote() bool {
+	return p.Source == GitPolicySet || p.Source == HTTPPolicySet
 }
 
 func (p *PolicySets) HasPolicies() bool {
@@ -7,6 +7,7 @@ import (
 	"log"
 	"regexp"
 	"strings"
+	"time"
 
 	version "github.com/hashicorp/go-version"
 )
@@ -134,6 +135,9 @@ type Project struct {
 	DeleteSourceBranchOnMerge *bool
 	RepoLocking               *bool
 	ExecutionOrderGroup       int
+	// DependsOn are the names of the projects that must be applied before
+	// this project can be applied.
+	DependsOn []string
 }
 
 // GetName returns the name of the project or an empty string if there is no
@@ -152,6 +156,9 @@ type Autoplan struct {
 
 type Stage struct {
 	Steps []Step
+	// Timeout is how long all of the stage's steps can take. 0 means no
+	// limit.
+	Timeout time.Duration
 }
 
 type Step struct {
@@ -165,6 +172,9 @@ type Step struct {
 	EnvVarName string
 	// EnvVarValue is the value to set EnvVarName to.
 	EnvVarValue string
+	// Timeout is how long the step can take. 0 means no limit other than
+	// the stage's timeout.
+	Timeout time.Duration
 }
 
 type Workflow struct {
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	bolt "go.etcd.io/bbolt"
@@ -18,17 +20,23 @@ import (
 
 // BoltDB is a database using BoltDB
 type BoltDB struct {
-	db                    *bolt.DB
-	locksBucketName       []byte
-	pullsBucketName       []byte
-	globalLocksBucketName []byte
+	db                     *bolt.DB
+	locksBucketName        []byte
+	pullsBucketName        []byte
+	globalLocksBucketName  []byte
+	apiJobsBucketName      []byte
+	queuedEventsBucketName []byte
+	repoDriftsBucketName   []byte
 }
 
 const (
-	locksBucketName       = "runLocks"
-	pullsBucketName       = "pulls"
-	globalLocksBucketName = "globalLocks"
-	pullKeySeparator      = "::"
+	locksBucketName        = "runLocks"
+	pullsBucketName        = "pulls"
+	globalLocksBucketName  = "globalLocks"
+	apiJobsBucketName      = "apiJobs"
+	queuedEventsBucketName = "queuedEvents"
+	repoDriftsBucketName   = "repoDrifts"
+	pullKeySeparator       = "::"
 )
 
 // New returns a valid locker. We need to be able to write to dataDir
@@ -56,6 +64,15 @@ func New(dataDir string) (*BoltDB, error) {
 		if _, err = tx.CreateBucketIfNotExists([]byte(globalLocksBucketName)); err != nil {
 			return errors.Wrapf(err, "creating bucket %q", globalLocksBucketName)
 		}
+		if _, err = tx.CreateBucketIfNotExists([]byte(apiJobsBucketName)); err != nil {
+			return errors.Wrapf(err, "creating bucket %q", apiJobsBucketName)
+		}
+		if _, err = tx.CreateBucketIfNotExists([]byte(queuedEventsBucketName)); err != nil {
+			return errors.Wrapf(err, "creating bucket %q", queuedEventsBucketName)
+		}
+		if _, err = tx.CreateBucketIfNotExists([]byte(repoDriftsBucketName)); err != nil {
+			return errors.Wrapf(err, "creating bucket %q", repoDriftsBucketName)
+		}
 		return nil
 	})
 	if err != nil {
@@ -63,20 +80,26 @@ func New(dataDir string) (*BoltDB, error) {
 	}
 	// todo: close BoltDB when server is sigtermed
 	return &BoltDB{
-		db:                    db,
-		locksBucketName:       []byte(locksBucketName),
-		pullsBucketName:       []byte(pullsBucketName),
-		globalLocksBucketName: []byte(globalLocksBucketName),
+		db:                     db,
+		locksBucketName:        []byte(locksBucketName),
+		pullsBucketName:        []byte(pullsBucketName),
+		globalLocksBucketName:  []byte(globalLocksBucketName),
+		apiJobsBucketName:      []byte(apiJobsBucketName),
+		queuedEventsBucketName: []byte(queuedEventsBucketName),
+		repoDriftsBucketName:   []byte(repoDriftsBucketName),
 	}, nil
 }
 
 // NewWithDB is used for testing.
 func NewWithDB(db *bolt.DB, bucket string, globalBucket string) (*BoltDB, error) {
 	return &BoltDB{
-		db:                    db,
-		locksBucketName:       []byte(bucket),
-		pullsBucketName:       []byte(pullsBucketName),
-		globalLocksBucketName: []byte(globalBucket),
+		db:                     db,
+		locksBucketName:        []byte(bucket),
+		pullsBucketName:        []byte(pullsBucketName),
+		globalLocksBucketName:  []byte(globalBucket),
+		apiJobsBucketName:      []byte(apiJobsBucketName),
+		queuedEventsBucketName: []byte(queuedEventsBucketName),
+		repoDriftsBucketName:   []byte(repoDriftsBucketName),
 	}, nil
 }
 
@@ -327,62 +350,7 @@ func (b *BoltDB) UpdatePullWithResults(pull models.PullRequest, newResults []com
 			return err
 		}
 
-		// If there is no pull OR if the pull we have is out of date, we
-		// just write a new pull.
-		if currStatus == nil || currStatus.Pull.HeadCommit != pull.HeadCommit {
-			var statuses []models.ProjectStatus
-			for _, r := range newResults {
-				statuses = append(statuses, b.projectResultToProject(r))
-			}
-			newStatus = models.PullStatus{
-				Pull:     pull,
-				Projects: statuses,
-			}
-		} else {
-			// If there's an existing pull at the right commit then we have to
-			// merge our project results with the existing ones. We do a merge
-			// because it's possible a user is just applying a single project
-			// in this command and so we don't want to delete our data about
-			// other projects that aren't affected by this command.
-			newStatus = *currStatus
-			for _, res := range newResults {
-				// First, check if we should update any existing projects.
-				updatedExisting := false
-				for i := range newStatus.Projects {
-					// NOTE: We're using a reference here because we are
-					// in-place updating its Status field.
-					proj := &newStatus.Projects[i]
-					if res.Workspace == proj.Workspace &&
-						res.RepoRelDir == proj.RepoRelDir &&
-						res.ProjectName == proj.ProjectName {
-
-						proj.Status = res.PlanStatus()
-
-						// Updating only policy sets which are included in results; keeping the rest.
-						if len(proj.PolicyStatus) > 0 {
-							for i, oldPolicySet := range proj.PolicyStatus {
-								for _, newPolicySet := range res.PolicyStatus() {
-									if oldPolicySet.PolicySetName == newPolicySet.PolicySetName {
-										proj.PolicyStatus[i] = newPolicySet
-									}
-								}
-							}
-						} else {
-							proj.PolicyStatus = res.PolicyStatus()
-						}
-
-						updatedExisting = true
-						break
-					}
-				}
-
-				if !updatedExisting {
-					// If we didn't update an existing project, then we need to
-					// add this because it's a new one.
-					newStatus.Projects = append(newStatus.Projects, b.projectResultToProject(res))
-				}
-			}
-		}
+		newStatus = locking.UpdatePullStatus(currStatus, pull, newResults)
 
 		// Now, we overwrite the key with our new status.
 		return b.writePullToBucket(bucket, key, newStatus)
@@ -420,6 +388,24 @@ func (b *BoltDB) DeletePullStatus(pull models.PullRequest) error {
 	return errors.Wrap(err, "DB transaction failed")
 }
 
+// ListPullStatuses returns the statuses of all pull requests.
+func (b *BoltDB) ListPullStatuses() ([]models.PullStatus, error) {
+	var statuses []models.PullStatus
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.pullsBucketName)
+		c := bucket.Cursor()
+		for k, _ := c.First(); k != nil; k, _ = c.Next() {
+			s, err := b.getPullFromBucket(bucket, k)
+			if err != nil {
+				return err
+			}
+			statuses = append(statuses, *s)
+		}
+		return nil
+	})
+	return statuses, errors.Wrap(err, "DB transaction failed")
+}
+
 // UpdateProjectStatus updates project status.
 func (b *BoltDB) UpdateProjectStatus(pull models.PullRequest, workspace string, repoRelDir string, newStatus models.ProjectPlanStatus) error {
 	key, err := b.pullKey(pull)
@@ -452,6 +438,142 @@ func (b *BoltDB) UpdateProjectStatus(pull models.PullRequest, workspace string,
 	return errors.Wrap(err, "DB transaction failed")
 }
 
+// UpdateAPIJob creates or overwrites the API job with job's ID.
+func (b *BoltDB) UpdateAPIJob(job models.APIJob) error {
+	serialized, err := json.Marshal(job)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.apiJobsBucketName)
+		return bucket.Put([]byte(job.ID), serialized)
+	})
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+// GetAPIJob returns the API job with id.
+// If there is no job, returns a nil pointer.
+func (b *BoltDB) GetAPIJob(id string) (*models.APIJob, error) {
+	var job *models.APIJob
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.apiJobsBucketName)
+		serialized := bucket.Get([]byte(id))
+		if serialized == nil {
+			return nil
+		}
+		job = &models.APIJob{}
+		return errors.Wrapf(json.Unmarshal(serialized, job), "deserializing API job at %q", id)
+	})
+	return job, errors.Wrap(err, "DB transaction failed")
+}
+
+// ListAPIJobs returns the API jobs, oldest first.
+func (b *BoltDB) ListAPIJobs() ([]models.APIJob, error) {
+	var jobs []models.APIJob
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.apiJobsBucketName)
+		return bucket.ForEach(func(k, v []byte) error {
+			var job models.APIJob
+			if err := json.Unmarshal(v, &job); err != nil {
+				return errors.Wrapf(err, "deserializing API job at %q", k)
+			}
+			jobs = append(jobs, job)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "DB transaction failed")
+	}
+	models.SortAPIJobs(jobs)
+	return jobs, nil
+}
+
+// DeleteAPIJob deletes the API job with id. It's not an error if there is no
+// such job.
+func (b *BoltDB) DeleteAPIJob(id string) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.apiJobsBucketName)
+		return bucket.Delete([]byte(id))
+	})
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+// EnqueueEvent persists event until it's deleted with DeleteQueuedEvent.
+func (b *BoltDB) EnqueueEvent(event models.QueuedEvent) error {
+	serialized, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.queuedEventsBucketName)
+		return bucket.Put([]byte(event.ID), serialized)
+	})
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+// DeleteQueuedEvent deletes the queued event with id. It's not an error if
+// there is no such event.
+func (b *BoltDB) DeleteQueuedEvent(id string) error {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.queuedEventsBucketName)
+		return bucket.Delete([]byte(id))
+	})
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+// ListQueuedEvents returns the queued events, oldest first.
+func (b *BoltDB) ListQueuedEvents() ([]models.QueuedEvent, error) {
+	var events []models.QueuedEvent
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.queuedEventsBucketName)
+		return bucket.ForEach(func(k, v []byte) error {
+			var event models.QueuedEvent
+			if err := json.Unmarshal(v, &event); err != nil {
+				return errors.Wrapf(err, "deserializing queued event at %q", k)
+			}
+			events = append(events, event)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "DB transaction failed")
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].EnqueuedAt.Before(events[j].EnqueuedAt)
+	})
+	return events, nil
+}
+
+// UpdateRepoDrift creates or overwrites the drift detection result at key.
+func (b *BoltDB) UpdateRepoDrift(key string, drift models.RepoDrift) error {
+	serialized, err := json.Marshal(drift)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.repoDriftsBucketName)
+		return bucket.Put([]byte(key), serialized)
+	})
+	return errors.Wrap(err, "DB transaction failed")
+}
+
+// ListRepoDrifts returns the drift detection results.
+func (b *BoltDB) ListRepoDrifts() ([]models.RepoDrift, error) {
+	var drifts []models.RepoDrift
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.repoDriftsBucketName)
+		return bucket.ForEach(func(k, v []byte) error {
+			var drift models.RepoDrift
+			if err := json.Unmarshal(v, &drift); err != nil {
+				return errors.Wrapf(err, "deserializing drift at %q", k)
+			}
+			drifts = append(drifts, drift)
+			return nil
+		})
+	})
+	return drifts, errors.Wrap(err, "DB transaction failed")
+}
+
 func (b *BoltDB) pullKey(pull models.PullRequest) ([]byte, error) {
 	hostname := pull.BaseRepo.VCSHost.Hostname
 	if strings.Contains(hostname, pullKeySeparator) {
@@ -494,13 +616,3 @@ func (b *BoltDB) writePullToBucket(bucket *bolt.Bucket, key []byte, pull models.
 	}
 	return bucket.Put(key, serialized)
 }
-
-func (b *BoltDB) projectResultToProject(p command.ProjectResult) models.ProjectStatus {
-	return models.ProjectStatus{
-		Workspace:    p.Workspace,
-		RepoRelDir:   p.RepoRelDir,
-		ProjectName:  p.ProjectName,
-		PolicyStatus: p.PolicyStatus(),
-		Status:       p.PlanStatus(),
-	}
-}
@@ -15,6 +15,7 @@ package db_test
 
 import (
 	"os"
+	"sort"
 	"testing"
 	"time"
 
@@ -748,9 +749,10 @@ func TestPullStatus_UpdateMerge_Apply(t *testing.T) {
 				Status:      models.ErroredApplyStatus,
 			},
 			{
-				RepoRelDir: "staythesame",
-				Workspace:  "default",
-				Status:     models.PlannedPlanStatus,
+				RepoRelDir:  "staythesame",
+				Workspace:   "default",
+				Status:      models.PlannedPlanStatus,
+				PlanHistory: []models.PlanRecord{{}},
 			},
 			{
 				RepoRelDir: "newresult",
@@ -875,6 +877,291 @@ func TestPullStatus_UpdateMerge_ApprovePolicies(t *testing.T) {
 	}
 }
 
+func TestPullStatus_UpdateMerge_ApproveDestroy(t *testing.T) {
+	b := newTestDB2(t)
+
+	pull := models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		URL:        "url",
+		HeadBranch: "head",
+		BaseBranch: "base",
+		Author:     "lkysow",
+		State:      models.OpenPullState,
+		BaseRepo: models.Repo{
+			FullName:          "runatlantis/atlantis",
+			Owner:             "runatlantis",
+			Name:              "atlantis",
+			CloneURL:          "clone-url",
+			SanitizedCloneURL: "clone-url",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+	_, err := b.UpdatePullWithResults(
+		pull,
+		[]command.ProjectResult{
+			{
+				Command:     command.Plan,
+				RepoRelDir:  "approveme",
+				Workspace:   "default",
+				PlanSuccess: &models.PlanSuccess{},
+			},
+			{
+				Command:     command.Plan,
+				RepoRelDir:  "staythesame",
+				Workspace:   "default",
+				PlanSuccess: &models.PlanSuccess{},
+			},
+		})
+	Ok(t, err)
+
+	updateStatus, err := b.UpdatePullWithResults(pull,
+		[]command.ProjectResult{
+			{
+				Command:    command.ApproveDestroy,
+				RepoRelDir: "approveme",
+				Workspace:  "default",
+				ApproveDestroySuccess: &models.ApproveDestroySuccess{
+					ApprovedBy: "owner",
+				},
+			},
+			{
+				Command:    command.ApproveDestroy,
+				RepoRelDir: "staythesame",
+				Workspace:  "default",
+				Error:      errors.New("not an owner"),
+			},
+		})
+	Ok(t, err)
+
+	getStatus, err := b.GetPullStatus(pull)
+	Ok(t, err)
+
+	for _, s := range []models.PullStatus{updateStatus, *getStatus} {
+		Equals(t, []models.ProjectStatus{
+			{
+				RepoRelDir:        "approveme",
+				Workspace:         "default",
+				Status:            models.PlannedPlanStatus,
+				DestroyApprovedBy: "owner",
+				PlanHistory:       []models.PlanRecord{{}},
+			},
+			{
+				RepoRelDir:  "staythesame",
+				Workspace:   "default",
+				Status:      models.PlannedPlanStatus,
+				PlanHistory: []models.PlanRecord{{}},
+			},
+		}, s.Projects)
+	}
+
+	// A new plan must be approved again.
+	updateStatus, err = b.UpdatePullWithResults(pull,
+		[]command.ProjectResult{
+			{
+				Command:     command.Plan,
+				RepoRelDir:  "approveme",
+				Workspace:   "default",
+				PlanSuccess: &models.PlanSuccess{},
+			},
+		})
+	Ok(t, err)
+	Equals(t, "", updateStatus.Projects[0].DestroyApprovedBy)
+}
+
+// Test that plans are kept in the project's plan history, including plans
+// of earlier commits.
+func TestPullStatus_PlanHistory(t *testing.T) {
+	b := newTestDB2(t)
+
+	pull := models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		State:      models.OpenPullState,
+		BaseRepo: models.Repo{
+			FullName: "runatlantis/atlantis",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+	firstPlan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := b.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:     command.Plan,
+			RepoRelDir:  ".",
+			Workspace:   "default",
+			User:        "alice",
+			JobID:       "job1",
+			PlanSuccess: &models.PlanSuccess{PlannedAt: firstPlan},
+		},
+	})
+	Ok(t, err)
+
+	pull.HeadCommit = "newsha"
+	secondPlan := firstPlan.Add(time.Hour)
+	status, err := b.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:    command.Plan,
+			RepoRelDir: ".",
+			Workspace:  "default",
+			User:       "bob",
+			JobID:      "job2",
+			PlanSuccess: &models.PlanSuccess{
+				PlannedAt: secondPlan,
+				Analysis:  &models.PlanAnalysis{ResourceChanges: []models.ResourceChange{{Address: "null_resource.a", Action: models.CreateResourceAction}}},
+			},
+		},
+	})
+	Ok(t, err)
+
+	// Errored plans aren't kept.
+	_, err = b.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:    command.Plan,
+			RepoRelDir: ".",
+			Workspace:  "default",
+			User:       "carol",
+			JobID:      "job3",
+			Error:      errors.New("plan failed"),
+		},
+	})
+	Ok(t, err)
+
+	expHistory := []models.PlanRecord{
+		{JobID: "job1", User: "alice", PlannedAt: firstPlan},
+		{JobID: "job2", User: "bob", PlannedAt: secondPlan, Stats: models.PlanSuccessStats{Add: 1, Changes: true}},
+	}
+	Equals(t, expHistory, status.Projects[0].PlanHistory)
+
+	statuses, err := b.ListPullStatuses()
+	Ok(t, err)
+	Equals(t, 1, len(statuses))
+	Equals(t, expHistory, statuses[0].Projects[0].PlanHistory)
+	Equals(t, "job3", statuses[0].Projects[0].JobID)
+	Equals(t, models.ErroredPlanStatus, statuses[0].Projects[0].Status)
+}
+
+func TestPullStatus_List(t *testing.T) {
+	b := newTestDB2(t)
+
+	statuses, err := b.ListPullStatuses()
+	Ok(t, err)
+	Equals(t, 0, len(statuses))
+
+	for _, num := range []int{1, 2} {
+		_, err = b.UpdatePullWithResults(models.PullRequest{
+			Num:      num,
+			BaseRepo: models.Repo{FullName: "runatlantis/atlantis", VCSHost: models.VCSHost{Hostname: "github.com"}},
+		}, []command.ProjectResult{{Command: command.Plan, RepoRelDir: ".", Workspace: "default", Failure: "failure"}})
+		Ok(t, err)
+	}
+	// Locks aren't pull statuses.
+	_, _, err = b.TryLock(lock)
+	Ok(t, err)
+
+	statuses, err = b.ListPullStatuses()
+	Ok(t, err)
+	var nums []int
+	for _, s := range statuses {
+		nums = append(nums, s.Pull.Num)
+	}
+	sort.Ints(nums)
+	Equals(t, []int{1, 2}, nums)
+}
+
+func TestAPIJob_UpdateGetListDelete(t *testing.T) {
+	b := newTestDB2(t)
+
+	job := models.APIJob{
+		ID:            "id",
+		Command:       "plan",
+		Status:        models.SucceededAPIJobStatus,
+		ProjectJobIDs: []string{"job1", "job2"},
+		Result:        []byte(`{"PlansDeleted":false}`),
+		CreatedAt:     time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt:     time.Date(2023, 1, 2, 3, 4, 6, 0, time.UTC),
+	}
+	Ok(t, b.UpdateAPIJob(job))
+
+	got, err := b.GetAPIJob("id")
+	Ok(t, err)
+	Equals(t, &job, got)
+
+	got, err = b.GetAPIJob("missing")
+	Ok(t, err)
+	Assert(t, got == nil, "exp nil job but got %v", got)
+
+	older := models.APIJob{ID: "older", Status: models.RunningAPIJobStatus, CreatedAt: job.CreatedAt.Add(-time.Hour)}
+	Ok(t, b.UpdateAPIJob(older))
+	jobs, err := b.ListAPIJobs()
+	Ok(t, err)
+	Equals(t, []models.APIJob{older, job}, jobs)
+
+	Ok(t, b.DeleteAPIJob("older"))
+	Ok(t, b.DeleteAPIJob("missing"))
+	jobs, err = b.ListAPIJobs()
+	Ok(t, err)
+	Equals(t, []models.APIJob{job}, jobs)
+}
+
+func TestRepoDrifts(t *testing.T) {
+	b := newTestDB2(t)
+	drift := models.RepoDrift{
+		Repository: "owner/repo",
+		VCSHost:    "github.com",
+		Branch:     "main",
+		Time:       time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		Projects:   []models.ProjectDrift{{RepoRelDir: ".", Workspace: "default", Drifted: true}},
+	}
+	Ok(t, b.UpdateRepoDrift("Github/owner/repo", models.RepoDrift{Repository: "owner/repo", Error: "old"}))
+	Ok(t, b.UpdateRepoDrift("Github/owner/repo", drift))
+
+	drifts, err := b.ListRepoDrifts()
+	Ok(t, err)
+	Equals(t, []models.RepoDrift{drift}, drifts)
+}
+
+func TestQueuedEvents(t *testing.T) {
+	b := newTestDB2(t)
+
+	first := models.QueuedEvent{
+		ID:         "first",
+		Kind:       models.AutoplanQueuedEvent,
+		BaseRepo:   models.Repo{FullName: "owner/repo"},
+		HeadRepo:   &models.Repo{FullName: "owner/repo"},
+		Pull:       &models.PullRequest{Num: 1},
+		PullNum:    1,
+		User:       models.User{Username: "user"},
+		EnqueuedAt: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	second := models.QueuedEvent{
+		ID:         "second",
+		Kind:       models.CommentQueuedEvent,
+		BaseRepo:   models.Repo{FullName: "owner/repo"},
+		PullNum:    1,
+		User:       models.User{Username: "user"},
+		Command:    []byte(`{"Name":1}`),
+		EnqueuedAt: time.Date(2023, 1, 2, 3, 4, 6, 0, time.UTC),
+	}
+	Ok(t, b.EnqueueEvent(second))
+	Ok(t, b.EnqueueEvent(first))
+
+	events, err := b.ListQueuedEvents()
+	Ok(t, err)
+	Equals(t, []models.QueuedEvent{first, second}, events)
+
+	Ok(t, b.DeleteQueuedEvent("first"))
+	Ok(t, b.DeleteQueuedEvent("missing"))
+	events, err = b.ListQueuedEvents()
+	Ok(t, err)
+	Equals(t, []models.QueuedEvent{second}, events)
+}
+
 // newTestDB returns a TestDB using a temporary path.
 func newTestDB() (*bolt.DB, *db.BoltDB) {
 	// Retrieve a temporary path.
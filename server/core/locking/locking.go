@@ -37,12 +37,18 @@ type Backend interface {
 	UnlockByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error)
 	UpdateProjectStatus(pull models.PullRequest, workspace string, repoRelDir string, newStatus models.ProjectPlanStatus) error
 	GetPullStatus(pull models.PullRequest) (*models.PullStatus, error)
+	ListPullStatuses() ([]models.PullStatus, error)
 	DeletePullStatus(pull models.PullRequest) error
 	UpdatePullWithResults(pull models.PullRequest, newResults []command.ProjectResult) (models.PullStatus, error)
 
 	LockCommand(cmdName command.Name, lockTime time.Time) (*command.Lock, error)
 	UnlockCommand(cmdName command.Name) error
 	CheckCommandLock(cmdName command.Name) (*command.Lock, error)
+
+	UpdateAPIJob(job models.APIJob) error
+	GetAPIJob(id string) (*models.APIJob, error)
+	ListAPIJobs() ([]models.APIJob, error)
+	DeleteAPIJob(id string) error
 }
 
 // TryLockResponse results from an attempted lock.
@@ -45,6 +45,21 @@ func (mock *MockBackend) CheckCommandLock(cmdName command.Name) (*command.Lock,
 	return ret0, ret1
 }
 
+func (mock *MockBackend) DeleteAPIJob(id string) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockBackend().")
+	}
+	params := []pegomock.Param{id}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("DeleteAPIJob", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockBackend) DeletePullStatus(pull models.PullRequest) error {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockBackend().")
@@ -60,6 +75,25 @@ func (mock *MockBackend) DeletePullStatus(pull models.PullRequest) error {
 	return ret0
 }
 
+func (mock *MockBackend) GetAPIJob(id string) (*models.APIJob, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockBackend().")
+	}
+	params := []pegomock.Param{id}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("GetAPIJob", params, []reflect.Type{reflect.TypeOf((**models.APIJob)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 *models.APIJob
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(*models.APIJob)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
 func (mock *MockBackend) GetLock(project models.Project, workspace string) (*models.ProjectLock, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockBackend().")
@@ -117,6 +151,44 @@ func (mock *MockBackend) List() ([]models.ProjectLock, error) {
 	return ret0, ret1
 }
 
+func (mock *MockBackend) ListAPIJobs() ([]models.APIJob, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockBackend().")
+	}
+	params := []pegomock.Param{}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("ListAPIJobs", params, []reflect.Type{reflect.TypeOf((*[]models.APIJob)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 []models.APIJob
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].([]models.APIJob)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
+func (mock *MockBackend) ListPullStatuses() ([]models.PullStatus, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockBackend().")
+	}
+	params := []pegomock.Param{}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("ListPullStatuses", params, []reflect.Type{reflect.TypeOf((*[]models.PullStatus)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 []models.PullStatus
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].([]models.PullStatus)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
 func (mock *MockBackend) LockCommand(cmdName command.Name, lockTime time.Time) (*command.Lock, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockBackend().")
@@ -212,6 +284,21 @@ func (mock *MockBackend) UnlockCommand(cmdName command.Name) error {
 	return ret0
 }
 
+func (mock *MockBackend) UpdateAPIJob(job models.APIJob) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockBackend().")
+	}
+	params := []pegomock.Param{job}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("UpdateAPIJob", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockBackend) UpdateProjectStatus(pull models.PullRequest, workspace string, repoRelDir string, newStatus models.ProjectPlanStatus) error {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockBackend().")
@@ -310,6 +397,33 @@ func (c *MockBackend_CheckCommandLock_OngoingVerification) GetAllCapturedArgumen
 	return
 }
 
+func (verifier *VerifierMockBackend) DeleteAPIJob(id string) *MockBackend_DeleteAPIJob_OngoingVerification {
+	params := []pegomock.Param{id}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "DeleteAPIJob", params, verifier.timeout)
+	return &MockBackend_DeleteAPIJob_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockBackend_DeleteAPIJob_OngoingVerification struct {
+	mock              *MockBackend
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockBackend_DeleteAPIJob_OngoingVerification) GetCapturedArguments() string {
+	id := c.GetAllCapturedArguments()
+	return id[len(id)-1]
+}
+
+func (c *MockBackend_DeleteAPIJob_OngoingVerification) GetAllCapturedArguments() (_param0 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockBackend) DeletePullStatus(pull models.PullRequest) *MockBackend_DeletePullStatus_OngoingVerification {
 	params := []pegomock.Param{pull}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "DeletePullStatus", params, verifier.timeout)
@@ -337,6 +451,33 @@ func (c *MockBackend_DeletePullStatus_OngoingVerification) GetAllCapturedArgumen
 	return
 }
 
+func (verifier *VerifierMockBackend) GetAPIJob(id string) *MockBackend_GetAPIJob_OngoingVerification {
+	params := []pegomock.Param{id}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "GetAPIJob", params, verifier.timeout)
+	return &MockBackend_GetAPIJob_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockBackend_GetAPIJob_OngoingVerification struct {
+	mock              *MockBackend
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockBackend_GetAPIJob_OngoingVerification) GetCapturedArguments() string {
+	id := c.GetAllCapturedArguments()
+	return id[len(id)-1]
+}
+
+func (c *MockBackend_GetAPIJob_OngoingVerification) GetAllCapturedArguments() (_param0 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockBackend) GetLock(project models.Project, workspace string) *MockBackend_GetLock_OngoingVerification {
 	params := []pegomock.Param{project, workspace}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "GetLock", params, verifier.timeout)
@@ -412,6 +553,40 @@ func (c *MockBackend_List_OngoingVerification) GetCapturedArguments() {
 func (c *MockBackend_List_OngoingVerification) GetAllCapturedArguments() {
 }
 
+func (verifier *VerifierMockBackend) ListAPIJobs() *MockBackend_ListAPIJobs_OngoingVerification {
+	params := []pegomock.Param{}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ListAPIJobs", params, verifier.timeout)
+	return &MockBackend_ListAPIJobs_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockBackend_ListAPIJobs_OngoingVerification struct {
+	mock              *MockBackend
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockBackend_ListAPIJobs_OngoingVerification) GetCapturedArguments() {
+}
+
+func (c *MockBackend_ListAPIJobs_OngoingVerification) GetAllCapturedArguments() {
+}
+
+func (verifier *VerifierMockBackend) ListPullStatuses() *MockBackend_ListPullStatuses_OngoingVerification {
+	params := []pegomock.Param{}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ListPullStatuses", params, verifier.timeout)
+	return &MockBackend_ListPullStatuses_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockBackend_ListPullStatuses_OngoingVerification struct {
+	mock              *MockBackend
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockBackend_ListPullStatuses_OngoingVerification) GetCapturedArguments() {
+}
+
+func (c *MockBackend_ListPullStatuses_OngoingVerification) GetAllCapturedArguments() {
+}
+
 func (verifier *VerifierMockBackend) LockCommand(cmdName command.Name, lockTime time.Time) *MockBackend_LockCommand_OngoingVerification {
 	params := []pegomock.Param{cmdName, lockTime}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "LockCommand", params, verifier.timeout)
@@ -559,6 +734,33 @@ func (c *MockBackend_UnlockCommand_OngoingVerification) GetAllCapturedArguments(
 	return
 }
 
+func (verifier *VerifierMockBackend) UpdateAPIJob(job models.APIJob) *MockBackend_UpdateAPIJob_OngoingVerification {
+	params := []pegomock.Param{job}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UpdateAPIJob", params, verifier.timeout)
+	return &MockBackend_UpdateAPIJob_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockBackend_UpdateAPIJob_OngoingVerification struct {
+	mock              *MockBackend
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockBackend_UpdateAPIJob_OngoingVerification) GetCapturedArguments() models.APIJob {
+	job := c.GetAllCapturedArguments()
+	return job[len(job)-1]
+}
+
+func (c *MockBackend_UpdateAPIJob_OngoingVerification) GetAllCapturedArguments() (_param0 []models.APIJob) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]models.APIJob, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(models.APIJob)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockBackend) UpdateProjectStatus(pull models.PullRequest, workspace string, repoRelDir string, newStatus models.ProjectPlanStatus) *MockBackend_UpdateProjectStatus_OngoingVerification {
 	params := []pegomock.Param{pull, workspace, repoRelDir, newStatus}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UpdateProjectStatus", params, verifier.timeout)
@@ -0,0 +1,113 @@
+package locking
+
+import (
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// UpdatePullStatus returns pull's status after newResults are applied to
+// currStatus, which is nil if the pull has no status yet. Backends call it
+// from UpdatePullWithResults so they all update statuses the same way.
+func UpdatePullStatus(currStatus *models.PullStatus, pull models.PullRequest, newResults []command.ProjectResult) models.PullStatus {
+	// If there is no pull OR if the pull we have is out of date, we
+	// just write a new pull.
+	if currStatus == nil || currStatus.Pull.HeadCommit != pull.HeadCommit {
+		var statuses []models.ProjectStatus
+		for _, res := range newResults {
+			status := projectResultToProject(res)
+			// Keep the plan history from earlier commits.
+			if currStatus != nil {
+				if prev := currStatus.FindProject(res.RepoRelDir, res.Workspace, res.ProjectName); prev != nil {
+					status.PlanHistory = models.AppendPlans(prev.PlanHistory, status.PlanHistory...)
+				}
+			}
+			statuses = append(statuses, status)
+		}
+		return models.PullStatus{
+			Pull:     pull,
+			Projects: statuses,
+		}
+	}
+
+	// If there's an existing pull at the right commit then we have to
+	// merge our project results with the existing ones. We do a merge
+	// because it's possible a user is just applying a single project
+	// in this command and so we don't want to delete our data about
+	// other projects that aren't affected by this command.
+	newStatus := *currStatus
+	for _, res := range newResults {
+		// First, check if we should update any existing projects.
+		updatedExisting := false
+		for i := range newStatus.Projects {
+			// NOTE: We're using a reference here because we are
+			// in-place updating its Status field.
+			proj := &newStatus.Projects[i]
+			if res.Workspace == proj.Workspace &&
+				res.RepoRelDir == proj.RepoRelDir &&
+				res.ProjectName == proj.ProjectName {
+
+				// Approving a destroy doesn't change where the project is at
+				// in the planning cycle.
+				if res.Command == command.ApproveDestroy {
+					if approvedBy := res.DestroyApprovedBy(); approvedBy != "" {
+						proj.DestroyApprovedBy = approvedBy
+					}
+					updatedExisting = true
+					break
+				}
+
+				proj.Status = res.PlanStatus()
+				if res.JobID != "" {
+					proj.JobID = res.JobID
+				}
+				// A new plan needs to be approved again.
+				if res.Command == command.Plan {
+					proj.DestroyApprovedBy = ""
+					proj.PlannedAt = res.PlannedAt()
+					proj.Targets = res.Targets()
+					proj.Replaces = res.Replaces()
+					proj.PlanHistory = models.AppendPlans(proj.PlanHistory, res.PlanRecords()...)
+				}
+
+				// Updating only policy sets which are included in results; keeping the rest.
+				if len(proj.PolicyStatus) > 0 {
+					for i, oldPolicySet := range proj.PolicyStatus {
+						for _, newPolicySet := range res.PolicyStatus() {
+							if oldPolicySet.PolicySetName == newPolicySet.PolicySetName {
+								proj.PolicyStatus[i] = newPolicySet
+							}
+						}
+					}
+				} else {
+					proj.PolicyStatus = res.PolicyStatus()
+				}
+
+				updatedExisting = true
+				break
+			}
+		}
+
+		if !updatedExisting {
+			// If we didn't update an existing project, then we need to
+			// add this because it's a new one.
+			newStatus.Projects = append(newStatus.Projects, projectResultToProject(res))
+		}
+	}
+	return newStatus
+}
+
+func projectResultToProject(res command.ProjectResult) models.ProjectStatus {
+	return models.ProjectStatus{
+		Workspace:         res.Workspace,
+		RepoRelDir:        res.RepoRelDir,
+		ProjectName:       res.ProjectName,
+		PolicyStatus:      res.PolicyStatus(),
+		Status:            res.PlanStatus(),
+		DestroyApprovedBy: res.DestroyApprovedBy(),
+		PlannedAt:         res.PlannedAt(),
+		Targets:           res.Targets(),
+		Replaces:          res.Replaces(),
+		PlanHistory:       res.PlanRecords(),
+		JobID:             res.JobID,
+	}
+}
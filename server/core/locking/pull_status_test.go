@@ -0,0 +1,46 @@
+package locking_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestUpdatePullStatus(t *testing.T) {
+	pull := models.PullRequest{Num: 1, HeadCommit: "sha"}
+	planned := command.ProjectResult{
+		Command:     command.Plan,
+		RepoRelDir:  "dir1",
+		Workspace:   "default",
+		PlanSuccess: &models.PlanSuccess{TerraformOutput: "tf-output"},
+	}
+
+	t.Log("a pull without a status gets one project per result")
+	status := locking.UpdatePullStatus(nil, pull, []command.ProjectResult{planned})
+	Equals(t, pull, status.Pull)
+	Equals(t, 1, len(status.Projects))
+	Equals(t, models.PlannedPlanStatus, status.Projects[0].Status)
+
+	t.Log("results at the same commit are merged with the other projects")
+	applied := command.ProjectResult{
+		Command:      command.Apply,
+		RepoRelDir:   "dir2",
+		Workspace:    "default",
+		ApplySuccess: "success!",
+	}
+	status = locking.UpdatePullStatus(&status, pull, []command.ProjectResult{applied})
+	Equals(t, 2, len(status.Projects))
+	Equals(t, models.PlannedPlanStatus, status.Projects[0].Status)
+	Equals(t, models.AppliedPlanStatus, status.Projects[1].Status)
+
+	t.Log("results at a new commit replace the projects")
+	newPull := pull
+	newPull.HeadCommit = "new-sha"
+	status = locking.UpdatePullStatus(&status, newPull, []command.ProjectResult{applied})
+	Equals(t, newPull, status.Pull)
+	Equals(t, 1, len(status.Projects))
+	Equals(t, "dir2", status.Projects[0].RepoRelDir)
+}
@@ -0,0 +1,123 @@
+package planstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// metadataSuffix is the suffix of the files holding the metadata of the plan
+// file of the same name.
+const metadataSuffix = ".metadata.json"
+
+// FileStore stores plans in a directory, ex. a volume shared by the Atlantis
+// servers.
+type FileStore struct {
+	Dir string
+}
+
+func (s *FileStore) Put(key string, plan Plan, contents []byte) error {
+	planPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(planPath), 0700); err != nil {
+		return errors.Wrap(err, "creating plan dir")
+	}
+	metadata, err := json.Marshal(plan)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(planPath+metadataSuffix, metadata); err != nil {
+		return err
+	}
+	return writeFile(planPath, contents)
+}
+
+func (s *FileStore) Get(key string) (Plan, []byte, error) {
+	planPath, err := s.path(key)
+	if err != nil {
+		return Plan{}, nil, err
+	}
+	contents, err := os.ReadFile(planPath)
+	if os.IsNotExist(err) {
+		return Plan{}, nil, ErrNotFound
+	}
+	if err != nil {
+		return Plan{}, nil, errors.Wrap(err, "reading plan")
+	}
+	metadata, err := os.ReadFile(planPath + metadataSuffix)
+	if err != nil {
+		return Plan{}, nil, errors.Wrap(err, "reading plan metadata")
+	}
+	var plan Plan
+	if err := json.Unmarshal(metadata, &plan); err != nil {
+		return Plan{}, nil, errors.Wrap(err, "parsing plan metadata")
+	}
+	return plan, contents, nil
+}
+
+func (s *FileStore) List(prefix string) ([]string, error) {
+	// Only walk the deepest dir that all the keys with prefix are in.
+	root := filepath.Join(s.Dir, filepath.FromSlash(path.Dir(prefix+"_")))
+	var keys []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasSuffix(p, metadataSuffix) || strings.HasSuffix(p, tmpSuffix) {
+			return nil
+		}
+		rel, err := filepath.Rel(s.Dir, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	return keys, errors.Wrap(err, "listing plans")
+}
+
+func (s *FileStore) Delete(key string) error {
+	planPath, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	for _, p := range []string{planPath, planPath + metadataSuffix} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// path returns the path of the plan at key. Keys can't point outside of the
+// store's dir.
+func (s *FileStore) path(key string) (string, error) {
+	if key == "" || path.IsAbs(key) || path.Clean(key) != key || strings.HasPrefix(key, "../") {
+		return "", fmt.Errorf("invalid plan key %q", key)
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(key)), nil
+}
+
+const tmpSuffix = ".tmp"
+
+// writeFile writes contents to a temporary file and then renames it to p so
+// that readers never see a partially written file.
+func writeFile(p string, contents []byte) error {
+	if err := os.WriteFile(p+tmpSuffix, contents, 0600); err != nil {
+		return errors.Wrapf(err, "writing %s", p)
+	}
+	return errors.Wrapf(os.Rename(p+tmpSuffix, p), "writing %s", p)
+}
@@ -0,0 +1,96 @@
+// Package planstore stores plan files outside of Atlantis' data dir so that
+// they outlive it and any Atlantis server can apply them.
+package planstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotFound is returned by Get when there's no plan at the key.
+var ErrNotFound = errors.New("plan not found")
+
+// Plan is the metadata of a plan file in a Store.
+type Plan struct {
+	// HeadCommit is the commit of the pull request the plan was made on.
+	HeadCommit string `json:"head_commit"`
+	// Checksum is the hex-encoded SHA-256 checksum of the plan file.
+	Checksum string `json:"checksum"`
+}
+
+// NewPlan returns the metadata of the plan file contents made on headCommit.
+func NewPlan(headCommit string, contents []byte) Plan {
+	return Plan{
+		HeadCommit: headCommit,
+		Checksum:   checksum(contents),
+	}
+}
+
+// Verify returns an error if contents isn't the plan file p was created for
+// or if p wasn't made on headCommit.
+func (p Plan) Verify(headCommit string, contents []byte) error {
+	if actual := checksum(contents); actual != p.Checksum {
+		return fmt.Errorf("plan file is corrupted: its checksum is %s, expected %s", actual, p.Checksum)
+	}
+	if p.HeadCommit != headCommit {
+		return fmt.Errorf("plan was made on commit %s but the pull request's head commit is %s–run plan again", p.HeadCommit, headCommit)
+	}
+	return nil
+}
+
+func checksum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
+
+// Store stores plan files by key. Keys are slash-separated paths laid out
+// like the working dirs the plans were made in, see Key.
+type Store interface {
+	// Put stores contents at key, replacing any plan already there.
+	Put(key string, plan Plan, contents []byte) error
+	// Get returns the plan at key and its contents. It returns ErrNotFound if
+	// there's no plan at key.
+	Get(key string) (Plan, []byte, error)
+	// List returns the keys of the plans whose keys start with prefix.
+	List(prefix string) ([]string, error)
+	// Delete deletes the plan at key. It's not an error if there's no plan at
+	// key.
+	Delete(key string) error
+}
+
+// Key returns the key of the plan file planFilename of the project at
+// repoRelDir, planned in workspace of a pull request.
+func Key(repoFullName string, pullNum int, workspace string, repoRelDir string, planFilename string) string {
+	return path.Join(WorkspacePrefix(repoFullName, pullNum, workspace), filepath.ToSlash(repoRelDir), planFilename)
+}
+
+// PullPrefix returns the prefix of the keys of a pull request's plans.
+func PullPrefix(repoFullName string, pullNum int) string {
+	return path.Join(repoFullName, strconv.Itoa(pullNum)) + "/"
+}
+
+// WorkspacePrefix returns the prefix of the keys of the plans made in a
+// workspace of a pull request.
+func WorkspacePrefix(repoFullName string, pullNum int, workspace string) string {
+	return PullPrefix(repoFullName, pullNum) + workspace + "/"
+}
+
+// DeleteAll deletes the plans whose keys start with prefix.
+func DeleteAll(store Store, prefix string) error {
+	keys, err := store.List(prefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := store.Delete(key); err != nil {
+			return errors.Wrapf(err, "deleting %s", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package planstore_test
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/planstore"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestKey(t *testing.T) {
+	Equals(t, "owner/repo/1/default/dir/sub/project-default.tfplan", planstore.Key("owner/repo", 1, "default", "dir/sub", "project-default.tfplan"))
+	Equals(t, "owner/repo/1/staging/default.tfplan", planstore.Key("owner/repo", 1, "staging", ".", "default.tfplan"))
+	Equals(t, "owner/repo/1/", planstore.PullPrefix("owner/repo", 1))
+	Equals(t, "owner/repo/1/staging/", planstore.WorkspacePrefix("owner/repo", 1, "staging"))
+}
+
+func TestPlan_Verify(t *testing.T) {
+	contents := []byte("plan")
+	plan := planstore.NewPlan("abc123", contents)
+	Ok(t, plan.Verify("abc123", contents))
+	ErrContains(t, "plan file is corrupted", plan.Verify("abc123", []byte("other")))
+	ErrEquals(t, "plan was made on commit abc123 but the pull request's head commit is def456–run plan again", plan.Verify("def456", contents))
+}
+
+func TestFileStore(t *testing.T) {
+	testStore(t, &planstore.FileStore{Dir: t.TempDir()})
+}
+
+func TestFileStore_InvalidKey(t *testing.T) {
+	s := &planstore.FileStore{Dir: t.TempDir()}
+	for _, key := range []string{"", "/abs", "../outside", "a/../../outside"} {
+		ErrEquals(t, `invalid plan key "`+key+`"`, s.Put(key, planstore.Plan{}, nil))
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	s3 := newFakeS3(t)
+	store, err := planstore.NewS3Store(planstore.S3Config{
+		Bucket:          "plans",
+		Prefix:          "atlantis/",
+		Region:          "us-east-1",
+		Endpoint:        s3.URL,
+		UsePathStyle:    true,
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	})
+	Ok(t, err)
+	testStore(t, store)
+
+	// Keys are stored under the prefix.
+	for key := range s3.objects {
+		Assert(t, strings.HasPrefix(key, "atlantis/"), "exp %s to have prefix", key)
+	}
+}
+
+func TestS3Store_DefaultCredentials(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "token")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	s3 := newFakeS3(t)
+	store, err := planstore.NewS3Store(planstore.S3Config{
+		Bucket:       "plans",
+		Endpoint:     s3.URL,
+		UsePathStyle: true,
+	})
+	Ok(t, err)
+
+	contents := []byte("plan contents")
+	Ok(t, store.Put("owner/repo/1/default/default.tfplan", planstore.NewPlan("abc123", contents), contents))
+	Equals(t, "token", s3.sessionToken)
+}
+
+func TestS3Store_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>") // nolint: errcheck
+	}))
+	defer server.Close()
+	store, err := planstore.NewS3Store(planstore.S3Config{
+		Bucket:          "plans",
+		Region:          "us-east-1",
+		Endpoint:        server.URL,
+		UsePathStyle:    true,
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+	})
+	Ok(t, err)
+	_, _, err = store.Get("owner/repo/1/default/default.tfplan")
+	ErrContains(t, "getting owner/repo/1/default/default.tfplan", err)
+	ErrContains(t, "StatusCode: 403", err)
+	ErrContains(t, "AccessDenied: Access Denied", err)
+
+	_, err = planstore.NewS3Store(planstore.S3Config{Bucket: "plans", Endpoint: "localhost:9000"})
+	ErrEquals(t, `endpoint must have http:// or https://, got "localhost:9000"`, err)
+	_, err = planstore.NewS3Store(planstore.S3Config{Bucket: "plans", AccessKeyID: "AKID"})
+	ErrEquals(t, "access key ID and secret access key must be set together", err)
+}
+
+// testStore tests the behaviour all Stores share.
+func testStore(t *testing.T, s planstore.Store) {
+	contents := []byte("plan contents")
+	plan := planstore.NewPlan("abc123", contents)
+	keys := []string{
+		"owner/repo/1/default/default.tfplan",
+		"owner/repo/1/default/dir/project-default.tfplan",
+		"owner/repo/1/staging/dir with spaces/staging.tfplan",
+		"owner/repo/10/default/default.tfplan",
+	}
+	for _, key := range keys {
+		Ok(t, s.Put(key, plan, contents))
+	}
+
+	actPlan, actContents, err := s.Get(keys[2])
+	Ok(t, err)
+	Equals(t, plan, actPlan)
+	Equals(t, contents, actContents)
+
+	_, _, err = s.Get("owner/repo/2/default/default.tfplan")
+	Equals(t, planstore.ErrNotFound, err)
+
+	listed, err := s.List(planstore.PullPrefix("owner/repo", 1))
+	Ok(t, err)
+	sort.Strings(listed)
+	Equals(t, keys[:3], listed)
+
+	// Putting a plan again replaces it.
+	newContents := []byte("new plan")
+	Ok(t, s.Put(keys[0], planstore.NewPlan("def456", newContents), newContents))
+	actPlan, actContents, err = s.Get(keys[0])
+	Ok(t, err)
+	Equals(t, "def456", actPlan.HeadCommit)
+	Equals(t, newContents, actContents)
+
+	Ok(t, planstore.DeleteAll(s, planstore.WorkspacePrefix("owner/repo", 1, "default")))
+	Ok(t, s.Delete(keys[0]))
+	listed, err = s.List("owner/repo/")
+	Ok(t, err)
+	sort.Strings(listed)
+	Equals(t, []string{keys[2], keys[3]}, listed)
+}
+
+// fakeS3 is an in-memory S3-compatible service.
+type fakeS3 struct {
+	*httptest.Server
+	mu      sync.Mutex
+	objects map[string]fakeObject
+	// sessionToken is the session token of the last request.
+	sessionToken string
+}
+
+type fakeObject struct {
+	contents []byte
+	header   http.Header
+}
+
+func newFakeS3(t *testing.T) *fakeS3 {
+	s := &fakeS3{objects: make(map[string]fakeObject)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeS3) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	s.sessionToken = r.Header.Get("X-Amz-Security-Token")
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != "plans" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && key == "":
+		s.list(w, r)
+	case r.Method == http.MethodPut:
+		contents, _ := io.ReadAll(r.Body)
+		header := http.Header{}
+		for name, values := range r.Header {
+			if strings.HasPrefix(name, "X-Amz-Meta-") {
+				header[name] = values
+			}
+		}
+		s.objects[key] = fakeObject{contents: contents, header: header}
+	case r.Method == http.MethodGet:
+		object, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code></Error>") // nolint: errcheck
+			return
+		}
+		for name, values := range object.header {
+			w.Header()[name] = values
+		}
+		w.Write(object.contents) // nolint: errcheck
+	case r.Method == http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// list lists objects one page at a time to exercise pagination.
+func (s *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("prefix")
+	var keys []string
+	for key := range s.objects {
+		if strings.HasPrefix(key, prefix) && key > r.URL.Query().Get("continuation-token") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	type object struct{ Key string }
+	result := struct {
+		XMLName               xml.Name `xml:"ListBucketResult"`
+		Contents              []object
+		IsTruncated           bool
+		NextContinuationToken string `xml:",omitempty"`
+	}{}
+	if len(keys) > 0 {
+		result.Contents = []object{{Key: keys[0]}}
+		result.IsTruncated = len(keys) > 1
+		if result.IsTruncated {
+			result.NextContinuationToken = keys[0]
+		}
+	}
+	xml.NewEncoder(w).Encode(result) // nolint: errcheck
+}
@@ -0,0 +1,190 @@
+package planstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pkg/errors"
+)
+
+// Metadata keys of the plans stored in S3.
+const (
+	headCommitMetadata = "atlantis-head-commit"
+	checksumMetadata   = "atlantis-checksum"
+)
+
+const (
+	// defaultS3Region is the region used if neither S3Config nor the AWS
+	// config sets one.
+	defaultS3Region = "us-east-1"
+	// s3RequestTimeout is how long a request to S3 can take.
+	s3RequestTimeout = 5 * time.Minute
+)
+
+// S3Config configures an S3Store.
+type S3Config struct {
+	Bucket string
+	// Prefix is prepended to the keys of the plans, ex. atlantis/.
+	Prefix string
+	// Region is the region of Bucket. If empty, the region of the AWS config
+	// is used, ex. from AWS_REGION, or else us-east-1.
+	Region string
+	// Endpoint is the URL of an S3-compatible service, ex. MinIO. If empty,
+	// AWS S3 is used.
+	Endpoint string
+	// UsePathStyle addresses buckets by path rather than by host, which most
+	// S3-compatible services need.
+	UsePathStyle bool
+	// AccessKeyID and SecretAccessKey are static credentials. If they're
+	// empty, the credentials are found by the AWS SDK's default chain, ex.
+	// environment variables, the shared config files, web identity tokens
+	// (IRSA) or the instance's role.
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// S3Store stores plans in an S3 bucket, or in a bucket of an S3-compatible
+// service.
+type S3Store struct {
+	cfg    S3Config
+	client *s3.Client
+}
+
+// NewS3Store returns an S3Store.
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("bucket must be set")
+	}
+	if (cfg.AccessKeyID == "") != (cfg.SecretAccessKey == "") {
+		return nil, errors.New("access key ID and secret access key must be set together")
+	}
+	if cfg.Endpoint != "" {
+		endpoint, err := url.Parse(cfg.Endpoint)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing endpoint")
+		}
+		if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+			return nil, fmt.Errorf("endpoint must have http:// or https://, got %q", cfg.Endpoint)
+		}
+	}
+
+	opts := []func(*config.LoadOptions) error{
+		config.WithDefaultRegion(defaultS3Region),
+		// A buildable client is used so that the AWS config can add a custom
+		// CA bundle to it, ex. from AWS_CA_BUNDLE.
+		config.WithHTTPClient(awshttp.NewBuildableClient().WithTimeout(s3RequestTimeout)),
+	}
+	if cfg.Region != "" {
+		opts = append(opts, config.WithRegion(cfg.Region))
+	}
+	if cfg.AccessKeyID != "" {
+		opts = append(opts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, "")))
+	}
+	awsCfg, err := config.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading AWS config")
+	}
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+		o.UsePathStyle = cfg.UsePathStyle
+	})
+	return &S3Store{
+		cfg:    cfg,
+		client: client,
+	}, nil
+}
+
+func (s *S3Store) Put(key string, plan Plan, contents []byte) error {
+	_, err := s.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(s.cfg.Bucket),
+		Key:    aws.String(s.cfg.Prefix + key),
+		Body:   bytes.NewReader(contents),
+		Metadata: map[string]string{
+			headCommitMetadata: plan.HeadCommit,
+			checksumMetadata:   plan.Checksum,
+		},
+	})
+	return errors.Wrapf(err, "putting %s", key)
+}
+
+func (s *S3Store) Get(key string) (Plan, []byte, error) {
+	out, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.cfg.Bucket),
+		Key:    aws.String(s.cfg.Prefix + key),
+	})
+	if isNotFound(err) {
+		return Plan{}, nil, ErrNotFound
+	}
+	if err != nil {
+		return Plan{}, nil, errors.Wrapf(err, "getting %s", key)
+	}
+	defer out.Body.Close() // nolint: errcheck
+	contents, err := io.ReadAll(out.Body)
+	if err != nil {
+		return Plan{}, nil, errors.Wrap(err, "reading plan")
+	}
+	plan := Plan{
+		HeadCommit: metadata(out.Metadata, headCommitMetadata),
+		Checksum:   metadata(out.Metadata, checksumMetadata),
+	}
+	return plan, contents, nil
+}
+
+func (s *S3Store) List(prefix string) ([]string, error) {
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.cfg.Bucket),
+		Prefix: aws.String(s.cfg.Prefix + prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing %s", prefix)
+		}
+		for _, object := range page.Contents {
+			keys = append(keys, strings.TrimPrefix(aws.ToString(object.Key), s.cfg.Prefix))
+		}
+	}
+	return keys, nil
+}
+
+func (s *S3Store) Delete(key string) error {
+	_, err := s.client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+		Bucket: aws.String(s.cfg.Bucket),
+		Key:    aws.String(s.cfg.Prefix + key),
+	})
+	if err != nil && !isNotFound(err) {
+		return errors.Wrapf(err, "deleting %s", key)
+	}
+	return nil
+}
+
+// isNotFound returns true if err is S3 responding that there's no object.
+func isNotFound(err error) bool {
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
+
+// metadata returns the value of the object metadata key. S3-compatible
+// services don't all keep the case of metadata keys.
+func metadata(m map[string]string, key string) string {
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,7 @@
+package postgres
+
+// Truncate deletes all rows so each test starts with an empty database.
+func Truncate(p *PostgresDB) error {
+	_, err := p.pool.Exec(ctx, `TRUNCATE atlantis_locks, atlantis_command_locks, atlantis_pulls, atlantis_api_jobs, atlantis_queued_events, atlantis_repo_drifts`)
+	return err
+}
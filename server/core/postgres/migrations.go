@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pkg/errors"
+)
+
+// migrationsLockID is the id of the advisory lock taken while migrating so
+// Atlantis instances starting at the same time don't migrate concurrently.
+const migrationsLockID = 7_411_273_000
+
+// migrations are the statements that migrate the schema from one version to
+// the next. migrations[i] migrates the schema to version i+1. Never edit a
+// migration that has been released, add a new one instead.
+var migrations = [][]string{
+	{
+		`CREATE TABLE atlantis_locks (
+			key TEXT PRIMARY KEY,
+			repo_full_name TEXT NOT NULL,
+			pull_num INTEGER NOT NULL,
+			lock JSONB NOT NULL
+		)`,
+		`CREATE INDEX atlantis_locks_pull_idx ON atlantis_locks (repo_full_name, pull_num)`,
+		`CREATE TABLE atlantis_command_locks (
+			command TEXT PRIMARY KEY,
+			lock JSONB NOT NULL
+		)`,
+		`CREATE TABLE atlantis_pulls (
+			key TEXT PRIMARY KEY,
+			status JSONB NOT NULL
+		)`,
+		`CREATE TABLE atlantis_api_jobs (
+			id TEXT PRIMARY KEY,
+			job JSONB NOT NULL
+		)`,
+	},
+	{
+		`CREATE TABLE atlantis_queued_events (
+			id TEXT PRIMARY KEY,
+			enqueued_at TIMESTAMPTZ NOT NULL,
+			event JSONB NOT NULL
+		)`,
+	},
+	{
+		`CREATE TABLE atlantis_repo_drifts (
+			key TEXT PRIMARY KEY,
+			drift JSONB NOT NULL
+		)`,
+	},
+}
+
+// migrate applies the migrations that haven't been applied yet in a single
+// transaction.
+func migrate(pool *pgxpool.Pool) error {
+	return pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationsLockID); err != nil {
+			return errors.Wrap(err, "acquiring migrations lock")
+		}
+		if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS atlantis_schema_migrations (
+			version INTEGER PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`); err != nil {
+			return errors.Wrap(err, "creating migrations table")
+		}
+
+		var version int
+		if err := tx.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM atlantis_schema_migrations`).Scan(&version); err != nil {
+			return errors.Wrap(err, "getting schema version")
+		}
+		if version > len(migrations) {
+			return errors.Errorf("schema version %d is newer than the latest version %d supported by this version of Atlantis", version, len(migrations))
+		}
+
+		for i := version; i < len(migrations); i++ {
+			for _, stmt := range migrations[i] {
+				if _, err := tx.Exec(ctx, stmt); err != nil {
+					return errors.Wrapf(err, "migrating to version %d", i+1)
+				}
+			}
+			if _, err := tx.Exec(ctx, `INSERT INTO atlantis_schema_migrations (version) VALUES ($1)`, i+1); err != nil {
+				return errors.Wrapf(err, "recording version %d", i+1)
+			}
+		}
+		return nil
+	})
+}
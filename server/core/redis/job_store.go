@@ -0,0 +1,115 @@
+package redis
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"github.com/runatlantis/atlantis/server/jobs"
+)
+
+const jobOutputIndexKey = "jobs/output-index"
+
+// JobStore stores the output of completed jobs in Redis. It implements
+// jobs.JobStore.
+type JobStore struct {
+	client    *redis.Client
+	retention jobs.Retention
+}
+
+// NewJobStore returns a JobStore that uses r's connection.
+func (r *RedisDB) NewJobStore(retention jobs.Retention) *JobStore {
+	return &JobStore{
+		client:    r.client,
+		retention: retention,
+	}
+}
+
+// Save stores the job. If the retention has a max age the job expires after
+// it.
+func (s *JobStore) Save(job jobs.StoredJob) error {
+	serialized, err := json.Marshal(job)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+	var expiration time.Duration
+	if s.retention.MaxAge > 0 {
+		expiration = s.retention.MaxAge
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.jobOutputKey(job.JobID), serialized, expiration)
+		pipe.ZAdd(ctx, jobOutputIndexKey, redis.Z{Score: float64(job.Time.Unix()), Member: job.JobID})
+		return nil
+	})
+	return errors.Wrap(err, "db transaction failed")
+}
+
+// Get returns the job with jobID.
+// If there is no job, returns a nil pointer.
+func (s *JobStore) Get(jobID string) (*jobs.StoredJob, error) {
+	key := s.jobOutputKey(jobID)
+	val, err := s.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "db transaction failed")
+	}
+	var job jobs.StoredJob
+	if err := json.Unmarshal([]byte(val), &job); err != nil {
+		return nil, errors.Wrapf(err, "deserializing job output at %q", key)
+	}
+	return &job, nil
+}
+
+// Prune deletes the jobs that exceed the retention. Jobs that already expired
+// are removed from the index.
+func (s *JobStore) Prune() error {
+	index, err := s.client.ZRangeWithScores(ctx, jobOutputIndexKey, 0, -1).Result()
+	if err != nil {
+		return errors.Wrap(err, "db transaction failed")
+	}
+
+	pipe := s.client.Pipeline()
+	sizes := make([]*redis.IntCmd, len(index))
+	for i, z := range index {
+		sizes[i] = pipe.StrLen(ctx, s.jobOutputKey(z.Member.(string)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return errors.Wrap(err, "db transaction failed")
+	}
+
+	var summaries []jobs.StoredJobSummary
+	var expired []string
+	for i, z := range index {
+		jobID := z.Member.(string)
+		size := sizes[i].Val()
+		if size == 0 {
+			expired = append(expired, jobID)
+			continue
+		}
+		summaries = append(summaries, jobs.StoredJobSummary{
+			JobID: jobID,
+			Time:  time.Unix(int64(z.Score), 0),
+			Size:  size,
+		})
+	}
+	expired = append(expired, s.retention.Expired(summaries, time.Now())...)
+	if len(expired) == 0 {
+		return nil
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, jobID := range expired {
+			pipe.Del(ctx, s.jobOutputKey(jobID))
+			pipe.ZRem(ctx, jobOutputIndexKey, jobID)
+		}
+		return nil
+	})
+	return errors.Wrap(err, "db transaction failed")
+}
+
+func (s *JobStore) jobOutputKey(jobID string) string {
+	return fmt.Sprintf("jobs/output/%s", jobID)
+}
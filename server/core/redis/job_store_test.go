@@ -0,0 +1,67 @@
+package redis_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/runatlantis/atlantis/server/jobs"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestJobStore_SaveGet(t *testing.T) {
+	s := miniredis.RunT(t)
+	store := newTestRedis(s).NewJobStore(jobs.Retention{MaxAge: time.Hour})
+
+	job := jobs.StoredJob{
+		JobID: "1234",
+		JobInfo: jobs.JobInfo{
+			PullInfo: jobs.PullInfo{
+				PullNum:     1,
+				Repo:        "repo",
+				ProjectName: "project",
+				Workspace:   "default",
+			},
+			HeadCommit: "abc123",
+		},
+		Output: []string{"line 1", "line 2"},
+		Time:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	Ok(t, store.Save(job))
+
+	stored, err := store.Get(job.JobID)
+	Ok(t, err)
+	Equals(t, &job, stored)
+
+	t.Log("jobs should expire after the max age")
+	s.FastForward(2 * time.Hour)
+	stored, err = store.Get(job.JobID)
+	Ok(t, err)
+	Assert(t, stored == nil, "exp nil")
+}
+
+func TestJobStore_Prune(t *testing.T) {
+	s := miniredis.RunT(t)
+	store := newTestRedis(s).NewJobStore(jobs.Retention{MaxSize: 300})
+
+	now := time.Now()
+	for i, jobID := range []string{"oldest", "older", "newest"} {
+		Ok(t, store.Save(jobs.StoredJob{
+			JobID:  jobID,
+			Output: []string{strings.Repeat("a", 100)},
+			Time:   now.Add(time.Duration(i) * time.Hour),
+		}))
+	}
+
+	Ok(t, store.Prune())
+
+	for jobID, exp := range map[string]bool{"oldest": false, "older": false, "newest": true} {
+		stored, err := store.Get(jobID)
+		Ok(t, err)
+		Equals(t, exp, stored != nil)
+	}
+	members, err := s.ZMembers("jobs/output-index")
+	Ok(t, err)
+	Equals(t, []string{"newest"}, members)
+}
@@ -6,11 +6,13 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/redis/go-redis/v9"
+	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 )
@@ -24,6 +26,10 @@ type RedisDB struct { // nolint: revive
 
 const (
 	pullKeySeparator = "::"
+	// queuedEventsKey is the hash holding the queued events by ID.
+	queuedEventsKey = "queued-events"
+	// repoDriftsKey is the hash holding the drift detection results by key.
+	repoDriftsKey = "repo-drifts"
 )
 
 func New(hostname string, port int, password string, tlsEnabled bool, insecureSkipVerify bool, db int) (*RedisDB, error) {
@@ -288,77 +294,164 @@ func (r *RedisDB) DeletePullStatus(pull models.PullRequest) error {
 	return errors.Wrap(r.deletePull(key), "db transaction failed")
 }
 
+// ListPullStatuses returns the statuses of all pull requests.
+func (r *RedisDB) ListPullStatuses() ([]models.PullStatus, error) {
+	var statuses []models.PullStatus
+	iter := r.client.Scan(ctx, 0, fmt.Sprintf("*%s*%s*", pullKeySeparator, pullKeySeparator), 0).Iterator()
+	for iter.Next(ctx) {
+		s, err := r.getPull(iter.Val())
+		if err != nil {
+			return nil, err
+		}
+		// The pull could have been deleted since we scanned it.
+		if s != nil {
+			statuses = append(statuses, *s)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, errors.Wrap(err, "db transaction failed")
+	}
+	return statuses, nil
+}
+
 func (r *RedisDB) UpdatePullWithResults(pull models.PullRequest, newResults []command.ProjectResult) (models.PullStatus, error) {
 	key, err := r.pullKey(pull)
 	if err != nil {
 		return models.PullStatus{}, err
 	}
 
-	var newStatus models.PullStatus
 	currStatus, err := r.getPull(key)
 	if err != nil {
-		return newStatus, errors.Wrap(err, "db transaction failed")
+		return models.PullStatus{}, errors.Wrap(err, "db transaction failed")
+	}
+
+	newStatus := locking.UpdatePullStatus(currStatus, pull, newResults)
+
+	// Now, we overwrite the key with our new status.
+	return newStatus, errors.Wrap(r.writePull(key, newStatus), "db transaction failed")
+}
+
+// UpdateAPIJob creates or overwrites the API job with job's ID.
+func (r *RedisDB) UpdateAPIJob(job models.APIJob) error {
+	serialized, err := json.Marshal(job)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
 	}
+	err = r.client.Set(ctx, r.apiJobKey(job.ID), serialized, 0).Err()
+	return errors.Wrap(err, "db transaction failed")
+}
 
-	// If there is no pull OR if the pull we have is out of date, we
-	// just write a new pull.
-	if currStatus == nil || currStatus.Pull.HeadCommit != pull.HeadCommit {
-		var statuses []models.ProjectStatus
-		for _, res := range newResults {
-			statuses = append(statuses, r.projectResultToProject(res))
+// GetAPIJob returns the API job with id.
+// If there is no job, returns a nil pointer.
+func (r *RedisDB) GetAPIJob(id string) (*models.APIJob, error) {
+	key := r.apiJobKey(id)
+	val, err := r.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "db transaction failed")
+	}
+	var job models.APIJob
+	if err := json.Unmarshal([]byte(val), &job); err != nil {
+		return nil, errors.Wrapf(err, "deserializing API job at %q", key)
+	}
+	return &job, nil
+}
+
+// ListAPIJobs returns the API jobs, oldest first.
+func (r *RedisDB) ListAPIJobs() ([]models.APIJob, error) {
+	var jobs []models.APIJob
+	iter := r.client.Scan(ctx, 0, r.apiJobKey("*"), 0).Iterator()
+	for iter.Next(ctx) {
+		val, err := r.client.Get(ctx, iter.Val()).Result()
+		// The job could have been deleted since we scanned it.
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, errors.Wrap(err, "db transaction failed")
 		}
-		newStatus = models.PullStatus{
-			Pull:     pull,
-			Projects: statuses,
+		var job models.APIJob
+		if err := json.Unmarshal([]byte(val), &job); err != nil {
+			return nil, errors.Wrapf(err, "deserializing API job at %q", iter.Val())
 		}
-	} else {
-		// If there's an existing pull at the right commit then we have to
-		// merge our project results with the existing ones. We do a merge
-		// because it's possible a user is just applying a single project
-		// in this command and so we don't want to delete our data about
-		// other projects that aren't affected by this command.
-		newStatus = *currStatus
-		for _, res := range newResults {
-			// First, check if we should update any existing projects.
-			updatedExisting := false
-			for i := range newStatus.Projects {
-				// NOTE: We're using a reference here because we are
-				// in-place updating its Status field.
-				proj := &newStatus.Projects[i]
-				if res.Workspace == proj.Workspace &&
-					res.RepoRelDir == proj.RepoRelDir &&
-					res.ProjectName == proj.ProjectName {
-
-					proj.Status = res.PlanStatus()
-
-					// Updating only policy sets which are included in results; keeping the rest.
-					if len(proj.PolicyStatus) > 0 {
-						for i, oldPolicySet := range proj.PolicyStatus {
-							for _, newPolicySet := range res.PolicyStatus() {
-								if oldPolicySet.PolicySetName == newPolicySet.PolicySetName {
-									proj.PolicyStatus[i] = newPolicySet
-								}
-							}
-						}
-					} else {
-						proj.PolicyStatus = res.PolicyStatus()
-					}
-
-					updatedExisting = true
-					break
-				}
-			}
+		jobs = append(jobs, job)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, errors.Wrap(err, "db transaction failed")
+	}
+	models.SortAPIJobs(jobs)
+	return jobs, nil
+}
 
-			if !updatedExisting {
-				// If we didn't update an existing project, then we need to
-				// add this because it's a new one.
-				newStatus.Projects = append(newStatus.Projects, r.projectResultToProject(res))
-			}
+// DeleteAPIJob deletes the API job with id. It's not an error if there is no
+// such job.
+func (r *RedisDB) DeleteAPIJob(id string) error {
+	err := r.client.Del(ctx, r.apiJobKey(id)).Err()
+	return errors.Wrap(err, "db transaction failed")
+}
+
+// EnqueueEvent persists event until it's deleted with DeleteQueuedEvent.
+func (r *RedisDB) EnqueueEvent(event models.QueuedEvent) error {
+	serialized, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+	err = r.client.HSet(ctx, queuedEventsKey, event.ID, serialized).Err()
+	return errors.Wrap(err, "db transaction failed")
+}
+
+// DeleteQueuedEvent deletes the queued event with id. It's not an error if
+// there is no such event.
+func (r *RedisDB) DeleteQueuedEvent(id string) error {
+	err := r.client.HDel(ctx, queuedEventsKey, id).Err()
+	return errors.Wrap(err, "db transaction failed")
+}
+
+// ListQueuedEvents returns the queued events, oldest first.
+func (r *RedisDB) ListQueuedEvents() ([]models.QueuedEvent, error) {
+	vals, err := r.client.HGetAll(ctx, queuedEventsKey).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "db transaction failed")
+	}
+	var events []models.QueuedEvent
+	for id, val := range vals {
+		var event models.QueuedEvent
+		if err := json.Unmarshal([]byte(val), &event); err != nil {
+			return nil, errors.Wrapf(err, "deserializing queued event at %q", id)
 		}
+		events = append(events, event)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].EnqueuedAt.Before(events[j].EnqueuedAt)
+	})
+	return events, nil
+}
+
+// UpdateRepoDrift creates or overwrites the drift detection result at key.
+func (r *RedisDB) UpdateRepoDrift(key string, drift models.RepoDrift) error {
+	serialized, err := json.Marshal(drift)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
 	}
+	err = r.client.HSet(ctx, repoDriftsKey, key, serialized).Err()
+	return errors.Wrap(err, "db transaction failed")
+}
 
-	// Now, we overwrite the key with our new status.
-	return newStatus, errors.Wrap(r.writePull(key, newStatus), "db transaction failed")
+// ListRepoDrifts returns the drift detection results.
+func (r *RedisDB) ListRepoDrifts() ([]models.RepoDrift, error) {
+	vals, err := r.client.HGetAll(ctx, repoDriftsKey).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "db transaction failed")
+	}
+	var drifts []models.RepoDrift
+	for key, val := range vals {
+		var drift models.RepoDrift
+		if err := json.Unmarshal([]byte(val), &drift); err != nil {
+			return nil, errors.Wrapf(err, "deserializing drift at %q", key)
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
 }
 
 func (r *RedisDB) getPull(key string) (*models.PullStatus, error) {
@@ -398,6 +491,10 @@ func (r *RedisDB) commandLockKey(cmdName command.Name) string {
 	return fmt.Sprintf("global/%s/lock", cmdName)
 }
 
+func (r *RedisDB) apiJobKey(id string) string {
+	return fmt.Sprintf("api/jobs/%s", id)
+}
+
 func (r *RedisDB) pullKey(pull models.PullRequest) (string, error) {
 	hostname := pull.BaseRepo.VCSHost.Hostname
 	if strings.Contains(hostname, pullKeySeparator) {
@@ -410,13 +507,3 @@ func (r *RedisDB) pullKey(pull models.PullRequest) (string, error) {
 
 	return fmt.Sprintf("%s::%s::%d", hostname, repo, pull.Num), nil
 }
-
-func (r *RedisDB) projectResultToProject(p command.ProjectResult) models.ProjectStatus {
-	return models.ProjectStatus{
-		Workspace:    p.Workspace,
-		RepoRelDir:   p.RepoRelDir,
-		ProjectName:  p.ProjectName,
-		PolicyStatus: p.PolicyStatus(),
-		Status:       p.PlanStatus(),
-	}
-}
@@ -11,6 +11,7 @@ import (
 	"math/big"
 	"net"
 	"os"
+	"sort"
 	"testing"
 	"time"
 
@@ -789,9 +790,10 @@ func TestPullStatus_UpdateMerge_Apply(t *testing.T) {
 				Status:      models.ErroredApplyStatus,
 			},
 			{
-				RepoRelDir: "staythesame",
-				Workspace:  "default",
-				Status:     models.PlannedPlanStatus,
+				RepoRelDir:  "staythesame",
+				Workspace:   "default",
+				Status:      models.PlannedPlanStatus,
+				PlanHistory: []models.PlanRecord{{}},
 			},
 			{
 				RepoRelDir: "newresult",
@@ -917,6 +919,201 @@ func TestPullStatus_UpdateMerge_ApprovePolicies(t *testing.T) {
 	}
 }
 
+// Test that plans are kept in the project's plan history, including plans
+// of earlier commits.
+func TestPullStatus_PlanHistory(t *testing.T) {
+	s := miniredis.RunT(t)
+	rdb := newTestRedis(s)
+
+	pull := models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		State:      models.OpenPullState,
+		BaseRepo: models.Repo{
+			FullName: "runatlantis/atlantis",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+	firstPlan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := rdb.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:     command.Plan,
+			RepoRelDir:  ".",
+			Workspace:   "default",
+			User:        "alice",
+			JobID:       "job1",
+			PlanSuccess: &models.PlanSuccess{PlannedAt: firstPlan},
+		},
+	})
+	Ok(t, err)
+
+	pull.HeadCommit = "newsha"
+	secondPlan := firstPlan.Add(time.Hour)
+	status, err := rdb.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:    command.Plan,
+			RepoRelDir: ".",
+			Workspace:  "default",
+			User:       "bob",
+			JobID:      "job2",
+			PlanSuccess: &models.PlanSuccess{
+				PlannedAt: secondPlan,
+				Analysis:  &models.PlanAnalysis{ResourceChanges: []models.ResourceChange{{Address: "null_resource.a", Action: models.CreateResourceAction}}},
+			},
+		},
+	})
+	Ok(t, err)
+
+	// Errored plans aren't kept.
+	_, err = rdb.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			Command:    command.Plan,
+			RepoRelDir: ".",
+			Workspace:  "default",
+			User:       "carol",
+			JobID:      "job3",
+			Error:      errors.New("plan failed"),
+		},
+	})
+	Ok(t, err)
+
+	expHistory := []models.PlanRecord{
+		{JobID: "job1", User: "alice", PlannedAt: firstPlan},
+		{JobID: "job2", User: "bob", PlannedAt: secondPlan, Stats: models.PlanSuccessStats{Add: 1, Changes: true}},
+	}
+	Equals(t, expHistory, status.Projects[0].PlanHistory)
+
+	statuses, err := rdb.ListPullStatuses()
+	Ok(t, err)
+	Equals(t, 1, len(statuses))
+	Equals(t, expHistory, statuses[0].Projects[0].PlanHistory)
+	Equals(t, "job3", statuses[0].Projects[0].JobID)
+	Equals(t, models.ErroredPlanStatus, statuses[0].Projects[0].Status)
+}
+
+func TestPullStatus_List(t *testing.T) {
+	s := miniredis.RunT(t)
+	rdb := newTestRedis(s)
+
+	statuses, err := rdb.ListPullStatuses()
+	Ok(t, err)
+	Equals(t, 0, len(statuses))
+
+	for _, num := range []int{1, 2} {
+		_, err = rdb.UpdatePullWithResults(models.PullRequest{
+			Num:      num,
+			BaseRepo: models.Repo{FullName: "runatlantis/atlantis", VCSHost: models.VCSHost{Hostname: "github.com"}},
+		}, []command.ProjectResult{{Command: command.Plan, RepoRelDir: ".", Workspace: "default", Failure: "failure"}})
+		Ok(t, err)
+	}
+	// Locks aren't pull statuses.
+	_, _, err = rdb.TryLock(lock)
+	Ok(t, err)
+
+	statuses, err = rdb.ListPullStatuses()
+	Ok(t, err)
+	var nums []int
+	for _, s := range statuses {
+		nums = append(nums, s.Pull.Num)
+	}
+	sort.Ints(nums)
+	Equals(t, []int{1, 2}, nums)
+}
+
+func TestAPIJob_UpdateGetListDelete(t *testing.T) {
+	s := miniredis.RunT(t)
+	rdb := newTestRedis(s)
+
+	job := models.APIJob{
+		ID:            "id",
+		Command:       "plan",
+		Status:        models.SucceededAPIJobStatus,
+		ProjectJobIDs: []string{"job1", "job2"},
+		Result:        []byte(`{"PlansDeleted":false}`),
+		CreatedAt:     time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		UpdatedAt:     time.Date(2023, 1, 2, 3, 4, 6, 0, time.UTC),
+	}
+	Ok(t, rdb.UpdateAPIJob(job))
+
+	got, err := rdb.GetAPIJob("id")
+	Ok(t, err)
+	Equals(t, &job, got)
+
+	got, err = rdb.GetAPIJob("missing")
+	Ok(t, err)
+	Assert(t, got == nil, "exp nil job but got %v", got)
+
+	older := models.APIJob{ID: "older", Status: models.RunningAPIJobStatus, CreatedAt: job.CreatedAt.Add(-time.Hour)}
+	Ok(t, rdb.UpdateAPIJob(older))
+	jobs, err := rdb.ListAPIJobs()
+	Ok(t, err)
+	Equals(t, []models.APIJob{older, job}, jobs)
+
+	Ok(t, rdb.DeleteAPIJob("older"))
+	Ok(t, rdb.DeleteAPIJob("missing"))
+	jobs, err = rdb.ListAPIJobs()
+	Ok(t, err)
+	Equals(t, []models.APIJob{job}, jobs)
+}
+
+func TestRepoDrifts(t *testing.T) {
+	s := miniredis.RunT(t)
+	rdb := newTestRedis(s)
+	drift := models.RepoDrift{
+		Repository: "owner/repo",
+		VCSHost:    "github.com",
+		Branch:     "main",
+		Time:       time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		Projects:   []models.ProjectDrift{{RepoRelDir: ".", Workspace: "default", Drifted: true}},
+	}
+	Ok(t, rdb.UpdateRepoDrift("Github/owner/repo", models.RepoDrift{Repository: "owner/repo", Error: "old"}))
+	Ok(t, rdb.UpdateRepoDrift("Github/owner/repo", drift))
+
+	drifts, err := rdb.ListRepoDrifts()
+	Ok(t, err)
+	Equals(t, []models.RepoDrift{drift}, drifts)
+}
+
+func TestQueuedEvents(t *testing.T) {
+	s := miniredis.RunT(t)
+	rdb := newTestRedis(s)
+
+	first := models.QueuedEvent{
+		ID:         "first",
+		Kind:       models.AutoplanQueuedEvent,
+		BaseRepo:   models.Repo{FullName: "owner/repo"},
+		HeadRepo:   &models.Repo{FullName: "owner/repo"},
+		Pull:       &models.PullRequest{Num: 1},
+		PullNum:    1,
+		User:       models.User{Username: "user"},
+		EnqueuedAt: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	second := models.QueuedEvent{
+		ID:         "second",
+		Kind:       models.CommentQueuedEvent,
+		BaseRepo:   models.Repo{FullName: "owner/repo"},
+		PullNum:    1,
+		User:       models.User{Username: "user"},
+		Command:    []byte(`{"Name":1}`),
+		EnqueuedAt: time.Date(2023, 1, 2, 3, 4, 6, 0, time.UTC),
+	}
+	Ok(t, rdb.EnqueueEvent(second))
+	Ok(t, rdb.EnqueueEvent(first))
+
+	events, err := rdb.ListQueuedEvents()
+	Ok(t, err)
+	Equals(t, []models.QueuedEvent{first, second}, events)
+
+	Ok(t, rdb.DeleteQueuedEvent("first"))
+	Ok(t, rdb.DeleteQueuedEvent("missing"))
+	events, err = rdb.ListQueuedEvents()
+	Ok(t, err)
+	Equals(t, []models.QueuedEvent{second}, events)
+}
+
 func newTestRedis(mr *miniredis.Miniredis) *redis.RedisDB {
 	r, err := redis.New(mr.Host(), mr.Server().Addr().Port, "", false, false, 0)
 	if err != nil {
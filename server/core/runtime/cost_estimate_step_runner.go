@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// CostEstimateStepRunner runs a cost tool against the JSON plan written by the
+// show step and saves its output so the estimate can be added to the plan
+// comment and checked by the cost_under apply requirement.
+type CostEstimateStepRunner struct {
+	RunStepRunner *RunStepRunner
+	// Command is the cost tool command. It's run like a custom run step so
+	// it can use $SHOWFILE. It must output JSON in the Infracost format.
+	Command string
+}
+
+func (c *CostEstimateStepRunner) Run(ctx command.ProjectContext, extraArgs []string, path string, envs map[string]string) (string, error) {
+	cmd := c.Command
+	if len(extraArgs) > 0 {
+		cmd = cmd + " " + strings.Join(extraArgs, " ")
+	}
+
+	// Pass `false` for streamOutput because the JSON isn't interesting to
+	// the user reading the build logs in the web UI.
+	output, err := c.RunStepRunner.Run(ctx, cmd, path, envs, false)
+	if err != nil {
+		return "", errors.Wrap(err, "estimating cost")
+	}
+	if _, err := models.NewCostEstimate([]byte(output)); err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(filepath.Join(path, ctx.GetCostEstimateFileName()), []byte(output), 0600); err != nil {
+		return "", errors.Wrap(err, "writing cost estimate")
+	}
+	// The estimate is rendered in the plan comment so there's no output.
+	return "", nil
+}
@@ -0,0 +1,68 @@
+package runtime_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	jobmocks "github.com/runatlantis/atlantis/server/jobs/mocks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestCostEstimateStepRunner_Run(t *testing.T) {
+	RegisterMockTestingT(t)
+	tfVersion, err := version.NewVersion("1.5.0")
+	Ok(t, err)
+	runner := runtime.CostEstimateStepRunner{
+		RunStepRunner: &runtime.RunStepRunner{
+			TerraformExecutor:       mocks.NewMockClient(),
+			DefaultTFVersion:        tfVersion,
+			ProjectCmdOutputHandler: jobmocks.NewMockProjectCommandOutputHandler(),
+		},
+		Command: `echo '{"currency": "USD", "diffTotalMonthlyCost": "12.5"}' #`,
+	}
+	ctx := command.ProjectContext{
+		Log:       logging.NewNoopLogger(t),
+		Workspace: "default",
+	}
+	tmpDir := t.TempDir()
+
+	t.Log("extra_args should be appended to the command")
+	out, err := runner.Run(ctx, []string{"--show-skipped"}, tmpDir, nil)
+	Ok(t, err)
+	Equals(t, "", out)
+
+	saved, err := os.ReadFile(filepath.Join(tmpDir, "default-cost.json"))
+	Ok(t, err)
+	Equals(t, "{\"currency\": \"USD\", \"diffTotalMonthlyCost\": \"12.5\"}\n", string(saved))
+}
+
+func TestCostEstimateStepRunner_RunInvalidOutput(t *testing.T) {
+	RegisterMockTestingT(t)
+	tfVersion, err := version.NewVersion("1.5.0")
+	Ok(t, err)
+	runner := runtime.CostEstimateStepRunner{
+		RunStepRunner: &runtime.RunStepRunner{
+			TerraformExecutor:       mocks.NewMockClient(),
+			DefaultTFVersion:        tfVersion,
+			ProjectCmdOutputHandler: jobmocks.NewMockProjectCommandOutputHandler(),
+		},
+		Command: "echo no api key",
+	}
+	ctx := command.ProjectContext{
+		Log:       logging.NewNoopLogger(t),
+		Workspace: "default",
+	}
+	tmpDir := t.TempDir()
+
+	_, err = runner.Run(ctx, nil, tmpDir, nil)
+	ErrEquals(t, "parsing cost estimate: no JSON object in output", err)
+	_, err = os.Stat(filepath.Join(tmpDir, "default-cost.json"))
+	Assert(t, os.IsNotExist(err), "exp cost file not to be written")
+}
@@ -25,8 +25,9 @@ func (i *InitStepRunner) Run(ctx command.ProjectContext, extraArgs []string, pat
 	}
 	// If .terraform.lock.hcl is not tracked in git and it exists prior to init
 	// delete it as it probably has been created by a previous run of
-	// terraform init
-	if common.FileExists(terraformLockfilePath) && !terraformLockFileTracked {
+	// terraform init. When applying, it's the one the plan was made with so
+	// it's kept.
+	if common.FileExists(terraformLockfilePath) && !terraformLockFileTracked && ctx.CommandName != command.Apply {
 		ctx.Log.Debug("Deleting `%s` that was generated by previous terraform init", terraformLockfilePath)
 		delErr := os.Remove(terraformLockfilePath)
 		if delErr != nil {
@@ -290,9 +290,10 @@ func TestRun_InitDeletesLockFileIfPresentAndNotTracked(t *testing.T) {
 		ThenReturn("output", nil)
 
 	ctx := command.ProjectContext{
-		Workspace:  "workspace",
-		RepoRelDir: ".",
-		Log:        logger,
+		CommandName: command.Plan,
+		Workspace:   "workspace",
+		RepoRelDir:  ".",
+		Log:         logger,
 	}
 	output, err := iso.Run(ctx, []string{"extra", "args"}, repoDir, map[string]string(nil))
 	Ok(t, err)
@@ -303,6 +304,42 @@ func TestRun_InitDeletesLockFileIfPresentAndNotTracked(t *testing.T) {
 	terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, repoDir, expectedArgs, map[string]string(nil), tfVersion, "workspace")
 }
 
+func TestRun_InitKeepsLockFileOnApply(t *testing.T) {
+	// Initialize the git repo.
+	repoDir := initRepo(t)
+
+	lockFilePath := filepath.Join(repoDir, ".terraform.lock.hcl")
+	err := os.WriteFile(lockFilePath, nil, 0600)
+	Ok(t, err)
+
+	RegisterMockTestingT(t)
+	terraform := mocks.NewMockClient()
+
+	tfVersion, _ := version.NewVersion("0.14.0")
+
+	iso := runtime.InitStepRunner{
+		TerraformExecutor: terraform,
+		DefaultTFVersion:  tfVersion,
+	}
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[*version.Version](), Any[string]())).
+		ThenReturn("output", nil)
+
+	ctx := command.ProjectContext{
+		CommandName: command.Apply,
+		Workspace:   "workspace",
+		RepoRelDir:  ".",
+		Log:         logging.NewNoopLogger(t),
+	}
+	_, err = iso.Run(ctx, nil, repoDir, map[string]string(nil))
+	Ok(t, err)
+
+	// The plan was made with the providers in the lock file so they're not
+	// upgraded.
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, repoDir, []string{"init", "-input=false"}, map[string]string(nil), tfVersion, "workspace")
+	_, err = os.Stat(lockFilePath)
+	Ok(t, err)
+}
+
 func runCmd(t *testing.T, dir string, name string, args ...string) string {
 	t.Helper()
 	cpCmd := exec.Command(name, args...)
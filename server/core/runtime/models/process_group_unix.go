@@ -0,0 +1,25 @@
+//go:build !windows
+
+package models
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in its own process group so that stopping it
+// also stops the processes it starts, ex. terraform started by sh.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// interruptProcessGroup sends SIGINT to cmd's process group so that
+// terraform can stop gracefully and release its state lock.
+func interruptProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+}
+
+// killProcessGroup sends SIGKILL to cmd's process group.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
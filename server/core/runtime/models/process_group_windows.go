@@ -0,0 +1,22 @@
+//go:build windows
+
+package models
+
+import (
+	"os/exec"
+)
+
+// setProcessGroup does nothing on Windows where processes can't be stopped
+// as a group.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// interruptProcessGroup kills cmd since Windows can't send interrupts to
+// other processes.
+func interruptProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// killProcessGroup kills cmd.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
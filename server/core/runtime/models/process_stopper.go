@@ -0,0 +1,151 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/jobs"
+)
+
+// KillGracePeriod is how long a process has to exit after it's interrupted
+// for timing out or being cancelled before it's killed.
+const KillGracePeriod = 30 * time.Second
+
+// processStartupTime is how long a process can take to start. A shell that's
+// interrupted while it starts can miss the interrupt, ex. dash catches it and
+// then execs the command it runs without acting on it, so a process that's
+// interrupted this soon after starting is interrupted again once it's passed.
+const processStartupTime = 250 * time.Millisecond
+
+// CombinedOutput runs cmd and returns its combined stdout and stderr like
+// exec.Cmd.CombinedOutput, except that cmd is stopped if the step it's
+// running for times out or its job is cancelled.
+func CombinedOutput(ctx command.ProjectContext, cmd *exec.Cmd, canceller *jobs.JobCanceller) ([]byte, error) {
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	setProcessGroup(cmd)
+	start, stopped := watchProcess(ctx, cmd, canceller)
+	if err := start(); err != nil {
+		return nil, err
+	}
+	err := cmd.Wait()
+	if stopErr := stopped(); stopErr != nil {
+		err = stopErr
+	}
+	return out.Bytes(), err
+}
+
+// watchProcess stops cmd, which must be started in its own process group,
+// when ctx.StepDeadline passes or the job for ctx is cancelled. It's called
+// before cmd is started so that a cancellation that arrives while cmd starts
+// isn't lost.
+//
+// start starts cmd. If the step has already timed out or the job has already
+// been cancelled, cmd isn't started and start returns why. Otherwise, once
+// cmd has started, its process group is interrupted as soon as it has to stop,
+// again if that was while it was starting, and killed if it hasn't exited
+// KillGracePeriod later. If start returns an
+// error, cmd wasn't started and stopped mustn't be called. Otherwise stopped
+// must be called once cmd has exited. It returns why cmd was stopped or nil if
+// it wasn't.
+func watchProcess(ctx command.ProjectContext, cmd *exec.Cmd, canceller *jobs.JobCanceller) (start func() error, stopped func() error) {
+	cancelCh := make(chan string, 1)
+	untrack := func() {}
+	if canceller != nil {
+		untrack = canceller.TrackProcess(ctx, func(cancelledBy string) {
+			select {
+			case cancelCh <- cancelledBy:
+			default:
+			}
+		})
+	}
+	var timeoutCh <-chan time.Time
+	stopTimer := func() bool { return false }
+	if !ctx.StepDeadline.IsZero() {
+		timer := time.NewTimer(time.Until(ctx.StepDeadline))
+		timeoutCh = timer.C
+		stopTimer = timer.Stop
+	}
+	cleanup := func() {
+		stopTimer()
+		untrack()
+	}
+
+	exited := make(chan struct{})
+	done := make(chan struct{})
+	var stopErr error
+	var startedAt time.Time
+	watch := func() {
+		defer close(done)
+		select {
+		case <-exited:
+			return
+		case user := <-cancelCh:
+			stopErr = fmt.Errorf("cancelled by %s", user)
+		case <-timeoutCh:
+			stopErr = fmt.Errorf("timed out after %s", ctx.StepTimeout)
+		}
+		ctx.Log.Warn("stopping process: %s", stopErr)
+		if err := interruptProcessGroup(cmd); err != nil {
+			ctx.Log.Warn("unable to interrupt process: %s", err)
+		}
+		var retryCh <-chan time.Time
+		if wait := processStartupTime - time.Since(startedAt); wait > 0 {
+			retryCh = time.After(wait)
+		}
+		killCh := time.After(KillGracePeriod)
+		for {
+			select {
+			case <-exited:
+				return
+			case <-retryCh:
+				retryCh = nil
+				ctx.Log.Debug("process was interrupted while starting, interrupting it again")
+				if err := interruptProcessGroup(cmd); err != nil {
+					ctx.Log.Warn("unable to interrupt process: %s", err)
+				}
+			case <-killCh:
+				ctx.Log.Warn("process didn't exit %s after being interrupted, killing it", KillGracePeriod)
+				if err := killProcessGroup(cmd); err != nil {
+					ctx.Log.Warn("unable to kill process: %s", err)
+				}
+				return
+			}
+		}
+	}
+
+	start = func() error {
+		var cancelledBy string
+		if canceller != nil {
+			cancelledBy = canceller.CancelledBy(ctx.JobID)
+		}
+		var err error
+		if cancelledBy != "" {
+			err = fmt.Errorf("cancelled by %s", cancelledBy)
+		} else if !ctx.StepDeadline.IsZero() && !time.Now().Before(ctx.StepDeadline) {
+			err = fmt.Errorf("timed out after %s", ctx.StepTimeout)
+		} else {
+			err = cmd.Start()
+		}
+		if err != nil {
+			cleanup()
+			return err
+		}
+		startedAt = time.Now()
+		// A cancellation or timeout that happened while cmd was starting is
+		// waiting on its channel, so cmd is interrupted straight away.
+		go watch()
+		return nil
+	}
+	stopped = func() error {
+		close(exited)
+		<-done
+		cleanup()
+		return stopErr
+	}
+	return start, stopped
+}
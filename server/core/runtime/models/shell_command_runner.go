@@ -26,25 +26,31 @@ type Line struct {
 }
 
 // ShellCommandRunner runs a command via `exec.Command` and streams output to the
-// `ProjectCommandOutputHandler`.
+// `ProjectCommandOutputHandler`. The command is stopped if the step it's
+// running for times out or its job is cancelled.
 type ShellCommandRunner struct {
 	command       string
 	workingDir    string
 	outputHandler jobs.ProjectCommandOutputHandler
 	streamOutput  bool
+	canceller     *jobs.JobCanceller
 	cmd           *exec.Cmd
 }
 
-func NewShellCommandRunner(command string, environ []string, workingDir string, streamOutput bool, outputHandler jobs.ProjectCommandOutputHandler) *ShellCommandRunner {
+// NewShellCommandRunner returns a runner for command. canceller can be nil if
+// jobs can't be cancelled.
+func NewShellCommandRunner(command string, environ []string, workingDir string, streamOutput bool, outputHandler jobs.ProjectCommandOutputHandler, canceller *jobs.JobCanceller) *ShellCommandRunner {
 	cmd := exec.Command("sh", "-c", command) // #nosec
 	cmd.Env = environ
 	cmd.Dir = workingDir
+	setProcessGroup(cmd)
 
 	return &ShellCommandRunner{
 		command:       command,
 		workingDir:    workingDir,
 		outputHandler: outputHandler,
 		streamOutput:  streamOutput,
+		canceller:     canceller,
 		cmd:           cmd,
 	}
 }
@@ -93,7 +99,8 @@ func (s *ShellCommandRunner) RunCommandAsync(ctx command.ProjectContext) (chan<-
 		stdin, _ := s.cmd.StdinPipe()
 
 		ctx.Log.Debug("starting %q in %q", s.command, s.workingDir)
-		err := s.cmd.Start()
+		startCmd, stopped := watchProcess(ctx, s.cmd, s.canceller)
+		err := startCmd()
 		if err != nil {
 			err = errors.Wrapf(err, "running %q in %q", s.command, s.workingDir)
 			ctx.Log.Err(err.Error())
@@ -148,6 +155,9 @@ func (s *ShellCommandRunner) RunCommandAsync(ctx command.ProjectContext) (chan<-
 
 		// Wait for the command to complete.
 		err = s.cmd.Wait()
+		if stopErr := stopped(); stopErr != nil {
+			err = stopErr
+		}
 
 		dur := time.Since(start)
 		log := ctx.Log.With("duration", dur)
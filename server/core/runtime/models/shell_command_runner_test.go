@@ -5,11 +5,14 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/petergtz/pegomock/v4"
 	"github.com/runatlantis/atlantis/server/core/runtime/models"
 	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/jobs/mocks"
+	"github.com/runatlantis/atlantis/server/logging"
 	logmocks "github.com/runatlantis/atlantis/server/logging/mocks"
 	. "github.com/runatlantis/atlantis/testing"
 )
@@ -54,7 +57,7 @@ func TestShellCommandRunner_Run(t *testing.T) {
 			expectedOutput := fmt.Sprintf("%s\n", strings.Join(c.ExpLines, "\n"))
 
 			// Run once with streaming enabled
-			runner := models.NewShellCommandRunner(c.Command, environ, cwd, true, projectCmdOutputHandler)
+			runner := models.NewShellCommandRunner(c.Command, environ, cwd, true, projectCmdOutputHandler, nil)
 			output, err := runner.Run(ctx)
 			Ok(t, err)
 			Equals(t, expectedOutput, output)
@@ -68,7 +71,7 @@ func TestShellCommandRunner_Run(t *testing.T) {
 			// command output handler should not have received anything
 
 			projectCmdOutputHandler = mocks.NewMockProjectCommandOutputHandler()
-			runner = models.NewShellCommandRunner(c.Command, environ, cwd, false, projectCmdOutputHandler)
+			runner = models.NewShellCommandRunner(c.Command, environ, cwd, false, projectCmdOutputHandler, nil)
 			output, err = runner.Run(ctx)
 			Ok(t, err)
 			Equals(t, expectedOutput, output)
@@ -78,3 +81,66 @@ func TestShellCommandRunner_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestShellCommandRunner_Timeout(t *testing.T) {
+	ctx := command.ProjectContext{
+		Log:          logging.NewNoopLogger(t),
+		Workspace:    "default",
+		RepoRelDir:   ".",
+		StepTimeout:  100 * time.Millisecond,
+		StepDeadline: time.Now().Add(100 * time.Millisecond),
+	}
+	runner := models.NewShellCommandRunner("echo started; sleep 30; echo finished", nil, t.TempDir(), false, nil, nil)
+
+	start := time.Now()
+	output, err := runner.Run(ctx)
+	ErrContains(t, "timed out after 100ms", err)
+	Equals(t, "started\n", output)
+	Assert(t, time.Since(start) < 10*time.Second, "exp command to be stopped, took %s", time.Since(start))
+}
+
+func TestShellCommandRunner_Cancelled(t *testing.T) {
+	ctx := command.ProjectContext{
+		Log:        logging.NewNoopLogger(t),
+		Workspace:  "default",
+		RepoRelDir: ".",
+		JobID:      "1234",
+	}
+	canceller := jobs.NewJobCanceller()
+	finish := canceller.StartJob(ctx)
+	defer finish()
+	// The job is cancelled before the command starts so it isn't started.
+	canceller.CancelJob(ctx.JobID, "alice")
+
+	runner := models.NewShellCommandRunner("echo started", nil, t.TempDir(), false, nil, canceller)
+
+	output, err := runner.Run(ctx)
+	ErrContains(t, "cancelled by alice", err)
+	Equals(t, "", output)
+}
+
+func TestShellCommandRunner_CancelledWhileRunning(t *testing.T) {
+	ctx := command.ProjectContext{
+		Log:        logging.NewNoopLogger(t),
+		Workspace:  "default",
+		RepoRelDir: ".",
+		JobID:      "1234",
+	}
+	canceller := jobs.NewJobCanceller()
+	finish := canceller.StartJob(ctx)
+	defer finish()
+
+	runner := models.NewShellCommandRunner("echo started; sleep 30; echo finished", nil, t.TempDir(), false, nil, canceller)
+
+	start := time.Now()
+	_, outCh := runner.RunCommandAsync(ctx)
+	Equals(t, models.Line{Line: "started"}, <-outCh)
+	canceller.CancelJob(ctx.JobID, "alice")
+	var err error
+	for line := range outCh {
+		Equals(t, "", line.Line)
+		err = line.Err
+	}
+	ErrContains(t, "cancelled by alice", err)
+	Assert(t, time.Since(start) < 10*time.Second, "exp command to be stopped, took %s", time.Since(start))
+}
@@ -9,6 +9,7 @@ import (
 
 	version "github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/planstore"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 )
@@ -30,14 +31,18 @@ type planStepRunner struct {
 	DefaultTFVersion    *version.Version
 	CommitStatusUpdater StatusUpdater
 	AsyncTFExec         AsyncTFExec
+	// PlanStore, if set, is where plan files are uploaded to so they can be
+	// applied by any Atlantis server.
+	PlanStore planstore.Store
 }
 
-func NewPlanStepRunner(terraformExecutor TerraformExec, defaultTfVersion *version.Version, commitStatusUpdater StatusUpdater, asyncTFExec AsyncTFExec) Runner {
+func NewPlanStepRunner(terraformExecutor TerraformExec, defaultTfVersion *version.Version, commitStatusUpdater StatusUpdater, asyncTFExec AsyncTFExec, planStore planstore.Store) Runner {
 	runner := &planStepRunner{
 		TerraformExecutor:   terraformExecutor,
 		DefaultTFVersion:    defaultTfVersion,
 		CommitStatusUpdater: commitStatusUpdater,
 		AsyncTFExec:         asyncTFExec,
+		PlanStore:           planStore,
 	}
 	return NewWorkspaceStepRunnerDelegate(terraformExecutor, defaultTfVersion, runner)
 }
@@ -49,6 +54,11 @@ func (p *planStepRunner) Run(ctx command.ProjectContext, extraArgs []string, pat
 	}
 
 	planFile := filepath.Join(path, GetPlanFilename(ctx.Workspace, ctx.ProjectName))
+	showResultFile := filepath.Join(path, ctx.GetShowResultFileName())
+	// Remove any JSON plan left over from a previous plan so it can't be
+	// mistaken for the output of this one.
+	os.Remove(showResultFile) // nolint: errcheck
+
 	planCmd := p.buildPlanCmd(ctx, extraArgs, path, tfVersion, planFile)
 	output, err := p.TerraformExecutor.RunCommandWithVersion(ctx, filepath.Clean(path), planCmd, envs, tfVersion, ctx.Workspace)
 	if p.isRemoteOpsErr(output, err) {
@@ -58,9 +68,44 @@ func (p *planStepRunner) Run(ctx command.ProjectContext, extraArgs []string, pat
 	if err != nil {
 		return output, err
 	}
+	if err := p.uploadPlan(ctx, planFile); err != nil {
+		return output, err
+	}
+	if err := p.showPlanJSON(ctx, path, tfVersion, planFile, showResultFile, envs); err != nil {
+		// The JSON plan is only used to analyze the plan so we don't fail
+		// the plan if we can't generate it.
+		ctx.Log.Warn("unable to generate JSON plan: %s", err)
+	}
 	return p.fmtPlanOutput(output, tfVersion), nil
 }
 
+// showPlanJSON runs terraform show -json on the plan file and saves the
+// output to showResultFile so the plan can be analyzed.
+func (p *planStepRunner) showPlanJSON(ctx command.ProjectContext, path string, tfVersion *version.Version, planFile string, showResultFile string, envs map[string]string) error {
+	if tfVersion.LessThan(version.Must(version.NewVersion(minimumShowTfVersion))) {
+		return nil
+	}
+	output, err := p.TerraformExecutor.RunCommandWithVersion(ctx, filepath.Clean(path), []string{"show", "-json", filepath.Clean(planFile)}, envs, tfVersion, ctx.Workspace)
+	if err != nil {
+		return errors.Wrap(err, "running terraform show")
+	}
+	return errors.Wrap(os.WriteFile(showResultFile, []byte(output), 0600), "writing terraform show result")
+}
+
+// uploadPlan uploads planFile to the plan store, along with the commit it
+// was made on so that it isn't applied once the pull request has changed.
+func (p *planStepRunner) uploadPlan(ctx command.ProjectContext, planFile string) error {
+	if p.PlanStore == nil {
+		return nil
+	}
+	contents, err := os.ReadFile(planFile)
+	if err != nil {
+		return errors.Wrap(err, "reading planfile")
+	}
+	key := planstore.Key(ctx.BaseRepo.FullName, ctx.Pull.Num, ctx.Workspace, ctx.RepoRelDir, filepath.Base(planFile))
+	return errors.Wrap(p.PlanStore.Put(key, planstore.NewPlan(ctx.Pull.HeadCommit, contents), contents), "uploading plan to plan store")
+}
+
 // isRemoteOpsErr returns true if there was an error caused due to this
 // project using TFE remote operations.
 func (p *planStepRunner) isRemoteOpsErr(output string, err error) bool {
@@ -75,6 +120,7 @@ func (p *planStepRunner) isRemoteOpsErr(output string, err error) bool {
 func (p *planStepRunner) remotePlan(ctx command.ProjectContext, extraArgs []string, path string, tfVersion *version.Version, planFile string, envs map[string]string) (string, error) {
 	argList := [][]string{
 		{"plan", "-input=false", "-refresh", "-no-color"},
+		p.resourceArgs(ctx),
 		extraArgs,
 		ctx.EscapedCommentArgs,
 	}
@@ -100,6 +146,9 @@ func (p *planStepRunner) remotePlan(ctx command.ProjectContext, extraArgs []stri
 	if err != nil {
 		return output, errors.Wrap(err, "unable to create planfile for remote ops")
 	}
+	if err := p.uploadPlan(ctx, planFile); err != nil {
+		return output, err
+	}
 
 	return p.fmtPlanOutput(output, tfVersion), nil
 }
@@ -122,6 +171,7 @@ func (p *planStepRunner) buildPlanCmd(ctx command.ProjectContext, extraArgs []st
 		// have spaces in its repo owner names.
 		{"plan", "-input=false", "-refresh", "-out", fmt.Sprintf("%q", planFile)},
 		tfVars,
+		p.resourceArgs(ctx),
 		extraArgs,
 		ctx.EscapedCommentArgs,
 		envFileArgs,
@@ -130,6 +180,28 @@ func (p *planStepRunner) buildPlanCmd(ctx command.ProjectContext, extraArgs []st
 	return p.flatten(argList)
 }
 
+// resourceArgs returns the -target and -replace flags for the resources the
+// plan was limited to in the comment. The addresses are escaped the same way
+// as the comment args since they come from the comment too.
+func (p *planStepRunner) resourceArgs(ctx command.ProjectContext) []string {
+	var args []string
+	for _, target := range ctx.Targets {
+		args = append(args, "-target="+escapeShellArg(target))
+	}
+	for _, replace := range ctx.Replaces {
+		args = append(args, "-replace="+escapeShellArg(replace))
+	}
+	return args
+}
+
+func escapeShellArg(arg string) string {
+	var escaped strings.Builder
+	for _, r := range arg {
+		escaped.WriteString("\\" + string(r))
+	}
+	return escaped.String()
+}
+
 // tfVars returns a list of "-var", "key=value" pairs that identify who and which
 // repo this command is running for. This can be used for naming the
 // session name in AWS which will identify in CloudTrail the source of
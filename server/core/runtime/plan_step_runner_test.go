@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/go-version"
 	. "github.com/petergtz/pegomock/v4"
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/planstore"
 	"github.com/runatlantis/atlantis/server/core/runtime"
 	runtimemocks "github.com/runatlantis/atlantis/server/core/runtime/mocks"
 	runtimemodels "github.com/runatlantis/atlantis/server/core/runtime/models"
@@ -39,7 +40,7 @@ func TestRun_AddsEnvVarFile(t *testing.T) {
 	// Using version >= 0.10 here so we don't expect any env commands.
 	tfVersion, _ := version.NewVersion("0.10.0")
 	logger := logging.NewNoopLogger(t)
-	s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTfExec)
+	s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTfExec, nil)
 
 	expPlanArgs := []string{"plan",
 		"-input=false",
@@ -98,7 +99,7 @@ func TestRun_UsesDiffPathForProject(t *testing.T) {
 	asyncTfExec := runtimemocks.NewMockAsyncTFExec()
 	tfVersion, _ := version.NewVersion("0.10.0")
 	logger := logging.NewNoopLogger(t)
-	s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTfExec)
+	s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTfExec, nil)
 	ctx := command.ProjectContext{
 		Log:                logger,
 		Workspace:          "default",
@@ -177,7 +178,7 @@ Terraform will perform the following actions:
 	commitStatusUpdater := runtimemocks.NewMockStatusUpdater()
 	asyncTfExec := runtimemocks.NewMockAsyncTFExec()
 	tfVersion, _ := version.NewVersion("0.10.0")
-	s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTfExec)
+	s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTfExec, nil)
 	When(terraform.RunCommandWithVersion(
 		Any[command.ProjectContext](),
 		Any[string](),
@@ -228,7 +229,7 @@ func TestRun_OutputOnErr(t *testing.T) {
 	commitStatusUpdater := runtimemocks.NewMockStatusUpdater()
 	asyncTfExec := runtimemocks.NewMockAsyncTFExec()
 	tfVersion, _ := version.NewVersion("0.10.0")
-	s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTfExec)
+	s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTfExec, nil)
 	expOutput := "expected output"
 	expErrMsg := "error!"
 	When(terraform.RunCommandWithVersion(
@@ -301,8 +302,9 @@ func TestRun_NoOptionalVarsIn012(t *testing.T) {
 				Any[string]())).ThenReturn("output", nil)
 
 			tfVersion, _ := version.NewVersion(c.tfVersion)
-			s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTfExec)
+			s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTfExec, nil)
 			ctx := command.ProjectContext{
+				Log:                logging.NewNoopLogger(t),
 				Workspace:          "default",
 				RepoRelDir:         ".",
 				User:               models.User{Username: "username"},
@@ -327,6 +329,155 @@ func TestRun_NoOptionalVarsIn012(t *testing.T) {
 
 }
 
+// Test that the targets and replaces from the comment are passed to the plan.
+func TestRun_TargetsAndReplaces(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := mocks.NewMockClient()
+	commitStatusUpdater := runtimemocks.NewMockStatusUpdater()
+	asyncTfExec := runtimemocks.NewMockAsyncTFExec()
+	When(terraform.RunCommandWithVersion(
+		Any[command.ProjectContext](),
+		Any[string](),
+		Any[[]string](),
+		Any[map[string]string](),
+		Any[*version.Version](),
+		Any[string]())).ThenReturn("output", nil)
+
+	tfVersion, _ := version.NewVersion("1.5.0")
+	s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTfExec, nil)
+	ctx := command.ProjectContext{
+		Log:                logging.NewNoopLogger(t),
+		Workspace:          "default",
+		RepoRelDir:         ".",
+		EscapedCommentArgs: []string{"comment", "args"},
+		Targets:            []string{"aws_instance.web", `module.vpc["a"]`},
+		Replaces:           []string{"aws_instance.db"},
+	}
+
+	output, err := s.Run(ctx, []string{"extra", "args"}, "/path", map[string]string(nil))
+	Ok(t, err)
+	Equals(t, "output", output)
+
+	expPlanArgs := []string{
+		"plan",
+		"-input=false",
+		"-refresh",
+		"-out",
+		fmt.Sprintf("%q", "/path/default.tfplan"),
+		`-target=\a\w\s\_\i\n\s\t\a\n\c\e\.\w\e\b`,
+		`-target=\m\o\d\u\l\e\.\v\p\c\[\"\a\"\]`,
+		`-replace=\a\w\s\_\i\n\s\t\a\n\c\e\.\d\b`,
+		"extra",
+		"args",
+		"comment",
+		"args",
+	}
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, "/path", expPlanArgs, map[string]string(nil), tfVersion, "default")
+}
+
+// Test that the plan is also saved as JSON so it can be analyzed, and that a
+// JSON plan from a previous run isn't left behind if show fails.
+func TestRun_SavesJSONPlan(t *testing.T) {
+	RegisterMockTestingT(t)
+	tfVersion, _ := version.NewVersion("1.5.0")
+	ctx := command.ProjectContext{
+		Log:       logging.NewNoopLogger(t),
+		Workspace: "default",
+	}
+
+	cases := []struct {
+		description string
+		showErr     error
+		expJSON     bool
+	}{
+		{"show succeeds", nil, true},
+		{"show fails", errors.New("show failed"), false},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			terraform := mocks.NewMockClient()
+			tmpDir := t.TempDir()
+			showFile := filepath.Join(tmpDir, "default.json")
+			Ok(t, os.WriteFile(showFile, []byte("stale"), 0600))
+
+			When(terraform.RunCommandWithVersion(
+				Any[command.ProjectContext](),
+				Any[string](),
+				Any[[]string](),
+				Any[map[string]string](),
+				Any[*version.Version](),
+				Any[string]())).
+				Then(func(params []Param) ReturnValues {
+					tfArgs := params[2].([]string)
+					if tfArgs[0] == "show" {
+						Equals(t, []string{"show", "-json", filepath.Join(tmpDir, "default.tfplan")}, tfArgs)
+						return []ReturnValue{`{"resource_changes":[]}`, c.showErr}
+					}
+					return []ReturnValue{"plan output", nil}
+				})
+
+			s := runtime.NewPlanStepRunner(terraform, tfVersion, runtimemocks.NewMockStatusUpdater(), runtimemocks.NewMockAsyncTFExec(), nil)
+			output, err := s.Run(ctx, nil, tmpDir, map[string]string(nil))
+			Ok(t, err)
+			Equals(t, "plan output", output)
+
+			contents, err := os.ReadFile(showFile)
+			if c.expJSON {
+				Ok(t, err)
+				Equals(t, `{"resource_changes":[]}`, string(contents))
+			} else {
+				Assert(t, os.IsNotExist(err), "exp JSON plan to be removed, got %v", err)
+			}
+		})
+	}
+}
+
+// Test that plans are uploaded to the plan store with the commit they were
+// made on, and that the plan fails if the upload fails.
+func TestRun_UploadsPlanToStore(t *testing.T) {
+	RegisterMockTestingT(t)
+	tfVersion, _ := version.NewVersion("0.11.0")
+	ctx := command.ProjectContext{
+		Log:        logging.NewNoopLogger(t),
+		Workspace:  "staging",
+		RepoRelDir: "dir",
+		BaseRepo:   models.Repo{FullName: "owner/repo"},
+		Pull:       models.PullRequest{Num: 2, HeadCommit: "abc123"},
+	}
+	terraform := mocks.NewMockClient()
+	When(terraform.RunCommandWithVersion(
+		Any[command.ProjectContext](),
+		Any[string](),
+		Any[[]string](),
+		Any[map[string]string](),
+		Any[*version.Version](),
+		Any[string]())).
+		Then(func(params []Param) ReturnValues {
+			path := params[1].(string)
+			if err := os.WriteFile(filepath.Join(path, "staging.tfplan"), []byte("plan"), 0600); err != nil {
+				return []ReturnValue{"", err}
+			}
+			return []ReturnValue{"plan output", nil}
+		})
+
+	store := &planstore.FileStore{Dir: t.TempDir()}
+	s := runtime.NewPlanStepRunner(terraform, tfVersion, runtimemocks.NewMockStatusUpdater(), runtimemocks.NewMockAsyncTFExec(), store)
+	_, err := s.Run(ctx, nil, t.TempDir(), map[string]string(nil))
+	Ok(t, err)
+
+	plan, contents, err := store.Get("owner/repo/2/staging/dir/staging.tfplan")
+	Ok(t, err)
+	Equals(t, "plan", string(contents))
+	Equals(t, planstore.NewPlan("abc123", contents), plan)
+
+	// The store's dir is a file so uploads fail.
+	storeFile := filepath.Join(t.TempDir(), "file")
+	Ok(t, os.WriteFile(storeFile, nil, 0600))
+	s = runtime.NewPlanStepRunner(terraform, tfVersion, runtimemocks.NewMockStatusUpdater(), runtimemocks.NewMockAsyncTFExec(), &planstore.FileStore{Dir: storeFile})
+	_, err = s.Run(ctx, nil, t.TempDir(), map[string]string(nil))
+	ErrContains(t, "uploading plan to plan store", err)
+}
+
 // Test plans if using remote ops.
 func TestRun_RemoteOps(t *testing.T) {
 	cases := []struct {
@@ -391,7 +542,7 @@ locally at this time.
 			commitStatusUpdater := runtimemocks.NewMockStatusUpdater()
 			tfVersion, _ := version.NewVersion(c.tfVersion)
 			asyncTf := &remotePlanMock{}
-			s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTf)
+			s := runtime.NewPlanStepRunner(terraform, tfVersion, commitStatusUpdater, asyncTf, nil)
 			absProjectPath := t.TempDir()
 
 			// First, terraform workspace gets run.
@@ -76,33 +76,52 @@ func (c ConftestTestCommandArgs) build() ([]string, error) {
 	return commandArgs, nil
 }
 
-// SourceResolver resolves the policy set to a local fs path
+// SourceResolver resolves the policy set to a local fs path and the revision
+// of the policies at that path. The revision is empty for local policy sets.
+// release must be called once the policies at path aren't used anymore so
+// they can be cleaned up. It's set if err is nil.
 //
 //go:generate pegomock generate --package mocks -o mocks/mock_conftest_client.go SourceResolver
 type SourceResolver interface {
-	Resolve(policySet valid.PolicySet) (string, error)
+	Resolve(policySet valid.PolicySet) (path string, revision string, release func(), err error)
 }
 
 // LocalSourceResolver resolves a local policy set to a local fs path
 type LocalSourceResolver struct {
 }
 
-func (p *LocalSourceResolver) Resolve(policySet valid.PolicySet) (string, error) {
-	return policySet.Path, nil
+func (p *LocalSourceResolver) Resolve(policySet valid.PolicySet) (string, string, func(), error) {
+	return policySet.Path, "", func() {}, nil
 
 }
 
 // SourceResolverProxy proxies to underlying source resolvers dynamically
 type SourceResolverProxy struct {
-	localSourceResolver SourceResolver
+	localSourceResolver  SourceResolver
+	remoteSourceResolver SourceResolver
 }
 
-func (p *SourceResolverProxy) Resolve(policySet valid.PolicySet) (string, error) {
+// NewSourceResolverProxy returns a SourceResolverProxy that resolves git and
+// http policy sets with remoteSourceResolver. remoteSourceResolver can be nil
+// if no remote policy sets are configured.
+func NewSourceResolverProxy(remoteSourceResolver SourceResolver) *SourceResolverProxy {
+	return &SourceResolverProxy{
+		localSourceResolver:  &LocalSourceResolver{},
+		remoteSourceResolver: remoteSourceResolver,
+	}
+}
+
+func (p *SourceResolverProxy) Resolve(policySet valid.PolicySet) (string, string, func(), error) {
 	switch source := policySet.Source; source {
 	case valid.LocalPolicySet:
 		return p.localSourceResolver.Resolve(policySet)
+	case valid.GitPolicySet, valid.HTTPPolicySet:
+		if p.remoteSourceResolver == nil {
+			return "", "", nil, fmt.Errorf("unable to resolve policy set source %s: remote policy sets are not enabled", source)
+		}
+		return p.remoteSourceResolver.Resolve(policySet)
 	default:
-		return "", fmt.Errorf("unable to resolve policy set source %s", source)
+		return "", "", nil, fmt.Errorf("unable to resolve policy set source %s", source)
 	}
 }
 
@@ -140,7 +159,7 @@ type ConfTestExecutorWorkflow struct {
 	Exec                   runtime_models.Exec
 }
 
-func NewConfTestExecutorWorkflow(log logging.SimpleLogging, versionRootDir string, conftestDownloder terraform.Downloader) *ConfTestExecutorWorkflow {
+func NewConfTestExecutorWorkflow(log logging.SimpleLogging, versionRootDir string, conftestDownloder terraform.Downloader, sourceResolver SourceResolver) *ConfTestExecutorWorkflow {
 	downloader := ConfTestVersionDownloader{
 		downloader: conftestDownloder,
 	}
@@ -160,10 +179,8 @@ func NewConfTestExecutorWorkflow(log logging.SimpleLogging, versionRootDir strin
 	return &ConfTestExecutorWorkflow{
 		VersionCache:           versionCache,
 		DefaultConftestVersion: version,
-		SourceResolver: &SourceResolverProxy{
-			localSourceResolver: &LocalSourceResolver{},
-		},
-		Exec: runtime_models.LocalExec{},
+		SourceResolver:         sourceResolver,
+		Exec:                   runtime_models.LocalExec{},
 	}
 }
 
@@ -175,11 +192,10 @@ func (c *ConfTestExecutorWorkflow) Run(ctx command.ProjectContext, executablePat
 	var combinedErr error
 
 	for _, policySet := range ctx.PolicySets.PolicySets {
-		path, resolveErr := c.SourceResolver.Resolve(policySet)
-
-		// Let's not fail the whole step because of a single failure. Log and fail silently
+		path, revision, release, resolveErr := c.SourceResolver.Resolve(policySet)
 		if resolveErr != nil {
-			ctx.Log.Err("Error resolving policyset %s. err: %s", policySet.Name, resolveErr.Error())
+			combinedErr = multierror.Append(combinedErr, fmt.Errorf("policy_set: %s: resolving policies: %s", policySet.Name, resolveErr))
+			policySetResults = append(policySetResults, unresolvedPolicySetResult(policySet, resolveErr))
 			continue
 		}
 
@@ -192,6 +208,7 @@ func (c *ConfTestExecutorWorkflow) Run(ctx command.ProjectContext, executablePat
 
 		serializedArgs, _ := args.build()
 		cmdOutput, cmdErr := c.Exec.CombinedOutput(serializedArgs, envs, workdir)
+		release()
 
 		if cmdErr != nil {
 			// Since we're running conftest for each policyset, individual command errors should be concatenated.
@@ -210,6 +227,7 @@ func (c *ConfTestExecutorWorkflow) Run(ctx command.ProjectContext, executablePat
 		policySetResults = append(policySetResults, models.PolicySetResult{
 			PolicySetName:  policySet.Name,
 			ConftestOutput: cmdOutput,
+			Revision:       revision,
 			Passed:         passed,
 			ReqApprovals:   policySet.ApproveCount,
 		})
@@ -245,6 +263,17 @@ func (c *ConfTestExecutorWorkflow) Run(ctx command.ProjectContext, executablePat
 
 }
 
+// unresolvedPolicySetResult is the result of a policy set whose policies
+// couldn't be resolved. It fails so the policies aren't skipped silently.
+func unresolvedPolicySetResult(policySet valid.PolicySet, resolveErr error) models.PolicySetResult {
+	return models.PolicySetResult{
+		PolicySetName:  policySet.Name,
+		ConftestOutput: fmt.Sprintf("unable to resolve policies: %s", resolveErr),
+		Passed:         false,
+		ReqApprovals:   policySet.ApproveCount,
+	}
+}
+
 func (c *ConfTestExecutorWorkflow) sanitizeOutput(inputFile string, output string) string {
 	return strings.Replace(output, inputFile, "<redacted plan file>", -1)
 }
@@ -196,8 +196,8 @@ func TestRun(t *testing.T) {
 		expectedArgsPolicy1 := []string{executablePath, "test", "-p", localPolicySetPath1, filepath.Join(workdir, "testproj-default.json"), "--no-color"}
 		expectedArgsPolicy2 := []string{executablePath, "test", "-p", localPolicySetPath2, filepath.Join(workdir, "testproj-default.json"), "--no-color"}
 
-		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, nil)
-		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, nil)
+		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, "", func() {}, nil)
+		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, "", func() {}, nil)
 
 		When(mockExec.CombinedOutput(expectedArgsPolicy1, envs, workdir)).ThenReturn(expectedOutput, nil)
 		When(mockExec.CombinedOutput(expectedArgsPolicy2, envs, workdir)).ThenReturn(expectedOutput, nil)
@@ -221,8 +221,8 @@ func TestRun(t *testing.T) {
 		expectedArgsPolicy1 := []string{executablePath, "test", "-p", localPolicySetPath1, filepath.Join(workdir, "testproj-default.json"), "--no-color"}
 		expectedArgsPolicy2 := []string{executablePath, "test", "-p", localPolicySetPath2, filepath.Join(workdir, "testproj-default.json"), "--no-color"}
 
-		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, nil)
-		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, nil)
+		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, "", func() {}, nil)
+		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, "", func() {}, nil)
 
 		When(mockExec.CombinedOutput(expectedArgsPolicy1, envs, workdir)).ThenReturn(expectedOutput, nil)
 		When(mockExec.CombinedOutput(expectedArgsPolicy2, envs, workdir)).ThenReturn(expectedOutput, nil)
@@ -241,41 +241,39 @@ func TestRun(t *testing.T) {
 		var extraArgs []string
 
 		expectedOutput := "Success"
-		expectedResult := `[{"PolicySetName":"policy1","ConftestOutput":"Success","Passed":true,"ReqApprovals":0,"CurApprovals":0}]`
+		expectedResult := `[{"PolicySetName":"policy1","ConftestOutput":"Success","Passed":true,"ReqApprovals":0,"CurApprovals":0},{"PolicySetName":"policy2","ConftestOutput":"unable to resolve policies: err","Passed":false,"ReqApprovals":0,"CurApprovals":0}]`
 
 		expectedArgsPolicy1 := []string{executablePath, "test", "-p", localPolicySetPath1, filepath.Join(workdir, "testproj-default.json"), "--no-color"}
 		expectedArgsPolicy2 := []string{executablePath, "test", "-p", localPolicySetPath2, filepath.Join(workdir, "testproj-default.json"), "--no-color"}
 
-		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, nil)
-		When(mockResolver.Resolve(policySet2)).ThenReturn("", errors.New("err"))
+		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, "", func() {}, nil)
+		When(mockResolver.Resolve(policySet2)).ThenReturn("", "", nil, errors.New("err"))
 
 		When(mockExec.CombinedOutput(expectedArgsPolicy1, envs, workdir)).ThenReturn(expectedOutput, nil)
 		When(mockExec.CombinedOutput(expectedArgsPolicy2, envs, workdir)).ThenReturn(expectedOutput, nil)
 
 		result, err := subject.Run(ctx, executablePath, envs, workdir, extraArgs)
 
-		Ok(t, errors.Unwrap(err))
+		ErrContains(t, "policy_set: policy2: resolving policies: err", err)
 
-		Assert(t, result == expectedResult, "result is expected")
+		Equals(t, expectedResult, result)
 
 	})
 
 	t.Run("error resolving both policy sources", func(t *testing.T) {
 		var extraArgs []string
 
-		expectedResult := ""
-		expectedArgsPolicy1 := []string{executablePath, "test", "-p", localPolicySetPath1, filepath.Join(workdir, "testproj-default.json"), "--no-color"}
+		expectedResult := `[{"PolicySetName":"policy1","ConftestOutput":"unable to resolve policies: err","Passed":false,"ReqApprovals":0,"CurApprovals":0},{"PolicySetName":"policy2","ConftestOutput":"unable to resolve policies: err","Passed":false,"ReqApprovals":0,"CurApprovals":0}]`
 
-		When(mockResolver.Resolve(policySet1)).ThenReturn("", errors.New("err"))
-		When(mockResolver.Resolve(policySet2)).ThenReturn("", errors.New("err"))
-
-		When(mockExec.CombinedOutput(expectedArgsPolicy1, envs, workdir)).ThenReturn(expectedResult, nil)
+		When(mockResolver.Resolve(policySet1)).ThenReturn("", "", nil, errors.New("err"))
+		When(mockResolver.Resolve(policySet2)).ThenReturn("", "", nil, errors.New("err"))
 
 		result, err := subject.Run(ctx, executablePath, envs, workdir, extraArgs)
 
-		Ok(t, err)
+		ErrContains(t, "policy_set: policy1: resolving policies: err", err)
+		ErrContains(t, "policy_set: policy2: resolving policies: err", err)
 
-		Assert(t, result == "", "result is expected")
+		Equals(t, expectedResult, result)
 
 	})
 
@@ -289,8 +287,8 @@ func TestRun(t *testing.T) {
 		expectedArgsPolicy1 := []string{executablePath, "test", "-p", localPolicySetPath1, filepath.Join(workdir, "testproj-default.json"), "--no-color"}
 		expectedArgsPolicy2 := []string{executablePath, "test", "-p", localPolicySetPath2, filepath.Join(workdir, "testproj-default.json"), "--no-color"}
 
-		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, nil)
-		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, nil)
+		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, "", func() {}, nil)
+		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, "", func() {}, nil)
 
 		When(mockExec.CombinedOutput(expectedArgsPolicy1, envs, workdir)).ThenReturn(expectedOutputPolicy1, errors.New("exit status code 1"))
 		When(mockExec.CombinedOutput(expectedArgsPolicy2, envs, workdir)).ThenReturn(expectedOutputPolicy2, nil)
@@ -311,8 +309,8 @@ func TestRun(t *testing.T) {
 		expectedArgsPolicy1 := []string{executablePath, "test", "-p", localPolicySetPath1, filepath.Join(workdir, "testproj-default.json"), "--no-color"}
 		expectedArgsPolicy2 := []string{executablePath, "test", "-p", localPolicySetPath2, filepath.Join(workdir, "testproj-default.json"), "--no-color"}
 
-		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, nil)
-		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, nil)
+		When(mockResolver.Resolve(policySet1)).ThenReturn(localPolicySetPath1, "", func() {}, nil)
+		When(mockResolver.Resolve(policySet2)).ThenReturn(localPolicySetPath2, "", func() {}, nil)
 
 		When(mockExec.CombinedOutput(expectedArgsPolicy1, envs, workdir)).ThenReturn(expectedOutput, errors.New("exit status code 1"))
 		When(mockExec.CombinedOutput(expectedArgsPolicy2, envs, workdir)).ThenReturn(expectedOutput, errors.New("exit status code 1"))
@@ -25,23 +25,31 @@ func NewMockSourceResolver(options ...pegomock.Option) *MockSourceResolver {
 func (mock *MockSourceResolver) SetFailHandler(fh pegomock.FailHandler) { mock.fail = fh }
 func (mock *MockSourceResolver) FailHandler() pegomock.FailHandler      { return mock.fail }
 
-func (mock *MockSourceResolver) Resolve(policySet valid.PolicySet) (string, error) {
+func (mock *MockSourceResolver) Resolve(policySet valid.PolicySet) (string, string, func(), error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockSourceResolver().")
 	}
 	params := []pegomock.Param{policySet}
-	result := pegomock.GetGenericMockFrom(mock).Invoke("Resolve", params, []reflect.Type{reflect.TypeOf((*string)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	result := pegomock.GetGenericMockFrom(mock).Invoke("Resolve", params, []reflect.Type{reflect.TypeOf((*string)(nil)).Elem(), reflect.TypeOf((*string)(nil)).Elem(), reflect.TypeOf((*func())(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
 	var ret0 string
-	var ret1 error
+	var ret1 string
+	var ret2 func()
+	var ret3 error
 	if len(result) != 0 {
 		if result[0] != nil {
 			ret0 = result[0].(string)
 		}
 		if result[1] != nil {
-			ret1 = result[1].(error)
+			ret1 = result[1].(string)
+		}
+		if result[2] != nil {
+			ret2 = result[2].(func())
+		}
+		if result[3] != nil {
+			ret3 = result[3].(error)
 		}
 	}
-	return ret0, ret1
+	return ret0, ret1, ret2, ret3
 }
 
 func (mock *MockSourceResolver) VerifyWasCalledOnce() *VerifierMockSourceResolver {
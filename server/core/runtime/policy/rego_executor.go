@@ -0,0 +1,297 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	version "github.com/hashicorp/go-version"
+	"github.com/open-policy-agent/opa/ast"
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/util"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// regoRuleName matches the rules that are evaluated. Like conftest, deny and
+// violation rules fail the policy set and warn rules don't. Rules can have a
+// suffix, ex. deny_public_bucket.
+var regoRuleName = regexp.MustCompile(`^(deny|violation|warn)(_[a-zA-Z0-9_]+)?$`)
+
+// regoNamespace is the package whose rules are evaluated. It's conftest's
+// default namespace. Other packages can hold helpers used by its rules.
+const regoNamespace = "main"
+
+// RegoExecutorWorkflow evaluates the Rego policies of each policy set
+// in-process against the JSON plan. It's an alternative to
+// ConfTestExecutorWorkflow that doesn't need a conftest binary and gives
+// structured results instead of conftest's text output.
+type RegoExecutorWorkflow struct {
+	SourceResolver SourceResolver
+}
+
+func NewRegoExecutorWorkflow(sourceResolver SourceResolver) *RegoExecutorWorkflow {
+	return &RegoExecutorWorkflow{
+		SourceResolver: sourceResolver,
+	}
+}
+
+// EnsureExecutorVersion is a no-op because policies are evaluated with the
+// OPA version Atlantis is built with.
+func (r *RegoExecutorWorkflow) EnsureExecutorVersion(log logging.SimpleLogging, v *version.Version) (string, error) {
+	if v != nil {
+		log.Debug("ignoring conftest version %s since policies are evaluated in-process", v)
+	}
+	return "", nil
+}
+
+// Run evaluates each policy set and returns the results as JSON, like
+// ConfTestExecutorWorkflow. extraArgs are conftest arguments so they're
+// ignored.
+func (r *RegoExecutorWorkflow) Run(ctx command.ProjectContext, executablePath string, envs map[string]string, workdir string, extraArgs []string) (string, error) {
+	ctx.Log.Debug("policy sets, %s ", ctx.PolicySets)
+	if len(extraArgs) > 0 {
+		ctx.Log.Warn("ignoring policy_check extra_args %q since policies are evaluated in-process", extraArgs)
+	}
+
+	inputFile := filepath.Join(workdir, ctx.GetShowResultFileName())
+	input, err := readRegoInput(inputFile)
+	if err != nil {
+		return "", err
+	}
+
+	var policySetResults []models.PolicySetResult
+	var combinedErr error
+	for _, policySet := range ctx.PolicySets.PolicySets {
+		path, revision, release, resolveErr := r.SourceResolver.Resolve(policySet)
+		if resolveErr != nil {
+			combinedErr = multierror.Append(combinedErr, fmt.Errorf("policy_set: %s: resolving policies: %s", policySet.Name, resolveErr))
+			policySetResults = append(policySetResults, unresolvedPolicySetResult(policySet, resolveErr))
+			continue
+		}
+
+		ruleResults, numRules, evalErr := evalRegoPolicies(path, input)
+		release()
+		if evalErr != nil {
+			combinedErr = multierror.Append(combinedErr, fmt.Errorf("policy_set: %s: rego: %s", policySet.Name, evalErr))
+			continue
+		}
+
+		passed := true
+		for _, result := range ruleResults {
+			if result.Severity == models.DenyPolicySeverity {
+				passed = false
+			}
+		}
+		if !passed {
+			// The wording matches conftest's so the failure is handled the
+			// same way.
+			combinedErr = multierror.Append(combinedErr, fmt.Errorf("policy_set: %s: rego: some policies failed", policySet.Name))
+		}
+
+		policySetResults = append(policySetResults, models.PolicySetResult{
+			PolicySetName:  policySet.Name,
+			ConftestOutput: regoOutput(ruleResults, numRules),
+			RuleResults:    ruleResults,
+			Revision:       revision,
+			Passed:         passed,
+			ReqApprovals:   policySet.ApproveCount,
+		})
+	}
+
+	if policySetResults == nil {
+		ctx.Log.Warn("no policies have been configured.")
+		return "", combinedErr
+	}
+
+	marshaledStatus, err := json.Marshal(policySetResults)
+	if err != nil {
+		return "", errors.New("cannot marshal data into []PolicySetResult. data")
+	}
+
+	// Write policy check results to a file which can be used by custom workflow run steps for metrics, notifications, etc.
+	policyCheckResultFile := filepath.Join(workdir, ctx.GetPolicyCheckResultFileName())
+	if err := os.WriteFile(policyCheckResultFile, marshaledStatus, 0600); err != nil {
+		combinedErr = multierror.Append(combinedErr, err)
+	}
+
+	return string(marshaledStatus), combinedErr
+}
+
+func readRegoInput(inputFile string) (interface{}, error) {
+	showJSON, err := os.ReadFile(inputFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading JSON plan")
+	}
+	var input interface{}
+	if err := util.UnmarshalJSON(showJSON, &input); err != nil {
+		return nil, errors.Wrap(err, "parsing JSON plan")
+	}
+	return input, nil
+}
+
+// evalRegoPolicies evaluates the deny, violation and warn rules in the main
+// package of the Rego files at path, which can be a file or a directory. It returns the messages
+// of the rules and how many rules were evaluated.
+func evalRegoPolicies(path string, input interface{}) ([]models.PolicyRuleResult, int, error) {
+	modules, err := loadRegoModules(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(modules) == 0 {
+		return nil, 0, fmt.Errorf("no policies found in %s", path)
+	}
+	compiler, err := ast.CompileModules(modules)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var results []models.PolicyRuleResult
+	rules := regoRules(compiler)
+	for _, rule := range rules {
+		rs, err := rego.New(
+			rego.Compiler(compiler),
+			rego.Query("data."+rule),
+			rego.Input(input),
+		).Eval(context.Background())
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "evaluating %s", rule)
+		}
+		severity := models.DenyPolicySeverity
+		if strings.HasPrefix(rule[strings.LastIndex(rule, ".")+1:], "warn") {
+			severity = models.WarnPolicySeverity
+		}
+		for _, r := range rs {
+			for _, expr := range r.Expressions {
+				for _, msg := range regoMessages(rule, expr.Value) {
+					results = append(results, models.PolicyRuleResult{
+						Rule:     rule,
+						Message:  msg,
+						Severity: severity,
+					})
+				}
+			}
+		}
+	}
+	return results, len(rules), nil
+}
+
+// loadRegoModules reads the Rego files at path keyed by file name. Test files
+// are skipped since they aren't policies.
+func loadRegoModules(path string) (map[string]string, error) {
+	modules := make(map[string]string)
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(p) != ".rego" || strings.HasSuffix(p, "_test.rego") {
+			return nil
+		}
+		contents, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		modules[p] = string(contents)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "loading policies from %s", path)
+	}
+	return modules, nil
+}
+
+// regoRules returns the rules of the main package to evaluate, ex. main.deny,
+// sorted so the results are always in the same order.
+func regoRules(compiler *ast.Compiler) []string {
+	seen := make(map[string]bool)
+	var rules []string
+	for _, module := range compiler.Modules {
+		pkg := strings.TrimPrefix(module.Package.Path.String(), "data.")
+		if pkg != regoNamespace {
+			continue
+		}
+		for _, rule := range module.Rules {
+			name := rule.Head.Ref()[0].String()
+			if !regoRuleName.MatchString(name) {
+				continue
+			}
+			if rule := pkg + "." + name; !seen[rule] {
+				seen[rule] = true
+				rules = append(rules, rule)
+			}
+		}
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// regoMessages converts the value of a rule to messages. Like conftest, rules
+// can be sets of strings or of objects with a msg key. Boolean rules use the
+// rule as the message.
+func regoMessages(rule string, value interface{}) []string {
+	switch v := value.(type) {
+	case []interface{}:
+		var msgs []string
+		for _, elem := range v {
+			msgs = append(msgs, regoMessages(rule, elem)...)
+		}
+		return msgs
+	case map[string]interface{}:
+		if msg, ok := v["msg"]; ok {
+			return []string{fmt.Sprint(msg)}
+		}
+		return []string{fmt.Sprint(v)}
+	case string:
+		return []string{v}
+	case bool:
+		if v {
+			return []string{rule}
+		}
+		return nil
+	case nil:
+		return nil
+	default:
+		return []string{fmt.Sprint(v)}
+	}
+}
+
+// regoOutput formats results like conftest's output so the summary of each
+// policy set can be shown the same way.
+func regoOutput(results []models.PolicyRuleResult, numRules int) string {
+	var lines []string
+	var warnings, failures int
+	matched := make(map[string]bool)
+	for _, result := range results {
+		matched[result.Rule] = true
+		prefix := "FAIL"
+		if result.Severity == models.WarnPolicySeverity {
+			prefix = "WARN"
+			warnings++
+		} else {
+			failures++
+		}
+		lines = append(lines, fmt.Sprintf("%s - %s: %s", prefix, result.Rule, result.Message))
+	}
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, fmt.Sprintf("%s, %d passed, %s, %s, 0 exceptions",
+		plural(numRules, "test"), numRules-len(matched), plural(warnings, "warning"), plural(failures, "failure")))
+	return strings.Join(lines, "\n")
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
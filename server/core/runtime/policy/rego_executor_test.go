@@ -0,0 +1,191 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+const regoPlanJSON = `{
+  "resource_changes": [
+    {"address": "aws_s3_bucket.public", "type": "aws_s3_bucket", "change": {"actions": ["create"], "after": {"acl": "public-read"}}},
+    {"address": "aws_instance.web", "type": "aws_instance", "change": {"actions": ["delete"]}}
+  ]
+}`
+
+const regoDenyPolicy = `package main
+
+deny[msg] {
+	rc := input.resource_changes[_]
+	rc.change.after.acl == "public-read"
+	msg := sprintf("%s must not be public", [rc.address])
+}
+
+warn_delete[{"msg": msg}] {
+	rc := input.resource_changes[_]
+	rc.change.actions[_] == "delete"
+	msg := sprintf("%s will be deleted", [rc.address])
+}
+
+violation[msg] {
+	false
+	msg := "never"
+}
+`
+
+const regoWarnPolicy = `package main
+
+import data.terraform.tags
+
+warn[msg] {
+	rc := input.resource_changes[_]
+	not tags.tagged(rc)
+	msg := sprintf("%s has no tags", [rc.address])
+}
+`
+
+// regoHelperPolicy is in a package other than main so its rules are only
+// used by the main package's rules, not evaluated themselves.
+const regoHelperPolicy = `package terraform.tags
+
+tagged(rc) {
+	rc.type != "aws_instance"
+}
+
+deny[msg] {
+	msg := "rules outside of main shouldn't be evaluated"
+}
+`
+
+func TestRegoExecutorWorkflow_Run(t *testing.T) {
+	policiesDir := t.TempDir()
+	Ok(t, os.MkdirAll(filepath.Join(policiesDir, "deny"), 0700))
+	Ok(t, os.MkdirAll(filepath.Join(policiesDir, "warn"), 0700))
+	Ok(t, os.WriteFile(filepath.Join(policiesDir, "deny", "policy.rego"), []byte(regoDenyPolicy), 0600))
+	Ok(t, os.WriteFile(filepath.Join(policiesDir, "warn", "policy.rego"), []byte(regoWarnPolicy), 0600))
+	Ok(t, os.WriteFile(filepath.Join(policiesDir, "warn", "tags.rego"), []byte(regoHelperPolicy), 0600))
+	t.Log("test files shouldn't be evaluated")
+	Ok(t, os.WriteFile(filepath.Join(policiesDir, "warn", "policy_test.rego"), []byte("package main\n\ndeny[msg] { msg := \"test\" }\n"), 0600))
+
+	workdir := t.TempDir()
+	ctx := command.ProjectContext{
+		Log:       logging.NewNoopLogger(t),
+		Workspace: "default",
+		PolicySets: valid.PolicySets{
+			PolicySets: []valid.PolicySet{
+				{Name: "deny", Path: filepath.Join(policiesDir, "deny"), Source: valid.LocalPolicySet, ApproveCount: 1},
+				{Name: "warn", Path: filepath.Join(policiesDir, "warn"), Source: valid.LocalPolicySet, ApproveCount: 1},
+			},
+		},
+	}
+	Ok(t, os.WriteFile(filepath.Join(workdir, ctx.GetShowResultFileName()), []byte(regoPlanJSON), 0600))
+
+	output, err := NewRegoExecutorWorkflow(NewSourceResolverProxy(nil)).Run(ctx, "", nil, workdir, nil)
+	ErrContains(t, "policy_set: deny: rego: some policies failed", err)
+
+	expResults := []models.PolicySetResult{
+		{
+			PolicySetName: "deny",
+			ConftestOutput: "FAIL - main.deny: aws_s3_bucket.public must not be public\n" +
+				"WARN - main.warn_delete: aws_instance.web will be deleted\n" +
+				"\n" +
+				"3 tests, 1 passed, 1 warning, 1 failure, 0 exceptions",
+			RuleResults: []models.PolicyRuleResult{
+				{Rule: "main.deny", Message: "aws_s3_bucket.public must not be public", Severity: models.DenyPolicySeverity},
+				{Rule: "main.warn_delete", Message: "aws_instance.web will be deleted", Severity: models.WarnPolicySeverity},
+			},
+			Passed:       false,
+			ReqApprovals: 1,
+		},
+		{
+			PolicySetName: "warn",
+			ConftestOutput: "WARN - main.warn: aws_instance.web has no tags\n" +
+				"\n" +
+				"1 test, 0 passed, 1 warning, 0 failures, 0 exceptions",
+			RuleResults: []models.PolicyRuleResult{
+				{Rule: "main.warn", Message: "aws_instance.web has no tags", Severity: models.WarnPolicySeverity},
+			},
+			Passed:       true,
+			ReqApprovals: 1,
+		},
+	}
+	var results []models.PolicySetResult
+	Ok(t, json.Unmarshal([]byte(output), &results))
+	Equals(t, expResults, results)
+
+	saved, err := os.ReadFile(filepath.Join(workdir, ctx.GetPolicyCheckResultFileName()))
+	Ok(t, err)
+	Equals(t, output, string(saved))
+
+	summary := (&models.PolicyCheckResults{PolicySetResults: results}).Summary()
+	Equals(t, "policy set: deny: 3 tests, 1 passed, 1 warning, 1 failure, 0 exceptions\npolicy set: warn: 1 test, 0 passed, 1 warning, 0 failures, 0 exceptions", summary)
+}
+
+func TestRegoExecutorWorkflow_RunInvalidPolicy(t *testing.T) {
+	policiesDir := t.TempDir()
+	Ok(t, os.WriteFile(filepath.Join(policiesDir, "policy.rego"), []byte("package main\n\ndeny[msg] {"), 0600))
+
+	workdir := t.TempDir()
+	ctx := command.ProjectContext{
+		Log:       logging.NewNoopLogger(t),
+		Workspace: "default",
+		PolicySets: valid.PolicySets{
+			PolicySets: []valid.PolicySet{
+				{Name: "invalid", Path: policiesDir, Source: valid.LocalPolicySet},
+			},
+		},
+	}
+	Ok(t, os.WriteFile(filepath.Join(workdir, ctx.GetShowResultFileName()), []byte(regoPlanJSON), 0600))
+
+	_, err := NewRegoExecutorWorkflow(NewSourceResolverProxy(nil)).Run(ctx, "", nil, workdir, nil)
+	ErrContains(t, "policy_set: invalid: rego:", err)
+}
+
+func TestRegoExecutorWorkflow_RunResolveError(t *testing.T) {
+	policiesDir := t.TempDir()
+	Ok(t, os.WriteFile(filepath.Join(policiesDir, "policy.rego"), []byte(regoDenyPolicy), 0600))
+
+	workdir := t.TempDir()
+	ctx := command.ProjectContext{
+		Log:       logging.NewNoopLogger(t),
+		Workspace: "default",
+		PolicySets: valid.PolicySets{
+			PolicySets: []valid.PolicySet{
+				{Name: "remote", Path: "https://example.com/policies.tar.gz", Source: valid.HTTPPolicySet, ApproveCount: 1},
+			},
+		},
+	}
+	Ok(t, os.WriteFile(filepath.Join(workdir, ctx.GetShowResultFileName()), []byte(regoPlanJSON), 0600))
+
+	resolver := failingSourceResolver{err: errors.New("downloading bundle: 404 Not Found")}
+	output, err := NewRegoExecutorWorkflow(resolver).Run(ctx, "", nil, workdir, nil)
+	ErrContains(t, "policy_set: remote: resolving policies: downloading bundle: 404 Not Found", err)
+
+	var results []models.PolicySetResult
+	Ok(t, json.Unmarshal([]byte(output), &results))
+	Equals(t, []models.PolicySetResult{
+		{
+			PolicySetName:  "remote",
+			ConftestOutput: "unable to resolve policies: downloading bundle: 404 Not Found",
+			Passed:         false,
+			ReqApprovals:   1,
+		},
+	}, results)
+}
+
+// failingSourceResolver fails to resolve every policy set.
+type failingSourceResolver struct {
+	err error
+}
+
+func (f failingSourceResolver) Resolve(valid.PolicySet) (string, string, func(), error) {
+	return "", "", nil, f.err
+}
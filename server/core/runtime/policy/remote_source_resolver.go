@@ -0,0 +1,370 @@
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	getter "github.com/hashicorp/go-getter/v2"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+const (
+	// currentRevisionFile is the file in a policy set's cache directory that
+	// holds the revision that's currently used.
+	currentRevisionFile = "current"
+	// fetchDir is the directory in a policy set's cache directory that policy
+	// sets are fetched into before being moved to their revision's directory.
+	fetchDir = ".fetch"
+)
+
+var unsafePathChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// RemoteSourceResolver fetches git and http policy sets into a cache directory
+// and resolves them to the cached copy. Policy sets are fetched the first time
+// they're resolved and then again on a schedule by PolicySetRefreshJob.
+//
+// Each revision is stored in its own directory. Revisions are removed once
+// they're neither current nor used by a policy check, so a policy check that's
+// running while a policy set is refreshed keeps using the policies it
+// started with.
+type RemoteSourceResolver struct {
+	CacheDir   string
+	Downloader terraform.Downloader
+
+	// mu guards revisions, inUse and fetchLocks.
+	mu sync.Mutex
+	// revisions is the current revision of each policy set by cache key.
+	revisions map[string]string
+	// inUse counts the policy checks using each revision by its directory.
+	inUse map[string]int
+	// fetchLocks serialize the fetches of each policy set by cache key so
+	// that concurrent fetches don't write to the same directory. Different
+	// policy sets are fetched at the same time.
+	fetchLocks map[string]*sync.Mutex
+}
+
+func NewRemoteSourceResolver(cacheDir string, downloader terraform.Downloader) *RemoteSourceResolver {
+	return &RemoteSourceResolver{
+		CacheDir:   cacheDir,
+		Downloader: downloader,
+		revisions:  make(map[string]string),
+		inUse:      make(map[string]int),
+		fetchLocks: make(map[string]*sync.Mutex),
+	}
+}
+
+// Resolve returns the path to the cached copy of policySet and its revision,
+// fetching it if it hasn't been fetched yet. Copies fetched before Atlantis
+// restarted are reused. The revision is kept until release is called.
+func (r *RemoteSourceResolver) Resolve(policySet valid.PolicySet) (string, string, func(), error) {
+	key := policySetCacheKey(policySet)
+	if revision, ok := r.acquireCurrentRevision(key); ok {
+		return r.policyPath(policySet, key, revision), revision, r.releaseFunc(key, revision), nil
+	}
+
+	fetchLock := r.fetchLock(key)
+	fetchLock.Lock()
+	defer fetchLock.Unlock()
+
+	// The policy set may have been fetched while we were waiting for the lock.
+	if revision, ok := r.acquireCurrentRevision(key); ok {
+		return r.policyPath(policySet, key, revision), revision, r.releaseFunc(key, revision), nil
+	}
+	if _, err := r.fetch(policySet, key); err != nil {
+		return "", "", nil, err
+	}
+	revision, _ := r.acquireCurrentRevision(key)
+	return r.policyPath(policySet, key, revision), revision, r.releaseFunc(key, revision), nil
+}
+
+// Refresh fetches policySet again so that changes to refs that move, ex.
+// branches, are picked up. It returns the revision that's now current.
+func (r *RemoteSourceResolver) Refresh(policySet valid.PolicySet) (string, error) {
+	key := policySetCacheKey(policySet)
+	fetchLock := r.fetchLock(key)
+	fetchLock.Lock()
+	defer fetchLock.Unlock()
+	return r.fetch(policySet, key)
+}
+
+// fetchLock returns the lock serializing the fetches of the policy set with
+// key.
+func (r *RemoteSourceResolver) fetchLock(key string) *sync.Mutex {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lock, ok := r.fetchLocks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		r.fetchLocks[key] = lock
+	}
+	return lock
+}
+
+// releaseFunc returns the func that stops using revision of the policy set
+// with key. The revision is removed if it's no longer current nor used.
+func (r *RemoteSourceResolver) releaseFunc(key string, revision string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			fetchLock := r.fetchLock(key)
+			fetchLock.Lock()
+			defer fetchLock.Unlock()
+
+			dir := filepath.Join(key, revisionDirName(revision))
+			r.mu.Lock()
+			r.inUse[dir]--
+			unused := r.inUse[dir] <= 0 && r.revisions[key] != revision
+			if r.inUse[dir] <= 0 {
+				delete(r.inUse, dir)
+			}
+			r.mu.Unlock()
+			if unused {
+				// Not being able to remove it isn't worth failing the check.
+				os.RemoveAll(filepath.Join(r.CacheDir, dir)) // nolint: errcheck
+			}
+		})
+	}
+}
+
+// fetch downloads policySet and makes it the current revision. Revisions
+// other than the new one and the ones in use are removed. Callers must hold
+// the policy set's fetch lock.
+func (r *RemoteSourceResolver) fetch(policySet valid.PolicySet, key string) (string, error) {
+	src, _ := getter.SourceDirSubdir(policySet.Path)
+	src, err := getterURL(policySet, src)
+	if err != nil {
+		return "", err
+	}
+
+	setDir := filepath.Join(r.CacheDir, key)
+	dst := filepath.Join(setDir, fetchDir)
+	if err := os.RemoveAll(dst); err != nil {
+		return "", errors.Wrapf(err, "cleaning up previous fetch of policy set %s", policySet.Name)
+	}
+	if err := os.MkdirAll(setDir, 0700); err != nil {
+		return "", errors.Wrapf(err, "creating cache dir for policy set %s", policySet.Name)
+	}
+	if err := r.Downloader.GetAny(dst, src); err != nil {
+		os.RemoveAll(dst) // nolint: errcheck
+		return "", errors.Wrapf(err, "fetching policy set %s from %q", policySet.Name, policySet.Path)
+	}
+
+	revision, err := policySetRevision(policySet, dst)
+	if err != nil {
+		os.RemoveAll(dst) // nolint: errcheck
+		return "", errors.Wrapf(err, "getting revision of policy set %s", policySet.Name)
+	}
+
+	revisionDir := filepath.Join(setDir, revisionDirName(revision))
+	if _, err := os.Stat(revisionDir); err == nil {
+		// We already have this revision.
+		if err := os.RemoveAll(dst); err != nil {
+			return "", err
+		}
+	} else if err := os.Rename(dst, revisionDir); err != nil {
+		return "", errors.Wrapf(err, "moving policy set %s into place", policySet.Name)
+	}
+	if err := os.WriteFile(filepath.Join(setDir, currentRevisionFile), []byte(revision), 0600); err != nil {
+		return "", errors.Wrapf(err, "writing revision of policy set %s", policySet.Name)
+	}
+
+	r.mu.Lock()
+	r.revisions[key] = revision
+	keep := []string{revisionDirName(revision)}
+	for dir := range r.inUse {
+		if filepath.Dir(dir) == key {
+			keep = append(keep, filepath.Base(dir))
+		}
+	}
+	r.mu.Unlock()
+
+	if err := pruneRevisions(setDir, keep...); err != nil {
+		return "", errors.Wrapf(err, "removing old revisions of policy set %s", policySet.Name)
+	}
+	return revision, nil
+}
+
+// acquireCurrentRevision returns the current revision of the policy set with
+// key and marks it used, loading it from the cache dir if it was fetched by a
+// previous run of Atlantis.
+func (r *RemoteSourceResolver) acquireCurrentRevision(key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	revision, ok := r.revisions[key]
+	if !ok {
+		setDir := filepath.Join(r.CacheDir, key)
+		contents, err := os.ReadFile(filepath.Join(setDir, currentRevisionFile))
+		if err != nil {
+			return "", false
+		}
+		revision = string(contents)
+		if _, err := os.Stat(filepath.Join(setDir, revisionDirName(revision))); err != nil {
+			return "", false
+		}
+		r.revisions[key] = revision
+	}
+	r.inUse[filepath.Join(key, revisionDirName(revision))]++
+	return revision, true
+}
+
+// policyPath returns the path to the policies of policySet at revision,
+// including the subdirectory of the source if there is one.
+func (r *RemoteSourceResolver) policyPath(policySet valid.PolicySet, key string, revision string) string {
+	_, subdir := getter.SourceDirSubdir(policySet.Path)
+	return filepath.Join(r.CacheDir, key, revisionDirName(revision), filepath.FromSlash(subdir))
+}
+
+// policySetCacheKey returns the name of the cache dir of policySet. It
+// includes a hash of the source so that changing the source of a policy set
+// doesn't reuse the old policies.
+func policySetCacheKey(policySet valid.PolicySet) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{policySet.Source, policySet.Path, policySet.Ref, policySet.Checksum}, "\n")))
+	return fmt.Sprintf("%s-%s", unsafePathChars.ReplaceAllString(policySet.Name, "_"), hex.EncodeToString(sum[:])[:12])
+}
+
+// getterURL returns the go-getter URL for src, which is the policy set's path
+// without its subdirectory.
+func getterURL(policySet valid.PolicySet, src string) (string, error) {
+	var param, value string
+	switch policySet.Source {
+	case valid.GitPolicySet:
+		if !strings.HasPrefix(src, "git::") {
+			src = "git::" + src
+		}
+		param, value = "ref", policySet.Ref
+	case valid.HTTPPolicySet:
+		param, value = "checksum", policySet.Checksum
+	default:
+		return "", fmt.Errorf("unable to fetch policy set source %s", policySet.Source)
+	}
+	if value == "" {
+		return src, nil
+	}
+	separator := "?"
+	if strings.Contains(src, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%s%s=%s", src, separator, param, url.QueryEscape(value)), nil
+}
+
+// policySetRevision returns the revision of the policy set fetched into dir.
+// For git policy sets it's the commit, otherwise it's the checksum of the
+// bundle or, if none is configured, of the policies.
+func policySetRevision(policySet valid.PolicySet, dir string) (string, error) {
+	if policySet.Source == valid.GitPolicySet {
+		cmd := exec.Command("git", "rev-parse", "HEAD") // nolint: gosec
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			return "", errors.Wrapf(err, "running git rev-parse HEAD: %s", out)
+		}
+		// The history isn't needed once we know the commit.
+		if err := os.RemoveAll(filepath.Join(dir, ".git")); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	if policySet.Checksum != "" {
+		return policySet.Checksum, nil
+	}
+	return dirChecksum(dir)
+}
+
+// dirChecksum returns the sha256 checksum of the paths and contents of the
+// files in dir.
+func dirChecksum(dir string) (string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(paths)
+
+	hash := sha256.New()
+	for _, p := range paths {
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\n", filepath.ToSlash(rel))
+		f, err := os.Open(p)
+		if err != nil {
+			return "", err
+		}
+		_, err = io.Copy(hash, f)
+		f.Close() // nolint: errcheck
+		if err != nil {
+			return "", err
+		}
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// revisionDirName returns the name of the directory revision is stored in.
+func revisionDirName(revision string) string {
+	return unsafePathChars.ReplaceAllString(revision, "_")
+}
+
+// pruneRevisions removes the revisions in setDir other than the ones in the
+// directories keep.
+func pruneRevisions(setDir string, keep ...string) error {
+	keepDirs := map[string]bool{
+		currentRevisionFile: true,
+		fetchDir:            true,
+	}
+	for _, dir := range keep {
+		keepDirs[dir] = true
+	}
+	entries, err := os.ReadDir(setDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if keepDirs[entry.Name()] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(setDir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PolicySetRefreshJob fetches a remote policy set again. It's run by the
+// scheduled executor service.
+type PolicySetRefreshJob struct {
+	Resolver  *RemoteSourceResolver
+	PolicySet valid.PolicySet
+	Logger    logging.SimpleLogging
+}
+
+func (j *PolicySetRefreshJob) Run() {
+	revision, err := j.Resolver.Refresh(j.PolicySet)
+	if err != nil {
+		j.Logger.Err("refreshing policy set %s: %s", j.PolicySet.Name, err)
+		return
+	}
+	j.Logger.Debug("refreshed policy set %s at revision %s", j.PolicySet.Name, revision)
+}
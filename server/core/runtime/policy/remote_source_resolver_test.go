@@ -0,0 +1,245 @@
+package policy
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	terraform_mocks "github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestRemoteSourceResolver_Git(t *testing.T) {
+	repoDir := t.TempDir()
+	runGit(t, repoDir, "init", "--initial-branch=main")
+	Ok(t, os.MkdirAll(filepath.Join(repoDir, "terraform"), 0700))
+	Ok(t, os.WriteFile(filepath.Join(repoDir, "terraform", "policy.rego"), []byte("package main\n"), 0600))
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "first")
+	firstCommit := runGit(t, repoDir, "rev-parse", "HEAD")
+
+	cacheDir := t.TempDir()
+	subject := NewRemoteSourceResolver(cacheDir, &terraform.DefaultDownloader{})
+	policySet := valid.PolicySet{
+		Name:   "git-policies",
+		Source: valid.GitPolicySet,
+		Path:   "file://" + repoDir + "//terraform",
+		Ref:    "main",
+	}
+
+	path, revision, release, err := subject.Resolve(policySet)
+	Ok(t, err)
+	Equals(t, firstCommit, revision)
+	Assert(t, strings.HasPrefix(path, cacheDir), "expected %q to be in the cache dir", path)
+	_, err = os.Stat(filepath.Join(path, "policy.rego"))
+	Ok(t, err)
+	_, err = os.Stat(filepath.Join(path, "..", ".git"))
+	Assert(t, os.IsNotExist(err), "expected .git to be removed")
+
+	// Resolving again uses the cached copy even if the branch moved.
+	Ok(t, os.WriteFile(filepath.Join(repoDir, "terraform", "other.rego"), []byte("package other\n"), 0600))
+	runGit(t, repoDir, "add", ".")
+	runGit(t, repoDir, "commit", "-m", "second")
+	secondCommit := runGit(t, repoDir, "rev-parse", "HEAD")
+
+	_, revision, releaseAgain, err := subject.Resolve(policySet)
+	Ok(t, err)
+	Equals(t, firstCommit, revision)
+	releaseAgain()
+
+	// Refreshing picks up the new commit and keeps the previous revision
+	// for policy checks that are still using it.
+	revision, err = subject.Refresh(policySet)
+	Ok(t, err)
+	Equals(t, secondCommit, revision)
+
+	newPath, revision, releaseNew, err := subject.Resolve(policySet)
+	Ok(t, err)
+	Equals(t, secondCommit, revision)
+	_, err = os.Stat(filepath.Join(newPath, "other.rego"))
+	Ok(t, err)
+	_, err = os.Stat(path)
+	Ok(t, err)
+
+	// The previous revision is removed once it's no longer used, but the
+	// current one is kept.
+	release()
+	_, err = os.Stat(path)
+	Assert(t, os.IsNotExist(err), "expected the previous revision to be removed")
+	releaseNew()
+	_, err = os.Stat(newPath)
+	Ok(t, err)
+}
+
+func TestRemoteSourceResolver_KeepsRevisionsInUse(t *testing.T) {
+	t.Log("revisions used by policy checks should survive any number of refreshes")
+	policies := "package main\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(policyBundle(t, map[string]string{"policy.rego": policies})) // nolint: errcheck
+	}))
+	defer server.Close()
+
+	subject := NewRemoteSourceResolver(t.TempDir(), &terraform.DefaultDownloader{})
+	policySet := valid.PolicySet{
+		Name:   "http-policies",
+		Source: valid.HTTPPolicySet,
+		Path:   server.URL + "/bundle.tar.gz",
+	}
+	path, _, release, err := subject.Resolve(policySet)
+	Ok(t, err)
+
+	for i := 0; i < 3; i++ {
+		policies = fmt.Sprintf("package main\n# %d\n", i)
+		_, err := subject.Refresh(policySet)
+		Ok(t, err)
+	}
+	_, err = os.Stat(filepath.Join(path, "policy.rego"))
+	Ok(t, err)
+
+	release()
+	_, err = os.Stat(path)
+	Assert(t, os.IsNotExist(err), "expected the revision to be removed once released")
+	entries, err := os.ReadDir(filepath.Dir(path))
+	Ok(t, err)
+	// The current revision and the file pointing at it.
+	Equals(t, 2, len(entries))
+}
+
+func TestRemoteSourceResolver_HTTP(t *testing.T) {
+	bundle := policyBundle(t, map[string]string{"policies/policy.rego": "package main\n"})
+	sum := sha256.Sum256(bundle)
+	checksum := "sha256:" + hex.EncodeToString(sum[:])
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bundle) // nolint: errcheck
+	}))
+	defer server.Close()
+
+	t.Run("checksum", func(t *testing.T) {
+		subject := NewRemoteSourceResolver(t.TempDir(), &terraform.DefaultDownloader{})
+		path, revision, _, err := subject.Resolve(valid.PolicySet{
+			Name:     "http-policies",
+			Source:   valid.HTTPPolicySet,
+			Path:     server.URL + "/bundle.tar.gz//policies",
+			Checksum: checksum,
+		})
+		Ok(t, err)
+		Equals(t, checksum, revision)
+		_, err = os.Stat(filepath.Join(path, "policy.rego"))
+		Ok(t, err)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		subject := NewRemoteSourceResolver(t.TempDir(), &terraform.DefaultDownloader{})
+		_, _, _, err := subject.Resolve(valid.PolicySet{
+			Name:     "http-policies",
+			Source:   valid.HTTPPolicySet,
+			Path:     server.URL + "/bundle.tar.gz",
+			Checksum: "sha256:" + strings.Repeat("0", 64),
+		})
+		ErrContains(t, "fetching policy set http-policies", err)
+	})
+
+	t.Run("no checksum", func(t *testing.T) {
+		subject := NewRemoteSourceResolver(t.TempDir(), &terraform.DefaultDownloader{})
+		_, revision, _, err := subject.Resolve(valid.PolicySet{
+			Name:   "http-policies",
+			Source: valid.HTTPPolicySet,
+			Path:   server.URL + "/bundle.tar.gz",
+		})
+		Ok(t, err)
+		Assert(t, strings.HasPrefix(revision, "sha256:"), "expected a checksum of the policies, got %q", revision)
+		Assert(t, revision != checksum, "expected a checksum of the policies, not the bundle")
+	})
+}
+
+func TestRemoteSourceResolver_ReusesCacheAfterRestart(t *testing.T) {
+	RegisterMockTestingT(t)
+	bundle := policyBundle(t, map[string]string{"policy.rego": "package main\n"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bundle) // nolint: errcheck
+	}))
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	policySet := valid.PolicySet{
+		Name:   "http-policies",
+		Source: valid.HTTPPolicySet,
+		Path:   server.URL + "/bundle.tar.gz",
+	}
+	path, revision, _, err := NewRemoteSourceResolver(cacheDir, &terraform.DefaultDownloader{}).Resolve(policySet)
+	Ok(t, err)
+
+	downloader := terraform_mocks.NewMockDownloader()
+	restarted := NewRemoteSourceResolver(cacheDir, downloader)
+	cachedPath, cachedRevision, _, err := restarted.Resolve(policySet)
+	Ok(t, err)
+	Equals(t, path, cachedPath)
+	Equals(t, revision, cachedRevision)
+	downloader.VerifyWasCalled(Never()).GetAny(Any[string](), Any[string]())
+}
+
+func TestGetterURL(t *testing.T) {
+	cases := []struct {
+		policySet valid.PolicySet
+		exp       string
+	}{
+		{
+			policySet: valid.PolicySet{Source: valid.GitPolicySet, Path: "https://github.com/org/policies.git"},
+			exp:       "git::https://github.com/org/policies.git",
+		},
+		{
+			policySet: valid.PolicySet{Source: valid.GitPolicySet, Path: "git::ssh://git@github.com/org/policies.git", Ref: "v1.0.0"},
+			exp:       "git::ssh://git@github.com/org/policies.git?ref=v1.0.0",
+		},
+		{
+			policySet: valid.PolicySet{Source: valid.HTTPPolicySet, Path: "https://example.com/policies.tar.gz?token=abc", Checksum: "sha256:123"},
+			exp:       "https://example.com/policies.tar.gz?token=abc&checksum=sha256%3A123",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.exp, func(t *testing.T) {
+			got, err := getterURL(c.policySet, c.policySet.Path)
+			Ok(t, err)
+			Equals(t, c.exp, got)
+		})
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=atlantis", "GIT_AUTHOR_EMAIL=atlantis@example.com",
+		"GIT_COMMITTER_NAME=atlantis", "GIT_COMMITTER_EMAIL=atlantis@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	Assert(t, err == nil, "git %s: %s", strings.Join(args, " "), out)
+	return strings.TrimSpace(string(out))
+}
+
+func policyBundle(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, contents := range files {
+		Ok(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(contents))}))
+		_, err := tw.Write([]byte(contents))
+		Ok(t, err)
+	}
+	Ok(t, tw.Close())
+	Ok(t, gz.Close())
+	return buf.Bytes()
+}
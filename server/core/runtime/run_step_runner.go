@@ -19,6 +19,9 @@ type RunStepRunner struct {
 	// TerraformBinDir is the directory where Atlantis downloads Terraform binaries.
 	TerraformBinDir         string
 	ProjectCmdOutputHandler jobs.ProjectCommandOutputHandler
+	// JobCanceller, if set, lets the commands be stopped when their job is
+	// cancelled.
+	JobCanceller *jobs.JobCanceller
 }
 
 func (r *RunStepRunner) Run(ctx command.ProjectContext, command string, path string, envs map[string]string, streamOutput bool) (string, error) {
@@ -67,7 +70,7 @@ func (r *RunStepRunner) Run(ctx command.ProjectContext, command string, path str
 		finalEnvVars = append(finalEnvVars, fmt.Sprintf("%s=%s", key, val))
 	}
 
-	runner := models.NewShellCommandRunner(command, finalEnvVars, path, streamOutput, r.ProjectCmdOutputHandler)
+	runner := models.NewShellCommandRunner(command, finalEnvVars, path, streamOutput, r.ProjectCmdOutputHandler, r.JobCanceller)
 	output, err := runner.Run(ctx)
 
 	if err != nil {
@@ -0,0 +1,292 @@
+package terraform
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+//go:generate pegomock generate --package mocks -o mocks/mock_run_queue_notifier.go RunQueueNotifier
+
+// RunQueueNotifier is told about runs that have to wait for a slot so it can
+// show their position, ex. in the commit status.
+type RunQueueNotifier interface {
+	// RunQueued is called when the run for ctx has to wait and each time its
+	// position in the queue changes. Positions start at 1.
+	RunQueued(ctx command.ProjectContext, position int) error
+	// RunDequeued is called when a run that had to wait gets a slot, before
+	// the run starts. RunQueued isn't called for the run after it.
+	RunDequeued(ctx command.ProjectContext) error
+}
+
+// QueuedRun is a run waiting for a slot.
+type QueuedRun struct {
+	Repo       string    `json:"repo"`
+	Pull       int       `json:"pull"`
+	Command    string    `json:"command"`
+	Project    string    `json:"project"`
+	Position   int       `json:"position"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+}
+
+// RunSchedulerStatus is a snapshot of the scheduler.
+type RunSchedulerStatus struct {
+	MaxRuns        int         `json:"max_runs"`
+	MaxRunsPerRepo int         `json:"max_runs_per_repo"`
+	Running        int         `json:"running"`
+	Queued         []QueuedRun `json:"queued"`
+}
+
+// RunScheduler limits how many project commands run at once across all pull
+// requests. A run holds its slot for all the steps of the command. Runs that don't get a slot wait in a queue where applies
+// are ahead of every other command and runs are otherwise first come, first
+// served.
+type RunScheduler struct {
+	// maxRuns is how many runs can happen at once. 0 means no limit.
+	maxRuns int
+	// maxRunsPerRepo is how many runs can happen at once in a single repo.
+	// 0 means no limit.
+	maxRunsPerRepo int
+	notifier       RunQueueNotifier
+
+	mu            sync.Mutex
+	running       int
+	runningByRepo map[string]int
+	queue         []*scheduledRun
+	nextSeq       uint64
+	// pending are the positions the notifier hasn't been told about yet, in
+	// the order they changed.
+	pending      map[*scheduledRun]int
+	pendingOrder []*scheduledRun
+	// wake is signalled when there are pending notifications.
+	wake chan struct{}
+}
+
+type scheduledRun struct {
+	ctx        command.ProjectContext
+	repo       string
+	priority   int
+	seq        uint64
+	enqueuedAt time.Time
+	position   int
+	// ready is closed when the run gets a slot.
+	ready chan struct{}
+
+	// notifyMu is held while the notifier is told about the run so the
+	// notifications about it are sent one at a time.
+	notifyMu sync.Mutex
+	// dequeued is true once the notifier has been told the run got a slot.
+	// Positions that arrive later are dropped. notifyMu must be held.
+	dequeued bool
+}
+
+// NewRunScheduler returns a scheduler allowing maxRuns runs at once and at
+// most maxRunsPerRepo per repo. Zero means no limit. notifier can be nil.
+func NewRunScheduler(maxRuns int, maxRunsPerRepo int, notifier RunQueueNotifier) *RunScheduler {
+	s := &RunScheduler{
+		maxRuns:        maxRuns,
+		maxRunsPerRepo: maxRunsPerRepo,
+		notifier:       notifier,
+		runningByRepo:  make(map[string]int),
+		pending:        make(map[*scheduledRun]int),
+		wake:           make(chan struct{}, 1),
+	}
+	if notifier != nil {
+		go s.notifyLoop()
+	}
+	return s
+}
+
+// Acquire blocks until the run for ctx gets a slot. The returned func must be
+// called once the run has finished to free the slot.
+func (s *RunScheduler) Acquire(ctx command.ProjectContext) (release func()) {
+	run := &scheduledRun{
+		ctx:        ctx,
+		repo:       ctx.BaseRepo.FullName,
+		priority:   runPriority(ctx.CommandName),
+		enqueuedAt: time.Now(),
+		ready:      make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	run.seq = s.nextSeq
+	s.nextSeq++
+	if len(s.queue) == 0 && s.hasSlot(run.repo) {
+		s.start(run)
+		s.mu.Unlock()
+		return s.releaseFunc(run)
+	}
+	s.queue = append(s.queue, run)
+	sort.SliceStable(s.queue, func(i, j int) bool {
+		if s.queue[i].priority != s.queue[j].priority {
+			return s.queue[i].priority > s.queue[j].priority
+		}
+		return s.queue[i].seq < s.queue[j].seq
+	})
+	// The run might be able to start right away if the runs ahead of it are
+	// waiting on their repo's limit.
+	s.schedule()
+	s.mu.Unlock()
+
+	<-run.ready
+	if run.position != 0 {
+		s.notifyDequeued(run)
+	}
+	return s.releaseFunc(run)
+}
+
+// Status returns a snapshot of the runs in progress and queued.
+func (s *RunScheduler) Status() RunSchedulerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := RunSchedulerStatus{
+		MaxRuns:        s.maxRuns,
+		MaxRunsPerRepo: s.maxRunsPerRepo,
+		Running:        s.running,
+		Queued:         []QueuedRun{},
+	}
+	for i, run := range s.queue {
+		status.Queued = append(status.Queued, QueuedRun{
+			Repo:       run.repo,
+			Pull:       run.ctx.Pull.Num,
+			Command:    run.ctx.CommandName.String(),
+			Project:    projectName(run.ctx),
+			Position:   i + 1,
+			EnqueuedAt: run.enqueuedAt,
+		})
+	}
+	return status
+}
+
+func (s *RunScheduler) releaseFunc(run *scheduledRun) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			s.running--
+			s.runningByRepo[run.repo]--
+			if s.runningByRepo[run.repo] == 0 {
+				delete(s.runningByRepo, run.repo)
+			}
+			s.schedule()
+			s.mu.Unlock()
+		})
+	}
+}
+
+// schedule starts the queued runs that have a slot and updates the positions
+// of the rest. s.mu must be held.
+func (s *RunScheduler) schedule() {
+	var waiting []*scheduledRun
+	for _, run := range s.queue {
+		if s.hasSlot(run.repo) {
+			s.start(run)
+			continue
+		}
+		waiting = append(waiting, run)
+	}
+	s.queue = waiting
+
+	for i, run := range s.queue {
+		if run.position != i+1 {
+			run.position = i + 1
+			s.queueNotification(run, run.position)
+		}
+	}
+}
+
+// hasSlot returns true if a run in repo can start. s.mu must be held.
+func (s *RunScheduler) hasSlot(repo string) bool {
+	if s.maxRuns > 0 && s.running >= s.maxRuns {
+		return false
+	}
+	if s.maxRunsPerRepo > 0 && s.runningByRepo[repo] >= s.maxRunsPerRepo {
+		return false
+	}
+	return true
+}
+
+// start gives run a slot. s.mu must be held.
+func (s *RunScheduler) start(run *scheduledRun) {
+	s.running++
+	s.runningByRepo[run.repo]++
+	close(run.ready)
+}
+
+// queueNotification records that run's position changed. s.mu must be held.
+func (s *RunScheduler) queueNotification(run *scheduledRun, position int) {
+	if s.notifier == nil {
+		return
+	}
+	if _, ok := s.pending[run]; !ok {
+		s.pendingOrder = append(s.pendingOrder, run)
+	}
+	s.pending[run] = position
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// notifyLoop tells the notifier about position changes. It runs in its own
+// goroutine because notifiers usually call the VCS host and we don't want to
+// hold up runs. Changes that happen while it's busy are coalesced so only the
+// latest position of each run is sent.
+func (s *RunScheduler) notifyLoop() {
+	for range s.wake {
+		s.mu.Lock()
+		order, pending := s.pendingOrder, s.pending
+		s.pendingOrder, s.pending = nil, make(map[*scheduledRun]int)
+		s.mu.Unlock()
+
+		for _, run := range order {
+			s.notifyQueued(run, pending[run])
+		}
+	}
+}
+
+// notifyQueued tells the notifier about run's position unless run already
+// has a slot.
+func (s *RunScheduler) notifyQueued(run *scheduledRun, position int) {
+	run.notifyMu.Lock()
+	defer run.notifyMu.Unlock()
+	if run.dequeued {
+		return
+	}
+	if err := s.notifier.RunQueued(run.ctx, position); err != nil {
+		run.ctx.Log.Warn("unable to update queue position: %s", err)
+	}
+}
+
+// notifyDequeued tells the notifier run got a slot. Unlike positions, it's
+// sent before the run starts and after any position that's being sent, so
+// nothing about the queue can arrive after the run has reported its result.
+func (s *RunScheduler) notifyDequeued(run *scheduledRun) {
+	if s.notifier == nil {
+		return
+	}
+	run.notifyMu.Lock()
+	defer run.notifyMu.Unlock()
+	run.dequeued = true
+	if err := s.notifier.RunDequeued(run.ctx); err != nil {
+		run.ctx.Log.Warn("unable to update queue position: %s", err)
+	}
+}
+
+// runPriority returns how far ahead runs of cmdName go in the queue. Applies
+// go first because a plan that's been approved is more urgent than a new one.
+func runPriority(cmdName command.Name) int {
+	if cmdName == command.Apply {
+		return 1
+	}
+	return 0
+}
+
+func projectName(ctx command.ProjectContext) string {
+	if ctx.ProjectName != "" {
+		return ctx.ProjectName
+	}
+	return ctx.RepoRelDir + "/" + ctx.Workspace
+}
@@ -0,0 +1,177 @@
+package terraform_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestRunScheduler_NoLimit(t *testing.T) {
+	s := terraform.NewRunScheduler(0, 0, nil)
+	var releases []func()
+	for i := 0; i < 10; i++ {
+		releases = append(releases, s.Acquire(runCtx("owner/repo", 1, command.Plan)))
+	}
+	Equals(t, 10, s.Status().Running)
+	for _, release := range releases {
+		release()
+	}
+	Equals(t, 0, s.Status().Running)
+}
+
+func TestRunScheduler_MaxRuns(t *testing.T) {
+	RegisterMockTestingT(t)
+	notifier := mocks.NewMockRunQueueNotifier()
+	s := terraform.NewRunScheduler(1, 0, notifier)
+	release := s.Acquire(runCtx("owner/repo", 1, command.Plan))
+
+	queuedCtx := runCtx("owner/other", 2, command.Plan)
+	acquired := acquireAsync(s, queuedCtx)
+	waitForQueued(t, s, 1)
+	Equals(t, terraform.QueuedRun{
+		Repo:       "owner/other",
+		Pull:       2,
+		Command:    "plan",
+		Project:    "./default",
+		Position:   1,
+		EnqueuedAt: s.Status().Queued[0].EnqueuedAt,
+	}, s.Status().Queued[0])
+	notifier.VerifyWasCalledEventually(Once(), time.Second).RunQueued(queuedCtx, 1)
+
+	release()
+	(<-acquired)()
+	notifier.VerifyWasCalledEventually(Once(), time.Second).RunDequeued(queuedCtx)
+	Equals(t, 0, s.Status().Running)
+}
+
+func TestRunScheduler_NotifiesDequeuedLast(t *testing.T) {
+	notifier := &blockingNotifier{queued: make(chan struct{}), unblock: make(chan struct{})}
+	s := terraform.NewRunScheduler(1, 0, notifier)
+	release := s.Acquire(runCtx("owner/repo", 1, command.Plan))
+
+	acquired := acquireAsync(s, runCtx("owner/other", 2, command.Plan))
+	<-notifier.queued
+
+	t.Log("the run shouldn't start while its position is being sent")
+	release()
+	select {
+	case <-acquired:
+		t.Fatal("exp run to wait for its position to be sent")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(notifier.unblock)
+	(<-acquired)()
+
+	t.Log("nothing should be sent once the run has been dequeued")
+	time.Sleep(100 * time.Millisecond)
+	Equals(t, []string{"queued 1", "dequeued"}, notifier.sent())
+}
+
+func TestRunScheduler_AppliesFirst(t *testing.T) {
+	s := terraform.NewRunScheduler(1, 0, nil)
+	release := s.Acquire(runCtx("owner/repo", 1, command.Plan))
+
+	planAcquired := acquireAsync(s, runCtx("owner/repo", 2, command.Plan))
+	waitForQueued(t, s, 1)
+	applyAcquired := acquireAsync(s, runCtx("owner/repo", 3, command.Apply))
+	waitForQueued(t, s, 2)
+	Equals(t, 3, s.Status().Queued[0].Pull)
+	Equals(t, 2, s.Status().Queued[1].Pull)
+
+	release()
+	releaseApply := <-applyAcquired
+	select {
+	case <-planAcquired:
+		t.Fatal("plan shouldn't run before the queued apply has finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+	releaseApply()
+	(<-planAcquired)()
+}
+
+func TestRunScheduler_MaxRunsPerRepo(t *testing.T) {
+	s := terraform.NewRunScheduler(0, 1, nil)
+	release := s.Acquire(runCtx("owner/repo", 1, command.Plan))
+
+	sameRepoAcquired := acquireAsync(s, runCtx("owner/repo", 2, command.Plan))
+	waitForQueued(t, s, 1)
+
+	t.Log("runs in other repos shouldn't wait behind the queued run")
+	s.Acquire(runCtx("owner/other", 3, command.Plan))()
+
+	release()
+	(<-sameRepoAcquired)()
+	Equals(t, 0, s.Status().Running)
+}
+
+func runCtx(repo string, pullNum int, cmdName command.Name) command.ProjectContext {
+	return command.ProjectContext{
+		BaseRepo:    models.Repo{FullName: repo},
+		Pull:        models.PullRequest{Num: pullNum},
+		CommandName: cmdName,
+		RepoRelDir:  ".",
+		Workspace:   "default",
+	}
+}
+
+// acquireAsync acquires a slot for ctx in the background. The release func is
+// sent on the returned channel once the slot is acquired.
+func acquireAsync(s *terraform.RunScheduler, ctx command.ProjectContext) <-chan func() {
+	acquired := make(chan func(), 1)
+	go func() {
+		acquired <- s.Acquire(ctx)
+	}()
+	return acquired
+}
+
+func waitForQueued(t *testing.T, s *terraform.RunScheduler, n int) {
+	for i := 0; i < 100; i++ {
+		if len(s.Status().Queued) == n {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("expected %d queued runs, got %d", n, len(s.Status().Queued))
+}
+
+// blockingNotifier records the notifications it's sent. RunQueued signals
+// queued and blocks until unblock is closed.
+type blockingNotifier struct {
+	queued  chan struct{}
+	unblock chan struct{}
+
+	mu            sync.Mutex
+	notifications []string
+}
+
+func (b *blockingNotifier) RunQueued(_ command.ProjectContext, position int) error {
+	b.record(fmt.Sprintf("queued %d", position))
+	b.queued <- struct{}{}
+	<-b.unblock
+	return nil
+}
+
+func (b *blockingNotifier) RunDequeued(_ command.ProjectContext) error {
+	b.record("dequeued")
+	return nil
+}
+
+func (b *blockingNotifier) record(notification string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.notifications = append(b.notifications, notification)
+}
+
+func (b *blockingNotifier) sent() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.notifications...)
+}
@@ -91,6 +91,10 @@ type DefaultClient struct {
 	usePluginCache bool
 
 	projectCmdOutputHandler jobs.ProjectCommandOutputHandler
+
+	// jobCanceller lets terraform processes be stopped when their job is
+	// cancelled. If nil, they can't be cancelled.
+	jobCanceller *jobs.JobCanceller
 }
 
 //go:generate pegomock generate --package mocks -o mocks/mock_downloader.go Downloader
@@ -267,6 +271,12 @@ func NewClient(
 	)
 }
 
+// SetJobCanceller makes terraform processes stop when their job is cancelled
+// through j.
+func (c *DefaultClient) SetJobCanceller(j *jobs.JobCanceller) {
+	c.jobCanceller = j
+}
+
 // Version returns the default version of Terraform we use if no other version
 // is defined.
 func (c *DefaultClient) DefaultVersion() *version.Version {
@@ -415,7 +425,7 @@ func (c *DefaultClient) RunCommandWithVersion(ctx command.ProjectContext, path s
 	}
 	cmd.Env = envVars
 	start := time.Now()
-	out, err := cmd.CombinedOutput()
+	out, err := models.CombinedOutput(ctx, cmd, c.jobCanceller)
 	dur := time.Since(start)
 	log := ctx.Log.With("duration", dur)
 	if err != nil {
@@ -509,9 +519,8 @@ func (c *DefaultClient) RunCommandAsync(ctx command.ProjectContext, path string,
 		envVars = append(envVars, fmt.Sprintf("%s=%s", key, val))
 	}
 
-	runner := models.NewShellCommandRunner(cmd, envVars, path, true, c.projectCmdOutputHandler)
-	inCh, outCh := runner.RunCommandAsync(ctx)
-	return inCh, outCh
+	runner := models.NewShellCommandRunner(cmd, envVars, path, true, c.projectCmdOutputHandler, c.jobCanceller)
+	return runner.RunCommandAsync(ctx)
 }
 
 // MustConstraint will parse one or more constraints from the given
@@ -1,6 +1,9 @@
 package events
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
@@ -22,6 +25,8 @@ func NewApplyCommandRunner(
 	SilenceNoProjects bool,
 	silenceVCSStatusNoProjects bool,
 	pullReqStatusFetcher vcs.PullReqStatusFetcher,
+	planStalenessChecker PlanStalenessChecker,
+	workingDirLocker WorkingDirLocker,
 ) *ApplyCommandRunner {
 	return &ApplyCommandRunner{
 		vcsClient:                  vcsClient,
@@ -38,6 +43,8 @@ func NewApplyCommandRunner(
 		SilenceNoProjects:          SilenceNoProjects,
 		silenceVCSStatusNoProjects: silenceVCSStatusNoProjects,
 		pullReqStatusFetcher:       pullReqStatusFetcher,
+		planStalenessChecker:       planStalenessChecker,
+		workingDirLocker:           workingDirLocker,
 	}
 }
 
@@ -54,6 +61,8 @@ type ApplyCommandRunner struct {
 	dbUpdater            *DBUpdater
 	parallelPoolSize     int
 	pullReqStatusFetcher vcs.PullReqStatusFetcher
+	planStalenessChecker PlanStalenessChecker
+	workingDirLocker     WorkingDirLocker
 	// SilenceNoProjects is whether Atlantis should respond to PRs if no projects
 	// are found
 	SilenceNoProjects bool
@@ -157,6 +166,16 @@ func (a *ApplyCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
 		return
 	}
 
+	// Don't apply any project if one of the plans is stale so that we don't
+	// leave the pull request partially applied.
+	if failure := a.stalePlansFailure(ctx, projectCmds); failure != "" {
+		if statusErr := a.commitStatusUpdater.UpdateCombined(ctx.Pull.BaseRepo, ctx.Pull, models.FailedCommitStatus, cmd.CommandName()); statusErr != nil {
+			ctx.Log.Warn("unable to update commit status: %s", statusErr)
+		}
+		a.pullUpdater.updatePull(ctx, cmd, command.Result{Failure: failure})
+		return
+	}
+
 	// Only run commands in parallel if enabled
 	var result command.Result
 	if a.isParallelEnabled(projectCmds) {
@@ -184,6 +203,41 @@ func (a *ApplyCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
 	}
 }
 
+// stalePlansFailure returns a failure listing the projects whose plans are
+// too old or out of date with the base branch, or "" if all of them can be
+// applied. The projects that were checked are marked so they aren't checked
+// again when they're applied. Projects whose plans can't be checked are
+// checked again, or fail on their own, when they're applied.
+func (a *ApplyCommandRunner) stalePlansFailure(ctx *command.Context, projectCmds []command.ProjectContext) string {
+	var reasons []string
+	for i, projectCmd := range projectCmds {
+		reason, err := a.stalePlanReason(projectCmd)
+		if err != nil {
+			ctx.Log.Warn("unable to check if plan for dir %q workspace %q is stale: %s", projectCmd.RepoRelDir, projectCmd.Workspace, err)
+			continue
+		}
+		projectCmds[i].PlanStalenessChecked = true
+		if reason != "" {
+			reasons = append(reasons, fmt.Sprintf("- dir: `%s` workspace: `%s`: %s", projectCmd.RepoRelDir, projectCmd.Workspace, reason))
+		}
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Not applying because some plans are stale:\n\n%s", strings.Join(reasons, "\n"))
+}
+
+// stalePlanReason checks the plan of projectCmd while holding its working
+// dir's lock so checking doesn't race a plan of the same project.
+func (a *ApplyCommandRunner) stalePlanReason(projectCmd command.ProjectContext) (string, error) {
+	unlockFn, err := a.workingDirLocker.TryLock(projectCmd.Pull.BaseRepo.FullName, projectCmd.Pull.Num, projectCmd.Workspace, projectCmd.RepoRelDir)
+	if err != nil {
+		return "", err
+	}
+	defer unlockFn()
+	return a.planStalenessChecker.StalePlanReason(projectCmd)
+}
+
 func (a *ApplyCommandRunner) IsLocked() (bool, error) {
 	lock, err := a.locker.CheckApplyLock()
 
@@ -491,7 +491,10 @@ func TestApplyCommandRunner_ExecutionOrder(t *testing.T) {
 
 			When(projectCommandBuilder.BuildApplyCommands(ctx, cmd)).ThenReturn(c.ProjectContexts, nil)
 			for i := range c.ProjectContexts {
-				When(projectCommandRunner.Apply(c.ProjectContexts[i])).ThenReturn(c.ProjectResults[i])
+				// The plans are checked for staleness before they're applied.
+				checked := c.ProjectContexts[i]
+				checked.PlanStalenessChecked = true
+				When(projectCommandRunner.Apply(checked)).ThenReturn(c.ProjectResults[i])
 			}
 
 			applyCommandRunner.Run(ctx, cmd)
@@ -507,3 +510,51 @@ func TestApplyCommandRunner_ExecutionOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyCommandRunner_StalePlans(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	RegisterMockTestingT(t)
+	vcsClient := setup(t)
+
+	scopeNull, _, _ := metrics.NewLoggingScope(logger, "atlantis")
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	cmd := &events.CommentCommand{Name: command.Apply}
+	ctx := &command.Context{
+		User:     testdata.User,
+		Log:      logging.NewNoopLogger(t),
+		Scope:    scopeNull,
+		Pull:     modelPull,
+		HeadRepo: testdata.GithubRepo,
+		Trigger:  command.CommentTrigger,
+	}
+	projectCmds := []command.ProjectContext{
+		{
+			CommandName: command.Apply,
+			RepoRelDir:  "fresh",
+			Workspace:   "default",
+		},
+		{
+			CommandName: command.Apply,
+			RepoRelDir:  "stale",
+			Workspace:   "default",
+		},
+	}
+
+	When(projectCommandBuilder.BuildApplyCommands(ctx, cmd)).ThenReturn(projectCmds, nil)
+	When(planStalenessChecker.StalePlanReason(projectCmds[1])).ThenReturn("Plan is too old.", nil)
+
+	applyCommandRunner.Run(ctx, cmd)
+
+	projectCommandRunner.VerifyWasCalled(Never()).Apply(Any[command.ProjectContext]())
+	commitUpdater.VerifyWasCalledOnce().UpdateCombined(
+		Any[models.Repo](),
+		Any[models.PullRequest](),
+		Eq[models.CommitStatus](models.FailedCommitStatus),
+		Eq[command.Name](command.Apply),
+	)
+	vcsClient.VerifyWasCalledOnce().CreateComment(
+		testdata.GithubRepo, modelPull.Num,
+		"**Apply Failed**: Not applying because some plans are stale:\n\n- dir: `stale` workspace: `default`: Plan is too old.",
+		"apply",
+	)
+}
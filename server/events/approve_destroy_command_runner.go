@@ -0,0 +1,90 @@
+package events
+
+import (
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+func NewApproveDestroyCommandRunner(
+	prjCommandBuilder ProjectApproveDestroyCommandBuilder,
+	prjCommandRunner ProjectApproveDestroyCommandRunner,
+	pullUpdater *PullUpdater,
+	dbUpdater *DBUpdater,
+	SilenceNoProjects bool,
+) *ApproveDestroyCommandRunner {
+	return &ApproveDestroyCommandRunner{
+		prjCmdBuilder:     prjCommandBuilder,
+		prjCmdRunner:      prjCommandRunner,
+		pullUpdater:       pullUpdater,
+		dbUpdater:         dbUpdater,
+		SilenceNoProjects: SilenceNoProjects,
+	}
+}
+
+// ApproveDestroyCommandRunner records an owner's approval to destroy the
+// protected resources in the current plans. The approval is checked by the
+// destroy_approved apply requirement.
+type ApproveDestroyCommandRunner struct {
+	pullUpdater   *PullUpdater
+	dbUpdater     *DBUpdater
+	prjCmdBuilder ProjectApproveDestroyCommandBuilder
+	prjCmdRunner  ProjectApproveDestroyCommandRunner
+	// SilenceNoProjects is whether Atlantis should respond to PRs if no projects
+	// are found
+	SilenceNoProjects bool
+}
+
+func (a *ApproveDestroyCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
+	projectCmds, err := a.prjCmdBuilder.BuildApproveDestroyCommands(ctx, cmd)
+	if err != nil {
+		a.pullUpdater.updatePull(ctx, cmd, command.Result{Error: err})
+		return
+	}
+
+	if len(projectCmds) == 0 && a.SilenceNoProjects {
+		ctx.Log.Info("determined there was no project to run approve_destroy in")
+		return
+	}
+
+	result := runProjectCmds(projectCmds, a.prjCmdRunner.ApproveDestroy)
+
+	a.pullUpdater.updatePull(
+		ctx,
+		cmd,
+		result,
+	)
+
+	planned, err := a.plannedResults(ctx, result.ProjectResults)
+	if err != nil {
+		ctx.Log.Err("getting pull status: %s", err)
+		return
+	}
+	if len(planned) == 0 {
+		return
+	}
+	if _, err := a.dbUpdater.updateDB(ctx, ctx.Pull, planned); err != nil {
+		ctx.Log.Err("writing results: %s", err)
+	}
+}
+
+// plannedResults returns the results of the projects that have been planned
+// at the pull request's head commit. Approving a destroy of a project that
+// hasn't been planned doesn't change its status, so the other results aren't
+// written to the database.
+func (a *ApproveDestroyCommandRunner) plannedResults(ctx *command.Context, results []command.ProjectResult) ([]command.ProjectResult, error) {
+	status, err := a.dbUpdater.Backend.GetPullStatus(ctx.Pull)
+	if err != nil {
+		return nil, err
+	}
+	if status == nil || status.Pull.HeadCommit != ctx.Pull.HeadCommit {
+		return nil, nil
+	}
+	var planned []command.ProjectResult
+	for _, r := range results {
+		if status.FindProject(r.RepoRelDir, r.Workspace, r.ProjectName) == nil {
+			ctx.Log.Debug("not saving approval of project at dir %q workspace %q because it hasn't been planned", r.RepoRelDir, r.Workspace)
+			continue
+		}
+		planned = append(planned, r)
+	}
+	return planned, nil
+}
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/jobs"
+)
+
+func NewCancelCommandRunner(
+	jobCanceller *jobs.JobCanceller,
+	vcsClient vcs.Client,
+) *CancelCommandRunner {
+	return &CancelCommandRunner{
+		jobCanceller: jobCanceller,
+		vcsClient:    vcsClient,
+	}
+}
+
+// CancelCommandRunner stops the jobs that are running for a pull request.
+// The cancelled projects are marked as errored by the project command runner.
+type CancelCommandRunner struct {
+	jobCanceller *jobs.JobCanceller
+	vcsClient    vcs.Client
+}
+
+func (c *CancelCommandRunner) Run(
+	ctx *command.Context,
+	cmd *CommentCommand,
+) {
+	baseRepo := ctx.Pull.BaseRepo
+	pullNum := ctx.Pull.Num
+
+	cancelled := c.jobCanceller.CancelPullJobs(baseRepo.FullName, pullNum, ctx.User.Username)
+	var projectResults []command.ProjectResult
+	for _, job := range cancelled {
+		projectResults = append(projectResults, command.ProjectResult{
+			Command:     command.Cancel,
+			ProjectName: job.ProjectName,
+			RepoRelDir:  job.RepoRelDir,
+			Workspace:   job.Workspace,
+		})
+	}
+	ctx.RunResults = append(ctx.RunResults, command.RunResult{
+		Name:   command.Cancel,
+		Result: command.Result{ProjectResults: projectResults},
+	})
+
+	if commentErr := c.vcsClient.CreateComment(baseRepo, pullNum, cancelComment(cancelled), command.Cancel.String()); commentErr != nil {
+		ctx.Log.Err("unable to comment: %s", commentErr)
+	}
+}
+
+// cancelComment returns the comment listing the cancelled jobs.
+func cancelComment(cancelled []jobs.CancelledJob) string {
+	if len(cancelled) == 0 {
+		return "There are no running Atlantis jobs for this PR to cancel."
+	}
+	var b strings.Builder
+	b.WriteString("Cancelled the running Atlantis jobs for this PR:\n")
+	for _, job := range cancelled {
+		fmt.Fprintf(&b, "\n* dir: `%s` workspace: `%s`", job.RepoRelDir, job.Workspace)
+		if job.ProjectName != "" {
+			fmt.Fprintf(&b, " project: `%s`", job.ProjectName)
+		}
+	}
+	return b.String()
+}
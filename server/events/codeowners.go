@@ -0,0 +1,82 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+
+	"github.com/moby/patternmatcher"
+	"github.com/pkg/errors"
+)
+
+// CodeOwnersPaths are where we look for the CODEOWNERS file, in order.
+var CodeOwnersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", ".gitlab/CODEOWNERS", "docs/CODEOWNERS"}
+
+// CodeOwners is a parsed CODEOWNERS file.
+type CodeOwners struct {
+	rules []codeOwnersRule
+}
+
+type codeOwnersRule struct {
+	matcher *patternmatcher.PatternMatcher
+	owners  []string
+}
+
+// ParseCodeOwners parses the content of a CODEOWNERS file. Each line is a
+// gitignore style pattern followed by its owners, ex. "/modules/ @org/team".
+func ParseCodeOwners(content []byte) (*CodeOwners, error) {
+	var codeOwners CodeOwners
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		// GitLab section headers, ex. "[Docs]", apply to the rules after them
+		// which we treat like any other rule.
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		matcher, err := patternmatcher.New([]string{codeOwnersPattern(fields[0])})
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing CODEOWNERS pattern %q", fields[0])
+		}
+		codeOwners.rules = append(codeOwners.rules, codeOwnersRule{
+			matcher: matcher,
+			owners:  fields[1:],
+		})
+	}
+	return &codeOwners, scanner.Err()
+}
+
+// Owners returns the owners of file, a path relative to the repo root. The
+// last matching rule wins so it returns nil if that rule has no owners or no
+// rule matches.
+func (c *CodeOwners) Owners(file string) []string {
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		// The patterns were validated when parsing so we can ignore the error.
+		if match, _ := c.rules[i].matcher.MatchesOrParentMatches(file); match {
+			if len(c.rules[i].owners) == 0 {
+				return nil
+			}
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// codeOwnersPattern converts a CODEOWNERS pattern into a pattern matched
+// against paths relative to the repo root. Like .gitignore, patterns without
+// a slash except at the end match at any depth.
+func codeOwnersPattern(pattern string) string {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" || pattern == "*" {
+		return "**"
+	}
+	if !anchored {
+		pattern = "**/" + pattern
+	}
+	return pattern
+}
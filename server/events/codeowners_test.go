@@ -0,0 +1,53 @@
+package events_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestCodeOwners_Owners(t *testing.T) {
+	codeOwners, err := events.ParseCodeOwners([]byte(`# Default owners.
+*                 @org/platform
+
+*.md              @org/docs  # Docs can be anywhere.
+/network/         @alice @bob
+modules/          @carol
+/network/legacy/
+build/logs        dev@example.com
+
+[Security]
+/iam/             @org/security
+`))
+	Ok(t, err)
+
+	cases := []struct {
+		file string
+		exp  []string
+	}{
+		{"main.tf", []string{"@org/platform"}},
+		{"README.md", []string{"@org/docs"}},
+		{"docs/README.md", []string{"@org/docs"}},
+		{"modules/vpc/README.md", []string{"@carol"}},
+		{"network/main.tf", []string{"@alice", "@bob"}},
+		{"network/vpc/main.tf", []string{"@alice", "@bob"}},
+		{"other/network/main.tf", []string{"@org/platform"}},
+		{"modules/vpc/main.tf", []string{"@carol"}},
+		{"network/legacy/main.tf", nil},
+		{"build/logs/out.log", []string{"dev@example.com"}},
+		{"nested/build/logs/out.log", []string{"@org/platform"}},
+		{"iam/roles.tf", []string{"@org/security"}},
+	}
+	for _, c := range cases {
+		t.Run(c.file, func(t *testing.T) {
+			Equals(t, c.exp, codeOwners.Owners(c.file))
+		})
+	}
+}
+
+func TestCodeOwners_NoRules(t *testing.T) {
+	codeOwners, err := events.ParseCodeOwners([]byte("# Nothing is owned.\n"))
+	Ok(t, err)
+	Equals(t, []string(nil), codeOwners.Owners("main.tf"))
+}
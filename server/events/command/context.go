@@ -42,5 +42,27 @@ type Context struct {
 	// ClearPolicyApproval is true if approval should be cleared on specified policies.
 	ClearPolicyApproval bool
 
+	// Teams caches the teams of the users that the command looks up.
+	Teams *TeamCache
+
+	// Targets are the resource addresses the plan command is limited to.
+	Targets []string
+
+	// Replaces are the resource addresses the plan command forces to be
+	// replaced.
+	Replaces []string
+
 	Trigger Trigger
+
+	// RunResults are the results of the commands run for this context in the
+	// order they finished, ex. plan and then policy_check. They're used to
+	// audit the commands.
+	RunResults []RunResult
+}
+
+// RunResult is the result of a command run for a Context.
+type RunResult struct {
+	Name       Name
+	SubCommand string
+	Result
 }
@@ -30,6 +30,11 @@ const (
 	Import
 	// State is a command to run terraform state rm
 	State
+	// ApproveDestroy is a command to approve the destruction of protected
+	// resources with owner check
+	ApproveDestroy
+	// Cancel is a command to cancel the running jobs of a pull request.
+	Cancel
 	// Adding more? Don't forget to update String() below
 )
 
@@ -45,8 +50,10 @@ var AllCommentCommands = []Name{
 	Apply,
 	Unlock,
 	ApprovePolicies,
+	ApproveDestroy,
 	Import,
 	State,
+	Cancel,
 }
 
 // TitleString returns the string representation in title form.
@@ -74,6 +81,10 @@ func (c Name) String() string {
 		return "import"
 	case State:
 		return "state"
+	case ApproveDestroy:
+		return "approve_destroy"
+	case Cancel:
+		return "cancel"
 	}
 	return ""
 }
@@ -149,6 +160,10 @@ func ParseCommandName(name string) (Name, error) {
 		return Import, nil
 	case "state":
 		return State, nil
+	case "approve_destroy":
+		return ApproveDestroy, nil
+	case "cancel":
+		return Cancel, nil
 	}
 	return -1, fmt.Errorf("unknown command name: %s", name)
 }
@@ -40,6 +40,7 @@ func TestName_String(t *testing.T) {
 		{command.Version, "version"},
 		{command.Import, "import"},
 		{command.State, "state"},
+		{command.ApproveDestroy, "approve_destroy"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.want, func(t *testing.T) {
@@ -63,6 +64,7 @@ func TestName_DefaultUsage(t *testing.T) {
 		{command.Version, "version"},
 		{command.Import, "import ADDRESS ID"},
 		{command.State, "state [rm ADDRESS...]"},
+		{command.ApproveDestroy, "approve_destroy"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.c.String(), func(t *testing.T) {
@@ -178,6 +180,7 @@ func TestParseCommandName(t *testing.T) {
 		{command.Version, "version"},
 		{command.Import, "import"},
 		{command.State, "state"},
+		{command.ApproveDestroy, "approve_destroy"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -26,6 +27,10 @@ type ProjectContext struct {
 	// ApprovePoliciesCmd is the command that users should run to approve policies for this plan. If
 	// this is an apply then this will be empty.
 	ApprovePoliciesCmd string
+	// ApproveDestroyCmd is the command that users should run to approve
+	// destroying protected resources in this plan. If this is an apply then
+	// this will be empty.
+	ApproveDestroyCmd string
 	// PlanRequirements is the list of requirements that must be satisfied
 	// before we will run the plan stage.
 	PlanRequirements []string
@@ -83,6 +88,10 @@ type ProjectContext struct {
 	// Steps are the sequence of commands we need to run for this project and this
 	// stage.
 	Steps []valid.Step
+	// InitSteps are the steps of the plan workflow that initialize the
+	// project. They're only set for apply, and are run before applying a plan
+	// that was made in another working dir.
+	InitSteps []valid.Step
 	// TerraformVersion is the version of terraform we should use when executing
 	// commands for this project. This can be set to nil in which case we will
 	// use the default Atlantis terraform version.
@@ -97,10 +106,23 @@ type ProjectContext struct {
 	// PolicySets represent the policies that are run on the plan as part of the
 	// policy check stage
 	PolicySets valid.PolicySets
+	// DestroyProtection configures which resources can't be destroyed without
+	// an owner's approval.
+	DestroyProtection valid.DestroyProtection
+	// ApprovalPolicy configures which approvals the approval_policy apply
+	// requirement needs. It's nil if the repo doesn't have one, in which case
+	// one approval from anyone is needed.
+	ApprovalPolicy *valid.ApprovalPolicy
+	// DestroyApprovedBy is the owner that approved destroying protected
+	// resources in the current plan. It's empty if there's no approval.
+	DestroyApprovedBy string
 	// PolicySetTarget describes which policy sets to target on the approve_policies step.
 	PolicySetTarget string
 	// ClearPolicyApproval determines whether policy counts will be incremented or cleared.
 	ClearPolicyApproval bool
+	// Teams caches the teams of the users that the command looks up. It's
+	// shared by all the projects of the command.
+	Teams *TeamCache
 	// DeleteSourceBranchOnMerge will attempt to allow a branch to be deleted when merged (AzureDevOps & GitLab Support Only)
 	DeleteSourceBranchOnMerge bool
 	// RepoLocking will get a lock when plan
@@ -113,6 +135,47 @@ type ProjectContext struct {
 	ExecutionOrderGroup int
 	// If plans/applies should be aborted if any prior plan/apply fails
 	AbortOnExcecutionOrderFail bool
+	// PlanTTL is how long the plan can be applied for. Plans don't expire if
+	// it's 0.
+	PlanTTL time.Duration
+	// PlannedAt is when the current plan was created. It's zero if it isn't
+	// known, ex. for plans created before it was stored.
+	PlannedAt time.Time
+	// PlanStalenessChecked is true if the plan has already been checked to
+	// not be stale for this command so it isn't checked again.
+	PlanStalenessChecked bool
+	// StageTimeout is how long all the steps of the stage being run can take
+	// together. There's no limit if it's 0.
+	StageTimeout time.Duration
+	// StepTimeout is how long the step being run can take. There's no limit
+	// if it's 0.
+	StepTimeout time.Duration
+	// StepDeadline is when the step being run is stopped for timing out. It's
+	// zero if the step has no timeout.
+	StepDeadline time.Time
+	// WhenModified are the patterns, relative to RepoRelDir, of the files
+	// that affect this project.
+	WhenModified []string
+	// DependsOn are the names of the projects that must be applied before
+	// this project can be applied.
+	DependsOn []string
+	// UnappliedDependencies are the projects in DependsOn that have been
+	// planned in this pull request but not applied yet.
+	UnappliedDependencies []string
+	// Targets are the resource addresses the plan is limited to. When
+	// planning they come from the command, otherwise from the current plan.
+	Targets []string
+	// Replaces are the resource addresses the plan forces to be replaced.
+	// When planning they come from the command, otherwise from the current
+	// plan.
+	Replaces []string
+}
+
+// IsTargeted returns true if the plan is limited to some resources or
+// replaces some resources, so applying it doesn't apply all of the
+// project's changes.
+func (p ProjectContext) IsTargeted() bool {
+	return len(p.Targets) > 0 || len(p.Replaces) > 0
 }
 
 // SetProjectScopeTags adds ProjectContext tags to a new returned scope.
@@ -152,6 +215,15 @@ func (p ProjectContext) GetPolicyCheckResultFileName() string {
 	return fmt.Sprintf("%s-%s-policyout.json", projName, p.Workspace)
 }
 
+// GetCostEstimateFileName returns the filename (not the path) to store the output of the cost_estimate step.
+func (p ProjectContext) GetCostEstimateFileName() string {
+	if p.ProjectName == "" {
+		return fmt.Sprintf("%s-cost.json", p.Workspace)
+	}
+	projName := strings.Replace(p.ProjectName, "/", planfileSlashReplace, -1)
+	return fmt.Sprintf("%s-%s-cost.json", projName, p.Workspace)
+}
+
 // Gets a unique identifier for the current pull request as a single string
 func (p ProjectContext) PullInfo() string {
 	normalizedOwner := strings.ReplaceAll(p.BaseRepo.Owner, "/", "-")
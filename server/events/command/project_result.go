@@ -1,24 +1,34 @@
 package command
 
 import (
+	"time"
+
 	"github.com/runatlantis/atlantis/server/events/models"
 )
 
 // ProjectResult is the result of executing a plan/policy_check/apply for a specific project.
 type ProjectResult struct {
-	Command            Name
-	SubCommand         string
-	RepoRelDir         string
-	Workspace          string
-	Error              error
-	Failure            string
-	PlanSuccess        *models.PlanSuccess
-	PolicyCheckResults *models.PolicyCheckResults
-	ApplySuccess       string
-	VersionSuccess     string
-	ImportSuccess      *models.ImportSuccess
-	StateRmSuccess     *models.StateRmSuccess
-	ProjectName        string
+	Command               Name
+	SubCommand            string
+	RepoRelDir            string
+	Workspace             string
+	Error                 error
+	Failure               string
+	PlanSuccess           *models.PlanSuccess
+	PolicyCheckResults    *models.PolicyCheckResults
+	ApplySuccess          string
+	VersionSuccess        string
+	ImportSuccess         *models.ImportSuccess
+	StateRmSuccess        *models.StateRmSuccess
+	ApproveDestroySuccess *models.ApproveDestroySuccess
+	// CostEstimate is set for plans of projects whose workflow has a
+	// cost_estimate step.
+	CostEstimate *models.CostEstimate
+	ProjectName  string
+	// User is the username of who ran the command.
+	User string
+	// JobID identifies the job that ran the command.
+	JobID string
 }
 
 // CommitStatus returns the vcs commit status of this project result.
@@ -41,6 +51,7 @@ func (p ProjectResult) PolicyStatus() []models.PolicySetStatus {
 				PolicySetName: policySet.PolicySetName,
 				Passed:        policySet.Passed,
 				Approvals:     policySet.CurApprovals,
+				Revision:      policySet.Revision,
 			}
 			policyStatuses = append(policyStatuses, policyStatus)
 		}
@@ -52,7 +63,7 @@ func (p ProjectResult) PolicyStatus() []models.PolicySetStatus {
 func (p ProjectResult) PlanStatus() models.ProjectPlanStatus {
 	switch p.Command {
 
-	case Plan:
+	case Plan, ApproveDestroy:
 		if p.Error != nil {
 			return models.ErroredPlanStatus
 		} else if p.Failure != "" {
@@ -80,5 +91,55 @@ func (p ProjectResult) PlanStatus() models.ProjectPlanStatus {
 
 // IsSuccessful returns true if this project result had no errors.
 func (p ProjectResult) IsSuccessful() bool {
-	return p.PlanSuccess != nil || (p.PolicyCheckResults != nil && p.Error == nil && p.Failure == "") || p.ApplySuccess != ""
+	return p.PlanSuccess != nil || (p.PolicyCheckResults != nil && p.Error == nil && p.Failure == "") || p.ApplySuccess != "" || p.ApproveDestroySuccess != nil
+}
+
+// PlannedAt returns when the plan was created or the zero time if this isn't
+// a successful plan.
+func (p ProjectResult) PlannedAt() time.Time {
+	if p.PlanSuccess == nil {
+		return time.Time{}
+	}
+	return p.PlanSuccess.PlannedAt
+}
+
+// Targets returns the resource addresses the plan is limited to or nil if
+// this isn't a successful plan.
+func (p ProjectResult) Targets() []string {
+	if p.PlanSuccess == nil {
+		return nil
+	}
+	return p.PlanSuccess.Targets
+}
+
+// Replaces returns the resource addresses the plan replaces or nil if this
+// isn't a successful plan.
+func (p ProjectResult) Replaces() []string {
+	if p.PlanSuccess == nil {
+		return nil
+	}
+	return p.PlanSuccess.Replaces
+}
+
+// PlanRecords returns the plan history entry for this result or nil if this
+// isn't a successful plan.
+func (p ProjectResult) PlanRecords() []models.PlanRecord {
+	if p.Command != Plan || p.PlanSuccess == nil {
+		return nil
+	}
+	return []models.PlanRecord{{
+		JobID:     p.JobID,
+		User:      p.User,
+		PlannedAt: p.PlanSuccess.PlannedAt,
+		Stats:     p.PlanSuccess.Stats(),
+	}}
+}
+
+// DestroyApprovedBy returns the owner that approved destroying protected
+// resources or an empty string if this isn't a successful approval.
+func (p ProjectResult) DestroyApprovedBy() string {
+	if p.ApproveDestroySuccess == nil {
+		return ""
+	}
+	return p.ApproveDestroySuccess.ApprovedBy
 }
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"sync"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// TeamNamesGetter looks up the teams that a user belongs to, ex. a VCS client.
+type TeamNamesGetter interface {
+	GetTeamNamesForUser(repo models.Repo, user models.User) ([]string, error)
+}
+
+// TeamCache caches the teams that users belong to for the duration of a
+// command so that the VCS host is asked about each user at most once, however
+// many projects and checks need their teams. A nil TeamCache doesn't cache.
+type TeamCache struct {
+	mu    sync.Mutex
+	teams map[string][]string
+}
+
+// GetTeamNamesForUser returns the teams of user in repo, looking them up with
+// getter the first time.
+func (c *TeamCache) GetTeamNamesForUser(getter TeamNamesGetter, repo models.Repo, user models.User) ([]string, error) {
+	if c == nil {
+		return getter.GetTeamNamesForUser(repo, user)
+	}
+	// The lock is held during the lookup so that projects running in
+	// parallel don't look up the same user at once.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := repo.ID() + "/" + user.Username
+	if teams, ok := c.teams[key]; ok {
+		return teams, nil
+	}
+	teams, err := getter.GetTeamNamesForUser(repo, user)
+	if err != nil {
+		return nil, err
+	}
+	if c.teams == nil {
+		c.teams = make(map[string][]string)
+	}
+	c.teams[key] = teams
+	return teams, nil
+}
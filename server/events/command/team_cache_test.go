@@ -0,0 +1,40 @@
+package command_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+type countingTeamNamesGetter struct {
+	lookups int
+}
+
+func (g *countingTeamNamesGetter) GetTeamNamesForUser(_ models.Repo, user models.User) ([]string, error) {
+	g.lookups++
+	return []string{user.Username + "-team"}, nil
+}
+
+func TestTeamCache_GetTeamNamesForUser(t *testing.T) {
+	getter := &countingTeamNamesGetter{}
+	repo := models.Repo{FullName: "acme/infra", VCSHost: models.VCSHost{Hostname: "github.com"}}
+	cache := &command.TeamCache{}
+	for i := 0; i < 2; i++ {
+		teams, err := cache.GetTeamNamesForUser(getter, repo, models.User{Username: "alice"})
+		Ok(t, err)
+		Equals(t, []string{"alice-team"}, teams)
+	}
+	Equals(t, 1, getter.lookups)
+
+	_, err := cache.GetTeamNamesForUser(getter, repo, models.User{Username: "bob"})
+	Ok(t, err)
+	Equals(t, 2, getter.lookups)
+
+	// A nil cache looks the teams up every time.
+	var nilCache *command.TeamCache
+	_, err = nilCache.GetTeamNamesForUser(getter, repo, models.User{Username: "alice"})
+	Ok(t, err)
+	Equals(t, 3, getter.lookups)
+}
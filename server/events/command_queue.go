@@ -0,0 +1,368 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+//go:generate pegomock generate --package mocks -o mocks/mock_command_queue.go CommandQueue
+
+// CommandQueue persists commands before they're run so that they aren't lost
+// if Atlantis stops while they're waiting or running.
+type CommandQueue interface {
+	// EnqueueCommentCommand persists a comment command and returns once it's
+	// safe to acknowledge the event. The command is run in the background.
+	EnqueueCommentCommand(baseRepo models.Repo, maybeHeadRepo *models.Repo, maybePull *models.PullRequest, user models.User, pullNum int, cmd *CommentCommand) error
+	// EnqueueAutoplanCommand persists an autoplan and returns once it's safe
+	// to acknowledge the event. The autoplan is run in the background.
+	EnqueueAutoplanCommand(baseRepo models.Repo, headRepo models.Repo, pull models.PullRequest, user models.User) error
+}
+
+// QueuedEventStore persists queued events. It's implemented by the BoltDB,
+// Redis and PostgreSQL databases.
+type QueuedEventStore interface {
+	// EnqueueEvent persists event until it's deleted with DeleteQueuedEvent.
+	EnqueueEvent(event models.QueuedEvent) error
+	// DeleteQueuedEvent deletes the queued event with id. It's not an error
+	// if there is no such event.
+	DeleteQueuedEvent(id string) error
+	// ListQueuedEvents returns the queued events, oldest first.
+	ListQueuedEvents() ([]models.QueuedEvent, error)
+}
+
+// DefaultQueueWorkers is the number of queued events run at the same time if
+// QueueWorkers isn't set.
+const DefaultQueueWorkers = 10
+
+// DefaultQueueLease is how long the events an instance runs are kept from the
+// other instances without being renewed if QueueLease isn't set. Leases are
+// renewed three times per lease so a renewal can fail without the event being
+// taken over.
+const DefaultQueueLease = time.Minute
+
+// EnqueueCommentCommand persists the comment command so the queue workers
+// started by StartEventQueue run it.
+func (c *DefaultCommandRunner) EnqueueCommentCommand(baseRepo models.Repo, maybeHeadRepo *models.Repo, maybePull *models.PullRequest, user models.User, pullNum int, cmd *CommentCommand) error {
+	serialized, err := json.Marshal(cmd)
+	if err != nil {
+		return errors.Wrap(err, "serializing command")
+	}
+	return c.enqueue(models.QueuedEvent{
+		Kind:     models.CommentQueuedEvent,
+		BaseRepo: baseRepo,
+		HeadRepo: maybeHeadRepo,
+		Pull:     maybePull,
+		PullNum:  pullNum,
+		User:     user,
+		Command:  serialized,
+	})
+}
+
+// EnqueueAutoplanCommand persists the autoplan so the queue workers started
+// by StartEventQueue run it.
+func (c *DefaultCommandRunner) EnqueueAutoplanCommand(baseRepo models.Repo, headRepo models.Repo, pull models.PullRequest, user models.User) error {
+	return c.enqueue(models.QueuedEvent{
+		Kind:     models.AutoplanQueuedEvent,
+		BaseRepo: baseRepo,
+		HeadRepo: &headRepo,
+		Pull:     &pull,
+		PullNum:  pull.Num,
+		User:     user,
+	})
+}
+
+// StartEventQueue handles the events left over from the last time this
+// instance ran and then starts the workers that run queued events. Left over
+// events are run again if ReplayQueuedEvents is true, otherwise their pull
+// requests are commented on and their commit statuses set to failed.
+//
+// Events belonging to other instances sharing QueuedEventStore are left
+// alone while their owner keeps renewing their lease. Once a lease expires,
+// because its owner stopped and hasn't come back, the event is handled by the
+// first instance to notice. It does nothing if QueuedEventStore isn't set.
+func (c *DefaultCommandRunner) StartEventQueue() error {
+	if c.QueuedEventStore == nil {
+		return nil
+	}
+	c.eventQueue = newEventQueue()
+	workers := c.QueueWorkers
+	if workers <= 0 {
+		workers = DefaultQueueWorkers
+	}
+	queue := c.eventQueue
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				c.runQueuedEvent(queue.pop())
+			}
+		}()
+	}
+
+	if err := c.recoverQueuedEvents(true); err != nil {
+		return err
+	}
+	go c.maintainLeases()
+	return nil
+}
+
+// StopEventQueue stops renewing the leases of the events this instance owns
+// and taking over expired events. It's called once the running events have
+// finished.
+func (c *DefaultCommandRunner) StopEventQueue() {
+	if c.eventQueue == nil {
+		return
+	}
+	close(c.eventQueue.stop)
+	<-c.eventQueue.stopped
+}
+
+func (c *DefaultCommandRunner) enqueue(event models.QueuedEvent) error {
+	if c.eventQueue == nil {
+		return errors.New("event queue hasn't been started–this is a bug")
+	}
+	event.ID = uuid.New().String()
+	event.EnqueuedAt = time.Now()
+	event.Owner = c.InstanceID
+	event.LeaseExpiresAt = event.EnqueuedAt.Add(c.queueLease())
+	if err := c.QueuedEventStore.EnqueueEvent(event); err != nil {
+		return errors.Wrap(err, "persisting event")
+	}
+	c.eventQueue.push(event)
+	return nil
+}
+
+// queueLease returns QueueLease or its default.
+func (c *DefaultCommandRunner) queueLease() time.Duration {
+	if c.QueueLease <= 0 {
+		return DefaultQueueLease
+	}
+	return c.QueueLease
+}
+
+// recoverQueuedEvents takes over the events whose lease expired. On startup
+// the events this instance owned before it stopped are taken over too.
+//
+// The store has no compare-and-swap, so two instances noticing the same
+// expired lease at the same moment can both take the event over. Leases only
+// expire when an instance stops for longer than QueueLease so this is rare.
+func (c *DefaultCommandRunner) recoverQueuedEvents(startup bool) error {
+	events, err := c.QueuedEventStore.ListQueuedEvents()
+	if err != nil {
+		return errors.Wrap(err, "listing queued events")
+	}
+	now := time.Now()
+	for _, event := range events {
+		if event.Owner == c.InstanceID {
+			// Once started, the events owned by this instance are all in
+			// the eventQueue.
+			if !startup {
+				continue
+			}
+		} else if event.LeaseExpiresAt.After(now) {
+			continue
+		}
+
+		previousOwner := event.Owner
+		event.Owner = c.InstanceID
+		event.LeaseExpiresAt = now.Add(c.queueLease())
+		if err := c.QueuedEventStore.EnqueueEvent(event); err != nil {
+			c.Logger.Err("unable to take over queued event %q from %q: %s", event.ID, previousOwner, err)
+			continue
+		}
+
+		if c.ReplayQueuedEvents {
+			c.Logger.Info("replaying %s event for %s#%d that didn't finish before Atlantis stopped", event.Kind, event.BaseRepo.FullName, event.PullNum)
+			c.eventQueue.push(event)
+			continue
+		}
+		c.Logger.Warn("failing %s event for %s#%d that didn't finish before Atlantis stopped", event.Kind, event.BaseRepo.FullName, event.PullNum)
+		c.failQueuedEvent(event)
+		if err := c.QueuedEventStore.DeleteQueuedEvent(event.ID); err != nil {
+			c.Logger.Err("unable to delete queued event %q: %s", event.ID, err)
+		}
+	}
+	return nil
+}
+
+// maintainLeases renews the leases of the events this instance owns and
+// takes over the events whose lease expired until StopEventQueue is called.
+func (c *DefaultCommandRunner) maintainLeases() {
+	queue := c.eventQueue
+	defer close(queue.stopped)
+	ticker := time.NewTicker(c.queueLease() / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-queue.stop:
+			return
+		case <-ticker.C:
+		}
+		queue.renew(func(event models.QueuedEvent) {
+			event.LeaseExpiresAt = time.Now().Add(c.queueLease())
+			if err := c.QueuedEventStore.EnqueueEvent(event); err != nil {
+				c.Logger.Warn("unable to renew the lease of queued event %q: %s", event.ID, err)
+			}
+		})
+		if err := c.recoverQueuedEvents(false); err != nil {
+			c.Logger.Warn("unable to take over expired queued events: %s", err)
+		}
+	}
+}
+
+// runQueuedEvent runs event and deletes it from the store once it's done.
+func (c *DefaultCommandRunner) runQueuedEvent(event models.QueuedEvent) {
+	switch event.Kind {
+	case models.AutoplanQueuedEvent:
+		if event.HeadRepo == nil || event.Pull == nil {
+			c.Logger.Err("queued autoplan event %q is missing its pull request", event.ID)
+			break
+		}
+		c.RunAutoplanCommand(event.BaseRepo, *event.HeadRepo, *event.Pull, event.User)
+	case models.CommentQueuedEvent:
+		cmd, err := queuedEventCommand(event)
+		if err != nil {
+			c.Logger.Err(err.Error())
+			break
+		}
+		c.RunCommentCommand(event.BaseRepo, event.HeadRepo, event.Pull, event.User, event.PullNum, cmd)
+	default:
+		c.Logger.Err("queued event %q has unknown kind %q", event.ID, event.Kind)
+	}
+
+	c.eventQueue.done(event.ID, func() {
+		if err := c.QueuedEventStore.DeleteQueuedEvent(event.ID); err != nil {
+			c.Logger.Err("unable to delete queued event %q: %s", event.ID, err)
+		}
+	})
+}
+
+// failQueuedEvent lets the user know that event won't be run and fails the
+// commit status it would have updated.
+func (c *DefaultCommandRunner) failQueuedEvent(event models.QueuedEvent) {
+	cmdName := command.Plan
+	if event.Kind == models.CommentQueuedEvent {
+		cmd, err := queuedEventCommand(event)
+		if err != nil {
+			c.Logger.Err(err.Error())
+			return
+		}
+		cmdName = cmd.Name
+	}
+
+	log := c.buildLogger(event.BaseRepo.FullName, event.PullNum)
+	comment := fmt.Sprintf("**Error:** Atlantis stopped before it finished running `%s` for @%s. Its results have been lost, please run it again.",
+		queuedEventDescription(event.Kind, cmdName), event.User.Username)
+	if err := c.VCSClient.CreateComment(event.BaseRepo, event.PullNum, comment, cmdName.String()); err != nil {
+		log.Err("unable to comment: %s", err)
+	}
+
+	// Only plan, policy check and apply have commit statuses.
+	if cmdName != command.Plan && cmdName != command.PolicyCheck && cmdName != command.Apply {
+		return
+	}
+	pull := event.Pull
+	if pull == nil {
+		_, fetched, err := c.ensureValidRepoMetadata(event.BaseRepo, event.HeadRepo, event.Pull, event.User, event.PullNum, log)
+		if err != nil {
+			return
+		}
+		pull = &fetched
+	}
+	if err := c.CommitStatusUpdater.UpdateCombined(event.BaseRepo, *pull, models.FailedCommitStatus, cmdName); err != nil {
+		log.Warn("unable to update commit status: %s", err)
+	}
+}
+
+// queuedEventCommand deserializes the comment command of a comment event.
+func queuedEventCommand(event models.QueuedEvent) (*CommentCommand, error) {
+	var cmd CommentCommand
+	if err := json.Unmarshal(event.Command, &cmd); err != nil {
+		return nil, errors.Wrapf(err, "deserializing command of queued event %q", event.ID)
+	}
+	return &cmd, nil
+}
+
+// queuedEventDescription returns how the user would refer to the command.
+func queuedEventDescription(kind models.QueuedEventKind, cmdName command.Name) string {
+	if kind == models.AutoplanQueuedEvent {
+		return "autoplan"
+	}
+	return "atlantis " + cmdName.String()
+}
+
+// eventQueue holds the events owned by this instance. Pending events are
+// popped by a fixed number of workers so a backlog only costs memory.
+type eventQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []models.QueuedEvent
+	// owned are the events that are pending or running, by ID. It has its
+	// own lock so renewing leases doesn't hold up the workers.
+	ownedMu sync.Mutex
+	owned   map[string]models.QueuedEvent
+	// stop is closed to stop maintaining leases, and stopped once it has.
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+func newEventQueue() *eventQueue {
+	q := &eventQueue{
+		owned:   make(map[string]models.QueuedEvent),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// push adds event to the end of the queue.
+func (q *eventQueue) push(event models.QueuedEvent) {
+	q.ownedMu.Lock()
+	q.owned[event.ID] = event
+	q.ownedMu.Unlock()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = append(q.pending, event)
+	q.cond.Signal()
+}
+
+// pop removes the event at the front of the queue, waiting for one if the
+// queue is empty.
+func (q *eventQueue) pop() models.QueuedEvent {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.pending) == 0 {
+		q.cond.Wait()
+	}
+	event := q.pending[0]
+	q.pending[0] = models.QueuedEvent{}
+	q.pending = q.pending[1:]
+	return event
+}
+
+// renew calls save for every owned event. done waits for it so a finished
+// event's lease isn't renewed after it was deleted from the store.
+func (q *eventQueue) renew(save func(models.QueuedEvent)) {
+	q.ownedMu.Lock()
+	defer q.ownedMu.Unlock()
+	for _, event := range q.owned {
+		save(event)
+	}
+}
+
+// done stops owning the event with id and calls remove while no lease is
+// being renewed.
+func (q *eventQueue) done(id string, remove func()) {
+	q.ownedMu.Lock()
+	defer q.ownedMu.Unlock()
+	delete(q.owned, id)
+	remove()
+}
@@ -0,0 +1,237 @@
+package events_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v53/github"
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/models/testdata"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestEnqueueCommentCommand_NotStarted(t *testing.T) {
+	setup(t)
+	store, err := db.New(t.TempDir())
+	Ok(t, err)
+	ch.QueuedEventStore = store
+
+	err = ch.EnqueueCommentCommand(testdata.GithubRepo, nil, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Plan})
+	ErrEquals(t, "event queue hasn't been started–this is a bug", err)
+}
+
+func TestEnqueueCommentCommand_Runs(t *testing.T) {
+	t.Log("a queued comment command should be run and then deleted from the store")
+	setup(t)
+	store, err := db.New(t.TempDir())
+	Ok(t, err)
+	ch.QueuedEventStore = store
+	Ok(t, ch.StartEventQueue())
+	t.Cleanup(ch.StopEventQueue)
+
+	pull := &github.PullRequest{State: github.String("open")}
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	When(githubGetter.GetPullRequest(testdata.GithubRepo, testdata.Pull.Num)).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(pull)).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+	When(deleteLockCommand.DeleteLocksByPull(testdata.GithubRepo.FullName, testdata.Pull.Num)).ThenReturn(1, nil)
+
+	Ok(t, ch.EnqueueCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Unlock}))
+
+	deleteLockCommand.VerifyWasCalledEventually(Once(), 5*time.Second).DeleteLocksByPull(testdata.GithubRepo.FullName, testdata.Pull.Num)
+	waitForEmptyQueue(t, store)
+}
+
+func TestStartEventQueue_FailsLeftoverEvents(t *testing.T) {
+	t.Log("events left over from the last run should be commented on, failed and deleted")
+	vcsClient := setup(t)
+	store, err := db.New(t.TempDir())
+	Ok(t, err)
+	ch.QueuedEventStore = store
+	ch.CommitStatusUpdater = commitUpdater
+
+	pull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	Ok(t, store.EnqueueEvent(models.QueuedEvent{
+		ID:         "autoplan",
+		Kind:       models.AutoplanQueuedEvent,
+		BaseRepo:   testdata.GithubRepo,
+		HeadRepo:   &testdata.GithubRepo,
+		Pull:       &pull,
+		PullNum:    pull.Num,
+		User:       testdata.User,
+		EnqueuedAt: time.Now(),
+	}))
+	Ok(t, store.EnqueueEvent(models.QueuedEvent{
+		ID:         "unlock",
+		Kind:       models.CommentQueuedEvent,
+		BaseRepo:   testdata.GithubRepo,
+		PullNum:    pull.Num,
+		User:       testdata.User,
+		Command:    unlockCommand,
+		EnqueuedAt: time.Now(),
+	}))
+
+	Ok(t, ch.StartEventQueue())
+	t.Cleanup(ch.StopEventQueue)
+
+	vcsClient.VerifyWasCalledOnce().CreateComment(testdata.GithubRepo, pull.Num,
+		"**Error:** Atlantis stopped before it finished running `autoplan` for @"+testdata.User.Username+". Its results have been lost, please run it again.", "plan")
+	vcsClient.VerifyWasCalledOnce().CreateComment(testdata.GithubRepo, pull.Num,
+		"**Error:** Atlantis stopped before it finished running `atlantis unlock` for @"+testdata.User.Username+". Its results have been lost, please run it again.", "unlock")
+	commitUpdater.VerifyWasCalledOnce().UpdateCombined(testdata.GithubRepo, pull, models.FailedCommitStatus, command.Plan)
+	commitUpdater.VerifyWasCalled(Never()).UpdateCombined(Any[models.Repo](), Any[models.PullRequest](), Any[models.CommitStatus](), Eq(command.Unlock))
+	deleteLockCommand.VerifyWasCalled(Never()).DeleteLocksByPull(Any[string](), Any[int]())
+
+	queued, err := store.ListQueuedEvents()
+	Ok(t, err)
+	Equals(t, 0, len(queued))
+}
+
+func TestStartEventQueue_ReplaysLeftoverEvents(t *testing.T) {
+	t.Log("events left over from the last run should be run again if replaying is enabled")
+	setup(t)
+	store, err := db.New(t.TempDir())
+	Ok(t, err)
+	ch.QueuedEventStore = store
+	ch.ReplayQueuedEvents = true
+
+	pull := &github.PullRequest{State: github.String("open")}
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	When(githubGetter.GetPullRequest(testdata.GithubRepo, testdata.Pull.Num)).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(pull)).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+	When(deleteLockCommand.DeleteLocksByPull(testdata.GithubRepo.FullName, testdata.Pull.Num)).ThenReturn(1, nil)
+	Ok(t, store.EnqueueEvent(models.QueuedEvent{
+		ID:         "unlock",
+		Kind:       models.CommentQueuedEvent,
+		BaseRepo:   testdata.GithubRepo,
+		PullNum:    testdata.Pull.Num,
+		User:       testdata.User,
+		Command:    unlockCommand,
+		EnqueuedAt: time.Now(),
+	}))
+
+	Ok(t, ch.StartEventQueue())
+	t.Cleanup(ch.StopEventQueue)
+
+	deleteLockCommand.VerifyWasCalledEventually(Once(), 5*time.Second).DeleteLocksByPull(testdata.GithubRepo.FullName, testdata.Pull.Num)
+	waitForEmptyQueue(t, store)
+}
+
+func TestStartEventQueue_LeavesEventsOfOtherInstances(t *testing.T) {
+	t.Log("events whose owner is still renewing their lease should be left alone")
+	vcsClient := setup(t)
+	store, err := db.New(t.TempDir())
+	Ok(t, err)
+	ch.QueuedEventStore = store
+	ch.InstanceID = "atlantis-0"
+
+	event := models.QueuedEvent{
+		ID:             "unlock",
+		Kind:           models.CommentQueuedEvent,
+		BaseRepo:       testdata.GithubRepo,
+		PullNum:        testdata.Pull.Num,
+		User:           testdata.User,
+		Command:        unlockCommand,
+		EnqueuedAt:     time.Now(),
+		Owner:          "atlantis-1",
+		LeaseExpiresAt: time.Now().Add(time.Hour),
+	}
+	Ok(t, store.EnqueueEvent(event))
+
+	Ok(t, ch.StartEventQueue())
+	t.Cleanup(ch.StopEventQueue)
+
+	vcsClient.VerifyWasCalled(Never()).CreateComment(Any[models.Repo](), Any[int](), Any[string](), Any[string]())
+	queued, err := store.ListQueuedEvents()
+	Ok(t, err)
+	Equals(t, 1, len(queued))
+	Equals(t, "atlantis-1", queued[0].Owner)
+}
+
+func TestStartEventQueue_TakesOverExpiredEvents(t *testing.T) {
+	t.Log("events whose lease expired should be taken over by another instance")
+	vcsClient := setup(t)
+	store, err := db.New(t.TempDir())
+	Ok(t, err)
+	ch.QueuedEventStore = store
+	ch.InstanceID = "atlantis-0"
+	ch.QueueLease = 300 * time.Millisecond
+
+	Ok(t, store.EnqueueEvent(models.QueuedEvent{
+		ID:             "unlock",
+		Kind:           models.CommentQueuedEvent,
+		BaseRepo:       testdata.GithubRepo,
+		PullNum:        testdata.Pull.Num,
+		User:           testdata.User,
+		Command:        unlockCommand,
+		EnqueuedAt:     time.Now(),
+		Owner:          "atlantis-1",
+		LeaseExpiresAt: time.Now().Add(500 * time.Millisecond),
+	}))
+
+	Ok(t, ch.StartEventQueue())
+	t.Cleanup(ch.StopEventQueue)
+	vcsClient.VerifyWasCalled(Never()).CreateComment(Any[models.Repo](), Any[int](), Any[string](), Any[string]())
+
+	vcsClient.VerifyWasCalledEventually(Once(), 5*time.Second).CreateComment(testdata.GithubRepo, testdata.Pull.Num,
+		"**Error:** Atlantis stopped before it finished running `atlantis unlock` for @"+testdata.User.Username+". Its results have been lost, please run it again.", "unlock")
+	waitForEmptyQueue(t, store)
+}
+
+func TestEnqueueCommentCommand_RenewsLease(t *testing.T) {
+	t.Log("the lease of a running event should be renewed by its owner")
+	setup(t)
+	store, err := db.New(t.TempDir())
+	Ok(t, err)
+	ch.QueuedEventStore = store
+	ch.InstanceID = "atlantis-0"
+	ch.QueueLease = 300 * time.Millisecond
+	ch.QueueWorkers = 1
+	Ok(t, ch.StartEventQueue())
+	t.Cleanup(ch.StopEventQueue)
+
+	pull := &github.PullRequest{State: github.String("open")}
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	When(githubGetter.GetPullRequest(testdata.GithubRepo, testdata.Pull.Num)).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(pull)).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+	unblock := make(chan struct{})
+	When(deleteLockCommand.DeleteLocksByPull(testdata.GithubRepo.FullName, testdata.Pull.Num)).Then(func([]Param) ReturnValues {
+		<-unblock
+		return ReturnValues{1, nil}
+	})
+
+	Ok(t, ch.EnqueueCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Unlock}))
+	deleteLockCommand.VerifyWasCalledEventually(Once(), 5*time.Second).DeleteLocksByPull(testdata.GithubRepo.FullName, testdata.Pull.Num)
+
+	// Wait past the original lease.
+	time.Sleep(600 * time.Millisecond)
+	queued, err := store.ListQueuedEvents()
+	Ok(t, err)
+	Equals(t, 1, len(queued))
+	Equals(t, "atlantis-0", queued[0].Owner)
+	Assert(t, queued[0].LeaseExpiresAt.After(time.Now()), "expected the lease to be renewed, it expired at %s", queued[0].LeaseExpiresAt)
+
+	close(unblock)
+	waitForEmptyQueue(t, store)
+}
+
+// unlockCommand is the serialized command of a queued "atlantis unlock".
+var unlockCommand, _ = json.Marshal(events.CommentCommand{Name: command.Unlock})
+
+// waitForEmptyQueue waits for the queue workers to delete the events they've
+// run.
+func waitForEmptyQueue(t *testing.T, store events.QueuedEventStore) {
+	for i := 0; i < 50; i++ {
+		queued, err := store.ListQueuedEvents()
+		Ok(t, err)
+		if len(queued) == 0 {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatal("queued events weren't deleted")
+}
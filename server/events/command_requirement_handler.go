@@ -1,9 +1,17 @@
 package events
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/config/raw"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs"
 )
 
 //go:generate pegomock generate --package mocks -o mocks/mock_command_requirement_handler.go CommandRequirementHandler
@@ -15,6 +23,7 @@ type CommandRequirementHandler interface {
 
 type DefaultCommandRequirementHandler struct {
 	WorkingDir WorkingDir
+	VCSClient  vcs.Client
 }
 
 func (a *DefaultCommandRequirementHandler) ValidatePlanProject(repoDir string, ctx command.ProjectContext) (failure string, err error) {
@@ -59,6 +68,34 @@ func (a *DefaultCommandRequirementHandler) ValidateApplyProject(repoDir string,
 			if a.WorkingDir.HasDiverged(ctx.Log, repoDir) {
 				return "Default branch must be rebased onto pull request before running apply.", nil
 			}
+		case raw.DestroyApprovedRequirement:
+			if ctx.DestroyApprovedBy != "" {
+				continue
+			}
+			protected, err := a.protectedDestroys(repoDir, ctx)
+			if err != nil {
+				return "", err
+			}
+			if len(protected) > 0 {
+				return fmt.Sprintf("This plan destroys protected resources: %s. An owner must approve destroying them by commenting `%s` before running apply.", strings.Join(protected, ", "), ctx.ApproveDestroyCmd), nil
+			}
+		case raw.ApprovalPolicyRequirement:
+			failure, err := a.validateApprovalPolicy(ctx)
+			if failure != "" || err != nil {
+				return failure, err
+			}
+		default:
+			maxDiff, ok, err := raw.ParseCostUnderRequirement(req)
+			if !ok {
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			failure, err := a.validateCostUnder(repoDir, ctx, maxDiff)
+			if failure != "" || err != nil {
+				return failure, err
+			}
 		}
 	}
 	// Passed all apply requirements configured.
@@ -85,3 +122,241 @@ func (a *DefaultCommandRequirementHandler) ValidateImportProject(repoDir string,
 	// Passed all import requirements configured.
 	return "", nil
 }
+
+// protectedDestroys returns the addresses of the protected resources that the
+// project's plan will delete or replace.
+func (a *DefaultCommandRequirementHandler) protectedDestroys(repoDir string, ctx command.ProjectContext) ([]string, error) {
+	showFile := filepath.Join(repoDir, ctx.RepoRelDir, ctx.GetShowResultFileName())
+	showJSON, err := os.ReadFile(showFile)
+	if os.IsNotExist(err) {
+		// Without the JSON plan we can't tell what will be destroyed so we
+		// err on the side of caution.
+		return nil, fmt.Errorf("unable to check for protected resources because %s does not exist, run plan again", ctx.GetShowResultFileName())
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", showFile)
+	}
+	analysis, err := models.NewPlanAnalysis(showJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var protected []string
+	for _, rc := range append(analysis.Deletions(), analysis.Replacements()...) {
+		if ctx.DestroyProtection.IsProtected(rc.Address) {
+			protected = append(protected, rc.Address)
+		}
+	}
+	return protected, nil
+}
+
+// validateCostUnder returns a failure if the project's plan increases the
+// monthly cost by maxDiff or more.
+func (a *DefaultCommandRequirementHandler) validateCostUnder(repoDir string, ctx command.ProjectContext, maxDiff float64) (string, error) {
+	costFile := filepath.Join(repoDir, ctx.RepoRelDir, ctx.GetCostEstimateFileName())
+	output, err := os.ReadFile(costFile)
+	if os.IsNotExist(err) {
+		return "This project's plan must have a cost estimate before running apply. Add the `cost_estimate` step to its plan workflow and run plan again.", nil
+	}
+	if err != nil {
+		return "", errors.Wrapf(err, "reading %s", costFile)
+	}
+	estimate, err := models.NewCostEstimate(output)
+	if err != nil {
+		return "", err
+	}
+	if estimate.DiffMonthlyCost >= maxDiff {
+		return fmt.Sprintf("This plan increases the monthly cost by %s. The increase must be under %s before running apply.", estimate.FormatCost(estimate.DiffMonthlyCost), estimate.FormatCost(maxDiff)), nil
+	}
+	return "", nil
+}
+
+// validateApprovalPolicy returns a failure if the pull request's approvals
+// don't satisfy the project's approval policy. The count is checked first,
+// then the owners and then the code owners.
+func (a *DefaultCommandRequirementHandler) validateApprovalPolicy(ctx command.ProjectContext) (string, error) {
+	policy := valid.ApprovalPolicy{Count: raw.DefaultApprovalPolicyCount}
+	if ctx.ApprovalPolicy != nil {
+		policy = *ctx.ApprovalPolicy
+	}
+
+	approvers, err := a.VCSClient.GetApprovers(ctx.Pull.BaseRepo, ctx.Pull)
+	if err != nil {
+		return "", errors.Wrap(err, "getting pull request approvers")
+	}
+	if len(approvers) < policy.Count {
+		return fmt.Sprintf("Pull request has %d approvals from people other than the author but needs %d before running apply.", len(approvers), policy.Count), nil
+	}
+
+	// We only look up the approvers' teams if an owner is a team and then at
+	// most once per approver for the whole command.
+	teams := ctx.Teams
+	if teams == nil {
+		teams = &command.TeamCache{}
+	}
+	approverTeams := func(approver string) ([]string, error) {
+		userTeams, err := teams.GetTeamNamesForUser(a.VCSClient, ctx.Pull.BaseRepo, models.User{Username: approver})
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting teams of %s", approver)
+		}
+		return userTeams, nil
+	}
+
+	if policy.HasOwners() {
+		approvedByOwner := false
+		for _, approver := range approvers {
+			var teams []string
+			if policy.HasTeamOwners() {
+				if teams, err = approverTeams(approver); err != nil {
+					return "", err
+				}
+			}
+			if policy.Owners.IsOwner(approver, teams) {
+				approvedByOwner = true
+				break
+			}
+		}
+		if !approvedByOwner {
+			return fmt.Sprintf("Pull request must be approved by an owner before running apply. Owners are %s.", formatOwners(policy.Owners)), nil
+		}
+	}
+
+	if policy.CodeOwners {
+		return a.validateCodeOwners(ctx, approvers, approverTeams)
+	}
+	return "", nil
+}
+
+// validateCodeOwners returns a failure if any file the pull request modifies
+// in the project isn't approved by one of its code owners. The CODEOWNERS
+// file is read from the base branch so that pull requests can't change who
+// has to approve them.
+func (a *DefaultCommandRequirementHandler) validateCodeOwners(ctx command.ProjectContext, approvers []string, approverTeams func(string) ([]string, error)) (string, error) {
+	if !a.VCSClient.SupportsSingleFileDownload(ctx.Pull.BaseRepo) {
+		return "", fmt.Errorf("reading CODEOWNERS from the base branch is not supported for %s", ctx.Pull.BaseRepo.VCSHost.Type.String())
+	}
+	basePull := ctx.Pull
+	basePull.HeadBranch = ctx.Pull.BaseBranch
+	var content []byte
+	for _, path := range CodeOwnersPaths {
+		found, fileContent, err := a.VCSClient.GetFileContent(basePull, path)
+		if err != nil {
+			return "", errors.Wrapf(err, "reading %s", path)
+		}
+		if found {
+			content = fileContent
+			break
+		}
+	}
+	if content == nil {
+		return fmt.Sprintf("Pull request must be approved by code owners before running apply but there is no CODEOWNERS file on the %q branch.", ctx.Pull.BaseBranch), nil
+	}
+	codeOwners, err := ParseCodeOwners(content)
+	if err != nil {
+		return "", err
+	}
+
+	modifiedFiles, err := a.VCSClient.GetModifiedFiles(ctx.Pull.BaseRepo, ctx.Pull)
+	if err != nil {
+		return "", errors.Wrap(err, "getting modified files")
+	}
+	var unapproved []string
+	for _, file := range modifiedFiles {
+		if !isInProjectDir(ctx.RepoRelDir, file) {
+			continue
+		}
+		owners := codeOwners.Owners(file)
+		if len(owners) == 0 {
+			continue
+		}
+		approved, err := approvedByCodeOwner(ctx.Pull.BaseRepo, owners, approvers, approverTeams)
+		if err != nil {
+			return "", err
+		}
+		if !approved {
+			unapproved = append(unapproved, fmt.Sprintf("`%s` (%s)", file, strings.Join(owners, ", ")))
+		}
+	}
+	if len(unapproved) > 0 {
+		return fmt.Sprintf("Pull request must be approved by a code owner of each modified file before running apply. Missing approvals for %s.", strings.Join(unapproved, ", ")), nil
+	}
+	return "", nil
+}
+
+// approvedByCodeOwner returns true if any of approvers is one of owners.
+// Owners are @user or @org/team and teams are matched by their whole path, so
+// @other-org/platform isn't owned by the platform team of the repo's org. On
+// GitLab, @group can also be a top-level group. Owners that are email
+// addresses can't be matched to approvers so they're ignored.
+func approvedByCodeOwner(repo models.Repo, owners []string, approvers []string, approverTeams func(string) ([]string, error)) (bool, error) {
+	for _, owner := range owners {
+		if !strings.HasPrefix(owner, "@") {
+			continue
+		}
+		name := strings.TrimPrefix(owner, "@")
+		isTeam := strings.Contains(name, "/")
+		if !isTeam {
+			for _, approver := range approvers {
+				if strings.EqualFold(name, approver) {
+					return true, nil
+				}
+			}
+			if repo.VCSHost.Type != models.Gitlab {
+				continue
+			}
+		}
+		for _, approver := range approvers {
+			teams, err := approverTeams(approver)
+			if err != nil {
+				return false, err
+			}
+			for _, path := range vcs.TeamPaths(repo, teams) {
+				if strings.EqualFold(name, path) {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// isInProjectDir returns true if file, relative to the repo root, is in the
+// project at repoRelDir.
+func isInProjectDir(repoRelDir string, file string) bool {
+	repoRelDir = filepath.Clean(repoRelDir)
+	if repoRelDir == "." {
+		return true
+	}
+	return strings.HasPrefix(filepath.Clean(file), repoRelDir+"/")
+}
+
+// formatOwners returns owners as a list for comments, ex. "`alice`, `bob` and
+// the `platform` team".
+func formatOwners(owners valid.PolicyOwners) string {
+	var names []string
+	for _, user := range owners.Users {
+		names = append(names, fmt.Sprintf("`%s`", user))
+	}
+	for _, team := range owners.Teams {
+		names = append(names, fmt.Sprintf("the `%s` team", team))
+	}
+	return joinWithAnd(names)
+}
+
+// formatProjectNames returns names as a list for comments, ex. "`staging` and
+// `qa`".
+func formatProjectNames(names []string) string {
+	var quoted []string
+	for _, name := range names {
+		quoted = append(quoted, fmt.Sprintf("`%s`", name))
+	}
+	return joinWithAnd(quoted)
+}
+
+// joinWithAnd joins items with commas and an "and" before the last one.
+func joinWithAnd(items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
@@ -2,6 +2,8 @@ package events_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	. "github.com/petergtz/pegomock/v4"
@@ -10,9 +12,11 @@ import (
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
 
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/mocks"
+	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -208,6 +212,304 @@ func TestAggregateApplyRequirements_ValidateApplyProject(t *testing.T) {
 	}
 }
 
+func TestAggregateApplyRequirements_ValidateApplyProject_DestroyApproved(t *testing.T) {
+	showJSON := `{
+  "resource_changes": [
+    {"address": "aws_instance.web", "change": {"actions": ["delete", "create"]}},
+    {"address": "aws_db_instance.main", "change": {"actions": ["delete"]}},
+    {"address": "aws_s3_bucket.logs", "change": {"actions": ["update"]}}
+  ]
+}`
+	tests := []struct {
+		name        string
+		protection  valid.DestroyProtection
+		approvedBy  string
+		noShowFile  bool
+		wantFailure string
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "fail by protected destroys",
+			wantFailure: "This plan destroys protected resources: aws_db_instance.main, aws_instance.web. An owner must approve destroying them by commenting `atlantis approve_destroy` before running apply.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name:        "fail by matching patterns",
+			protection:  valid.DestroyProtection{Resources: []string{"aws_db_instance.*"}},
+			wantFailure: "This plan destroys protected resources: aws_db_instance.main. An owner must approve destroying them by commenting `atlantis approve_destroy` before running apply.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name:       "pass with no matching patterns",
+			protection: valid.DestroyProtection{Resources: []string{"aws_s3_bucket.*"}},
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "pass when approved",
+			approvedBy: "owner",
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "error without JSON plan",
+			noShowFile: true,
+			wantErr:    assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			repoDir := t.TempDir()
+			ctx := command.ProjectContext{
+				ApplyRequirements: []string{raw.DestroyApprovedRequirement},
+				ApproveDestroyCmd: "atlantis approve_destroy",
+				DestroyProtection: tt.protection,
+				DestroyApprovedBy: tt.approvedBy,
+				RepoRelDir:        ".",
+				Workspace:         "default",
+			}
+			if !tt.noShowFile {
+				Ok(t, os.WriteFile(filepath.Join(repoDir, ctx.GetShowResultFileName()), []byte(showJSON), 0600))
+			}
+			a := &events.DefaultCommandRequirementHandler{WorkingDir: mocks.NewMockWorkingDir()}
+			gotFailure, err := a.ValidateApplyProject(repoDir, ctx)
+			if !tt.wantErr(t, err, fmt.Sprintf("ValidateApplyProject(%v, %v)", repoDir, ctx)) {
+				return
+			}
+			assert.Equalf(t, tt.wantFailure, gotFailure, "ValidateApplyProject(%v, %v)", repoDir, ctx)
+		})
+	}
+}
+
+func TestAggregateApplyRequirements_ValidateApplyProject_CostUnder(t *testing.T) {
+	tests := []struct {
+		name        string
+		requirement string
+		costJSON    string
+		wantFailure string
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "pass when increase is under the limit",
+			requirement: "cost_under:500",
+			costJSON:    `{"currency": "USD", "diffTotalMonthlyCost": "499.99"}`,
+			wantErr:     assert.NoError,
+		},
+		{
+			name:        "pass when cost decreases",
+			requirement: "cost_under: 0",
+			costJSON:    `{"currency": "USD", "diffTotalMonthlyCost": "-20"}`,
+			wantErr:     assert.NoError,
+		},
+		{
+			name:        "fail when increase is over the limit",
+			requirement: "cost_under: 500",
+			costJSON:    `{"currency": "USD", "diffTotalMonthlyCost": "742.64"}`,
+			wantFailure: "This plan increases the monthly cost by $742.64. The increase must be under $500.00 before running apply.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name:        "fail without cost estimate",
+			requirement: "cost_under:500",
+			wantFailure: "This project's plan must have a cost estimate before running apply. Add the `cost_estimate` step to its plan workflow and run plan again.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name:        "error with invalid amount",
+			requirement: "cost_under:lots",
+			wantErr:     assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			repoDir := t.TempDir()
+			ctx := command.ProjectContext{
+				ApplyRequirements: []string{tt.requirement},
+				RepoRelDir:        ".",
+				Workspace:         "default",
+			}
+			if tt.costJSON != "" {
+				Ok(t, os.WriteFile(filepath.Join(repoDir, ctx.GetCostEstimateFileName()), []byte(tt.costJSON), 0600))
+			}
+			a := &events.DefaultCommandRequirementHandler{WorkingDir: mocks.NewMockWorkingDir()}
+			gotFailure, err := a.ValidateApplyProject(repoDir, ctx)
+			if !tt.wantErr(t, err, fmt.Sprintf("ValidateApplyProject(%v, %v)", repoDir, ctx)) {
+				return
+			}
+			assert.Equalf(t, tt.wantFailure, gotFailure, "ValidateApplyProject(%v, %v)", repoDir, ctx)
+		})
+	}
+}
+
+func TestAggregateApplyRequirements_ValidateApplyProject_ApprovalPolicy(t *testing.T) {
+	codeOwners := `# Platform owns everything by default.
+*           @org/platform
+/network/   @alice
+/network/README.md
+`
+	githubRepo := models.Repo{Owner: "org", VCSHost: models.VCSHost{Type: models.Github}}
+	gitlabRepo := models.Repo{Owner: "acme/infra", VCSHost: models.VCSHost{Type: models.Gitlab}}
+	tests := []struct {
+		name          string
+		repo          models.Repo
+		policy        *valid.ApprovalPolicy
+		approvers     []string
+		teams         map[string][]string
+		codeOwners    string
+		modifiedFiles []string
+		wantFailure   string
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "pass default policy",
+			approvers: []string{"bob"},
+			wantErr:   assert.NoError,
+		},
+		{
+			name:        "fail default policy",
+			wantFailure: "Pull request has 0 approvals from people other than the author but needs 1 before running apply.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name:        "fail by count",
+			policy:      &valid.ApprovalPolicy{Count: 2},
+			approvers:   []string{"bob"},
+			wantFailure: "Pull request has 1 approvals from people other than the author but needs 2 before running apply.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name:      "pass by owner user",
+			policy:    &valid.ApprovalPolicy{Count: 2, Owners: valid.PolicyOwners{Users: []string{"Alice"}}},
+			approvers: []string{"bob", "alice"},
+			wantErr:   assert.NoError,
+		},
+		{
+			name:      "pass by owner team",
+			policy:    &valid.ApprovalPolicy{Count: 1, Owners: valid.PolicyOwners{Teams: []string{"platform"}}},
+			approvers: []string{"bob", "carol"},
+			teams:     map[string][]string{"carol": {"Platform", "platform"}},
+			wantErr:   assert.NoError,
+		},
+		{
+			name:        "fail by owners",
+			policy:      &valid.ApprovalPolicy{Count: 1, Owners: valid.PolicyOwners{Users: []string{"alice"}, Teams: []string{"platform"}}},
+			approvers:   []string{"bob"},
+			wantFailure: "Pull request must be approved by an owner before running apply. Owners are `alice` and the `platform` team.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name:          "pass by code owners",
+			policy:        &valid.ApprovalPolicy{Count: 1, CodeOwners: true},
+			approvers:     []string{"alice"},
+			codeOwners:    codeOwners,
+			modifiedFiles: []string{"network/main.tf", "network/README.md", "other/main.tf"},
+			wantErr:       assert.NoError,
+		},
+		{
+			name:          "pass by code owners team",
+			policy:        &valid.ApprovalPolicy{Count: 1, CodeOwners: true},
+			approvers:     []string{"carol"},
+			teams:         map[string][]string{"carol": {"platform"}},
+			codeOwners:    "*.tf @org/platform\n",
+			modifiedFiles: []string{"network/vpc/main.tf"},
+			wantErr:       assert.NoError,
+		},
+		{
+			name:          "fail by code owners team of another org",
+			policy:        &valid.ApprovalPolicy{Count: 1, CodeOwners: true},
+			approvers:     []string{"carol"},
+			teams:         map[string][]string{"carol": {"platform"}},
+			codeOwners:    "*.tf @other-org/platform\n",
+			modifiedFiles: []string{"network/main.tf"},
+			wantFailure:   "Pull request must be approved by a code owner of each modified file before running apply. Missing approvals for `network/main.tf` (@other-org/platform).",
+			wantErr:       assert.NoError,
+		},
+		{
+			name:          "pass by code owners gitlab group",
+			repo:          gitlabRepo,
+			policy:        &valid.ApprovalPolicy{Count: 1, CodeOwners: true},
+			approvers:     []string{"carol"},
+			teams:         map[string][]string{"carol": {"acme", "acme/infra/platform"}},
+			codeOwners:    "*.tf @acme/infra/platform\n",
+			modifiedFiles: []string{"network/main.tf"},
+			wantErr:       assert.NoError,
+		},
+		{
+			name:          "pass by code owners gitlab top-level group",
+			repo:          gitlabRepo,
+			policy:        &valid.ApprovalPolicy{Count: 1, CodeOwners: true},
+			approvers:     []string{"carol"},
+			teams:         map[string][]string{"carol": {"acme"}},
+			codeOwners:    "*.tf @acme\n",
+			modifiedFiles: []string{"network/main.tf"},
+			wantErr:       assert.NoError,
+		},
+		{
+			name:          "fail by code owners gitlab subgroup of another group",
+			repo:          gitlabRepo,
+			policy:        &valid.ApprovalPolicy{Count: 1, CodeOwners: true},
+			approvers:     []string{"carol"},
+			teams:         map[string][]string{"carol": {"acme/infra/platform"}},
+			codeOwners:    "*.tf @acme/platform\n",
+			modifiedFiles: []string{"network/main.tf"},
+			wantFailure:   "Pull request must be approved by a code owner of each modified file before running apply. Missing approvals for `network/main.tf` (@acme/platform).",
+			wantErr:       assert.NoError,
+		},
+		{
+			name:          "fail by code owners",
+			policy:        &valid.ApprovalPolicy{Count: 1, CodeOwners: true},
+			approvers:     []string{"carol"},
+			teams:         map[string][]string{"carol": {"platform"}},
+			codeOwners:    codeOwners,
+			modifiedFiles: []string{"network/main.tf", "network/vpc/main.tf"},
+			wantFailure:   "Pull request must be approved by a code owner of each modified file before running apply. Missing approvals for `network/main.tf` (@alice), `network/vpc/main.tf` (@alice).",
+			wantErr:       assert.NoError,
+		},
+		{
+			name:          "fail without CODEOWNERS",
+			policy:        &valid.ApprovalPolicy{Count: 1, CodeOwners: true},
+			approvers:     []string{"alice"},
+			modifiedFiles: []string{"network/main.tf"},
+			wantFailure:   "Pull request must be approved by code owners before running apply but there is no CODEOWNERS file on the \"main\" branch.",
+			wantErr:       assert.NoError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			repo := tt.repo
+			if repo.Owner == "" {
+				repo = githubRepo
+			}
+			vcsClient := vcsmocks.NewMockClient()
+			When(vcsClient.GetApprovers(Any[models.Repo](), Any[models.PullRequest]())).ThenReturn(tt.approvers, nil)
+			for approver, teams := range tt.teams {
+				When(vcsClient.GetTeamNamesForUser(Any[models.Repo](), Eq(models.User{Username: approver}))).ThenReturn(teams, nil)
+			}
+			When(vcsClient.SupportsSingleFileDownload(Any[models.Repo]())).ThenReturn(true)
+			When(vcsClient.GetFileContent(Any[models.PullRequest](), Any[string]())).ThenReturn(false, nil, nil)
+			if tt.codeOwners != "" {
+				When(vcsClient.GetFileContent(Eq(models.PullRequest{BaseRepo: repo, BaseBranch: "main", HeadBranch: "main"}), Eq(".github/CODEOWNERS"))).ThenReturn(true, []byte(tt.codeOwners), nil)
+			}
+			When(vcsClient.GetModifiedFiles(Any[models.Repo](), Any[models.PullRequest]())).ThenReturn(tt.modifiedFiles, nil)
+
+			ctx := command.ProjectContext{
+				ApplyRequirements: []string{raw.ApprovalPolicyRequirement},
+				ApprovalPolicy:    tt.policy,
+				Pull:              models.PullRequest{BaseRepo: repo, BaseBranch: "main", HeadBranch: "feature"},
+				RepoRelDir:        "network",
+				Workspace:         "default",
+			}
+			a := &events.DefaultCommandRequirementHandler{WorkingDir: mocks.NewMockWorkingDir(), VCSClient: vcsClient}
+			gotFailure, err := a.ValidateApplyProject("repoDir", ctx)
+			if !tt.wantErr(t, err, fmt.Sprintf("ValidateApplyProject(%v, %v)", "repoDir", ctx)) {
+				return
+			}
+			assert.Equalf(t, tt.wantFailure, gotFailure, "ValidateApplyProject(%v, %v)", "repoDir", ctx)
+		})
+	}
+}
+
 func TestAggregateApplyRequirements_ValidateImportProject(t *testing.T) {
 	repoDir := "repoDir"
 	fullRequirements := []string{
@@ -16,14 +16,18 @@ package events
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/go-github/v53/github"
 	"github.com/mcdafydd/go-azuredevops/azuredevops"
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/audit"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/metrics"
 	"github.com/runatlantis/atlantis/server/recovery"
@@ -62,6 +66,14 @@ type AzureDevopsPullGetter interface {
 	GetPullRequest(repo models.Repo, pullNum int) (*azuredevops.GitPullRequest, error)
 }
 
+//go:generate pegomock generate --package mocks -o mocks/mock_gitea_pull_getter.go GiteaPullGetter
+
+// GiteaPullGetter makes API calls to get pull requests.
+type GiteaPullGetter interface {
+	// GetPullRequest gets the pull request with id pullNum for the repo.
+	GetPullRequest(repo models.Repo, pullNum int) (*gitea.PullRequest, error)
+}
+
 //go:generate pegomock generate --package mocks -o mocks/mock_gitlab_merge_request_getter.go GitlabMergeRequestGetter
 
 // GitlabMergeRequestGetter makes API calls to get merge requests.
@@ -95,6 +107,7 @@ type DefaultCommandRunner struct {
 	VCSClient                vcs.Client
 	GithubPullGetter         GithubPullGetter
 	AzureDevopsPullGetter    AzureDevopsPullGetter
+	GiteaPullGetter          GiteaPullGetter
 	GitlabMergeRequestGetter GitlabMergeRequestGetter
 	DisableAutoplan          bool
 	EventParser              EventParsing
@@ -123,6 +136,28 @@ type DefaultCommandRunner struct {
 	PullStatusFetcher              PullStatusFetcher
 	TeamAllowlistChecker           *TeamAllowlistChecker
 	VarFileAllowlistChecker        *VarFileAllowlistChecker
+	// AuditSink records the commands that are run. If nil, commands aren't
+	// audited.
+	AuditSink audit.Sink
+	// CommitStatusUpdater is used to fail the commit statuses of queued events
+	// that are left over after a restart.
+	CommitStatusUpdater CommitStatusUpdater
+	// QueuedEventStore persists the events passed to the Enqueue methods until
+	// they've run. It must be set to use the event queue.
+	QueuedEventStore QueuedEventStore
+	// QueueWorkers is the number of queued events run at the same time.
+	QueueWorkers int
+	// ReplayQueuedEvents controls whether events left over after a restart
+	// are run again or failed.
+	ReplayQueuedEvents bool
+	// InstanceID identifies this Atlantis instance among the instances
+	// sharing QueuedEventStore. It should stay the same when the instance
+	// restarts, ex. its hostname.
+	InstanceID string
+	// QueueLease is how long the events this instance runs are kept from the
+	// other instances without being renewed. Defaults to DefaultQueueLease.
+	QueueLease time.Duration
+	eventQueue *eventQueue
 }
 
 // RunAutoplanCommand runs plan and policy_checks when a pull request is opened or updated.
@@ -155,6 +190,7 @@ func (c *DefaultCommandRunner) RunAutoplanCommand(baseRepo models.Repo, headRepo
 		HeadRepo:   headRepo,
 		PullStatus: status,
 		Trigger:    command.AutoTrigger,
+		Teams:      &command.TeamCache{},
 	}
 	if !c.validateCtxAndComment(ctx, command.Autoplan) {
 		return
@@ -171,7 +207,13 @@ func (c *DefaultCommandRunner) RunAutoplanCommand(baseRepo models.Repo, headRepo
 
 	autoPlanRunner := buildCommentCommandRunner(c, command.Plan)
 
+	start := time.Now()
 	autoPlanRunner.Run(ctx, nil)
+	// Only audit autoplans that ran since most pushes don't modify any
+	// projects.
+	if len(ctx.RunResults) > 0 {
+		audit.Record(log, c.AuditSink, audit.NewCommandEvents(audit.AutoplanSource, ctx, command.Plan, start)...)
+	}
 
 	err = c.PostWorkflowHooksCommandRunner.RunPostHooks(ctx, nil)
 
@@ -182,28 +224,86 @@ func (c *DefaultCommandRunner) RunAutoplanCommand(baseRepo models.Repo, headRepo
 
 // commentUserDoesNotHavePermissions comments on the pull request that the user
 // is not allowed to execute the command.
-func (c *DefaultCommandRunner) commentUserDoesNotHavePermissions(baseRepo models.Repo, pullNum int, user models.User, cmd *CommentCommand) {
+func (c *DefaultCommandRunner) commentUserDoesNotHavePermissions(baseRepo models.Repo, pullNum int, user models.User, cmd *CommentCommand, reason string) {
 	errMsg := fmt.Sprintf("```\nError: User @%s does not have permissions to execute '%s' command.\n```", user.Username, cmd.Name.String())
+	if reason != "" {
+		errMsg += "\n\n" + reason
+	}
 	if err := c.VCSClient.CreateComment(baseRepo, pullNum, errMsg, ""); err != nil {
 		c.Logger.Err("unable to comment on pull request: %s", err)
 	}
 }
 
-// checkUserPermissions checks if the user has permissions to execute the command
-func (c *DefaultCommandRunner) checkUserPermissions(repo models.Repo, user models.User, cmd *CommentCommand) (bool, error) {
+// checkUserPermissions checks if the user has permissions to execute the
+// command. The permissions in the server-side repo config are used if there
+// are any, otherwise the team allowlist is. If the user doesn't have
+// permission, it returns false and, if known, why. The user's teams are
+// looked up through teams so the command doesn't look them up again.
+func (c *DefaultCommandRunner) checkUserPermissions(repo models.Repo, user models.User, cmd *CommentCommand, teams *command.TeamCache) (bool, string, error) {
+	if len(c.GlobalCfg.Permissions) > 0 {
+		return c.checkPermissionRules(repo, user, cmd, teams)
+	}
 	if c.TeamAllowlistChecker == nil || !c.TeamAllowlistChecker.HasRules() {
 		// allowlist restriction is not enabled
-		return true, nil
+		return true, "", nil
 	}
-	teams, err := c.VCSClient.GetTeamNamesForUser(repo, user)
+	userTeams, err := teams.GetTeamNamesForUser(c.VCSClient, repo, user)
 	if err != nil {
-		return false, err
+		return false, "", err
 	}
-	ok := c.TeamAllowlistChecker.IsCommandAllowedForAnyTeam(teams, cmd.Name.String())
+	ok := c.TeamAllowlistChecker.IsCommandAllowedForAnyTeam(userTeams, cmd.Name.String())
 	if !ok {
-		return false, nil
+		return false, "", nil
 	}
-	return true, nil
+	return true, "", nil
+}
+
+// checkPermissionRules checks the permissions in the server-side repo config.
+// Rules limited to projects or workspaces only allow commands that select a
+// matching project with -p or workspace with -w.
+func (c *DefaultCommandRunner) checkPermissionRules(repo models.Repo, user models.User, cmd *CommentCommand, teams *command.TeamCache) (bool, string, error) {
+	permissions := c.GlobalCfg.Permissions
+	var userTeams []string
+	if permissions.HasTeamRules() {
+		var err error
+		userTeams, err = teams.GetTeamNamesForUser(c.VCSClient, repo, user)
+		if err != nil {
+			return false, "", err
+		}
+	}
+	req := valid.PermissionRequest{
+		RepoID:    repo.ID(),
+		User:      user.Username,
+		Teams:     userTeams,
+		Command:   cmd.Name.String(),
+		Project:   cmd.ProjectName,
+		Workspace: cmd.Workspace,
+	}
+	if permissions.Allows(req) {
+		return true, "", nil
+	}
+
+	rules := permissions.RulesFor(req.RepoID, req.User, req.Teams)
+	if len(rules) == 0 {
+		return false, fmt.Sprintf("No rule in the permissions config allows @%s to run commands on `%s`.", user.Username, req.RepoID), nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "In `%s`, @%s can run:\n", req.RepoID, user.Username)
+	var needsProject, needsWorkspace bool
+	for _, rule := range rules {
+		fmt.Fprintf(&b, "\n* %s", rule)
+		if rule.AllowsCommand(req.Command) {
+			needsProject = needsProject || (len(rule.Projects) > 0 && req.Project == "")
+			needsWorkspace = needsWorkspace || (len(rule.Workspaces) > 0 && req.Workspace == "")
+		}
+	}
+	if needsProject {
+		b.WriteString("\n\nUse `-p` to select one of the allowed projects.")
+	}
+	if needsWorkspace {
+		b.WriteString("\n\nUse `-w` to select one of the allowed workspaces.")
+	}
+	return false, b.String(), nil
 }
 
 // checkVarFilesInPlanCommandAllowlisted checks if paths in a 'plan' command are allowlisted.
@@ -240,14 +340,15 @@ func (c *DefaultCommandRunner) RunCommentCommand(baseRepo models.Repo, maybeHead
 	timer := scope.Timer(metrics.ExecutionTimeMetric).Start()
 	defer timer.Stop()
 
+	teams := &command.TeamCache{}
 	// Check if the user who commented has the permissions to execute the 'plan' or 'apply' commands
-	ok, err := c.checkUserPermissions(baseRepo, user, cmd)
+	ok, reason, err := c.checkUserPermissions(baseRepo, user, cmd, teams)
 	if err != nil {
 		c.Logger.Err("Unable to check user permissions: %s", err)
 		return
 	}
 	if !ok {
-		c.commentUserDoesNotHavePermissions(baseRepo, pullNum, user, cmd)
+		c.commentUserDoesNotHavePermissions(baseRepo, pullNum, user, cmd, reason)
 		return
 	}
 
@@ -281,6 +382,9 @@ func (c *DefaultCommandRunner) RunCommentCommand(baseRepo models.Repo, maybeHead
 		Trigger:             command.CommentTrigger,
 		PolicySet:           cmd.PolicySet,
 		ClearPolicyApproval: cmd.ClearPolicyApproval,
+		Targets:             cmd.Targets,
+		Replaces:            cmd.Replaces,
+		Teams:               teams,
 	}
 
 	if !c.validateCtxAndComment(ctx, cmd.Name) {
@@ -295,7 +399,11 @@ func (c *DefaultCommandRunner) RunCommentCommand(baseRepo models.Repo, maybeHead
 
 	cmdRunner := buildCommentCommandRunner(c, cmd.CommandName())
 
+	start := time.Now()
 	cmdRunner.Run(ctx, cmd)
+	if isAuditedCommand(cmd.Name) {
+		audit.Record(log, c.AuditSink, audit.NewCommandEvents(audit.CommentSource, ctx, cmd.Name, start)...)
+	}
 
 	err = c.PostWorkflowHooksCommandRunner.RunPostHooks(ctx, cmd)
 
@@ -304,6 +412,13 @@ func (c *DefaultCommandRunner) RunCommentCommand(baseRepo models.Repo, maybeHead
 	}
 }
 
+// isAuditedCommand returns true if the comment command cmdName is recorded in
+// the audit log. Unlock is audited by UnlockCommandRunner since it knows
+// whether the locks were deleted.
+func isAuditedCommand(cmdName command.Name) bool {
+	return cmdName != command.Unlock && cmdName != command.Version
+}
+
 func (c *DefaultCommandRunner) getGithubData(baseRepo models.Repo, pullNum int) (models.PullRequest, models.Repo, error) {
 	if c.GithubPullGetter == nil {
 		return models.PullRequest{}, models.Repo{}, errors.New("Atlantis not configured to support GitHub")
@@ -346,6 +461,21 @@ func (c *DefaultCommandRunner) getAzureDevopsData(baseRepo models.Repo, pullNum
 	return pull, headRepo, nil
 }
 
+func (c *DefaultCommandRunner) getGiteaData(baseRepo models.Repo, pullNum int) (models.PullRequest, models.Repo, error) {
+	if c.GiteaPullGetter == nil {
+		return models.PullRequest{}, models.Repo{}, errors.New("atlantis not configured to support Gitea")
+	}
+	giteaPull, err := c.GiteaPullGetter.GetPullRequest(baseRepo, pullNum)
+	if err != nil {
+		return models.PullRequest{}, models.Repo{}, errors.Wrap(err, "making pull request API call to Gitea")
+	}
+	pull, _, headRepo, err := c.EventParser.ParseGiteaPull(giteaPull)
+	if err != nil {
+		return pull, headRepo, errors.Wrap(err, "extracting required fields from comment data")
+	}
+	return pull, headRepo, nil
+}
+
 func (c *DefaultCommandRunner) buildLogger(repoFullName string, pullNum int) logging.SimpleLogging {
 
 	return c.Logger.WithHistory(
@@ -379,6 +509,8 @@ func (c *DefaultCommandRunner) ensureValidRepoMetadata(
 		pull = *maybePull
 	case models.AzureDevops:
 		pull, headRepo, err = c.getAzureDevopsData(baseRepo, pullNum)
+	case models.Gitea:
+		pull, headRepo, err = c.getGiteaData(baseRepo, pullNum)
 	default:
 		err = errors.New("Unknown VCS type–this is a bug")
 	}
@@ -19,16 +19,20 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/db"
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/metrics"
 
 	"github.com/google/go-github/v53/github"
 	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/audit"
+	auditmocks "github.com/runatlantis/atlantis/server/audit/mocks"
 	lockingmocks "github.com/runatlantis/atlantis/server/core/locking/mocks"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/mocks"
@@ -49,8 +53,10 @@ var workingDir events.WorkingDir
 var pendingPlanFinder *mocks.MockPendingPlanFinder
 var drainer *events.Drainer
 var deleteLockCommand *mocks.MockDeleteLockCommand
+var auditSink *auditmocks.MockSink
 var commitUpdater *mocks.MockCommitStatusUpdater
 var pullReqStatusFetcher *vcsmocks.MockPullReqStatusFetcher
+var planStalenessChecker *mocks.MockPlanStalenessChecker
 
 // TODO: refactor these into their own unit tests.
 // these were all split out from default command runner in an effort to improve
@@ -60,11 +66,13 @@ var pullUpdater *events.PullUpdater
 var autoMerger *events.AutoMerger
 var policyCheckCommandRunner *events.PolicyCheckCommandRunner
 var approvePoliciesCommandRunner *events.ApprovePoliciesCommandRunner
+var approveDestroyCommandRunner *events.ApproveDestroyCommandRunner
 var planCommandRunner *events.PlanCommandRunner
 var applyLockChecker *lockingmocks.MockApplyLockChecker
 var lockingLocker *lockingmocks.MockLocker
 var applyCommandRunner *events.ApplyCommandRunner
 var unlockCommandRunner *events.UnlockCommandRunner
+var jobCanceller *jobs.JobCanceller
 var importCommandRunner *events.ImportCommandRunner
 var preWorkflowHooksCommandRunner events.PreWorkflowHooksCommandRunner
 var postWorkflowHooksCommandRunner events.PostWorkflowHooksCommandRunner
@@ -111,9 +119,11 @@ func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.Mock
 	pendingPlanFinder = mocks.NewMockPendingPlanFinder()
 	commitUpdater = mocks.NewMockCommitStatusUpdater()
 	pullReqStatusFetcher = vcsmocks.NewMockPullReqStatusFetcher()
+	planStalenessChecker = mocks.NewMockPlanStalenessChecker()
 
 	drainer = &events.Drainer{}
 	deleteLockCommand = mocks.NewMockDeleteLockCommand()
+	auditSink = auditmocks.NewMockSink()
 	applyLockChecker = lockingmocks.NewMockApplyLockChecker()
 	lockingLocker = lockingmocks.NewMockLocker()
 
@@ -178,6 +188,8 @@ func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.Mock
 		testConfig.SilenceNoProjects,
 		testConfig.silenceVCSStatusNoProjects,
 		pullReqStatusFetcher,
+		planStalenessChecker,
+		events.NewDefaultWorkingDirLocker(),
 	)
 
 	approvePoliciesCommandRunner = events.NewApprovePoliciesCommandRunner(
@@ -191,10 +203,25 @@ func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.Mock
 		vcsClient,
 	)
 
+	approveDestroyCommandRunner = events.NewApproveDestroyCommandRunner(
+		projectCommandBuilder,
+		projectCommandRunner,
+		pullUpdater,
+		dbUpdater,
+		testConfig.SilenceNoProjects,
+	)
+
 	unlockCommandRunner = events.NewUnlockCommandRunner(
 		deleteLockCommand,
 		vcsClient,
 		testConfig.SilenceNoProjects,
+		auditSink,
+	)
+
+	jobCanceller = jobs.NewJobCanceller()
+	cancelCommandRunner := events.NewCancelCommandRunner(
+		jobCanceller,
+		vcsClient,
 	)
 
 	versionCommandRunner := events.NewVersionCommandRunner(
@@ -217,9 +244,11 @@ func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.Mock
 		command.Plan:            planCommandRunner,
 		command.Apply:           applyCommandRunner,
 		command.ApprovePolicies: approvePoliciesCommandRunner,
+		command.ApproveDestroy:  approveDestroyCommandRunner,
 		command.Unlock:          unlockCommandRunner,
 		command.Version:         versionCommandRunner,
 		command.Import:          importCommandRunner,
+		command.Cancel:          cancelCommandRunner,
 	}
 
 	preWorkflowHooksCommandRunner = mocks.NewMockPreWorkflowHooksCommandRunner()
@@ -249,6 +278,7 @@ func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.Mock
 		PreWorkflowHooksCommandRunner:  preWorkflowHooksCommandRunner,
 		PostWorkflowHooksCommandRunner: postWorkflowHooksCommandRunner,
 		PullStatusFetcher:              testConfig.backend,
+		AuditSink:                      auditSink,
 	}
 
 	return vcsClient
@@ -326,6 +356,55 @@ func TestRunCommentCommand_TeamAllowListChecker(t *testing.T) {
 	})
 }
 
+func TestRunCommentCommand_Permissions(t *testing.T) {
+	t.Run("allowed by team", func(t *testing.T) {
+		vcsClient := setup(t)
+		ch.GlobalCfg.Permissions = valid.Permissions{
+			{Teams: []string{"ops"}, Commands: []string{"plan"}},
+		}
+		var pull github.PullRequest
+		modelPull := models.PullRequest{
+			BaseRepo: testdata.GithubRepo,
+			State:    models.OpenPullState,
+		}
+		When(githubGetter.GetPullRequest(testdata.GithubRepo, testdata.Pull.Num)).ThenReturn(&pull, nil)
+		When(eventParsing.ParseGithubPull(&pull)).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+		When(vcsClient.GetTeamNamesForUser(testdata.GithubRepo, testdata.User)).ThenReturn([]string{"ops"}, nil)
+
+		ch.RunCommentCommand(testdata.GithubRepo, nil, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Plan})
+		vcsClient.VerifyWasCalledOnce().CreateComment(testdata.GithubRepo, modelPull.Num, "Ran Plan for 0 projects:", "plan")
+	})
+
+	t.Run("no matching rules", func(t *testing.T) {
+		vcsClient := setup(t)
+		ch.GlobalCfg.Permissions = valid.Permissions{
+			{Users: []string{"someone-else"}, Commands: []string{"*"}},
+		}
+
+		ch.RunCommentCommand(testdata.GithubRepo, nil, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Apply})
+		vcsClient.VerifyWasCalled(Never()).GetTeamNamesForUser(testdata.GithubRepo, testdata.User)
+		vcsClient.VerifyWasCalledOnce().CreateComment(testdata.GithubRepo, testdata.Pull.Num,
+			"```\nError: User @lkysow does not have permissions to execute 'apply' command.\n```\n\n"+
+				"No rule in the permissions config allows @lkysow to run commands on `github.com/runatlantis/atlantis`.", "")
+	})
+
+	t.Run("scoped to projects", func(t *testing.T) {
+		vcsClient := setup(t)
+		ch.GlobalCfg.Permissions = valid.Permissions{
+			{Users: []string{"lkysow"}, Commands: []string{"plan", "apply"}, Projects: []string{"staging-*"}},
+			{Users: []string{"*"}, Commands: []string{"version"}},
+		}
+
+		ch.RunCommentCommand(testdata.GithubRepo, nil, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Apply})
+		vcsClient.VerifyWasCalledOnce().CreateComment(testdata.GithubRepo, testdata.Pull.Num,
+			"```\nError: User @lkysow does not have permissions to execute 'apply' command.\n```\n\n"+
+				"In `github.com/runatlantis/atlantis`, @lkysow can run:\n\n"+
+				"* `plan`, `apply` for projects `staging-*`\n"+
+				"* `version`\n\n"+
+				"Use `-p` to select one of the allowed projects.", "")
+	})
+}
+
 func TestRunCommentCommand_ForkPRDisabled(t *testing.T) {
 	t.Log("if a command is run on a forked pull request and this is disabled atlantis should" +
 		" comment saying that this is not allowed")
@@ -619,6 +698,92 @@ func TestRunUnlockCommandFail_VCSComment(t *testing.T) {
 	vcsClient.VerifyWasCalledOnce().CreateComment(testdata.GithubRepo, testdata.Pull.Num, "Failed to delete PR locks", "unlock")
 }
 
+func TestRunUnlockCommand_Audit(t *testing.T) {
+	t.Log("if an unlock command is run, an audit event with its result should be recorded once")
+	setup(t)
+	pull := &github.PullRequest{State: github.String("open")}
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num, HeadCommit: "abc123"}
+	When(githubGetter.GetPullRequest(testdata.GithubRepo, testdata.Pull.Num)).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(pull)).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+	When(deleteLockCommand.DeleteLocksByPull(testdata.GithubRepo.FullName, testdata.Pull.Num)).ThenReturn(0, errors.New("err"))
+
+	ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Unlock})
+
+	event := auditSink.VerifyWasCalledOnce().Record(Any[audit.Event]()).GetCapturedArguments()
+	event.Time, event.Duration = time.Time{}, 0
+	Equals(t, audit.Event{
+		Source:    audit.CommentSource,
+		User:      testdata.User.Username,
+		Command:   "unlock",
+		Repo:      testdata.GithubRepo.FullName,
+		PullNum:   testdata.Pull.Num,
+		CommitSHA: "abc123",
+		Result:    audit.ErrorResult,
+		Message:   "err",
+	}, event)
+}
+
+func TestRunCancelCommand_VCSComment(t *testing.T) {
+	t.Log("if a cancel command is run, atlantis should cancel the running jobs" +
+		" for the pull request and comment with the jobs it cancelled")
+	vcsClient := setup(t)
+	pull := &github.PullRequest{State: github.String("open")}
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	When(githubGetter.GetPullRequest(testdata.GithubRepo, testdata.Pull.Num)).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(pull)).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+
+	ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Cancel})
+	vcsClient.VerifyWasCalledOnce().CreateComment(testdata.GithubRepo, testdata.Pull.Num, "There are no running Atlantis jobs for this PR to cancel.", "cancel")
+
+	defer jobCanceller.StartJob(command.ProjectContext{
+		JobID:       "1234",
+		BaseRepo:    testdata.GithubRepo,
+		Pull:        modelPull,
+		ProjectName: "project",
+		RepoRelDir:  "dir",
+		Workspace:   "default",
+	})()
+	ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Cancel})
+	vcsClient.VerifyWasCalledOnce().CreateComment(testdata.GithubRepo, testdata.Pull.Num, "Cancelled the running Atlantis jobs for this PR:\n\n* dir: `dir` workspace: `default` project: `project`", "cancel")
+	Equals(t, testdata.User.Username, jobCanceller.CancelledBy("1234"))
+}
+
+func TestRunCommentCommand_AuditProjects(t *testing.T) {
+	t.Log("if a plan command is run, an audit event should be recorded for each project")
+	setup(t)
+	pull := &github.PullRequest{State: github.String("open")}
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num, HeadCommit: "abc123"}
+	When(githubGetter.GetPullRequest(testdata.GithubRepo, testdata.Pull.Num)).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(pull)).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+	When(projectCommandBuilder.BuildPlanCommands(Any[*command.Context](), Any[*events.CommentCommand]())).ThenReturn([]command.ProjectContext{
+		{CommandName: command.Plan, ProjectName: "one", RepoRelDir: "one", Workspace: "default"},
+		{CommandName: command.Plan, ProjectName: "two", RepoRelDir: "two", Workspace: "default"},
+	}, nil)
+	When(projectCommandRunner.Plan(Any[command.ProjectContext]())).
+		ThenReturn(command.ProjectResult{Command: command.Plan, ProjectName: "one", RepoRelDir: "one", Workspace: "default", PlanSuccess: &models.PlanSuccess{}}).
+		ThenReturn(command.ProjectResult{Command: command.Plan, ProjectName: "two", RepoRelDir: "two", Workspace: "default", Error: errors.New("plan failed")})
+
+	ch.RunCommentCommand(testdata.GithubRepo, nil, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Plan})
+
+	recorded := auditSink.VerifyWasCalled(Times(2)).Record(Any[audit.Event]()).GetAllCapturedArguments()
+	for i := range recorded {
+		recorded[i].Time, recorded[i].Duration = time.Time{}, 0
+	}
+	base := audit.Event{
+		Source:    audit.CommentSource,
+		User:      testdata.User.Username,
+		Command:   "plan",
+		Repo:      testdata.GithubRepo.FullName,
+		PullNum:   testdata.Pull.Num,
+		CommitSHA: "abc123",
+		Workspace: "default",
+	}
+	one, two := base, base
+	one.Project, one.Directory, one.Result = "one", "one", audit.SuccessResult
+	two.Project, two.Directory, two.Result, two.Message = "two", "two", audit.ErrorResult, "plan failed"
+	Equals(t, []audit.Event{one, two}, recorded)
+}
+
 func TestRunAutoplanCommand_DeletePlans(t *testing.T) {
 	setup(t)
 	tmp := t.TempDir()
@@ -863,6 +1028,114 @@ func TestApprovedPoliciesUpdateFailedPolicyStatus(t *testing.T) {
 	)
 }
 
+func TestApproveDestroyUpdatesPullStatus(t *testing.T) {
+	t.Log("if \"atlantis approve_destroy\" is run by an owner the approval is saved without changing the plan status")
+	setup(t)
+	tmp := t.TempDir()
+	boltDB, err := db.New(tmp)
+	Ok(t, err)
+	dbUpdater.Backend = boltDB
+
+	pull := &github.PullRequest{
+		State: github.String("open"),
+	}
+	modelPull := models.PullRequest{
+		BaseRepo: testdata.GithubRepo,
+		State:    models.OpenPullState,
+		Num:      testdata.Pull.Num,
+	}
+	When(githubGetter.GetPullRequest(testdata.GithubRepo, testdata.Pull.Num)).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(pull)).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+
+	_, err = boltDB.UpdatePullWithResults(modelPull, []command.ProjectResult{
+		{
+			Command:     command.Plan,
+			RepoRelDir:  ".",
+			Workspace:   "default",
+			PlanSuccess: &models.PlanSuccess{},
+		},
+	})
+	Ok(t, err)
+
+	When(projectCommandBuilder.BuildApproveDestroyCommands(Any[*command.Context](), Any[*events.CommentCommand]())).ThenReturn([]command.ProjectContext{
+		{
+			CommandName: command.ApproveDestroy,
+			RepoRelDir:  ".",
+			Workspace:   "default",
+		},
+	}, nil)
+	When(projectCommandRunner.ApproveDestroy(Any[command.ProjectContext]())).ThenReturn(command.ProjectResult{
+		Command:    command.ApproveDestroy,
+		RepoRelDir: ".",
+		Workspace:  "default",
+		ApproveDestroySuccess: &models.ApproveDestroySuccess{
+			ApprovedBy: testdata.User.Username,
+		},
+	})
+
+	ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, &testdata.Pull, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.ApproveDestroy})
+
+	pullStatus, err := boltDB.GetPullStatus(modelPull)
+	Ok(t, err)
+	Equals(t, 1, len(pullStatus.Projects))
+	Equals(t, models.PlannedPlanStatus, pullStatus.Projects[0].Status)
+	Equals(t, testdata.User.Username, pullStatus.Projects[0].DestroyApprovedBy)
+}
+
+func TestApproveDestroyIgnoresUnplannedProjects(t *testing.T) {
+	t.Log("if \"atlantis approve_destroy\" is run for a project that hasn't been planned no status is saved")
+	setup(t)
+	tmp := t.TempDir()
+	boltDB, err := db.New(tmp)
+	Ok(t, err)
+	dbUpdater.Backend = boltDB
+
+	pull := &github.PullRequest{
+		State: github.String("open"),
+	}
+	modelPull := models.PullRequest{
+		BaseRepo: testdata.GithubRepo,
+		State:    models.OpenPullState,
+		Num:      testdata.Pull.Num,
+	}
+	When(githubGetter.GetPullRequest(testdata.GithubRepo, testdata.Pull.Num)).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(pull)).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+
+	_, err = boltDB.UpdatePullWithResults(modelPull, []command.ProjectResult{
+		{
+			Command:     command.Plan,
+			RepoRelDir:  ".",
+			Workspace:   "default",
+			PlanSuccess: &models.PlanSuccess{},
+		},
+	})
+	Ok(t, err)
+
+	When(projectCommandBuilder.BuildApproveDestroyCommands(Any[*command.Context](), Any[*events.CommentCommand]())).ThenReturn([]command.ProjectContext{
+		{
+			CommandName: command.ApproveDestroy,
+			RepoRelDir:  "unplanned",
+			Workspace:   "default",
+		},
+	}, nil)
+	When(projectCommandRunner.ApproveDestroy(Any[command.ProjectContext]())).ThenReturn(command.ProjectResult{
+		Command:    command.ApproveDestroy,
+		RepoRelDir: "unplanned",
+		Workspace:  "default",
+		ApproveDestroySuccess: &models.ApproveDestroySuccess{
+			ApprovedBy: testdata.User.Username,
+		},
+	})
+
+	ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, &testdata.Pull, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.ApproveDestroy})
+
+	pullStatus, err := boltDB.GetPullStatus(modelPull)
+	Ok(t, err)
+	Equals(t, 1, len(pullStatus.Projects))
+	Equals(t, ".", pullStatus.Projects[0].RepoRelDir)
+	Equals(t, "", pullStatus.Projects[0].DestroyApprovedBy)
+}
+
 func TestApplyMergeablityWhenPolicyCheckFails(t *testing.T) {
 	t.Log("if \"atlantis apply\" is run with failing policy check then apply is not performed")
 	setup(t)
@@ -45,6 +45,10 @@ const (
 	verboseFlagShort             = ""
 	clearPolicyApprovalFlagLong  = "clear-policy-approval"
 	clearPolicyApprovalFlagShort = ""
+	targetFlagLong               = "target"
+	targetFlagShort              = ""
+	replaceFlagLong              = "replace"
+	replaceFlagShort             = ""
 )
 
 // multiLineRegex is used to ignore multi-line comments since those aren't valid
@@ -73,6 +77,8 @@ type CommentBuilder interface {
 	BuildApplyComment(repoRelDir string, workspace string, project string, autoMergeDisabled bool) string
 	// BuildApprovePoliciesComment builds an approve_policies comment for the specified args.
 	BuildApprovePoliciesComment(repoRelDir string, workspace string, project string) string
+	// BuildApproveDestroyComment builds an approve_destroy comment for the specified args.
+	BuildApproveDestroyComment(repoRelDir string, workspace string, project string) string
 }
 
 // CommentParser implements CommentParsing
@@ -81,12 +87,13 @@ type CommentParser struct {
 	GitlabUser      string
 	BitbucketUser   string
 	AzureDevopsUser string
+	GiteaUser       string
 	ExecutableName  string
 	AllowCommands   []command.Name
 }
 
 // NewCommentParser returns a CommentParser
-func NewCommentParser(githubUser, gitlabUser, bitbucketUser, azureDevopsUser, executableName string, allowCommands []command.Name) *CommentParser {
+func NewCommentParser(githubUser, gitlabUser, bitbucketUser, azureDevopsUser, giteaUser, executableName string, allowCommands []command.Name) *CommentParser {
 	var commentAllowCommands []command.Name
 	for _, acceptableCommand := range command.AllCommentCommands {
 		for _, allowCommand := range allowCommands {
@@ -102,6 +109,7 @@ func NewCommentParser(githubUser, gitlabUser, bitbucketUser, azureDevopsUser, ex
 		GitlabUser:      gitlabUser,
 		BitbucketUser:   bitbucketUser,
 		AzureDevopsUser: azureDevopsUser,
+		GiteaUser:       giteaUser,
 		ExecutableName:  executableName,
 		AllowCommands:   commentAllowCommands,
 	}
@@ -135,9 +143,12 @@ type CommentParseResult struct {
 // - @GithubUser plan -w staging
 // - atlantis plan -w staging -d dir --verbose
 // - atlantis plan --verbose -- -key=value -key2 value2
+// - atlantis plan --target aws_instance.web --replace aws_instance.db
 // - atlantis unlock
+// - atlantis cancel
 // - atlantis version
 // - atlantis approve_policies
+// - atlantis approve_destroy -p project
 // - atlantis import ADDRESS ID
 func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) CommentParseResult {
 	comment := strings.TrimSpace(rawComment)
@@ -178,6 +189,8 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 		vcsUser = e.BitbucketUser
 	case models.AzureDevops:
 		vcsUser = e.AzureDevopsUser
+	case models.Gitea:
+		vcsUser = e.GiteaUser
 	}
 	executableNames := []string{"run", e.ExecutableName, "@" + vcsUser}
 	if !e.stringInSlice(executableName, executableNames) {
@@ -223,6 +236,7 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 	var policySet string
 	var clearPolicyApproval bool
 	var verbose, autoMergeDisabled bool
+	var targets, replaces []string
 	var flagSet *pflag.FlagSet
 	var name command.Name
 
@@ -235,6 +249,8 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 		flagSet.StringVarP(&workspace, workspaceFlagLong, workspaceFlagShort, "", "Switch to this Terraform workspace before planning.")
 		flagSet.StringVarP(&dir, dirFlagLong, dirFlagShort, "", "Which directory to run plan in relative to root of repo, ex. 'child/dir'.")
 		flagSet.StringVarP(&project, projectFlagLong, projectFlagShort, "", "Which project to run plan for. Refers to the name of the project configured in a repo config file. Cannot be used at same time as workspace or dir flags.")
+		flagSet.StringArrayVarP(&targets, targetFlagLong, targetFlagShort, nil, "Limit the plan to this resource address, ex. 'aws_instance.web'. Can be used more than once.")
+		flagSet.StringArrayVarP(&replaces, replaceFlagLong, replaceFlagShort, nil, "Plan to replace this resource address, ex. 'aws_instance.web'. Can be used more than once.")
 		flagSet.BoolVarP(&verbose, verboseFlagLong, verboseFlagShort, false, "Append Atlantis log to comment.")
 	case command.Apply.String():
 		name = command.Apply
@@ -255,10 +271,22 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 		flagSet.StringVarP(&policySet, policySetFlagLong, policySetFlagShort, "", "Approve policies for this project. Refers to the name of the project configured in a repo config file. Cannot be used at same time as workspace or dir flags.")
 		flagSet.BoolVarP(&clearPolicyApproval, clearPolicyApprovalFlagLong, clearPolicyApprovalFlagShort, false, "Clear any existing policy approvals.")
 		flagSet.BoolVarP(&verbose, verboseFlagLong, verboseFlagShort, false, "Append Atlantis log to comment.")
+	case command.ApproveDestroy.String():
+		name = command.ApproveDestroy
+		flagSet = pflag.NewFlagSet(command.ApproveDestroy.String(), pflag.ContinueOnError)
+		flagSet.SetOutput(io.Discard)
+		flagSet.StringVarP(&workspace, workspaceFlagLong, workspaceFlagShort, "", "Approve destroying protected resources for this Terraform workspace.")
+		flagSet.StringVarP(&dir, dirFlagLong, dirFlagShort, "", "Approve destroying protected resources for this directory, relative to root of repo, ex. 'child/dir'.")
+		flagSet.StringVarP(&project, projectFlagLong, projectFlagShort, "", "Approve destroying protected resources for this project. Refers to the name of the project configured in a repo config file. Cannot be used at same time as workspace or dir flags.")
+		flagSet.BoolVarP(&verbose, verboseFlagLong, verboseFlagShort, false, "Append Atlantis log to comment.")
 	case command.Unlock.String():
 		name = command.Unlock
 		flagSet = pflag.NewFlagSet(command.Unlock.String(), pflag.ContinueOnError)
 		flagSet.SetOutput(io.Discard)
+	case command.Cancel.String():
+		name = command.Cancel
+		flagSet = pflag.NewFlagSet(command.Cancel.String(), pflag.ContinueOnError)
+		flagSet.SetOutput(io.Discard)
 	case command.Version.String():
 		name = command.Version
 		flagSet = pflag.NewFlagSet(command.Version.String(), pflag.ContinueOnError)
@@ -296,6 +324,21 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 		return CommentParseResult{CommentResponse: e.errMarkdown(err.Error(), cmd, flagSet)}
 	}
 
+	if name == command.Plan {
+		// Targets and replaces passed to Terraform directly are treated like
+		// the flags so that they're always visible.
+		var extraTargets, extraReplaces []string
+		extraTargets, extraArgs = extractResourceArgs(extraArgs, "target")
+		extraReplaces, extraArgs = extractResourceArgs(extraArgs, "replace")
+		targets = append(targets, extraTargets...)
+		replaces = append(replaces, extraReplaces...)
+		for _, address := range append(targets, replaces...) {
+			if address == "" || strings.HasPrefix(address, "-") || strings.ContainsAny(address, " \t") {
+				return CommentParseResult{CommentResponse: e.errMarkdown(fmt.Sprintf("invalid resource address: %q", address), cmd, flagSet)}
+			}
+		}
+	}
+
 	// Use the same validation that Terraform uses: https://git.io/vxGhU. Plus
 	// we also don't allow '..'. We don't want the workspace to contain a path
 	// since we create files based on the name.
@@ -314,7 +357,7 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 	}
 
 	return CommentParseResult{
-		Command: NewCommentCommand(dir, extraArgs, name, subName, verbose, autoMergeDisabled, workspace, project, policySet, clearPolicyApproval),
+		Command: NewCommentCommand(dir, extraArgs, name, subName, verbose, autoMergeDisabled, workspace, project, policySet, clearPolicyApproval, targets, replaces),
 	}
 }
 
@@ -381,6 +424,32 @@ func (e *CommentParser) parseArgs(name command.Name, args []string, flagSet *pfl
 	return subCommand, extraArgs, ""
 }
 
+// extractResourceArgs removes the Terraform -<name> args, ex. -target=ADDRESS
+// or -target ADDRESS, from args and returns their addresses and the remaining
+// args.
+func extractResourceArgs(args []string, name string) ([]string, []string) {
+	var addresses, rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		trimmed := strings.TrimLeft(arg, "-")
+		if trimmed == arg {
+			rest = append(rest, arg)
+			continue
+		}
+		if value, ok := strings.CutPrefix(trimmed, name+"="); ok {
+			addresses = append(addresses, value)
+			continue
+		}
+		if trimmed == name && i+1 < len(args) {
+			addresses = append(addresses, args[i+1])
+			i++
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return addresses, rest
+}
+
 // BuildPlanComment builds a plan comment for the specified args.
 func (e *CommentParser) BuildPlanComment(repoRelDir string, workspace string, project string, commentArgs []string) string {
 	flags := e.buildFlags(repoRelDir, workspace, project, false)
@@ -409,6 +478,12 @@ func (e *CommentParser) BuildApprovePoliciesComment(repoRelDir string, workspace
 	return fmt.Sprintf("%s %s%s", e.ExecutableName, command.ApprovePolicies.String(), flags)
 }
 
+// BuildApproveDestroyComment builds an approve_destroy comment for the specified args.
+func (e *CommentParser) BuildApproveDestroyComment(repoRelDir string, workspace string, project string) string {
+	flags := e.buildFlags(repoRelDir, workspace, project, false)
+	return fmt.Sprintf("%s %s%s", e.ExecutableName, command.ApproveDestroy.String(), flags)
+}
+
 func (e *CommentParser) buildFlags(repoRelDir string, workspace string, project string, autoMergeDisabled bool) string {
 	// Add quotes if dir has spaces.
 	if strings.Contains(repoRelDir, " ") {
@@ -490,8 +565,10 @@ func (e *CommentParser) HelpComment() string {
 		AllowApply           bool
 		AllowUnlock          bool
 		AllowApprovePolicies bool
+		AllowApproveDestroy  bool
 		AllowImport          bool
 		AllowState           bool
+		AllowCancel          bool
 	}{
 		ExecutableName:       e.ExecutableName,
 		AllowVersion:         e.isAllowedCommand(command.Version.String()),
@@ -499,8 +576,10 @@ func (e *CommentParser) HelpComment() string {
 		AllowApply:           e.isAllowedCommand(command.Apply.String()),
 		AllowUnlock:          e.isAllowedCommand(command.Unlock.String()),
 		AllowApprovePolicies: e.isAllowedCommand(command.ApprovePolicies.String()),
+		AllowApproveDestroy:  e.isAllowedCommand(command.ApproveDestroy.String()),
 		AllowImport:          e.isAllowedCommand(command.Import.String()),
 		AllowState:           e.isAllowedCommand(command.State.String()),
+		AllowCancel:          e.isAllowedCommand(command.Cancel.String()),
 	}); err != nil {
 		return fmt.Sprintf("Failed to render template, this is a bug: %v", err)
 	}
@@ -544,10 +623,19 @@ Commands:
   unlock   Removes all atlantis locks and discards all plans for this PR.
            To unlock a specific plan you can use the Atlantis UI.
 {{- end }}
+{{- if .AllowCancel }}
+  cancel   Stops the plans, applies and other commands that are running for
+           this PR. To cancel a specific job you can use its job page.
+{{- end }}
 {{- if .AllowApprovePolicies }}
   approve_policies
            Approves all current policy checking failures for the PR.
 {{- end }}
+{{- if .AllowApproveDestroy }}
+  approve_destroy
+           Approves destroying protected resources in the current plans.
+           To approve a specific project, use the -d, -w and -p flags.
+{{- end }}
 {{- if .AllowVersion }}
   version  Print the output of 'terraform version'
 {{- end }}
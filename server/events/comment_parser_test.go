@@ -38,6 +38,7 @@ func TestNewCommentParser(t *testing.T) {
 		gitlabUser      string
 		bitbucketUser   string
 		azureDevopsUser string
+		giteaUser       string
 		executableName  string
 		allowCommands   []command.Name
 	}
@@ -68,7 +69,7 @@ func TestNewCommentParser(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equalf(t, tt.want, events.NewCommentParser(tt.args.githubUser, tt.args.gitlabUser, tt.args.bitbucketUser, tt.args.azureDevopsUser, tt.args.executableName, tt.args.allowCommands), "NewCommentParser(%v, %v, %v, %v, %v, %v)", tt.args.githubUser, tt.args.gitlabUser, tt.args.bitbucketUser, tt.args.azureDevopsUser, tt.args.executableName, tt.args.allowCommands)
+			assert.Equalf(t, tt.want, events.NewCommentParser(tt.args.githubUser, tt.args.gitlabUser, tt.args.bitbucketUser, tt.args.azureDevopsUser, tt.args.giteaUser, tt.args.executableName, tt.args.allowCommands), "NewCommentParser(%v, %v, %v, %v, %v, %v, %v)", tt.args.githubUser, tt.args.gitlabUser, tt.args.bitbucketUser, tt.args.azureDevopsUser, tt.args.giteaUser, tt.args.executableName, tt.args.allowCommands)
 		})
 	}
 }
@@ -268,6 +269,7 @@ func TestParse_InvalidCommand(t *testing.T) {
 		"gitlab-user",
 		"bitbucket-user",
 		"azure-devops-user",
+		"gitea-user",
 		"atlantis",
 		[]command.Name{
 			command.Version,
@@ -297,6 +299,8 @@ func TestParse_SubcommandUsage(t *testing.T) {
 		{"atlantis apply --help", "apply"},
 		{"atlantis approve_policies -h", "approve_policies"},
 		{"atlantis approve_policies --help", "approve_policies"},
+		{"atlantis approve_destroy -h", "approve_destroy"},
+		{"atlantis approve_destroy --help", "approve_destroy"},
 		{"atlantis import -h", "import ADDRESS ID"},
 		{"atlantis import --help", "import ADDRESS ID"},
 		{"atlantis state -h", "state [rm ADDRESS...]"},
@@ -677,7 +681,7 @@ func TestParse_Parsing(t *testing.T) {
 	}
 
 	for _, test := range cases {
-		for _, cmdName := range []string{"plan", "apply", "import 'some[\"addr\"]' id", "state rm 'some[\"addr\"]'"} {
+		for _, cmdName := range []string{"plan", "apply", "approve_destroy", "import 'some[\"addr\"]' id", "state rm 'some[\"addr\"]'"} {
 			comment := fmt.Sprintf("atlantis %s %s", cmdName, test.flags)
 			t.Run(comment, func(t *testing.T) {
 				r := commentParser.Parse(comment, models.Github)
@@ -698,6 +702,9 @@ func TestParse_Parsing(t *testing.T) {
 					Assert(t, r.Command.Name == command.ApprovePolicies, "did not parse comment %q as approve_policies command", comment)
 					Assert(t, test.expExtraArgs == actExtraArgs, "exp extra args to equal %v but got %v for comment %q", test.expExtraArgs, actExtraArgs, comment)
 				}
+				if cmdName == "approve_destroy" {
+					Assert(t, r.Command.Name == command.ApproveDestroy, "did not parse comment %q as approve_destroy command", comment)
+				}
 				if strings.HasPrefix(cmdName, "import") {
 					expExtraArgs := "some[\"addr\"] id" // import use default args with `some["addr"] id`
 					if test.expExtraArgs != "" {
@@ -720,6 +727,84 @@ func TestParse_Parsing(t *testing.T) {
 	}
 }
 
+func TestParse_ResourceTargeting(t *testing.T) {
+	cases := []struct {
+		comment      string
+		expTargets   []string
+		expReplaces  []string
+		expExtraArgs []string
+	}{
+		{
+			"atlantis plan",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"atlantis plan --target aws_instance.web --target module.vpc",
+			[]string{"aws_instance.web", "module.vpc"},
+			nil,
+			nil,
+		},
+		{
+			"atlantis plan --replace=aws_instance.db",
+			nil,
+			[]string{"aws_instance.db"},
+			nil,
+		},
+		{
+			"atlantis plan --target aws_instance.web -- -target=module.vpc -replace aws_instance.db -var foo=bar",
+			[]string{"aws_instance.web", "module.vpc"},
+			[]string{"aws_instance.db"},
+			[]string{"-var", "foo=bar"},
+		},
+		{
+			`atlantis plan -- --target='aws_instance.web["a"]' -refresh=false`,
+			[]string{`aws_instance.web["a"]`},
+			nil,
+			[]string{"-refresh=false"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.comment, func(t *testing.T) {
+			r := commentParser.Parse(c.comment, models.Github)
+			Equals(t, "", r.CommentResponse)
+			Equals(t, c.expTargets, r.Command.Targets)
+			Equals(t, c.expReplaces, r.Command.Replaces)
+			Equals(t, c.expExtraArgs, r.Command.Flags)
+		})
+	}
+}
+
+func TestParse_Cancel(t *testing.T) {
+	r := commentParser.Parse("atlantis cancel", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, command.Cancel, r.Command.Name)
+
+	r = commentParser.Parse("atlantis cancel -d .", models.Github)
+	Assert(t, strings.Contains(r.CommentResponse, "unknown shorthand flag: 'd' in -d"), "exp unknown flag error, got %q", r.CommentResponse)
+
+	r = commentParser.Parse("atlantis cancel arg", models.Github)
+	Assert(t, strings.Contains(r.CommentResponse, "unknown argument(s) – arg"), "exp unknown argument error, got %q", r.CommentResponse)
+}
+
+func TestParse_InvalidResourceAddress(t *testing.T) {
+	cases := []string{
+		"atlantis plan --target=",
+		"atlantis plan --replace --verbose",
+		"atlantis plan -- -target=",
+		"atlantis plan -- -target '-var'",
+		`atlantis plan --target "aws_instance.web aws_instance.db"`,
+	}
+	for _, c := range cases {
+		t.Run(c, func(t *testing.T) {
+			r := commentParser.Parse(c, models.Github)
+			Assert(t, strings.Contains(r.CommentResponse, "Error: invalid resource address"),
+				"For comment %q expected CommentResponse %q to contain invalid resource address", c, r.CommentResponse)
+		})
+	}
+}
+
 func TestBuildPlanApplyVersionComment(t *testing.T) {
 	cases := []struct {
 		repoRelDir        string
@@ -825,7 +910,7 @@ func TestBuildPlanApplyVersionComment(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.expPlanFlags, func(t *testing.T) {
-			for _, cmd := range []command.Name{command.Plan, command.Apply, command.Version} {
+			for _, cmd := range []command.Name{command.Plan, command.Apply, command.Version, command.ApproveDestroy} {
 				switch cmd {
 				case command.Plan:
 					actComment := commentParser.BuildPlanComment(c.repoRelDir, c.workspace, c.project, c.commentArgs)
@@ -833,6 +918,9 @@ func TestBuildPlanApplyVersionComment(t *testing.T) {
 				case command.Apply:
 					actComment := commentParser.BuildApplyComment(c.repoRelDir, c.workspace, c.project, c.autoMergeDisabled)
 					Equals(t, fmt.Sprintf("atlantis apply %s", c.expApplyFlags), actComment)
+				case command.ApproveDestroy:
+					actComment := commentParser.BuildApproveDestroyComment(c.repoRelDir, c.workspace, c.project)
+					Equals(t, fmt.Sprintf("atlantis approve_destroy %s", c.expVersionFlags), actComment)
 				}
 			}
 		})
@@ -875,8 +963,13 @@ Commands:
            To only apply a specific plan, use the -d, -w and -p flags.
   unlock   Removes all atlantis locks and discards all plans for this PR.
            To unlock a specific plan you can use the Atlantis UI.
+  cancel   Stops the plans, applies and other commands that are running for
+           this PR. To cancel a specific job you can use its job page.
   approve_policies
            Approves all current policy checking failures for the PR.
+  approve_destroy
+           Approves destroying protected resources in the current plans.
+           To approve a specific project, use the -d, -w and -p flags.
   version  Print the output of 'terraform version'
   import ADDRESS ID
            Runs 'terraform import' for the passed address resource.
@@ -1007,13 +1100,17 @@ func TestParse_VCSUsername(t *testing.T) {
 }
 
 var PlanUsage = `Usage of plan:
-  -d, --dir string         Which directory to run plan in relative to root of repo,
-                           ex. 'child/dir'.
-  -p, --project string     Which project to run plan for. Refers to the name of the
-                           project configured in a repo config file. Cannot be used
-                           at same time as workspace or dir flags.
-      --verbose            Append Atlantis log to comment.
-  -w, --workspace string   Switch to this Terraform workspace before planning.
+  -d, --dir string            Which directory to run plan in relative to root of
+                              repo, ex. 'child/dir'.
+  -p, --project string        Which project to run plan for. Refers to the name of
+                              the project configured in a repo config file. Cannot
+                              be used at same time as workspace or dir flags.
+      --replace stringArray   Plan to replace this resource address, ex.
+                              'aws_instance.web'. Can be used more than once.
+      --target stringArray    Limit the plan to this resource address, ex.
+                              'aws_instance.web'. Can be used more than once.
+      --verbose               Append Atlantis log to comment.
+  -w, --workspace string      Switch to this Terraform workspace before planning.
 `
 
 var ApplyUsage = `Usage of apply:
@@ -53,20 +53,53 @@ var _ runtime.StatusUpdater = (*DefaultCommitStatusUpdater)(nil)
 
 func (d *DefaultCommitStatusUpdater) UpdateCombined(repo models.Repo, pull models.PullRequest, status models.CommitStatus, cmdName command.Name) error {
 	src := fmt.Sprintf("%s/%s", d.StatusName, cmdName.String())
-	var descripWords string
+	return d.Client.UpdateStatus(repo, pull, status, src, combinedStatusDescription(cmdName, status), "")
+}
+
+func (d *DefaultCommitStatusUpdater) UpdateCombinedCount(repo models.Repo, pull models.PullRequest, status models.CommitStatus, cmdName command.Name, numSuccess int, numTotal int) error {
+	src := fmt.Sprintf("%s/%s", d.StatusName, cmdName.String())
+	return d.Client.UpdateStatus(repo, pull, status, src, combinedCountDescription(cmdName, numSuccess, numTotal), "")
+}
+
+func (d *DefaultCommitStatusUpdater) UpdateProject(ctx command.ProjectContext, cmdName command.Name, status models.CommitStatus, url string, result *command.ProjectResult) error {
+	src := projectStatusName(d.StatusName, ctx, cmdName)
+	return d.Client.UpdateStatus(ctx.BaseRepo, ctx.Pull, status, src, projectStatusDescription(cmdName, status, result), url)
+}
+
+func (d *DefaultCommitStatusUpdater) UpdateProjectQueued(ctx command.ProjectContext, cmdName command.Name, position int, url string) error {
+	src := projectStatusName(d.StatusName, ctx, cmdName)
+	return d.Client.UpdateStatus(ctx.BaseRepo, ctx.Pull, models.PendingCommitStatus, src, queuedStatusDescription(cmdName, position), url)
+}
+
+func (d *DefaultCommitStatusUpdater) UpdatePreWorkflowHook(pull models.PullRequest, status models.CommitStatus, hookDescription string, runtimeDescription string, url string) error {
+	return d.updateWorkflowHook(pull, status, hookDescription, runtimeDescription, "pre_workflow_hook", url)
+}
+
+func (d *DefaultCommitStatusUpdater) UpdatePostWorkflowHook(pull models.PullRequest, status models.CommitStatus, hookDescription string, runtimeDescription string, url string) error {
+	return d.updateWorkflowHook(pull, status, hookDescription, runtimeDescription, "post_workflow_hook", url)
+}
+
+func (d *DefaultCommitStatusUpdater) updateWorkflowHook(pull models.PullRequest, status models.CommitStatus, hookDescription string, runtimeDescription string, workflowType string, url string) error {
+	src := fmt.Sprintf("%s/%s: %s", d.StatusName, workflowType, hookDescription)
+	return d.Client.UpdateStatus(pull.BaseRepo, pull, status, src, workflowHookDescription(status, runtimeDescription), url)
+}
+
+// combinedStatusDescription describes the combined status of cmdName.
+func combinedStatusDescription(cmdName command.Name, status models.CommitStatus) string {
 	switch status {
 	case models.PendingCommitStatus:
-		descripWords = genProjectStatusDescription(cmdName.String(), "in progress...")
+		return genProjectStatusDescription(cmdName.String(), "in progress...")
 	case models.FailedCommitStatus:
-		descripWords = genProjectStatusDescription(cmdName.String(), "failed.")
+		return genProjectStatusDescription(cmdName.String(), "failed.")
 	case models.SuccessCommitStatus:
-		descripWords = genProjectStatusDescription(cmdName.String(), "succeeded.")
+		return genProjectStatusDescription(cmdName.String(), "succeeded.")
 	}
-	return d.Client.UpdateStatus(repo, pull, status, src, descripWords, "")
+	return ""
 }
 
-func (d *DefaultCommitStatusUpdater) UpdateCombinedCount(repo models.Repo, pull models.PullRequest, status models.CommitStatus, cmdName command.Name, numSuccess int, numTotal int) error {
-	src := fmt.Sprintf("%s/%s", d.StatusName, cmdName.String())
+// combinedCountDescription describes a combined status where numSuccess out of
+// numTotal projects succeeded.
+func combinedCountDescription(cmdName command.Name, numSuccess int, numTotal int) string {
 	cmdVerb := "unknown"
 
 	switch cmdName {
@@ -78,59 +111,50 @@ func (d *DefaultCommitStatusUpdater) UpdateCombinedCount(repo models.Repo, pull
 		cmdVerb = "applied"
 	}
 
-	return d.Client.UpdateStatus(repo, pull, status, src, fmt.Sprintf("%d/%d projects %s successfully.", numSuccess, numTotal, cmdVerb), "")
+	return fmt.Sprintf("%d/%d projects %s successfully.", numSuccess, numTotal, cmdVerb)
 }
 
-func (d *DefaultCommitStatusUpdater) UpdateProject(ctx command.ProjectContext, cmdName command.Name, status models.CommitStatus, url string, result *command.ProjectResult) error {
+// projectStatusName is the name of the status of cmdName for a single project.
+func projectStatusName(statusName string, ctx command.ProjectContext, cmdName command.Name) string {
 	projectID := ctx.ProjectName
 	if projectID == "" {
 		projectID = fmt.Sprintf("%s/%s", ctx.RepoRelDir, ctx.Workspace)
 	}
-	src := fmt.Sprintf("%s/%s: %s", d.StatusName, cmdName.String(), projectID)
-	var descripWords string
-	switch status {
-	case models.PendingCommitStatus:
-		descripWords = genProjectStatusDescription(cmdName.String(), "in progress...")
-	case models.FailedCommitStatus:
-		descripWords = genProjectStatusDescription(cmdName.String(), "failed.")
-	case models.SuccessCommitStatus:
-		if result != nil && result.PlanSuccess != nil {
-			descripWords = result.PlanSuccess.DiffSummary()
-		} else {
-			descripWords = genProjectStatusDescription(cmdName.String(), "succeeded.")
-		}
-	}
-	return d.Client.UpdateStatus(ctx.BaseRepo, ctx.Pull, status, src, descripWords, url)
+	return fmt.Sprintf("%s/%s: %s", statusName, cmdName.String(), projectID)
 }
 
-func genProjectStatusDescription(cmdName, description string) string {
-	return fmt.Sprintf("%s %s", cases.Title(language.English).String(cmdName), description)
+// projectStatusDescription describes the status of cmdName for a single
+// project. Successful plans are described by their diff summary.
+func projectStatusDescription(cmdName command.Name, status models.CommitStatus, result *command.ProjectResult) string {
+	if status == models.SuccessCommitStatus && result != nil && result.PlanSuccess != nil {
+		return result.PlanSuccess.DiffSummary()
+	}
+	return combinedStatusDescription(cmdName, status)
 }
 
-func (d *DefaultCommitStatusUpdater) UpdatePreWorkflowHook(pull models.PullRequest, status models.CommitStatus, hookDescription string, runtimeDescription string, url string) error {
-	return d.updateWorkflowHook(pull, status, hookDescription, runtimeDescription, "pre_workflow_hook", url)
+// queuedStatusDescription describes a run of cmdName waiting at position in
+// the queue.
+func queuedStatusDescription(cmdName command.Name, position int) string {
+	return genProjectStatusDescription(cmdName.String(), fmt.Sprintf("queued at position %d...", position))
 }
 
-func (d *DefaultCommitStatusUpdater) UpdatePostWorkflowHook(pull models.PullRequest, status models.CommitStatus, hookDescription string, runtimeDescription string, url string) error {
-	return d.updateWorkflowHook(pull, status, hookDescription, runtimeDescription, "post_workflow_hook", url)
-}
-
-func (d *DefaultCommitStatusUpdater) updateWorkflowHook(pull models.PullRequest, status models.CommitStatus, hookDescription string, runtimeDescription string, workflowType string, url string) error {
-	src := fmt.Sprintf("%s/%s: %s", d.StatusName, workflowType, hookDescription)
-
-	var descripWords string
+// workflowHookDescription describes the status of a workflow hook, preferring
+// the description the hook printed itself.
+func workflowHookDescription(status models.CommitStatus, runtimeDescription string) string {
 	if runtimeDescription != "" {
-		descripWords = runtimeDescription
-	} else {
-		switch status {
-		case models.PendingCommitStatus:
-			descripWords = "in progress..."
-		case models.FailedCommitStatus:
-			descripWords = "failed."
-		case models.SuccessCommitStatus:
-			descripWords = "succeeded."
-		}
+		return runtimeDescription
+	}
+	switch status {
+	case models.PendingCommitStatus:
+		return "in progress..."
+	case models.FailedCommitStatus:
+		return "failed."
+	case models.SuccessCommitStatus:
+		return "succeeded."
 	}
+	return ""
+}
 
-	return d.Client.UpdateStatus(pull.BaseRepo, pull, status, src, descripWords, url)
+func genProjectStatusDescription(cmdName, description string) string {
+	return fmt.Sprintf("%s %s", cases.Title(language.English).String(cmdName), description)
 }
@@ -211,6 +211,21 @@ func TestDefaultCommitStatusUpdater_UpdateProject(t *testing.T) {
 			},
 			expDescrip: "Plan: 1 to add, 2 to change, 3 to destroy.",
 		},
+		{
+			status: models.SuccessCommitStatus,
+			cmd:    command.Plan,
+			result: &command.ProjectResult{
+				PlanSuccess: &models.PlanSuccess{
+					TerraformOutput: "Plan: 1 to add, 2 to change, 3 to destroy.",
+					Analysis: &models.PlanAnalysis{
+						ResourceChanges: []models.ResourceChange{
+							{Address: "aws_instance.web", Action: models.ReplaceResourceAction},
+						},
+					},
+				},
+			},
+			expDescrip: "Plan: 1 to add, 0 to change, 1 to destroy.",
+		},
 		{
 			status:     models.PendingCommitStatus,
 			cmd:        command.Apply,
@@ -258,3 +273,16 @@ func TestDefaultCommitStatusUpdater_UpdateProjectCustomStatusName(t *testing.T)
 	client.VerifyWasCalledOnce().UpdateStatus(models.Repo{}, models.PullRequest{},
 		models.SuccessCommitStatus, "custom/apply: ./default", "Apply succeeded.", "url")
 }
+
+func TestDefaultCommitStatusUpdater_UpdateProjectQueued(t *testing.T) {
+	RegisterMockTestingT(t)
+	client := mocks.NewMockClient()
+	s := events.DefaultCommitStatusUpdater{Client: client, StatusName: "atlantis"}
+	err := s.UpdateProjectQueued(command.ProjectContext{
+		RepoRelDir: ".",
+		Workspace:  "default",
+	}, command.Plan, 3, "url")
+	Ok(t, err)
+	client.VerifyWasCalledOnce().UpdateStatus(models.Repo{}, models.PullRequest{},
+		models.PendingCommitStatus, "atlantis/plan: ./default", "Plan queued at position 3...", "url")
+}
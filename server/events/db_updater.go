@@ -6,8 +6,17 @@ import (
 	"github.com/runatlantis/atlantis/server/events/models"
 )
 
+// PullStatusNotifier is told when the status of a pull request in the
+// database changes, ex. so that the dashboard can be updated.
+type PullStatusNotifier interface {
+	// PullStatusChanged is called after pull's status was updated or deleted.
+	PullStatusChanged(pull models.PullRequest)
+}
+
 type DBUpdater struct {
 	Backend locking.Backend
+	// PullStatusNotifier, if set, is told when a pull's status is updated.
+	PullStatusNotifier PullStatusNotifier
 }
 
 func (c *DBUpdater) updateDB(ctx *command.Context, pull models.PullRequest, results []command.ProjectResult) (models.PullStatus, error) {
@@ -23,5 +32,9 @@ func (c *DBUpdater) updateDB(ctx *command.Context, pull models.PullRequest, resu
 		filtered = append(filtered, r)
 	}
 	ctx.Log.Debug("updating DB with pull results")
-	return c.Backend.UpdatePullWithResults(pull, filtered)
+	status, err := c.Backend.UpdatePullWithResults(pull, filtered)
+	if err == nil && c.PullStatusNotifier != nil {
+		c.PullStatusNotifier.PullStatusChanged(pull)
+	}
+	return status, err
 }
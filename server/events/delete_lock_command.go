@@ -21,6 +21,8 @@ type DefaultDeleteLockCommand struct {
 	WorkingDir       WorkingDir
 	WorkingDirLocker WorkingDirLocker
 	Backend          locking.Backend
+	// PullStatusNotifier, if set, is told when a pull's status is updated.
+	PullStatusNotifier PullStatusNotifier
 }
 
 // DeleteLock handles deleting the lock at id
@@ -77,5 +79,7 @@ func (l *DefaultDeleteLockCommand) deleteWorkingDir(lock models.ProjectLock) {
 	}
 	if err := l.Backend.UpdateProjectStatus(lock.Pull, lock.Workspace, lock.Project.Path, models.DiscardedPlanStatus); err != nil {
 		l.Logger.Err("unable to delete project status: %s", err)
+	} else if l.PullStatusNotifier != nil {
+		l.PullStatusNotifier.PullStatusChanged(lock.Pull)
 	}
 }
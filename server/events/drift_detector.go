@@ -0,0 +1,236 @@
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/metrics"
+	tally "github.com/uber-go/tally/v4"
+)
+
+// DriftStore persists drift detection results. It's implemented by the
+// BoltDB, Redis and PostgreSQL databases.
+type DriftStore interface {
+	// UpdateRepoDrift creates or overwrites the result at key.
+	UpdateRepoDrift(key string, drift models.RepoDrift) error
+	// ListRepoDrifts returns all results.
+	ListRepoDrifts() ([]models.RepoDrift, error)
+}
+
+// DriftDetector plans every project in a repo's config file and reports the
+// projects whose infrastructure no longer matches their configuration.
+// Projects are planned without taking project locks so drift detection never
+// blocks pull requests. They're planned as pull request
+// models.DriftDetectionPullNum so that they have their own working
+// directories.
+type DriftDetector struct {
+	VCSClient             vcs.Client
+	Parser                EventParsing
+	ProjectCommandBuilder ProjectPlanCommandBuilder
+	// ProjectCommandRunner shouldn't update commit statuses since there's no
+	// pull request to update.
+	ProjectCommandRunner ProjectPlanCommandRunner
+	Webhooks             WebhooksSender
+	Scope                tally.Scope
+	Logger               logging.SimpleLogging
+	// Store persists the results. If nil, they're only held in memory.
+	Store DriftStore
+
+	mu      sync.RWMutex
+	results map[string]models.RepoDrift
+}
+
+// Detect plans every project of the repo on branch and records the result.
+// If branch is empty, the repo's default branch is planned.
+func (d *DriftDetector) Detect(vcsHostType models.VCSHostType, repoFullName string, branch string) models.RepoDrift {
+	drift := models.RepoDrift{
+		Repository: repoFullName,
+		Branch:     branch,
+	}
+
+	repo, branch, projects, err := d.detect(vcsHostType, repoFullName, branch)
+	drift.VCSHost = repo.VCSHost.Hostname
+	drift.Branch = branch
+	drift.Projects = projects
+	drift.Time = time.Now()
+	if err != nil {
+		d.Logger.Err("detecting drift in %s on branch %s: %s", repoFullName, branch, err)
+		drift.Error = err.Error()
+	} else if drift.HasDrift() {
+		d.Logger.Warn("detected drift in %s on branch %s", repoFullName, branch)
+	} else {
+		d.Logger.Info("detected no drift in %s on branch %s", repoFullName, branch)
+	}
+
+	key := d.resultKey(vcsHostType, repoFullName)
+	if d.Store != nil {
+		if err := d.Store.UpdateRepoDrift(key, drift); err != nil {
+			d.Logger.Err("saving drift of %s: %s", repoFullName, err)
+		}
+		return drift
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.results == nil {
+		d.results = make(map[string]models.RepoDrift)
+	}
+	d.results[key] = drift
+	return drift
+}
+
+// Results returns the result of the last run for each repo sorted by repo.
+func (d *DriftDetector) Results() ([]models.RepoDrift, error) {
+	results := make([]models.RepoDrift, 0)
+	if d.Store != nil {
+		stored, err := d.Store.ListRepoDrifts()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, stored...)
+	} else {
+		d.mu.RLock()
+		for _, r := range d.results {
+			results = append(results, r)
+		}
+		d.mu.RUnlock()
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].VCSHost != results[j].VCSHost {
+			return results[i].VCSHost < results[j].VCSHost
+		}
+		return results[i].Repository < results[j].Repository
+	})
+	return results, nil
+}
+
+func (d *DriftDetector) detect(vcsHostType models.VCSHostType, repoFullName string, branch string) (models.Repo, string, []models.ProjectDrift, error) {
+	cloneURL, err := d.VCSClient.GetCloneURL(vcsHostType, repoFullName)
+	if err != nil {
+		return models.Repo{}, branch, nil, fmt.Errorf("getting clone url: %s", err)
+	}
+	repo, err := d.Parser.ParseAPIPlanRequest(vcsHostType, repoFullName, cloneURL)
+	if err != nil {
+		return models.Repo{}, branch, nil, fmt.Errorf("parsing repo: %s", err)
+	}
+	if branch == "" {
+		if branch, err = remoteDefaultBranch(repo); err != nil {
+			return repo, branch, nil, fmt.Errorf("getting default branch: %s", err)
+		}
+	}
+
+	ctx := &command.Context{
+		HeadRepo: repo,
+		Pull: models.PullRequest{
+			Num:        models.DriftDetectionPullNum,
+			BaseBranch: branch,
+			HeadBranch: branch,
+			HeadCommit: branch,
+			BaseRepo:   repo,
+		},
+		Scope: d.Scope,
+		Log:   d.Logger,
+	}
+	cmds, err := d.ProjectCommandBuilder.BuildDriftCommands(ctx)
+	if err != nil {
+		return repo, branch, nil, fmt.Errorf("building plan commands: %s", err)
+	}
+
+	scope := d.Scope.SubScope("drift")
+	projects := make([]models.ProjectDrift, 0, len(cmds))
+	for _, cmd := range cmds {
+		// Drift detection plans the branch and not a pull request so it
+		// must not lock the project.
+		cmd.RepoLocking = false
+		res := d.ProjectCommandRunner.Plan(cmd)
+
+		project := models.ProjectDrift{
+			ProjectName: cmd.ProjectName,
+			RepoRelDir:  cmd.RepoRelDir,
+			Workspace:   cmd.Workspace,
+		}
+		projectScope := cmd.SetProjectScopeTags(scope)
+		switch {
+		case res.Error != nil:
+			project.Error = res.Error.Error()
+			projectScope.Counter(metrics.ExecutionErrorMetric).Inc(1)
+		case res.Failure != "":
+			project.Error = res.Failure
+			projectScope.Counter(metrics.ExecutionFailureMetric).Inc(1)
+		case res.PlanSuccess != nil && !res.PlanSuccess.NoChanges():
+			project.Drifted = true
+			project.Summary = res.PlanSuccess.DiffSummary()
+			projectScope.Counter(metrics.DriftDetectedMetric).Inc(1)
+		default:
+			if res.PlanSuccess != nil {
+				project.Summary = res.PlanSuccess.DiffSummary()
+			}
+			projectScope.Counter(metrics.DriftNotDetectedMetric).Inc(1)
+		}
+		projects = append(projects, project)
+
+		if !project.Drifted {
+			continue
+		}
+		err := d.Webhooks.SendDrift(d.Logger, webhooks.DriftResult{
+			Workspace:   cmd.Workspace,
+			Repo:        repo,
+			Branch:      branch,
+			ProjectName: cmd.ProjectName,
+			Directory:   cmd.RepoRelDir,
+			Summary:     project.Summary,
+		})
+		if err != nil {
+			d.Logger.Warn("unable to send drift webhook: %s", err)
+		}
+	}
+	return repo, branch, projects, nil
+}
+
+// remoteDefaultBranch returns the branch that the HEAD of repo's remote
+// points to.
+func remoteDefaultBranch(repo models.Repo) (string, error) {
+	cmd := exec.Command("git", "ls-remote", "--symref", repo.CloneURL, "HEAD") // nolint: gosec
+	// Fail instead of waiting for credentials.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("running git ls-remote: %s: %s", err, strings.ReplaceAll(stderr.String(), repo.CloneURL, repo.SanitizedCloneURL))
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if ref, ok := strings.CutPrefix(line, "ref: refs/heads/"); ok {
+			branch, _, _ := strings.Cut(ref, "\t")
+			return branch, nil
+		}
+	}
+	return "", fmt.Errorf("HEAD of %s isn't a branch", repo.SanitizedCloneURL)
+}
+
+func (d *DriftDetector) resultKey(vcsHostType models.VCSHostType, repoFullName string) string {
+	return fmt.Sprintf("%s/%s", vcsHostType.String(), repoFullName)
+}
+
+// DriftDetectionJob runs drift detection for a single repo. It implements
+// scheduled.Job.
+type DriftDetectionJob struct {
+	Detector     *DriftDetector
+	VCSHostType  models.VCSHostType
+	RepoFullName string
+	Branch       string
+}
+
+func (j *DriftDetectionJob) Run() {
+	j.Detector.Detect(j.VCSHostType, j.RepoFullName, j.Branch)
+}
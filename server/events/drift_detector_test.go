@@ -0,0 +1,190 @@
+package events_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/mocks"
+	"github.com/runatlantis/atlantis/server/events/models"
+	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+	tally "github.com/uber-go/tally/v4"
+)
+
+func TestDriftDetector_Detect(t *testing.T) {
+	RegisterMockTestingT(t)
+	logger := logging.NewNoopLogger(t)
+	vcsClient := vcsmocks.NewMockClient()
+	parser := mocks.NewMockEventParsing()
+	builder := mocks.NewMockProjectCommandBuilder()
+	runner := mocks.NewMockProjectCommandRunner()
+	webhooksSender := mocks.NewMockWebhooksSender()
+	scope := tally.NewTestScope("", nil)
+	detector := &events.DriftDetector{
+		VCSClient:             vcsClient,
+		Parser:                parser,
+		ProjectCommandBuilder: builder,
+		ProjectCommandRunner:  runner,
+		Webhooks:              webhooksSender,
+		Scope:                 scope,
+		Logger:                logger,
+	}
+
+	repo, err := models.NewRepo(models.Github, "runatlantis/atlantis", "https://github.com/runatlantis/atlantis.git", "user", "token")
+	Ok(t, err)
+	When(vcsClient.GetCloneURL(models.Github, "runatlantis/atlantis")).ThenReturn(repo.CloneURL, nil)
+	When(parser.ParseAPIPlanRequest(models.Github, "runatlantis/atlantis", repo.CloneURL)).ThenReturn(repo, nil)
+
+	drifted := command.ProjectContext{
+		CommandName: command.Plan,
+		BaseRepo:    repo,
+		ProjectName: "drifted",
+		RepoRelDir:  "drifted",
+		Workspace:   "default",
+		RepoLocking: true,
+	}
+	inSync := command.ProjectContext{
+		CommandName: command.Plan,
+		BaseRepo:    repo,
+		ProjectName: "in-sync",
+		RepoRelDir:  "in-sync",
+		Workspace:   "default",
+		RepoLocking: true,
+	}
+	When(builder.BuildDriftCommands(Any[*command.Context]())).ThenReturn([]command.ProjectContext{drifted, inSync}, nil)
+	When(runner.Plan(Any[command.ProjectContext]())).Then(func(params []Param) ReturnValues {
+		ctx := params[0].(command.ProjectContext)
+		output := "No changes. Your infrastructure matches the configuration."
+		if ctx.ProjectName == "drifted" {
+			output = "Plan: 1 to add, 0 to change, 0 to destroy."
+		}
+		return ReturnValues{command.ProjectResult{PlanSuccess: &models.PlanSuccess{TerraformOutput: output}}}
+	})
+
+	drift := detector.Detect(models.Github, "runatlantis/atlantis", "main")
+	Equals(t, "", drift.Error)
+	Equals(t, "runatlantis/atlantis", drift.Repository)
+	Equals(t, "github.com", drift.VCSHost)
+	Equals(t, "main", drift.Branch)
+	Equals(t, []models.ProjectDrift{
+		{
+			ProjectName: "drifted",
+			RepoRelDir:  "drifted",
+			Workspace:   "default",
+			Drifted:     true,
+			Summary:     "Plan: 1 to add, 0 to change, 0 to destroy.",
+		},
+		{
+			ProjectName: "in-sync",
+			RepoRelDir:  "in-sync",
+			Workspace:   "default",
+			Summary:     "No changes. Your infrastructure matches the configuration.",
+		},
+	}, drift.Projects)
+	results, err := detector.Results()
+	Ok(t, err)
+	Equals(t, []models.RepoDrift{drift}, results)
+
+	ctx := builder.VerifyWasCalledOnce().BuildDriftCommands(Any[*command.Context]()).GetCapturedArguments()
+	Equals(t, "main", ctx.Pull.HeadBranch)
+	Equals(t, repo, ctx.Pull.BaseRepo)
+	Equals(t, models.DriftDetectionPullNum, ctx.Pull.Num)
+
+	t.Log("projects should be planned without locking them")
+	for _, planCtx := range runner.VerifyWasCalled(Times(2)).Plan(Any[command.ProjectContext]()).GetAllCapturedArguments() {
+		Equals(t, false, planCtx.RepoLocking)
+	}
+
+	t.Log("a webhook should only be sent for the drifted project")
+	webhooksSender.VerifyWasCalledOnce().SendDrift(Any[logging.SimpleLogging](), Eq(webhooks.DriftResult{
+		Workspace:   "default",
+		Repo:        repo,
+		Branch:      "main",
+		ProjectName: "drifted",
+		Directory:   "drifted",
+		Summary:     "Plan: 1 to add, 0 to change, 0 to destroy.",
+	}))
+
+	counters := scope.Snapshot().Counters()
+	Assert(t, len(counters) == 2, "expected 2 counters, got %d", len(counters))
+	for _, c := range counters {
+		Equals(t, int64(1), c.Value())
+		switch c.Tags()["project"] {
+		case "drifted":
+			Equals(t, "drift.drift_detected", c.Name())
+		case "in-sync":
+			Equals(t, "drift.drift_not_detected", c.Name())
+		default:
+			t.Errorf("unexpected counter %s", c.Name())
+		}
+	}
+}
+
+func TestDriftDetector_DetectError(t *testing.T) {
+	RegisterMockTestingT(t)
+	vcsClient := vcsmocks.NewMockClient()
+	detector := &events.DriftDetector{
+		VCSClient:             vcsClient,
+		Parser:                mocks.NewMockEventParsing(),
+		ProjectCommandBuilder: mocks.NewMockProjectCommandBuilder(),
+		ProjectCommandRunner:  mocks.NewMockProjectCommandRunner(),
+		Webhooks:              mocks.NewMockWebhooksSender(),
+		Scope:                 tally.NewTestScope("", nil),
+		Logger:                logging.NewNoopLogger(t),
+	}
+	When(vcsClient.GetCloneURL(models.Github, "runatlantis/atlantis")).ThenReturn("", errors.New("not found"))
+
+	drift := detector.Detect(models.Github, "runatlantis/atlantis", "main")
+	Equals(t, "getting clone url: not found", drift.Error)
+	Equals(t, 0, len(drift.Projects))
+	results, err := detector.Results()
+	Ok(t, err)
+	Equals(t, []models.RepoDrift{drift}, results)
+}
+
+func TestDriftDetector_DefaultBranchAndStore(t *testing.T) {
+	RegisterMockTestingT(t)
+	vcsClient := vcsmocks.NewMockClient()
+	parser := mocks.NewMockEventParsing()
+	builder := mocks.NewMockProjectCommandBuilder()
+	store, err := db.New(t.TempDir())
+	Ok(t, err)
+	newDetector := func() *events.DriftDetector {
+		return &events.DriftDetector{
+			VCSClient:             vcsClient,
+			Parser:                parser,
+			ProjectCommandBuilder: builder,
+			ProjectCommandRunner:  mocks.NewMockProjectCommandRunner(),
+			Webhooks:              mocks.NewMockWebhooksSender(),
+			Scope:                 tally.NewTestScope("", nil),
+			Logger:                logging.NewNoopLogger(t),
+			Store:                 store,
+		}
+	}
+
+	// The remote's HEAD points to "branch" instead of "main".
+	repoDir := initRepo(t)
+	runCmd(t, repoDir, "git", "checkout", "branch")
+	repo := models.Repo{FullName: "runatlantis/atlantis", CloneURL: repoDir, SanitizedCloneURL: repoDir}
+	When(vcsClient.GetCloneURL(models.Github, "runatlantis/atlantis")).ThenReturn(repoDir, nil)
+	When(parser.ParseAPIPlanRequest(models.Github, "runatlantis/atlantis", repoDir)).ThenReturn(repo, nil)
+
+	drift := newDetector().Detect(models.Github, "runatlantis/atlantis", "")
+	Equals(t, "", drift.Error)
+	Equals(t, "branch", drift.Branch)
+	ctx := builder.VerifyWasCalledOnce().BuildDriftCommands(Any[*command.Context]()).GetCapturedArguments()
+	Equals(t, "branch", ctx.Pull.HeadBranch)
+
+	t.Log("results should be kept by the store when Atlantis restarts")
+	results, err := newDetector().Results()
+	Ok(t, err)
+	Equals(t, 1, len(results))
+	Equals(t, "branch", results[0].Branch)
+	Equals(t, "runatlantis/atlantis", results[0].Repository)
+}
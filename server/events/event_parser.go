@@ -28,6 +28,7 @@ import (
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketcloud"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketserver"
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
 	"github.com/xanzy/go-gitlab"
 )
 
@@ -121,6 +122,12 @@ type CommentCommand struct {
 	PolicySet string
 	// ClearPolicyApproval is true if approvals should be cleared out for specified policies.
 	ClearPolicyApproval bool
+	// Targets are the resource addresses a plan is limited to, ex.
+	// atlantis plan --target aws_instance.web
+	Targets []string
+	// Replaces are the resource addresses a plan forces to be replaced, ex.
+	// atlantis plan --replace aws_instance.web
+	Replaces []string
 }
 
 // IsForSpecificProject returns true if the command is for a specific dir, workspace
@@ -152,11 +159,11 @@ func (c CommentCommand) IsAutoplan() bool {
 
 // String returns a string representation of the command.
 func (c CommentCommand) String() string {
-	return fmt.Sprintf("command=%q verbose=%t dir=%q workspace=%q project=%q policyset=%q, clear-policy-approval=%t, flags=%q", c.Name.String(), c.Verbose, c.RepoRelDir, c.Workspace, c.ProjectName, c.PolicySet, c.ClearPolicyApproval, strings.Join(c.Flags, ","))
+	return fmt.Sprintf("command=%q verbose=%t dir=%q workspace=%q project=%q policyset=%q, clear-policy-approval=%t, flags=%q, targets=%q, replaces=%q", c.Name.String(), c.Verbose, c.RepoRelDir, c.Workspace, c.ProjectName, c.PolicySet, c.ClearPolicyApproval, strings.Join(c.Flags, ","), strings.Join(c.Targets, ","), strings.Join(c.Replaces, ","))
 }
 
 // NewCommentCommand constructs a CommentCommand, setting all missing fields to defaults.
-func NewCommentCommand(repoRelDir string, flags []string, name command.Name, subName string, verbose, autoMergeDisabled bool, workspace string, project string, policySet string, clearPolicyApproval bool) *CommentCommand {
+func NewCommentCommand(repoRelDir string, flags []string, name command.Name, subName string, verbose, autoMergeDisabled bool, workspace string, project string, policySet string, clearPolicyApproval bool, targets []string, replaces []string) *CommentCommand {
 	// If repoRelDir was empty we want to keep it that way to indicate that it
 	// wasn't specified in the comment.
 	if repoRelDir != "" {
@@ -176,6 +183,8 @@ func NewCommentCommand(repoRelDir string, flags []string, name command.Name, sub
 		ProjectName:         project,
 		PolicySet:           policySet,
 		ClearPolicyApproval: clearPolicyApproval,
+		Targets:             targets,
+		Replaces:            replaces,
 	}
 }
 
@@ -192,6 +201,15 @@ type EventParsing interface {
 	ParseGithubIssueCommentEvent(comment *github.IssueCommentEvent) (
 		baseRepo models.Repo, user models.User, pullNum int, err error)
 
+	// ParseGithubCheckRunEvent parses GitHub check_run requested_action
+	// events, sent when a button on one of our check runs is clicked.
+	// baseRepo is the repo that the pull request will be merged into.
+	// user is the user that clicked the button.
+	// pullNum is the number of the pull request the check run is for.
+	// cmd is the command the button runs.
+	ParseGithubCheckRunEvent(event *github.CheckRunEvent) (
+		baseRepo models.Repo, user models.User, pullNum int, cmd *CommentCommand, err error)
+
 	// ParseGithubPull parses the response from the GitHub API endpoint (not
 	// from a webhook) that returns a pull request.
 	// pull is the parsed pull request.
@@ -315,6 +333,36 @@ type EventParsing interface {
 	// ParseAzureDevopsRepo parses the response from the Azure DevOps API endpoint that
 	// returns a repo into the Atlantis model.
 	ParseAzureDevopsRepo(adRepo *azuredevops.GitRepository) (models.Repo, error)
+
+	// ParseGiteaPullEvent parses a pull request event from Gitea or Forgejo.
+	// pull is the parsed pull request.
+	// pullEventType is the type of event, for example opened/closed.
+	// baseRepo is the repo the pull request will be merged into.
+	// headRepo is the repo the pull request branch is from.
+	// user is the user that triggered the event.
+	ParseGiteaPullEvent(body []byte) (
+		pull models.PullRequest, pullEventType models.PullRequestEventType,
+		baseRepo models.Repo, headRepo models.Repo, user models.User, err error)
+
+	// ParseGiteaIssueCommentEvent parses an issue comment event from Gitea or
+	// Forgejo. Gitea models pull requests as issues so the event doesn't
+	// contain the pull request's branches, only its number.
+	// baseRepo is the repo the pull request will be merged into.
+	// user is the user that left the comment.
+	// pullNum is the number of the pull request that triggered the webhook.
+	// commentID is the ID of the comment.
+	// comment is the comment that triggered the event.
+	ParseGiteaIssueCommentEvent(body []byte) (
+		baseRepo models.Repo, user models.User, pullNum int, commentID int64,
+		comment string, err error)
+
+	// ParseGiteaPull parses the response from the Gitea API endpoint (not
+	// from a webhook) that returns a pull request.
+	// pull is the parsed pull request.
+	// baseRepo is the repo the pull request will be merged into.
+	// headRepo is the repo the pull request branch is from.
+	ParseGiteaPull(giteaPull *gitea.PullRequest) (
+		pull models.PullRequest, baseRepo models.Repo, headRepo models.Repo, err error)
 }
 
 // EventParser parses VCS events.
@@ -329,6 +377,8 @@ type EventParser struct {
 	BitbucketServerURL string
 	AzureDevopsToken   string
 	AzureDevopsUser    string
+	GiteaToken         string
+	GiteaUser          string
 }
 
 func (e *EventParser) ParseAPIPlanRequest(vcsHostType models.VCSHostType, repoFullName string, cloneURL string) (models.Repo, error) {
@@ -337,6 +387,8 @@ func (e *EventParser) ParseAPIPlanRequest(vcsHostType models.VCSHostType, repoFu
 		return models.NewRepo(vcsHostType, repoFullName, cloneURL, e.GithubUser, e.GithubToken)
 	case models.Gitlab:
 		return models.NewRepo(vcsHostType, repoFullName, cloneURL, e.GitlabUser, e.GitlabToken)
+	case models.Gitea:
+		return models.NewRepo(vcsHostType, repoFullName, cloneURL, e.GiteaUser, e.GiteaToken)
 	}
 	return models.Repo{}, fmt.Errorf("not implemented")
 }
@@ -464,6 +516,52 @@ func (e *EventParser) ParseGithubIssueCommentEvent(comment *github.IssueCommentE
 	return
 }
 
+// ParseGithubCheckRunEvent parses GitHub check_run requested_action events.
+// See EventParsing for return value docs.
+func (e *EventParser) ParseGithubCheckRunEvent(event *github.CheckRunEvent) (baseRepo models.Repo, user models.User, pullNum int, cmd *CommentCommand, err error) {
+	baseRepo, err = e.ParseGithubRepo(event.Repo)
+	if err != nil {
+		return
+	}
+	if event.GetSender().GetLogin() == "" {
+		err = errors.New("sender.login is null")
+		return
+	}
+	user = models.User{
+		Username: event.GetSender().GetLogin(),
+	}
+	checkRun := event.GetCheckRun()
+	if checkRun == nil || len(checkRun.PullRequests) == 0 {
+		err = errors.New("check_run.pull_requests is empty")
+		return
+	}
+	pullNum = checkRun.PullRequests[0].GetNumber()
+	if pullNum == 0 {
+		err = errors.New("check_run.pull_requests[0].number is null")
+		return
+	}
+	requestedAction := event.GetRequestedAction()
+	if requestedAction == nil {
+		err = errors.New("requested_action is null")
+		return
+	}
+	if requestedAction.Identifier != ApplyCheckRunAction {
+		err = fmt.Errorf("unsupported requested action %q", requestedAction.Identifier)
+		return
+	}
+	dir, workspace, project, err := parseCheckRunExternalID(checkRun.GetExternalID())
+	if err != nil {
+		return
+	}
+	// Comments can't specify a project together with a dir or workspace so
+	// neither can we.
+	if project != "" {
+		dir, workspace = "", ""
+	}
+	cmd = NewCommentCommand(dir, nil, command.Apply, "", false, false, workspace, project, "", false, nil, nil)
+	return
+}
+
 // ParseGithubPullEvent parses GitHub pull request events.
 // See EventParsing for return value docs.
 func (e *EventParser) ParseGithubPullEvent(pullEvent *github.PullRequestEvent) (pull models.PullRequest, pullEventType models.PullRequestEventType, baseRepo models.Repo, headRepo models.Repo, user models.User, err error) {
@@ -979,3 +1077,104 @@ func (e *EventParser) ParseAzureDevopsRepo(adRepo *azuredevops.GitRepository) (m
 	fullName := fmt.Sprintf("%s/%s/%s", owner, project, repo)
 	return models.NewRepo(models.AzureDevops, fullName, cloneURL, e.AzureDevopsUser, e.AzureDevopsToken)
 }
+
+// ParseGiteaPullEvent parses a pull request event from Gitea or Forgejo.
+// See EventParsing for return value docs.
+func (e *EventParser) ParseGiteaPullEvent(body []byte) (pull models.PullRequest, pullEventType models.PullRequestEventType, baseRepo models.Repo, headRepo models.Repo, user models.User, err error) {
+	var event gitea.PullRequestEvent
+	if err = json.Unmarshal(body, &event); err != nil {
+		err = errors.Wrap(err, "parsing json")
+		return
+	}
+	if err = validator.New().Struct(event); err != nil {
+		err = errors.Wrapf(err, "API response %q was missing fields", string(body))
+		return
+	}
+	pull, baseRepo, headRepo, err = e.ParseGiteaPull(event.PullRequest)
+	if err != nil {
+		return
+	}
+
+	switch *event.Action {
+	case gitea.PullOpenedAction, gitea.PullReopenedAction:
+		pullEventType = models.OpenedPullEvent
+	case gitea.PullSynchronizedAction:
+		pullEventType = models.UpdatedPullEvent
+	case gitea.PullClosedAction:
+		pullEventType = models.ClosedPullEvent
+	default:
+		pullEventType = models.OtherPullEvent
+	}
+	user = models.User{Username: *event.Sender.Login}
+	return
+}
+
+// ParseGiteaIssueCommentEvent parses an issue comment event from Gitea or
+// Forgejo.
+// See EventParsing for return value docs.
+func (e *EventParser) ParseGiteaIssueCommentEvent(body []byte) (baseRepo models.Repo, user models.User, pullNum int, commentID int64, comment string, err error) {
+	var event gitea.IssueCommentEvent
+	if err = json.Unmarshal(body, &event); err != nil {
+		err = errors.Wrap(err, "parsing json")
+		return
+	}
+	if err = validator.New().Struct(event); err != nil {
+		err = errors.Wrapf(err, "API response %q was missing fields", string(body))
+		return
+	}
+	if !event.IsPull && event.Issue.PullRequest == nil {
+		err = errors.New("comment was not on a pull request")
+		return
+	}
+	baseRepo, err = e.parseGiteaRepo(event.Repository)
+	if err != nil {
+		return
+	}
+	user = models.User{Username: *event.Comment.User.Login}
+	pullNum = *event.Issue.Number
+	commentID = *event.Comment.ID
+	comment = *event.Comment.Body
+	return
+}
+
+// ParseGiteaPull parses the response from the Gitea API endpoint (not
+// from a webhook) that returns a pull request.
+// See EventParsing for return value docs.
+func (e *EventParser) ParseGiteaPull(giteaPull *gitea.PullRequest) (pull models.PullRequest, baseRepo models.Repo, headRepo models.Repo, err error) {
+	if err = validator.New().Struct(giteaPull); err != nil {
+		err = errors.Wrap(err, "pull request was missing fields")
+		return
+	}
+	baseRepo, err = e.parseGiteaRepo(giteaPull.Base.Repo)
+	if err != nil {
+		return
+	}
+	headRepo, err = e.parseGiteaRepo(giteaPull.Head.Repo)
+	if err != nil {
+		return
+	}
+
+	pullState := models.ClosedPullState
+	if *giteaPull.State == "open" {
+		pullState = models.OpenPullState
+	}
+
+	pull = models.PullRequest{
+		Author:     *giteaPull.User.Login,
+		HeadBranch: *giteaPull.Head.Ref,
+		HeadCommit: *giteaPull.Head.SHA,
+		URL:        *giteaPull.HTMLURL,
+		Num:        *giteaPull.Number,
+		State:      pullState,
+		BaseRepo:   baseRepo,
+		BaseBranch: *giteaPull.Base.Ref,
+	}
+	return
+}
+
+func (e *EventParser) parseGiteaRepo(repo *gitea.Repository) (models.Repo, error) {
+	if err := validator.New().Struct(repo); err != nil {
+		return models.Repo{}, errors.Wrap(err, "repository was missing fields")
+	}
+	return models.NewRepo(models.Gitea, *repo.FullName, *repo.CloneURL, e.GiteaUser, e.GiteaToken)
+}
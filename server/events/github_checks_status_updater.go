@@ -0,0 +1,167 @@
+package events
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+)
+
+// ApplyCheckRunAction is the identifier of the "Apply" button on plan check
+// runs.
+const ApplyCheckRunAction = "apply"
+
+// GithubChecksStatusUpdater implements CommitStatusUpdater with GitHub check
+// runs instead of commit statuses. The check runs are named like the commit
+// statuses would be so branch protection rules keep working. Pull requests
+// that aren't on GitHub are updated with Fallback.
+type GithubChecksStatusUpdater struct {
+	Client vcs.GithubCheckRunUpdater
+	// StatusName is the name used to identify Atlantis when creating check
+	// runs.
+	StatusName string
+	// ApplyDisabled is true if apply commands are disabled, in which case
+	// plans don't get an "Apply" button.
+	ApplyDisabled bool
+	Fallback      *DefaultCommitStatusUpdater
+}
+
+var _ runtime.StatusUpdater = (*GithubChecksStatusUpdater)(nil)
+
+func (g *GithubChecksStatusUpdater) UpdateCombined(repo models.Repo, pull models.PullRequest, status models.CommitStatus, cmdName command.Name) error {
+	if repo.VCSHost.Type != models.Github {
+		return g.Fallback.UpdateCombined(repo, pull, status, cmdName)
+	}
+	return g.Client.UpdateCheckRun(repo, pull, vcs.CheckRun{
+		Name:   fmt.Sprintf("%s/%s", g.StatusName, cmdName.String()),
+		Status: status,
+		Title:  combinedStatusDescription(cmdName, status),
+	})
+}
+
+func (g *GithubChecksStatusUpdater) UpdateCombinedCount(repo models.Repo, pull models.PullRequest, status models.CommitStatus, cmdName command.Name, numSuccess int, numTotal int) error {
+	if repo.VCSHost.Type != models.Github {
+		return g.Fallback.UpdateCombinedCount(repo, pull, status, cmdName, numSuccess, numTotal)
+	}
+	return g.Client.UpdateCheckRun(repo, pull, vcs.CheckRun{
+		Name:   fmt.Sprintf("%s/%s", g.StatusName, cmdName.String()),
+		Status: status,
+		Title:  combinedCountDescription(cmdName, numSuccess, numTotal),
+	})
+}
+
+// UpdateProject creates or updates the check run of a single project. Once
+// the command has finished, its output is shown on the check run. Plans with
+// changes also get an annotation per changed resource and an "Apply" button.
+func (g *GithubChecksStatusUpdater) UpdateProject(ctx command.ProjectContext, cmdName command.Name, status models.CommitStatus, url string, result *command.ProjectResult) error {
+	if ctx.BaseRepo.VCSHost.Type != models.Github {
+		return g.Fallback.UpdateProject(ctx, cmdName, status, url, result)
+	}
+	checkRun := vcs.CheckRun{
+		Name:       projectStatusName(g.StatusName, ctx, cmdName),
+		Status:     status,
+		Title:      projectStatusDescription(cmdName, status, result),
+		DetailsURL: url,
+		ExternalID: checkRunExternalID(ctx),
+	}
+	if result != nil {
+		switch {
+		case result.Error != nil:
+			checkRun.Summary = fmt.Sprintf("```\n%s\n```", result.Error)
+		case result.Failure != "":
+			checkRun.Summary = result.Failure
+		case result.PlanSuccess != nil:
+			checkRun.Summary = result.PlanSuccess.Summary()
+			checkRun.Text = fmt.Sprintf("```diff\n%s\n```", result.PlanSuccess.TerraformOutput)
+			checkRun.Annotations = planAnnotations(ctx.RepoRelDir, result.PlanSuccess.Analysis)
+			if cmdName == command.Plan && !g.ApplyDisabled && !result.PlanSuccess.NoChanges() {
+				checkRun.Actions = []vcs.CheckRunAction{{
+					Label:       "Apply",
+					Description: "Apply this plan.",
+					Identifier:  ApplyCheckRunAction,
+				}}
+			}
+		case result.ApplySuccess != "":
+			checkRun.Text = fmt.Sprintf("```\n%s\n```", result.ApplySuccess)
+		}
+	}
+	return g.Client.UpdateCheckRun(ctx.BaseRepo, ctx.Pull, checkRun)
+}
+
+func (g *GithubChecksStatusUpdater) UpdateProjectQueued(ctx command.ProjectContext, cmdName command.Name, position int, url string) error {
+	if ctx.BaseRepo.VCSHost.Type != models.Github {
+		return g.Fallback.UpdateProjectQueued(ctx, cmdName, position, url)
+	}
+	return g.Client.UpdateCheckRun(ctx.BaseRepo, ctx.Pull, vcs.CheckRun{
+		Name:       projectStatusName(g.StatusName, ctx, cmdName),
+		Status:     models.PendingCommitStatus,
+		Title:      queuedStatusDescription(cmdName, position),
+		DetailsURL: url,
+		ExternalID: checkRunExternalID(ctx),
+	})
+}
+
+// UpdatePreWorkflowHook and UpdatePostWorkflowHook keep using commit statuses
+// because workflow hooks don't have output worth showing on a check run.
+func (g *GithubChecksStatusUpdater) UpdatePreWorkflowHook(pull models.PullRequest, status models.CommitStatus, hookDescription string, runtimeDescription string, url string) error {
+	return g.Fallback.UpdatePreWorkflowHook(pull, status, hookDescription, runtimeDescription, url)
+}
+
+func (g *GithubChecksStatusUpdater) UpdatePostWorkflowHook(pull models.PullRequest, status models.CommitStatus, hookDescription string, runtimeDescription string, url string) error {
+	return g.Fallback.UpdatePostWorkflowHook(pull, status, hookDescription, runtimeDescription, url)
+}
+
+// planAnnotations annotates the declaration of each resource the plan
+// changes. Deleted and replaced resources are warnings so they stand out.
+// Resources whose declaration wasn't found are only in the plan output.
+func planAnnotations(dir string, analysis *models.PlanAnalysis) []vcs.CheckRunAnnotation {
+	if analysis == nil {
+		return nil
+	}
+	var annotations []vcs.CheckRunAnnotation
+	for _, change := range analysis.ResourceChanges {
+		if change.File == "" {
+			continue
+		}
+		level := "notice"
+		if change.Action == models.DeleteResourceAction || change.Action == models.ReplaceResourceAction {
+			level = "warning"
+		}
+		annotations = append(annotations, vcs.CheckRunAnnotation{
+			Path:      path.Join(dir, change.File),
+			StartLine: change.Line,
+			Level:     level,
+			Title:     change.Address,
+			Message:   fmt.Sprintf("Terraform will %s this resource.", change.Action),
+		})
+	}
+	return annotations
+}
+
+// checkRunExternalID encodes the project a check run is for so that requested
+// actions can be mapped back to it. See parseCheckRunExternalID.
+func checkRunExternalID(ctx command.ProjectContext) string {
+	v := url.Values{}
+	v.Set("dir", ctx.RepoRelDir)
+	v.Set("workspace", ctx.Workspace)
+	if ctx.ProjectName != "" {
+		v.Set("project", ctx.ProjectName)
+	}
+	return v.Encode()
+}
+
+// parseCheckRunExternalID decodes the project encoded by checkRunExternalID.
+func parseCheckRunExternalID(externalID string) (dir string, workspace string, project string, err error) {
+	v, err := url.ParseQuery(externalID)
+	if err != nil {
+		return "", "", "", fmt.Errorf("parsing check run external id %q: %w", externalID, err)
+	}
+	if v.Get("dir") == "" && v.Get("project") == "" {
+		return "", "", "", fmt.Errorf("check run external id %q doesn't identify a project", externalID)
+	}
+	return v.Get("dir"), v.Get("workspace"), v.Get("project"), nil
+}
@@ -0,0 +1,163 @@
+package events_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+var githubChecksRepo = models.Repo{
+	FullName: "owner/repo",
+	VCSHost:  models.VCSHost{Type: models.Github, Hostname: "github.com"},
+}
+
+func TestGithubChecksStatusUpdater_UpdateCombined(t *testing.T) {
+	RegisterMockTestingT(t)
+	client := mocks.NewMockGithubCheckRunUpdater()
+	s := events.GithubChecksStatusUpdater{Client: client, StatusName: "atlantis"}
+	pull := models.PullRequest{Num: 1}
+
+	Ok(t, s.UpdateCombined(githubChecksRepo, pull, models.PendingCommitStatus, command.Plan))
+	Ok(t, s.UpdateCombinedCount(githubChecksRepo, pull, models.SuccessCommitStatus, command.Apply, 1, 2))
+
+	client.VerifyWasCalledOnce().UpdateCheckRun(githubChecksRepo, pull, vcs.CheckRun{
+		Name:   "atlantis/plan",
+		Status: models.PendingCommitStatus,
+		Title:  "Plan in progress...",
+	})
+	client.VerifyWasCalledOnce().UpdateCheckRun(githubChecksRepo, pull, vcs.CheckRun{
+		Name:   "atlantis/apply",
+		Status: models.SuccessCommitStatus,
+		Title:  "1/2 projects applied successfully.",
+	})
+}
+
+func TestGithubChecksStatusUpdater_UpdateProject(t *testing.T) {
+	ctx := command.ProjectContext{
+		BaseRepo:   githubChecksRepo,
+		RepoRelDir: "dir1",
+		Workspace:  "default",
+	}
+	planSuccess := &models.PlanSuccess{
+		TerraformOutput: "  - aws_instance.web",
+		Analysis: &models.PlanAnalysis{
+			ResourceChanges: []models.ResourceChange{
+				{Address: "aws_instance.web", Action: models.DeleteResourceAction, File: "main.tf", Line: 3},
+				{Address: "aws_s3_bucket.logs", Action: models.CreateResourceAction, File: "logs.tf", Line: 1},
+				{Address: "module.remote.aws_iam_role.ci", Action: models.CreateResourceAction},
+			},
+		},
+	}
+	cases := []struct {
+		description   string
+		cmd           command.Name
+		status        models.CommitStatus
+		result        *command.ProjectResult
+		applyDisabled bool
+		exp           vcs.CheckRun
+	}{
+		{
+			description: "pending",
+			cmd:         command.Plan,
+			status:      models.PendingCommitStatus,
+			exp: vcs.CheckRun{
+				Title: "Plan in progress...",
+			},
+		},
+		{
+			description: "error",
+			cmd:         command.Plan,
+			status:      models.FailedCommitStatus,
+			result:      &command.ProjectResult{Error: errors.New("init failed")},
+			exp: vcs.CheckRun{
+				Title:   "Plan failed.",
+				Summary: "```\ninit failed\n```",
+			},
+		},
+		{
+			description: "plan with changes",
+			cmd:         command.Plan,
+			status:      models.SuccessCommitStatus,
+			result:      &command.ProjectResult{PlanSuccess: planSuccess},
+			exp: vcs.CheckRun{
+				Title:   "Plan: 2 to add, 0 to change, 1 to destroy.",
+				Summary: planSuccess.Summary(),
+				Text:    "```diff\n  - aws_instance.web\n```",
+				Annotations: []vcs.CheckRunAnnotation{
+					{Path: "dir1/main.tf", StartLine: 3, Level: "warning", Title: "aws_instance.web", Message: "Terraform will delete this resource."},
+					{Path: "dir1/logs.tf", StartLine: 1, Level: "notice", Title: "aws_s3_bucket.logs", Message: "Terraform will create this resource."},
+				},
+				Actions: []vcs.CheckRunAction{
+					{Label: "Apply", Description: "Apply this plan.", Identifier: "apply"},
+				},
+			},
+		},
+		{
+			description:   "plan with apply disabled",
+			cmd:           command.Plan,
+			status:        models.SuccessCommitStatus,
+			result:        &command.ProjectResult{PlanSuccess: planSuccess},
+			applyDisabled: true,
+			exp: vcs.CheckRun{
+				Title:   "Plan: 2 to add, 0 to change, 1 to destroy.",
+				Summary: planSuccess.Summary(),
+				Text:    "```diff\n  - aws_instance.web\n```",
+				Annotations: []vcs.CheckRunAnnotation{
+					{Path: "dir1/main.tf", StartLine: 3, Level: "warning", Title: "aws_instance.web", Message: "Terraform will delete this resource."},
+					{Path: "dir1/logs.tf", StartLine: 1, Level: "notice", Title: "aws_s3_bucket.logs", Message: "Terraform will create this resource."},
+				},
+			},
+		},
+		{
+			description: "apply",
+			cmd:         command.Apply,
+			status:      models.SuccessCommitStatus,
+			result:      &command.ProjectResult{ApplySuccess: "Apply complete!"},
+			exp: vcs.CheckRun{
+				Title: "Apply succeeded.",
+				Text:  "```\nApply complete!\n```",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			client := mocks.NewMockGithubCheckRunUpdater()
+			s := events.GithubChecksStatusUpdater{Client: client, StatusName: "atlantis", ApplyDisabled: c.applyDisabled}
+			Ok(t, s.UpdateProject(ctx, c.cmd, c.status, "url", c.result))
+
+			exp := c.exp
+			exp.Name = "atlantis/" + c.cmd.String() + ": dir1/default"
+			exp.Status = c.status
+			exp.DetailsURL = "url"
+			exp.ExternalID = "dir=dir1&workspace=default"
+			client.VerifyWasCalledOnce().UpdateCheckRun(githubChecksRepo, models.PullRequest{}, exp)
+		})
+	}
+}
+
+func TestGithubChecksStatusUpdater_Fallback(t *testing.T) {
+	t.Log("pull requests that aren't on GitHub should get commit statuses")
+	RegisterMockTestingT(t)
+	checksClient := mocks.NewMockGithubCheckRunUpdater()
+	client := mocks.NewMockClient()
+	s := events.GithubChecksStatusUpdater{
+		Client:     checksClient,
+		StatusName: "atlantis",
+		Fallback:   &events.DefaultCommitStatusUpdater{Client: client, StatusName: "atlantis"},
+	}
+	repo := models.Repo{VCSHost: models.VCSHost{Type: models.Gitlab}}
+
+	Ok(t, s.UpdateCombined(repo, models.PullRequest{}, models.PendingCommitStatus, command.Plan))
+
+	client.VerifyWasCalledOnce().UpdateStatus(repo, models.PullRequest{}, models.PendingCommitStatus, "atlantis/plan", "Plan in progress...", "")
+	checksClient.VerifyWasCalled(Never()).UpdateCheckRun(Any[models.Repo](), Any[models.PullRequest](), Any[vcs.CheckRun]())
+}
@@ -40,6 +40,15 @@ func (b *InstrumentedProjectCommandBuilder) BuildPlanCommands(ctx *command.Conte
 	)
 }
 
+func (b *InstrumentedProjectCommandBuilder) BuildDriftCommands(ctx *command.Context) ([]command.ProjectContext, error) {
+	return b.buildAndEmitStats(
+		"drift",
+		func() ([]command.ProjectContext, error) {
+			return b.ProjectCommandBuilder.BuildDriftCommands(ctx)
+		},
+	)
+}
+
 func (b *InstrumentedProjectCommandBuilder) BuildImportCommands(ctx *command.Context, comment *CommentCommand) ([]command.ProjectContext, error) {
 	return b.buildAndEmitStats(
 		"import",
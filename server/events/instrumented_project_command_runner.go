@@ -11,6 +11,7 @@ type IntrumentedCommandRunner interface {
 	PolicyCheck(ctx command.ProjectContext) command.ProjectResult
 	Apply(ctx command.ProjectContext) command.ProjectResult
 	ApprovePolicies(ctx command.ProjectContext) command.ProjectResult
+	ApproveDestroy(ctx command.ProjectContext) command.ProjectResult
 	Import(ctx command.ProjectContext) command.ProjectResult
 	StateRm(ctx command.ProjectContext) command.ProjectResult
 }
@@ -50,6 +51,10 @@ func (p *InstrumentedProjectCommandRunner) ApprovePolicies(ctx command.ProjectCo
 	return RunAndEmitStats(ctx, p.projectCommandRunner.ApprovePolicies, p.scope)
 }
 
+func (p *InstrumentedProjectCommandRunner) ApproveDestroy(ctx command.ProjectContext) command.ProjectResult {
+	return RunAndEmitStats(ctx, p.projectCommandRunner.ApproveDestroy, p.scope)
+}
+
 func (p *InstrumentedProjectCommandRunner) Import(ctx command.ProjectContext) command.ProjectResult {
 	return RunAndEmitStats(ctx, p.projectCommandRunner.Import, p.scope)
 }
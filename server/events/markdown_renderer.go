@@ -32,6 +32,7 @@ var (
 	applyCommandTitle           = command.Apply.TitleString()
 	policyCheckCommandTitle     = command.PolicyCheck.TitleString()
 	approvePoliciesCommandTitle = command.ApprovePolicies.TitleString()
+	approveDestroyCommandTitle  = command.ApproveDestroy.TitleString()
 	versionCommandTitle         = command.Version.TitleString()
 	importCommandTitle          = command.Import.TitleString()
 	stateCommandTitle           = command.State.TitleString()
@@ -101,6 +102,7 @@ type planSuccessData struct {
 	DisableRepoLocking       bool
 	EnableDiffMarkdownFormat bool
 	PlanStats                models.PlanSuccessStats
+	CostEstimate             *models.CostEstimate
 }
 
 type policyCheckResultsData struct {
@@ -205,6 +207,7 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 				DisableRepoLocking:       common.DisableRepoLocking,
 				EnableDiffMarkdownFormat: common.EnableDiffMarkdownFormat,
 				PlanStats:                result.PlanSuccess.Stats(),
+				CostEstimate:             result.CostEstimate,
 			}
 			if m.shouldUseWrappedTmpl(vcsHost, result.PlanSuccess.TerraformOutput) {
 				data.PlanSummary = result.PlanSuccess.Summary()
@@ -270,6 +273,8 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 			} else {
 				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("importSuccessUnwrapped"), result.ImportSuccess)
 			}
+		} else if result.ApproveDestroySuccess != nil {
+			resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("approveDestroySuccess"), result.ApproveDestroySuccess)
 		} else if result.StateRmSuccess != nil {
 			result.StateRmSuccess.Output = strings.TrimSpace(result.StateRmSuccess.Output)
 			if m.shouldUseWrappedTmpl(vcsHost, result.StateRmSuccess.Output) {
@@ -311,6 +316,8 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 		tmpl = templates.Lookup("singleProjectVersionUnsuccessful")
 	case len(resultsTmplData) == 1 && common.Command == applyCommandTitle:
 		tmpl = templates.Lookup("singleProjectApply")
+	case len(resultsTmplData) == 1 && common.Command == approveDestroyCommandTitle:
+		tmpl = templates.Lookup("singleProjectApproveDestroy")
 	case len(resultsTmplData) == 1 && common.Command == importCommandTitle:
 		tmpl = templates.Lookup("singleProjectImport")
 	case len(resultsTmplData) == 1 && common.Command == stateCommandTitle:
@@ -336,6 +343,8 @@ func (m *MarkdownRenderer) renderProjectResults(results []command.ProjectResult,
 		}
 	case common.Command == applyCommandTitle:
 		tmpl = templates.Lookup("multiProjectApply")
+	case common.Command == approveDestroyCommandTitle:
+		tmpl = templates.Lookup("multiProjectApproveDestroy")
 	case common.Command == versionCommandTitle:
 		tmpl = templates.Lookup("multiProjectVersion")
 	case common.Command == importCommandTitle:
@@ -317,6 +317,60 @@ $$$
 * :repeat: To re-run policies **plan** this project again by commenting:
     * $atlantis plan -d path -w workspace$
 
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+		{
+			"single policy check with rule results",
+			command.PolicyCheck,
+			"",
+			[]command.ProjectResult{
+				{
+					PolicyCheckResults: &models.PolicyCheckResults{
+						PolicySetResults: []models.PolicySetResult{
+							{
+								PolicySetName:  "policy1",
+								ConftestOutput: "FAIL - main.deny: aws_s3_bucket.public must not be public\nWARN - main.warn: aws_instance.web has no tags\n\n2 tests, 0 passed, 1 warning, 1 failure, 0 exceptions",
+								RuleResults: []models.PolicyRuleResult{
+									{Rule: "main.deny", Message: "aws_s3_bucket.public must not be public", Severity: models.DenyPolicySeverity},
+									{Rule: "main.warn", Message: "aws_instance.web has no tags", Severity: models.WarnPolicySeverity},
+								},
+								Revision:     "3f9c2a1",
+								Passed:       false,
+								ReqApprovals: 1,
+							},
+						},
+						LockURL:   "lock-url",
+						RePlanCmd: "atlantis plan -d path -w workspace",
+						ApplyCmd:  "atlantis apply -d path -w workspace",
+					},
+					Workspace:  "workspace",
+					RepoRelDir: "path",
+				},
+			},
+			models.Github,
+			`Ran Policy Check for dir: $path$ workspace: $workspace$
+
+#### Policy Set: $policy1$
+Revision: $3f9c2a1$
+* :no_entry: **deny** $main.deny$: aws_s3_bucket.public must not be public
+* :warning: **warn** $main.warn$: aws_instance.web has no tags
+
+
+#### Policy Approval Status:
+$$$
+policy set: policy1: requires: 1 approval(s), have: 0.
+$$$
+* :heavy_check_mark: To **approve** this project, comment:
+    * $$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To re-run policies **plan** this project again by commenting:
+    * $atlantis plan -d path -w workspace$
+
 ---
 * :fast_forward: To **apply** all unapplied plans from this pull request, comment:
     * $atlantis apply$
@@ -3016,3 +3070,394 @@ $$$
 		})
 	}
 }
+
+// Test that replacements and deletions from the structured plan are listed and
+// that the summary comes from the structured plan rather than the output.
+func TestRenderProjectResults_PlanAnalysis(t *testing.T) {
+	analysis := &models.PlanAnalysis{
+		ResourceChanges: []models.ResourceChange{
+			{Address: "aws_instance.web", Action: models.ReplaceResourceAction, ReplacePaths: []string{"ami"}},
+			{Address: "aws_instance.db", Action: models.ReplaceResourceAction, ActionReason: "replace_because_tainted"},
+			{Address: "aws_s3_bucket.logs", Action: models.DeleteResourceAction},
+			{Address: "aws_s3_bucket.new", Action: models.CreateResourceAction},
+		},
+		ResourceDrift: []models.ResourceChange{
+			{Address: "aws_security_group.web", Action: models.UpdateResourceAction},
+		},
+	}
+	cases := []struct {
+		Description string
+		Output      string
+		Expected    string
+	}{
+		{
+			"unwrapped",
+			"terraform-output",
+			`Ran Plan for dir: $path$ workspace: $workspace$
+
+$$$diff
+terraform-output
+$$$
+
+:recycle: The following resources will be **replaced**:
+* $aws_instance.db$ (tainted)
+* $aws_instance.web$ (forced by $ami$)
+
+:wastebasket: The following resources will be **destroyed**:
+* $aws_s3_bucket.logs$
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -d path -w workspace$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -d path -w workspace$
+
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+		{
+			"wrapped",
+			strings.Repeat("line\n", 14),
+			`Ran Plan for dir: $path$ workspace: $workspace$
+
+<details><summary>Show Output</summary>
+
+$$$diff
+` + strings.Repeat("line\n", 14) + `$$$
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -d path -w workspace$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -d path -w workspace$
+</details>
+
+**Note: Objects have changed outside of Terraform**
+Plan: 3 to add, 0 to change, 3 to destroy.
+:recycle: The following resources will be **replaced**:
+* $aws_instance.db$ (tainted)
+* $aws_instance.web$ (forced by $ami$)
+
+:wastebasket: The following resources will be **destroyed**:
+* $aws_s3_bucket.logs$
+
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+	}
+
+	r := events.NewMarkdownRenderer(
+		false,      // gitlabSupportsCommonMark
+		false,      // disableApplyAll
+		false,      // disableApply
+		false,      // disableMarkdownFolding
+		false,      // disableRepoLocking
+		false,      // enableDiffMarkdownFormat
+		"",         // MarkdownTemplateOverridesDir
+		"atlantis", // executableName
+		false,      // hideUnchangedPlanComments
+	)
+	for _, c := range cases {
+		t.Run(c.Description, func(t *testing.T) {
+			res := command.Result{
+				ProjectResults: []command.ProjectResult{
+					{
+						Workspace:  "workspace",
+						RepoRelDir: "path",
+						PlanSuccess: &models.PlanSuccess{
+							TerraformOutput: c.Output,
+							LockURL:         "lock-url",
+							ApplyCmd:        "atlantis apply -d path -w workspace",
+							RePlanCmd:       "atlantis plan -d path -w workspace",
+							Analysis:        analysis,
+						},
+					},
+				},
+			}
+			s := r.Render(res, command.Plan, "", "log", false, models.Github)
+			Equals(t, strings.TrimSpace(strings.Replace(c.Expected, "$", "`", -1)), strings.TrimSpace(s))
+		})
+	}
+}
+
+// Test that the cost estimate is rendered below the plan.
+func TestRenderProjectResults_CostEstimate(t *testing.T) {
+	estimate := &models.CostEstimate{
+		Currency:        "EUR",
+		PastMonthlyCost: 195.59,
+		MonthlyCost:     863.14,
+		DiffMonthlyCost: 667.55,
+		Resources: []models.ResourceCost{
+			{Address: "aws_instance.web", MonthlyCost: 742.64, DiffMonthlyCost: 700.4},
+			{Address: "aws_nat_gateway.nat", DiffMonthlyCost: -32.85},
+		},
+	}
+	cases := []struct {
+		Description string
+		Output      string
+		Expected    string
+	}{
+		{
+			"unwrapped",
+			"terraform-output",
+			`Ran Plan for dir: $path$ workspace: $workspace$
+
+$$$diff
+terraform-output
+$$$
+
+:moneybag: Monthly cost will increase by 667.55 EUR (195.59 EUR → 863.14 EUR).
+
+| Resource | Monthly cost | Change |
+| --- | ---: | ---: |
+| $aws_instance.web$ | 742.64 EUR | +700.40 EUR |
+| $aws_nat_gateway.nat$ | 0.00 EUR | -32.85 EUR |
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -d path -w workspace$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -d path -w workspace$
+
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+		{
+			"wrapped",
+			strings.Repeat("line\n", 14),
+			`Ran Plan for dir: $path$ workspace: $workspace$
+
+<details><summary>Show Output</summary>
+
+$$$diff
+` + strings.Repeat("line\n", 14) + `$$$
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -d path -w workspace$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -d path -w workspace$
+</details>
+
+:moneybag: Monthly cost will increase by 667.55 EUR (195.59 EUR → 863.14 EUR).
+
+| Resource | Monthly cost | Change |
+| --- | ---: | ---: |
+| $aws_instance.web$ | 742.64 EUR | +700.40 EUR |
+| $aws_nat_gateway.nat$ | 0.00 EUR | -32.85 EUR |
+
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+	}
+
+	r := events.NewMarkdownRenderer(false, false, false, false, false, false, "", "atlantis", false)
+	for _, c := range cases {
+		t.Run(c.Description, func(t *testing.T) {
+			res := command.Result{
+				ProjectResults: []command.ProjectResult{
+					{
+						Workspace:  "workspace",
+						RepoRelDir: "path",
+						PlanSuccess: &models.PlanSuccess{
+							TerraformOutput: c.Output,
+							LockURL:         "lock-url",
+							ApplyCmd:        "atlantis apply -d path -w workspace",
+							RePlanCmd:       "atlantis plan -d path -w workspace",
+						},
+						CostEstimate: estimate,
+					},
+				},
+			}
+			s := r.Render(res, command.Plan, "", "log", false, models.Github)
+			Equals(t, strings.TrimSpace(strings.Replace(c.Expected, "$", "`", -1)), strings.TrimSpace(s))
+		})
+	}
+}
+
+func TestRenderProjectResults_DependsOn(t *testing.T) {
+	cases := []struct {
+		Description string
+		Output      string
+		Expected    string
+	}{
+		{
+			"unwrapped",
+			"terraform-output",
+			`Ran Plan for project: $production$ dir: $production$ workspace: $default$
+
+$$$diff
+terraform-output
+$$$
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -p production$
+    * :link: Depends on $staging$, $qa$, which must be applied first
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -p production$
+
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+		{
+			"wrapped",
+			strings.Repeat("line\n", 14),
+			`Ran Plan for project: $production$ dir: $production$ workspace: $default$
+
+<details><summary>Show Output</summary>
+
+$$$diff
+` + strings.Repeat("line\n", 14) + `$$$
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -p production$
+    * :link: Depends on $staging$, $qa$, which must be applied first
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -p production$
+</details>
+
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+	}
+
+	r := events.NewMarkdownRenderer(false, false, false, false, false, false, "", "atlantis", false)
+	for _, c := range cases {
+		t.Run(c.Description, func(t *testing.T) {
+			res := command.Result{
+				ProjectResults: []command.ProjectResult{
+					{
+						Workspace:   "default",
+						RepoRelDir:  "production",
+						ProjectName: "production",
+						PlanSuccess: &models.PlanSuccess{
+							TerraformOutput: c.Output,
+							LockURL:         "lock-url",
+							ApplyCmd:        "atlantis apply -p production",
+							RePlanCmd:       "atlantis plan -p production",
+							DependsOn:       []string{"staging", "qa"},
+						},
+					},
+				},
+			}
+			s := r.Render(res, command.Plan, "", "log", false, models.Github)
+			Equals(t, strings.TrimSpace(strings.Replace(c.Expected, "$", "`", -1)), strings.TrimSpace(s))
+		})
+	}
+}
+
+func TestRenderProjectResults_PartialPlan(t *testing.T) {
+	cases := []struct {
+		Description string
+		Output      string
+		Expected    string
+	}{
+		{
+			"unwrapped",
+			"terraform-output",
+			`Ran Plan for project: $production$ dir: $production$ workspace: $default$
+
+:warning: **Partial plan:** this plan was limited to specific resources so it may not include all of this project's changes.
+* :dart: Targets $aws_instance.web$
+* :dart: Targets $module.vpc$
+* :recycle: Replaces $aws_instance.db$
+
+$$$diff
+terraform-output
+$$$
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -p production$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -p production$
+
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+		{
+			"wrapped",
+			strings.Repeat("line\n", 14),
+			`Ran Plan for project: $production$ dir: $production$ workspace: $default$
+
+:warning: **Partial plan:** this plan was limited to specific resources so it may not include all of this project's changes.
+* :dart: Targets $aws_instance.web$
+* :dart: Targets $module.vpc$
+* :recycle: Replaces $aws_instance.db$
+
+<details><summary>Show Output</summary>
+
+$$$diff
+` + strings.Repeat("line\n", 14) + `$$$
+
+* :arrow_forward: To **apply** this plan, comment:
+    * $atlantis apply -p production$
+* :put_litter_in_its_place: To **delete** this plan click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+    * $atlantis plan -p production$
+</details>
+
+---
+* :fast_forward: To **apply** all unapplied plans from this pull request, comment:
+    * $atlantis apply$
+* :put_litter_in_its_place: To delete all plans and locks for the PR, comment:
+    * $atlantis unlock$
+`,
+		},
+	}
+
+	r := events.NewMarkdownRenderer(false, false, false, false, false, false, "", "atlantis", false)
+	for _, c := range cases {
+		t.Run(c.Description, func(t *testing.T) {
+			res := command.Result{
+				ProjectResults: []command.ProjectResult{
+					{
+						Workspace:   "default",
+						RepoRelDir:  "production",
+						ProjectName: "production",
+						PlanSuccess: &models.PlanSuccess{
+							TerraformOutput: c.Output,
+							LockURL:         "lock-url",
+							ApplyCmd:        "atlantis apply -p production",
+							RePlanCmd:       "atlantis plan -p production",
+							Targets:         []string{"aws_instance.web", "module.vpc"},
+							Replaces:        []string{"aws_instance.db"},
+						},
+					},
+				},
+			}
+			s := r.Render(res, command.Plan, "", "log", false, models.Github)
+			Equals(t, strings.TrimSpace(strings.Replace(c.Expected, "$", "`", -1)), strings.TrimSpace(s))
+		})
+	}
+}
@@ -39,6 +39,21 @@ func (mock *MockCommentBuilder) BuildApplyComment(repoRelDir string, workspace s
 	return ret0
 }
 
+func (mock *MockCommentBuilder) BuildApproveDestroyComment(repoRelDir string, workspace string, project string) string {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockCommentBuilder().")
+	}
+	params := []pegomock.Param{repoRelDir, workspace, project}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("BuildApproveDestroyComment", params, []reflect.Type{reflect.TypeOf((*string)(nil)).Elem()})
+	var ret0 string
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(string)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockCommentBuilder) BuildApprovePoliciesComment(repoRelDir string, workspace string, project string) string {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockCommentBuilder().")
@@ -145,6 +160,41 @@ func (c *MockCommentBuilder_BuildApplyComment_OngoingVerification) GetAllCapture
 	return
 }
 
+func (verifier *VerifierMockCommentBuilder) BuildApproveDestroyComment(repoRelDir string, workspace string, project string) *MockCommentBuilder_BuildApproveDestroyComment_OngoingVerification {
+	params := []pegomock.Param{repoRelDir, workspace, project}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "BuildApproveDestroyComment", params, verifier.timeout)
+	return &MockCommentBuilder_BuildApproveDestroyComment_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockCommentBuilder_BuildApproveDestroyComment_OngoingVerification struct {
+	mock              *MockCommentBuilder
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockCommentBuilder_BuildApproveDestroyComment_OngoingVerification) GetCapturedArguments() (string, string, string) {
+	repoRelDir, workspace, project := c.GetAllCapturedArguments()
+	return repoRelDir[len(repoRelDir)-1], workspace[len(workspace)-1], project[len(project)-1]
+}
+
+func (c *MockCommentBuilder_BuildApproveDestroyComment_OngoingVerification) GetAllCapturedArguments() (_param0 []string, _param1 []string, _param2 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+		_param1 = make([]string, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(string)
+		}
+		_param2 = make([]string, len(c.methodInvocations))
+		for u, param := range params[2] {
+			_param2[u] = param.(string)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockCommentBuilder) BuildApprovePoliciesComment(repoRelDir string, workspace string, project string) *MockCommentBuilder_BuildApprovePoliciesComment_OngoingVerification {
 	params := []pegomock.Param{repoRelDir, workspace, project}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "BuildApprovePoliciesComment", params, verifier.timeout)
@@ -41,6 +41,36 @@ func (mock *MockWebhooksSender) Send(log logging.SimpleLogging, res webhooks.App
 	return ret0
 }
 
+func (mock *MockWebhooksSender) SendDrift(log logging.SimpleLogging, res webhooks.DriftResult) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockWebhooksSender().")
+	}
+	params := []pegomock.Param{log, res}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("SendDrift", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
+func (mock *MockWebhooksSender) SendEvent(log logging.SimpleLogging, res webhooks.EventResult) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockWebhooksSender().")
+	}
+	params := []pegomock.Param{log, res}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("SendEvent", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockWebhooksSender) VerifyWasCalledOnce() *VerifierMockWebhooksSender {
 	return &VerifierMockWebhooksSender{
 		mock:                   mock,
@@ -108,3 +138,65 @@ func (c *MockWebhooksSender_Send_OngoingVerification) GetAllCapturedArguments()
 	}
 	return
 }
+
+func (verifier *VerifierMockWebhooksSender) SendDrift(log logging.SimpleLogging, res webhooks.DriftResult) *MockWebhooksSender_SendDrift_OngoingVerification {
+	params := []pegomock.Param{log, res}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "SendDrift", params, verifier.timeout)
+	return &MockWebhooksSender_SendDrift_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockWebhooksSender_SendDrift_OngoingVerification struct {
+	mock              *MockWebhooksSender
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockWebhooksSender_SendDrift_OngoingVerification) GetCapturedArguments() (logging.SimpleLogging, webhooks.DriftResult) {
+	log, res := c.GetAllCapturedArguments()
+	return log[len(log)-1], res[len(res)-1]
+}
+
+func (c *MockWebhooksSender_SendDrift_OngoingVerification) GetAllCapturedArguments() (_param0 []logging.SimpleLogging, _param1 []webhooks.DriftResult) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]logging.SimpleLogging, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(logging.SimpleLogging)
+		}
+		_param1 = make([]webhooks.DriftResult, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(webhooks.DriftResult)
+		}
+	}
+	return
+}
+
+func (verifier *VerifierMockWebhooksSender) SendEvent(log logging.SimpleLogging, res webhooks.EventResult) *MockWebhooksSender_SendEvent_OngoingVerification {
+	params := []pegomock.Param{log, res}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "SendEvent", params, verifier.timeout)
+	return &MockWebhooksSender_SendEvent_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockWebhooksSender_SendEvent_OngoingVerification struct {
+	mock              *MockWebhooksSender
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockWebhooksSender_SendEvent_OngoingVerification) GetCapturedArguments() (logging.SimpleLogging, webhooks.EventResult) {
+	log, res := c.GetAllCapturedArguments()
+	return log[len(log)-1], res[len(res)-1]
+}
+
+func (c *MockWebhooksSender_SendEvent_OngoingVerification) GetAllCapturedArguments() (_param0 []logging.SimpleLogging, _param1 []webhooks.EventResult) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]logging.SimpleLogging, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(logging.SimpleLogging)
+		}
+		_param1 = make([]webhooks.EventResult, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(webhooks.EventResult)
+		}
+	}
+	return
+}
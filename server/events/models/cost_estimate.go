@@ -0,0 +1,165 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// CostEstimate is the estimated monthly cost of a plan.
+type CostEstimate struct {
+	// Currency is the ISO 4217 code of the amounts, ex. USD.
+	Currency string
+	// PastMonthlyCost is the monthly cost before the plan is applied.
+	PastMonthlyCost float64
+	// MonthlyCost is the monthly cost once the plan is applied.
+	MonthlyCost float64
+	// DiffMonthlyCost is how much the monthly cost changes. It's negative if
+	// the plan makes things cheaper.
+	DiffMonthlyCost float64
+	// Resources are the resources whose monthly cost changes, most
+	// expensive change first.
+	Resources []ResourceCost
+}
+
+// ResourceCost is the estimated monthly cost of a single resource.
+type ResourceCost struct {
+	// Address is the address of the resource, ex. aws_instance.web.
+	Address string
+	// MonthlyCost is the monthly cost once the plan is applied.
+	MonthlyCost float64
+	// DiffMonthlyCost is how much the monthly cost changes.
+	DiffMonthlyCost float64
+}
+
+// costJSON is the subset of the Infracost JSON output that we use. Other cost
+// tools can be used as long as they output the same format.
+// See https://www.infracost.io/docs/features/json_output_format/.
+type costJSON struct {
+	Currency             string            `json:"currency"`
+	TotalMonthlyCost     costAmount        `json:"totalMonthlyCost"`
+	PastTotalMonthlyCost costAmount        `json:"pastTotalMonthlyCost"`
+	DiffTotalMonthlyCost costAmount        `json:"diffTotalMonthlyCost"`
+	Projects             []costProjectJSON `json:"projects"`
+}
+
+type costProjectJSON struct {
+	Breakdown *costBreakdownJSON `json:"breakdown"`
+	Diff      *costBreakdownJSON `json:"diff"`
+}
+
+type costBreakdownJSON struct {
+	Resources []struct {
+		Name        string     `json:"name"`
+		MonthlyCost costAmount `json:"monthlyCost"`
+	} `json:"resources"`
+}
+
+// costAmount is an amount that can be a string, ex. "742.64", a number or
+// null. Infracost uses strings so no precision is lost.
+type costAmount float64
+
+func (c *costAmount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*c = 0
+		return nil
+	}
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	if s == "" {
+		*c = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid cost %s", data)
+	}
+	*c = costAmount(f)
+	return nil
+}
+
+// NewCostEstimate parses the JSON output of a cost tool. Any output before the
+// JSON document, ex. log lines, is ignored.
+func NewCostEstimate(output []byte) (*CostEstimate, error) {
+	start := bytes.IndexByte(output, '{')
+	if start == -1 {
+		return nil, errors.New("parsing cost estimate: no JSON object in output")
+	}
+	var parsed costJSON
+	if err := json.NewDecoder(bytes.NewReader(output[start:])).Decode(&parsed); err != nil {
+		return nil, errors.Wrap(err, "parsing cost estimate")
+	}
+
+	e := &CostEstimate{
+		Currency:        parsed.Currency,
+		PastMonthlyCost: float64(parsed.PastTotalMonthlyCost),
+		MonthlyCost:     float64(parsed.TotalMonthlyCost),
+		DiffMonthlyCost: float64(parsed.DiffTotalMonthlyCost),
+	}
+	if e.Currency == "" {
+		e.Currency = "USD"
+	}
+	for _, project := range parsed.Projects {
+		if project.Diff == nil {
+			continue
+		}
+		monthlyCosts := make(map[string]float64)
+		if project.Breakdown != nil {
+			for _, r := range project.Breakdown.Resources {
+				monthlyCosts[r.Name] = float64(r.MonthlyCost)
+			}
+		}
+		for _, r := range project.Diff.Resources {
+			if r.MonthlyCost == 0 {
+				continue
+			}
+			e.Resources = append(e.Resources, ResourceCost{
+				Address:         r.Name,
+				MonthlyCost:     monthlyCosts[r.Name],
+				DiffMonthlyCost: float64(r.MonthlyCost),
+			})
+		}
+	}
+	sort.SliceStable(e.Resources, func(i, j int) bool {
+		return math.Abs(e.Resources[i].DiffMonthlyCost) > math.Abs(e.Resources[j].DiffMonthlyCost)
+	})
+	return e, nil
+}
+
+// FormatCost formats amount in the estimate's currency, ex. $742.64.
+func (c CostEstimate) FormatCost(amount float64) string {
+	if c.Currency == "USD" {
+		if amount < 0 {
+			return fmt.Sprintf("-$%.2f", -amount)
+		}
+		return fmt.Sprintf("$%.2f", amount)
+	}
+	return fmt.Sprintf("%.2f %s", amount, c.Currency)
+}
+
+// FormatDiff formats a change in cost with its sign, ex. +$742.64.
+func (c CostEstimate) FormatDiff(amount float64) string {
+	if amount > 0 {
+		return "+" + c.FormatCost(amount)
+	}
+	return c.FormatCost(amount)
+}
+
+// Summary is a one line description of the change in monthly cost.
+func (c CostEstimate) Summary() string {
+	switch {
+	case c.DiffMonthlyCost > 0:
+		return fmt.Sprintf("Monthly cost will increase by %s (%s → %s).", c.FormatCost(c.DiffMonthlyCost), c.FormatCost(c.PastMonthlyCost), c.FormatCost(c.MonthlyCost))
+	case c.DiffMonthlyCost < 0:
+		return fmt.Sprintf("Monthly cost will decrease by %s (%s → %s).", c.FormatCost(-c.DiffMonthlyCost), c.FormatCost(c.PastMonthlyCost), c.FormatCost(c.MonthlyCost))
+	default:
+		return fmt.Sprintf("Monthly cost will not change (%s).", c.FormatCost(c.MonthlyCost))
+	}
+}
@@ -0,0 +1,52 @@
+package models_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestNewCostEstimate(t *testing.T) {
+	output, err := os.ReadFile(filepath.Join("testdata", "infracost.json"))
+	Ok(t, err)
+
+	t.Log("log lines before the JSON should be ignored")
+	estimate, err := models.NewCostEstimate(append([]byte("level=info msg=\"Evaluating plan\"\n"), output...))
+	Ok(t, err)
+	Equals(t, &models.CostEstimate{
+		Currency:        "USD",
+		PastMonthlyCost: 195.59,
+		MonthlyCost:     863.14,
+		DiffMonthlyCost: 667.55,
+		Resources: []models.ResourceCost{
+			{Address: "aws_instance.web", MonthlyCost: 742.64, DiffMonthlyCost: 700.4},
+			{Address: "aws_nat_gateway.nat", DiffMonthlyCost: -32.85},
+		},
+	}, estimate)
+	Equals(t, "Monthly cost will increase by $667.55 ($195.59 → $863.14).", estimate.Summary())
+	Equals(t, "-$32.85", estimate.FormatDiff(-32.85))
+	Equals(t, "+$700.40", estimate.FormatDiff(700.4))
+}
+
+func TestNewCostEstimate_Numbers(t *testing.T) {
+	estimate, err := models.NewCostEstimate([]byte(`{"currency": "EUR", "totalMonthlyCost": 10, "pastTotalMonthlyCost": 15.5, "diffTotalMonthlyCost": -5.5}`))
+	Ok(t, err)
+	Equals(t, &models.CostEstimate{
+		Currency:        "EUR",
+		PastMonthlyCost: 15.5,
+		MonthlyCost:     10,
+		DiffMonthlyCost: -5.5,
+	}, estimate)
+	Equals(t, "Monthly cost will decrease by 5.50 EUR (15.50 EUR → 10.00 EUR).", estimate.Summary())
+}
+
+func TestNewCostEstimate_Invalid(t *testing.T) {
+	_, err := models.NewCostEstimate([]byte("Error: no such file"))
+	ErrEquals(t, "parsing cost estimate: no JSON object in output", err)
+
+	_, err = models.NewCostEstimate([]byte(`{"totalMonthlyCost": "lots"}`))
+	ErrEquals(t, "parsing cost estimate: invalid cost \"lots\"", err)
+}
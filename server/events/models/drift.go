@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+)
+
+// RepoDrift is the result of the last drift detection run for a repo.
+type RepoDrift struct {
+	// Repository is the full name of the repo, ex. runatlantis/atlantis.
+	Repository string
+	// VCSHost is the hostname of the repo's VCS, ex. github.com.
+	VCSHost string
+	// Branch is the branch that was planned.
+	Branch string
+	// Time is when the run finished.
+	Time time.Time
+	// Error is set if the projects couldn't be planned at all, ex. because
+	// the repo couldn't be cloned.
+	Error string `json:",omitempty"`
+	// Projects are the results for each project in the repo config file.
+	Projects []ProjectDrift
+}
+
+// ProjectDrift is the drift detection result for a single project.
+type ProjectDrift struct {
+	ProjectName string
+	RepoRelDir  string
+	Workspace   string
+	// Drifted is true if planning the project resulted in changes.
+	Drifted bool
+	// Summary is the one line summary of the plan.
+	Summary string `json:",omitempty"`
+	// Error is set if the project couldn't be planned.
+	Error string `json:",omitempty"`
+}
+
+// DriftDetectionPullNum is the pull request number that drift detection
+// plans as. It's negative so it can't clash with real pull requests, and
+// asynchronous API jobs use lower numbers.
+const DriftDetectionPullNum = -1
+
+// HasDrift returns true if any project has drifted.
+func (r RepoDrift) HasDrift() bool {
+	for _, p := range r.Projects {
+		if p.Drifted {
+			return true
+		}
+	}
+	return false
+}
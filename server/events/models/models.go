@@ -19,10 +19,12 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
 	paths "path"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -300,6 +302,7 @@ const (
 	BitbucketCloud
 	BitbucketServer
 	AzureDevops
+	Gitea
 )
 
 func (h VCSHostType) String() string {
@@ -314,6 +317,8 @@ func (h VCSHostType) String() string {
 		return "BitbucketServer"
 	case AzureDevops:
 		return "AzureDevops"
+	case Gitea:
+		return "Gitea"
 	}
 	return "<missing String() implementation>"
 }
@@ -330,6 +335,8 @@ func NewVCSHostType(t string) (VCSHostType, error) {
 		return BitbucketServer, nil
 	case "AzureDevops":
 		return AzureDevops, nil
+	case "Gitea":
+		return Gitea, nil
 	}
 
 	return -1, fmt.Errorf("%q is not a valid type", t)
@@ -365,14 +372,55 @@ type PlanSuccess struct {
 	// branch we're merging into has been updated since we cloned and merged
 	// it.
 	HasDiverged bool
+	// Analysis is the structured summary of the plan. It's nil if the plan
+	// couldn't be converted to JSON, ex. when using TFE remote operations or
+	// Terraform < 0.12, in which case we fall back to parsing
+	// TerraformOutput.
+	Analysis *PlanAnalysis
+	// PlannedAt is when the plan was created.
+	PlannedAt time.Time
+	// DependsOn are the names of the projects that must be applied before
+	// this plan can be applied.
+	DependsOn []string
+	// Targets are the resource addresses the plan is limited to. If empty,
+	// the plan isn't limited.
+	Targets []string
+	// Replaces are the resource addresses the plan forces to be replaced.
+	Replaces []string
 }
 
 type PolicySetResult struct {
 	PolicySetName  string
 	ConftestOutput string
-	Passed         bool
-	ReqApprovals   int
-	CurApprovals   int
+	// RuleResults are the messages of each rule that matched. They're only
+	// set when policies are evaluated in-process, conftest only gives us
+	// ConftestOutput.
+	RuleResults []PolicyRuleResult `json:",omitempty"`
+	// Revision identifies the version of the policies that were evaluated,
+	// ex. a commit SHA. It's only set for remote policy sets.
+	Revision     string `json:",omitempty"`
+	Passed       bool
+	ReqApprovals int
+	CurApprovals int
+}
+
+// PolicySeverity is how a policy rule's message affects the policy check.
+type PolicySeverity string
+
+const (
+	// DenyPolicySeverity messages fail the policy set.
+	DenyPolicySeverity PolicySeverity = "deny"
+	// WarnPolicySeverity messages are shown but don't fail the policy set.
+	WarnPolicySeverity PolicySeverity = "warn"
+)
+
+// PolicyRuleResult is a message from a single policy rule, ex. a deny rule
+// that matched a resource in the plan.
+type PolicyRuleResult struct {
+	// Rule is the rule's package and name, ex. main.deny.
+	Rule     string
+	Message  string
+	Severity PolicySeverity
 }
 
 // PolicySetApproval tracks the number of approvals a given policy set has.
@@ -380,11 +428,20 @@ type PolicySetStatus struct {
 	PolicySetName string
 	Passed        bool
 	Approvals     int
+	// Revision is the revision of the policies that were evaluated.
+	Revision string `json:",omitempty"`
 }
 
+const (
+	changesOutsideNote = "Note: Objects have changed outside of Terraform"
+	noChangesSummary   = "No changes. Your infrastructure matches the configuration."
+	// outputChangesSummary is the summary of plans that only change outputs.
+	outputChangesSummary = "Changes to Outputs."
+)
+
 // Summary regexes
 var (
-	reChangesOutside = regexp.MustCompile(`Note: Objects have changed outside of Terraform`)
+	reChangesOutside = regexp.MustCompile(changesOutsideNote)
 	rePlanChanges    = regexp.MustCompile(`Plan: (\d+) to add, (\d+) to change, (\d+) to destroy.`)
 	reNoChanges      = regexp.MustCompile(`No changes. (Infrastructure is up-to-date|Your infrastructure matches the configuration).`)
 )
@@ -392,14 +449,24 @@ var (
 // Summary extracts summaries of plan changes from TerraformOutput.
 func (p *PlanSuccess) Summary() string {
 	note := ""
-	if match := reChangesOutside.FindString(p.TerraformOutput); match != "" {
-		note = "\n**" + match + "**\n"
+	if p.Stats().ChangesOutside {
+		note = "\n**" + changesOutsideNote + "**\n"
 	}
 	return note + p.DiffSummary()
 }
 
 // DiffSummary extracts one line summary of plan changes from TerraformOutput.
 func (p *PlanSuccess) DiffSummary() string {
+	if p.Analysis != nil {
+		stats := p.Analysis.Stats()
+		if !stats.Changes {
+			if len(p.Analysis.OutputChanges) > 0 {
+				return outputChangesSummary
+			}
+			return noChangesSummary
+		}
+		return fmt.Sprintf("Plan: %d to add, %d to change, %d to destroy.", stats.Add, stats.Change, stats.Destroy)
+	}
 	if match := rePlanChanges.FindString(p.TerraformOutput); match != "" {
 		return match
 	}
@@ -408,6 +475,9 @@ func (p *PlanSuccess) DiffSummary() string {
 
 // NoChanges returns true if the plan has no changes.
 func (p *PlanSuccess) NoChanges() bool {
+	if p.Analysis != nil {
+		return p.Analysis.NoChanges()
+	}
 	return reNoChanges.MatchString(p.TerraformOutput)
 }
 
@@ -429,6 +499,9 @@ func (p PlanSuccess) DiffMarkdownFormattedTerraformOutput() string {
 
 // Stats returns plan change stats and contextual information.
 func (p PlanSuccess) Stats() PlanSuccessStats {
+	if p.Analysis != nil {
+		return p.Analysis.Stats()
+	}
 	return NewPlanSuccessStats(p.TerraformOutput)
 }
 
@@ -460,6 +533,15 @@ type ImportSuccess struct {
 	RePlanCmd string
 }
 
+// ApproveDestroySuccess is the result of a successful approve_destroy run.
+type ApproveDestroySuccess struct {
+	// ApprovedBy is the username of the owner that approved destroying the
+	// protected resources.
+	ApprovedBy string
+	// ApplyCmd is the command that users should run to apply this plan.
+	ApplyCmd string
+}
+
 // StateRmSuccess is the result of a successful state rm run.
 type StateRmSuccess struct {
 	// Output is the output from terraform state rm
@@ -529,6 +611,18 @@ type PullStatus struct {
 	Pull PullRequest
 }
 
+// FindProject returns the status of the project with name in repoRelDir and
+// workspace or nil if it isn't in the pull request.
+func (p PullStatus) FindProject(repoRelDir string, workspace string, name string) *ProjectStatus {
+	for i := range p.Projects {
+		proj := &p.Projects[i]
+		if proj.RepoRelDir == repoRelDir && proj.Workspace == workspace && proj.ProjectName == name {
+			return proj
+		}
+	}
+	return nil
+}
+
 // StatusCount returns the number of projects that have status.
 func (p PullStatus) StatusCount(status ProjectPlanStatus) int {
 	c := 0
@@ -549,6 +643,53 @@ type ProjectStatus struct {
 	PolicyStatus []PolicySetStatus
 	// Status is the status of where this project is at in the planning cycle.
 	Status ProjectPlanStatus
+	// DestroyApprovedBy is the username of the owner that approved destroying
+	// protected resources in the current plan. It's cleared on every plan.
+	DestroyApprovedBy string
+	// PlannedAt is when the current plan was created. It's zero if the
+	// project hasn't been planned successfully.
+	PlannedAt time.Time
+	// Targets are the resource addresses the current plan is limited to.
+	Targets []string `json:",omitempty"`
+	// Replaces are the resource addresses the current plan replaces.
+	Replaces []string `json:",omitempty"`
+	// PlanHistory are the most recent successful plans of the project,
+	// oldest first. At most MaxPlanHistory plans are kept.
+	PlanHistory []PlanRecord `json:",omitempty"`
+	// JobID identifies the job of the last command run for the project.
+	JobID string `json:",omitempty"`
+}
+
+// LastPlan returns the most recent successful plan of the project or nil if
+// it hasn't been planned.
+func (p ProjectStatus) LastPlan() *PlanRecord {
+	if len(p.PlanHistory) == 0 {
+		return nil
+	}
+	return &p.PlanHistory[len(p.PlanHistory)-1]
+}
+
+// MaxPlanHistory is how many plans are kept in a project's plan history.
+const MaxPlanHistory = 10
+
+// PlanRecord describes a successful plan of a project.
+type PlanRecord struct {
+	// JobID identifies the job that ran the plan.
+	JobID string `json:",omitempty"`
+	// User is the username of who ran the plan.
+	User      string `json:",omitempty"`
+	PlannedAt time.Time
+	Stats     PlanSuccessStats
+}
+
+// AppendPlans appends plans to history and drops the oldest plans so that at
+// most MaxPlanHistory are kept.
+func AppendPlans(history []PlanRecord, plans ...PlanRecord) []PlanRecord {
+	history = append(history, plans...)
+	if len(history) > MaxPlanHistory {
+		history = history[len(history)-MaxPlanHistory:]
+	}
+	return history
 }
 
 // ProjectPlanStatus is the status of where this project is at in the planning
@@ -601,6 +742,103 @@ func (p ProjectPlanStatus) String() string {
 	}
 }
 
+// APIJob is a plan or apply that was started through the API and is running
+// in the background.
+type APIJob struct {
+	// ID uniquely identifies the job.
+	ID string
+	// Command is the command that's being run, ex. plan or apply.
+	Command string
+	// Status is where the job is at.
+	Status APIJobStatus
+	// Repository is the full name of the repository the job runs in.
+	Repository string
+	// PullNum is the pull request the job runs for. Jobs that aren't for a
+	// pull request get their own negative number so that they don't share
+	// working directories and locks.
+	PullNum int
+	// ProjectJobIDs are the IDs of the jobs streaming the output of each
+	// project. Their logs are available at /jobs/{job-id}.
+	ProjectJobIDs []string
+	// Result is the JSON encoded command result once the job has finished.
+	Result json.RawMessage `json:",omitempty"`
+	// Error is set if the job couldn't be run.
+	Error string
+	// CreatedAt is when the job was started.
+	CreatedAt time.Time
+	// UpdatedAt is when the job's status last changed.
+	UpdatedAt time.Time
+}
+
+// APIJobStatus is the status of an APIJob.
+type APIJobStatus string
+
+const (
+	// PendingAPIJobStatus means that the job has been accepted but the
+	// projects haven't been built yet.
+	PendingAPIJobStatus APIJobStatus = "pending"
+	// RunningAPIJobStatus means that the projects are running.
+	RunningAPIJobStatus APIJobStatus = "running"
+	// SucceededAPIJobStatus means that all projects ran successfully.
+	SucceededAPIJobStatus APIJobStatus = "succeeded"
+	// FailedAPIJobStatus means that the job couldn't be run or that a project
+	// errored.
+	FailedAPIJobStatus APIJobStatus = "failed"
+)
+
+// IsFinished returns true if the job won't change anymore.
+func (s APIJobStatus) IsFinished() bool {
+	return s == SucceededAPIJobStatus || s == FailedAPIJobStatus
+}
+
+// SortAPIJobs sorts jobs by when they were created, oldest first.
+func SortAPIJobs(jobs []APIJob) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+}
+
+// QueuedEvent is a pull request or comment event that has been accepted but
+// not finished running yet. It's persisted so it isn't lost if Atlantis
+// stops before running it.
+type QueuedEvent struct {
+	// ID uniquely identifies the event.
+	ID string
+	// Kind is whether this is an autoplan or a comment event.
+	Kind QueuedEventKind
+	// BaseRepo is the repository that the pull request will be merged into.
+	BaseRepo Repo
+	// HeadRepo is the repository the pull request is coming from. It is nil
+	// for comment events from VCS hosts that don't include it.
+	HeadRepo *Repo
+	// Pull is the pull request. It is nil for comment events from VCS hosts
+	// that don't include it.
+	Pull *PullRequest
+	// PullNum is the pull request number.
+	PullNum int
+	// User is the user that triggered the event.
+	User User
+	// Command is the JSON encoded comment command for comment events.
+	Command json.RawMessage `json:",omitempty"`
+	// EnqueuedAt is when the event was accepted.
+	EnqueuedAt time.Time
+	// Owner is the ID of the Atlantis instance running the event.
+	Owner string `json:",omitempty"`
+	// LeaseExpiresAt is when the other Atlantis instances sharing the
+	// database can take the event over unless Owner renews the lease first.
+	LeaseExpiresAt time.Time
+}
+
+// QueuedEventKind is the kind of a QueuedEvent.
+type QueuedEventKind string
+
+const (
+	// AutoplanQueuedEvent is a pull request being opened or updated.
+	AutoplanQueuedEvent QueuedEventKind = "autoplan"
+	// CommentQueuedEvent is an Atlantis command commented on a pull request.
+	CommentQueuedEvent QueuedEventKind = "comment"
+)
+
 // WorkflowHookCommandContext defines the context for a pre and post worklfow_hooks that will
 // be executed before workflows.
 type WorkflowHookCommandContext struct {
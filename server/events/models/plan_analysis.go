@@ -0,0 +1,228 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// ResourceAction is the action Terraform will take on a resource.
+type ResourceAction string
+
+const (
+	CreateResourceAction  ResourceAction = "create"
+	UpdateResourceAction  ResourceAction = "update"
+	DeleteResourceAction  ResourceAction = "delete"
+	ReplaceResourceAction ResourceAction = "replace"
+	ReadResourceAction    ResourceAction = "read"
+	NoOpResourceAction    ResourceAction = "no-op"
+)
+
+// ResourceChange describes a single resource in a plan.
+type ResourceChange struct {
+	// Address is the full address of the resource, ex.
+	// module.vpc.aws_subnet.private[0].
+	Address string
+	// Action is what Terraform will do to the resource.
+	Action ResourceAction
+	// ActionReason is why Terraform chose the action, ex.
+	// replace_because_tainted. It's only set by Terraform for some actions.
+	ActionReason string
+	// ReplacePaths are the attribute paths that forced a replacement, ex.
+	// ["ami"].
+	ReplacePaths []string
+	// File is the file declaring the resource, or the module call containing
+	// it, relative to the project's directory. Line is the line of the
+	// declaration. They're empty if the declaration wasn't found.
+	File string `json:",omitempty"`
+	Line int    `json:",omitempty"`
+}
+
+// PlanAnalysis is a structured summary of a plan. It is built from the output
+// of `terraform show -json` so it doesn't depend on the wording of
+// Terraform's human readable output.
+type PlanAnalysis struct {
+	// ResourceChanges are the resources the plan will change. Resources
+	// with no changes are omitted.
+	ResourceChanges []ResourceChange
+	// ResourceDrift are the resources that have changed outside of
+	// Terraform since the last apply.
+	ResourceDrift []ResourceChange
+	// OutputChanges are the names of the root module outputs the plan will
+	// change, sorted.
+	OutputChanges []string
+}
+
+// tfPlanJSON is the subset of the `terraform show -json` plan representation
+// that we use.
+// See https://developer.hashicorp.com/terraform/internals/json-format.
+type tfPlanJSON struct {
+	ResourceChanges []tfResourceChangeJSON `json:"resource_changes"`
+	ResourceDrift   []tfResourceChangeJSON `json:"resource_drift"`
+	OutputChanges   map[string]struct {
+		Actions []string `json:"actions"`
+	} `json:"output_changes"`
+}
+
+type tfResourceChangeJSON struct {
+	Address      string `json:"address"`
+	ActionReason string `json:"action_reason"`
+	Change       struct {
+		Actions      []string        `json:"actions"`
+		ReplacePaths [][]interface{} `json:"replace_paths"`
+	} `json:"change"`
+}
+
+// NewPlanAnalysis parses the JSON output of `terraform show -json <planfile>`.
+func NewPlanAnalysis(showJSON []byte) (*PlanAnalysis, error) {
+	var plan tfPlanJSON
+	if err := json.Unmarshal(showJSON, &plan); err != nil {
+		return nil, errors.Wrap(err, "parsing terraform show output")
+	}
+
+	a := &PlanAnalysis{}
+	for _, rc := range plan.ResourceChanges {
+		change := rc.toResourceChange()
+		if change.Action == NoOpResourceAction {
+			continue
+		}
+		a.ResourceChanges = append(a.ResourceChanges, change)
+	}
+	for _, rc := range plan.ResourceDrift {
+		a.ResourceDrift = append(a.ResourceDrift, rc.toResourceChange())
+	}
+	for name, oc := range plan.OutputChanges {
+		if toResourceAction(oc.Actions) != NoOpResourceAction {
+			a.OutputChanges = append(a.OutputChanges, name)
+		}
+	}
+	sort.Strings(a.OutputChanges)
+	return a, nil
+}
+
+func (rc tfResourceChangeJSON) toResourceChange() ResourceChange {
+	change := ResourceChange{
+		Address:      rc.Address,
+		Action:       toResourceAction(rc.Change.Actions),
+		ActionReason: rc.ActionReason,
+	}
+	for _, path := range rc.Change.ReplacePaths {
+		change.ReplacePaths = append(change.ReplacePaths, replacePathString(path))
+	}
+	return change
+}
+
+// toResourceAction converts Terraform's list of actions into a single
+// action. Terraform represents a replacement as both a delete and a create in
+// either order.
+func toResourceAction(actions []string) ResourceAction {
+	if len(actions) == 2 {
+		return ReplaceResourceAction
+	}
+	if len(actions) == 1 {
+		return ResourceAction(actions[0])
+	}
+	return NoOpResourceAction
+}
+
+// replacePathString renders a replace path such as ["tags", "Name"] or
+// ["ingress", 0, "cidr_blocks"] as tags.Name or ingress[0].cidr_blocks.
+func replacePathString(path []interface{}) string {
+	var s string
+	for _, step := range path {
+		switch v := step.(type) {
+		case string:
+			if s != "" {
+				s += "."
+			}
+			s += v
+		case float64:
+			s += fmt.Sprintf("[%d]", int(v))
+		}
+	}
+	return s
+}
+
+// Stats returns the number of resources that will be added, changed and
+// destroyed. Like Terraform, a replacement counts as both an add and a
+// destroy.
+func (a *PlanAnalysis) Stats() PlanSuccessStats {
+	s := PlanSuccessStats{
+		ChangesOutside: len(a.ResourceDrift) > 0,
+	}
+	for _, rc := range a.ResourceChanges {
+		switch rc.Action {
+		case CreateResourceAction:
+			s.Add++
+		case UpdateResourceAction:
+			s.Change++
+		case DeleteResourceAction:
+			s.Destroy++
+		case ReplaceResourceAction:
+			s.Add++
+			s.Destroy++
+		}
+	}
+	s.Changes = s.Add+s.Change+s.Destroy > 0
+	return s
+}
+
+// NoChanges returns true if the plan changes neither resources nor outputs.
+func (a *PlanAnalysis) NoChanges() bool {
+	return !a.Stats().Changes && len(a.OutputChanges) == 0
+}
+
+// Replacements returns the resources that will be destroyed and re-created,
+// sorted by address.
+func (a *PlanAnalysis) Replacements() []ResourceChange {
+	return a.withAction(ReplaceResourceAction)
+}
+
+// Deletions returns the resources that will be destroyed, sorted by address.
+// Replacements aren't included.
+func (a *PlanAnalysis) Deletions() []ResourceChange {
+	return a.withAction(DeleteResourceAction)
+}
+
+func (a *PlanAnalysis) withAction(action ResourceAction) []ResourceChange {
+	var changes []ResourceChange
+	for _, rc := range a.ResourceChanges {
+		if rc.Action == action {
+			changes = append(changes, rc)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Address < changes[j].Address
+	})
+	return changes
+}
+
+// ReplaceReason returns a human readable reason for why the resource is being
+// replaced.
+func (rc ResourceChange) ReplaceReason() string {
+	switch rc.ActionReason {
+	case "replace_because_tainted":
+		return "tainted"
+	case "replace_by_request":
+		return "requested"
+	case "replace_by_triggers":
+		return "replace_triggered_by"
+	}
+	if len(rc.ReplacePaths) > 0 {
+		return fmt.Sprintf("forced by %s", joinBackticked(rc.ReplacePaths))
+	}
+	return ""
+}
+
+func joinBackticked(s []string) string {
+	var out string
+	for i, v := range s {
+		if i > 0 {
+			out += ", "
+		}
+		out += "`" + v + "`"
+	}
+	return out
+}
@@ -0,0 +1,167 @@
+package models_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestNewPlanAnalysis(t *testing.T) {
+	showJSON, err := os.ReadFile(filepath.Join("testdata", "plan.json"))
+	Ok(t, err)
+
+	analysis, err := models.NewPlanAnalysis(showJSON)
+	Ok(t, err)
+	Equals(t, &models.PlanAnalysis{
+		ResourceChanges: []models.ResourceChange{
+			{
+				Address:      "aws_instance.web",
+				Action:       models.ReplaceResourceAction,
+				ActionReason: "replace_because_cannot_update",
+				ReplacePaths: []string{"ami", "ebs_block_device[0].volume_size"},
+			},
+			{
+				Address:      "aws_instance.db",
+				Action:       models.ReplaceResourceAction,
+				ActionReason: "replace_because_tainted",
+			},
+			{
+				Address: "aws_s3_bucket.logs",
+				Action:  models.DeleteResourceAction,
+			},
+			{
+				Address: "module.vpc.aws_subnet.private[0]",
+				Action:  models.UpdateResourceAction,
+			},
+			{
+				Address: "aws_s3_bucket.new",
+				Action:  models.CreateResourceAction,
+			},
+		},
+		ResourceDrift: []models.ResourceChange{
+			{
+				Address: "aws_security_group.web",
+				Action:  models.UpdateResourceAction,
+			},
+		},
+	}, analysis)
+
+	Equals(t, models.PlanSuccessStats{
+		Add:            3,
+		Change:         1,
+		Destroy:        3,
+		Changes:        true,
+		ChangesOutside: true,
+	}, analysis.Stats())
+	Equals(t, []string{"aws_instance.db", "aws_instance.web"}, addresses(analysis.Replacements()))
+	Equals(t, []string{"aws_s3_bucket.logs"}, addresses(analysis.Deletions()))
+}
+
+// Test that output changes are parsed so output-only plans aren't mistaken
+// for plans with no changes.
+func TestNewPlanAnalysis_OutputChanges(t *testing.T) {
+	analysis, err := models.NewPlanAnalysis([]byte(`{
+  "output_changes": {
+    "url": {"actions": ["update"]},
+    "id": {"actions": ["create"]},
+    "unchanged": {"actions": ["no-op"]}
+  }
+}`))
+	Ok(t, err)
+	Equals(t, []string{"id", "url"}, analysis.OutputChanges)
+	Equals(t, false, analysis.Stats().Changes)
+	Equals(t, false, analysis.NoChanges())
+}
+
+func TestNewPlanAnalysis_InvalidJSON(t *testing.T) {
+	_, err := models.NewPlanAnalysis([]byte("not json"))
+	Assert(t, err != nil, "exp error")
+}
+
+func TestResourceChange_ReplaceReason(t *testing.T) {
+	cases := []struct {
+		change models.ResourceChange
+		exp    string
+	}{
+		{models.ResourceChange{ActionReason: "replace_because_tainted"}, "tainted"},
+		{models.ResourceChange{ActionReason: "replace_by_request"}, "requested"},
+		{models.ResourceChange{ActionReason: "replace_by_triggers"}, "replace_triggered_by"},
+		{models.ResourceChange{ActionReason: "replace_because_cannot_update", ReplacePaths: []string{"ami", "tags.Name"}}, "forced by `ami`, `tags.Name`"},
+		{models.ResourceChange{}, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.exp, func(t *testing.T) {
+			Equals(t, c.exp, c.change.ReplaceReason())
+		})
+	}
+}
+
+// If the plan has been analyzed, the summaries should come from the analysis
+// rather than from the Terraform output.
+func TestPlanSuccess_Analysis(t *testing.T) {
+	cases := []struct {
+		description  string
+		analysis     *models.PlanAnalysis
+		expSummary   string
+		expNoChanges bool
+	}{
+		{
+			"changes",
+			&models.PlanAnalysis{
+				ResourceChanges: []models.ResourceChange{
+					{Address: "a", Action: models.CreateResourceAction},
+					{Address: "b", Action: models.ReplaceResourceAction},
+					{Address: "c", Action: models.UpdateResourceAction},
+				},
+			},
+			"Plan: 2 to add, 1 to change, 1 to destroy.",
+			false,
+		},
+		{
+			"no changes",
+			&models.PlanAnalysis{},
+			"No changes. Your infrastructure matches the configuration.",
+			true,
+		},
+		{
+			"drift",
+			&models.PlanAnalysis{
+				ResourceDrift: []models.ResourceChange{
+					{Address: "a", Action: models.UpdateResourceAction},
+				},
+			},
+			"\n**Note: Objects have changed outside of Terraform**\nNo changes. Your infrastructure matches the configuration.",
+			true,
+		},
+		{
+			"output changes only",
+			&models.PlanAnalysis{
+				OutputChanges: []string{"url"},
+			},
+			"Changes to Outputs.",
+			false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			p := models.PlanSuccess{
+				TerraformOutput: "Plan: 100 to add, 100 to change, 100 to destroy.",
+				Analysis:        c.analysis,
+			}
+			Equals(t, c.expSummary, p.Summary())
+			Equals(t, c.expNoChanges, p.NoChanges())
+			Equals(t, c.analysis.Stats(), p.Stats())
+		})
+	}
+}
+
+func addresses(changes []models.ResourceChange) []string {
+	var addrs []string
+	for _, c := range changes {
+		addrs = append(addrs, c.Address)
+	}
+	return addrs
+}
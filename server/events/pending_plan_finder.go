@@ -1,12 +1,15 @@
 package events
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/planstore"
 	"github.com/runatlantis/atlantis/server/core/runtime"
 )
 
@@ -98,3 +101,66 @@ func (p *DefaultPendingPlanFinder) DeletePlans(pullDir string) error {
 	}
 	return nil
 }
+
+// StorePendingPlanFinder finds unapplied plans in a plan store rather than in
+// the working dirs, so it finds plans made by any Atlantis server.
+type StorePendingPlanFinder struct {
+	Store planstore.Store
+	// DataDir is Atlantis' data dir. The pull dirs passed to the finder must
+	// be in it.
+	DataDir string
+}
+
+// Find finds all pending plans of the pull request whose working directory
+// is pullDir.
+func (p *StorePendingPlanFinder) Find(pullDir string) ([]PendingPlan, error) {
+	prefix, err := p.pullPrefix(pullDir)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := p.Store.List(prefix)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing plans in plan store")
+	}
+	var plans []PendingPlan
+	for _, key := range keys {
+		workspace, file, ok := strings.Cut(strings.TrimPrefix(key, prefix), "/")
+		if !ok || path.Ext(file) != ".tfplan" {
+			continue
+		}
+		projectName, err := runtime.ProjectNameFromPlanfile(workspace, path.Base(file))
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, PendingPlan{
+			RepoDir:     filepath.Join(pullDir, workspace),
+			RepoRelDir:  filepath.FromSlash(path.Dir(file)),
+			Workspace:   workspace,
+			ProjectName: projectName,
+		})
+	}
+	return plans, nil
+}
+
+// DeletePlans deletes all plans of the pull request whose working directory
+// is pullDir from the plan store and from the working directory.
+func (p *StorePendingPlanFinder) DeletePlans(pullDir string) error {
+	prefix, err := p.pullPrefix(pullDir)
+	if err != nil {
+		return err
+	}
+	if err := planstore.DeleteAll(p.Store, prefix); err != nil {
+		return errors.Wrap(err, "deleting plans from plan store")
+	}
+	return (&DefaultPendingPlanFinder{}).DeletePlans(pullDir)
+}
+
+// pullPrefix returns the prefix of the keys of the plans of the pull request
+// whose working directory is pullDir.
+func (p *StorePendingPlanFinder) pullPrefix(pullDir string) (string, error) {
+	rel, err := filepath.Rel(filepath.Join(p.DataDir, workingDirPrefix), pullDir)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("%q is not a pull request's working directory", pullDir)
+	}
+	return filepath.ToSlash(rel) + "/", nil
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/runatlantis/atlantis/server/core/planstore"
 	"github.com/runatlantis/atlantis/server/events"
 	. "github.com/runatlantis/atlantis/testing"
 )
@@ -290,6 +291,77 @@ func TestPendingPlanFinder_DeletePlans(t *testing.T) {
 	Equals(t, 0, len(foundPlans))
 }
 
+// Test that plans are found in the plan store even if they aren't in the
+// working dir.
+func TestStorePendingPlanFinder_Find(t *testing.T) {
+	store := &planstore.FileStore{Dir: t.TempDir()}
+	for _, key := range []string{
+		"owner/repo/1/default/default.tfplan",
+		"owner/repo/1/default/dir1/proj-default.tfplan",
+		"owner/repo/1/staging/dir2/staging.tfplan",
+		"owner/repo/1/default/dir1/default.json",
+		"owner/repo/10/default/default.tfplan",
+	} {
+		Ok(t, store.Put(key, planstore.Plan{}, nil))
+	}
+	pf := &events.StorePendingPlanFinder{Store: store, DataDir: "/data"}
+
+	plans, err := pf.Find("/data/repos/owner/repo/1")
+	Ok(t, err)
+	Equals(t, []events.PendingPlan{
+		{
+			RepoDir:    "/data/repos/owner/repo/1/default",
+			RepoRelDir: ".",
+			Workspace:  "default",
+		},
+		{
+			RepoDir:     "/data/repos/owner/repo/1/default",
+			RepoRelDir:  "dir1",
+			Workspace:   "default",
+			ProjectName: "proj",
+		},
+		{
+			RepoDir:    "/data/repos/owner/repo/1/staging",
+			RepoRelDir: "dir2",
+			Workspace:  "staging",
+		},
+	}, plans)
+
+	_, err = pf.Find("/elsewhere/owner/repo/1")
+	ErrEquals(t, `"/elsewhere/owner/repo/1" is not a pull request's working directory`, err)
+}
+
+// Test that plans are deleted from the plan store and the working dir.
+func TestStorePendingPlanFinder_DeletePlans(t *testing.T) {
+	dataDir := DirStructure(t, map[string]interface{}{
+		"repos": map[string]interface{}{
+			"owner": map[string]interface{}{
+				"repo": map[string]interface{}{
+					"1": map[string]interface{}{
+						"default": map[string]interface{}{
+							"default.tfplan": nil,
+						},
+					},
+				},
+			},
+		},
+	})
+	pullDir := filepath.Join(dataDir, "repos", "owner", "repo", "1")
+	runCmd(t, filepath.Join(pullDir, "default"), "git", "init")
+	store := &planstore.FileStore{Dir: t.TempDir()}
+	Ok(t, store.Put("owner/repo/1/default/default.tfplan", planstore.Plan{}, nil))
+	Ok(t, store.Put("owner/repo/2/default/default.tfplan", planstore.Plan{}, nil))
+
+	pf := &events.StorePendingPlanFinder{Store: store, DataDir: dataDir}
+	Ok(t, pf.DeletePlans(pullDir))
+
+	_, err := os.Stat(filepath.Join(pullDir, "default", "default.tfplan"))
+	Assert(t, os.IsNotExist(err), "exp local plan to be deleted")
+	keys, err := store.List("owner/repo/")
+	Ok(t, err)
+	Equals(t, []string{"owner/repo/2/default/default.tfplan"}, keys)
+}
+
 func runCmd(t *testing.T, dir string, name string, args ...string) string {
 	t.Helper()
 	cpCmd := exec.Command(name, args...)
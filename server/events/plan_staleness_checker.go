@@ -0,0 +1,103 @@
+package events
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+//go:generate pegomock generate --package mocks -o mocks/mock_plan_staleness_checker.go PlanStalenessChecker
+
+// PlanStalenessChecker checks whether a project's plan can still be applied.
+type PlanStalenessChecker interface {
+	// StalePlanReason returns why the plan for ctx must not be applied or ""
+	// if it can be applied.
+	StalePlanReason(ctx command.ProjectContext) (string, error)
+}
+
+// DefaultPlanStalenessChecker refuses plans that are older than the
+// project's plan TTL and plans that the base branch has moved on from in a
+// way that affects the project. Plans only expire if the project has a plan
+// TTL, changes to the base branch are always checked.
+type DefaultPlanStalenessChecker struct {
+	WorkingDir WorkingDir
+}
+
+func (c *DefaultPlanStalenessChecker) StalePlanReason(ctx command.ProjectContext) (string, error) {
+	repoDir, err := c.WorkingDir.GetWorkingDir(ctx.Pull.BaseRepo, ctx.Pull, ctx.Workspace)
+	if err != nil {
+		return "", err
+	}
+
+	if ctx.PlanTTL > 0 {
+		reason, err := expiredPlanReason(ctx, repoDir)
+		if reason != "" || err != nil {
+			return reason, err
+		}
+	}
+	return baseChangedReason(ctx, c.WorkingDir, repoDir)
+}
+
+// expiredPlanReason returns why the plan is refused if it's older than the
+// project's plan TTL.
+func expiredPlanReason(ctx command.ProjectContext, repoDir string) (string, error) {
+	plannedAt := ctx.PlannedAt
+	if plannedAt.IsZero() {
+		// Plans from before the plan time was stored don't have it so fall
+		// back to when the planfile was written.
+		planFile := filepath.Join(repoDir, ctx.RepoRelDir, runtime.GetPlanFilename(ctx.Workspace, ctx.ProjectName))
+		info, err := os.Stat(planFile)
+		if err != nil {
+			return "", errors.Wrap(err, "getting plan time")
+		}
+		plannedAt = info.ModTime()
+	}
+	if age := time.Since(plannedAt); age > ctx.PlanTTL {
+		return fmt.Sprintf("Plan is %s old which is older than the plan TTL of %s. Re-plan with `%s` before applying.",
+			age.Round(time.Minute), ctx.PlanTTL, ctx.RePlanCmd), nil
+	}
+	return "", nil
+}
+
+// baseChangedReason returns why the plan is refused if the base branch has
+// changed the project's files since the plan was created.
+func baseChangedReason(ctx command.ProjectContext, workingDir WorkingDir, repoDir string) (string, error) {
+	changed, err := workingDir.GetBaseChangedFiles(ctx.Log, repoDir, ctx.Pull)
+	if err != nil {
+		return "", errors.Wrap(err, "getting files changed on base branch")
+	}
+	if len(changed) == 0 {
+		return "", nil
+	}
+	whenModified := ctx.WhenModified
+	if len(whenModified) == 0 {
+		whenModified = raw.DefaultAutoPlanWhenModified
+	}
+	pm, err := whenModifiedMatcher(ctx.RepoRelDir, whenModified)
+	if err != nil {
+		return "", errors.Wrapf(err, "matching modified files with patterns: %v", whenModified)
+	}
+	var affected []string
+	for _, file := range changed {
+		match, err := pm.Matches(file)
+		if err != nil {
+			ctx.Log.Debug("match err for file %q: %s", file, err)
+			continue
+		}
+		if match {
+			affected = append(affected, file)
+		}
+	}
+	if len(affected) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("Base branch %q has changed since the plan was created: %s. Re-plan with `%s` before applying.",
+		ctx.Pull.BaseBranch, strings.Join(affected, ", "), ctx.RePlanCmd), nil
+}
@@ -0,0 +1,124 @@
+package events_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/mocks"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestDefaultPlanStalenessChecker_StalePlanReason(t *testing.T) {
+	cases := []struct {
+		description  string
+		planTTL      time.Duration
+		plannedAt    time.Time
+		whenModified []string
+		baseChanged  []string
+		exp          string
+	}{
+		{
+			description: "no plan ttl",
+			plannedAt:   time.Now().Add(-48 * time.Hour),
+			baseChanged: []string{"README.md"},
+			exp:         "",
+		},
+		{
+			description: "no plan ttl and base changed project files",
+			plannedAt:   time.Now().Add(-48 * time.Hour),
+			baseChanged: []string{"project/main.tf"},
+			exp:         "Base branch \"main\" has changed since the plan was created: project/main.tf. Re-plan with `atlantis plan -d project` before applying.",
+		},
+		{
+			description: "fresh plan",
+			planTTL:     time.Hour,
+			plannedAt:   time.Now(),
+			exp:         "",
+		},
+		{
+			description: "expired plan",
+			planTTL:     time.Hour,
+			plannedAt:   time.Now().Add(-2 * time.Hour),
+			exp:         "Plan is 2h0m0s old which is older than the plan TTL of 1h0m0s. Re-plan with `atlantis plan -d project` before applying.",
+		},
+		{
+			description: "base changed project files",
+			planTTL:     time.Hour,
+			plannedAt:   time.Now(),
+			baseChanged: []string{"README.md", "project/main.tf", "project/variables.tf"},
+			exp:         "Base branch \"main\" has changed since the plan was created: project/main.tf, project/variables.tf. Re-plan with `atlantis plan -d project` before applying.",
+		},
+		{
+			description: "base changed other files",
+			planTTL:     time.Hour,
+			plannedAt:   time.Now(),
+			baseChanged: []string{"README.md", "other/main.tf"},
+			exp:         "",
+		},
+		{
+			description:  "base changed when_modified files",
+			planTTL:      time.Hour,
+			plannedAt:    time.Now(),
+			whenModified: []string{"../modules/**/*.tf", "*.tf"},
+			baseChanged:  []string{"modules/vpc/main.tf"},
+			exp:          "Base branch \"main\" has changed since the plan was created: modules/vpc/main.tf. Re-plan with `atlantis plan -d project` before applying.",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			workingDir := mocks.NewMockWorkingDir()
+			repoDir := t.TempDir()
+			When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(repoDir, nil)
+			When(workingDir.GetBaseChangedFiles(Any[logging.SimpleLogging](), Eq(repoDir), Any[models.PullRequest]())).ThenReturn(c.baseChanged, nil)
+
+			checker := &events.DefaultPlanStalenessChecker{WorkingDir: workingDir}
+			reason, err := checker.StalePlanReason(command.ProjectContext{
+				Log:          logging.NewNoopLogger(t),
+				Pull:         models.PullRequest{BaseBranch: "main"},
+				RepoRelDir:   "project",
+				Workspace:    "default",
+				RePlanCmd:    "atlantis plan -d project",
+				PlanTTL:      c.planTTL,
+				PlannedAt:    c.plannedAt,
+				WhenModified: c.whenModified,
+			})
+			Ok(t, err)
+			Equals(t, c.exp, reason)
+		})
+	}
+}
+
+// Test that plans without a plan time use the time the planfile was written.
+func TestDefaultPlanStalenessChecker_PlanfileTime(t *testing.T) {
+	RegisterMockTestingT(t)
+	workingDir := mocks.NewMockWorkingDir()
+	repoDir := t.TempDir()
+	When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(repoDir, nil)
+
+	planFile := filepath.Join(repoDir, "project", runtime.GetPlanFilename("default", ""))
+	Ok(t, os.MkdirAll(filepath.Dir(planFile), 0700))
+	Ok(t, os.WriteFile(planFile, nil, 0600))
+	planTime := time.Now().Add(-3 * time.Hour)
+	Ok(t, os.Chtimes(planFile, planTime, planTime))
+
+	checker := &events.DefaultPlanStalenessChecker{WorkingDir: workingDir}
+	reason, err := checker.StalePlanReason(command.ProjectContext{
+		Log:        logging.NewNoopLogger(t),
+		RepoRelDir: "project",
+		Workspace:  "default",
+		RePlanCmd:  "atlantis plan -d project",
+		PlanTTL:    time.Hour,
+	})
+	Ok(t, err)
+	Equals(t, "Plan is 3h0m0s old which is older than the plan TTL of 1h0m0s. Re-plan with `atlantis plan -d project` before applying.", reason)
+}
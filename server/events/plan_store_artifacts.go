@@ -0,0 +1,118 @@
+package events
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/planstore"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// lockFileName is the name of Terraform's dependency lock file.
+const lockFileName = ".terraform.lock.hcl"
+
+// planArtifacts returns the files in the project dir, besides the plan file,
+// that applying the plan needs: the JSON plan that destroy_approved checks,
+// the cost estimate that cost_under checks and the lock file the providers
+// were installed from. They're keyed by the name they're stored under in the
+// plan store and map to their name in the project dir.
+func planArtifacts(ctx command.ProjectContext) map[string]string {
+	planFile := runtime.GetPlanFilename(ctx.Workspace, ctx.ProjectName)
+	return map[string]string{
+		ctx.GetShowResultFileName():                            ctx.GetShowResultFileName(),
+		ctx.GetCostEstimateFileName():                          ctx.GetCostEstimateFileName(),
+		strings.TrimSuffix(planFile, ".tfplan") + lockFileName: lockFileName,
+	}
+}
+
+// planStoreKey returns the key of the file name of the project in the plan
+// store.
+func planStoreKey(ctx command.ProjectContext, name string) string {
+	return planstore.Key(ctx.BaseRepo.FullName, ctx.Pull.Num, ctx.Workspace, ctx.RepoRelDir, name)
+}
+
+// uploadPlanArtifacts uploads the project's plan artifacts to the plan store
+// so that another Atlantis server can apply the plan. Artifacts that this plan
+// didn't make are deleted so those of a previous plan aren't used with it.
+func (p *DefaultProjectCommandRunner) uploadPlanArtifacts(ctx command.ProjectContext, projAbsPath string) error {
+	for name, file := range planArtifacts(ctx) {
+		key := planStoreKey(ctx, name)
+		contents, err := os.ReadFile(filepath.Join(projAbsPath, file))
+		if os.IsNotExist(err) {
+			if err := p.PlanStore.Delete(key); err != nil {
+				return errors.Wrapf(err, "deleting %s from plan store", name)
+			}
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "reading %s", file)
+		}
+		if err := p.PlanStore.Put(key, planstore.NewPlan(ctx.Pull.HeadCommit, contents), contents); err != nil {
+			return errors.Wrapf(err, "uploading %s to plan store", name)
+		}
+	}
+	return nil
+}
+
+// downloadPlan downloads the project's plan from the plan store after checking
+// that it's intact and was made on the pull request's head commit. It returns
+// nil if the plan in the project dir is the same one. Otherwise, ex. because
+// the project was planned by another Atlantis server, the plan artifacts are
+// downloaded to the project dir and the plan is returned to be written there
+// once the project has been initialized again.
+func (p *DefaultProjectCommandRunner) downloadPlan(ctx command.ProjectContext, projAbsPath string) ([]byte, error) {
+	planFile := runtime.GetPlanFilename(ctx.Workspace, ctx.ProjectName)
+	plan, contents, err := p.PlanStore.Get(planStoreKey(ctx, planFile))
+	if err == planstore.ErrNotFound {
+		return nil, fmt.Errorf("no plan found at path %q and workspace %q–did you run plan?", ctx.RepoRelDir, ctx.Workspace)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "downloading plan from plan store")
+	}
+	if err := plan.Verify(ctx.Pull.HeadCommit, contents); err != nil {
+		return nil, err
+	}
+	if local, err := os.ReadFile(filepath.Join(projAbsPath, planFile)); err == nil && bytes.Equal(local, contents) {
+		return nil, nil
+	}
+
+	ctx.Log.Info("plan was made in another working dir, downloading it from the plan store")
+	for name, file := range planArtifacts(ctx) {
+		artifact, artifactContents, err := p.PlanStore.Get(planStoreKey(ctx, name))
+		if err == planstore.ErrNotFound {
+			if err := os.Remove(filepath.Join(projAbsPath, file)); err != nil && !os.IsNotExist(err) {
+				return nil, errors.Wrapf(err, "removing %s", file)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "downloading %s from plan store", name)
+		}
+		if err := artifact.Verify(ctx.Pull.HeadCommit, artifactContents); err != nil {
+			return nil, errors.Wrap(err, name)
+		}
+		if err := os.WriteFile(filepath.Join(projAbsPath, file), artifactContents, 0600); err != nil {
+			return nil, errors.Wrapf(err, "writing %s", file)
+		}
+	}
+	return contents, nil
+}
+
+// deletePlan deletes the project's plan and its artifacts from the plan store
+// once it's been applied.
+func (p *DefaultProjectCommandRunner) deletePlan(ctx command.ProjectContext) error {
+	if err := p.PlanStore.Delete(planStoreKey(ctx, runtime.GetPlanFilename(ctx.Workspace, ctx.ProjectName))); err != nil {
+		return err
+	}
+	for name := range planArtifacts(ctx) {
+		if err := p.PlanStore.Delete(planStoreKey(ctx, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package events_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/planstore"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/mocks"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+// Test that plans are downloaded from the plan store, and only applied if
+// they're intact and were made on the pull request's head commit. The project
+// is only initialized again if the plan was made in another working dir.
+func TestDefaultProjectCommandRunner_ApplyFromPlanStore(t *testing.T) {
+	key := "owner/repo/2/workspace/dir/workspace.tfplan"
+	cases := []struct {
+		description string
+		plan        *planstore.Plan
+		localPlan   string
+		expErr      string
+		expSteps    []string
+	}{
+		{
+			description: "no plan",
+			expErr:      `no plan found at path "dir" and workspace "workspace"–did you run plan?`,
+		},
+		{
+			description: "plan made on head commit",
+			plan:        &planstore.Plan{HeadCommit: "abc123", Checksum: planstore.NewPlan("", []byte("plan")).Checksum},
+			expSteps:    []string{"init", "apply"},
+		},
+		{
+			description: "plan made on head commit in this working dir",
+			plan:        &planstore.Plan{HeadCommit: "abc123", Checksum: planstore.NewPlan("", []byte("plan")).Checksum},
+			localPlan:   "plan",
+			expSteps:    []string{"apply"},
+		},
+		{
+			description: "outdated plan in this working dir",
+			plan:        &planstore.Plan{HeadCommit: "abc123", Checksum: planstore.NewPlan("", []byte("plan")).Checksum},
+			localPlan:   "old plan",
+			expSteps:    []string{"init", "apply"},
+		},
+		{
+			description: "plan made on previous commit",
+			plan:        &planstore.Plan{HeadCommit: "def456", Checksum: planstore.NewPlan("", []byte("plan")).Checksum},
+			expErr:      "plan was made on commit def456 but the pull request's head commit is abc123–run plan again",
+		},
+		{
+			description: "corrupted plan",
+			plan:        &planstore.Plan{HeadCommit: "abc123", Checksum: "bad"},
+			expErr:      "plan file is corrupted",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			store := &planstore.FileStore{Dir: t.TempDir()}
+			if c.plan != nil {
+				Ok(t, store.Put(key, *c.plan, []byte("plan")))
+			}
+			repoDir := t.TempDir()
+			projDir := filepath.Join(repoDir, "dir")
+			Ok(t, os.Mkdir(projDir, 0700))
+			if c.localPlan != "" {
+				Ok(t, os.WriteFile(filepath.Join(projDir, "workspace.tfplan"), []byte(c.localPlan), 0600))
+			}
+
+			workingDir := mocks.NewMockWorkingDir()
+			When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(repoDir, nil)
+			requirementHandler := mocks.NewMockCommandRequirementHandler()
+			When(requirementHandler.ValidateApplyProject(Any[string](), Any[command.ProjectContext]())).ThenReturn("", nil)
+			var steps []string
+			var appliedPlan string
+			initStep := mocks.NewMockStepRunner()
+			When(initStep.Run(Any[command.ProjectContext](), Any[[]string](), Any[string](), Any[map[string]string]())).Then(func(params []Param) ReturnValues {
+				steps = append(steps, "init")
+				return []ReturnValue{"", nil}
+			})
+			applyStep := mocks.NewMockStepRunner()
+			When(applyStep.Run(Any[command.ProjectContext](), Any[[]string](), Any[string](), Any[map[string]string]())).Then(func(params []Param) ReturnValues {
+				steps = append(steps, "apply")
+				contents, err := os.ReadFile(filepath.Join(params[2].(string), "workspace.tfplan"))
+				Ok(t, err)
+				appliedPlan = string(contents)
+				return []ReturnValue{"applied", nil}
+			})
+			runner := &events.DefaultProjectCommandRunner{
+				InitStepRunner:            initStep,
+				ApplyStepRunner:           applyStep,
+				WorkingDir:                workingDir,
+				Webhooks:                  mocks.NewMockWebhooksSender(),
+				WorkingDirLocker:          events.NewDefaultWorkingDirLocker(),
+				CommandRequirementHandler: requirementHandler,
+				PlanStore:                 store,
+			}
+
+			res := runner.Apply(command.ProjectContext{
+				Log:                  logging.NewNoopLogger(t),
+				Steps:                valid.DefaultApplyStage.Steps,
+				InitSteps:            []valid.Step{{StepName: "init"}},
+				Workspace:            "workspace",
+				RepoRelDir:           "dir",
+				BaseRepo:             models.Repo{FullName: "owner/repo"},
+				Pull:                 models.PullRequest{Num: 2, HeadCommit: "abc123"},
+				PlanStalenessChecked: true,
+			})
+			if c.expErr != "" {
+				ErrContains(t, c.expErr, res.Error)
+				Equals(t, 0, len(steps))
+				return
+			}
+			Ok(t, res.Error)
+			Equals(t, "applied", res.ApplySuccess)
+			Equals(t, c.expSteps, steps)
+			Equals(t, "plan", appliedPlan)
+			_, _, err := store.Get(key)
+			Equals(t, planstore.ErrNotFound, err)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package events
+
+import (
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/planstore"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// PlanStoreWorkingDir implements WorkingDir.
+// It acts as a proxy to an instance of WorkingDir that also deletes the plans
+// in the plan store when a working dir is deleted, so that discarded plans
+// can't be applied by any Atlantis server.
+type PlanStoreWorkingDir struct {
+	WorkingDir
+	PlanStore planstore.Store
+}
+
+// Delete deletes the pull request's working dir and all its plans.
+func (w *PlanStoreWorkingDir) Delete(r models.Repo, p models.PullRequest) error {
+	if err := w.WorkingDir.Delete(r, p); err != nil {
+		return err
+	}
+	return errors.Wrap(planstore.DeleteAll(w.PlanStore, planstore.PullPrefix(r.FullName, p.Num)), "deleting plans from plan store")
+}
+
+// DeleteForWorkspace deletes the working dir of the pull request's workspace
+// and the plans made in it.
+func (w *PlanStoreWorkingDir) DeleteForWorkspace(r models.Repo, p models.PullRequest, workspace string) error {
+	if err := w.WorkingDir.DeleteForWorkspace(r, p, workspace); err != nil {
+		return err
+	}
+	return errors.Wrap(planstore.DeleteAll(w.PlanStore, planstore.WorkspacePrefix(r.FullName, p.Num, workspace)), "deleting plans from plan store")
+}
@@ -0,0 +1,199 @@
+package events_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/config"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/planstore"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	terraform_mocks "github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	eventMocks "github.com/runatlantis/atlantis/server/events/mocks"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/metrics"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+// Test that deleting a working dir also deletes its plans from the plan store.
+func TestPlanStoreWorkingDir_Delete(t *testing.T) {
+	RegisterMockTestingT(t)
+	repo := models.Repo{FullName: "owner/repo"}
+	pull := models.PullRequest{Num: 1}
+	store := &planstore.FileStore{Dir: t.TempDir()}
+	for _, key := range []string{
+		"owner/repo/1/default/default.tfplan",
+		"owner/repo/1/staging/staging.tfplan",
+		"owner/repo/2/default/default.tfplan",
+	} {
+		Ok(t, store.Put(key, planstore.Plan{}, nil))
+	}
+	workingDir := eventMocks.NewMockWorkingDir()
+	wd := &events.PlanStoreWorkingDir{WorkingDir: workingDir, PlanStore: store}
+
+	Ok(t, wd.DeleteForWorkspace(repo, pull, "staging"))
+	workingDir.VerifyWasCalledOnce().DeleteForWorkspace(repo, pull, "staging")
+	keys, err := store.List("owner/repo/")
+	Ok(t, err)
+	Equals(t, []string{"owner/repo/1/default/default.tfplan", "owner/repo/2/default/default.tfplan"}, keys)
+
+	Ok(t, wd.Delete(repo, pull))
+	workingDir.VerifyWasCalledOnce().Delete(repo, pull)
+	keys, err = store.List("owner/repo/")
+	Ok(t, err)
+	Equals(t, []string{"owner/repo/2/default/default.tfplan"}, keys)
+}
+
+// Test that a plan made by one Atlantis server can be applied by another
+// server that hasn't cloned the pull request, with apply requirements that
+// check the plan's artifacts.
+func TestPlanStore_ApplyOnAnotherServer(t *testing.T) {
+	RegisterMockTestingT(t)
+	logger := logging.NewNoopLogger(t)
+	repoDir := initRepo(t)
+	runCmd(t, repoDir, "git", "checkout", "branch")
+	runCmd(t, repoDir, "touch", "main.tf")
+	runCmd(t, repoDir, "git", "add", "main.tf")
+	runCmd(t, repoDir, "git", "commit", "-m", "main.tf")
+	pull := models.PullRequest{
+		Num:        1,
+		HeadBranch: "branch",
+		HeadCommit: runCmd(t, repoDir, "git", "rev-parse", "HEAD"),
+		BaseRepo:   models.Repo{FullName: "owner/repo"},
+	}
+	store := &planstore.FileStore{Dir: t.TempDir()}
+	newWorkingDir := func() *events.FileWorkspace {
+		return &events.FileWorkspace{
+			DataDir:                     t.TempDir(),
+			TestingOverrideHeadCloneURL: fmt.Sprintf("file://%s", repoDir),
+			GpgNoSigningEnabled:         true,
+		}
+	}
+
+	// The first server plans the project in the staging workspace. Its plan
+	// deletes a protected resource and increases the cost by $10.
+	planningDir := newWorkingDir()
+	planStep := eventMocks.NewMockStepRunner()
+	When(planStep.Run(Any[command.ProjectContext](), Any[[]string](), Any[string](), Any[map[string]string]())).Then(func(params []Param) ReturnValues {
+		path := params[2].(string)
+		for file, contents := range map[string]string{
+			"staging.tfplan":      "plan",
+			"staging.json":        `{"resource_changes": [{"address": "aws_db_instance.main", "change": {"actions": ["delete"]}}]}`,
+			"staging-cost.json":   `{"currency": "USD", "diffTotalMonthlyCost": "10"}`,
+			".terraform.lock.hcl": "lock",
+		} {
+			Ok(t, os.WriteFile(filepath.Join(path, file), []byte(contents), 0600))
+		}
+		Ok(t, store.Put(planstore.Key("owner/repo", 1, "staging", ".", "staging.tfplan"), planstore.NewPlan(pull.HeadCommit, []byte("plan")), []byte("plan")))
+		return []ReturnValue{"planned", nil}
+	})
+	locker := eventMocks.NewMockProjectLocker()
+	When(locker.TryLock(Any[logging.SimpleLogging](), Any[models.PullRequest](), Any[models.User](), Any[string](), Any[models.Project](), AnyBool())).
+		ThenReturn(&events.TryLockResponse{LockAcquired: true}, nil)
+	planner := &events.DefaultProjectCommandRunner{
+		Locker:                    locker,
+		LockURLGenerator:          mockURLGenerator{},
+		PlanStepRunner:            planStep,
+		WorkingDir:                planningDir,
+		WorkingDirLocker:          events.NewDefaultWorkingDirLocker(),
+		CommandRequirementHandler: &events.DefaultCommandRequirementHandler{WorkingDir: planningDir},
+		PlanStore:                 store,
+	}
+	planRes := planner.Plan(command.ProjectContext{
+		CommandName: command.Plan,
+		Log:         logger,
+		Steps:       []valid.Step{{StepName: "plan"}},
+		BaseRepo:    pull.BaseRepo,
+		Pull:        pull,
+		RepoRelDir:  ".",
+		Workspace:   "staging",
+	})
+	Ok(t, planRes.Error)
+	Equals(t, "planned", planRes.PlanSuccess.TerraformOutput)
+
+	// The second server applies it.
+	applyingDir := newWorkingDir()
+	scope, _, _ := metrics.NewLoggingScope(logger, "atlantis")
+	terraformClient := terraform_mocks.NewMockClient()
+	builder := events.NewProjectCommandBuilder(
+		false,
+		&config.ParserValidator{},
+		&events.DefaultProjectFinder{},
+		nil,
+		applyingDir,
+		events.NewDefaultWorkingDirLocker(),
+		valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}),
+		&events.StorePendingPlanFinder{Store: store, DataDir: applyingDir.DataDir},
+		true,
+		&events.CommentParser{ExecutableName: "atlantis"},
+		false,
+		false,
+		"",
+		"**/*.tf,**/*.tfvars,**/*.tfvars.json,**/terragrunt.hcl,**/.terraform.lock.hcl",
+		false,
+		false,
+		scope,
+		logger,
+		terraformClient,
+	)
+	ctxs, err := builder.BuildApplyCommands(&command.Context{
+		Log:   logger,
+		Scope: scope,
+		Pull:  pull,
+	}, &events.CommentCommand{Name: command.Apply})
+	Ok(t, err)
+	Equals(t, 1, len(ctxs))
+	Equals(t, ".", ctxs[0].RepoRelDir)
+	Equals(t, "staging", ctxs[0].Workspace)
+	Equals(t, []valid.Step{{StepName: "init"}}, ctxs[0].InitSteps)
+	ctx := ctxs[0]
+	ctx.ApplyRequirements = []string{"destroy_approved", "cost_under:100"}
+	ctx.DestroyProtection = valid.DestroyProtection{Resources: []string{"aws_db_instance.*"}}
+
+	// The workspace is cloned again if it's missing when the plan is applied.
+	Ok(t, applyingDir.DeleteForWorkspace(pull.BaseRepo, pull, "staging"))
+	tfVersion, _ := version.NewVersion("1.5.0")
+	When(terraformClient.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[*version.Version](), Any[string]())).
+		ThenReturn("applied", nil)
+	runner := &events.DefaultProjectCommandRunner{
+		InitStepRunner:            &runtime.InitStepRunner{TerraformExecutor: terraformClient, DefaultTFVersion: tfVersion},
+		ApplyStepRunner:           &runtime.ApplyStepRunner{TerraformExecutor: terraformClient},
+		WorkingDir:                applyingDir,
+		Webhooks:                  eventMocks.NewMockWebhooksSender(),
+		WorkingDirLocker:          events.NewDefaultWorkingDirLocker(),
+		CommandRequirementHandler: &events.DefaultCommandRequirementHandler{WorkingDir: applyingDir},
+		PlanStalenessChecker:      &events.DefaultPlanStalenessChecker{WorkingDir: applyingDir},
+		PlanStore:                 store,
+	}
+
+	// The destroy hasn't been approved yet.
+	res := runner.Apply(ctx)
+	Ok(t, res.Error)
+	Equals(t, "This plan destroys protected resources: aws_db_instance.main. An owner must approve destroying them by commenting `atlantis approve_destroy -w staging` before running apply.", res.Failure)
+	terraformClient.VerifyWasCalled(Never()).RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[*version.Version](), Any[string]())
+
+	// Once it's approved, the project is initialized with the lock file the
+	// plan was made with and the plan is applied.
+	ctx.DestroyApprovedBy = "alice"
+	res = runner.Apply(ctx)
+	Ok(t, res.Error)
+	Equals(t, "", res.Failure)
+	Equals(t, "applied", res.ApplySuccess)
+	cloneDir, err := applyingDir.GetWorkingDir(pull.BaseRepo, pull, "staging")
+	Ok(t, err)
+	terraformClient.VerifyWasCalledOnce().RunCommandWithVersion(Any[command.ProjectContext](), Eq(cloneDir), Eq([]string{"init", "-input=false"}), Any[map[string]string](), Any[*version.Version](), Eq("staging"))
+	terraformClient.VerifyWasCalledOnce().RunCommandWithVersion(Any[command.ProjectContext](), Eq(cloneDir), Eq([]string{"apply", "-input=false", fmt.Sprintf("%q", filepath.Join(cloneDir, "staging.tfplan"))}), Any[map[string]string](), Any[*version.Version](), Eq("staging"))
+	lockFile, err := os.ReadFile(filepath.Join(cloneDir, ".terraform.lock.hcl"))
+	Ok(t, err)
+	Equals(t, "lock", string(lockFile))
+	keys, err := store.List("owner/repo/")
+	Ok(t, err)
+	Equals(t, 0, len(keys))
+}
@@ -48,7 +48,8 @@ func NewInstrumentedProjectCommandBuilder(
 	workingDir WorkingDir,
 	workingDirLocker WorkingDirLocker,
 	globalCfg valid.GlobalCfg,
-	pendingPlanFinder *DefaultPendingPlanFinder,
+	pendingPlanFinder PendingPlanFinder,
+	planStoreEnabled bool,
 	commentBuilder CommentBuilder,
 	skipCloneNoChanges bool,
 	EnableRegExpCmd bool,
@@ -76,6 +77,7 @@ func NewInstrumentedProjectCommandBuilder(
 			workingDirLocker,
 			globalCfg,
 			pendingPlanFinder,
+			planStoreEnabled,
 			commentBuilder,
 			skipCloneNoChanges,
 			EnableRegExpCmd,
@@ -100,7 +102,8 @@ func NewProjectCommandBuilder(
 	workingDir WorkingDir,
 	workingDirLocker WorkingDirLocker,
 	globalCfg valid.GlobalCfg,
-	pendingPlanFinder *DefaultPendingPlanFinder,
+	pendingPlanFinder PendingPlanFinder,
+	planStoreEnabled bool,
 	commentBuilder CommentBuilder,
 	skipCloneNoChanges bool,
 	EnableRegExpCmd bool,
@@ -120,6 +123,7 @@ func NewProjectCommandBuilder(
 		WorkingDirLocker:      workingDirLocker,
 		GlobalCfg:             globalCfg,
 		PendingPlanFinder:     pendingPlanFinder,
+		PlanStoreEnabled:      planStoreEnabled,
 		SkipCloneNoChanges:    skipCloneNoChanges,
 		EnableRegExpCmd:       EnableRegExpCmd,
 		AutoDetectModuleFiles: AutoDetectModuleFiles,
@@ -143,6 +147,9 @@ type ProjectPlanCommandBuilder interface {
 	// comment doesn't specify one project then there may be multiple commands
 	// to be run.
 	BuildPlanCommands(ctx *command.Context, comment *CommentCommand) ([]command.ProjectContext, error)
+	// BuildDriftCommands builds project plan commands for every project
+	// defined in the repo config file, regardless of what was modified.
+	BuildDriftCommands(ctx *command.Context) ([]command.ProjectContext, error)
 }
 
 type ProjectApplyCommandBuilder interface {
@@ -157,6 +164,11 @@ type ProjectApprovePoliciesCommandBuilder interface {
 	BuildApprovePoliciesCommands(ctx *command.Context, comment *CommentCommand) ([]command.ProjectContext, error)
 }
 
+type ProjectApproveDestroyCommandBuilder interface {
+	// BuildApproveDestroyCommands builds project ApproveDestroy commands for this ctx and comment.
+	BuildApproveDestroyCommands(ctx *command.Context, comment *CommentCommand) ([]command.ProjectContext, error)
+}
+
 type ProjectVersionCommandBuilder interface {
 	// BuildVersionCommands builds project Version commands for this ctx and comment. If
 	// comment doesn't specify one project then there may be multiple commands
@@ -185,6 +197,7 @@ type ProjectCommandBuilder interface {
 	ProjectPlanCommandBuilder
 	ProjectApplyCommandBuilder
 	ProjectApprovePoliciesCommandBuilder
+	ProjectApproveDestroyCommandBuilder
 	ProjectVersionCommandBuilder
 	ProjectImportCommandBuilder
 	ProjectStateCommandBuilder
@@ -194,13 +207,16 @@ type ProjectCommandBuilder interface {
 // This class combines the data from the comment and any atlantis.yaml file or
 // Atlantis server config and then generates a set of contexts.
 type DefaultProjectCommandBuilder struct {
-	ParserValidator              *config.ParserValidator
-	ProjectFinder                ProjectFinder
-	VCSClient                    vcs.Client
-	WorkingDir                   WorkingDir
-	WorkingDirLocker             WorkingDirLocker
-	GlobalCfg                    valid.GlobalCfg
-	PendingPlanFinder            *DefaultPendingPlanFinder
+	ParserValidator   *config.ParserValidator
+	ProjectFinder     ProjectFinder
+	VCSClient         vcs.Client
+	WorkingDir        WorkingDir
+	WorkingDirLocker  WorkingDirLocker
+	GlobalCfg         valid.GlobalCfg
+	PendingPlanFinder PendingPlanFinder
+	// PlanStoreEnabled is true if plans are kept in a plan store, so pull
+	// requests planned by another Atlantis server can be applied.
+	PlanStoreEnabled             bool
 	ProjectCommandContextBuilder ProjectCommandContextBuilder
 	SkipCloneNoChanges           bool
 	EnableRegExpCmd              bool
@@ -238,6 +254,68 @@ func (p *DefaultProjectCommandBuilder) BuildPlanCommands(ctx *command.Context, c
 	return pcc, err
 }
 
+// See ProjectCommandBuilder.BuildDriftCommands.
+func (p *DefaultProjectCommandBuilder) BuildDriftCommands(ctx *command.Context) ([]command.ProjectContext, error) {
+	// Need to lock the workspace we're about to clone to.
+	workspace := DefaultWorkspace
+
+	unlockFn, err := p.WorkingDirLocker.TryLock(ctx.Pull.BaseRepo.FullName, ctx.Pull.Num, workspace, DefaultRepoRelDir)
+	if err != nil {
+		ctx.Log.Warn("workspace was locked")
+		return nil, err
+	}
+	ctx.Log.Debug("got workspace lock")
+	defer unlockFn()
+
+	repoDir, _, err := p.WorkingDir.Clone(ctx.Log, ctx.HeadRepo, ctx.Pull, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	repoCfgFile := p.GlobalCfg.RepoConfigFile(ctx.Pull.BaseRepo.ID())
+	hasRepoCfg, err := p.ParserValidator.HasRepoCfg(repoDir, repoCfgFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "looking for %s file in %q", repoCfgFile, repoDir)
+	}
+	if !hasRepoCfg {
+		ctx.Log.Info("found no %s file", repoCfgFile)
+		return nil, nil
+	}
+	repoCfg, err := p.ParserValidator.ParseRepoCfg(repoDir, p.GlobalCfg, ctx.Pull.BaseRepo.ID(), ctx.Pull.BaseBranch)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", repoCfgFile)
+	}
+	ctx.Log.Info("successfully parsed %s file", repoCfgFile)
+
+	var projCtxs []command.ProjectContext
+	for _, proj := range repoCfg.Projects {
+		ctx.Log.Debug("determining config for project at dir: %q workspace: %q", proj.Dir, proj.Workspace)
+		mergedCfg := p.GlobalCfg.MergeProjectCfg(ctx.Log, ctx.Pull.BaseRepo.ID(), proj, repoCfg)
+
+		projCtxs = append(projCtxs,
+			p.ProjectCommandContextBuilder.BuildProjectContext(
+				ctx,
+				command.Plan,
+				"",
+				mergedCfg,
+				nil,
+				repoDir,
+				repoCfg.Automerge,
+				repoCfg.ParallelApply,
+				repoCfg.ParallelPlan,
+				false,
+				repoCfg.AbortOnExcecutionOrderFail,
+				p.TerraformExecutor,
+			)...)
+	}
+
+	sort.Slice(projCtxs, func(i, j int) bool {
+		return projCtxs[i].ExecutionOrderGroup < projCtxs[j].ExecutionOrderGroup
+	})
+
+	return projCtxs, nil
+}
+
 // See ProjectCommandBuilder.BuildApplyCommands.
 func (p *DefaultProjectCommandBuilder) BuildApplyCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
 	if !cmd.IsForSpecificProject() {
@@ -255,6 +333,14 @@ func (p *DefaultProjectCommandBuilder) BuildApprovePoliciesCommands(ctx *command
 	return pac, err
 }
 
+func (p *DefaultProjectCommandBuilder) BuildApproveDestroyCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
+	if !cmd.IsForSpecificProject() {
+		return p.buildAllProjectCommandsByPlan(ctx, cmd)
+	}
+	pac, err := p.buildProjectCommand(ctx, cmd)
+	return pac, err
+}
+
 func (p *DefaultProjectCommandBuilder) BuildVersionCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
 	if !cmd.IsForSpecificProject() {
 		return p.buildAllProjectCommandsByPlan(ctx, cmd)
@@ -610,25 +696,33 @@ func (p *DefaultProjectCommandBuilder) buildAllProjectCommandsByPlan(ctx *comman
 	}
 	defer unlockFn()
 
-	pullDir, err := p.WorkingDir.GetPullDir(ctx.Pull.BaseRepo, ctx.Pull)
+	// use the default repository workspace because it is the only one guaranteed to have an atlantis.yaml,
+	// other workspaces will not have the file if they are using pre_workflow_hooks to generate it dynamically
+	defaultRepoDir, err := p.getOrCloneWorkingDir(ctx, DefaultWorkspace)
 	if err != nil {
 		return nil, err
 	}
 
-	plans, err := p.PendingPlanFinder.Find(pullDir)
+	pullDir, err := p.WorkingDir.GetPullDir(ctx.Pull.BaseRepo, ctx.Pull)
 	if err != nil {
 		return nil, err
 	}
 
-	// use the default repository workspace because it is the only one guaranteed to have an atlantis.yaml,
-	// other workspaces will not have the file if they are using pre_workflow_hooks to generate it dynamically
-	defaultRepoDir, err := p.WorkingDir.GetWorkingDir(ctx.Pull.BaseRepo, ctx.Pull, DefaultWorkspace)
+	plans, err := p.PendingPlanFinder.Find(pullDir)
 	if err != nil {
 		return nil, err
 	}
 
 	var cmds []command.ProjectContext
 	for _, plan := range plans {
+		// Clone the plans' workspaces while holding the pull request's lock,
+		// rather than when applying them, since projects in the same
+		// workspace can be applied in parallel.
+		if p.PlanStoreEnabled {
+			if _, err := p.getOrCloneWorkingDir(ctx, plan.Workspace); err != nil {
+				return nil, err
+			}
+		}
 		commentCmds, err := p.buildProjectCommandCtx(ctx, commentCmd.CommandName(), commentCmd.SubName, plan.ProjectName, commentCmd.Flags, defaultRepoDir, plan.RepoRelDir, plan.Workspace, commentCmd.Verbose)
 		if err != nil {
 			return nil, errors.Wrapf(err, "building command for dir %q", plan.RepoRelDir)
@@ -643,6 +737,18 @@ func (p *DefaultProjectCommandBuilder) buildAllProjectCommandsByPlan(ctx *comman
 	return cmds, nil
 }
 
+// getOrCloneWorkingDir returns the pull request's working dir for workspace.
+// When plans are kept in a plan store the pull request may have been planned
+// by another Atlantis server, so it's cloned if this server hasn't cloned it.
+func (p *DefaultProjectCommandBuilder) getOrCloneWorkingDir(ctx *command.Context, workspace string) (string, error) {
+	repoDir, err := p.WorkingDir.GetWorkingDir(ctx.Pull.BaseRepo, ctx.Pull, workspace)
+	if p.PlanStoreEnabled && os.IsNotExist(errors.Cause(err)) {
+		ctx.Log.Info("workspace %q hasn't been cloned on this server, cloning it to find its plans", workspace)
+		repoDir, _, err = p.WorkingDir.Clone(ctx.Log, ctx.HeadRepo, ctx.Pull, workspace)
+	}
+	return repoDir, err
+}
+
 // buildProjectCommand builds an command for the single project
 // identified by cmd except plan.
 func (p *DefaultProjectCommandBuilder) buildProjectCommand(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
@@ -660,7 +766,7 @@ func (p *DefaultProjectCommandBuilder) buildProjectCommand(ctx *command.Context,
 
 	// use the default repository workspace because it is the only one guaranteed to have an atlantis.yaml,
 	// other workspaces will not have the file if they are using pre_workflow_hooks to generate it dynamically
-	repoDir, err := p.WorkingDir.GetWorkingDir(ctx.Pull.BaseRepo, ctx.Pull, DefaultWorkspace)
+	repoDir, err := p.getOrCloneWorkingDir(ctx, DefaultWorkspace)
 	if os.IsNotExist(errors.Cause(err)) {
 		return projCtx, errors.New("no working directory found–did you run plan?")
 	} else if err != nil {
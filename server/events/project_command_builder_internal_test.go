@@ -63,6 +63,7 @@ workflows:
 			expCtx: command.ProjectContext{
 				ApplyCmd:           "atlantis apply -d project1 -w myworkspace",
 				ApprovePoliciesCmd: "atlantis approve_policies -d project1 -w myworkspace",
+				ApproveDestroyCmd:  "atlantis approve_destroy -d project1 -w myworkspace",
 				BaseRepo:           baseRepo,
 				EscapedCommentArgs: []string{`\f\l\a\g`},
 				AutomergeEnabled:   false,
@@ -120,6 +121,7 @@ projects:
 			expCtx: command.ProjectContext{
 				ApplyCmd:           "atlantis apply -d project1 -w myworkspace",
 				ApprovePoliciesCmd: "atlantis approve_policies -d project1 -w myworkspace",
+				ApproveDestroyCmd:  "atlantis approve_destroy -d project1 -w myworkspace",
 				BaseRepo:           baseRepo,
 				EscapedCommentArgs: []string{`\f\l\a\g`},
 				AutomergeEnabled:   true,
@@ -144,6 +146,7 @@ projects:
 				Workspace:          "myworkspace",
 				PolicySets:         emptyPolicySets,
 				RepoLocking:        true,
+				WhenModified:       []string{"../modules/**/*.tf"},
 			},
 			expPlanSteps:  []string{"init", "plan"},
 			expApplySteps: []string{"apply"},
@@ -181,6 +184,7 @@ projects:
 			expCtx: command.ProjectContext{
 				ApplyCmd:           "atlantis apply -d project1 -w myworkspace",
 				ApprovePoliciesCmd: "atlantis approve_policies -d project1 -w myworkspace",
+				ApproveDestroyCmd:  "atlantis approve_destroy -d project1 -w myworkspace",
 				BaseRepo:           baseRepo,
 				EscapedCommentArgs: []string{`\f\l\a\g`},
 				AutomergeEnabled:   true,
@@ -205,6 +209,7 @@ projects:
 				Workspace:          "myworkspace",
 				PolicySets:         emptyPolicySets,
 				RepoLocking:        true,
+				WhenModified:       []string{"../modules/**/*.tf"},
 			},
 			expPlanSteps:  []string{"init", "plan"},
 			expApplySteps: []string{"apply"},
@@ -250,6 +255,7 @@ projects:
 			expCtx: command.ProjectContext{
 				ApplyCmd:           "atlantis apply -d project1 -w myworkspace",
 				ApprovePoliciesCmd: "atlantis approve_policies -d project1 -w myworkspace",
+				ApproveDestroyCmd:  "atlantis approve_destroy -d project1 -w myworkspace",
 				BaseRepo:           baseRepo,
 				EscapedCommentArgs: []string{`\f\l\a\g`},
 				AutomergeEnabled:   true,
@@ -274,6 +280,7 @@ projects:
 				Workspace:          "myworkspace",
 				PolicySets:         emptyPolicySets,
 				RepoLocking:        true,
+				WhenModified:       []string{"../modules/**/*.tf"},
 			},
 			expPlanSteps:  []string{"plan"},
 			expApplySteps: []string{},
@@ -406,6 +413,7 @@ workflows:
 			expCtx: command.ProjectContext{
 				ApplyCmd:           "atlantis apply -d project1 -w myworkspace",
 				ApprovePoliciesCmd: "atlantis approve_policies -d project1 -w myworkspace",
+				ApproveDestroyCmd:  "atlantis approve_destroy -d project1 -w myworkspace",
 				BaseRepo:           baseRepo,
 				EscapedCommentArgs: []string{`\f\l\a\g`},
 				AutomergeEnabled:   true,
@@ -430,6 +438,7 @@ workflows:
 				Workspace:          "myworkspace",
 				PolicySets:         emptyPolicySets,
 				RepoLocking:        true,
+				WhenModified:       []string{"../modules/**/*.tf"},
 			},
 			expPlanSteps:  []string{"plan"},
 			expApplySteps: []string{"apply"},
@@ -469,6 +478,7 @@ projects:
 			expCtx: command.ProjectContext{
 				ApplyCmd:           "atlantis apply -d project1 -w myworkspace",
 				ApprovePoliciesCmd: "atlantis approve_policies -d project1 -w myworkspace",
+				ApproveDestroyCmd:  "atlantis approve_destroy -d project1 -w myworkspace",
 				BaseRepo:           baseRepo,
 				EscapedCommentArgs: []string{`\f\l\a\g`},
 				AutomergeEnabled:   true,
@@ -493,6 +503,7 @@ projects:
 				Workspace:          "myworkspace",
 				PolicySets:         emptyPolicySets,
 				RepoLocking:        true,
+				WhenModified:       []string{"../modules/**/*.tf"},
 			},
 			expPlanSteps:  []string{"plan"},
 			expApplySteps: []string{"apply"},
@@ -535,6 +546,7 @@ workflows:
 			expCtx: command.ProjectContext{
 				ApplyCmd:           "atlantis apply -d project1 -w myworkspace",
 				ApprovePoliciesCmd: "atlantis approve_policies -d project1 -w myworkspace",
+				ApproveDestroyCmd:  "atlantis approve_destroy -d project1 -w myworkspace",
 				BaseRepo:           baseRepo,
 				EscapedCommentArgs: []string{`\f\l\a\g`},
 				AutomergeEnabled:   true,
@@ -559,6 +571,7 @@ workflows:
 				Workspace:          "myworkspace",
 				PolicySets:         emptyPolicySets,
 				RepoLocking:        true,
+				WhenModified:       []string{"../modules/**/*.tf"},
 			},
 			expPlanSteps:  []string{},
 			expApplySteps: []string{},
@@ -587,6 +600,7 @@ projects:
 			expCtx: command.ProjectContext{
 				ApplyCmd:           "atlantis apply -d project1 -w myworkspace",
 				ApprovePoliciesCmd: "atlantis approve_policies -d project1 -w myworkspace",
+				ApproveDestroyCmd:  "atlantis approve_destroy -d project1 -w myworkspace",
 				BaseRepo:           baseRepo,
 				EscapedCommentArgs: []string{`\f\l\a\g`},
 				AutomergeEnabled:   false,
@@ -610,6 +624,7 @@ projects:
 				Workspace:          "myworkspace",
 				PolicySets:         emptyPolicySets,
 				RepoLocking:        true,
+				WhenModified:       []string{"**/*.tf*", "**/terragrunt.hcl"},
 			},
 			expPlanSteps:  []string{"plan"},
 			expApplySteps: []string{"apply"},
@@ -662,6 +677,7 @@ projects:
 				NewDefaultWorkingDirLocker(),
 				globalCfg,
 				&DefaultPendingPlanFinder{},
+				false,
 				&CommentParser{ExecutableName: "atlantis"},
 				false,
 				false,
@@ -711,9 +727,22 @@ projects:
 						})
 					}
 
+					// Applies get the plan steps before the plan step to
+					// initialize the project.
+					var expInitSteps []valid.Step
+					for _, stepName := range c.expPlanSteps {
+						if cmd != command.Apply || stepName == "plan" {
+							break
+						}
+						expInitSteps = append(expInitSteps, valid.Step{
+							StepName: stepName,
+						})
+					}
+
 					c.expCtx.CommandName = cmd
 					// Init fields we couldn't in our cases map.
 					c.expCtx.Steps = expSteps
+					c.expCtx.InitSteps = expInitSteps
 					ctx.PolicySets = emptyPolicySets
 
 					// Job ID cannot be compared since its generated at random
@@ -800,6 +829,7 @@ projects:
 			expCtx: command.ProjectContext{
 				ApplyCmd:           "atlantis apply -p myproject_1",
 				ApprovePoliciesCmd: "atlantis approve_policies -p myproject_1",
+				ApproveDestroyCmd:  "atlantis approve_destroy -p myproject_1",
 				BaseRepo:           baseRepo,
 				EscapedCommentArgs: []string{`\f\l\a\g`},
 				AutomergeEnabled:   true,
@@ -824,6 +854,7 @@ projects:
 				Workspace:          "myworkspace",
 				PolicySets:         emptyPolicySets,
 				RepoLocking:        true,
+				WhenModified:       []string{"../modules/**/*.tf"},
 			},
 			expPlanSteps:  []string{"init", "plan"},
 			expApplySteps: []string{"apply"},
@@ -873,6 +904,7 @@ projects:
 				NewDefaultWorkingDirLocker(),
 				globalCfg,
 				&DefaultPendingPlanFinder{},
+				false,
 				&CommentParser{ExecutableName: "atlantis"},
 				false,
 				true,
@@ -923,9 +955,22 @@ projects:
 						})
 					}
 
+					// Applies get the plan steps before the plan step to
+					// initialize the project.
+					var expInitSteps []valid.Step
+					for _, stepName := range c.expPlanSteps {
+						if cmd != command.Apply || stepName == "plan" {
+							break
+						}
+						expInitSteps = append(expInitSteps, valid.Step{
+							StepName: stepName,
+						})
+					}
+
 					c.expCtx.CommandName = cmd
 					// Init fields we couldn't in our cases map.
 					c.expCtx.Steps = expSteps
+					c.expCtx.InitSteps = expInitSteps
 					ctx.PolicySets = emptyPolicySets
 
 					// Job ID cannot be compared since its generated at random
@@ -977,6 +1022,7 @@ repos:
 			expCtx: command.ProjectContext{
 				ApplyCmd:           "atlantis apply -d project1 -w myworkspace",
 				ApprovePoliciesCmd: "atlantis approve_policies -d project1 -w myworkspace",
+				ApproveDestroyCmd:  "atlantis approve_destroy -d project1 -w myworkspace",
 				BaseRepo:           baseRepo,
 				EscapedCommentArgs: []string{`\f\l\a\g`},
 				AutomergeEnabled:   false,
@@ -1039,6 +1085,7 @@ workflows:
 			expCtx: command.ProjectContext{
 				ApplyCmd:           "atlantis apply -d project1 -w myworkspace",
 				ApprovePoliciesCmd: "atlantis approve_policies -d project1 -w myworkspace",
+				ApproveDestroyCmd:  "atlantis approve_destroy -d project1 -w myworkspace",
 				BaseRepo:           baseRepo,
 				EscapedCommentArgs: []string{`\f\l\a\g`},
 				AutomergeEnabled:   true,
@@ -1063,6 +1110,7 @@ workflows:
 				Workspace:          "myworkspace",
 				PolicySets:         emptyPolicySets,
 				RepoLocking:        true,
+				WhenModified:       []string{"../modules/**/*.tf"},
 				PolicySetTarget:    "",
 			},
 			expPolicyCheckSteps: []string{"policy_check"},
@@ -1117,6 +1165,7 @@ workflows:
 				NewDefaultWorkingDirLocker(),
 				globalCfg,
 				&DefaultPendingPlanFinder{},
+				false,
 				&CommentParser{ExecutableName: "atlantis"},
 				false,
 				false,
@@ -1269,6 +1318,7 @@ projects:
 				NewDefaultWorkingDirLocker(),
 				globalCfg,
 				&DefaultPendingPlanFinder{},
+				false,
 				&CommentParser{ExecutableName: "atlantis"},
 				false,
 				false,
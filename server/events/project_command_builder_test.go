@@ -158,6 +158,7 @@ projects:
 				events.NewDefaultWorkingDirLocker(),
 				valid.NewGlobalCfgFromArgs(globalCfgArgs),
 				&events.DefaultPendingPlanFinder{},
+				false,
 				&events.CommentParser{ExecutableName: "atlantis"},
 				false,
 				false,
@@ -189,6 +190,119 @@ projects:
 	}
 }
 
+func TestDefaultProjectCommandBuilder_BuildDriftCommands(t *testing.T) {
+	type expCtxFields struct {
+		ProjectName string
+		RepoRelDir  string
+		Workspace   string
+	}
+	cases := []struct {
+		Description  string
+		AtlantisYAML string
+		exp          []expCtxFields
+	}{
+		{
+			Description: "no atlantis.yaml",
+			exp:         nil,
+		},
+		{
+			Description: "all projects are planned regardless of autoplan",
+			AtlantisYAML: `
+version: 3
+projects:
+- dir: mydir
+- dir: .
+  workspace: myworkspace
+  autoplan:
+    enabled: false
+- dir: .
+  name: myname
+  workspace: myworkspace2
+`,
+			exp: []expCtxFields{
+				{
+					ProjectName: "",
+					RepoRelDir:  "mydir",
+					Workspace:   "default",
+				},
+				{
+					ProjectName: "",
+					RepoRelDir:  ".",
+					Workspace:   "myworkspace",
+				},
+				{
+					ProjectName: "myname",
+					RepoRelDir:  ".",
+					Workspace:   "myworkspace2",
+				},
+			},
+		},
+	}
+
+	logger := logging.NewNoopLogger(t)
+	scope, _, _ := metrics.NewLoggingScope(logger, "atlantis")
+
+	terraformClient := terraform_mocks.NewMockClient()
+	When(terraformClient.ListAvailableVersions(Any[logging.SimpleLogging]())).ThenReturn([]string{}, nil)
+
+	for _, c := range cases {
+		t.Run(c.Description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpDir := DirStructure(t, map[string]interface{}{
+				"main.tf": nil,
+				"mydir": map[string]interface{}{
+					"main.tf": nil,
+				},
+			})
+
+			workingDir := mocks.NewMockWorkingDir()
+			When(workingDir.Clone(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(tmpDir, false, nil)
+			vcsClient := vcsmocks.NewMockClient()
+			if c.AtlantisYAML != "" {
+				err := os.WriteFile(filepath.Join(tmpDir, valid.DefaultAtlantisFile), []byte(c.AtlantisYAML), 0600)
+				Ok(t, err)
+			}
+
+			builder := events.NewProjectCommandBuilder(
+				false,
+				&config.ParserValidator{},
+				&events.DefaultProjectFinder{},
+				vcsClient,
+				workingDir,
+				events.NewDefaultWorkingDirLocker(),
+				valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}),
+				&events.DefaultPendingPlanFinder{},
+				false,
+				&events.CommentParser{ExecutableName: "atlantis"},
+				false,
+				false,
+				"",
+				"**/*.tf,**/*.tfvars,**/*.tfvars.json,**/terragrunt.hcl,**/.terraform.lock.hcl",
+				false,
+				false,
+				scope,
+				logger,
+				terraformClient,
+			)
+
+			ctxs, err := builder.BuildDriftCommands(&command.Context{
+				Log:   logger,
+				Scope: scope,
+			})
+			Ok(t, err)
+			Equals(t, len(c.exp), len(ctxs))
+			for i, actCtx := range ctxs {
+				expCtx := c.exp[i]
+				Equals(t, expCtx.ProjectName, actCtx.ProjectName)
+				Equals(t, expCtx.RepoRelDir, actCtx.RepoRelDir)
+				Equals(t, expCtx.Workspace, actCtx.Workspace)
+				Equals(t, command.Plan, actCtx.CommandName)
+			}
+			vcsClient.VerifyWasCalled(Never()).GetModifiedFiles(Any[models.Repo](), Any[models.PullRequest]())
+		})
+	}
+}
+
 // Test building a plan and apply command for one project.
 func TestDefaultProjectCommandBuilder_BuildSinglePlanApplyCommand(t *testing.T) {
 	cases := []struct {
@@ -450,6 +564,7 @@ projects:
 					events.NewDefaultWorkingDirLocker(),
 					valid.NewGlobalCfgFromArgs(globalCfgArgs),
 					&events.DefaultPendingPlanFinder{},
+					false,
 					&events.CommentParser{ExecutableName: "atlantis"},
 					false,
 					true,
@@ -632,6 +747,7 @@ projects:
 				events.NewDefaultWorkingDirLocker(),
 				valid.NewGlobalCfgFromArgs(globalCfgArgs),
 				&events.DefaultPendingPlanFinder{},
+				false,
 				&events.CommentParser{ExecutableName: "atlantis"},
 				false,
 				true,
@@ -818,6 +934,7 @@ projects:
 				events.NewDefaultWorkingDirLocker(),
 				valid.NewGlobalCfgFromArgs(globalCfgArgs),
 				&events.DefaultPendingPlanFinder{},
+				false,
 				&events.CommentParser{ExecutableName: "atlantis"},
 				false,
 				false,
@@ -917,6 +1034,7 @@ func TestDefaultProjectCommandBuilder_BuildMultiApply(t *testing.T) {
 		events.NewDefaultWorkingDirLocker(),
 		valid.NewGlobalCfgFromArgs(globalCfgArgs),
 		&events.DefaultPendingPlanFinder{},
+		false,
 		&events.CommentParser{ExecutableName: "atlantis"},
 		false,
 		false,
@@ -1007,6 +1125,7 @@ projects:
 		events.NewDefaultWorkingDirLocker(),
 		valid.NewGlobalCfgFromArgs(globalCfgArgs),
 		&events.DefaultPendingPlanFinder{},
+		false,
 		&events.CommentParser{ExecutableName: "atlantis"},
 		false,
 		false,
@@ -1092,6 +1211,7 @@ func TestDefaultProjectCommandBuilder_EscapeArgs(t *testing.T) {
 				events.NewDefaultWorkingDirLocker(),
 				valid.NewGlobalCfgFromArgs(globalCfgArgs),
 				&events.DefaultPendingPlanFinder{},
+				false,
 				&events.CommentParser{ExecutableName: "atlantis"},
 				false,
 				false,
@@ -1259,6 +1379,7 @@ projects:
 				events.NewDefaultWorkingDirLocker(),
 				valid.NewGlobalCfgFromArgs(globalCfgArgs),
 				&events.DefaultPendingPlanFinder{},
+				false,
 				&events.CommentParser{ExecutableName: "atlantis"},
 				false,
 				false,
@@ -1353,6 +1474,7 @@ parallel_plan: true`,
 			events.NewDefaultWorkingDirLocker(),
 			valid.NewGlobalCfgFromArgs(globalCfgArgs),
 			&events.DefaultPendingPlanFinder{},
+			false,
 			&events.CommentParser{ExecutableName: "atlantis"},
 			true,
 			false,
@@ -1417,6 +1539,7 @@ func TestDefaultProjectCommandBuilder_WithPolicyCheckEnabled_BuildAutoplanComman
 		events.NewDefaultWorkingDirLocker(),
 		globalCfg,
 		&events.DefaultPendingPlanFinder{},
+		false,
 		&events.CommentParser{ExecutableName: "atlantis"},
 		false,
 		false,
@@ -1504,6 +1627,7 @@ func TestDefaultProjectCommandBuilder_BuildVersionCommand(t *testing.T) {
 		events.NewDefaultWorkingDirLocker(),
 		valid.NewGlobalCfgFromArgs(globalCfgArgs),
 		&events.DefaultPendingPlanFinder{},
+		false,
 		&events.CommentParser{ExecutableName: "atlantis"},
 		false,
 		false,
@@ -2,6 +2,7 @@ package events
 
 import (
 	"path/filepath"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -98,11 +99,14 @@ func (cb *DefaultProjectCommandContextBuilder) BuildProjectContext(
 	ctx.Log.Debug("Building project command context for %s", cmdName)
 
 	var steps []valid.Step
+	var stageTimeout time.Duration
 	switch cmdName {
 	case command.Plan:
 		steps = prjCfg.Workflow.Plan.Steps
+		stageTimeout = prjCfg.Workflow.Plan.Timeout
 	case command.Apply:
 		steps = prjCfg.Workflow.Apply.Steps
+		stageTimeout = prjCfg.Workflow.Apply.Timeout
 	case command.Version:
 		// Setting statically since there will only be one step
 		steps = []valid.Step{{
@@ -110,10 +114,12 @@ func (cb *DefaultProjectCommandContextBuilder) BuildProjectContext(
 		}}
 	case command.Import:
 		steps = prjCfg.Workflow.Import.Steps
+		stageTimeout = prjCfg.Workflow.Import.Timeout
 	case command.State:
 		switch subName {
 		case "rm":
 			steps = prjCfg.Workflow.StateRm.Steps
+			stageTimeout = prjCfg.Workflow.StateRm.Timeout
 		default:
 			// comment_parser prevent invalid subcommand, so not need to handle this.
 			// if comes here, state_command_runner will respond on PR, so it's enough to do log only.
@@ -132,6 +138,7 @@ func (cb *DefaultProjectCommandContextBuilder) BuildProjectContext(
 		cmdName,
 		cb.CommentBuilder.BuildApplyComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name, prjCfg.AutoMergeDisabled),
 		cb.CommentBuilder.BuildApprovePoliciesComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name),
+		cb.CommentBuilder.BuildApproveDestroyComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name),
 		cb.CommentBuilder.BuildPlanComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name, commentFlags),
 		prjCfg,
 		steps,
@@ -145,6 +152,7 @@ func (cb *DefaultProjectCommandContextBuilder) BuildProjectContext(
 		ctx.Scope,
 		ctx.PullRequestStatus,
 	)
+	projectCmdContext.StageTimeout = stageTimeout
 
 	projectCmds = append(projectCmds, projectCmdContext)
 
@@ -193,11 +201,12 @@ func (cb *PolicyCheckProjectCommandContextBuilder) BuildProjectContext(
 		ctx.Log.Debug("Building project command context for %s", command.PolicyCheck)
 		steps := prjCfg.Workflow.PolicyCheck.Steps
 
-		projectCmds = append(projectCmds, newProjectCommandContext(
+		policyCheckCmdContext := newProjectCommandContext(
 			ctx,
 			command.PolicyCheck,
 			cb.CommentBuilder.BuildApplyComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name, prjCfg.AutoMergeDisabled),
 			cb.CommentBuilder.BuildApprovePoliciesComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name),
+			cb.CommentBuilder.BuildApproveDestroyComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name),
 			cb.CommentBuilder.BuildPlanComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name, commentFlags),
 			prjCfg,
 			steps,
@@ -210,7 +219,10 @@ func (cb *PolicyCheckProjectCommandContextBuilder) BuildProjectContext(
 			abortOnExcecutionOrderFail,
 			ctx.Scope,
 			ctx.PullRequestStatus,
-		))
+		)
+		policyCheckCmdContext.StageTimeout = prjCfg.Workflow.PolicyCheck.Timeout
+
+		projectCmds = append(projectCmds, policyCheckCmdContext)
 	}
 
 	return
@@ -222,6 +234,7 @@ func newProjectCommandContext(ctx *command.Context,
 	cmd command.Name,
 	applyCmd string,
 	approvePoliciesCmd string,
+	approveDestroyCmd string,
 	planCmd string,
 	projCfg valid.MergedProjectCfg,
 	steps []valid.Step,
@@ -238,20 +251,49 @@ func newProjectCommandContext(ctx *command.Context,
 
 	var projectPlanStatus models.ProjectPlanStatus
 	var projectPolicyStatus []models.PolicySetStatus
+	var destroyApprovedBy string
+	var plannedAt time.Time
+	var unappliedDependencies []string
+	targets, replaces := ctx.Targets, ctx.Replaces
+	var applyInitSteps []valid.Step
+	if cmd == command.Apply {
+		applyInitSteps = initSteps(projCfg.Workflow.Plan.Steps)
+	}
 
 	if ctx.PullStatus != nil {
+		// Dependencies that weren't planned in this pull request don't have
+		// anything to apply so they don't hold up this project.
+		for _, dep := range projCfg.DependsOn {
+			for _, project := range ctx.PullStatus.Projects {
+				if project.ProjectName == dep && project.Status != models.AppliedPlanStatus {
+					unappliedDependencies = append(unappliedDependencies, dep)
+					break
+				}
+			}
+		}
+
 		for _, project := range ctx.PullStatus.Projects {
 
 			// if name is not used, let's match the directory
 			if projCfg.Name == "" && project.RepoRelDir == projCfg.RepoRelDir {
 				projectPlanStatus = project.Status
 				projectPolicyStatus = project.PolicyStatus
+				destroyApprovedBy = project.DestroyApprovedBy
+				plannedAt = project.PlannedAt
+				if cmd != command.Plan {
+					targets, replaces = project.Targets, project.Replaces
+				}
 				break
 			}
 
 			if projCfg.Name != "" && project.ProjectName == projCfg.Name {
 				projectPlanStatus = project.Status
 				projectPolicyStatus = project.PolicyStatus
+				destroyApprovedBy = project.DestroyApprovedBy
+				plannedAt = project.PlannedAt
+				if cmd != command.Plan {
+					targets, replaces = project.Targets, project.Replaces
+				}
 				break
 			}
 		}
@@ -261,6 +303,7 @@ func newProjectCommandContext(ctx *command.Context,
 		CommandName:                cmd,
 		ApplyCmd:                   applyCmd,
 		ApprovePoliciesCmd:         approvePoliciesCmd,
+		ApproveDestroyCmd:          approveDestroyCmd,
 		BaseRepo:                   ctx.Pull.BaseRepo,
 		EscapedCommentArgs:         escapedCommentArgs,
 		AutomergeEnabled:           automergeEnabled,
@@ -271,6 +314,7 @@ func newProjectCommandContext(ctx *command.Context,
 		ParallelPolicyCheckEnabled: parallelPlanEnabled,
 		AutoplanEnabled:            projCfg.AutoplanEnabled,
 		Steps:                      steps,
+		InitSteps:                  applyInitSteps,
 		HeadRepo:                   ctx.HeadRepo,
 		Log:                        ctx.Log,
 		Scope:                      scope,
@@ -289,13 +333,37 @@ func newProjectCommandContext(ctx *command.Context,
 		Verbose:                    verbose,
 		Workspace:                  projCfg.Workspace,
 		PolicySets:                 policySets,
+		DestroyProtection:          projCfg.DestroyProtection,
+		DestroyApprovedBy:          destroyApprovedBy,
+		ApprovalPolicy:             projCfg.ApprovalPolicy,
 		PolicySetTarget:            ctx.PolicySet,
 		ClearPolicyApproval:        ctx.ClearPolicyApproval,
+		Teams:                      ctx.Teams,
 		PullReqStatus:              pullStatus,
 		JobID:                      uuid.New().String(),
 		ExecutionOrderGroup:        projCfg.ExecutionOrderGroup,
 		AbortOnExcecutionOrderFail: abortOnExcecutionOrderFail,
+		PlanTTL:                    projCfg.PlanTTL,
+		PlannedAt:                  plannedAt,
+		WhenModified:               projCfg.WhenModified,
+		DependsOn:                  projCfg.DependsOn,
+		UnappliedDependencies:      unappliedDependencies,
+		Targets:                    targets,
+		Replaces:                   replaces,
+	}
+}
+
+// initSteps returns the steps of the plan workflow that run before its plan
+// step, which initialize the project. It returns nil if there's no plan step.
+func initSteps(planSteps []valid.Step) []valid.Step {
+	var steps []valid.Step
+	for _, step := range planSteps {
+		if step.StepName == "plan" {
+			return steps
+		}
+		steps = append(steps, step)
 	}
+	return nil
 }
 
 func escapeArgs(args []string) []string {
@@ -2,6 +2,7 @@ package events_test
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/petergtz/pegomock/v4"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -126,4 +127,67 @@ func TestProjectCommandContextBuilder_PullStatus(t *testing.T) {
 
 		assert.True(t, result[0].AbortOnExcecutionOrderFail)
 	})
+	t.Run("with dependencies", func(t *testing.T) {
+		projCfg.Name = "production"
+		projCfg.DependsOn = []string{"dev", "staging", "qa", "unchanged"}
+		When(mockCommentBuilder.BuildPlanComment(projRepoRelDir, projWorkspace, "production", []string{})).ThenReturn(expectedPlanCmt)
+		When(mockCommentBuilder.BuildApplyComment(projRepoRelDir, projWorkspace, "production", false)).ThenReturn(expectedApplyCmt)
+		pullStatus.Projects = []models.ProjectStatus{
+			{
+				Status:      models.AppliedPlanStatus,
+				ProjectName: "dev",
+			},
+			{
+				Status:      models.PlannedPlanStatus,
+				ProjectName: "staging",
+			},
+			{
+				Status:      models.ErroredApplyStatus,
+				ProjectName: "qa",
+			},
+		}
+
+		result := subject.BuildProjectContext(commandCtx, command.Apply, "", projCfg, []string{}, "some/dir", false, false, false, false, false, terraformClient)
+
+		assert.Equal(t, []string{"dev", "staging", "qa", "unchanged"}, result[0].DependsOn)
+		assert.Equal(t, []string{"staging", "qa"}, result[0].UnappliedDependencies)
+	})
+	t.Run("with targets", func(t *testing.T) {
+		projCfg.Name = "production"
+		projCfg.DependsOn = nil
+		When(mockCommentBuilder.BuildPlanComment(projRepoRelDir, projWorkspace, "production", []string{})).ThenReturn(expectedPlanCmt)
+		When(mockCommentBuilder.BuildApplyComment(projRepoRelDir, projWorkspace, "production", false)).ThenReturn(expectedApplyCmt)
+		pullStatus.Projects = []models.ProjectStatus{
+			{
+				Status:      models.PlannedPlanStatus,
+				ProjectName: "production",
+				Targets:     []string{"aws_instance.web"},
+				Replaces:    []string{"aws_instance.db"},
+			},
+		}
+		planCtx := *commandCtx
+		planCtx.Targets = []string{"module.vpc"}
+
+		// Plans use the targets from the comment.
+		result := subject.BuildProjectContext(&planCtx, command.Plan, "", projCfg, []string{}, "some/dir", false, false, false, false, false, terraformClient)
+		assert.Equal(t, []string{"module.vpc"}, result[0].Targets)
+		assert.Nil(t, result[0].Replaces)
+
+		// Applies use the targets from the current plan.
+		result = subject.BuildProjectContext(commandCtx, command.Apply, "", projCfg, []string{}, "some/dir", false, false, false, false, false, terraformClient)
+		assert.Equal(t, []string{"aws_instance.web"}, result[0].Targets)
+		assert.Equal(t, []string{"aws_instance.db"}, result[0].Replaces)
+		assert.True(t, result[0].IsTargeted())
+	})
+	t.Run("with stage timeout", func(t *testing.T) {
+		timeoutCfg := projCfg
+		timeoutCfg.Workflow.Plan = valid.Stage{Steps: valid.DefaultPlanStage.Steps, Timeout: time.Hour}
+		timeoutCfg.Workflow.Apply = valid.Stage{Steps: valid.DefaultApplyStage.Steps, Timeout: 30 * time.Minute}
+
+		result := subject.BuildProjectContext(commandCtx, command.Plan, "", timeoutCfg, []string{}, "some/dir", false, false, false, false, false, terraformClient)
+		assert.Equal(t, time.Hour, result[0].StageTimeout)
+
+		result = subject.BuildProjectContext(commandCtx, command.Apply, "", timeoutCfg, []string{}, "some/dir", false, false, false, false, false, terraformClient)
+		assert.Equal(t, 30*time.Minute, result[0].StageTimeout)
+	})
 }
@@ -18,17 +18,21 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"encoding/json"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/planstore"
 	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/core/terraform"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/logging"
 )
 
@@ -88,6 +92,10 @@ type MultiEnvStepRunner interface {
 type WebhooksSender interface {
 	// Send sends the webhook.
 	Send(log logging.SimpleLogging, res webhooks.ApplyResult) error
+	// SendDrift sends the drift webhook.
+	SendDrift(log logging.SimpleLogging, res webhooks.DriftResult) error
+	// SendEvent sends the event webhook.
+	SendEvent(log logging.SimpleLogging, res webhooks.EventResult) error
 }
 
 //go:generate pegomock generate --package mocks -o mocks/mock_project_command_runner.go ProjectCommandRunner
@@ -112,6 +120,12 @@ type ProjectApprovePoliciesCommandRunner interface {
 	ApprovePolicies(ctx command.ProjectContext) command.ProjectResult
 }
 
+type ProjectApproveDestroyCommandRunner interface {
+	// ApproveDestroy approves destroying the protected resources in the
+	// project's plan.
+	ApproveDestroy(ctx command.ProjectContext) command.ProjectResult
+}
+
 type ProjectVersionCommandRunner interface {
 	// Version runs terraform version for the project described by ctx.
 	Version(ctx command.ProjectContext) command.ProjectResult
@@ -134,6 +148,7 @@ type ProjectCommandRunner interface {
 	ProjectApplyCommandRunner
 	ProjectPolicyCheckCommandRunner
 	ProjectApprovePoliciesCommandRunner
+	ProjectApproveDestroyCommandRunner
 	ProjectVersionCommandRunner
 	ProjectImportCommandRunner
 	ProjectStateCommandRunner
@@ -199,6 +214,60 @@ func (p *ProjectOutputWrapper) updateProjectPRStatus(commandName command.Name, c
 	return result
 }
 
+// ProjectWebhooksWrapper is a decorator that sends an event webhook after
+// each project is planned, policy checked or applied.
+type ProjectWebhooksWrapper struct {
+	ProjectCommandRunner
+	Webhooks        WebhooksSender
+	JobURLGenerator jobs.ProjectJobURLGenerator
+}
+
+func (p *ProjectWebhooksWrapper) Plan(ctx command.ProjectContext) command.ProjectResult {
+	result := p.ProjectCommandRunner.Plan(ctx)
+	p.sendEvent(webhooks.PlanEvent, ctx, result)
+	return result
+}
+
+func (p *ProjectWebhooksWrapper) PolicyCheck(ctx command.ProjectContext) command.ProjectResult {
+	result := p.ProjectCommandRunner.PolicyCheck(ctx)
+	p.sendEvent(webhooks.PolicyCheckEvent, ctx, result)
+	return result
+}
+
+func (p *ProjectWebhooksWrapper) Apply(ctx command.ProjectContext) command.ProjectResult {
+	result := p.ProjectCommandRunner.Apply(ctx)
+	p.sendEvent(webhooks.ApplyEvent, ctx, result)
+	return result
+}
+
+func (p *ProjectWebhooksWrapper) sendEvent(event string, ctx command.ProjectContext, result command.ProjectResult) {
+	eventResult := webhooks.EventResult{
+		ApplyResult: webhooks.ApplyResult{
+			Workspace: ctx.Workspace,
+			Repo:      ctx.BaseRepo,
+			Pull:      ctx.Pull,
+			User:      ctx.User,
+			Success:   result.Error == nil && result.Failure == "",
+			Directory: ctx.RepoRelDir,
+		},
+		Event:       event,
+		ProjectName: ctx.ProjectName,
+		Time:        time.Now(),
+	}
+	if result.PlanSuccess != nil {
+		stats := result.PlanSuccess.Stats()
+		eventResult.PlanStats = &stats
+	}
+	if url, err := p.JobURLGenerator.GenerateProjectJobURL(ctx); err == nil {
+		eventResult.JobURL = url
+	} else {
+		ctx.Log.Debug("unable to generate job url for webhook: %s", err)
+	}
+	if err := p.Webhooks.SendEvent(ctx.Log, eventResult); err != nil {
+		ctx.Log.Warn("unable to send %s webhook: %s", event, err)
+	}
+}
+
 // DefaultProjectCommandRunner implements ProjectCommandRunner.
 type DefaultProjectCommandRunner struct {
 	VcsClient                 vcs.Client
@@ -209,6 +278,7 @@ type DefaultProjectCommandRunner struct {
 	ShowStepRunner            StepRunner
 	ApplyStepRunner           StepRunner
 	PolicyCheckStepRunner     StepRunner
+	CostEstimateStepRunner    StepRunner
 	VersionStepRunner         StepRunner
 	ImportStepRunner          StepRunner
 	StateRmStepRunner         StepRunner
@@ -220,19 +290,37 @@ type DefaultProjectCommandRunner struct {
 	Webhooks                  WebhooksSender
 	WorkingDirLocker          WorkingDirLocker
 	CommandRequirementHandler CommandRequirementHandler
+	PlanStalenessChecker      PlanStalenessChecker
+	// RestrictTargetedApplies is true if plans made with --target or
+	// --replace can only be applied by users in TargetedApplyAllowlist.
+	RestrictTargetedApplies bool
+	TargetedApplyAllowlist  []string
+	// JobCanceller, if set, lets running jobs be cancelled with the cancel
+	// command or from the job page.
+	JobCanceller *jobs.JobCanceller
+	// RunScheduler, if set, limits how many project commands run their steps
+	// at once.
+	RunScheduler *terraform.RunScheduler
+	// PlanStore, if set, is where plans and the files applying them needs
+	// are kept, so a project planned by another Atlantis server can be
+	// applied.
+	PlanStore planstore.Store
 }
 
 // Plan runs terraform plan for the project described by ctx.
 func (p *DefaultProjectCommandRunner) Plan(ctx command.ProjectContext) command.ProjectResult {
-	planSuccess, failure, err := p.doPlan(ctx)
+	planSuccess, costEstimate, failure, err := p.doPlan(ctx)
 	return command.ProjectResult{
-		Command:     command.Plan,
-		PlanSuccess: planSuccess,
-		Error:       err,
-		Failure:     failure,
-		RepoRelDir:  ctx.RepoRelDir,
-		Workspace:   ctx.Workspace,
-		ProjectName: ctx.ProjectName,
+		Command:      command.Plan,
+		PlanSuccess:  planSuccess,
+		CostEstimate: costEstimate,
+		Error:        err,
+		Failure:      failure,
+		RepoRelDir:   ctx.RepoRelDir,
+		Workspace:    ctx.Workspace,
+		ProjectName:  ctx.ProjectName,
+		User:         ctx.User.Username,
+		JobID:        ctx.JobID,
 	}
 }
 
@@ -247,6 +335,8 @@ func (p *DefaultProjectCommandRunner) PolicyCheck(ctx command.ProjectContext) co
 		RepoRelDir:         ctx.RepoRelDir,
 		Workspace:          ctx.Workspace,
 		ProjectName:        ctx.ProjectName,
+		User:               ctx.User.Username,
+		JobID:              ctx.JobID,
 	}
 }
 
@@ -261,6 +351,8 @@ func (p *DefaultProjectCommandRunner) Apply(ctx command.ProjectContext) command.
 		RepoRelDir:   ctx.RepoRelDir,
 		Workspace:    ctx.Workspace,
 		ProjectName:  ctx.ProjectName,
+		User:         ctx.User.Username,
+		JobID:        ctx.JobID,
 	}
 }
 
@@ -274,6 +366,23 @@ func (p *DefaultProjectCommandRunner) ApprovePolicies(ctx command.ProjectContext
 		RepoRelDir:         ctx.RepoRelDir,
 		Workspace:          ctx.Workspace,
 		ProjectName:        ctx.ProjectName,
+		User:               ctx.User.Username,
+		JobID:              ctx.JobID,
+	}
+}
+
+func (p *DefaultProjectCommandRunner) ApproveDestroy(ctx command.ProjectContext) command.ProjectResult {
+	approveOut, failure, err := p.doApproveDestroy(ctx)
+	return command.ProjectResult{
+		Command:               command.ApproveDestroy,
+		Failure:               failure,
+		Error:                 err,
+		ApproveDestroySuccess: approveOut,
+		RepoRelDir:            ctx.RepoRelDir,
+		Workspace:             ctx.Workspace,
+		ProjectName:           ctx.ProjectName,
+		User:                  ctx.User.Username,
+		JobID:                 ctx.JobID,
 	}
 }
 
@@ -287,6 +396,8 @@ func (p *DefaultProjectCommandRunner) Version(ctx command.ProjectContext) comman
 		RepoRelDir:     ctx.RepoRelDir,
 		Workspace:      ctx.Workspace,
 		ProjectName:    ctx.ProjectName,
+		User:           ctx.User.Username,
+		JobID:          ctx.JobID,
 	}
 }
 
@@ -301,6 +412,8 @@ func (p *DefaultProjectCommandRunner) Import(ctx command.ProjectContext) command
 		RepoRelDir:    ctx.RepoRelDir,
 		Workspace:     ctx.Workspace,
 		ProjectName:   ctx.ProjectName,
+		User:          ctx.User.Username,
+		JobID:         ctx.JobID,
 	}
 }
 
@@ -316,6 +429,8 @@ func (p *DefaultProjectCommandRunner) StateRm(ctx command.ProjectContext) comman
 		RepoRelDir:     ctx.RepoRelDir,
 		Workspace:      ctx.Workspace,
 		ProjectName:    ctx.ProjectName,
+		User:           ctx.User.Username,
+		JobID:          ctx.JobID,
 	}
 }
 
@@ -344,7 +459,7 @@ func (p *DefaultProjectCommandRunner) doApprovePolicies(ctx command.ProjectConte
 	// Only query the users team membership if any teams have been configured as owners on any policy set(s).
 	if policySetCfg.HasTeamOwners() {
 		// A convenient way to access vcsClient. Not sure if best way.
-		userTeams, err := p.VcsClient.GetTeamNamesForUser(ctx.Pull.BaseRepo, ctx.User)
+		userTeams, err := ctx.Teams.GetTeamNamesForUser(p.VcsClient, ctx.Pull.BaseRepo, ctx.User)
 		if err != nil {
 			ctx.Log.Err("unable to get team membership for user: %s", err)
 			return nil, "", err
@@ -395,6 +510,7 @@ func (p *DefaultProjectCommandRunner) doApprovePolicies(ctx command.ProjectConte
 					Passed:        policyStatus.Passed,
 					CurApprovals:  prjPolicyStatus[i].Approvals,
 					ReqApprovals:  policySet.ApproveCount,
+					Revision:      policyStatus.Revision,
 				})
 			}
 		}
@@ -411,6 +527,28 @@ func (p *DefaultProjectCommandRunner) doApprovePolicies(ctx command.ProjectConte
 	}, failure, prjErr
 }
 
+func (p *DefaultProjectCommandRunner) doApproveDestroy(ctx command.ProjectContext) (*models.ApproveDestroySuccess, string, error) {
+	teams := []string{}
+
+	// Only query the users team membership if any teams have been configured as owners.
+	if ctx.DestroyProtection.HasTeamOwners() {
+		userTeams, err := ctx.Teams.GetTeamNamesForUser(p.VcsClient, ctx.Pull.BaseRepo, ctx.User)
+		if err != nil {
+			ctx.Log.Err("unable to get team membership for user: %s", err)
+			return nil, "", err
+		}
+		teams = append(teams, userTeams...)
+	}
+	if !ctx.DestroyProtection.Owners.IsOwner(ctx.User.Username, teams) {
+		return nil, "", fmt.Errorf("user %s is not a destroy protection owner - please contact the owners to approve destroying protected resources", ctx.User.Username)
+	}
+
+	return &models.ApproveDestroySuccess{
+		ApprovedBy: ctx.User.Username,
+		ApplyCmd:   ctx.ApplyCmd,
+	}, "", nil
+}
+
 func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext) (*models.PolicyCheckResults, string, error) {
 	// Acquire Atlantis lock for this repo/dir/workspace.
 	// This should already be acquired from the prior plan operation.
@@ -526,21 +664,21 @@ func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext)
 	return result, failure, nil
 }
 
-func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*models.PlanSuccess, string, error) {
+func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*models.PlanSuccess, *models.CostEstimate, string, error) {
 	// Acquire Atlantis lock for this repo/dir/workspace.
 	lockAttempt, err := p.Locker.TryLock(ctx.Log, ctx.Pull, ctx.User, ctx.Workspace, models.NewProject(ctx.Pull.BaseRepo.FullName, ctx.RepoRelDir), ctx.RepoLocking)
 	if err != nil {
-		return nil, "", errors.Wrap(err, "acquiring lock")
+		return nil, nil, "", errors.Wrap(err, "acquiring lock")
 	}
 	if !lockAttempt.LockAcquired {
-		return nil, lockAttempt.LockFailureReason, nil
+		return nil, nil, lockAttempt.LockFailureReason, nil
 	}
 	ctx.Log.Debug("acquired lock for project")
 
 	// Acquire internal lock for the directory we're going to operate in.
 	unlockFn, err := p.WorkingDirLocker.TryLock(ctx.Pull.BaseRepo.FullName, ctx.Pull.Num, ctx.Workspace, ctx.RepoRelDir)
 	if err != nil {
-		return nil, "", err
+		return nil, nil, "", err
 	}
 	defer unlockFn()
 
@@ -551,16 +689,23 @@ func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*model
 		if unlockErr := lockAttempt.UnlockFn(); unlockErr != nil {
 			ctx.Log.Err("error unlocking state after plan error: %v", unlockErr)
 		}
-		return nil, "", cloneErr
+		return nil, nil, "", cloneErr
 	}
 	projAbsPath := filepath.Join(repoDir, ctx.RepoRelDir)
 	if _, err = os.Stat(projAbsPath); os.IsNotExist(err) {
-		return nil, "", DirNotExistErr{RepoRelDir: ctx.RepoRelDir}
+		return nil, nil, "", DirNotExistErr{RepoRelDir: ctx.RepoRelDir}
 	}
 
 	failure, err := p.CommandRequirementHandler.ValidatePlanProject(repoDir, ctx)
 	if failure != "" || err != nil {
-		return nil, failure, err
+		return nil, nil, failure, err
+	}
+
+	// Remove the estimate of a previous plan so it can't be mistaken for the
+	// estimate of this one if the cost_estimate step was removed.
+	costEstimateFile := filepath.Join(projAbsPath, ctx.GetCostEstimateFileName())
+	if err := os.Remove(costEstimateFile); err != nil && !os.IsNotExist(err) {
+		return nil, nil, "", errors.Wrap(err, "removing previous cost estimate")
 	}
 
 	outputs, err := p.runSteps(ctx.Steps, ctx, projAbsPath)
@@ -569,7 +714,16 @@ func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*model
 		if unlockErr := lockAttempt.UnlockFn(); unlockErr != nil {
 			ctx.Log.Err("error unlocking state after plan error: %v", unlockErr)
 		}
-		return nil, "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
+		return nil, nil, "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
+	}
+
+	if p.PlanStore != nil {
+		if err := p.uploadPlanArtifacts(ctx, projAbsPath); err != nil {
+			if unlockErr := lockAttempt.UnlockFn(); unlockErr != nil {
+				ctx.Log.Err("error unlocking state after plan error: %v", unlockErr)
+			}
+			return nil, nil, "", err
+		}
 	}
 
 	return &models.PlanSuccess{
@@ -578,11 +732,66 @@ func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*model
 		RePlanCmd:       ctx.RePlanCmd,
 		ApplyCmd:        ctx.ApplyCmd,
 		HasDiverged:     hasDiverged,
-	}, "", nil
+		Analysis:        p.analyzePlan(ctx, projAbsPath),
+		PlannedAt:       time.Now(),
+		DependsOn:       ctx.DependsOn,
+		Targets:         ctx.Targets,
+		Replaces:        ctx.Replaces,
+	}, p.readCostEstimate(ctx, costEstimateFile), "", nil
+}
+
+// analyzePlan parses the JSON plan saved by the plan step. It returns nil if
+// there is no JSON plan, ex. when using TFE remote operations.
+func (p *DefaultProjectCommandRunner) analyzePlan(ctx command.ProjectContext, projAbsPath string) *models.PlanAnalysis {
+	showJSON, err := os.ReadFile(filepath.Join(projAbsPath, ctx.GetShowResultFileName()))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			ctx.Log.Warn("unable to read JSON plan: %s", err)
+		}
+		return nil
+	}
+	analysis, err := models.NewPlanAnalysis(showJSON)
+	if err != nil {
+		ctx.Log.Warn("unable to analyze plan: %s", err)
+		return nil
+	}
+	locateResourceChanges(projAbsPath, analysis.ResourceChanges)
+	return analysis
+}
+
+// readCostEstimate parses the estimate saved by the cost_estimate step. It
+// returns nil if the workflow doesn't estimate costs.
+func (p *DefaultProjectCommandRunner) readCostEstimate(ctx command.ProjectContext, costEstimateFile string) *models.CostEstimate {
+	output, err := os.ReadFile(costEstimateFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			ctx.Log.Warn("unable to read cost estimate: %s", err)
+		}
+		return nil
+	}
+	estimate, err := models.NewCostEstimate(output)
+	if err != nil {
+		ctx.Log.Warn("unable to parse cost estimate: %s", err)
+		return nil
+	}
+	return estimate
 }
 
 func (p *DefaultProjectCommandRunner) doApply(ctx command.ProjectContext) (applyOut string, failure string, err error) {
+	// Acquire internal lock for the directory we're going to operate in.
+	// It's taken first since the workspace may have to be cloned.
+	unlockFn, err := p.WorkingDirLocker.TryLock(ctx.Pull.BaseRepo.FullName, ctx.Pull.Num, ctx.Workspace, ctx.RepoRelDir)
+	if err != nil {
+		return "", "", err
+	}
+	defer unlockFn()
+
 	repoDir, err := p.WorkingDir.GetWorkingDir(ctx.Pull.BaseRepo, ctx.Pull, ctx.Workspace)
+	if p.PlanStore != nil && os.IsNotExist(errors.Cause(err)) {
+		// The project was planned by another Atlantis server.
+		ctx.Log.Info("workspace %q hasn't been cloned on this server, cloning it to apply the plan from the plan store", ctx.Workspace)
+		repoDir, _, err = p.WorkingDir.Clone(ctx.Log, ctx.HeadRepo, ctx.Pull, ctx.Workspace)
+	}
 	if err != nil {
 		if os.IsNotExist(err) {
 			return "", "", errors.New("project has not been cloned–did you run plan?")
@@ -594,19 +803,55 @@ func (p *DefaultProjectCommandRunner) doApply(ctx command.ProjectContext) (apply
 		return "", "", DirNotExistErr{RepoRelDir: ctx.RepoRelDir}
 	}
 
+	var downloadedPlan []byte
+	if p.PlanStore != nil {
+		downloadedPlan, err = p.downloadPlan(ctx, absPath)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if len(ctx.UnappliedDependencies) > 0 {
+		return "", fmt.Sprintf("This project depends on %s, which must be applied before running apply.", formatProjectNames(ctx.UnappliedDependencies)), nil
+	}
+
+	if failure = p.targetedApplyFailure(ctx); failure != "" {
+		return "", failure, nil
+	}
+
 	failure, err = p.CommandRequirementHandler.ValidateApplyProject(repoDir, ctx)
 	if failure != "" || err != nil {
 		return "", failure, err
 	}
 
-	// Acquire internal lock for the directory we're going to operate in.
-	unlockFn, err := p.WorkingDirLocker.TryLock(ctx.Pull.BaseRepo.FullName, ctx.Pull.Num, ctx.Workspace, ctx.RepoRelDir)
-	if err != nil {
-		return "", "", err
+	// The plan is checked while holding the lock since checking fetches the
+	// base branch into the working dir.
+	if !ctx.PlanStalenessChecked {
+		failure, err = p.PlanStalenessChecker.StalePlanReason(ctx)
+		if failure != "" || err != nil {
+			return "", failure, err
+		}
 	}
-	defer unlockFn()
 
-	outputs, err := p.runSteps(ctx.Steps, ctx, absPath)
+	// A plan from another working dir is applied with the providers it was
+	// made with, so the project is initialized again before it's applied.
+	// If applying fails, the plan is removed so the project is initialized
+	// again when apply is retried.
+	steps := ctx.Steps
+	planPath := filepath.Join(absPath, runtime.GetPlanFilename(ctx.Workspace, ctx.ProjectName))
+	if downloadedPlan != nil {
+		if err := os.WriteFile(planPath, downloadedPlan, 0600); err != nil {
+			return "", "", errors.Wrap(err, "writing planfile")
+		}
+		steps = append(append([]valid.Step{}, ctx.InitSteps...), ctx.Steps...)
+	}
+
+	outputs, err := p.runSteps(steps, ctx, absPath)
+	if err != nil && downloadedPlan != nil {
+		if removeErr := os.Remove(planPath); removeErr != nil && !os.IsNotExist(removeErr) {
+			ctx.Log.Warn("failed to delete planfile after failed apply: %s", removeErr)
+		}
+	}
 
 	p.Webhooks.Send(ctx.Log, webhooks.ApplyResult{ // nolint: errcheck
 		Workspace: ctx.Workspace,
@@ -621,9 +866,37 @@ func (p *DefaultProjectCommandRunner) doApply(ctx command.ProjectContext) (apply
 		return "", "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
 	}
 
+	if p.PlanStore != nil {
+		if err := p.deletePlan(ctx); err != nil {
+			ctx.Log.Warn("failed to delete plan from plan store after successful apply: %s", err)
+		}
+	}
+
 	return strings.Join(outputs, "\n"), "", nil
 }
 
+// targetedApplyFailure returns why ctx.User can't apply a plan that was made
+// with --target or --replace or "" if they can.
+func (p *DefaultProjectCommandRunner) targetedApplyFailure(ctx command.ProjectContext) string {
+	if !p.RestrictTargetedApplies || !ctx.IsTargeted() {
+		return ""
+	}
+	for _, user := range p.TargetedApplyAllowlist {
+		if strings.EqualFold(user, ctx.User.Username) {
+			return ""
+		}
+	}
+	if len(p.TargetedApplyAllowlist) == 0 {
+		return fmt.Sprintf("Applying partial plans is disabled. Re-plan with `%s` to plan all of the project's changes before running apply.", ctx.RePlanCmd)
+	}
+	var users []string
+	for _, user := range p.TargetedApplyAllowlist {
+		users = append(users, fmt.Sprintf("`%s`", user))
+	}
+	return fmt.Sprintf("Partial plans can only be applied by %s. Re-plan with `%s` to plan all of the project's changes before running apply.",
+		joinWithAnd(users), ctx.RePlanCmd)
+}
+
 func (p *DefaultProjectCommandRunner) doVersion(ctx command.ProjectContext) (versionOut string, failure string, err error) {
 	repoDir, err := p.WorkingDir.GetWorkingDir(ctx.Pull.BaseRepo, ctx.Pull, ctx.Workspace)
 	if err != nil {
@@ -742,8 +1015,34 @@ func (p *DefaultProjectCommandRunner) doStateRm(ctx command.ProjectContext) (out
 func (p *DefaultProjectCommandRunner) runSteps(steps []valid.Step, ctx command.ProjectContext, absPath string) ([]string, error) {
 	var outputs []string
 
+	if p.JobCanceller != nil {
+		finish := p.JobCanceller.StartJob(ctx)
+		defer finish()
+	}
+
+	// The slot is held for all the steps so a queued command reports its
+	// position once and isn't put back in the queue between steps.
+	if p.RunScheduler != nil {
+		release := p.RunScheduler.Acquire(ctx)
+		defer release()
+	}
+
+	// The timeouts start once the command has a slot.
+	var stageDeadline time.Time
+	if ctx.StageTimeout > 0 {
+		stageDeadline = time.Now().Add(ctx.StageTimeout)
+	}
+
 	envs := make(map[string]string)
 	for _, step := range steps {
+		if err := p.cancelledErr(ctx); err != nil {
+			return outputs, err
+		}
+		ctx.StepTimeout, ctx.StepDeadline = stepDeadline(step.Timeout, ctx.StageTimeout, stageDeadline)
+		if !ctx.StepDeadline.IsZero() && !time.Now().Before(ctx.StepDeadline) {
+			return outputs, fmt.Errorf("timed out after %s", ctx.StageTimeout)
+		}
+
 		var out string
 		var err error
 		switch step.StepName {
@@ -755,6 +1054,8 @@ func (p *DefaultProjectCommandRunner) runSteps(steps []valid.Step, ctx command.P
 			_, err = p.ShowStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
 		case "policy_check":
 			out, err = p.PolicyCheckStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+		case "cost_estimate":
+			_, err = p.CostEstimateStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
 		case "apply":
 			out, err = p.ApplyStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
 		case "version":
@@ -779,8 +1080,40 @@ func (p *DefaultProjectCommandRunner) runSteps(steps []valid.Step, ctx command.P
 			outputs = append(outputs, out)
 		}
 		if err != nil {
+			if cancelledErr := p.cancelledErr(ctx); cancelledErr != nil {
+				return outputs, cancelledErr
+			}
 			return outputs, err
 		}
 	}
 	return outputs, nil
 }
+
+// cancelledErr returns an error saying who cancelled the job for ctx or nil
+// if it hasn't been cancelled.
+func (p *DefaultProjectCommandRunner) cancelledErr(ctx command.ProjectContext) error {
+	if p.JobCanceller == nil {
+		return nil
+	}
+	if user := p.JobCanceller.CancelledBy(ctx.JobID); user != "" {
+		return fmt.Errorf("cancelled by %s", user)
+	}
+	return nil
+}
+
+// stepDeadline returns how long a step can run for and when it must stop by
+// given the step's own timeout and what's left of its stage's timeout. Zero
+// values mean there's no limit.
+func stepDeadline(stepTimeout time.Duration, stageTimeout time.Duration, stageDeadline time.Time) (time.Duration, time.Time) {
+	if stepTimeout <= 0 {
+		if stageDeadline.IsZero() {
+			return 0, time.Time{}
+		}
+		return stageTimeout, stageDeadline
+	}
+	deadline := time.Now().Add(stepTimeout)
+	if !stageDeadline.IsZero() && stageDeadline.Before(deadline) {
+		return stageTimeout, stageDeadline
+	}
+	return stepTimeout, deadline
+}
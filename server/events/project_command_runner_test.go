@@ -17,12 +17,15 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	. "github.com/petergtz/pegomock/v4"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/core/terraform"
 	tmocks "github.com/runatlantis/atlantis/server/core/terraform/mocks"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/command"
@@ -30,6 +33,8 @@ import (
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/models/testdata"
 	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/jobs"
 	jobmocks "github.com/runatlantis/atlantis/server/jobs/mocks"
 	"github.com/runatlantis/atlantis/server/logging"
 	. "github.com/runatlantis/atlantis/testing"
@@ -113,11 +118,19 @@ func TestDefaultProjectCommandRunner_Plan(t *testing.T) {
 	When(mockPlan.Run(ctx, nil, repoDir, expEnvs)).ThenReturn("plan", nil)
 	When(mockApply.Run(ctx, nil, repoDir, expEnvs)).ThenReturn("apply", nil)
 	When(mockRun.Run(ctx, "", repoDir, expEnvs, true)).ThenReturn("run", nil)
+	// The plan step saves the JSON plan which should then be analyzed.
+	showJSON := `{"resource_changes":[{"address":"null_resource.a","change":{"actions":["create"]}}]}`
+	Ok(t, os.WriteFile(filepath.Join(repoDir, ctx.GetShowResultFileName()), []byte(showJSON), 0600))
 	res := runner.Plan(ctx)
 
 	Assert(t, res.PlanSuccess != nil, "exp plan success")
 	Equals(t, "https://lock-key", res.PlanSuccess.LockURL)
 	Equals(t, "run\napply\nplan\ninit", res.PlanSuccess.TerraformOutput)
+	Equals(t, &models.PlanAnalysis{
+		ResourceChanges: []models.ResourceChange{
+			{Address: "null_resource.a", Action: models.CreateResourceAction},
+		},
+	}, res.PlanSuccess.Analysis)
 	expSteps := []string{"run", "apply", "plan", "init", "env"}
 	for _, step := range expSteps {
 		switch step {
@@ -133,6 +146,54 @@ func TestDefaultProjectCommandRunner_Plan(t *testing.T) {
 	}
 }
 
+// Test that the estimate saved by the cost_estimate step is added to the
+// result and that estimates of previous plans aren't.
+func TestDefaultProjectCommandRunner_PlanCostEstimate(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockPlan := mocks.NewMockStepRunner()
+	mockCostEstimate := mocks.NewMockStepRunner()
+	mockWorkingDir := mocks.NewMockWorkingDir()
+	mockLocker := mocks.NewMockProjectLocker()
+	runner := events.DefaultProjectCommandRunner{
+		Locker:                    mockLocker,
+		LockURLGenerator:          mockURLGenerator{},
+		PlanStepRunner:            mockPlan,
+		CostEstimateStepRunner:    mockCostEstimate,
+		WorkingDir:                mockWorkingDir,
+		WorkingDirLocker:          events.NewDefaultWorkingDirLocker(),
+		CommandRequirementHandler: mocks.NewMockCommandRequirementHandler(),
+	}
+	repoDir := t.TempDir()
+	When(mockWorkingDir.Clone(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](), Any[string]())).
+		ThenReturn(repoDir, false, nil)
+	When(mockLocker.TryLock(Any[logging.SimpleLogging](), Any[models.PullRequest](), Any[models.User](), Any[string](), Any[models.Project](), AnyBool())).
+		ThenReturn(&events.TryLockResponse{LockAcquired: true, LockKey: "lock-key"}, nil)
+	ctx := command.ProjectContext{
+		Log:        logging.NewNoopLogger(t),
+		Workspace:  "default",
+		RepoRelDir: ".",
+	}
+	costEstimateFile := filepath.Join(repoDir, ctx.GetCostEstimateFileName())
+	When(mockCostEstimate.Run(Any[command.ProjectContext](), Any[[]string](), Eq(repoDir), Any[map[string]string]())).
+		Then(func(params []Param) ReturnValues {
+			err := os.WriteFile(costEstimateFile, []byte(`{"currency": "USD", "diffTotalMonthlyCost": "12.5"}`), 0600)
+			return ReturnValues{"", err}
+		})
+
+	ctx.Steps = []valid.Step{{StepName: "plan"}, {StepName: "cost_estimate"}}
+	res := runner.Plan(ctx)
+	Ok(t, res.Error)
+	Equals(t, &models.CostEstimate{Currency: "USD", DiffMonthlyCost: 12.5}, res.CostEstimate)
+
+	t.Log("the estimate shouldn't be kept once the cost_estimate step is removed")
+	ctx.Steps = []valid.Step{{StepName: "plan"}}
+	res = runner.Plan(ctx)
+	Ok(t, res.Error)
+	Assert(t, res.CostEstimate == nil, "exp no cost estimate")
+	_, err := os.Stat(costEstimateFile)
+	Assert(t, os.IsNotExist(err), "exp previous cost estimate to be removed")
+}
+
 func TestProjectOutputWrapper(t *testing.T) {
 	RegisterMockTestingT(t)
 	ctx := command.ProjectContext{
@@ -241,12 +302,105 @@ func TestProjectOutputWrapper(t *testing.T) {
 	}
 }
 
+func TestProjectWebhooksWrapper(t *testing.T) {
+	RegisterMockTestingT(t)
+	ctx := command.ProjectContext{
+		Log:         logging.NewNoopLogger(t),
+		Workspace:   "default",
+		RepoRelDir:  "dir",
+		ProjectName: "project",
+		BaseRepo:    models.Repo{FullName: "owner/repo"},
+		Pull:        models.PullRequest{Num: 1},
+		User:        models.User{Username: "user"},
+	}
+
+	cases := []struct {
+		Description string
+		CommandName command.Name
+		Event       string
+		Result      command.ProjectResult
+		ExpSuccess  bool
+		ExpStats    *models.PlanSuccessStats
+	}{
+		{
+			Description: "plan success",
+			CommandName: command.Plan,
+			Event:       webhooks.PlanEvent,
+			Result: command.ProjectResult{
+				PlanSuccess: &models.PlanSuccess{TerraformOutput: "Plan: 1 to add, 2 to change, 3 to destroy."},
+			},
+			ExpSuccess: true,
+			ExpStats:   &models.PlanSuccessStats{Add: 1, Change: 2, Destroy: 3, Changes: true},
+		},
+		{
+			Description: "plan error",
+			CommandName: command.Plan,
+			Event:       webhooks.PlanEvent,
+			Result:      command.ProjectResult{Error: errors.New("error")},
+		},
+		{
+			Description: "policy check failure",
+			CommandName: command.PolicyCheck,
+			Event:       webhooks.PolicyCheckEvent,
+			Result:      command.ProjectResult{Failure: "failure"},
+		},
+		{
+			Description: "apply success",
+			CommandName: command.Apply,
+			Event:       webhooks.ApplyEvent,
+			Result:      command.ProjectResult{ApplySuccess: "success"},
+			ExpSuccess:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Description, func(t *testing.T) {
+			mockProjectCommandRunner := mocks.NewMockProjectCommandRunner()
+			mockWebhooks := mocks.NewMockWebhooksSender()
+			mockJobURLGenerator := jobmocks.NewMockProjectJobURLGenerator()
+			runner := &events.ProjectWebhooksWrapper{
+				ProjectCommandRunner: mockProjectCommandRunner,
+				Webhooks:             mockWebhooks,
+				JobURLGenerator:      mockJobURLGenerator,
+			}
+			When(mockProjectCommandRunner.Plan(Any[command.ProjectContext]())).ThenReturn(c.Result)
+			When(mockProjectCommandRunner.PolicyCheck(Any[command.ProjectContext]())).ThenReturn(c.Result)
+			When(mockProjectCommandRunner.Apply(Any[command.ProjectContext]())).ThenReturn(c.Result)
+			When(mockJobURLGenerator.GenerateProjectJobURL(ctx)).ThenReturn("job-url", nil)
+
+			var res command.ProjectResult
+			switch c.CommandName {
+			case command.Plan:
+				res = runner.Plan(ctx)
+			case command.PolicyCheck:
+				res = runner.PolicyCheck(ctx)
+			case command.Apply:
+				res = runner.Apply(ctx)
+			}
+			Equals(t, c.Result, res)
+
+			_, event := mockWebhooks.VerifyWasCalledOnce().SendEvent(Any[logging.SimpleLogging](), Any[webhooks.EventResult]()).GetCapturedArguments()
+			Equals(t, c.Event, event.Event)
+			Equals(t, c.ExpSuccess, event.Success)
+			Equals(t, c.ExpStats, event.PlanStats)
+			Equals(t, "job-url", event.JobURL)
+			Equals(t, "project", event.ProjectName)
+			Equals(t, "dir", event.Directory)
+			Equals(t, "default", event.Workspace)
+			Equals(t, ctx.BaseRepo, event.Repo)
+			Equals(t, ctx.Pull, event.Pull)
+			Equals(t, ctx.User, event.User)
+		})
+	}
+}
+
 // Test what happens if there's no working dir. This signals that the project
 // was never planned.
 func TestDefaultProjectCommandRunner_ApplyNotCloned(t *testing.T) {
 	mockWorkingDir := mocks.NewMockWorkingDir()
 	runner := &events.DefaultProjectCommandRunner{
-		WorkingDir: mockWorkingDir,
+		WorkingDir:       mockWorkingDir,
+		WorkingDirLocker: events.NewDefaultWorkingDirLocker(),
 	}
 	ctx := command.ProjectContext{}
 	When(mockWorkingDir.GetWorkingDir(ctx.BaseRepo, ctx.Pull, ctx.Workspace)).ThenReturn("", os.ErrNotExist)
@@ -324,6 +478,167 @@ func TestDefaultProjectCommandRunner_ApplyDiverged(t *testing.T) {
 	Equals(t, "Default branch must be rebased onto pull request before running apply.", res.Failure)
 }
 
+// Test that if the plan is stale we give an error.
+func TestDefaultProjectCommandRunner_ApplyStalePlan(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockWorkingDir := mocks.NewMockWorkingDir()
+	mockStepRunner := mocks.NewMockStepRunner()
+	runner := &events.DefaultProjectCommandRunner{
+		WorkingDir:       mockWorkingDir,
+		WorkingDirLocker: events.NewDefaultWorkingDirLocker(),
+		ApplyStepRunner:  mockStepRunner,
+		Webhooks:         mocks.NewMockWebhooksSender(),
+		CommandRequirementHandler: &events.DefaultCommandRequirementHandler{
+			WorkingDir: mockWorkingDir,
+		},
+		PlanStalenessChecker: &events.DefaultPlanStalenessChecker{
+			WorkingDir: mockWorkingDir,
+		},
+	}
+	ctx := command.ProjectContext{
+		Log:        logging.NewNoopLogger(t),
+		Steps:      []valid.Step{{StepName: "apply"}},
+		RepoRelDir: ".",
+		RePlanCmd:  "atlantis plan -d .",
+		PlanTTL:    time.Hour,
+		PlannedAt:  time.Now().Add(-90 * time.Minute),
+	}
+	tmp := t.TempDir()
+	When(mockWorkingDir.GetWorkingDir(ctx.BaseRepo, ctx.Pull, ctx.Workspace)).ThenReturn(tmp, nil)
+
+	res := runner.Apply(ctx)
+	Equals(t, "Plan is 1h30m0s old which is older than the plan TTL of 1h0m0s. Re-plan with `atlantis plan -d .` before applying.", res.Failure)
+	mockStepRunner.VerifyWasCalled(Never()).Run(Any[command.ProjectContext](), Any[[]string](), Any[string](), Any[map[string]string]())
+
+	t.Log("plans that were already checked aren't checked again")
+	ctx.PlanStalenessChecked = true
+	When(mockStepRunner.Run(Any[command.ProjectContext](), Any[[]string](), Any[string](), Any[map[string]string]())).ThenReturn("applied", nil)
+	res = runner.Apply(ctx)
+	Equals(t, "", res.Failure)
+	Equals(t, "applied", res.ApplySuccess)
+}
+
+// Test that projects aren't applied before the projects they depend on.
+func TestDefaultProjectCommandRunner_ApplyUnappliedDependencies(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockWorkingDir := mocks.NewMockWorkingDir()
+	mockStepRunner := mocks.NewMockStepRunner()
+	runner := &events.DefaultProjectCommandRunner{
+		WorkingDir:       mockWorkingDir,
+		WorkingDirLocker: events.NewDefaultWorkingDirLocker(),
+		ApplyStepRunner:  mockStepRunner,
+		CommandRequirementHandler: &events.DefaultCommandRequirementHandler{
+			WorkingDir: mockWorkingDir,
+		},
+		PlanStalenessChecker: &events.DefaultPlanStalenessChecker{
+			WorkingDir: mockWorkingDir,
+		},
+	}
+	ctx := command.ProjectContext{
+		Log:                   logging.NewNoopLogger(t),
+		Steps:                 []valid.Step{{StepName: "apply"}},
+		RepoRelDir:            ".",
+		DependsOn:             []string{"dev", "staging", "qa"},
+		UnappliedDependencies: []string{"staging", "qa"},
+	}
+	tmp := t.TempDir()
+	When(mockWorkingDir.GetWorkingDir(ctx.BaseRepo, ctx.Pull, ctx.Workspace)).ThenReturn(tmp, nil)
+
+	res := runner.Apply(ctx)
+	Equals(t, "This project depends on `staging` and `qa`, which must be applied before running apply.", res.Failure)
+	mockStepRunner.VerifyWasCalled(Never()).Run(Any[command.ProjectContext](), Any[[]string](), Any[string](), Any[map[string]string]())
+}
+
+// Test that partial plans can only be applied by allowlisted users when
+// targeted applies are restricted.
+func TestDefaultProjectCommandRunner_ApplyTargetedPlan(t *testing.T) {
+	cases := []struct {
+		description string
+		restrict    bool
+		allowlist   []string
+		username    string
+		targets     []string
+		replaces    []string
+		expFailure  string
+	}{
+		{
+			description: "not restricted",
+			targets:     []string{"aws_instance.web"},
+			username:    "someone",
+		},
+		{
+			description: "not targeted",
+			restrict:    true,
+			username:    "someone",
+		},
+		{
+			description: "no allowlist",
+			restrict:    true,
+			targets:     []string{"aws_instance.web"},
+			username:    "someone",
+			expFailure:  "Applying partial plans is disabled. Re-plan with `atlantis plan -d .` to plan all of the project's changes before running apply.",
+		},
+		{
+			description: "user not in allowlist",
+			restrict:    true,
+			allowlist:   []string{"alice", "bob"},
+			replaces:    []string{"aws_instance.db"},
+			username:    "someone",
+			expFailure:  "Partial plans can only be applied by `alice` and `bob`. Re-plan with `atlantis plan -d .` to plan all of the project's changes before running apply.",
+		},
+		{
+			description: "user in allowlist",
+			restrict:    true,
+			allowlist:   []string{"alice", "bob"},
+			targets:     []string{"aws_instance.web"},
+			username:    "Bob",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			mockWorkingDir := mocks.NewMockWorkingDir()
+			mockStepRunner := mocks.NewMockStepRunner()
+			runner := &events.DefaultProjectCommandRunner{
+				WorkingDir:       mockWorkingDir,
+				WorkingDirLocker: events.NewDefaultWorkingDirLocker(),
+				ApplyStepRunner:  mockStepRunner,
+				Webhooks:         mocks.NewMockWebhooksSender(),
+				CommandRequirementHandler: &events.DefaultCommandRequirementHandler{
+					WorkingDir: mockWorkingDir,
+				},
+				PlanStalenessChecker: &events.DefaultPlanStalenessChecker{
+					WorkingDir: mockWorkingDir,
+				},
+				RestrictTargetedApplies: c.restrict,
+				TargetedApplyAllowlist:  c.allowlist,
+			}
+			ctx := command.ProjectContext{
+				Log:        logging.NewNoopLogger(t),
+				Steps:      []valid.Step{{StepName: "apply"}},
+				RepoRelDir: ".",
+				RePlanCmd:  "atlantis plan -d .",
+				User:       models.User{Username: c.username},
+				Targets:    c.targets,
+				Replaces:   c.replaces,
+			}
+			tmp := t.TempDir()
+			When(mockWorkingDir.GetWorkingDir(ctx.BaseRepo, ctx.Pull, ctx.Workspace)).ThenReturn(tmp, nil)
+			When(mockStepRunner.Run(Any[command.ProjectContext](), Any[[]string](), Any[string](), Any[map[string]string]())).ThenReturn("apply", nil)
+
+			res := runner.Apply(ctx)
+			Equals(t, c.expFailure, res.Failure)
+			if c.expFailure != "" {
+				mockStepRunner.VerifyWasCalled(Never()).Run(Any[command.ProjectContext](), Any[[]string](), Any[string](), Any[map[string]string]())
+			} else {
+				Ok(t, res.Error)
+				Equals(t, "apply", res.ApplySuccess)
+			}
+		})
+	}
+}
+
 // Test that it runs the expected apply steps.
 func TestDefaultProjectCommandRunner_Apply(t *testing.T) {
 	cases := []struct {
@@ -430,6 +745,7 @@ func TestDefaultProjectCommandRunner_Apply(t *testing.T) {
 				Webhooks:                  mockSender,
 				WorkingDirLocker:          events.NewDefaultWorkingDirLocker(),
 				CommandRequirementHandler: applyReqHandler,
+				PlanStalenessChecker:      &events.DefaultPlanStalenessChecker{WorkingDir: mockWorkingDir},
 			}
 			repoDir := t.TempDir()
 			When(mockWorkingDir.GetWorkingDir(
@@ -500,6 +816,7 @@ func TestDefaultProjectCommandRunner_ApplyRunStepFailure(t *testing.T) {
 		WorkingDir:                mockWorkingDir,
 		WorkingDirLocker:          events.NewDefaultWorkingDirLocker(),
 		CommandRequirementHandler: applyReqHandler,
+		PlanStalenessChecker:      &events.DefaultPlanStalenessChecker{WorkingDir: mockWorkingDir},
 		Webhooks:                  mockSender,
 	}
 	repoDir := t.TempDir()
@@ -624,6 +941,187 @@ func TestDefaultProjectCommandRunner_RunEnvSteps(t *testing.T) {
 	Equals(t, "var=\n\nvar=value\n\ndynamic_var=dynamic_value\n\ndynamic_var=overridden\n", res.PlanSuccess.TerraformOutput)
 }
 
+// Test that steps are stopped when they time out or their job is cancelled.
+func TestDefaultProjectCommandRunner_StopSteps(t *testing.T) {
+	cases := []struct {
+		description  string
+		stageTimeout time.Duration
+		stepTimeout  time.Duration
+		cancel       bool
+		expErr       string
+	}{
+		{
+			description: "step timeout",
+			stepTimeout: 100 * time.Millisecond,
+			expErr:      "timed out after 100ms",
+		},
+		{
+			description:  "stage timeout",
+			stageTimeout: 100 * time.Millisecond,
+			stepTimeout:  time.Hour,
+			expErr:       "timed out after 100ms",
+		},
+		{
+			description: "cancelled",
+			cancel:      true,
+			expErr:      "cancelled by alice",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tfClient := tmocks.NewMockClient()
+			tfVersion, err := version.NewVersion("0.12.0")
+			Ok(t, err)
+			canceller := jobs.NewJobCanceller()
+			run := runtime.RunStepRunner{
+				TerraformExecutor:       tfClient,
+				DefaultTFVersion:        tfVersion,
+				ProjectCmdOutputHandler: jobmocks.NewMockProjectCommandOutputHandler(),
+				JobCanceller:            canceller,
+			}
+			mockWorkingDir := mocks.NewMockWorkingDir()
+			mockLocker := mocks.NewMockProjectLocker()
+			runner := events.DefaultProjectCommandRunner{
+				Locker:                    mockLocker,
+				LockURLGenerator:          mockURLGenerator{},
+				RunStepRunner:             &run,
+				WorkingDir:                mockWorkingDir,
+				WorkingDirLocker:          events.NewDefaultWorkingDirLocker(),
+				CommandRequirementHandler: mocks.NewMockCommandRequirementHandler(),
+				JobCanceller:              canceller,
+			}
+			When(mockWorkingDir.Clone(
+				Any[logging.SimpleLogging](),
+				Any[models.Repo](),
+				Any[models.PullRequest](),
+				Any[string](),
+			)).ThenReturn(t.TempDir(), false, nil)
+			When(mockLocker.TryLock(
+				Any[logging.SimpleLogging](),
+				Any[models.PullRequest](),
+				Any[models.User](),
+				Any[string](),
+				Any[models.Project](),
+				AnyBool(),
+			)).ThenReturn(&events.TryLockResponse{
+				LockAcquired: true,
+				LockKey:      "lock-key",
+				UnlockFn:     func() error { return nil },
+			}, nil)
+
+			ctx := command.ProjectContext{
+				Log:          logging.NewNoopLogger(t),
+				JobID:        "1234",
+				BaseRepo:     models.Repo{FullName: "owner/repo"},
+				Pull:         models.PullRequest{Num: 1},
+				StageTimeout: c.stageTimeout,
+				Steps: []valid.Step{
+					{
+						StepName:   "run",
+						RunCommand: "sleep 30",
+						Timeout:    c.stepTimeout,
+					},
+					{
+						StepName:   "run",
+						RunCommand: "echo not run",
+					},
+				},
+				Workspace:  "default",
+				RepoRelDir: ".",
+			}
+			if c.cancel {
+				go func() {
+					for len(canceller.CancelPullJobs("owner/repo", 1, "alice")) == 0 {
+						time.Sleep(10 * time.Millisecond)
+					}
+				}()
+			}
+
+			start := time.Now()
+			res := runner.Plan(ctx)
+			Assert(t, res.PlanSuccess == nil, "exp plan to fail")
+			ErrContains(t, c.expErr, res.Error)
+			Assert(t, time.Since(start) < 10*time.Second, "exp step to be stopped, took %s", time.Since(start))
+		})
+	}
+}
+
+func TestDefaultProjectCommandRunner_RunScheduler(t *testing.T) {
+	t.Log("a queued command should take a single slot for all its steps and only start its timeouts once it has it")
+	RegisterMockTestingT(t)
+	tfClient := tmocks.NewMockClient()
+	tfVersion, err := version.NewVersion("0.12.0")
+	Ok(t, err)
+	run := runtime.RunStepRunner{
+		TerraformExecutor:       tfClient,
+		DefaultTFVersion:        tfVersion,
+		ProjectCmdOutputHandler: jobmocks.NewMockProjectCommandOutputHandler(),
+	}
+	notifier := tmocks.NewMockRunQueueNotifier()
+	scheduler := terraform.NewRunScheduler(1, 0, notifier)
+	mockWorkingDir := mocks.NewMockWorkingDir()
+	mockLocker := mocks.NewMockProjectLocker()
+	runner := events.DefaultProjectCommandRunner{
+		Locker:                    mockLocker,
+		LockURLGenerator:          mockURLGenerator{},
+		RunStepRunner:             &run,
+		WorkingDir:                mockWorkingDir,
+		WorkingDirLocker:          events.NewDefaultWorkingDirLocker(),
+		CommandRequirementHandler: mocks.NewMockCommandRequirementHandler(),
+		RunScheduler:              scheduler,
+	}
+	When(mockWorkingDir.Clone(
+		Any[logging.SimpleLogging](),
+		Any[models.Repo](),
+		Any[models.PullRequest](),
+		Any[string](),
+	)).ThenReturn(t.TempDir(), false, nil)
+	When(mockLocker.TryLock(
+		Any[logging.SimpleLogging](),
+		Any[models.PullRequest](),
+		Any[models.User](),
+		Any[string](),
+		Any[models.Project](),
+		AnyBool(),
+	)).ThenReturn(&events.TryLockResponse{
+		LockAcquired: true,
+		LockKey:      "lock-key",
+		UnlockFn:     func() error { return nil },
+	}, nil)
+
+	ctx := command.ProjectContext{
+		Log:          logging.NewNoopLogger(t),
+		CommandName:  command.Plan,
+		BaseRepo:     models.Repo{FullName: "owner/repo"},
+		Pull:         models.PullRequest{Num: 1},
+		StageTimeout: 500 * time.Millisecond,
+		Steps: []valid.Step{
+			{StepName: "run", RunCommand: "echo one"},
+			{StepName: "run", RunCommand: "echo two"},
+		},
+		Workspace:  "default",
+		RepoRelDir: ".",
+	}
+
+	// Take the only slot so the plan has to wait for longer than its timeout.
+	release := scheduler.Acquire(command.ProjectContext{BaseRepo: models.Repo{FullName: "owner/other"}})
+	results := make(chan command.ProjectResult)
+	go func() {
+		results <- runner.Plan(ctx)
+	}()
+	notifier.VerifyWasCalledEventually(Once(), 5*time.Second).RunQueued(Any[command.ProjectContext](), Eq(1))
+	time.Sleep(time.Second)
+	release()
+
+	res := <-results
+	Ok(t, res.Error)
+	Equals(t, "one\n\ntwo\n", res.PlanSuccess.TerraformOutput)
+	notifier.VerifyWasCalledOnce().RunQueued(Any[command.ProjectContext](), Any[int]())
+	notifier.VerifyWasCalledOnce().RunDequeued(Any[command.ProjectContext]())
+	Equals(t, 0, scheduler.Status().Running)
+}
+
 // Test that it runs the expected import steps.
 func TestDefaultProjectCommandRunner_Import(t *testing.T) {
 	expEnvs := map[string]string{}
@@ -705,6 +1203,7 @@ func TestDefaultProjectCommandRunner_Import(t *testing.T) {
 				Webhooks:                  mockSender,
 				WorkingDirLocker:          events.NewDefaultWorkingDirLocker(),
 				CommandRequirementHandler: applyReqHandler,
+				PlanStalenessChecker:      &events.DefaultPlanStalenessChecker{WorkingDir: mockWorkingDir},
 			}
 			ctx := command.ProjectContext{
 				Log:                logging.NewNoopLogger(t),
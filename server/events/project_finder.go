@@ -196,27 +196,7 @@ func (p *DefaultProjectFinder) DetermineProjectsViaConfig(log logging.SimpleLogg
 			continue
 		}
 
-		var whenModifiedRelToRepoRoot []string
-		for _, wm := range project.Autoplan.WhenModified {
-			wm = strings.TrimSpace(wm)
-			// An exclusion uses a '!' at the beginning. If it's there, we need
-			// to remove it, then add in the project path, then add it back.
-			exclusion := false
-			if wm != "" && wm[0] == '!' {
-				wm = wm[1:]
-				exclusion = true
-			}
-
-			// Prepend project dir to when modified patterns because the patterns
-			// are relative to the project dirs but our list of modified files is
-			// relative to the repo root.
-			wmRelPath := filepath.Join(project.Dir, wm)
-			if exclusion {
-				wmRelPath = "!" + wmRelPath
-			}
-			whenModifiedRelToRepoRoot = append(whenModifiedRelToRepoRoot, wmRelPath)
-		}
-		pm, err := patternmatcher.New(whenModifiedRelToRepoRoot)
+		pm, err := whenModifiedMatcher(project.Dir, project.Autoplan.WhenModified)
 		if err != nil {
 			return nil, errors.Wrapf(err, "matching modified files with patterns: %v", project.Autoplan.WhenModified)
 		}
@@ -386,3 +366,29 @@ func (p *DefaultProjectFinder) removeNonExistingDirs(relativePaths []string, abs
 	}
 	return filtered
 }
+
+// whenModifiedMatcher returns a matcher for files relative to the repo root
+// from when_modified patterns, which are relative to projectDir.
+func whenModifiedMatcher(projectDir string, whenModified []string) (*patternmatcher.PatternMatcher, error) {
+	var whenModifiedRelToRepoRoot []string
+	for _, wm := range whenModified {
+		wm = strings.TrimSpace(wm)
+		// An exclusion uses a '!' at the beginning. If it's there, we need
+		// to remove it, then add in the project path, then add it back.
+		exclusion := false
+		if wm != "" && wm[0] == '!' {
+			wm = wm[1:]
+			exclusion = true
+		}
+
+		// Prepend project dir to when modified patterns because the patterns
+		// are relative to the project dirs but our list of modified files is
+		// relative to the repo root.
+		wmRelPath := filepath.Join(projectDir, wm)
+		if exclusion {
+			wmRelPath = "!" + wmRelPath
+		}
+		whenModifiedRelToRepoRoot = append(whenModifiedRelToRepoRoot, wmRelPath)
+	}
+	return patternmatcher.New(whenModifiedRelToRepoRoot)
+}
@@ -55,6 +55,8 @@ type PullClosedExecutor struct {
 	Backend                  locking.Backend
 	PullClosedTemplate       PullCleanupTemplate
 	LogStreamResourceCleaner ResourceCleaner
+	// PullStatusNotifier, if set, is told when a pull's status is deleted.
+	PullStatusNotifier PullStatusNotifier
 }
 
 type templatedProject struct {
@@ -112,6 +114,8 @@ func (p *PullClosedExecutor) CleanUpPull(repo models.Repo, pull models.PullReque
 	// Delete pull from DB.
 	if err := p.Backend.DeletePullStatus(pull); err != nil {
 		p.Logger.Err("deleting pull from db: %s", err)
+	} else if p.PullStatusNotifier != nil {
+		p.PullStatusNotifier.PullStatusChanged(pull)
 	}
 
 	// If there are no locks then there's no need to comment.
@@ -95,6 +95,34 @@ func TestCleanUpPullNoLocks(t *testing.T) {
 	cp.VerifyWasCalled(Never()).CreateComment(Any[models.Repo](), Any[int](), Any[string](), Any[string]())
 }
 
+type recordingPullStatusNotifier struct {
+	pulls []models.PullRequest
+}
+
+func (r *recordingPullStatusNotifier) PullStatusChanged(pull models.PullRequest) {
+	r.pulls = append(r.pulls, pull)
+}
+
+func TestCleanUpPullNotifiesPullStatusChanged(t *testing.T) {
+	t.Log("deleting the pull's status is shown on the dashboard")
+	RegisterMockTestingT(t)
+	l := lockmocks.NewMockLocker()
+	db, err := db.New(t.TempDir())
+	Ok(t, err)
+	notifier := &recordingPullStatusNotifier{}
+	pce := events.PullClosedExecutor{
+		Locker:             l,
+		VCSClient:          vcsmocks.NewMockClient(),
+		WorkingDir:         mocks.NewMockWorkingDir(),
+		Backend:            db,
+		PullStatusNotifier: notifier,
+	}
+	When(l.UnlockByPull(testdata.GithubRepo.FullName, testdata.Pull.Num)).ThenReturn(nil, nil)
+	err = pce.CleanUpPull(testdata.GithubRepo, testdata.Pull)
+	Ok(t, err)
+	Equals(t, []models.PullRequest{testdata.Pull}, notifier.pulls)
+}
+
 func TestCleanUpPullComments(t *testing.T) {
 	t.Log("should comment correctly")
 	RegisterMockTestingT(t)
@@ -194,7 +222,7 @@ func TestCleanUpLogStreaming(t *testing.T) {
 
 		// Create Log streaming resources
 		prjCmdOutput := make(chan *jobs.ProjectCmdOutputLine)
-		prjCmdOutHandler := jobs.NewAsyncProjectCommandOutputHandler(prjCmdOutput, logger)
+		prjCmdOutHandler := jobs.NewAsyncProjectCommandOutputHandler(prjCmdOutput, &jobs.NoopJobStore{}, logger)
 		ctx := command.ProjectContext{
 			BaseRepo:    testdata.GithubRepo,
 			Pull:        testdata.Pull,
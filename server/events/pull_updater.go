@@ -12,6 +12,12 @@ type PullUpdater struct {
 }
 
 func (c *PullUpdater) updatePull(ctx *command.Context, cmd PullCommand, res command.Result) {
+	ctx.RunResults = append(ctx.RunResults, command.RunResult{
+		Name:       cmd.CommandName(),
+		SubCommand: cmd.SubCommandName(),
+		Result:     res,
+	})
+
 	// Log if we got any errors or failures.
 	if res.Error != nil {
 		ctx.Log.Err(res.Error.Error())
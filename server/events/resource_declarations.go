@@ -0,0 +1,101 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+var declarationsSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "resource", LabelNames: []string{"type", "name"}},
+		{Type: "data", LabelNames: []string{"type", "name"}},
+		{Type: "module", LabelNames: []string{"name"}},
+	},
+}
+
+// locateResourceChanges sets the file and line where each changed resource is
+// declared in the root module in projAbsPath. Resources in child modules are
+// located at their root module call. Files that don't parse are skipped.
+func locateResourceChanges(projAbsPath string, changes []models.ResourceChange) {
+	infos, err := os.ReadDir(projAbsPath)
+	if err != nil {
+		return
+	}
+	declarations := make(map[string]hcl.Pos)
+	files := make(map[string]string)
+	parser := hclparse.NewParser()
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), ".tf") {
+			continue
+		}
+		file, diags := parser.ParseHCLFile(filepath.Join(projAbsPath, info.Name()))
+		if diags.HasErrors() {
+			continue
+		}
+		content, _, _ := file.Body.PartialContent(declarationsSchema)
+		for _, block := range content.Blocks {
+			key := strings.Join(block.Labels, ".")
+			switch block.Type {
+			case "data":
+				key = "data." + key
+			case "module":
+				key = "module." + key
+			}
+			declarations[key] = block.DefRange.Start
+			files[key] = info.Name()
+		}
+	}
+
+	for i, change := range changes {
+		key := rootDeclarationKey(change.Address)
+		if pos, ok := declarations[key]; ok {
+			changes[i].File = files[key]
+			changes[i].Line = pos.Line
+		}
+	}
+}
+
+// rootDeclarationKey returns the part of a resource address that identifies
+// its declaration in the root module, ex. module.vpc for
+// module.vpc.aws_subnet.private[0] and aws_instance.web for
+// aws_instance.web["a.b"].
+func rootDeclarationKey(address string) string {
+	// Split on the dots outside of instance keys.
+	var parts []string
+	start, depth, quoted := 0, 0, false
+	for i, r := range address {
+		switch {
+		case r == '"' && (i == 0 || address[i-1] != '\\'):
+			quoted = !quoted
+		case quoted:
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case r == '.' && depth == 0:
+			parts = append(parts, address[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, address[start:])
+	for i, part := range parts {
+		if idx := strings.Index(part, "["); idx >= 0 {
+			parts[i] = part[:idx]
+		}
+	}
+
+	switch {
+	case parts[0] == "module" && len(parts) >= 2:
+		return "module." + parts[1]
+	case parts[0] == "data" && len(parts) >= 3:
+		return strings.Join(parts[:3], ".")
+	case len(parts) >= 2:
+		return strings.Join(parts[:2], ".")
+	}
+	return address
+}
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestLocateResourceChanges(t *testing.T) {
+	dir := t.TempDir()
+	Ok(t, os.WriteFile(filepath.Join(dir, "main.tf"), []byte(`terraform {
+  required_version = ">= 1.0"
+}
+
+resource "aws_instance" "web" {
+  count = 2
+}
+
+data "aws_ami" "ubuntu" {}
+`), 0600))
+	Ok(t, os.WriteFile(filepath.Join(dir, "modules.tf"), []byte(`module "vpc" {
+  source = "./vpc"
+}
+`), 0600))
+	Ok(t, os.WriteFile(filepath.Join(dir, "broken.tf"), []byte(`resource "aws_s3_bucket" "logs" {`), 0600))
+
+	changes := []models.ResourceChange{
+		{Address: "aws_instance.web[1]"},
+		{Address: "data.aws_ami.ubuntu"},
+		{Address: `module.vpc["a.b"].aws_subnet.private[0]`},
+		{Address: "aws_s3_bucket.logs"},
+		{Address: "aws_iam_role.ci"},
+	}
+	locateResourceChanges(dir, changes)
+
+	Equals(t, []models.ResourceChange{
+		{Address: "aws_instance.web[1]", File: "main.tf", Line: 5},
+		{Address: "data.aws_ami.ubuntu", File: "main.tf", Line: 9},
+		{Address: `module.vpc["a.b"].aws_subnet.private[0]`, File: "modules.tf", Line: 1},
+		{Address: "aws_s3_bucket.logs"},
+		{Address: "aws_iam_role.ci"},
+	}, changes)
+}
+
+func TestRootDeclarationKey(t *testing.T) {
+	cases := map[string]string{
+		"aws_instance.web":                           "aws_instance.web",
+		`aws_instance.web["a.b"]`:                    "aws_instance.web",
+		"data.aws_ami.ubuntu":                        "data.aws_ami.ubuntu",
+		"module.vpc.aws_subnet.private[0]":           "module.vpc",
+		`module.vpc["x.y"].module.subnets.aws_vpc.a`: "module.vpc",
+	}
+	for address, exp := range cases {
+		t.Run(address, func(t *testing.T) {
+			Equals(t, exp, rootDeclarationKey(address))
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package events
 
 import (
+	"time"
+
+	"github.com/runatlantis/atlantis/server/audit"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/vcs"
 )
@@ -9,11 +12,13 @@ func NewUnlockCommandRunner(
 	deleteLockCommand DeleteLockCommand,
 	vcsClient vcs.Client,
 	SilenceNoProjects bool,
+	auditSink audit.Sink,
 ) *UnlockCommandRunner {
 	return &UnlockCommandRunner{
 		deleteLockCommand: deleteLockCommand,
 		vcsClient:         vcsClient,
 		SilenceNoProjects: SilenceNoProjects,
+		auditSink:         auditSink,
 	}
 }
 
@@ -23,6 +28,7 @@ type UnlockCommandRunner struct {
 	// SilenceNoProjects is whether Atlantis should respond to PRs if no projects
 	// are found
 	SilenceNoProjects bool
+	auditSink         audit.Sink
 }
 
 func (u *UnlockCommandRunner) Run(
@@ -32,12 +38,18 @@ func (u *UnlockCommandRunner) Run(
 	baseRepo := ctx.Pull.BaseRepo
 	pullNum := ctx.Pull.Num
 
+	start := time.Now()
 	vcsMessage := "All Atlantis locks for this PR have been unlocked and plans discarded"
 	numLocks, err := u.deleteLockCommand.DeleteLocksByPull(baseRepo.FullName, pullNum)
 	if err != nil {
 		vcsMessage = "Failed to delete PR locks"
 		ctx.Log.Err("failed to delete locks by pull %s", err.Error())
 	}
+	ctx.RunResults = append(ctx.RunResults, command.RunResult{
+		Name:   command.Unlock,
+		Result: command.Result{Error: err},
+	})
+	audit.Record(ctx.Log, u.auditSink, audit.NewCommandEvents(audit.CommentSource, ctx, command.Unlock, start)...)
 
 	// if there are no locks to delete, no errors, and SilenceNoProjects is enabled, don't comment
 	if err == nil && numLocks == 0 && u.SilenceNoProjects {
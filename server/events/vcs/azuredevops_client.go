@@ -170,6 +170,34 @@ func (g *AzureDevopsClient) PullIsApproved(repo models.Repo, pull models.PullReq
 	return approvalStatus, nil
 }
 
+// GetApprovers returns the unique names of the reviewers that voted to approve
+// the pull request.
+func (g *AzureDevopsClient) GetApprovers(repo models.Repo, pull models.PullRequest) ([]string, error) {
+	owner, project, repoName := SplitAzureDevopsRepoFullName(repo.FullName)
+
+	opts := azuredevops.PullRequestGetOptions{
+		IncludeWorkItemRefs: true,
+	}
+	adPull, _, err := g.Client.PullRequests.GetWithRepo(g.ctx, owner, project, repoName, pull.Num, &opts)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting pull request")
+	}
+
+	var approvers []string
+	for _, review := range adPull.Reviewers {
+		if review == nil {
+			continue
+		}
+		if review.IdentityRef.GetUniqueName() == adPull.GetCreatedBy().GetUniqueName() {
+			continue
+		}
+		if review.GetVote() == azuredevops.VoteApproved || review.GetVote() == azuredevops.VoteApprovedWithSuggestions {
+			approvers = append(approvers, review.IdentityRef.GetUniqueName())
+		}
+	}
+	return approvers, nil
+}
+
 func (g *AzureDevopsClient) DiscardReviews(repo models.Repo, pull models.PullRequest) error {
 	// TODO implement
 	return nil
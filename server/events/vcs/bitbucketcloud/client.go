@@ -137,6 +137,32 @@ func (b *Client) PullIsApproved(repo models.Repo, pull models.PullRequest) (appr
 	return approvalStatus, nil
 }
 
+// GetApprovers returns the account IDs of the participants that approved the
+// pull request.
+func (b *Client) GetApprovers(repo models.Repo, pull models.PullRequest) ([]string, error) {
+	path := fmt.Sprintf("%s/2.0/repositories/%s/pullrequests/%d", b.BaseURL, repo.FullName, pull.Num)
+	resp, err := b.makeRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var pullResp PullRequest
+	if err := json.Unmarshal(resp, &pullResp); err != nil {
+		return nil, errors.Wrapf(err, "Could not parse response %q", string(resp))
+	}
+	if err := validator.New().Struct(pullResp); err != nil {
+		return nil, errors.Wrapf(err, "API response %q was missing fields", string(resp))
+	}
+	authorUUID := *pullResp.Author.UUID
+	var approvers []string
+	for _, participant := range pullResp.Participants {
+		if !*participant.Approved || *participant.User.UUID == authorUUID || participant.User.AccountID == nil {
+			continue
+		}
+		approvers = append(approvers, *participant.User.AccountID)
+	}
+	return approvers, nil
+}
+
 // PullIsMergeable returns true if the merge request has no conflicts and can be merged.
 func (b *Client) PullIsMergeable(repo models.Repo, pull models.PullRequest, vcsstatusname string) (bool, error) {
 	nextPageURL := fmt.Sprintf("%s/2.0/repositories/%s/pullrequests/%d/diffstat", b.BaseURL, repo.FullName, pull.Num)
@@ -196,6 +196,35 @@ func (b *Client) PullIsApproved(repo models.Repo, pull models.PullRequest) (appr
 	return approvalStatus, nil
 }
 
+// GetApprovers returns the usernames of the reviewers that approved the pull
+// request.
+func (b *Client) GetApprovers(repo models.Repo, pull models.PullRequest) ([]string, error) {
+	projectKey, err := b.GetProjectKey(repo.Name, repo.SanitizedCloneURL)
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/pull-requests/%d", b.BaseURL, projectKey, repo.Name, pull.Num)
+	resp, err := b.makeRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var pullResp PullRequest
+	if err := json.Unmarshal(resp, &pullResp); err != nil {
+		return nil, errors.Wrapf(err, "Could not parse response %q", string(resp))
+	}
+	if err := validator.New().Struct(pullResp); err != nil {
+		return nil, errors.Wrapf(err, "API response %q was missing fields", string(resp))
+	}
+	var approvers []string
+	for _, reviewer := range pullResp.Reviewers {
+		if !*reviewer.Approved || reviewer.User == nil || reviewer.User.Username == nil || *reviewer.User.Username == pull.Author {
+			continue
+		}
+		approvers = append(approvers, *reviewer.User.Username)
+	}
+	return approvers, nil
+}
+
 func (b *Client) DiscardReviews(repo models.Repo, pull models.PullRequest) error {
 	// TODO implement
 	return nil
@@ -346,9 +375,36 @@ func (b *Client) makeRequest(method string, path string, reqBody io.Reader) ([]b
 	return respBody, nil
 }
 
-// GetTeamNamesForUser returns the names of the teams or groups that the user belongs to (in the organization the repository belongs to).
+// GetTeamNamesForUser returns the names of the Bitbucket groups that the user
+// belongs to. Groups are server-wide so they don't depend on the repository.
 func (b *Client) GetTeamNamesForUser(repo models.Repo, user models.User) ([]string, error) {
-	return nil, nil
+	var teamNames []string
+	nextPageStart := 0
+	baseURL := fmt.Sprintf("%s/rest/api/1.0/admin/users/more-members?context=%s",
+		b.BaseURL, url.QueryEscape(user.Username))
+	// We'll only loop 1000 times as a safety measure.
+	maxLoops := 1000
+	for i := 0; i < maxLoops; i++ {
+		resp, err := b.makeRequest("GET", fmt.Sprintf("%s&start=%d", baseURL, nextPageStart), nil)
+		if err != nil {
+			return nil, err
+		}
+		var groups Groups
+		if err := json.Unmarshal(resp, &groups); err != nil {
+			return nil, errors.Wrapf(err, "Could not parse response %q", string(resp))
+		}
+		if err := validator.New().Struct(groups); err != nil {
+			return nil, errors.Wrapf(err, "API response %q was missing fields", string(resp))
+		}
+		for _, group := range groups.Values {
+			teamNames = append(teamNames, *group.Name)
+		}
+		if *groups.IsLastPage {
+			break
+		}
+		nextPageStart = *groups.NextPageStart
+	}
+	return teamNames, nil
 }
 
 func (b *Client) SupportsSingleFileDownload(repo models.Repo) bool {
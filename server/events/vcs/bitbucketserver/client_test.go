@@ -136,6 +136,28 @@ func TestClient_GetModifiedFilesPagination(t *testing.T) {
 	Equals(t, []string{"file1.txt", "file2.txt", "file3.txt"}, files)
 }
 
+func TestClient_GetTeamNamesForUser(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/rest/api/1.0/admin/users/more-members?context=jane.doe&start=0":
+			w.Write([]byte(`{"values": [{"name": "platform"}, {"name": "security"}], "isLastPage": false, "nextPageStart": 2}`)) // nolint: errcheck
+		case "/rest/api/1.0/admin/users/more-members?context=jane.doe&start=2":
+			w.Write([]byte(`{"values": [{"name": "stash-users"}], "isLastPage": true}`)) // nolint: errcheck
+		default:
+			t.Errorf("got unexpected request at %q", r.RequestURI)
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+
+	client, err := bitbucketserver.NewClient(http.DefaultClient, "user", "pass", testServer.URL, "runatlantis.io")
+	Ok(t, err)
+
+	teams, err := client.GetTeamNamesForUser(models.Repo{FullName: "owner/repo"}, models.User{Username: "jane.doe"})
+	Ok(t, err)
+	Equals(t, []string{"platform", "security", "stash-users"}, teams)
+}
+
 // Test that we use the correct version parameter in our call to merge the pull
 // request.
 func TestClient_MergePull(t *testing.T) {
@@ -31,7 +31,8 @@ type PullRequest struct {
 	ToRef     *Ref    `json:"toRef,omitempty" validate:"required"`
 	State     *string `json:"state,omitempty" validate:"required"`
 	Reviewers []struct {
-		Approved *bool `json:"approved,omitempty" validate:"required"`
+		Approved *bool  `json:"approved,omitempty" validate:"required"`
+		User     *Actor `json:"user,omitempty"`
 	} `json:"reviewers,omitempty" validate:"required"`
 }
 
@@ -72,6 +73,14 @@ type Changes struct {
 	IsLastPage    *bool `json:"isLastPage,omitempty" validate:"required"`
 }
 
+type Groups struct {
+	Values []struct {
+		Name *string `json:"name,omitempty" validate:"required"`
+	} `json:"values,omitempty" validate:"required,dive"`
+	NextPageStart *int  `json:"nextPageStart,omitempty"`
+	IsLastPage    *bool `json:"isLastPage,omitempty" validate:"required"`
+}
+
 type MergeStatus struct {
 	CanMerge   *bool `json:"canMerge,omitempty" validate:"required"`
 	Conflicted *bool `json:"conflicted,omitempty" validate:"required"`
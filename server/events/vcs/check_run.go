@@ -0,0 +1,67 @@
+package vcs
+
+import "github.com/runatlantis/atlantis/server/events/models"
+
+// CheckRun is a GitHub check run reporting the status of a command. Check
+// runs are identified by their name on the pull request's head commit.
+type CheckRun struct {
+	// Name is shown in the list of checks, ex. atlantis/plan: project1.
+	Name string
+	// Status is mapped to the check run's status and conclusion.
+	Status models.CommitStatus
+	// Title is the one line description shown under the check's name.
+	Title string
+	// Summary is the markdown shown at the top of the check's page.
+	Summary string
+	// Text is the markdown shown under the summary, ex. the plan output. It's
+	// truncated if it's too long.
+	Text string
+	// DetailsURL links to the command's output.
+	DetailsURL string
+	// ExternalID is sent back in check_run events so they can be mapped to
+	// the project the check run is for.
+	ExternalID string
+	// Annotations point out things of interest, ex. the resources a plan
+	// changes.
+	Annotations []CheckRunAnnotation
+	// Actions are buttons shown on the check that send a check_run
+	// requested_action event when clicked.
+	Actions []CheckRunAction
+}
+
+// CheckRunAnnotation is a message attached to lines of a file in a check run.
+// GitHub only shows annotations on the pull request's diff if they point at
+// lines it changes, the others are listed on the check run.
+type CheckRunAnnotation struct {
+	// Path is the path of the file relative to the repo root.
+	Path string
+	// StartLine and EndLine are the lines annotated, starting at 1. EndLine
+	// defaults to StartLine.
+	StartLine int
+	EndLine   int
+	// Level is one of notice, warning or failure.
+	Level   string
+	Title   string
+	Message string
+}
+
+// CheckRunAction is a button shown on a check run.
+type CheckRunAction struct {
+	// Label is the text of the button. At most 20 characters.
+	Label string
+	// Description explains what the button does. At most 40 characters.
+	Description string
+	// Identifier is sent back in the requested_action event. At most 20
+	// characters.
+	Identifier string
+}
+
+//go:generate pegomock generate --package mocks -o mocks/mock_github_check_run_updater.go GithubCheckRunUpdater
+
+// GithubCheckRunUpdater creates and updates GitHub check runs. Only GitHub
+// Apps can use the Checks API.
+type GithubCheckRunUpdater interface {
+	// UpdateCheckRun creates the check run on the head commit of pull or
+	// updates it if one with the same name already exists.
+	UpdateCheckRun(repo models.Repo, pull models.PullRequest, checkRun CheckRun) error
+}
@@ -29,6 +29,10 @@ type Client interface {
 	ReactToComment(repo models.Repo, pullNum int, commentID int64, reaction string) error
 	HidePrevCommandComments(repo models.Repo, pullNum int, command string) error
 	PullIsApproved(repo models.Repo, pull models.PullRequest) (models.ApprovalStatus, error)
+	// GetApprovers returns the usernames of the users whose approval of the
+	// pull request currently counts. Approvals from the pull request's
+	// author aren't included.
+	GetApprovers(repo models.Repo, pull models.PullRequest) ([]string, error)
 	PullIsMergeable(repo models.Repo, pull models.PullRequest, vcsstatusname string) (bool, error)
 	// UpdateStatus updates the commit status to state for pull. src is the
 	// source of this status. This should be relatively static across runs,
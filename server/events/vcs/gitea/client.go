@@ -0,0 +1,493 @@
+package gitea
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	validator "github.com/go-playground/validator/v10"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs/common"
+)
+
+// maxCommentLength is the maximum number of chars we'll put in a single
+// comment. Gitea stores comments in a LONGTEXT column by default but large
+// comments render very slowly so we split them like the other clients do.
+const maxCommentLength = 65536
+
+// pageSize is the number of items requested per page for paginated endpoints.
+const pageSize = 50
+
+type Client struct {
+	HTTPClient  *http.Client
+	Username    string
+	Token       string
+	BaseURL     string
+	AtlantisURL string
+}
+
+// NewClient builds a Gitea client. Returns an error if the baseURL is
+// malformed. httpClient is the client to use to make the requests, username
+// is the user Atlantis runs as and token is a personal access token for that
+// user. baseURL is the URL of the Gitea or Forgejo instance, ex.
+// https://gitea.corp.com. Don't include the '/api/v1' path. atlantisURL is the
+// URL for Atlantis that will be linked to from the commit statuses.
+func NewClient(httpClient *http.Client, username string, token string, baseURL string, atlantisURL string) (*Client, error) {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	parsedURL, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", baseURL)
+	}
+	if parsedURL.Scheme == "" {
+		return nil, fmt.Errorf("must have 'http://' or 'https://' in base url %q", baseURL)
+	}
+	return &Client{
+		HTTPClient:  httpClient,
+		Username:    username,
+		Token:       token,
+		BaseURL:     strings.TrimRight(parsedURL.String(), "/"),
+		AtlantisURL: atlantisURL,
+	}, nil
+}
+
+// GetModifiedFiles returns the names of files that were modified in the pull
+// request relative to the repo root, e.g. parent/child/file.txt.
+func (c *Client) GetModifiedFiles(repo models.Repo, pull models.PullRequest) ([]string, error) {
+	var files []string
+	// We'll only loop 1000 times as a safety measure.
+	maxLoops := 1000
+	for page := 1; page <= maxLoops; page++ {
+		path := fmt.Sprintf("%s/pulls/%d/files?page=%d&limit=%d", c.repoURL(repo.FullName), pull.Num, page, pageSize)
+		resp, err := c.makeRequest("GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+		var changed []ChangedFile
+		if err := json.Unmarshal(resp, &changed); err != nil {
+			return nil, errors.Wrapf(err, "Could not parse response %q", string(resp))
+		}
+		for _, f := range changed {
+			if err := validator.New().Struct(f); err != nil {
+				return nil, errors.Wrapf(err, "API response %q was missing fields", string(resp))
+			}
+			files = append(files, *f.Filename)
+			// If the file was renamed, we'll want to run plan in the directory
+			// it was moved from as well.
+			if f.PreviousFilename != nil && *f.PreviousFilename != "" {
+				files = append(files, *f.PreviousFilename)
+			}
+		}
+		if len(changed) < pageSize {
+			break
+		}
+	}
+
+	// Now ensure all files are unique.
+	hash := make(map[string]bool)
+	var unique []string
+	for _, f := range files {
+		if !hash[f] {
+			unique = append(unique, f)
+			hash[f] = true
+		}
+	}
+	return unique, nil
+}
+
+// CreateComment creates a comment on the pull request. If the comment is too
+// long it is split into multiple comments.
+func (c *Client) CreateComment(repo models.Repo, pullNum int, comment string, command string) error {
+	sepEnd := "\n```\n**Warning**: Output length greater than max comment size. Continued in next comment."
+	sepStart := "Continued from previous comment.\n```diff\n"
+	comments := common.SplitComment(comment, maxCommentLength, sepEnd, sepStart)
+	for _, comment := range comments {
+		bodyBytes, err := json.Marshal(map[string]string{"body": comment})
+		if err != nil {
+			return errors.Wrap(err, "json encoding")
+		}
+		// Gitea treats pull requests as issues so comments are created through
+		// the issue API.
+		path := fmt.Sprintf("%s/issues/%d/comments", c.repoURL(repo.FullName), pullNum)
+		if _, err := c.makeRequest("POST", path, bytes.NewBuffer(bodyBytes)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReactToComment adds a reaction to a comment.
+func (c *Client) ReactToComment(repo models.Repo, pullNum int, commentID int64, reaction string) error { // nolint revive
+	if commentID <= 0 {
+		return nil
+	}
+	bodyBytes, err := json.Marshal(map[string]string{"content": reaction})
+	if err != nil {
+		return errors.Wrap(err, "json encoding")
+	}
+	path := fmt.Sprintf("%s/issues/comments/%d/reactions", c.repoURL(repo.FullName), commentID)
+	_, err = c.makeRequest("POST", path, bytes.NewBuffer(bodyBytes))
+	return err
+}
+
+// HidePrevCommandComments is a no-op because Gitea has no way to minimize
+// comments.
+func (c *Client) HidePrevCommandComments(repo models.Repo, pullNum int, command string) error {
+	return nil
+}
+
+// PullIsApproved returns true if the pull request was approved by someone
+// other than its author and that approval hasn't been dismissed.
+func (c *Client) PullIsApproved(repo models.Repo, pull models.PullRequest) (approvalStatus models.ApprovalStatus, err error) {
+	reviews, err := c.getReviews(repo, pull)
+	if err != nil {
+		return approvalStatus, err
+	}
+	for _, review := range reviews {
+		if *review.State != "APPROVED" || review.Dismissed || review.Stale {
+			continue
+		}
+		// Like Bitbucket, don't count an approval from the pull request author.
+		if *review.User.Login == pull.Author {
+			continue
+		}
+		approvalStatus = models.ApprovalStatus{
+			IsApproved: true,
+			ApprovedBy: *review.User.Login,
+		}
+		if review.Submitted != nil {
+			if t, err := time.Parse(time.RFC3339, *review.Submitted); err == nil {
+				approvalStatus.Date = t
+			}
+		}
+		return approvalStatus, nil
+	}
+	return approvalStatus, nil
+}
+
+// GetApprovers returns the users whose latest review of the pull request is an
+// approval that hasn't been dismissed or made stale by new commits.
+func (c *Client) GetApprovers(repo models.Repo, pull models.PullRequest) ([]string, error) {
+	reviews, err := c.getReviews(repo, pull)
+	if err != nil {
+		return nil, err
+	}
+	var users []string
+	approved := make(map[string]bool)
+	for _, review := range reviews {
+		if *review.State == "COMMENT" || *review.State == "PENDING" {
+			continue
+		}
+		login := *review.User.Login
+		if login == pull.Author {
+			continue
+		}
+		if _, ok := approved[login]; !ok {
+			users = append(users, login)
+		}
+		approved[login] = *review.State == "APPROVED" && !review.Dismissed && !review.Stale
+	}
+	var approvers []string
+	for _, user := range users {
+		if approved[user] {
+			approvers = append(approvers, user)
+		}
+	}
+	return approvers, nil
+}
+
+// PullIsMergeable returns true if the pull request has no conflicts and every
+// commit status other than our own apply status is passing.
+func (c *Client) PullIsMergeable(repo models.Repo, pull models.PullRequest, vcsstatusname string) (bool, error) {
+	giteaPull, err := c.GetPullRequest(repo, pull.Num)
+	if err != nil {
+		return false, err
+	}
+	if !giteaPull.Mergeable {
+		return false, nil
+	}
+
+	path := fmt.Sprintf("%s/commits/%s/status", c.repoURL(repo.FullName), *giteaPull.Head.SHA)
+	resp, err := c.makeRequest("GET", path, nil)
+	if err != nil {
+		return false, err
+	}
+	var combined CombinedStatus
+	if err := json.Unmarshal(resp, &combined); err != nil {
+		return false, errors.Wrapf(err, "Could not parse response %q", string(resp))
+	}
+	for _, status := range combined.Statuses {
+		if status.Context == nil || status.State == nil {
+			continue
+		}
+		// Ignore any commit statuses with 'atlantis/apply' as prefix since
+		// those are pending until we apply.
+		if strings.HasPrefix(*status.Context, fmt.Sprintf("%s/%s", vcsstatusname, command.Apply.String())) {
+			continue
+		}
+		if *status.State != "success" && *status.State != "warning" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// UpdateStatus updates the status of a commit.
+func (c *Client) UpdateStatus(repo models.Repo, pull models.PullRequest, state models.CommitStatus, src string, description string, url string) error {
+	giteaState := "failure"
+	switch state {
+	case models.PendingCommitStatus:
+		giteaState = "pending"
+	case models.SuccessCommitStatus:
+		giteaState = "success"
+	case models.FailedCommitStatus:
+		giteaState = "failure"
+	}
+	if url == "" {
+		url = c.AtlantisURL
+	}
+
+	bodyBytes, err := json.Marshal(map[string]string{
+		"context":     src,
+		"target_url":  url,
+		"state":       giteaState,
+		"description": description,
+	})
+	if err != nil {
+		return errors.Wrap(err, "json encoding")
+	}
+	path := fmt.Sprintf("%s/statuses/%s", c.repoURL(repo.FullName), pull.HeadCommit)
+	_, err = c.makeRequest("POST", path, bytes.NewBuffer(bodyBytes))
+	return err
+}
+
+// DiscardReviews dismisses all approving reviews on the pull request.
+func (c *Client) DiscardReviews(repo models.Repo, pull models.PullRequest) error {
+	reviews, err := c.getReviews(repo, pull)
+	if err != nil {
+		return err
+	}
+	bodyBytes, err := json.Marshal(map[string]string{"message": "Dismissed by Atlantis after a new plan"})
+	if err != nil {
+		return errors.Wrap(err, "json encoding")
+	}
+	for _, review := range reviews {
+		if *review.State != "APPROVED" || review.Dismissed {
+			continue
+		}
+		path := fmt.Sprintf("%s/pulls/%d/reviews/%d/dismissals", c.repoURL(repo.FullName), pull.Num, *review.ID)
+		if _, err := c.makeRequest("POST", path, bytes.NewBuffer(bodyBytes)); err != nil {
+			return errors.Wrapf(err, "dismissing review %d", *review.ID)
+		}
+	}
+	return nil
+}
+
+// MergePull merges the pull request.
+func (c *Client) MergePull(pull models.PullRequest, pullOptions models.PullRequestOptions) error {
+	bodyBytes, err := json.Marshal(map[string]interface{}{
+		"Do":                        "merge",
+		"MergeMessageField":         common.AutomergeCommitMsg(pull.Num),
+		"delete_branch_after_merge": pullOptions.DeleteSourceBranchOnMerge,
+	})
+	if err != nil {
+		return errors.Wrap(err, "json encoding")
+	}
+	path := fmt.Sprintf("%s/pulls/%d/merge", c.repoURL(pull.BaseRepo.FullName), pull.Num)
+	_, err = c.makeRequest("POST", path, bytes.NewBuffer(bodyBytes))
+	return err
+}
+
+// MarkdownPullLink specifies the string used in a pull request comment to reference another pull request.
+func (c *Client) MarkdownPullLink(pull models.PullRequest) (string, error) {
+	return fmt.Sprintf("#%d", pull.Num), nil
+}
+
+// GetTeamNamesForUser returns the names of the teams in the repo owner's
+// organization that the user belongs to. If the repo is owned by a user
+// rather than an organization there are no teams.
+func (c *Client) GetTeamNamesForUser(repo models.Repo, user models.User) ([]string, error) {
+	var teamNames []string
+	// We'll only loop 1000 times as a safety measure.
+	maxLoops := 1000
+	for page := 1; page <= maxLoops; page++ {
+		path := fmt.Sprintf("%s/api/v1/orgs/%s/teams?page=%d&limit=%d", c.BaseURL, url.PathEscape(repo.Owner), page, pageSize)
+		resp, status, err := c.makeRequestWithStatus("GET", path, nil)
+		if status == http.StatusNotFound {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var teams []Team
+		if err := json.Unmarshal(resp, &teams); err != nil {
+			return nil, errors.Wrapf(err, "Could not parse response %q", string(resp))
+		}
+		for _, team := range teams {
+			if err := validator.New().Struct(team); err != nil {
+				return nil, errors.Wrapf(err, "API response %q was missing fields", string(resp))
+			}
+			memberPath := fmt.Sprintf("%s/api/v1/teams/%d/members/%s", c.BaseURL, *team.ID, url.PathEscape(user.Username))
+			_, status, err := c.makeRequestWithStatus("GET", memberPath, nil)
+			if status == http.StatusNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			teamNames = append(teamNames, *team.Name)
+		}
+		if len(teams) < pageSize {
+			break
+		}
+	}
+	return teamNames, nil
+}
+
+// GetFileContent a repository file content from VCS (which support fetch a single file from repository)
+// The first return value indicates whether the repo contains a file or not
+// if BaseRepo had a file, its content will placed on the second return value
+func (c *Client) GetFileContent(pull models.PullRequest, fileName string) (bool, []byte, error) {
+	path := fmt.Sprintf("%s/raw/%s?ref=%s", c.repoURL(pull.BaseRepo.FullName), escapePath(fileName), url.QueryEscape(pull.HeadBranch))
+	resp, status, err := c.makeRequestWithStatus("GET", path, nil)
+	if status == http.StatusNotFound {
+		return false, []byte{}, nil
+	}
+	if err != nil {
+		return true, []byte{}, err
+	}
+	return true, resp, nil
+}
+
+func (c *Client) SupportsSingleFileDownload(models.Repo) bool {
+	return true
+}
+
+// GetCloneURL returns the clone URL of the repo.
+func (c *Client) GetCloneURL(VCSHostType models.VCSHostType, repo string) (string, error) {
+	resp, err := c.makeRequest("GET", c.repoURL(repo), nil)
+	if err != nil {
+		return "", err
+	}
+	var repository Repository
+	if err := json.Unmarshal(resp, &repository); err != nil {
+		return "", errors.Wrapf(err, "Could not parse response %q", string(resp))
+	}
+	if err := validator.New().Struct(repository); err != nil {
+		return "", errors.Wrapf(err, "API response %q was missing fields", string(resp))
+	}
+	return *repository.CloneURL, nil
+}
+
+// GetPullRequest returns the pull request.
+func (c *Client) GetPullRequest(repo models.Repo, num int) (*PullRequest, error) {
+	path := fmt.Sprintf("%s/pulls/%d", c.repoURL(repo.FullName), num)
+	resp, err := c.makeRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	var pull PullRequest
+	if err := json.Unmarshal(resp, &pull); err != nil {
+		return nil, errors.Wrapf(err, "Could not parse response %q", string(resp))
+	}
+	if err := validator.New().Struct(pull); err != nil {
+		return nil, errors.Wrapf(err, "API response %q was missing fields", string(resp))
+	}
+	return &pull, nil
+}
+
+func (c *Client) getReviews(repo models.Repo, pull models.PullRequest) ([]Review, error) {
+	var reviews []Review
+	// We'll only loop 1000 times as a safety measure.
+	maxLoops := 1000
+	for page := 1; page <= maxLoops; page++ {
+		path := fmt.Sprintf("%s/pulls/%d/reviews?page=%d&limit=%d", c.repoURL(repo.FullName), pull.Num, page, pageSize)
+		resp, err := c.makeRequest("GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+		var pageReviews []Review
+		if err := json.Unmarshal(resp, &pageReviews); err != nil {
+			return nil, errors.Wrapf(err, "Could not parse response %q", string(resp))
+		}
+		for _, r := range pageReviews {
+			if err := validator.New().Struct(r); err != nil {
+				return nil, errors.Wrapf(err, "API response %q was missing fields", string(resp))
+			}
+		}
+		reviews = append(reviews, pageReviews...)
+		if len(pageReviews) < pageSize {
+			break
+		}
+	}
+	return reviews, nil
+}
+
+// repoURL returns the API URL for the repo, ex.
+// https://gitea.corp.com/api/v1/repos/owner/repo.
+func (c *Client) repoURL(repoFullName string) string {
+	return fmt.Sprintf("%s/api/v1/repos/%s", c.BaseURL, escapePath(repoFullName))
+}
+
+// escapePath escapes each segment of a slash separated path.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// prepRequest adds auth and necessary headers.
+func (c *Client) prepRequest(method string, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Authorization", "token "+c.Token)
+	req.Header.Add("Accept", "application/json")
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+func (c *Client) makeRequest(method string, path string, reqBody io.Reader) ([]byte, error) {
+	resp, _, err := c.makeRequestWithStatus(method, path, reqBody)
+	return resp, err
+}
+
+// makeRequestWithStatus makes the request and also returns the response status
+// code so callers can treat statuses like 404 as non-errors.
+func (c *Client) makeRequestWithStatus(method string, path string, reqBody io.Reader) ([]byte, int, error) {
+	req, err := c.prepRequest(method, path, reqBody)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "constructing request")
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	requestStr := fmt.Sprintf("%s %s", method, path)
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+	default:
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, resp.StatusCode, fmt.Errorf("making request %q unexpected status code: %d, body: %s", requestStr, resp.StatusCode, string(respBody))
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, resp.StatusCode, errors.Wrapf(err, "reading response from request %q", requestStr)
+	}
+	return respBody, resp.StatusCode, nil
+}
@@ -0,0 +1,273 @@
+package gitea_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+var repo = models.Repo{
+	FullName: "owner/repo",
+	Owner:    "owner",
+	Name:     "repo",
+	VCSHost: models.VCSHost{
+		Type:     models.Gitea,
+		Hostname: "gitea.example.com",
+	},
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	contents, err := os.ReadFile(filepath.Join("testdata", name))
+	Ok(t, err)
+	return contents
+}
+
+func newClient(t *testing.T, serverURL string) *gitea.Client {
+	t.Helper()
+	client, err := gitea.NewClient(http.DefaultClient, "atlantis", "token", serverURL, "https://atlantis.example.com")
+	Ok(t, err)
+	return client
+}
+
+func TestNewClient_RequiresScheme(t *testing.T) {
+	_, err := gitea.NewClient(nil, "user", "token", "gitea.example.com", "")
+	ErrEquals(t, `must have 'http://' or 'https://' in base url "gitea.example.com"`, err)
+}
+
+// Should follow pagination and include the previous name of renamed files.
+func TestClient_GetModifiedFiles(t *testing.T) {
+	files := readFixture(t, "pull-files.json")
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Equals(t, "token token", r.Header.Get("Authorization"))
+		switch r.RequestURI {
+		case "/api/v1/repos/owner/repo/pulls/1/files?page=1&limit=50":
+			w.Write(files) // nolint: errcheck
+		default:
+			t.Errorf("got unexpected request at %q", r.RequestURI)
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+
+	modified, err := newClient(t, testServer.URL).GetModifiedFiles(repo, models.PullRequest{Num: 1})
+	Ok(t, err)
+	Equals(t, []string{"main.tf", "modules/new/main.tf", "modules/old/main.tf"}, modified)
+}
+
+func TestClient_CreateComment(t *testing.T) {
+	var body map[string]string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Equals(t, "POST", r.Method)
+		Equals(t, "/api/v1/repos/owner/repo/issues/1/comments", r.RequestURI)
+		Ok(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("{}")) // nolint: errcheck
+	}))
+	defer testServer.Close()
+
+	err := newClient(t, testServer.URL).CreateComment(repo, 1, "comment", "plan")
+	Ok(t, err)
+	Equals(t, "comment", body["body"])
+}
+
+// Approvals from the author, stale approvals and dismissed approvals shouldn't
+// count.
+func TestClient_PullIsApproved(t *testing.T) {
+	reviews := readFixture(t, "pull-reviews.json")
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/api/v1/repos/owner/repo/pulls/1/reviews?page=1&limit=50":
+			w.Write(reviews) // nolint: errcheck
+		default:
+			t.Errorf("got unexpected request at %q", r.RequestURI)
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+
+	status, err := newClient(t, testServer.URL).PullIsApproved(repo, models.PullRequest{Num: 1, Author: "author"})
+	Ok(t, err)
+	Assert(t, status.IsApproved, "exp approved")
+	Equals(t, "reviewer", status.ApprovedBy)
+	Equals(t, 2023, status.Date.Year())
+}
+
+func TestClient_PullIsApproved_OnlyAuthor(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":10,"user":{"login":"author"},"state":"APPROVED"}]`)) // nolint: errcheck
+	}))
+	defer testServer.Close()
+
+	status, err := newClient(t, testServer.URL).PullIsApproved(repo, models.PullRequest{Num: 1, Author: "author"})
+	Ok(t, err)
+	Assert(t, !status.IsApproved, "exp not approved")
+}
+
+func TestClient_GetApprovers(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"id":1,"user":{"login":"alice"},"state":"APPROVED"},
+			{"id":2,"user":{"login":"bob"},"state":"APPROVED","dismissed":true},
+			{"id":3,"user":{"login":"carol"},"state":"APPROVED","stale":true},
+			{"id":4,"user":{"login":"dave"},"state":"APPROVED"},
+			{"id":5,"user":{"login":"dave"},"state":"REQUEST_CHANGES"},
+			{"id":6,"user":{"login":"author"},"state":"APPROVED"},
+			{"id":7,"user":{"login":"alice"},"state":"COMMENT"}
+		]`)) // nolint: errcheck
+	}))
+	defer testServer.Close()
+
+	approvers, err := newClient(t, testServer.URL).GetApprovers(repo, models.PullRequest{Num: 1, Author: "author"})
+	Ok(t, err)
+	Equals(t, []string{"alice"}, approvers)
+}
+
+func TestClient_PullIsMergeable(t *testing.T) {
+	pull := readFixture(t, "pull-request.json")
+	cases := []struct {
+		description string
+		statuses    string
+		exp         bool
+	}{
+		{
+			"all passing",
+			`{"state":"success","statuses":[{"context":"ci/build","status":"success"}]}`,
+			true,
+		},
+		{
+			"atlantis apply pending is ignored",
+			`{"state":"pending","statuses":[{"context":"ci/build","status":"success"},{"context":"atlantis/apply","status":"pending"}]}`,
+			true,
+		},
+		{
+			"other check failing",
+			`{"state":"failure","statuses":[{"context":"ci/build","status":"failure"}]}`,
+			false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.RequestURI {
+				case "/api/v1/repos/owner/repo/pulls/1":
+					w.Write(pull) // nolint: errcheck
+				case "/api/v1/repos/owner/repo/commits/a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0/status":
+					w.Write([]byte(c.statuses)) // nolint: errcheck
+				default:
+					t.Errorf("got unexpected request at %q", r.RequestURI)
+					http.Error(w, "not found", http.StatusNotFound)
+				}
+			}))
+			defer testServer.Close()
+
+			mergeable, err := newClient(t, testServer.URL).PullIsMergeable(repo, models.PullRequest{Num: 1}, "atlantis")
+			Ok(t, err)
+			Equals(t, c.exp, mergeable)
+		})
+	}
+}
+
+func TestClient_UpdateStatus(t *testing.T) {
+	cases := []struct {
+		status   models.CommitStatus
+		expState string
+	}{
+		{models.PendingCommitStatus, "pending"},
+		{models.SuccessCommitStatus, "success"},
+		{models.FailedCommitStatus, "failure"},
+	}
+	for _, c := range cases {
+		t.Run(c.expState, func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Equals(t, "/api/v1/repos/owner/repo/statuses/sha", r.RequestURI)
+				body, err := io.ReadAll(r.Body)
+				Ok(t, err)
+				exp := `{"context":"src","description":"description","state":"` + c.expState + `","target_url":"https://atlantis.example.com"}`
+				Equals(t, exp, string(body))
+				w.WriteHeader(http.StatusCreated)
+			}))
+			defer testServer.Close()
+
+			err := newClient(t, testServer.URL).UpdateStatus(repo, models.PullRequest{Num: 1, HeadCommit: "sha"}, c.status, "src", "description", "")
+			Ok(t, err)
+		})
+	}
+}
+
+func TestClient_MergePull(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Equals(t, "/api/v1/repos/owner/repo/pulls/1/merge", r.RequestURI)
+		var body map[string]interface{}
+		Ok(t, json.NewDecoder(r.Body).Decode(&body))
+		Equals(t, "merge", body["Do"])
+		Equals(t, true, body["delete_branch_after_merge"])
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	err := newClient(t, testServer.URL).MergePull(models.PullRequest{Num: 1, BaseRepo: repo}, models.PullRequestOptions{DeleteSourceBranchOnMerge: true})
+	Ok(t, err)
+}
+
+func TestClient_GetFileContent(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/api/v1/repos/owner/repo/raw/atlantis.yaml?ref=feature":
+			w.Write([]byte("version: 3\n")) // nolint: errcheck
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+	client := newClient(t, testServer.URL)
+	pull := models.PullRequest{Num: 1, BaseRepo: repo, HeadBranch: "feature"}
+
+	found, content, err := client.GetFileContent(pull, "atlantis.yaml")
+	Ok(t, err)
+	Assert(t, found, "exp found")
+	Equals(t, "version: 3\n", string(content))
+
+	found, _, err = client.GetFileContent(pull, "missing.yaml")
+	Ok(t, err)
+	Assert(t, !found, "exp not found")
+}
+
+func TestClient_GetTeamNamesForUser(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.RequestURI {
+		case "/api/v1/orgs/owner/teams?page=1&limit=50":
+			w.Write([]byte(`[{"id":1,"name":"infra"},{"id":2,"name":"frontend"}]`)) // nolint: errcheck
+		case "/api/v1/teams/1/members/someone":
+			w.Write([]byte(`{"login":"someone"}`)) // nolint: errcheck
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	defer testServer.Close()
+
+	teams, err := newClient(t, testServer.URL).GetTeamNamesForUser(repo, models.User{Username: "someone"})
+	Ok(t, err)
+	Equals(t, []string{"infra"}, teams)
+}
+
+func TestClient_GetCloneURL(t *testing.T) {
+	repoResp := readFixture(t, "repo.json")
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Equals(t, "/api/v1/repos/owner/repo", r.RequestURI)
+		w.Write(repoResp) // nolint: errcheck
+	}))
+	defer testServer.Close()
+
+	cloneURL, err := newClient(t, testServer.URL).GetCloneURL(models.Gitea, "owner/repo")
+	Ok(t, err)
+	Equals(t, "https://gitea.example.com/owner/repo.git", cloneURL)
+}
@@ -0,0 +1,26 @@
+// Package gitea holds code for Gitea and its Forgejo fork. Both expose the
+// same REST API and webhook payloads so a single client serves either.
+package gitea
+
+const (
+	// EventHeader is the header Gitea uses to set the webhook event type.
+	// Forgejo also sends this header for compatibility.
+	EventHeader = "X-Gitea-Event"
+	// ForgejoEventHeader is the header Forgejo uses to set the webhook event
+	// type.
+	ForgejoEventHeader = "X-Forgejo-Event"
+	// DeliveryHeader is the header containing the unique webhook delivery ID.
+	DeliveryHeader = "X-Gitea-Delivery"
+	// SignatureHeader is the header containing the hex encoded HMAC-SHA256
+	// signature of the request body.
+	SignatureHeader = "X-Gitea-Signature"
+
+	PullRequestEventType  = "pull_request"
+	IssueCommentEventType = "issue_comment"
+
+	PullOpenedAction       = "opened"
+	PullReopenedAction     = "reopened"
+	PullSynchronizedAction = "synchronized"
+	PullClosedAction       = "closed"
+	CommentCreatedAction   = "created"
+)
@@ -0,0 +1,92 @@
+package gitea
+
+type PullRequestEvent struct {
+	Action      *string      `json:"action,omitempty" validate:"required"`
+	Number      *int         `json:"number,omitempty" validate:"required"`
+	PullRequest *PullRequest `json:"pull_request,omitempty" validate:"required"`
+	Repository  *Repository  `json:"repository,omitempty" validate:"required"`
+	Sender      *User        `json:"sender,omitempty" validate:"required"`
+}
+
+type IssueCommentEvent struct {
+	Action     *string     `json:"action,omitempty" validate:"required"`
+	Issue      *Issue      `json:"issue,omitempty" validate:"required"`
+	Comment    *Comment    `json:"comment,omitempty" validate:"required"`
+	Repository *Repository `json:"repository,omitempty" validate:"required"`
+	Sender     *User       `json:"sender,omitempty" validate:"required"`
+	IsPull     bool        `json:"is_pull"`
+}
+
+type Issue struct {
+	Number      *int             `json:"number,omitempty" validate:"required"`
+	PullRequest *PullRequestMeta `json:"pull_request,omitempty"`
+}
+
+// PullRequestMeta is set on an issue when the issue is a pull request.
+type PullRequestMeta struct {
+	Merged bool `json:"merged"`
+}
+
+type Comment struct {
+	ID   *int64  `json:"id,omitempty" validate:"required"`
+	Body *string `json:"body,omitempty" validate:"required"`
+	User *User   `json:"user,omitempty" validate:"required"`
+}
+
+type User struct {
+	Login *string `json:"login,omitempty" validate:"required"`
+}
+
+type Repository struct {
+	FullName *string `json:"full_name,omitempty" validate:"required"`
+	CloneURL *string `json:"clone_url,omitempty" validate:"required"`
+	HTMLURL  *string `json:"html_url,omitempty"`
+}
+
+type PullRequest struct {
+	Number    *int        `json:"number,omitempty" validate:"required"`
+	User      *User       `json:"user,omitempty" validate:"required"`
+	State     *string     `json:"state,omitempty" validate:"required"`
+	HTMLURL   *string     `json:"html_url,omitempty" validate:"required"`
+	Mergeable bool        `json:"mergeable"`
+	Merged    bool        `json:"merged"`
+	Head      *BranchMeta `json:"head,omitempty" validate:"required"`
+	Base      *BranchMeta `json:"base,omitempty" validate:"required"`
+}
+
+type BranchMeta struct {
+	Ref  *string     `json:"ref,omitempty" validate:"required"`
+	SHA  *string     `json:"sha,omitempty" validate:"required"`
+	Repo *Repository `json:"repo,omitempty" validate:"required"`
+}
+
+type ChangedFile struct {
+	Filename         *string `json:"filename,omitempty" validate:"required"`
+	PreviousFilename *string `json:"previous_filename,omitempty"`
+	Status           *string `json:"status,omitempty"`
+}
+
+type Review struct {
+	ID        *int64  `json:"id,omitempty" validate:"required"`
+	State     *string `json:"state,omitempty" validate:"required"`
+	User      *User   `json:"user,omitempty" validate:"required"`
+	Dismissed bool    `json:"dismissed"`
+	Stale     bool    `json:"stale"`
+	Official  bool    `json:"official"`
+	Submitted *string `json:"submitted_at,omitempty"`
+}
+
+type Team struct {
+	ID   *int64  `json:"id,omitempty" validate:"required"`
+	Name *string `json:"name,omitempty" validate:"required"`
+}
+
+type CommitStatus struct {
+	Context *string `json:"context,omitempty" validate:"required"`
+	State   *string `json:"status,omitempty" validate:"required"`
+}
+
+type CombinedStatus struct {
+	State    *string        `json:"state,omitempty" validate:"required"`
+	Statuses []CommitStatus `json:"statuses"`
+}
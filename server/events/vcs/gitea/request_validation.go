@@ -0,0 +1,28 @@
+package gitea
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+)
+
+// ValidateSignature validates that payload was signed with secretKey.
+// Unlike GitHub and Bitbucket Server, Gitea sends the hex encoded
+// HMAC-SHA256 digest without a hash type prefix.
+func ValidateSignature(payload []byte, signature string, secretKey []byte) error {
+	if signature == "" {
+		return errors.New("missing signature")
+	}
+	messageMAC, err := hex.DecodeString(signature)
+	if err != nil {
+		return errors.Wrapf(err, "error decoding signature %q", signature)
+	}
+	mac := hmac.New(sha256.New, secretKey)
+	mac.Write(payload) // nolint: errcheck
+	if !hmac.Equal(messageMAC, mac.Sum(nil)) {
+		return errors.New("payload signature check failed")
+	}
+	return nil
+}
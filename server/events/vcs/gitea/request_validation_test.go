@@ -0,0 +1,27 @@
+package gitea_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+const validationBody = `{"action":"created","comment":{"id":1337,"body":"atlantis plan"}}`
+
+func TestValidateSignature(t *testing.T) {
+	sig := "77e98f72b1b14b6bd60d36b908cb4ee10dc6d3f7d04c3eaba5cebea2f904716e"
+	err := gitea.ValidateSignature([]byte(validationBody), sig, []byte("mysecret"))
+	Ok(t, err)
+}
+
+func TestValidateSignature_Invalid(t *testing.T) {
+	sig := "77e98f72b1b14b6bd60d36b908cb4ee10dc6d3f7d04c3eaba5cebea2f904716e"
+	err := gitea.ValidateSignature([]byte(validationBody+" "), sig, []byte("mysecret"))
+	ErrEquals(t, "payload signature check failed", err)
+}
+
+func TestValidateSignature_Missing(t *testing.T) {
+	err := gitea.ValidateSignature([]byte(validationBody), "", []byte("mysecret"))
+	ErrEquals(t, "missing signature", err)
+}
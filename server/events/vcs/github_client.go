@@ -20,6 +20,7 @@ import (
 	"net/http"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/go-github/v53/github"
 	"github.com/pkg/errors"
@@ -34,6 +35,14 @@ import (
 // by GitHub.
 const maxCommentLength = 65536
 
+// Limits of the Checks API.
+const (
+	maxCheckRunTextLength     = 65535
+	maxCheckRunAnnotations    = 50
+	maxCheckRunActions        = 3
+	checkRunTextTruncatedNote = "\n\n**Warning**: Output truncated. See the details link for the full output."
+)
+
 var (
 	clientMutationID            = githubv4.NewString("atlantis")
 	pullRequestDismissalMessage = *githubv4.NewString("Dismissing reviews because of plan changes")
@@ -349,6 +358,53 @@ func (g *GithubClient) PullIsApproved(repo models.Repo, pull models.PullRequest)
 	return approvalStatus, nil
 }
 
+// GetApprovers returns the users whose latest review of the pull request is
+// an approval. Later reviews that request changes or are dismissed cancel an
+// approval but comments don't.
+func (g *GithubClient) GetApprovers(repo models.Repo, pull models.PullRequest) ([]string, error) {
+	var users []string
+	latestStates := make(map[string]string)
+	nextPage := 0
+	for {
+		opts := github.ListOptions{
+			PerPage: 300,
+		}
+		if nextPage != 0 {
+			opts.Page = nextPage
+		}
+		g.logger.Debug("GET /repos/%v/%v/pulls/%d/reviews", repo.Owner, repo.Name, pull.Num)
+		pageReviews, resp, err := g.client.PullRequests.ListReviews(g.ctx, repo.Owner, repo.Name, pull.Num, &opts)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting reviews")
+		}
+		for _, review := range pageReviews {
+			if review == nil || review.GetState() == "COMMENTED" || review.GetState() == "PENDING" {
+				continue
+			}
+			login := review.GetUser().GetLogin()
+			if login == "" || login == pull.Author {
+				continue
+			}
+			if _, ok := latestStates[login]; !ok {
+				users = append(users, login)
+			}
+			latestStates[login] = review.GetState()
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		nextPage = resp.NextPage
+	}
+
+	var approvers []string
+	for _, user := range users {
+		if latestStates[user] == "APPROVED" {
+			approvers = append(approvers, user)
+		}
+	}
+	return approvers, nil
+}
+
 // DiscardReviews dismisses all reviews on a pull request
 func (g *GithubClient) DiscardReviews(repo models.Repo, pull models.PullRequest) error {
 	reviewStatus, err := g.getPRReviews(repo, pull)
@@ -434,6 +490,10 @@ func (g *GithubClient) GetCombinedStatusMinusApply(repo models.Repo, pull *githu
 			}
 
 			for _, r := range suite.CheckRuns {
+				// Atlantis' own apply check run is skipped like its status.
+				if strings.HasPrefix(r.GetName(), fmt.Sprintf("%s/%s", vcstatusname, command.Apply.String())) {
+					continue
+				}
 				//check to see if the check is required
 				if isRequiredCheck(*r.Name, required.RequiredStatusChecks.Contexts) {
 					if *c.Conclusion == "success" {
@@ -577,6 +637,155 @@ func (g *GithubClient) UpdateStatus(repo models.Repo, pull models.PullRequest, s
 	return err
 }
 
+// UpdateCheckRun creates the check run on the head commit of pull or updates
+// it if one with the same name already exists.
+func (g *GithubClient) UpdateCheckRun(repo models.Repo, pull models.PullRequest, checkRun CheckRun) error {
+	status := "in_progress"
+	var conclusion *string
+	switch checkRun.Status {
+	case models.SuccessCommitStatus:
+		status, conclusion = "completed", github.String("success")
+	case models.FailedCommitStatus:
+		status, conclusion = "completed", github.String("failure")
+	}
+
+	title := checkRun.Title
+	if title == "" {
+		title = checkRun.Name
+	}
+	summary := checkRun.Summary
+	if summary == "" {
+		summary = title
+	}
+	output := &github.CheckRunOutput{
+		Title:   github.String(title),
+		Summary: github.String(summary),
+	}
+	if checkRun.Text != "" {
+		output.Text = github.String(truncateCheckRunText(checkRun.Text))
+	}
+	// Only maxCheckRunAnnotations can be sent per request. The rest are
+	// appended by updating the check run.
+	var annotations []*github.CheckRunAnnotation
+	for _, a := range checkRun.Annotations {
+		endLine := a.EndLine
+		if endLine < a.StartLine {
+			endLine = a.StartLine
+		}
+		annotations = append(annotations, &github.CheckRunAnnotation{
+			Path:            github.String(a.Path),
+			StartLine:       github.Int(a.StartLine),
+			EndLine:         github.Int(endLine),
+			AnnotationLevel: github.String(a.Level),
+			Title:           github.String(a.Title),
+			Message:         github.String(a.Message),
+		})
+	}
+	output.Annotations, annotations = splitCheckRunAnnotations(annotations)
+	var actions []*github.CheckRunAction
+	for i, a := range checkRun.Actions {
+		if i == maxCheckRunActions {
+			break
+		}
+		actions = append(actions, &github.CheckRunAction{
+			Label:       a.Label,
+			Description: a.Description,
+			Identifier:  a.Identifier,
+		})
+	}
+	var detailsURL, externalID *string
+	if checkRun.DetailsURL != "" {
+		detailsURL = github.String(checkRun.DetailsURL)
+	}
+	if checkRun.ExternalID != "" {
+		externalID = github.String(checkRun.ExternalID)
+	}
+
+	g.logger.Debug("GET /repos/%v/%v/commits/%v/check-runs", repo.Owner, repo.Name, pull.HeadCommit)
+	existing, _, err := g.client.Checks.ListCheckRunsForRef(g.ctx, repo.Owner, repo.Name, pull.HeadCommit, &github.ListCheckRunsOptions{
+		CheckName: github.String(checkRun.Name),
+	})
+	if err != nil {
+		return errors.Wrap(err, "listing check runs")
+	}
+	var id int64
+	if len(existing.CheckRuns) > 0 {
+		id = existing.CheckRuns[0].GetID()
+		g.logger.Debug("PATCH /repos/%v/%v/check-runs/%d", repo.Owner, repo.Name, id)
+		_, _, err = g.client.Checks.UpdateCheckRun(g.ctx, repo.Owner, repo.Name, id, github.UpdateCheckRunOptions{
+			Name:       checkRun.Name,
+			DetailsURL: detailsURL,
+			ExternalID: externalID,
+			Status:     github.String(status),
+			Conclusion: conclusion,
+			Output:     output,
+			Actions:    actions,
+		})
+		if err != nil {
+			return errors.Wrap(err, "updating check run")
+		}
+	} else {
+		g.logger.Debug("POST /repos/%v/%v/check-runs", repo.Owner, repo.Name)
+		created, _, err := g.client.Checks.CreateCheckRun(g.ctx, repo.Owner, repo.Name, github.CreateCheckRunOptions{
+			Name:       checkRun.Name,
+			HeadSHA:    pull.HeadCommit,
+			DetailsURL: detailsURL,
+			ExternalID: externalID,
+			Status:     github.String(status),
+			Conclusion: conclusion,
+			Output:     output,
+			Actions:    actions,
+		})
+		if err != nil {
+			return errors.Wrap(err, "creating check run")
+		}
+		id = created.GetID()
+	}
+
+	// GitHub appends the annotations of each update to the check run's
+	// existing annotations.
+	for len(annotations) > 0 {
+		output.Annotations, annotations = splitCheckRunAnnotations(annotations)
+		g.logger.Debug("PATCH /repos/%v/%v/check-runs/%d", repo.Owner, repo.Name, id)
+		_, _, err = g.client.Checks.UpdateCheckRun(g.ctx, repo.Owner, repo.Name, id, github.UpdateCheckRunOptions{
+			Name:   checkRun.Name,
+			Output: output,
+		})
+		if err != nil {
+			return errors.Wrap(err, "adding check run annotations")
+		}
+	}
+	return nil
+}
+
+// truncateCheckRunText shortens text to the length allowed by the Checks API.
+// It cuts on a rune boundary and closes a code block left open by the cut so
+// the note saying the text was truncated isn't rendered as code.
+func truncateCheckRunText(text string) string {
+	if len(text) <= maxCheckRunTextLength {
+		return text
+	}
+	const fence = "\n```"
+	cut := maxCheckRunTextLength - len(checkRunTextTruncatedNote) - len(fence)
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	text = text[:cut]
+	if strings.Count(text, "```")%2 == 1 {
+		text += fence
+	}
+	return text + checkRunTextTruncatedNote
+}
+
+// splitCheckRunAnnotations splits off the annotations that can be sent in a
+// single request.
+func splitCheckRunAnnotations(annotations []*github.CheckRunAnnotation) (batch []*github.CheckRunAnnotation, rest []*github.CheckRunAnnotation) {
+	if len(annotations) <= maxCheckRunAnnotations {
+		return annotations, nil
+	}
+	return annotations[:maxCheckRunAnnotations], annotations[maxCheckRunAnnotations:]
+}
+
 // MergePull merges the pull request.
 func (g *GithubClient) MergePull(pull models.PullRequest, pullOptions models.PullRequestOptions) error {
 	// Users can set their repo to disallow certain types of merging.
@@ -11,7 +11,9 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
+	"github.com/google/go-github/v53/github"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
@@ -384,6 +386,147 @@ func TestGithubClient_UpdateStatus(t *testing.T) {
 	}
 }
 
+func TestGithubClient_UpdateCheckRun(t *testing.T) {
+	checkRun := vcs.CheckRun{
+		Name:       "atlantis/plan: project1",
+		Status:     models.SuccessCommitStatus,
+		Title:      "Plan: 1 to add, 0 to change, 0 to destroy.",
+		Text:       "output",
+		ExternalID: "project=project1",
+		Annotations: []vcs.CheckRunAnnotation{
+			{Path: "dir/main.tf", StartLine: 3, Level: "notice", Title: "null_resource.a", Message: "will be created"},
+		},
+		Actions: []vcs.CheckRunAction{
+			{Label: "Apply", Description: "Apply this plan", Identifier: "apply"},
+		},
+	}
+	expOpts := `"external_id":"project=project1","status":"completed","conclusion":"success",` +
+		`"output":{"title":"Plan: 1 to add, 0 to change, 0 to destroy.","summary":"Plan: 1 to add, 0 to change, 0 to destroy.","text":"output",` +
+		`"annotations":[{"path":"dir/main.tf","start_line":3,"end_line":3,"annotation_level":"notice","message":"will be created","title":"null_resource.a"}]},` +
+		`"actions":[{"label":"Apply","description":"Apply this plan","identifier":"apply"}]}`
+
+	cases := []struct {
+		description string
+		existing    string
+		expRequest  string
+		expBody     string
+	}{
+		{
+			"creates the check run",
+			`{"total_count":0,"check_runs":[]}`,
+			"POST /api/v3/repos/owner/repo/check-runs",
+			`{"name":"atlantis/plan: project1","head_sha":"sha",` + expOpts,
+		},
+		{
+			"updates the existing check run",
+			`{"total_count":1,"check_runs":[{"id":5}]}`,
+			"PATCH /api/v3/repos/owner/repo/check-runs/5",
+			`{"name":"atlantis/plan: project1",` + expOpts,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			var gotRequest, gotBody string
+			testServer := httptest.NewTLSServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.Method + " " + r.RequestURI {
+					case "GET /api/v3/repos/owner/repo/commits/sha/check-runs?check_name=atlantis%2Fplan%3A+project1":
+						w.Write([]byte(c.existing)) // nolint: errcheck
+					case "POST /api/v3/repos/owner/repo/check-runs", "PATCH /api/v3/repos/owner/repo/check-runs/5":
+						body, err := io.ReadAll(r.Body)
+						Ok(t, err)
+						gotRequest, gotBody = r.Method+" "+r.RequestURI, strings.TrimSpace(string(body))
+						w.Write([]byte(`{"id":5}`)) // nolint: errcheck
+					default:
+						t.Errorf("got unexpected request %s %q", r.Method, r.RequestURI)
+						http.Error(w, "not found", http.StatusNotFound)
+					}
+				}))
+
+			testServerURL, err := url.Parse(testServer.URL)
+			Ok(t, err)
+			client, err := vcs.NewGithubClient(testServerURL.Host, &vcs.GithubUserCredentials{"user", "pass"}, vcs.GithubConfig{}, logging.NewNoopLogger(t))
+			Ok(t, err)
+			defer disableSSLVerification()()
+
+			err = client.UpdateCheckRun(models.Repo{
+				FullName: "owner/repo",
+				Owner:    "owner",
+				Name:     "repo",
+				VCSHost: models.VCSHost{
+					Type:     models.Github,
+					Hostname: "github.com",
+				},
+			}, models.PullRequest{
+				Num:        1,
+				HeadCommit: "sha",
+			}, checkRun)
+			Ok(t, err)
+			Equals(t, c.expRequest, gotRequest)
+			Equals(t, c.expBody, gotBody)
+		})
+	}
+}
+
+func TestGithubClient_UpdateCheckRun_LongOutput(t *testing.T) {
+	t.Log("long text should be truncated and annotations sent in batches of 50")
+	var annotations []vcs.CheckRunAnnotation
+	for i := 0; i < 120; i++ {
+		annotations = append(annotations, vcs.CheckRunAnnotation{Path: "main.tf", StartLine: i + 1, Level: "notice", Message: "changed"})
+	}
+	checkRun := vcs.CheckRun{
+		Name:        "atlantis/plan",
+		Status:      models.SuccessCommitStatus,
+		Text:        "```diff\n" + strings.Repeat("é", 40000) + "\n```",
+		Annotations: annotations,
+	}
+
+	var requests []string
+	var outputs []github.CheckRunOutput
+	testServer := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.RequestURI {
+			case "GET /api/v3/repos/owner/repo/commits/sha/check-runs?check_name=atlantis%2Fplan":
+				w.Write([]byte(`{"total_count":0,"check_runs":[]}`)) // nolint: errcheck
+			case "POST /api/v3/repos/owner/repo/check-runs", "PATCH /api/v3/repos/owner/repo/check-runs/5":
+				var body struct {
+					Output github.CheckRunOutput `json:"output"`
+				}
+				Ok(t, json.NewDecoder(r.Body).Decode(&body))
+				requests = append(requests, r.Method)
+				outputs = append(outputs, body.Output)
+				w.Write([]byte(`{"id":5}`)) // nolint: errcheck
+			default:
+				t.Errorf("got unexpected request %s %q", r.Method, r.RequestURI)
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}))
+
+	testServerURL, err := url.Parse(testServer.URL)
+	Ok(t, err)
+	client, err := vcs.NewGithubClient(testServerURL.Host, &vcs.GithubUserCredentials{"user", "pass"}, vcs.GithubConfig{}, logging.NewNoopLogger(t))
+	Ok(t, err)
+	defer disableSSLVerification()()
+
+	Ok(t, client.UpdateCheckRun(models.Repo{Owner: "owner", Name: "repo"}, models.PullRequest{HeadCommit: "sha"}, checkRun))
+
+	Equals(t, []string{"POST", "PATCH", "PATCH"}, requests)
+	text := outputs[0].GetText()
+	Assert(t, len(text) <= 65535, "expected text to be truncated to 65535 bytes, got %d", len(text))
+	Assert(t, utf8.ValidString(text), "expected text to be truncated on a rune boundary")
+	Assert(t, strings.HasSuffix(text, "é\n```\n\n**Warning**: Output truncated. See the details link for the full output."),
+		"expected the code block to be closed before the warning, got %q", text[len(text)-100:])
+	var lines []int
+	for _, output := range outputs {
+		Assert(t, len(output.Annotations) <= 50, "expected at most 50 annotations per request, got %d", len(output.Annotations))
+		for _, a := range output.Annotations {
+			lines = append(lines, a.GetStartLine())
+		}
+	}
+	Equals(t, 120, len(lines))
+	Equals(t, 120, lines[119])
+}
+
 func TestGithubClient_PullIsApproved(t *testing.T) {
 	respTemplate := `[
 		{
@@ -469,6 +612,58 @@ func TestGithubClient_PullIsApproved(t *testing.T) {
 	Equals(t, false, approvalStatus.IsApproved)
 }
 
+func TestGithubClient_GetApprovers(t *testing.T) {
+	review := func(id int, login string, state string) string {
+		return fmt.Sprintf(`{"id": %d, "user": {"login": %q}, "state": %q}`, id, login, state)
+	}
+	firstResp := "[" + strings.Join([]string{
+		review(1, "alice", "APPROVED"),
+		review(2, "bob", "APPROVED"),
+		review(3, "author", "APPROVED"),
+	}, ",") + "]"
+	secondResp := "[" + strings.Join([]string{
+		review(4, "bob", "CHANGES_REQUESTED"),
+		review(5, "alice", "COMMENTED"),
+		review(6, "carol", "CHANGES_REQUESTED"),
+		review(7, "carol", "APPROVED"),
+	}, ",") + "]"
+	testServer := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.RequestURI {
+			case "/api/v3/repos/owner/repo/pulls/1/reviews?per_page=300":
+				w.Header().Add("Link", `<https://api.github.com/resource?page=2>; rel="next",
+      <https://api.github.com/resource?page=2>; rel="last"`)
+				w.Write([]byte(firstResp)) // nolint: errcheck
+			case "/api/v3/repos/owner/repo/pulls/1/reviews?page=2&per_page=300":
+				w.Write([]byte(secondResp)) // nolint: errcheck
+			default:
+				t.Errorf("got unexpected request at %q", r.RequestURI)
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}))
+
+	testServerURL, err := url.Parse(testServer.URL)
+	Ok(t, err)
+	client, err := vcs.NewGithubClient(testServerURL.Host, &vcs.GithubUserCredentials{"user", "pass"}, vcs.GithubConfig{}, logging.NewNoopLogger(t))
+	Ok(t, err)
+	defer disableSSLVerification()()
+
+	approvers, err := client.GetApprovers(models.Repo{
+		FullName: "owner/repo",
+		Owner:    "owner",
+		Name:     "repo",
+		VCSHost: models.VCSHost{
+			Type:     models.Github,
+			Hostname: "github.com",
+		},
+	}, models.PullRequest{
+		Num:    1,
+		Author: "author",
+	})
+	Ok(t, err)
+	Equals(t, []string{"alice", "carol"}, approvers)
+}
+
 func TestGithubClient_PullIsMergeable(t *testing.T) {
 	vcsStatusName := "atlantis-test"
 	cases := []struct {
@@ -263,6 +263,22 @@ func (g *GitlabClient) PullIsApproved(repo models.Repo, pull models.PullRequest)
 	}, nil
 }
 
+// GetApprovers returns the users that approved the merge request.
+func (g *GitlabClient) GetApprovers(repo models.Repo, pull models.PullRequest) ([]string, error) {
+	approvals, _, err := g.Client.MergeRequests.GetMergeRequestApprovals(repo.FullName, pull.Num)
+	if err != nil {
+		return nil, err
+	}
+	var approvers []string
+	for _, approvedBy := range approvals.ApprovedBy {
+		if approvedBy.User == nil || approvedBy.User.Username == pull.Author {
+			continue
+		}
+		approvers = append(approvers, approvedBy.User.Username)
+	}
+	return approvers, nil
+}
+
 // PullIsMergeable returns true if the merge request can be merged.
 // In GitLab, there isn't a single field that tells us if the pull request is
 // mergeable so for now we check the merge_status and approvals_before_merge
@@ -477,9 +493,99 @@ func MustConstraint(constraint string) version.Constraints {
 	return c
 }
 
-// GetTeamNamesForUser returns the names of the teams or groups that the user belongs to (in the organization the repository belongs to).
+// GetTeamNamesForUser returns the full paths of the groups that the user is a
+// direct member of under the top-level group that owns the repository, ex.
+// "acme/platform". Full paths are used, rather than the last segment, so that
+// they match the teams in the permissions config and the groups in CODEOWNERS
+// files. If the repository is owned by a user there are no groups.
+//
+// The groups are found from the user's memberships, which needs an
+// administrator's token. With other tokens each group's members are checked.
 func (g *GitlabClient) GetTeamNamesForUser(repo models.Repo, user models.User) ([]string, error) {
-	return nil, nil
+	users, _, err := g.Client.Users.ListUsers(&gitlab.ListUsersOptions{Username: gitlab.String(user.Username)})
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting user %s", user.Username)
+	}
+	if len(users) == 0 {
+		return nil, nil
+	}
+	userID := users[0].ID
+
+	topLevelGroup := strings.Split(repo.Owner, "/")[0]
+	top, resp, err := g.Client.Groups.GetGroup(topLevelGroup, &gitlab.GetGroupOptions{WithProjects: gitlab.Bool(false)})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting group %s", topLevelGroup)
+	}
+	groups := []*gitlab.Group{top}
+	opt := &gitlab.ListDescendantGroupsOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	// We'll only loop 1000 times as a safety measure.
+	maxLoops := 1000
+	for i := 0; i < maxLoops; i++ {
+		descendants, resp, err := g.Client.Groups.ListDescendantGroups(top.ID, opt)
+		if err != nil {
+			return nil, errors.Wrapf(err, "listing subgroups of %s", topLevelGroup)
+		}
+		groups = append(groups, descendants...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	memberOf, err := g.groupMemberships(userID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting memberships of %s", user.Username)
+	}
+	var teamNames []string
+	for _, group := range groups {
+		if memberOf != nil {
+			if memberOf[group.ID] {
+				teamNames = append(teamNames, group.FullPath)
+			}
+			continue
+		}
+		_, resp, err := g.Client.GroupMembers.GetGroupMember(group.ID, userID)
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting membership of %s", group.FullPath)
+		}
+		teamNames = append(teamNames, group.FullPath)
+	}
+	return teamNames, nil
+}
+
+// groupMemberships returns the IDs of the groups that the user is a direct
+// member of. It returns nil if the token isn't allowed to list memberships.
+func (g *GitlabClient) groupMemberships(userID int) (map[int]bool, error) {
+	memberOf := make(map[int]bool)
+	opt := &gitlab.GetUserMembershipOptions{
+		Type:        gitlab.String("Namespace"),
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}
+	// We'll only loop 1000 times as a safety measure.
+	maxLoops := 1000
+	for i := 0; i < maxLoops; i++ {
+		memberships, resp, err := g.Client.Users.GetUserMemberships(userID, opt)
+		if resp != nil && resp.StatusCode == http.StatusForbidden {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, membership := range memberships {
+			memberOf[membership.SourceID] = true
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return memberOf, nil
 }
 
 // GetFileContent a repository file content from VCS (which support fetch a single file from repository)
@@ -24,6 +24,7 @@ func NewInstrumentedGithubClient(client *GithubClient, statsScope tally.Scope, l
 	return &InstrumentedGithubClient{
 		InstrumentedClient: instrumentedGHClient,
 		PullRequestGetter:  client,
+		CheckRunUpdater:    client,
 		StatsScope:         scope,
 		Logger:             logger,
 	}
@@ -40,6 +41,7 @@ type GithubPullRequestGetter interface {
 type IGithubClient interface {
 	Client
 	GithubPullRequestGetter
+	GithubCheckRunUpdater
 }
 
 // InstrumentedGithubClient should delegate to the underlying InstrumentedClient for vcs provider-agnostic
@@ -47,6 +49,7 @@ type IGithubClient interface {
 type InstrumentedGithubClient struct {
 	*InstrumentedClient
 	PullRequestGetter GithubPullRequestGetter
+	CheckRunUpdater   GithubCheckRunUpdater
 	StatsScope        tally.Scope
 	Logger            logging.SimpleLogging
 }
@@ -78,6 +81,27 @@ func (c *InstrumentedGithubClient) GetPullRequest(repo models.Repo, pullNum int)
 
 }
 
+func (c *InstrumentedGithubClient) UpdateCheckRun(repo models.Repo, pull models.PullRequest, checkRun CheckRun) error {
+	scope := c.StatsScope.SubScope("update_check_run")
+	scope = SetGitScopeTags(scope, repo.FullName, pull.Num)
+	logger := c.Logger.WithHistory(fmtLogSrc(repo, pull.Num)...)
+
+	executionTime := scope.Timer(metrics.ExecutionTimeMetric).Start()
+	defer executionTime.Stop()
+
+	executionSuccess := scope.Counter(metrics.ExecutionSuccessMetric)
+	executionError := scope.Counter(metrics.ExecutionErrorMetric)
+
+	if err := c.CheckRunUpdater.UpdateCheckRun(repo, pull, checkRun); err != nil {
+		executionError.Inc(1)
+		logger.Err("Unable to update check run %q, error: %s", checkRun.Name, err.Error())
+		return err
+	}
+
+	executionSuccess.Inc(1)
+	return nil
+}
+
 type InstrumentedClient struct {
 	Client
 	StatsScope tally.Scope
@@ -192,6 +216,29 @@ func (c *InstrumentedClient) PullIsApproved(repo models.Repo, pull models.PullRe
 	return approved, err
 }
 
+func (c *InstrumentedClient) GetApprovers(repo models.Repo, pull models.PullRequest) ([]string, error) {
+	scope := c.StatsScope.SubScope("get_approvers")
+	scope = SetGitScopeTags(scope, repo.FullName, pull.Num)
+	logger := c.Logger.WithHistory(fmtLogSrc(repo, pull.Num)...)
+
+	executionTime := scope.Timer(metrics.ExecutionTimeMetric).Start()
+	defer executionTime.Stop()
+
+	executionSuccess := scope.Counter(metrics.ExecutionSuccessMetric)
+	executionError := scope.Counter(metrics.ExecutionErrorMetric)
+
+	approvers, err := c.Client.GetApprovers(repo, pull)
+
+	if err != nil {
+		executionError.Inc(1)
+		logger.Err("Unable to get pull approvers, error: %s", err.Error())
+	} else {
+		executionSuccess.Inc(1)
+	}
+
+	return approvers, err
+}
+
 func (c *InstrumentedClient) PullIsMergeable(repo models.Repo, pull models.PullRequest, vcsstatusname string) (bool, error) {
 	scope := c.StatsScope.SubScope("pull_is_mergeable")
 	scope = SetGitScopeTags(scope, repo.FullName, pull.Num)
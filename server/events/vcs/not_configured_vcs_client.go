@@ -41,6 +41,9 @@ func (a *NotConfiguredVCSClient) ReactToComment(repo models.Repo, pullNum int, c
 func (a *NotConfiguredVCSClient) PullIsApproved(repo models.Repo, pull models.PullRequest) (models.ApprovalStatus, error) {
 	return models.ApprovalStatus{}, a.err()
 }
+func (a *NotConfiguredVCSClient) GetApprovers(repo models.Repo, pull models.PullRequest) ([]string, error) {
+	return nil, a.err()
+}
 func (a *NotConfiguredVCSClient) DiscardReviews(repo models.Repo, pull models.PullRequest) error {
 	return nil
 }
@@ -25,7 +25,7 @@ type ClientProxy struct {
 	clients map[models.VCSHostType]Client
 }
 
-func NewClientProxy(githubClient Client, gitlabClient Client, bitbucketCloudClient Client, bitbucketServerClient Client, azuredevopsClient Client) *ClientProxy {
+func NewClientProxy(githubClient Client, gitlabClient Client, bitbucketCloudClient Client, bitbucketServerClient Client, azuredevopsClient Client, giteaClient Client) *ClientProxy {
 	if githubClient == nil {
 		githubClient = &NotConfiguredVCSClient{}
 	}
@@ -41,6 +41,9 @@ func NewClientProxy(githubClient Client, gitlabClient Client, bitbucketCloudClie
 	if azuredevopsClient == nil {
 		azuredevopsClient = &NotConfiguredVCSClient{}
 	}
+	if giteaClient == nil {
+		giteaClient = &NotConfiguredVCSClient{}
+	}
 	return &ClientProxy{
 		clients: map[models.VCSHostType]Client{
 			models.Github:          githubClient,
@@ -48,6 +51,7 @@ func NewClientProxy(githubClient Client, gitlabClient Client, bitbucketCloudClie
 			models.BitbucketCloud:  bitbucketCloudClient,
 			models.BitbucketServer: bitbucketServerClient,
 			models.AzureDevops:     azuredevopsClient,
+			models.Gitea:           giteaClient,
 		},
 	}
 }
@@ -72,6 +76,10 @@ func (d *ClientProxy) PullIsApproved(repo models.Repo, pull models.PullRequest)
 	return d.clients[repo.VCSHost.Type].PullIsApproved(repo, pull)
 }
 
+func (d *ClientProxy) GetApprovers(repo models.Repo, pull models.PullRequest) ([]string, error) {
+	return d.clients[repo.VCSHost.Type].GetApprovers(repo, pull)
+}
+
 func (d *ClientProxy) DiscardReviews(repo models.Repo, pull models.PullRequest) error {
 	return d.clients[repo.VCSHost.Type].DiscardReviews(repo, pull)
 }
@@ -1 +1,25 @@
 package vcs
+
+import (
+	"strings"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// TeamPaths converts the team names that GetTeamNamesForUser returned for a
+// user of repo into the paths that CODEOWNERS files use, ex. "acme/platform".
+// GitLab already returns full group paths and Bitbucket Server groups don't
+// belong to an organization. On the other hosts teams belong to the
+// organization that owns the repo.
+func TeamPaths(repo models.Repo, teams []string) []string {
+	switch repo.VCSHost.Type {
+	case models.Gitlab, models.BitbucketServer:
+		return teams
+	}
+	org := strings.Split(repo.Owner, "/")[0]
+	paths := make([]string, 0, len(teams))
+	for _, team := range teams {
+		paths = append(paths, org+"/"+team)
+	}
+	return paths
+}
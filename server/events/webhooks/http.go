@@ -0,0 +1,230 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+const (
+	// SignatureHeader is the header containing the HMAC-SHA256 signature of
+	// the request body, ex. "sha256=<hex digest>".
+	SignatureHeader = "X-Atlantis-Signature"
+	// EventHeader is the header containing the event of the request.
+	EventHeader = "X-Atlantis-Event"
+
+	defaultHTTPMaxAttempts = 3
+	defaultHTTPBackoff     = time.Second
+	defaultHTTPTimeout     = 10 * time.Second
+	defaultHTTPQueueSize   = 100
+)
+
+// HTTPWebhook POSTs events as JSON to a URL. Events passed to SendEvent and
+// Enqueue are sent one at a time in the background so callers never wait for
+// the requests or their retries.
+type HTTPWebhook struct {
+	Client         *http.Client
+	Event          string
+	WorkspaceRegex *regexp.Regexp
+	URL            string
+	// Secret is used to sign the request body. If empty the request isn't
+	// signed.
+	Secret string
+	// MaxAttempts is how many times a request is attempted before giving up.
+	MaxAttempts int
+	// Backoff is how long to wait before the first retry. It doubles with
+	// each retry.
+	Backoff time.Duration
+	// QueueSize is how many events can wait to be sent in the background.
+	// Events are dropped while the queue is full.
+	QueueSize int
+
+	startOnce sync.Once
+	queue     chan queuedPost
+}
+
+// queuedPost is an event waiting to be sent in the background.
+type queuedPost struct {
+	log   logging.SimpleLogging
+	event string
+	body  []byte
+}
+
+// HTTPPayload is the JSON body of the requests sent by HTTPWebhook.
+type HTTPPayload struct {
+	Event       string
+	Repository  string
+	VCSHost     string
+	PullNum     int
+	PullURL     string
+	HeadCommit  string
+	BaseBranch  string
+	User        string
+	ProjectName string
+	Directory   string
+	Workspace   string
+	Success     bool
+	PlanStats   *models.PlanSuccessStats `json:",omitempty"`
+	JobURL      string                   `json:",omitempty"`
+	Time        time.Time
+}
+
+func NewHTTP(event string, r *regexp.Regexp, url string, secret string) *HTTPWebhook {
+	return &HTTPWebhook{
+		Client:         &http.Client{Timeout: defaultHTTPTimeout},
+		Event:          event,
+		WorkspaceRegex: r,
+		URL:            url,
+		Secret:         secret,
+		MaxAttempts:    defaultHTTPMaxAttempts,
+		Backoff:        defaultHTTPBackoff,
+		QueueSize:      defaultHTTPQueueSize,
+	}
+}
+
+// Send is a no-op. Apply events are sent by SendEvent.
+func (h *HTTPWebhook) Send(log logging.SimpleLogging, applyResult ApplyResult) error {
+	return nil
+}
+
+// SendDrift is a no-op since drift events aren't supported.
+func (h *HTTPWebhook) SendDrift(log logging.SimpleLogging, driftResult DriftResult) error {
+	return nil
+}
+
+// SendEvent queues the event to be POSTed if it's the event this webhook is
+// for and the workspace matches the regex.
+func (h *HTTPWebhook) SendEvent(log logging.SimpleLogging, result EventResult) error {
+	if result.Event != h.Event || !h.WorkspaceRegex.MatchString(result.Workspace) {
+		return nil
+	}
+
+	return h.Enqueue(log, result.Event, NewHTTPPayload(result))
+}
+
+// Enqueue queues payload to be POSTed as JSON for event in the background.
+// Errors sending it are logged to log. It returns an error if the queue is
+// full.
+func (h *HTTPWebhook) Enqueue(log logging.SimpleLogging, event string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshalling payload: %s", err)
+	}
+
+	h.startOnce.Do(h.start)
+	select {
+	case h.queue <- queuedPost{log: log, event: event, body: body}:
+		return nil
+	default:
+		return fmt.Errorf("dropping %s event to %s since %d events are waiting to be sent", event, h.URL, cap(h.queue))
+	}
+}
+
+// start starts the goroutine that sends the queued events.
+func (h *HTTPWebhook) start() {
+	size := h.QueueSize
+	if size <= 0 {
+		size = defaultHTTPQueueSize
+	}
+	h.queue = make(chan queuedPost, size)
+	go func() {
+		for p := range h.queue {
+			if err := h.send(p.log, p.event, p.body); err != nil {
+				p.log.Warn("error sending http webhook: %s", err)
+			}
+		}
+	}()
+}
+
+// Post POSTs payload as JSON for event and waits for the response. Requests
+// that fail with a network error or a 5xx or 429 response are retried.
+func (h *HTTPWebhook) Post(log logging.SimpleLogging, event string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshalling payload: %s", err)
+	}
+	return h.send(log, event, body)
+}
+
+// send POSTs body for event, retrying with backoff.
+func (h *HTTPWebhook) send(log logging.SimpleLogging, event string, body []byte) error {
+	backoff := h.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := h.post(body, event)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= h.MaxAttempts {
+			return fmt.Errorf("sending %s event to %s: %s", event, h.URL, err)
+		}
+		log.Debug("retrying %s event to %s in %s: %s", event, h.URL, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post sends body to the URL. It returns true if the request failed and
+// should be retried.
+func (h *HTTPWebhook) post(body []byte, event string) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	if h.Secret != "" {
+		req.Header.Set(SignatureHeader, "sha256="+Sign(h.Secret, body))
+	}
+
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()        // nolint: errcheck
+	io.Copy(io.Discard, resp.Body) // nolint: errcheck
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("unexpected status %d", resp.StatusCode)
+}
+
+// NewHTTPPayload returns the payload for result. It only contains the
+// repo's name and not its clone URL since that contains credentials.
+func NewHTTPPayload(result EventResult) HTTPPayload {
+	return HTTPPayload{
+		Event:       result.Event,
+		Repository:  result.Repo.FullName,
+		VCSHost:     result.Repo.VCSHost.Hostname,
+		PullNum:     result.Pull.Num,
+		PullURL:     result.Pull.URL,
+		HeadCommit:  result.Pull.HeadCommit,
+		BaseBranch:  result.Pull.BaseBranch,
+		User:        result.User.Username,
+		ProjectName: result.ProjectName,
+		Directory:   result.Directory,
+		Workspace:   result.Workspace,
+		Success:     result.Success,
+		PlanStats:   result.PlanStats,
+		JobURL:      result.JobURL,
+		Time:        result.Time,
+	}
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of body using secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body) // nolint: errcheck
+	return hex.EncodeToString(mac.Sum(nil))
+}
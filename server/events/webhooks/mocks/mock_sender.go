@@ -41,6 +41,36 @@ func (mock *MockSender) Send(log logging.SimpleLogging, applyResult webhooks.App
 	return ret0
 }
 
+func (mock *MockSender) SendDrift(log logging.SimpleLogging, driftResult webhooks.DriftResult) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockSender().")
+	}
+	params := []pegomock.Param{log, driftResult}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("SendDrift", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
+func (mock *MockSender) SendEvent(log logging.SimpleLogging, eventResult webhooks.EventResult) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockSender().")
+	}
+	params := []pegomock.Param{log, eventResult}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("SendEvent", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockSender) VerifyWasCalledOnce() *VerifierMockSender {
 	return &VerifierMockSender{
 		mock:                   mock,
@@ -108,3 +138,65 @@ func (c *MockSender_Send_OngoingVerification) GetAllCapturedArguments() (_param0
 	}
 	return
 }
+
+func (verifier *VerifierMockSender) SendDrift(log logging.SimpleLogging, driftResult webhooks.DriftResult) *MockSender_SendDrift_OngoingVerification {
+	params := []pegomock.Param{log, driftResult}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "SendDrift", params, verifier.timeout)
+	return &MockSender_SendDrift_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockSender_SendDrift_OngoingVerification struct {
+	mock              *MockSender
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockSender_SendDrift_OngoingVerification) GetCapturedArguments() (logging.SimpleLogging, webhooks.DriftResult) {
+	log, driftResult := c.GetAllCapturedArguments()
+	return log[len(log)-1], driftResult[len(driftResult)-1]
+}
+
+func (c *MockSender_SendDrift_OngoingVerification) GetAllCapturedArguments() (_param0 []logging.SimpleLogging, _param1 []webhooks.DriftResult) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]logging.SimpleLogging, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(logging.SimpleLogging)
+		}
+		_param1 = make([]webhooks.DriftResult, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(webhooks.DriftResult)
+		}
+	}
+	return
+}
+
+func (verifier *VerifierMockSender) SendEvent(log logging.SimpleLogging, eventResult webhooks.EventResult) *MockSender_SendEvent_OngoingVerification {
+	params := []pegomock.Param{log, eventResult}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "SendEvent", params, verifier.timeout)
+	return &MockSender_SendEvent_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockSender_SendEvent_OngoingVerification struct {
+	mock              *MockSender
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockSender_SendEvent_OngoingVerification) GetCapturedArguments() (logging.SimpleLogging, webhooks.EventResult) {
+	log, eventResult := c.GetAllCapturedArguments()
+	return log[len(log)-1], eventResult[len(eventResult)-1]
+}
+
+func (c *MockSender_SendEvent_OngoingVerification) GetAllCapturedArguments() (_param0 []logging.SimpleLogging, _param1 []webhooks.EventResult) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]logging.SimpleLogging, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(logging.SimpleLogging)
+		}
+		_param1 = make([]webhooks.EventResult, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(webhooks.EventResult)
+		}
+	}
+	return
+}
@@ -47,3 +47,18 @@ func (s *SlackWebhook) Send(log logging.SimpleLogging, applyResult ApplyResult)
 	}
 	return s.Client.PostMessage(s.Channel, applyResult)
 }
+
+// SendDrift sends the drift webhook to Slack if the workspace matches the
+// regex.
+func (s *SlackWebhook) SendDrift(log logging.SimpleLogging, driftResult DriftResult) error {
+	if !s.WorkspaceRegex.MatchString(driftResult.Workspace) {
+		return nil
+	}
+	return s.Client.PostDriftMessage(s.Channel, driftResult)
+}
+
+// SendEvent is a no-op since Slack webhooks are only sent for apply and drift
+// results.
+func (s *SlackWebhook) SendEvent(log logging.SimpleLogging, eventResult EventResult) error {
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 
 const (
 	slackSuccessColour = "good"
+	slackWarningColour = "warning"
 	slackFailureColour = "danger"
 )
 
@@ -31,6 +32,7 @@ type SlackClient interface {
 	AuthTest() error
 	TokenIsSet() bool
 	PostMessage(channel string, applyResult ApplyResult) error
+	PostDriftMessage(channel string, driftResult DriftResult) error
 }
 
 //go:generate pegomock generate --package mocks -o mocks/mock_underlying_slack_client.go UnderlyingSlackClient
@@ -75,6 +77,55 @@ func (d *DefaultSlackClient) PostMessage(channel string, applyResult ApplyResult
 	return err
 }
 
+func (d *DefaultSlackClient) PostDriftMessage(channel string, driftResult DriftResult) error {
+	attachment := d.createDriftAttachment(driftResult)
+	_, _, err := d.Slack.PostMessage(
+		channel,
+		slack.MsgOptionAsUser(true),
+		slack.MsgOptionText("", false),
+		slack.MsgOptionAttachments(attachment),
+	)
+	return err
+}
+
+func (d *DefaultSlackClient) createDriftAttachment(driftResult DriftResult) slack.Attachment {
+	directory := driftResult.Directory
+	// Since "." looks weird, replace it with "/" to make it clear this is the root.
+	if directory == "." {
+		directory = "/"
+	}
+
+	fields := []slack.AttachmentField{
+		{
+			Title: "Workspace",
+			Value: driftResult.Workspace,
+			Short: true,
+		},
+		{
+			Title: "Directory",
+			Value: directory,
+			Short: true,
+		},
+	}
+	if driftResult.ProjectName != "" {
+		fields = append(fields, slack.AttachmentField{
+			Title: "Project",
+			Value: driftResult.ProjectName,
+			Short: true,
+		})
+	}
+	fields = append(fields, slack.AttachmentField{
+		Title: "Plan",
+		Value: driftResult.Summary,
+	})
+
+	return slack.Attachment{
+		Color:  slackWarningColour,
+		Text:   fmt.Sprintf("Drift detected in %s on branch %s", driftResult.Repo.FullName, driftResult.Branch),
+		Fields: fields,
+	}
+}
+
 func (d *DefaultSlackClient) createAttachments(applyResult ApplyResult) []slack.Attachment {
 	var colour string
 	var successWord string
@@ -66,3 +66,29 @@ func TestSend_NoopSuccess(t *testing.T) {
 	Ok(t, err)
 	client.VerifyWasCalled(Never()).PostMessage(channel, result)
 }
+
+func TestSendDrift_PostDriftMessage(t *testing.T) {
+	t.Log("Sending a drift hook with a matching regex should call PostDriftMessage")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+	regex, err := regexp.Compile("prod.*")
+	Ok(t, err)
+
+	channel := "somechannel"
+	hook := webhooks.SlackWebhook{
+		Client:         client,
+		WorkspaceRegex: regex,
+		Channel:        channel,
+	}
+	result := webhooks.DriftResult{
+		Workspace: "production",
+	}
+	_ = hook.SendDrift(logging.NewNoopLogger(t), result)
+	client.VerifyWasCalledOnce().PostDriftMessage(channel, result)
+
+	t.Log("PostDriftMessage should not be called if the regex doesn't match")
+	result.Workspace = "staging"
+	err = hook.SendDrift(logging.NewNoopLogger(t), result)
+	Ok(t, err)
+	client.VerifyWasCalled(Never()).PostDriftMessage(channel, result)
+}
@@ -16,6 +16,7 @@ package webhooks
 import (
 	"fmt"
 	"regexp"
+	"time"
 
 	"errors"
 
@@ -24,7 +25,14 @@ import (
 )
 
 const SlackKind = "slack"
+const HTTPKind = "http"
 const ApplyEvent = "apply"
+const DriftEvent = "drift"
+const PlanEvent = "plan"
+const PolicyCheckEvent = "policy_check"
+const LockEvent = "lock"
+const UnlockEvent = "unlock"
+const AuditEvent = "audit"
 
 //go:generate pegomock generate --package mocks -o mocks/mock_sender.go Sender
 
@@ -32,6 +40,12 @@ const ApplyEvent = "apply"
 type Sender interface {
 	// Send sends the webhook (if the implementation thinks it should).
 	Send(log logging.SimpleLogging, applyResult ApplyResult) error
+	// SendDrift sends the drift webhook (if the implementation thinks it
+	// should).
+	SendDrift(log logging.SimpleLogging, driftResult DriftResult) error
+	// SendEvent sends the event webhook (if the implementation thinks it
+	// should).
+	SendEvent(log logging.SimpleLogging, eventResult EventResult) error
 }
 
 // ApplyResult is the result of a terraform apply.
@@ -44,9 +58,44 @@ type ApplyResult struct {
 	Directory string
 }
 
+// DriftResult is a project whose infrastructure has drifted from the
+// configuration on Branch.
+type DriftResult struct {
+	Workspace   string
+	Repo        models.Repo
+	Branch      string
+	ProjectName string
+	Directory   string
+	// Summary is the one line summary of the plan, ex.
+	// "Plan: 1 to add, 0 to change, 0 to destroy.".
+	Summary string
+}
+
+// EventResult is a plan, policy check, apply, lock or unlock event for a
+// project.
+type EventResult struct {
+	ApplyResult
+	// Event is the event, ex. "plan".
+	Event       string
+	ProjectName string
+	// PlanStats is only set for successful plans.
+	PlanStats *models.PlanSuccessStats
+	// JobURL is the URL of the project's job output, if there is one.
+	JobURL string
+	Time   time.Time
+}
+
 // MultiWebhookSender sends multiple webhooks for each one it's configured for.
 type MultiWebhookSender struct {
+	// Webhooks are sent apply results.
 	Webhooks []Sender
+	// DriftWebhooks are sent drift results.
+	DriftWebhooks []Sender
+	// EventWebhooks are sent plan, policy check, apply, lock and unlock
+	// events.
+	EventWebhooks []Sender
+	// AuditWebhooks are sent audit events by an audit.WebhookSink.
+	AuditWebhooks []*HTTPWebhook
 }
 
 type Config struct {
@@ -54,10 +103,15 @@ type Config struct {
 	WorkspaceRegex string
 	Kind           string
 	Channel        string
+	URL            string
+	Secret         string
 }
 
 func NewMultiWebhookSender(configs []Config, client SlackClient) (*MultiWebhookSender, error) {
 	var webhooks []Sender
+	var driftWebhooks []Sender
+	var eventWebhooks []Sender
+	var auditWebhooks []*HTTPWebhook
 	for _, c := range configs {
 		r, err := regexp.Compile(c.WorkspaceRegex)
 		if err != nil {
@@ -66,11 +120,11 @@ func NewMultiWebhookSender(configs []Config, client SlackClient) (*MultiWebhookS
 		if c.Kind == "" || c.Event == "" {
 			return nil, errors.New("must specify \"kind\" and \"event\" keys for webhooks")
 		}
-		if c.Event != ApplyEvent {
-			return nil, fmt.Errorf("\"event: %s\" not supported. Only \"event: %s\" is supported right now", c.Event, ApplyEvent)
-		}
 		switch c.Kind {
 		case SlackKind:
+			if c.Event != ApplyEvent && c.Event != DriftEvent {
+				return nil, fmt.Errorf("\"event: %s\" not supported. Only \"event: %s\" and \"event: %s\" are supported for \"kind: %s\" right now", c.Event, ApplyEvent, DriftEvent, SlackKind)
+			}
 			if !client.TokenIsSet() {
 				return nil, errors.New("must specify top-level \"slack-token\" if using a webhook of \"kind: slack\"")
 			}
@@ -81,14 +135,35 @@ func NewMultiWebhookSender(configs []Config, client SlackClient) (*MultiWebhookS
 			if err != nil {
 				return nil, err
 			}
-			webhooks = append(webhooks, slack)
+			if c.Event == DriftEvent {
+				driftWebhooks = append(driftWebhooks, slack)
+			} else {
+				webhooks = append(webhooks, slack)
+			}
+		case HTTPKind:
+			switch c.Event {
+			case ApplyEvent, PlanEvent, PolicyCheckEvent, LockEvent, UnlockEvent, AuditEvent:
+			default:
+				return nil, fmt.Errorf("\"event: %s\" not supported. Only \"event: %s\", \"event: %s\", \"event: %s\", \"event: %s\", \"event: %s\" and \"event: %s\" are supported for \"kind: %s\" right now", c.Event, ApplyEvent, PlanEvent, PolicyCheckEvent, LockEvent, UnlockEvent, AuditEvent, HTTPKind)
+			}
+			if c.URL == "" {
+				return nil, errors.New("must specify \"url\" if using a webhook of \"kind: http\"")
+			}
+			if c.Event == AuditEvent {
+				auditWebhooks = append(auditWebhooks, NewHTTP(c.Event, r, c.URL, c.Secret))
+			} else {
+				eventWebhooks = append(eventWebhooks, NewHTTP(c.Event, r, c.URL, c.Secret))
+			}
 		default:
-			return nil, fmt.Errorf("\"kind: %s\" not supported. Only \"kind: %s\" is supported right now", c.Kind, SlackKind)
+			return nil, fmt.Errorf("\"kind: %s\" not supported. Only \"kind: %s\" and \"kind: %s\" are supported right now", c.Kind, SlackKind, HTTPKind)
 		}
 	}
 
 	return &MultiWebhookSender{
-		Webhooks: webhooks,
+		Webhooks:      webhooks,
+		DriftWebhooks: driftWebhooks,
+		EventWebhooks: eventWebhooks,
+		AuditWebhooks: auditWebhooks,
 	}, nil
 }
 
@@ -101,3 +176,23 @@ func (w *MultiWebhookSender) Send(log logging.SimpleLogging, result ApplyResult)
 	}
 	return nil
 }
+
+// SendDrift sends the drift webhook using its DriftWebhooks.
+func (w *MultiWebhookSender) SendDrift(log logging.SimpleLogging, result DriftResult) error {
+	for _, w := range w.DriftWebhooks {
+		if err := w.SendDrift(log, result); err != nil {
+			log.Warn("error sending slack webhook: %s", err)
+		}
+	}
+	return nil
+}
+
+// SendEvent sends the event webhook using its EventWebhooks.
+func (w *MultiWebhookSender) SendEvent(log logging.SimpleLogging, result EventResult) error {
+	for _, w := range w.EventWebhooks {
+		if err := w.SendEvent(log, result); err != nil {
+			log.Warn("error sending http webhook: %s", err)
+		}
+	}
+	return nil
+}
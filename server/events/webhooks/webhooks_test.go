@@ -76,7 +76,7 @@ func TestNewWebhooksManager_UnsupportedEvent(t *testing.T) {
 	configs[0].Event = unsupportedEvent
 	_, err := webhooks.NewMultiWebhookSender(configs, client)
 	Assert(t, err != nil, "expected error")
-	Equals(t, "\"event: badevent\" not supported. Only \"event: apply\" is supported right now", err.Error())
+	Equals(t, "\"event: badevent\" not supported. Only \"event: apply\" and \"event: drift\" are supported for \"kind: slack\" right now", err.Error())
 }
 
 func TestNewWebhooksManager_NoKind(t *testing.T) {
@@ -100,7 +100,7 @@ func TestNewWebhooksManager_UnsupportedKind(t *testing.T) {
 	configs[0].Kind = unsupportedKind
 	_, err := webhooks.NewMultiWebhookSender(configs, client)
 	Assert(t, err != nil, "expected error")
-	Equals(t, "\"kind: badkind\" not supported. Only \"kind: slack\" is supported right now", err.Error())
+	Equals(t, "\"kind: badkind\" not supported. Only \"kind: slack\" and \"kind: http\" are supported right now", err.Error())
 }
 
 func TestNewWebhooksManager_NoConfigSuccess(t *testing.T) {
@@ -145,6 +145,86 @@ func TestNewWebhooksManager_MultipleConfigSuccess(t *testing.T) {
 	Equals(t, nConfigs, len(m.Webhooks)) // nolint: staticcheck
 }
 
+func TestNewWebhooksManager_DriftConfigSuccess(t *testing.T) {
+	t.Log("When there is a drift config, it should only be used for drift webhooks")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+	When(client.TokenIsSet()).ThenReturn(true)
+
+	driftConfig := validConfig
+	driftConfig.Event = webhooks.DriftEvent
+	m, err := webhooks.NewMultiWebhookSender([]webhooks.Config{validConfig, driftConfig}, client)
+	Ok(t, err)
+	Equals(t, 1, len(m.Webhooks))      // nolint: staticcheck
+	Equals(t, 1, len(m.DriftWebhooks)) // nolint: staticcheck
+}
+
+func TestNewWebhooksManager_HTTPConfigSuccess(t *testing.T) {
+	t.Log("When there is an http config, it should only be used for event webhooks")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+	When(client.TokenIsSet()).ThenReturn(true)
+
+	httpConfig := webhooks.Config{
+		Event:          webhooks.PlanEvent,
+		WorkspaceRegex: validRegex,
+		Kind:           webhooks.HTTPKind,
+		URL:            "https://example.com/hook",
+		Secret:         "secret",
+	}
+	m, err := webhooks.NewMultiWebhookSender([]webhooks.Config{validConfig, httpConfig}, client)
+	Ok(t, err)
+	Equals(t, 1, len(m.Webhooks))      // nolint: staticcheck
+	Equals(t, 1, len(m.EventWebhooks)) // nolint: staticcheck
+}
+
+func TestNewWebhooksManager_HTTPAuditConfigSuccess(t *testing.T) {
+	t.Log("When there is an http audit config, it should only be used for audit webhooks")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+
+	configs := []webhooks.Config{{
+		Event:          webhooks.AuditEvent,
+		WorkspaceRegex: validRegex,
+		Kind:           webhooks.HTTPKind,
+		URL:            "https://example.com/hook",
+	}}
+	m, err := webhooks.NewMultiWebhookSender(configs, client)
+	Ok(t, err)
+	Equals(t, 0, len(m.EventWebhooks)) // nolint: staticcheck
+	Equals(t, 1, len(m.AuditWebhooks)) // nolint: staticcheck
+	Equals(t, "https://example.com/hook", m.AuditWebhooks[0].URL)
+}
+
+func TestNewWebhooksManager_HTTPNoURL(t *testing.T) {
+	t.Log("When the url key is not specified in an http config, an error is returned")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+
+	configs := []webhooks.Config{{
+		Event:          webhooks.LockEvent,
+		WorkspaceRegex: validRegex,
+		Kind:           webhooks.HTTPKind,
+	}}
+	_, err := webhooks.NewMultiWebhookSender(configs, client)
+	ErrEquals(t, "must specify \"url\" if using a webhook of \"kind: http\"", err)
+}
+
+func TestNewWebhooksManager_HTTPUnsupportedEvent(t *testing.T) {
+	t.Log("When given an event that http webhooks don't support, an error is returned")
+	RegisterMockTestingT(t)
+	client := mocks.NewMockSlackClient()
+
+	configs := []webhooks.Config{{
+		Event:          webhooks.DriftEvent,
+		WorkspaceRegex: validRegex,
+		Kind:           webhooks.HTTPKind,
+		URL:            "https://example.com/hook",
+	}}
+	_, err := webhooks.NewMultiWebhookSender(configs, client)
+	ErrContains(t, "\"event: drift\" not supported", err)
+}
+
 func TestSend_SingleSuccess(t *testing.T) {
 	t.Log("Sending one webhook should succeed")
 	RegisterMockTestingT(t)
@@ -177,3 +257,47 @@ func TestSend_MultipleSuccess(t *testing.T) {
 		s.VerifyWasCalledOnce().Send(logger, result)
 	}
 }
+
+func TestSendDrift_MultipleSuccess(t *testing.T) {
+	t.Log("Sending multiple drift webhooks should succeed and not send apply webhooks")
+	RegisterMockTestingT(t)
+	applySender := mocks.NewMockSender()
+	driftSenders := []*mocks.MockSender{
+		mocks.NewMockSender(),
+		mocks.NewMockSender(),
+	}
+	manager := webhooks.MultiWebhookSender{
+		Webhooks:      []webhooks.Sender{applySender},
+		DriftWebhooks: []webhooks.Sender{driftSenders[0], driftSenders[1]},
+	}
+	logger := logging.NewNoopLogger(t)
+	result := webhooks.DriftResult{}
+	err := manager.SendDrift(logger, result)
+	Ok(t, err)
+	for _, s := range driftSenders {
+		s.VerifyWasCalledOnce().SendDrift(logger, result)
+	}
+	applySender.VerifyWasCalled(Never()).SendDrift(logger, result)
+}
+
+func TestSendEvent_MultipleSuccess(t *testing.T) {
+	t.Log("Sending multiple event webhooks should succeed and not send apply webhooks")
+	RegisterMockTestingT(t)
+	applySender := mocks.NewMockSender()
+	eventSenders := []*mocks.MockSender{
+		mocks.NewMockSender(),
+		mocks.NewMockSender(),
+	}
+	manager := webhooks.MultiWebhookSender{
+		Webhooks:      []webhooks.Sender{applySender},
+		EventWebhooks: []webhooks.Sender{eventSenders[0], eventSenders[1]},
+	}
+	logger := logging.NewNoopLogger(t)
+	result := webhooks.EventResult{Event: webhooks.PlanEvent}
+	err := manager.SendEvent(logger, result)
+	Ok(t, err)
+	for _, s := range eventSenders {
+		s.VerifyWasCalledOnce().SendEvent(logger, result)
+	}
+	applySender.VerifyWasCalled(Never()).SendEvent(logger, result)
+}
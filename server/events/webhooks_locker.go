@@ -0,0 +1,66 @@
+package events
+
+import (
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// WebhooksLocker is a decorator that sends a lock event webhook when a project
+// lock is acquired and an unlock event webhook when one is deleted.
+type WebhooksLocker struct {
+	locking.Locker
+	Webhooks WebhooksSender
+	Logger   logging.SimpleLogging
+}
+
+// TryLock sends a lock event if the lock was acquired by this call. Calls
+// for a project the pull already holds the lock for don't send an event.
+func (l *WebhooksLocker) TryLock(p models.Project, workspace string, pull models.PullRequest, user models.User) (locking.TryLockResponse, error) {
+	resp, err := l.Locker.TryLock(p, workspace, pull, user)
+	if err == nil && resp.LockAcquired {
+		l.sendEvent(webhooks.LockEvent, resp.CurrLock)
+	}
+	return resp, err
+}
+
+// Unlock sends an unlock event if a lock was deleted.
+func (l *WebhooksLocker) Unlock(key string) (*models.ProjectLock, error) {
+	lock, err := l.Locker.Unlock(key)
+	if err == nil && lock != nil {
+		l.sendEvent(webhooks.UnlockEvent, *lock)
+	}
+	return lock, err
+}
+
+// UnlockByPull sends an unlock event for each deleted lock.
+func (l *WebhooksLocker) UnlockByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error) {
+	locks, err := l.Locker.UnlockByPull(repoFullName, pullNum)
+	if err == nil {
+		for _, lock := range locks {
+			l.sendEvent(webhooks.UnlockEvent, lock)
+		}
+	}
+	return locks, err
+}
+
+func (l *WebhooksLocker) sendEvent(event string, lock models.ProjectLock) {
+	err := l.Webhooks.SendEvent(l.Logger, webhooks.EventResult{
+		ApplyResult: webhooks.ApplyResult{
+			Workspace: lock.Workspace,
+			Repo:      lock.Pull.BaseRepo,
+			Pull:      lock.Pull,
+			User:      lock.User,
+			Success:   true,
+			Directory: lock.Project.Path,
+		},
+		Event: event,
+		Time:  time.Now(),
+	})
+	if err != nil {
+		l.Logger.Warn("unable to send %s webhook: %s", event, err)
+	}
+}
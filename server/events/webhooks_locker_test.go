@@ -0,0 +1,127 @@
+package events_test
+
+import (
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/locking"
+	lockmocks "github.com/runatlantis/atlantis/server/core/locking/mocks"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/mocks"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestWebhooksLocker_TryLock(t *testing.T) {
+	RegisterMockTestingT(t)
+	repo := models.Repo{FullName: "owner/repo"}
+	project := models.NewProject(repo.FullName, "dir")
+	pull := models.PullRequest{Num: 1, BaseRepo: repo}
+	user := models.User{Username: "user"}
+	lock := models.ProjectLock{Project: project, Workspace: "default", Pull: pull, User: user}
+
+	cases := []struct {
+		Description  string
+		LockAcquired bool
+		ExpEvents    int
+	}{
+		{
+			Description:  "lock acquired",
+			LockAcquired: true,
+			ExpEvents:    1,
+		},
+		{
+			Description:  "lock already held",
+			LockAcquired: false,
+			ExpEvents:    0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Description, func(t *testing.T) {
+			underlying := lockmocks.NewMockLocker()
+			webhooksSender := mocks.NewMockWebhooksSender()
+			locker := &events.WebhooksLocker{
+				Locker:   underlying,
+				Webhooks: webhooksSender,
+				Logger:   logging.NewNoopLogger(t),
+			}
+			resp := locking.TryLockResponse{LockAcquired: c.LockAcquired, CurrLock: lock, LockKey: "key"}
+			When(underlying.TryLock(project, "default", pull, user)).ThenReturn(resp, nil)
+
+			actResp, err := locker.TryLock(project, "default", pull, user)
+			Ok(t, err)
+			Equals(t, resp, actResp)
+
+			verification := webhooksSender.VerifyWasCalled(Times(c.ExpEvents)).SendEvent(Any[logging.SimpleLogging](), Any[webhooks.EventResult]())
+			if c.ExpEvents == 0 {
+				return
+			}
+			_, event := verification.GetCapturedArguments()
+			Equals(t, webhooks.LockEvent, event.Event)
+			Equals(t, repo, event.Repo)
+			Equals(t, pull, event.Pull)
+			Equals(t, user, event.User)
+			Equals(t, "default", event.Workspace)
+			Equals(t, "dir", event.Directory)
+		})
+	}
+}
+
+func TestWebhooksLocker_Unlock(t *testing.T) {
+	RegisterMockTestingT(t)
+	underlying := lockmocks.NewMockLocker()
+	webhooksSender := mocks.NewMockWebhooksSender()
+	locker := &events.WebhooksLocker{
+		Locker:   underlying,
+		Webhooks: webhooksSender,
+		Logger:   logging.NewNoopLogger(t),
+	}
+	lock := models.ProjectLock{
+		Project:   models.NewProject("owner/repo", "dir"),
+		Workspace: "default",
+		Pull:      models.PullRequest{Num: 1, BaseRepo: models.Repo{FullName: "owner/repo"}},
+	}
+	When(underlying.Unlock("key")).ThenReturn(&lock, nil)
+	When(underlying.Unlock("missing")).ThenReturn(nil, nil)
+
+	t.Log("deleting a lock that doesn't exist shouldn't send an event")
+	_, err := locker.Unlock("missing")
+	Ok(t, err)
+	webhooksSender.VerifyWasCalled(Never()).SendEvent(Any[logging.SimpleLogging](), Any[webhooks.EventResult]())
+
+	actLock, err := locker.Unlock("key")
+	Ok(t, err)
+	Equals(t, &lock, actLock)
+	_, event := webhooksSender.VerifyWasCalledOnce().SendEvent(Any[logging.SimpleLogging](), Any[webhooks.EventResult]()).GetCapturedArguments()
+	Equals(t, webhooks.UnlockEvent, event.Event)
+	Equals(t, "dir", event.Directory)
+}
+
+func TestWebhooksLocker_UnlockByPull(t *testing.T) {
+	RegisterMockTestingT(t)
+	underlying := lockmocks.NewMockLocker()
+	webhooksSender := mocks.NewMockWebhooksSender()
+	locker := &events.WebhooksLocker{
+		Locker:   underlying,
+		Webhooks: webhooksSender,
+		Logger:   logging.NewNoopLogger(t),
+	}
+	locks := []models.ProjectLock{
+		{Project: models.NewProject("owner/repo", "dir1"), Workspace: "default"},
+		{Project: models.NewProject("owner/repo", "dir2"), Workspace: "default"},
+	}
+	When(underlying.UnlockByPull("owner/repo", 1)).ThenReturn(locks, nil)
+
+	actLocks, err := locker.UnlockByPull("owner/repo", 1)
+	Ok(t, err)
+	Equals(t, locks, actLocks)
+	_, sent := webhooksSender.VerifyWasCalled(Times(2)).SendEvent(Any[logging.SimpleLogging](), Any[webhooks.EventResult]()).GetAllCapturedArguments()
+	Equals(t, "dir1", sent[0].Directory)
+	Equals(t, "dir2", sent[1].Directory)
+	for _, e := range sent {
+		Equals(t, webhooks.UnlockEvent, e.Event)
+	}
+}
@@ -45,6 +45,11 @@ type WorkingDir interface {
 	// If workspace does not exist on disk, error will be of type os.IsNotExist.
 	GetWorkingDir(r models.Repo, p models.PullRequest, workspace string) (string, error)
 	HasDiverged(log logging.SimpleLogging, cloneDir string) bool
+	// GetBaseChangedFiles returns the files, relative to the repo root, that
+	// have changed on the base branch since it was merged into the clone at
+	// cloneDir. It returns nil if we aren't using the checkout merge strategy
+	// since only then do plans include the base branch.
+	GetBaseChangedFiles(log logging.SimpleLogging, cloneDir string, p models.PullRequest) ([]string, error)
 	GetPullDir(r models.Repo, p models.PullRequest) (string, error)
 	// Delete deletes the workspace for this repo and pull.
 	Delete(r models.Repo, p models.PullRequest) error
@@ -207,6 +212,38 @@ func (w *FileWorkspace) HasDiverged(log logging.SimpleLogging, cloneDir string)
 	return hasDiverged
 }
 
+func (w *FileWorkspace) GetBaseChangedFiles(log logging.SimpleLogging, cloneDir string, p models.PullRequest) ([]string, error) {
+	if !w.CheckoutMerge {
+		return nil, nil
+	}
+
+	// HEAD^1 is the commit of the base branch that we merged with, see
+	// forceClone.
+	cmds := [][]string{
+		{"git", "fetch", "origin", p.BaseBranch},
+		{"git", "diff", "--name-only", "HEAD^1", "FETCH_HEAD"},
+	}
+	var output []byte
+	for _, args := range cmds {
+		cmd := exec.Command(args[0], args[1:]...) // nolint: gosec
+		cmd.Dir = cloneDir
+		var err error
+		output, err = cmd.CombinedOutput()
+		if err != nil {
+			return nil, fmt.Errorf("running %s: %s: %s", strings.Join(args, " "), w.sanitizeGitCredentials(string(output), p.BaseRepo, p.BaseRepo), err)
+		}
+	}
+
+	var files []string
+	for _, file := range strings.Split(string(output), "\n") {
+		if file = strings.TrimSpace(file); file != "" {
+			files = append(files, file)
+		}
+	}
+	log.Debug("files changed on base branch %q since plan: %v", p.BaseBranch, files)
+	return files, nil
+}
+
 func (w *FileWorkspace) forceClone(log logging.SimpleLogging,
 	cloneDir string,
 	headRepo models.Repo,
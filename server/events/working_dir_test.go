@@ -581,3 +581,55 @@ func initRepo(t *testing.T) string {
 	runCmd(t, repoDir, "git", "branch", "branch")
 	return repoDir
 }
+
+func TestGetBaseChangedFiles(t *testing.T) {
+	// Initialize the git repo.
+	repoDir := initRepo(t)
+
+	// Simulate a PR.
+	runCmd(t, repoDir, "git", "checkout", "-b", "pr")
+	runCmd(t, repoDir, "touch", "file1")
+	runCmd(t, repoDir, "git", "add", "file1")
+	runCmd(t, repoDir, "git", "commit", "-m", "file1")
+
+	// Atlantis checkout of the PR.
+	prDir := repoDir + "/repos/0/default"
+	runCmd(t, repoDir, "mkdir", "-p", "repos/0/default")
+	runCmd(t, prDir, "git", "clone", "--branch", "main", "--single-branch", repoDir, ".")
+	runCmd(t, prDir, "git", "remote", "add", "head", repoDir)
+	runCmd(t, prDir, "git", "fetch", "head", "+refs/heads/pr")
+	runCmd(t, prDir, "git", "config", "--local", "user.email", "atlantisbot@runatlantis.io")
+	runCmd(t, prDir, "git", "config", "--local", "user.name", "atlantisbot")
+	runCmd(t, prDir, "git", "config", "--local", "commit.gpgsign", "false")
+	runCmd(t, prDir, "git", "merge", "-q", "--no-ff", "-m", "atlantis-merge", "FETCH_HEAD")
+
+	wd := &events.FileWorkspace{
+		DataDir:             repoDir,
+		CheckoutMerge:       true,
+		CheckoutDepth:       50,
+		GpgNoSigningEnabled: true,
+	}
+	pull := models.PullRequest{BaseBranch: "main"}
+
+	files, err := wd.GetBaseChangedFiles(logging.NewNoopLogger(t), prDir, pull)
+	Ok(t, err)
+	Equals(t, 0, len(files))
+
+	// Commit to main after the plan.
+	runCmd(t, repoDir, "git", "checkout", "main")
+	runCmd(t, repoDir, "mkdir", "-p", "project")
+	runCmd(t, repoDir, "touch", "project/main.tf")
+	runCmd(t, repoDir, "git", "add", "project/main.tf")
+	runCmd(t, repoDir, "git", "commit", "-m", "main.tf")
+
+	files, err = wd.GetBaseChangedFiles(logging.NewNoopLogger(t), prDir, pull)
+	Ok(t, err)
+	Equals(t, []string{"project/main.tf"}, files)
+
+	// Without the checkout merge strategy the plan doesn't include the base
+	// branch so nothing has changed.
+	wd.CheckoutMerge = false
+	files, err = wd.GetBaseChangedFiles(logging.NewNoopLogger(t), prDir, pull)
+	Ok(t, err)
+	Equals(t, 0, len(files))
+}
@@ -0,0 +1,152 @@
+package jobs
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// JobCanceller cancels running jobs, either for the cancel comment command or
+// from the job page. Jobs are registered while their steps run and the
+// processes started by the steps are tracked so they can be stopped.
+type JobCanceller struct {
+	mu      sync.Mutex
+	running map[string]*runningJob
+}
+
+// CancelledJob is a job that was cancelled.
+type CancelledJob struct {
+	JobID       string
+	ProjectName string
+	RepoRelDir  string
+	Workspace   string
+}
+
+type runningJob struct {
+	CancelledJob
+	repoFullName string
+	pullNum      int
+	// cancelledBy is who cancelled the job or "" if it hasn't been cancelled.
+	cancelledBy string
+	// stops holds the functions that stop the job's running processes.
+	stops   map[int]func(cancelledBy string)
+	nextKey int
+}
+
+// NewJobCanceller returns a JobCanceller with no running jobs.
+func NewJobCanceller() *JobCanceller {
+	return &JobCanceller{
+		running: make(map[string]*runningJob),
+	}
+}
+
+// StartJob records that the job for ctx is running until the returned
+// function is called.
+func (c *JobCanceller) StartJob(ctx command.ProjectContext) (finish func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running[ctx.JobID] = &runningJob{
+		CancelledJob: CancelledJob{
+			JobID:       ctx.JobID,
+			ProjectName: ctx.ProjectName,
+			RepoRelDir:  ctx.RepoRelDir,
+			Workspace:   ctx.Workspace,
+		},
+		repoFullName: ctx.BaseRepo.FullName,
+		pullNum:      ctx.Pull.Num,
+		stops:        make(map[int]func(string)),
+	}
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.running, ctx.JobID)
+	}
+}
+
+// TrackProcess records that stop stops a process running for the job for ctx
+// until the returned function is called. If the job has already been
+// cancelled, stop is called straight away. Processes for jobs that weren't
+// started with StartJob aren't tracked.
+func (c *JobCanceller) TrackProcess(ctx command.ProjectContext, stop func(cancelledBy string)) (untrack func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	job, ok := c.running[ctx.JobID]
+	if !ok {
+		return func() {}
+	}
+	if job.cancelledBy != "" {
+		go stop(job.cancelledBy)
+		return func() {}
+	}
+	key := job.nextKey
+	job.nextKey++
+	job.stops[key] = stop
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(job.stops, key)
+	}
+}
+
+// CancelledBy returns who cancelled the job with jobID or "" if it hasn't
+// been cancelled or isn't running.
+func (c *JobCanceller) CancelledBy(jobID string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if job, ok := c.running[jobID]; ok {
+		return job.cancelledBy
+	}
+	return ""
+}
+
+// CancelJob cancels the job with jobID on behalf of user. It returns false if
+// the job isn't running.
+func (c *JobCanceller) CancelJob(jobID string, user string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	job, ok := c.running[jobID]
+	if !ok {
+		return false
+	}
+	c.cancel(job, user)
+	return true
+}
+
+// CancelPullJobs cancels the running jobs for the pull request on behalf of
+// user and returns them.
+func (c *JobCanceller) CancelPullJobs(repoFullName string, pullNum int, user string) []CancelledJob {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var cancelled []CancelledJob
+	for _, job := range c.running {
+		if job.repoFullName != repoFullName || job.pullNum != pullNum {
+			continue
+		}
+		c.cancel(job, user)
+		cancelled = append(cancelled, job.CancelledJob)
+	}
+	// Sort so the jobs are listed in the same order every time.
+	sort.Slice(cancelled, func(i, j int) bool {
+		a, b := cancelled[i], cancelled[j]
+		if a.ProjectName != b.ProjectName {
+			return a.ProjectName < b.ProjectName
+		}
+		if a.RepoRelDir != b.RepoRelDir {
+			return a.RepoRelDir < b.RepoRelDir
+		}
+		return a.Workspace < b.Workspace
+	})
+	return cancelled
+}
+
+// cancel must be called with c.mu held.
+func (c *JobCanceller) cancel(job *runningJob, user string) {
+	if job.cancelledBy != "" {
+		return
+	}
+	job.cancelledBy = user
+	for _, stop := range job.stops {
+		go stop(user)
+	}
+}
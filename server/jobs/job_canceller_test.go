@@ -0,0 +1,63 @@
+package jobs_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/jobs"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func cancellerTestCtx(jobID string, pullNum int, dir string) command.ProjectContext {
+	return command.ProjectContext{
+		JobID:      jobID,
+		BaseRepo:   models.Repo{FullName: "owner/repo"},
+		Pull:       models.PullRequest{Num: pullNum},
+		RepoRelDir: dir,
+		Workspace:  "default",
+	}
+}
+
+func TestJobCanceller_CancelJob(t *testing.T) {
+	canceller := jobs.NewJobCanceller()
+	ctx := cancellerTestCtx("1", 1, "dir")
+
+	// Jobs that aren't running can't be cancelled.
+	Equals(t, false, canceller.CancelJob("1", "alice"))
+
+	finish := canceller.StartJob(ctx)
+	stopped := make(chan string, 1)
+	untrack := canceller.TrackProcess(ctx, func(cancelledBy string) { stopped <- cancelledBy })
+	defer untrack()
+
+	Equals(t, "", canceller.CancelledBy("1"))
+	Equals(t, true, canceller.CancelJob("1", "alice"))
+	Equals(t, "alice", <-stopped)
+	Equals(t, "alice", canceller.CancelledBy("1"))
+
+	// Processes started after the job was cancelled are stopped straight away.
+	canceller.TrackProcess(ctx, func(cancelledBy string) { stopped <- cancelledBy })
+	Equals(t, "alice", <-stopped)
+
+	finish()
+	Equals(t, "", canceller.CancelledBy("1"))
+	Equals(t, false, canceller.CancelJob("1", "alice"))
+}
+
+func TestJobCanceller_CancelPullJobs(t *testing.T) {
+	canceller := jobs.NewJobCanceller()
+	defer canceller.StartJob(cancellerTestCtx("1", 1, "b"))()
+	defer canceller.StartJob(cancellerTestCtx("2", 1, "a"))()
+	defer canceller.StartJob(cancellerTestCtx("3", 2, "a"))()
+
+	Equals(t, []jobs.CancelledJob{
+		{JobID: "2", RepoRelDir: "a", Workspace: "default"},
+		{JobID: "1", RepoRelDir: "b", Workspace: "default"},
+	}, canceller.CancelPullJobs("owner/repo", 1, "alice"))
+	Equals(t, "alice", canceller.CancelledBy("1"))
+	Equals(t, "alice", canceller.CancelledBy("2"))
+	Equals(t, "", canceller.CancelledBy("3"))
+
+	Equals(t, 0, len(canceller.CancelPullJobs("owner/other", 2, "alice")))
+}
@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"sort"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// JobStore persists the output of completed jobs so it can still be viewed
+// after Atlantis restarts.
+type JobStore interface {
+	// Save stores the output of a completed job, overwriting any job with the
+	// same ID.
+	Save(job StoredJob) error
+	// Get returns the job with jobID or nil if it isn't stored.
+	Get(jobID string) (*StoredJob, error)
+	// Prune deletes the jobs that exceed the store's retention.
+	Prune() error
+}
+
+// StoredJob is a completed job held by a JobStore.
+type StoredJob struct {
+	JobID string
+	JobInfo
+	Output []string
+	// Time is when the job completed.
+	Time time.Time
+}
+
+// Retention configures how long a JobStore keeps jobs.
+type Retention struct {
+	// MaxAge is how long jobs are kept after they complete. Zero or less
+	// means forever.
+	MaxAge time.Duration
+	// MaxSize is the total size in bytes of the jobs that are kept. The
+	// oldest jobs are deleted first. Zero or less means unlimited.
+	MaxSize int64
+}
+
+// StoredJobSummary is the information about a stored job that's needed to
+// apply a Retention.
+type StoredJobSummary struct {
+	JobID string
+	Time  time.Time
+	Size  int64
+}
+
+// Expired returns the IDs of the jobs that should be deleted: all jobs older
+// than MaxAge and then the oldest jobs until the total size of the remaining
+// jobs is at most MaxSize.
+func (r Retention) Expired(jobs []StoredJobSummary, now time.Time) []string {
+	sorted := make([]StoredJobSummary, len(jobs))
+	copy(sorted, jobs)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	var totalSize int64
+	for _, j := range sorted {
+		totalSize += j.Size
+	}
+
+	var expired []string
+	for _, j := range sorted {
+		tooOld := r.MaxAge > 0 && now.Sub(j.Time) > r.MaxAge
+		tooBig := r.MaxSize > 0 && totalSize > r.MaxSize
+		if !tooOld && !tooBig {
+			break
+		}
+		expired = append(expired, j.JobID)
+		totalSize -= j.Size
+	}
+	return expired
+}
+
+// NoopJobStore doesn't store jobs so job output is only held in memory.
+type NoopJobStore struct{}
+
+func (s *NoopJobStore) Save(job StoredJob) error {
+	return nil
+}
+
+func (s *NoopJobStore) Get(jobID string) (*StoredJob, error) {
+	return nil, nil
+}
+
+func (s *NoopJobStore) Prune() error {
+	return nil
+}
+
+// JobStorePruneJob prunes a JobStore. It implements scheduled.Job.
+type JobStorePruneJob struct {
+	Store  JobStore
+	Logger logging.SimpleLogging
+}
+
+func (j *JobStorePruneJob) Run() {
+	if err := j.Store.Prune(); err != nil {
+		j.Logger.Err("pruning job output: %s", err)
+	}
+}
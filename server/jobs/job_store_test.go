@@ -0,0 +1,60 @@
+package jobs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/jobs"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestRetention_Expired(t *testing.T) {
+	now := time.Now()
+	summaries := []jobs.StoredJobSummary{
+		{JobID: "new", Time: now.Add(-time.Hour), Size: 100},
+		{JobID: "old", Time: now.Add(-72 * time.Hour), Size: 100},
+		{JobID: "middle", Time: now.Add(-24 * time.Hour), Size: 100},
+	}
+
+	cases := []struct {
+		description string
+		retention   jobs.Retention
+		exp         []string
+	}{
+		{
+			description: "no limits",
+			retention:   jobs.Retention{},
+			exp:         nil,
+		},
+		{
+			description: "negative limits",
+			retention:   jobs.Retention{MaxAge: -1, MaxSize: -1},
+			exp:         nil,
+		},
+		{
+			description: "max age",
+			retention:   jobs.Retention{MaxAge: 48 * time.Hour},
+			exp:         []string{"old"},
+		},
+		{
+			description: "max size deletes oldest first",
+			retention:   jobs.Retention{MaxSize: 150},
+			exp:         []string{"old", "middle"},
+		},
+		{
+			description: "max size within limit",
+			retention:   jobs.Retention{MaxSize: 300},
+			exp:         nil,
+		},
+		{
+			description: "max age and max size",
+			retention:   jobs.Retention{MaxAge: 48 * time.Hour, MaxSize: 250},
+			exp:         []string{"old"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			Equals(t, c.exp, c.retention.Expired(summaries, now))
+		})
+	}
+}
@@ -18,6 +18,9 @@ type ProjectStatusUpdater interface {
 	// UpdateProject sets the commit status for the project represented by
 	// ctx.
 	UpdateProject(ctx command.ProjectContext, cmdName command.Name, status models.CommitStatus, url string, result *command.ProjectResult) error
+	// UpdateProjectQueued sets the commit status for the project represented
+	// by ctx to show that its cmdName run is waiting at position in the queue.
+	UpdateProjectQueued(ctx command.ProjectContext, cmdName command.Name, position int, url string) error
 }
 
 type JobURLSetter struct {
@@ -40,3 +43,30 @@ func (j *JobURLSetter) SetJobURLWithStatus(ctx command.ProjectContext, cmdName c
 	}
 	return j.projectStatusUpdater.UpdateProject(ctx, cmdName, status, url, result)
 }
+
+// RunQueued shows the position of the project's run in its commit status. Only
+// plans and applies have project commit statuses so other commands are
+// ignored.
+func (j *JobURLSetter) RunQueued(ctx command.ProjectContext, position int) error {
+	if !hasProjectStatus(ctx.CommandName) {
+		return nil
+	}
+	url, err := j.projectJobURLGenerator.GenerateProjectJobURL(ctx)
+	if err != nil {
+		return err
+	}
+	return j.projectStatusUpdater.UpdateProjectQueued(ctx, ctx.CommandName, position, url)
+}
+
+// RunDequeued sets the project's commit status back to in progress once its
+// run has left the queue.
+func (j *JobURLSetter) RunDequeued(ctx command.ProjectContext) error {
+	if !hasProjectStatus(ctx.CommandName) {
+		return nil
+	}
+	return j.SetJobURLWithStatus(ctx, ctx.CommandName, models.PendingCommitStatus, nil)
+}
+
+func hasProjectStatus(cmdName command.Name) bool {
+	return cmdName == command.Plan || cmdName == command.Apply
+}
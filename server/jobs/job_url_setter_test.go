@@ -42,4 +42,35 @@ func TestJobURLSetter(t *testing.T) {
 		err := jobURLSetter.SetJobURLWithStatus(ctx, command.Plan, models.PendingCommitStatus, nil)
 		assert.Error(t, err)
 	})
+
+	t.Run("update project status with queue position", func(t *testing.T) {
+		RegisterMockTestingT(t)
+		projectStatusUpdater := mocks.NewMockProjectStatusUpdater()
+		projectJobURLGenerator := mocks.NewMockProjectJobURLGenerator()
+		jobURLSetter := jobs.NewJobURLSetter(projectJobURLGenerator, projectStatusUpdater)
+		planCtx := ctx
+		planCtx.CommandName = command.Plan
+
+		When(projectJobURLGenerator.GenerateProjectJobURL(Eq[command.ProjectContext](planCtx))).ThenReturn("url-to-project-jobs", nil)
+		Ok(t, jobURLSetter.RunQueued(planCtx, 2))
+		Ok(t, jobURLSetter.RunDequeued(planCtx))
+
+		projectStatusUpdater.VerifyWasCalledOnce().UpdateProjectQueued(planCtx, command.Plan, 2, "url-to-project-jobs")
+		projectStatusUpdater.VerifyWasCalledOnce().UpdateProject(planCtx, command.Plan, models.PendingCommitStatus, "url-to-project-jobs", nil)
+	})
+
+	t.Run("queue position ignored for commands without project status", func(t *testing.T) {
+		RegisterMockTestingT(t)
+		projectStatusUpdater := mocks.NewMockProjectStatusUpdater()
+		projectJobURLGenerator := mocks.NewMockProjectJobURLGenerator()
+		jobURLSetter := jobs.NewJobURLSetter(projectJobURLGenerator, projectStatusUpdater)
+		importCtx := ctx
+		importCtx.CommandName = command.Import
+
+		Ok(t, jobURLSetter.RunQueued(importCtx, 2))
+		Ok(t, jobURLSetter.RunDequeued(importCtx))
+
+		projectStatusUpdater.VerifyWasCalled(Never()).UpdateProjectQueued(Any[command.ProjectContext](), Any[command.Name](), Any[int](), Any[string]())
+		projectStatusUpdater.VerifyWasCalled(Never()).UpdateProject(Any[command.ProjectContext](), Any[command.Name](), Any[models.CommitStatus](), Any[string](), Any[*command.ProjectResult]())
+	})
 }
@@ -0,0 +1,125 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const localJobStoreExt = ".json"
+
+// jobIDRegex matches valid job IDs. Job IDs are used as file names so they
+// must not contain path separators.
+var jobIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// LocalJobStore stores each job as a JSON file in a directory.
+type LocalJobStore struct {
+	dir       string
+	retention Retention
+}
+
+// NewLocalJobStore returns a LocalJobStore that stores jobs in dir, creating
+// it if it doesn't exist.
+func NewLocalJobStore(dir string, retention Retention) (*LocalJobStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "creating %s", dir)
+	}
+	return &LocalJobStore{
+		dir:       dir,
+		retention: retention,
+	}, nil
+}
+
+func (s *LocalJobStore) Save(job StoredJob) error {
+	path, err := s.path(job.JobID)
+	if err != nil {
+		return err
+	}
+	serialized, err := json.Marshal(job)
+	if err != nil {
+		return errors.Wrap(err, "serializing")
+	}
+
+	// Write to a temporary file first so a partially written job is never
+	// served.
+	tmp, err := os.CreateTemp(s.dir, job.JobID+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "creating temporary file")
+	}
+	defer os.Remove(tmp.Name()) // nolint: errcheck
+	if _, err := tmp.Write(serialized); err != nil {
+		tmp.Close() // nolint: errcheck
+		return errors.Wrapf(err, "writing %s", tmp.Name())
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "closing %s", tmp.Name())
+	}
+	return errors.Wrapf(os.Rename(tmp.Name(), path), "renaming %s", tmp.Name())
+}
+
+func (s *LocalJobStore) Get(jobID string) (*StoredJob, error) {
+	if !jobIDRegex.MatchString(jobID) {
+		return nil, nil
+	}
+	path, err := s.path(jobID)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", path)
+	}
+	var job StoredJob
+	if err := json.Unmarshal(contents, &job); err != nil {
+		return nil, errors.Wrapf(err, "deserializing %s", path)
+	}
+	return &job, nil
+}
+
+func (s *LocalJobStore) Prune() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", s.dir)
+	}
+
+	var summaries []StoredJobSummary
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != localJobStoreExt {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			// The file was deleted since the directory was read.
+			continue
+		}
+		summaries = append(summaries, StoredJobSummary{
+			JobID: strings.TrimSuffix(e.Name(), localJobStoreExt),
+			Time:  info.ModTime(),
+			Size:  info.Size(),
+		})
+	}
+
+	for _, jobID := range s.retention.Expired(summaries, time.Now()) {
+		path := filepath.Join(s.dir, jobID+localJobStoreExt)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "deleting %s", path)
+		}
+	}
+	return nil
+}
+
+func (s *LocalJobStore) path(jobID string) (string, error) {
+	if !jobIDRegex.MatchString(jobID) {
+		return "", fmt.Errorf("invalid job id %q", jobID)
+	}
+	return filepath.Join(s.dir, jobID+localJobStoreExt), nil
+}
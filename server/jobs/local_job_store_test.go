@@ -0,0 +1,69 @@
+package jobs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/jobs"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestLocalJobStore_SaveGet(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "jobs")
+	store, err := jobs.NewLocalJobStore(dir, jobs.Retention{})
+	Ok(t, err)
+
+	job := jobs.StoredJob{
+		JobID: "1234-abcd",
+		JobInfo: jobs.JobInfo{
+			PullInfo: jobs.PullInfo{
+				PullNum:     1,
+				Repo:        "repo",
+				ProjectName: "project",
+				Workspace:   "default",
+			},
+			HeadCommit: "abc123",
+		},
+		Output: []string{"line 1", "line 2"},
+		Time:   time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	Ok(t, store.Save(job))
+
+	stored, err := store.Get(job.JobID)
+	Ok(t, err)
+	Equals(t, &job, stored)
+
+	t.Log("jobs that aren't stored should return nil")
+	stored, err = store.Get("unknown")
+	Ok(t, err)
+	Assert(t, stored == nil, "exp nil")
+
+	t.Log("job ids that aren't valid file names should return nil")
+	stored, err = store.Get("../jobs/1234-abcd")
+	Ok(t, err)
+	Assert(t, stored == nil, "exp nil")
+	ErrContains(t, "invalid job id", store.Save(jobs.StoredJob{JobID: "../escape"}))
+}
+
+func TestLocalJobStore_Prune(t *testing.T) {
+	dir := t.TempDir()
+	store, err := jobs.NewLocalJobStore(dir, jobs.Retention{MaxAge: 48 * time.Hour})
+	Ok(t, err)
+
+	for _, jobID := range []string{"old", "new"} {
+		Ok(t, store.Save(jobs.StoredJob{JobID: jobID, Output: []string{"output"}}))
+	}
+	old := time.Now().Add(-72 * time.Hour)
+	Ok(t, os.Chtimes(filepath.Join(dir, "old.json"), old, old))
+
+	Ok(t, store.Prune())
+
+	stored, err := store.Get("old")
+	Ok(t, err)
+	Assert(t, stored == nil, "exp old job to be pruned")
+	stored, err = store.Get("new")
+	Ok(t, err)
+	Assert(t, stored != nil, "exp new job to be kept")
+}
@@ -66,6 +66,21 @@ func (mock *MockProjectCommandOutputHandler) IsKeyExists(key string) bool {
 	return ret0
 }
 
+func (mock *MockProjectCommandOutputHandler) Jobs() []jobs.Job {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockProjectCommandOutputHandler().")
+	}
+	params := []pegomock.Param{}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("Jobs", params, []reflect.Type{reflect.TypeOf((*[]jobs.Job)(nil)).Elem()})
+	var ret0 []jobs.Job
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].([]jobs.Job)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockProjectCommandOutputHandler) Register(jobID string, receiver chan string) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockProjectCommandOutputHandler().")
@@ -229,6 +244,23 @@ func (c *MockProjectCommandOutputHandler_IsKeyExists_OngoingVerification) GetAll
 	return
 }
 
+func (verifier *VerifierMockProjectCommandOutputHandler) Jobs() *MockProjectCommandOutputHandler_Jobs_OngoingVerification {
+	params := []pegomock.Param{}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "Jobs", params, verifier.timeout)
+	return &MockProjectCommandOutputHandler_Jobs_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockProjectCommandOutputHandler_Jobs_OngoingVerification struct {
+	mock              *MockProjectCommandOutputHandler
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockProjectCommandOutputHandler_Jobs_OngoingVerification) GetCapturedArguments() {
+}
+
+func (c *MockProjectCommandOutputHandler_Jobs_OngoingVerification) GetAllCapturedArguments() {
+}
+
 func (verifier *VerifierMockProjectCommandOutputHandler) Register(jobID string, receiver chan string) *MockProjectCommandOutputHandler_Register_OngoingVerification {
 	params := []pegomock.Param{jobID, receiver}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "Register", params, verifier.timeout)
@@ -41,6 +41,21 @@ func (mock *MockProjectStatusUpdater) UpdateProject(ctx command.ProjectContext,
 	return ret0
 }
 
+func (mock *MockProjectStatusUpdater) UpdateProjectQueued(ctx command.ProjectContext, cmdName command.Name, position int, url string) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockProjectStatusUpdater().")
+	}
+	params := []pegomock.Param{ctx, cmdName, position, url}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("UpdateProjectQueued", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockProjectStatusUpdater) VerifyWasCalledOnce() *VerifierMockProjectStatusUpdater {
 	return &VerifierMockProjectStatusUpdater{
 		mock:                   mock,
@@ -120,3 +135,42 @@ func (c *MockProjectStatusUpdater_UpdateProject_OngoingVerification) GetAllCaptu
 	}
 	return
 }
+
+func (verifier *VerifierMockProjectStatusUpdater) UpdateProjectQueued(ctx command.ProjectContext, cmdName command.Name, position int, url string) *MockProjectStatusUpdater_UpdateProjectQueued_OngoingVerification {
+	params := []pegomock.Param{ctx, cmdName, position, url}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UpdateProjectQueued", params, verifier.timeout)
+	return &MockProjectStatusUpdater_UpdateProjectQueued_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockProjectStatusUpdater_UpdateProjectQueued_OngoingVerification struct {
+	mock              *MockProjectStatusUpdater
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockProjectStatusUpdater_UpdateProjectQueued_OngoingVerification) GetCapturedArguments() (command.ProjectContext, command.Name, int, string) {
+	ctx, cmdName, position, url := c.GetAllCapturedArguments()
+	return ctx[len(ctx)-1], cmdName[len(cmdName)-1], position[len(position)-1], url[len(url)-1]
+}
+
+func (c *MockProjectStatusUpdater_UpdateProjectQueued_OngoingVerification) GetAllCapturedArguments() (_param0 []command.ProjectContext, _param1 []command.Name, _param2 []int, _param3 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]command.ProjectContext, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(command.ProjectContext)
+		}
+		_param1 = make([]command.Name, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(command.Name)
+		}
+		_param2 = make([]int, len(c.methodInvocations))
+		for u, param := range params[2] {
+			_param2[u] = param.(int)
+		}
+		_param3 = make([]string, len(c.methodInvocations))
+		for u, param := range params[3] {
+			_param3[u] = param.(string)
+		}
+	}
+	return
+}
@@ -2,6 +2,7 @@ package jobs
 
 import (
 	"sync"
+	"time"
 
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
@@ -11,6 +12,7 @@ import (
 type OutputBuffer struct {
 	OperationComplete bool
 	Buffer            []string
+	JobInfo           JobInfo
 }
 
 type PullInfo struct {
@@ -25,6 +27,18 @@ type JobInfo struct {
 	HeadCommit string
 }
 
+// Job is a summary of a job whose output is held by the output handler.
+type Job struct {
+	JobID string
+	PullInfo
+	// Complete is true once the job's operation has finished.
+	Complete bool
+}
+
+// completedJobsQueueSize is how many completed jobs can wait to be saved to
+// the job store before Handle blocks.
+const completedJobsQueueSize = 100
+
 type ProjectCmdOutputLine struct {
 	JobID             string
 	JobInfo           JobInfo
@@ -43,10 +57,17 @@ type AsyncProjectCommandOutputHandler struct {
 	receiverBuffers     map[string]map[chan string]bool
 	receiverBuffersLock sync.RWMutex
 
+	// jobStore persists the output of completed jobs so it's still
+	// available after the buffers are cleaned up or Atlantis restarts.
+	jobStore JobStore
+	// completedJobs holds the completed jobs waiting to be saved to jobStore.
+	completedJobs chan StoredJob
+
 	logger logging.SimpleLogging
 
 	// Tracks all the jobs for a pull request which is used for clean up after a pull request is closed.
-	pullToJobMapping sync.Map
+	pullToJobMapping     map[PullInfo]map[string]bool
+	pullToJobMappingLock sync.RWMutex
 }
 
 //go:generate pegomock generate --package mocks -o mocks/mock_project_command_output_handler.go ProjectCommandOutputHandler
@@ -66,6 +87,9 @@ type ProjectCommandOutputHandler interface {
 
 	IsKeyExists(key string) bool
 
+	// Jobs returns all jobs that haven't been cleaned up yet.
+	Jobs() []Job
+
 	// Listens for msg from channel
 	Handle()
 
@@ -75,22 +99,28 @@ type ProjectCommandOutputHandler interface {
 
 func NewAsyncProjectCommandOutputHandler(
 	projectCmdOutput chan *ProjectCmdOutputLine,
+	jobStore JobStore,
 	logger logging.SimpleLogging,
 ) ProjectCommandOutputHandler {
 	return &AsyncProjectCommandOutputHandler{
 		projectCmdOutput:     projectCmdOutput,
+		jobStore:             jobStore,
+		completedJobs:        make(chan StoredJob, completedJobsQueueSize),
 		logger:               logger,
 		receiverBuffers:      map[string]map[chan string]bool{},
 		projectOutputBuffers: map[string]OutputBuffer{},
-		pullToJobMapping:     sync.Map{},
+		pullToJobMapping:     map[PullInfo]map[string]bool{},
 	}
 }
 
 func (p *AsyncProjectCommandOutputHandler) IsKeyExists(key string) bool {
 	p.projectOutputBuffersLock.RLock()
-	defer p.projectOutputBuffersLock.RUnlock()
 	_, ok := p.projectOutputBuffers[key]
-	return ok
+	p.projectOutputBuffersLock.RUnlock()
+	if ok {
+		return true
+	}
+	return p.getStoredJob(key) != nil
 }
 
 func (p *AsyncProjectCommandOutputHandler) Send(ctx command.ProjectContext, msg string, operationComplete bool) {
@@ -130,6 +160,9 @@ func (p *AsyncProjectCommandOutputHandler) Register(jobID string, receiver chan
 }
 
 func (p *AsyncProjectCommandOutputHandler) Handle() {
+	go p.saveCompletedJobs()
+	defer close(p.completedJobs)
+
 	for msg := range p.projectCmdOutput {
 		if msg.OperationComplete {
 			p.completeJob(msg.JobID)
@@ -137,19 +170,48 @@ func (p *AsyncProjectCommandOutputHandler) Handle() {
 		}
 
 		// Add job to pullToJob mapping
-		if _, ok := p.pullToJobMapping.Load(msg.JobInfo.PullInfo); !ok {
-			p.pullToJobMapping.Store(msg.JobInfo.PullInfo, map[string]bool{})
+		p.pullToJobMappingLock.Lock()
+		if _, ok := p.pullToJobMapping[msg.JobInfo.PullInfo]; !ok {
+			p.pullToJobMapping[msg.JobInfo.PullInfo] = map[string]bool{}
 		}
-		value, _ := p.pullToJobMapping.Load(msg.JobInfo.PullInfo)
-		jobMapping := value.(map[string]bool)
-		jobMapping[msg.JobID] = true
+		p.pullToJobMapping[msg.JobInfo.PullInfo][msg.JobID] = true
+		p.pullToJobMappingLock.Unlock()
 
 		// Forward new message to all receiver channels and output buffer
-		p.writeLogLine(msg.JobID, msg.Line)
+		p.writeLogLine(msg.JobID, msg.JobInfo, msg.Line)
 	}
 }
 
 func (p *AsyncProjectCommandOutputHandler) completeJob(jobID string) {
+	outputBuffer, ok := p.markJobComplete(jobID)
+	if !ok {
+		return
+	}
+
+	// The job is saved by saveCompletedJobs so a slow store doesn't hold up
+	// the output of other jobs. Its output stays in the buffer until the pull
+	// is cleaned up.
+	p.completedJobs <- StoredJob{
+		JobID:   jobID,
+		JobInfo: outputBuffer.JobInfo,
+		Output:  outputBuffer.Buffer,
+		Time:    time.Now(),
+	}
+}
+
+// saveCompletedJobs saves the completed jobs to the job store until Handle
+// returns.
+func (p *AsyncProjectCommandOutputHandler) saveCompletedJobs() {
+	for job := range p.completedJobs {
+		if err := p.jobStore.Save(job); err != nil {
+			p.logger.Err("storing output of job %s: %s", job.JobID, err)
+		}
+	}
+}
+
+// markJobComplete marks the job complete and closes its receiver channels.
+// It returns the job's output buffer and false if the job has no output.
+func (p *AsyncProjectCommandOutputHandler) markJobComplete(jobID string) (OutputBuffer, bool) {
 	p.projectOutputBuffersLock.Lock()
 	p.receiverBuffersLock.Lock()
 	defer func() {
@@ -158,7 +220,8 @@ func (p *AsyncProjectCommandOutputHandler) completeJob(jobID string) {
 	}()
 
 	// Update operation status to complete
-	if outputBuffer, ok := p.projectOutputBuffers[jobID]; ok {
+	outputBuffer, ok := p.projectOutputBuffers[jobID]
+	if ok {
 		outputBuffer.OperationComplete = true
 		p.projectOutputBuffers[jobID] = outputBuffer
 	}
@@ -170,13 +233,36 @@ func (p *AsyncProjectCommandOutputHandler) completeJob(jobID string) {
 		}
 	}
 
+	return outputBuffer, ok
+}
+
+// getStoredJob returns the job from the job store or nil if it isn't
+// stored.
+func (p *AsyncProjectCommandOutputHandler) getStoredJob(jobID string) *StoredJob {
+	job, err := p.jobStore.Get(jobID)
+	if err != nil {
+		p.logger.Err("getting stored output of job %s: %s", jobID, err)
+		return nil
+	}
+	return job
 }
 
 func (p *AsyncProjectCommandOutputHandler) addChan(ch chan string, jobID string) {
 	p.projectOutputBuffersLock.RLock()
-	outputBuffer := p.projectOutputBuffers[jobID]
+	outputBuffer, ok := p.projectOutputBuffers[jobID]
 	p.projectOutputBuffersLock.RUnlock()
 
+	// Jobs that aren't in memory anymore, ex. because Atlantis restarted, are
+	// streamed from the job store.
+	if !ok {
+		if job := p.getStoredJob(jobID); job != nil {
+			outputBuffer = OutputBuffer{
+				OperationComplete: true,
+				Buffer:            job.Output,
+			}
+		}
+	}
+
 	for _, line := range outputBuffer.Buffer {
 		ch <- line
 	}
@@ -198,7 +284,7 @@ func (p *AsyncProjectCommandOutputHandler) addChan(ch chan string, jobID string)
 }
 
 // Add log line to buffer and send to all current channels
-func (p *AsyncProjectCommandOutputHandler) writeLogLine(jobID string, line string) {
+func (p *AsyncProjectCommandOutputHandler) writeLogLine(jobID string, jobInfo JobInfo, line string) {
 	p.receiverBuffersLock.Lock()
 	for ch := range p.receiverBuffers[jobID] {
 		select {
@@ -213,7 +299,8 @@ func (p *AsyncProjectCommandOutputHandler) writeLogLine(jobID string, line strin
 	p.projectOutputBuffersLock.Lock()
 	if _, ok := p.projectOutputBuffers[jobID]; !ok {
 		p.projectOutputBuffers[jobID] = OutputBuffer{
-			Buffer: []string{},
+			Buffer:  []string{},
+			JobInfo: jobInfo,
 		}
 	}
 	outputBuffer := p.projectOutputBuffers[jobID]
@@ -232,35 +319,57 @@ func (p *AsyncProjectCommandOutputHandler) Deregister(jobID string, ch chan stri
 }
 
 func (p *AsyncProjectCommandOutputHandler) GetReceiverBufferForPull(jobID string) map[chan string]bool {
+	p.receiverBuffersLock.RLock()
+	defer p.receiverBuffersLock.RUnlock()
 	return p.receiverBuffers[jobID]
 }
 
 func (p *AsyncProjectCommandOutputHandler) GetProjectOutputBuffer(jobID string) OutputBuffer {
+	p.projectOutputBuffersLock.RLock()
+	defer p.projectOutputBuffersLock.RUnlock()
 	return p.projectOutputBuffers[jobID]
 }
 
 func (p *AsyncProjectCommandOutputHandler) GetJobIDMapForPull(pullInfo PullInfo) map[string]bool {
-	if value, ok := p.pullToJobMapping.Load(pullInfo); ok {
-		return value.(map[string]bool)
-	}
-	return nil
+	p.pullToJobMappingLock.RLock()
+	defer p.pullToJobMappingLock.RUnlock()
+	return p.pullToJobMapping[pullInfo]
 }
 
-func (p *AsyncProjectCommandOutputHandler) CleanUp(pullInfo PullInfo) {
-	if value, ok := p.pullToJobMapping.Load(pullInfo); ok {
-		jobMapping := value.(map[string]bool)
-		for jobID := range jobMapping {
-			p.projectOutputBuffersLock.Lock()
-			delete(p.projectOutputBuffers, jobID)
-			p.projectOutputBuffersLock.Unlock()
+func (p *AsyncProjectCommandOutputHandler) Jobs() []Job {
+	p.pullToJobMappingLock.RLock()
+	defer p.pullToJobMappingLock.RUnlock()
+	p.projectOutputBuffersLock.RLock()
+	defer p.projectOutputBuffersLock.RUnlock()
 
-			p.receiverBuffersLock.Lock()
-			delete(p.receiverBuffers, jobID)
-			p.receiverBuffersLock.Unlock()
+	var jobs []Job
+	for pullInfo, jobMapping := range p.pullToJobMapping {
+		for jobID := range jobMapping {
+			jobs = append(jobs, Job{
+				JobID:    jobID,
+				PullInfo: pullInfo,
+				Complete: p.projectOutputBuffers[jobID].OperationComplete,
+			})
 		}
+	}
+	return jobs
+}
 
-		// Remove job mapping
-		p.pullToJobMapping.Delete(pullInfo)
+func (p *AsyncProjectCommandOutputHandler) CleanUp(pullInfo PullInfo) {
+	// Remove job mapping
+	p.pullToJobMappingLock.Lock()
+	jobMapping := p.pullToJobMapping[pullInfo]
+	delete(p.pullToJobMapping, pullInfo)
+	p.pullToJobMappingLock.Unlock()
+
+	for jobID := range jobMapping {
+		p.projectOutputBuffersLock.Lock()
+		delete(p.projectOutputBuffers, jobID)
+		p.projectOutputBuffersLock.Unlock()
+
+		p.receiverBuffersLock.Lock()
+		delete(p.receiverBuffers, jobID)
+		p.receiverBuffersLock.Unlock()
 	}
 }
 
@@ -285,3 +394,7 @@ func (p *NoopProjectOutputHandler) CleanUp(pullInfo PullInfo) {
 func (p *NoopProjectOutputHandler) IsKeyExists(key string) bool {
 	return false
 }
+
+func (p *NoopProjectOutputHandler) Jobs() []Job {
+	return nil
+}
@@ -1,6 +1,7 @@
 package jobs_test
 
 import (
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -47,6 +48,7 @@ func createProjectCommandOutputHandler(t *testing.T) jobs.ProjectCommandOutputHa
 	prjCmdOutputChan := make(chan *jobs.ProjectCmdOutputLine)
 	prjCmdOutputHandler := jobs.NewAsyncProjectCommandOutputHandler(
 		prjCmdOutputChan,
+		&jobs.NoopJobStore{},
 		logger,
 	)
 
@@ -249,4 +251,144 @@ func TestProjectCommandOutputHandler(t *testing.T) {
 
 		assert.True(t, <-opComplete)
 	})
+	t.Run("list active and completed jobs", func(t *testing.T) {
+		projectOutputHandler := createProjectCommandOutputHandler(t)
+		completedCtx := createTestProjectCmdContext(t)
+		completedCtx.JobID = "5678"
+
+		projectOutputHandler.Send(ctx, Msg, false)
+		projectOutputHandler.Send(completedCtx, Msg, false)
+		projectOutputHandler.Send(completedCtx, "", true)
+
+		// Wait for the handler to process the message
+		time.Sleep(10 * time.Millisecond)
+
+		pullInfo := jobs.PullInfo{
+			PullNum:     ctx.Pull.Num,
+			Repo:        ctx.BaseRepo.Name,
+			ProjectName: ctx.ProjectName,
+			Workspace:   ctx.Workspace,
+		}
+		assert.ElementsMatch(t, []jobs.Job{
+			{JobID: ctx.JobID, PullInfo: pullInfo, Complete: false},
+			{JobID: completedCtx.JobID, PullInfo: pullInfo, Complete: true},
+		}, projectOutputHandler.Jobs())
+	})
+
+	t.Run("list jobs while jobs start", func(t *testing.T) {
+		projectOutputHandler := createProjectCommandOutputHandler(t)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				jobCtx := createTestProjectCmdContext(t)
+				jobCtx.JobID = strconv.Itoa(i)
+				projectOutputHandler.Send(jobCtx, Msg, false)
+			}
+		}()
+		for {
+			select {
+			case <-done:
+				assert.Eventually(t, func() bool {
+					return len(projectOutputHandler.Jobs()) == 100
+				}, time.Second, 10*time.Millisecond)
+				return
+			default:
+				projectOutputHandler.Jobs()
+			}
+		}
+	})
+
+	t.Run("serve completed jobs from the job store after clean up", func(t *testing.T) {
+		store, err := jobs.NewLocalJobStore(t.TempDir(), jobs.Retention{})
+		Ok(t, err)
+		prjCmdOutputChan := make(chan *jobs.ProjectCmdOutputLine)
+		projectOutputHandler := jobs.NewAsyncProjectCommandOutputHandler(
+			prjCmdOutputChan,
+			store,
+			logging.NewNoopLogger(t),
+		)
+		go projectOutputHandler.Handle()
+
+		projectOutputHandler.Send(ctx, Msg, false)
+		projectOutputHandler.Send(ctx, "", true)
+
+		// Wait for the handler to process the message
+		time.Sleep(10 * time.Millisecond)
+
+		pullInfo := jobs.PullInfo{
+			PullNum:     ctx.Pull.Num,
+			Repo:        ctx.BaseRepo.Name,
+			ProjectName: ctx.ProjectName,
+			Workspace:   ctx.Workspace,
+		}
+		// The job is saved in the background.
+		var stored *jobs.StoredJob
+		assert.Eventually(t, func() bool {
+			stored, err = store.Get(ctx.JobID)
+			return err == nil && stored != nil
+		}, time.Second, 10*time.Millisecond)
+		Ok(t, err)
+		Equals(t, []string{Msg}, stored.Output)
+		Equals(t, pullInfo, stored.PullInfo)
+		Equals(t, ctx.Pull.HeadCommit, stored.HeadCommit)
+
+		projectOutputHandler.CleanUp(pullInfo)
+		Assert(t, projectOutputHandler.IsKeyExists(ctx.JobID), "exp stored job to exist")
+		Assert(t, !projectOutputHandler.IsKeyExists("unknown"), "exp unknown job not to exist")
+
+		ch := make(chan string, 2)
+		projectOutputHandler.Register(ctx.JobID, ch)
+		var received []string
+		for msg := range ch {
+			received = append(received, msg)
+		}
+		Equals(t, []string{Msg}, received)
+	})
+
+	t.Run("don't wait for the job store", func(t *testing.T) {
+		store := &blockingJobStore{saving: make(chan jobs.StoredJob), release: make(chan struct{})}
+		defer close(store.release)
+		prjCmdOutputChan := make(chan *jobs.ProjectCmdOutputLine)
+		projectOutputHandler := jobs.NewAsyncProjectCommandOutputHandler(
+			prjCmdOutputChan,
+			store,
+			logging.NewNoopLogger(t),
+		)
+		go projectOutputHandler.Handle()
+
+		projectOutputHandler.Send(ctx, Msg, false)
+		projectOutputHandler.Send(ctx, "", true)
+		saved := <-store.saving
+		Equals(t, ctx.JobID, saved.JobID)
+		Equals(t, []string{Msg}, saved.Output)
+
+		// The output of other jobs is still handled while the save blocks.
+		otherCtx := createTestProjectCmdContext(t)
+		otherCtx.JobID = "5678"
+		ch := make(chan string, 1)
+		projectOutputHandler.Register(otherCtx.JobID, ch)
+		projectOutputHandler.Send(otherCtx, Msg, false)
+		select {
+		case msg := <-ch:
+			Equals(t, Msg, msg)
+		case <-time.After(time.Second):
+			t.Fatal("exp output to be handled while the job store is saving")
+		}
+	})
+}
+
+// blockingJobStore sends the jobs it saves to saving and blocks until release
+// is closed.
+type blockingJobStore struct {
+	jobs.NoopJobStore
+	saving  chan jobs.StoredJob
+	release chan struct{}
+}
+
+func (b *blockingJobStore) Save(job jobs.StoredJob) error {
+	b.saving <- job
+	<-b.release
+	return nil
 }
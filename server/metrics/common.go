@@ -5,4 +5,7 @@ const (
 	ExecutionSuccessMetric = "execution_success"
 	ExecutionErrorMetric   = "execution_error"
 	ExecutionFailureMetric = "execution_failure"
+
+	DriftDetectedMetric    = "drift_detected"
+	DriftNotDetectedMetric = "drift_not_detected"
 )
@@ -18,6 +18,7 @@ import (
 	"strings"
 
 	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/webauth"
 	"github.com/urfave/negroni/v3"
 )
 
@@ -28,6 +29,7 @@ func NewRequestLogger(s *Server) *RequestLogger {
 		s.WebAuthentication,
 		s.WebUsername,
 		s.WebPassword,
+		s.WebOIDC,
 	}
 }
 
@@ -38,29 +40,40 @@ type RequestLogger struct {
 	WebAuthentication bool
 	WebUsername       string
 	WebPassword       string
+	// WebOIDC, if set, makes users log in with an OIDC provider instead of
+	// basic auth.
+	WebOIDC *webauth.OIDC
 }
 
 // ServeHTTP implements the middleware function. It logs all requests at DEBUG level.
 func (l *RequestLogger) ServeHTTP(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	l.logger.Debug("%s %s – from %s", r.Method, r.URL.RequestURI(), r.RemoteAddr)
+	switch {
+	case !l.WebAuthentication && l.WebOIDC == nil,
+		r.URL.Path == "/events",
+		r.URL.Path == "/healthz",
+		r.URL.Path == "/status",
+		strings.HasPrefix(r.URL.Path, "/api/"):
+		next(rw, r)
+	case l.WebOIDC != nil:
+		l.serveOIDC(rw, r, next)
+	default:
+		l.serveBasicAuth(rw, r, next)
+	}
+	l.logger.Debug("%s %s – respond HTTP %d", r.Method, r.URL.RequestURI(), rw.(negroni.ResponseWriter).Status())
+}
+
+func (l *RequestLogger) serveBasicAuth(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	allowed := false
-	if !l.WebAuthentication ||
-		r.URL.Path == "/events" ||
-		r.URL.Path == "/healthz" ||
-		r.URL.Path == "/status" ||
-		strings.HasPrefix(r.URL.Path, "/api/") {
-		allowed = true
-	} else {
-		user, pass, ok := r.BasicAuth()
-		if ok {
-			r.SetBasicAuth(user, pass)
-			if user == l.WebUsername && pass == l.WebPassword {
-				l.logger.Debug("[VALID] log in: >> url: %s", r.URL.RequestURI())
-				allowed = true
-			} else {
-				allowed = false
-				l.logger.Info("[INVALID] log in attempt: >> url: %s", r.URL.RequestURI())
-			}
+	user, pass, ok := r.BasicAuth()
+	if ok {
+		r.SetBasicAuth(user, pass)
+		if user == l.WebUsername && pass == l.WebPassword {
+			l.logger.Debug("[VALID] log in: >> url: %s", r.URL.RequestURI())
+			allowed = true
+		} else {
+			allowed = false
+			l.logger.Info("[INVALID] log in attempt: >> url: %s", r.URL.RequestURI())
 		}
 	}
 	if !allowed {
@@ -69,5 +82,49 @@ func (l *RequestLogger) ServeHTTP(rw http.ResponseWriter, r *http.Request, next
 	} else {
 		next(rw, r)
 	}
-	l.logger.Debug("%s %s – respond HTTP %d", r.Method, r.URL.RequestURI(), rw.(negroni.ResponseWriter).Status())
+}
+
+// serveOIDC sends users without a session to log in and checks that users
+// with one are in the groups allowed to make r.
+func (l *RequestLogger) serveOIDC(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if strings.HasPrefix(r.URL.Path, webauth.RoutePrefix) {
+		next(rw, r)
+		return
+	}
+	user, ok := l.WebOIDC.Authenticate(r)
+	if !ok {
+		// Only pages can be redirected to the login page, the UI's other
+		// requests would follow the redirect without the user seeing it.
+		if r.Method == http.MethodGet {
+			http.Redirect(rw, r, l.WebOIDC.LoginURL(r.URL.RequestURI()), http.StatusFound)
+			return
+		}
+		http.Error(rw, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	perm := requiredPermission(r)
+	if !l.WebOIDC.Allows(user, perm) {
+		l.logger.Info("[FORBIDDEN] %s isn't allowed to %s: >> url: %s", user.Name, perm, r.URL.RequestURI())
+		http.Error(rw, "Forbidden", http.StatusForbidden)
+		return
+	}
+	if r.Method == http.MethodGet {
+		l.logger.Debug("%s %s – by %s", r.Method, r.URL.RequestURI(), user.Name)
+	} else {
+		l.logger.Info("%s %s – by %s", r.Method, r.URL.RequestURI(), user.Name)
+	}
+	next(rw, r.WithContext(webauth.WithUser(r.Context(), user)))
+}
+
+// requiredPermission returns the permission users need to make r.
+func requiredPermission(r *http.Request) webauth.Permission {
+	switch {
+	case r.Method == http.MethodDelete && r.URL.Path == "/locks",
+		r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/jobs/") && strings.HasSuffix(r.URL.Path, "/cancel"):
+		return webauth.DiscardLockPermission
+	case r.Method == http.MethodPost && r.URL.Path == "/apply/lock",
+		r.Method == http.MethodDelete && r.URL.Path == "/apply/unlock":
+		return webauth.ApplyLockPermission
+	}
+	return webauth.ViewPermission
 }
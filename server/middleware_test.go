@@ -0,0 +1,31 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/webauth"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestRequiredPermission(t *testing.T) {
+	cases := []struct {
+		method string
+		path   string
+		exp    webauth.Permission
+	}{
+		{http.MethodGet, "/", webauth.ViewPermission},
+		{http.MethodGet, "/lock", webauth.ViewPermission},
+		{http.MethodGet, "/jobs/1234", webauth.ViewPermission},
+		{http.MethodDelete, "/locks", webauth.DiscardLockPermission},
+		{http.MethodPost, "/jobs/1234/cancel", webauth.DiscardLockPermission},
+		{http.MethodPost, "/apply/lock", webauth.ApplyLockPermission},
+		{http.MethodDelete, "/apply/unlock", webauth.ApplyLockPermission},
+	}
+	for _, c := range cases {
+		t.Run(c.method+" "+c.path, func(t *testing.T) {
+			Equals(t, c.exp, requiredPermission(httptest.NewRequest(c.method, c.path, nil)))
+		})
+	}
+}
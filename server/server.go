@@ -33,6 +33,7 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/mitchellh/go-homedir"
 	tally "github.com/uber-go/tally/v4"
 	prometheus "github.com/uber-go/tally/v4/prometheus"
@@ -41,18 +42,22 @@ import (
 	cfg "github.com/runatlantis/atlantis/server/core/config"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/core/postgres"
 	"github.com/runatlantis/atlantis/server/core/redis"
 	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/metrics"
 	"github.com/runatlantis/atlantis/server/scheduled"
+	"github.com/runatlantis/atlantis/server/webauth"
 
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/audit"
 	"github.com/runatlantis/atlantis/server/controllers"
 	events_controllers "github.com/runatlantis/atlantis/server/controllers/events"
 	"github.com/runatlantis/atlantis/server/controllers/templates"
 	"github.com/runatlantis/atlantis/server/controllers/websocket"
 	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/core/planstore"
 	"github.com/runatlantis/atlantis/server/core/runtime"
 	"github.com/runatlantis/atlantis/server/core/runtime/policy"
 	"github.com/runatlantis/atlantis/server/core/terraform"
@@ -62,6 +67,7 @@ import (
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketcloud"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketserver"
+	"github.com/runatlantis/atlantis/server/events/vcs/gitea"
 	"github.com/runatlantis/atlantis/server/events/webhooks"
 	"github.com/runatlantis/atlantis/server/logging"
 )
@@ -83,6 +89,13 @@ const (
 	// terraformPluginCacheDir is the name of the dir inside our data dir
 	// where we tell terraform to cache plugins and modules.
 	TerraformPluginCacheDirName = "plugin-cache"
+	// JobsDirName is the name of the dir inside our data dir where the
+	// output of completed jobs is stored when using the disk job output
+	// store.
+	JobsDirName = "jobs"
+	// PolicySetsDirName is the name of the dir inside our data dir where git
+	// and http policy sets are cached.
+	PolicySetsDirName = "policy-sets"
 )
 
 // Server runs the Atlantis web server.
@@ -105,6 +118,7 @@ type Server struct {
 	LocksController                *controllers.LocksController
 	StatusController               *controllers.StatusController
 	JobsController                 *controllers.JobsController
+	DashboardController            *controllers.DashboardController
 	APIController                  *controllers.APIController
 	IndexTemplate                  templates.TemplateWriter
 	LockDetailTemplate             templates.TemplateWriter
@@ -119,6 +133,7 @@ type Server struct {
 	WebAuthentication              bool
 	WebUsername                    string
 	WebPassword                    string
+	WebOIDC                        *webauth.OIDC
 	ProjectCmdOutputHandler        jobs.ProjectCommandOutputHandler
 	ScheduledExecutorService       *scheduled.ExecutorService
 }
@@ -129,6 +144,7 @@ type Config struct {
 	AtlantisURLFlag         string
 	AtlantisVersion         string
 	DefaultTFVersionFlag    string
+	GHTeamAllowlistFlag     string
 	RepoConfigJSONFlag      string
 	SilenceForkPRErrorsFlag string
 }
@@ -146,6 +162,12 @@ type WebhookConfig struct {
 	// Channel is the channel to send this webhook to. It only applies to
 	// slack webhooks. Should be without '#'.
 	Channel string `mapstructure:"channel"`
+	// URL is the URL to POST this webhook to. It only applies to http
+	// webhooks.
+	URL string `mapstructure:"url"`
+	// Secret is used to sign the body of http webhooks. It only applies to
+	// http webhooks.
+	Secret string `mapstructure:"secret"`
 }
 
 //go:embed static
@@ -171,6 +193,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	var bitbucketCloudClient *bitbucketcloud.Client
 	var bitbucketServerClient *bitbucketserver.Client
 	var azuredevopsClient *vcs.AzureDevopsClient
+	var giteaClient *gitea.Client
 
 	policyChecksEnabled := false
 	if userConfig.EnablePolicyChecksFlag {
@@ -192,6 +215,22 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 
 	validator := &cfg.ParserValidator{}
 
+	var apiJobRetention time.Duration
+	if userConfig.APIJobRetention != "" {
+		apiJobRetention, err = time.ParseDuration(userConfig.APIJobRetention)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing API job retention")
+		}
+	}
+
+	var planTTL time.Duration
+	if userConfig.PlanTTL != "" {
+		planTTL, err = time.ParseDuration(userConfig.PlanTTL)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing plan TTL")
+		}
+	}
+
 	globalCfg := valid.NewGlobalCfgFromArgs(
 		valid.GlobalCfgArgs{
 			AllowRepoCfg:       userConfig.AllowRepoConfig,
@@ -199,6 +238,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			ApprovedReq:        userConfig.RequireApproval,
 			UnDivergedReq:      userConfig.RequireUnDiverged,
 			PolicyCheckEnabled: userConfig.EnablePolicyChecksFlag,
+			PlanTTL:            planTTL,
 		})
 	if userConfig.RepoConfig != "" {
 		globalCfg, err = validator.ParseGlobalCfg(userConfig.RepoConfig, globalCfg)
@@ -211,6 +251,9 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			return nil, errors.Wrapf(err, "parsing --%s", config.RepoConfigJSONFlag)
 		}
 	}
+	if len(globalCfg.Permissions) > 0 && userConfig.GithubTeamAllowlist != "" {
+		return nil, fmt.Errorf("--%s can't be used with permissions in the server-side repo config, move the allowlist into permissions", config.GHTeamAllowlistFlag)
+	}
 
 	statsScope, statsReporter, closer, err := metrics.NewScope(globalCfg.Metrics, logger, userConfig.StatsNamespace)
 
@@ -299,6 +342,20 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			return nil, err
 		}
 	}
+	if userConfig.GiteaUser != "" {
+		supportedVCSHosts = append(supportedVCSHosts, models.Gitea)
+
+		var err error
+		giteaClient, err = gitea.NewClient(
+			http.DefaultClient,
+			userConfig.GiteaUser,
+			userConfig.GiteaToken,
+			userConfig.GiteaBaseURL,
+			userConfig.AtlantisURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "setting up Gitea client")
+		}
+	}
 
 	home, err := homedir.Dir()
 	if err != nil {
@@ -332,6 +389,15 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 				return nil, err
 			}
 		}
+		if userConfig.GiteaUser != "" {
+			giteaURL, err := url.Parse(userConfig.GiteaBaseURL)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing Gitea base URL %q", userConfig.GiteaBaseURL)
+			}
+			if err := vcs.WriteGitCreds(userConfig.GiteaUser, userConfig.GiteaToken, giteaURL.Host, home, logger, false); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	// default the project files used to generate the module index to the autoplan-file-list if autoplan-modules is true
@@ -347,6 +413,8 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			Event:          c.Event,
 			Kind:           c.Kind,
 			WorkspaceRegex: c.WorkspaceRegex,
+			URL:            c.URL,
+			Secret:         c.Secret,
 		}
 		webhooksConfig = append(webhooksConfig, config)
 	}
@@ -354,8 +422,25 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "initializing webhooks")
 	}
-	vcsClient := vcs.NewClientProxy(githubClient, gitlabClient, bitbucketCloudClient, bitbucketServerClient, azuredevopsClient)
-	commitStatusUpdater := &events.DefaultCommitStatusUpdater{Client: vcsClient, StatusName: userConfig.VCSStatusName}
+	auditSink, auditStore, err := newAuditSink(userConfig, webhooksManager, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing audit log")
+	}
+	vcsClient := vcs.NewClientProxy(githubClient, gitlabClient, bitbucketCloudClient, bitbucketServerClient, azuredevopsClient, giteaClient)
+	defaultCommitStatusUpdater := &events.DefaultCommitStatusUpdater{Client: vcsClient, StatusName: userConfig.VCSStatusName}
+	var commitStatusUpdater interface {
+		events.CommitStatusUpdater
+		runtime.StatusUpdater
+		jobs.ProjectStatusUpdater
+	} = defaultCommitStatusUpdater
+	if userConfig.GithubChecks && githubClient != nil {
+		commitStatusUpdater = &events.GithubChecksStatusUpdater{
+			Client:        githubClient,
+			StatusName:    userConfig.VCSStatusName,
+			ApplyDisabled: userConfig.DisableApply,
+			Fallback:      defaultCommitStatusUpdater,
+		}
+	}
 
 	binDir, err := mkSubDir(userConfig.DataDir, BinDirName)
 
@@ -384,6 +469,11 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		Underlying:                underlyingRouter,
 	}
 
+	jobStore, err := newJobStore(userConfig, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing job output store")
+	}
+
 	var projectCmdOutputHandler jobs.ProjectCommandOutputHandler
 
 	if userConfig.TFEToken != "" && !userConfig.TFELocalExecutionMode {
@@ -393,6 +483,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		projectCmdOutput := make(chan *jobs.ProjectCmdOutputLine)
 		projectCmdOutputHandler = jobs.NewAsyncProjectCommandOutputHandler(
 			projectCmdOutput,
+			jobStore,
 			logger,
 		)
 	}
@@ -416,6 +507,12 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	if err != nil && flag.Lookup("test.v") == nil {
 		return nil, errors.Wrap(err, "initializing terraform")
 	}
+	jobURLSetter := jobs.NewJobURLSetter(router, commitStatusUpdater)
+	runScheduler := terraform.NewRunScheduler(userConfig.MaxConcurrentRuns, userConfig.MaxConcurrentRunsPerRepo, jobURLSetter)
+	jobCanceller := jobs.NewJobCanceller()
+	if terraformClient != nil {
+		terraformClient.SetJobCanceller(jobCanceller)
+	}
 	markdownRenderer := events.NewMarkdownRenderer(
 		gitlabClient.SupportsCommonMark(),
 		userConfig.DisableApplyAll,
@@ -439,6 +536,12 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		if err != nil {
 			return nil, err
 		}
+	case "postgres":
+		logger.Info("Utilizing PostgreSQL")
+		backend, err = postgres.New(userConfig.PostgresDSN)
+		if err != nil {
+			return nil, err
+		}
 	case "boltdb":
 		logger.Info("Utilizing BoltDB")
 		backend, err = db.New(userConfig.DataDir)
@@ -452,7 +555,11 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		logger.Info("Repo Locking is disabled")
 		lockingClient = noOpLocker
 	} else {
-		lockingClient = locking.NewClient(backend)
+		lockingClient = &events.WebhooksLocker{
+			Locker:   locking.NewClient(backend),
+			Webhooks: webhooksManager,
+			Logger:   logger,
+		}
 	}
 
 	applyLockingClient = locking.NewApplyClient(backend, disableApply)
@@ -464,6 +571,21 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		CheckoutDepth:    userConfig.CheckoutDepth,
 		GithubAppEnabled: githubAppEnabled,
 	}
+	var pendingPlanFinder events.PendingPlanFinder = &events.DefaultPendingPlanFinder{}
+	planStore, err := newPlanStore(userConfig, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing plan store")
+	}
+	if planStore != nil {
+		workingDir = &events.PlanStoreWorkingDir{
+			WorkingDir: workingDir,
+			PlanStore:  planStore,
+		}
+		pendingPlanFinder = &events.StorePendingPlanFinder{
+			Store:   planStore,
+			DataDir: userConfig.DataDir,
+		}
+	}
 
 	scheduledExecutorService := scheduled.NewExecutorService(
 		statsScope,
@@ -489,17 +611,27 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		scheduledExecutorService.AddJob(tokenJd)
 	}
 
+	scheduledExecutorService.AddJob(scheduled.JobDefinition{
+		Job: &jobs.JobStorePruneJob{
+			Store:  jobStore,
+			Logger: logger,
+		},
+		Period: time.Hour,
+	})
+
 	projectLocker := &events.DefaultProjectLocker{
 		Locker:     lockingClient,
 		NoOpLocker: noOpLocker,
 		VCSClient:  vcsClient,
 	}
+	dashboardUpdates := controllers.NewDashboardUpdates()
 	deleteLockCommand := &events.DefaultDeleteLockCommand{
-		Locker:           lockingClient,
-		Logger:           logger,
-		WorkingDir:       workingDir,
-		WorkingDirLocker: workingDirLocker,
-		Backend:          backend,
+		Locker:             lockingClient,
+		Logger:             logger,
+		WorkingDir:         workingDir,
+		WorkingDirLocker:   workingDirLocker,
+		Backend:            backend,
+		PullStatusNotifier: dashboardUpdates,
 	}
 
 	pullClosedExecutor := events.NewInstrumentedPullClosedExecutor(
@@ -513,6 +645,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			PullClosedTemplate:       &events.PullClosedEventTemplate{},
 			LogStreamResourceCleaner: projectCmdOutputHandler,
 			VCSClient:                vcsClient,
+			PullStatusNotifier:       dashboardUpdates,
 		},
 	)
 	eventParser := &events.EventParser{
@@ -526,27 +659,31 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		BitbucketServerURL: userConfig.BitbucketBaseURL,
 		AzureDevopsUser:    userConfig.AzureDevopsUser,
 		AzureDevopsToken:   userConfig.AzureDevopsToken,
+		GiteaUser:          userConfig.GiteaUser,
+		GiteaToken:         userConfig.GiteaToken,
 	}
 	commentParser := events.NewCommentParser(
 		userConfig.GithubUser,
 		userConfig.GitlabUser,
 		userConfig.BitbucketUser,
 		userConfig.AzureDevopsUser,
+		userConfig.GiteaUser,
 		userConfig.ExecutableName,
 		allowCommands,
 	)
 	defaultTfVersion := terraformClient.DefaultVersion()
-	pendingPlanFinder := &events.DefaultPendingPlanFinder{}
 	runStepRunner := &runtime.RunStepRunner{
 		TerraformExecutor:       terraformClient,
 		DefaultTFVersion:        defaultTfVersion,
 		TerraformBinDir:         terraformClient.TerraformBinDir(),
 		ProjectCmdOutputHandler: projectCmdOutputHandler,
+		JobCanceller:            jobCanceller,
 	}
 	drainer := &events.Drainer{}
 	statusController := &controllers.StatusController{
 		Logger:          logger,
 		Drainer:         drainer,
+		RunScheduler:    runScheduler,
 		AtlantisVersion: config.AtlantisVersion,
 	}
 	preWorkflowHooksCommandRunner := &events.DefaultPreWorkflowHooksCommandRunner{
@@ -580,6 +717,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		workingDirLocker,
 		globalCfg,
 		pendingPlanFinder,
+		planStore != nil,
 		commentParser,
 		userConfig.SkipCloneNoChanges,
 		userConfig.EnableRegExpCmd,
@@ -598,10 +736,22 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		return nil, errors.Wrap(err, "initializing show step runner")
 	}
 
-	policyCheckStepRunner, err := runtime.NewPolicyCheckStepRunner(
-		defaultTfVersion,
-		policy.NewConfTestExecutorWorkflow(logger, binDir, &terraform.DefaultDownloader{}),
-	)
+	remoteSourceResolver, policySetJobs, err := remotePolicySets(globalCfg, userConfig, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing remote policy sets")
+	}
+	for _, jd := range policySetJobs {
+		scheduledExecutorService.AddJob(jd)
+	}
+	sourceResolver := policy.NewSourceResolverProxy(remoteSourceResolver)
+
+	var policyExecutor runtime.VersionedExecutorWorkflow
+	if userConfig.PolicyExecutor == "rego" {
+		policyExecutor = policy.NewRegoExecutorWorkflow(sourceResolver)
+	} else {
+		policyExecutor = policy.NewConfTestExecutorWorkflow(logger, binDir, &terraform.DefaultDownloader{}, sourceResolver)
+	}
+	policyCheckStepRunner, err := runtime.NewPolicyCheckStepRunner(defaultTfVersion, policyExecutor)
 
 	if err != nil {
 		return nil, errors.Wrap(err, "initializing policy check step runner")
@@ -609,6 +759,10 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 
 	applyRequirementHandler := &events.DefaultCommandRequirementHandler{
 		WorkingDir: workingDir,
+		VCSClient:  vcsClient,
+	}
+	planStalenessChecker := &events.DefaultPlanStalenessChecker{
+		WorkingDir: workingDir,
 	}
 
 	projectCommandRunner := &events.DefaultProjectCommandRunner{
@@ -619,9 +773,13 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			TerraformExecutor: terraformClient,
 			DefaultTFVersion:  defaultTfVersion,
 		},
-		PlanStepRunner:        runtime.NewPlanStepRunner(terraformClient, defaultTfVersion, commitStatusUpdater, terraformClient),
+		PlanStepRunner:        runtime.NewPlanStepRunner(terraformClient, defaultTfVersion, commitStatusUpdater, terraformClient, planStore),
 		ShowStepRunner:        showStepRunner,
 		PolicyCheckStepRunner: policyCheckStepRunner,
+		CostEstimateStepRunner: &runtime.CostEstimateStepRunner{
+			RunStepRunner: runStepRunner,
+			Command:       userConfig.CostEstimateCommand,
+		},
 		ApplyStepRunner: &runtime.ApplyStepRunner{
 			TerraformExecutor:   terraformClient,
 			DefaultTFVersion:    defaultTfVersion,
@@ -645,10 +803,17 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		Webhooks:                  webhooksManager,
 		WorkingDirLocker:          workingDirLocker,
 		CommandRequirementHandler: applyRequirementHandler,
+		PlanStalenessChecker:      planStalenessChecker,
+		RestrictTargetedApplies:   userConfig.RestrictTargetedApplies,
+		TargetedApplyAllowlist:    userConfig.ToTargetedApplyAllowlist(),
+		JobCanceller:              jobCanceller,
+		RunScheduler:              runScheduler,
+		PlanStore:                 planStore,
 	}
 
 	dbUpdater := &events.DBUpdater{
-		Backend: backend,
+		Backend:            backend,
+		PullStatusNotifier: dashboardUpdates,
 	}
 
 	pullUpdater := &events.PullUpdater{
@@ -663,9 +828,13 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	}
 
 	projectOutputWrapper := &events.ProjectOutputWrapper{
-		JobMessageSender:     projectCmdOutputHandler,
-		ProjectCommandRunner: projectCommandRunner,
-		JobURLSetter:         jobs.NewJobURLSetter(router, commitStatusUpdater),
+		JobMessageSender: projectCmdOutputHandler,
+		ProjectCommandRunner: &events.ProjectWebhooksWrapper{
+			ProjectCommandRunner: projectCommandRunner,
+			Webhooks:             webhooksManager,
+			JobURLGenerator:      router,
+		},
+		JobURLSetter: jobURLSetter,
 	}
 	instrumentedProjectCmdRunner := events.NewInstrumentedProjectCommandRunner(
 		statsScope,
@@ -719,6 +888,8 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		userConfig.SilenceNoProjects,
 		userConfig.SilenceVCSStatusNoProjects,
 		pullReqStatusFetcher,
+		planStalenessChecker,
+		workingDirLocker,
 	)
 
 	approvePoliciesCommandRunner := events.NewApprovePoliciesCommandRunner(
@@ -732,10 +903,19 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		vcsClient,
 	)
 
+	approveDestroyCommandRunner := events.NewApproveDestroyCommandRunner(
+		projectCommandBuilder,
+		instrumentedProjectCmdRunner,
+		pullUpdater,
+		dbUpdater,
+		userConfig.SilenceNoProjects,
+	)
+
 	unlockCommandRunner := events.NewUnlockCommandRunner(
 		deleteLockCommand,
 		vcsClient,
 		userConfig.SilenceNoProjects,
+		auditSink,
 	)
 
 	versionCommandRunner := events.NewVersionCommandRunner(
@@ -760,14 +940,21 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		instrumentedProjectCmdRunner,
 	)
 
+	cancelCommandRunner := events.NewCancelCommandRunner(
+		jobCanceller,
+		vcsClient,
+	)
+
 	commentCommandRunnerByCmd := map[command.Name]events.CommentCommandRunner{
 		command.Plan:            planCommandRunner,
 		command.Apply:           applyCommandRunner,
 		command.ApprovePolicies: approvePoliciesCommandRunner,
+		command.ApproveDestroy:  approveDestroyCommandRunner,
 		command.Unlock:          unlockCommandRunner,
 		command.Version:         versionCommandRunner,
 		command.Import:          importCommandRunner,
 		command.State:           stateCommandRunner,
+		command.Cancel:          cancelCommandRunner,
 	}
 
 	githubTeamAllowlistChecker, err := events.NewTeamAllowlistChecker(userConfig.GithubTeamAllowlist)
@@ -784,6 +971,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		GithubPullGetter:               githubClient,
 		GitlabMergeRequestGetter:       gitlabClient,
 		AzureDevopsPullGetter:          azuredevopsClient,
+		GiteaPullGetter:                giteaClient,
 		CommentCommandRunnerByCmd:      commentCommandRunnerByCmd,
 		EventParser:                    eventParser,
 		Logger:                         logger,
@@ -800,6 +988,26 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		PullStatusFetcher:              backend,
 		TeamAllowlistChecker:           githubTeamAllowlistChecker,
 		VarFileAllowlistChecker:        varFileAllowlistChecker,
+		AuditSink:                      auditSink,
+		CommitStatusUpdater:            commitStatusUpdater,
+		QueueWorkers:                   userConfig.EventQueueWorkers,
+		ReplayQueuedEvents:             userConfig.EventQueueRecovery == "replay",
+	}
+	var commandQueue events.CommandQueue
+	if userConfig.EnableEventQueue {
+		queuedEventStore, ok := backend.(events.QueuedEventStore)
+		if !ok {
+			return nil, fmt.Errorf("locking db type %q doesn't support the event queue", userConfig.LockingDBType)
+		}
+		logger.Info("Persisting events in the event queue")
+		instanceID, err := os.Hostname()
+		if err != nil {
+			logger.Warn("unable to get the hostname to identify this instance's queued events, using a random ID: %s", err)
+			instanceID = uuid.New().String()
+		}
+		commandRunner.QueuedEventStore = queuedEventStore
+		commandRunner.InstanceID = instanceID
+		commandQueue = commandRunner
 	}
 	repoAllowlist, err := events.NewRepoAllowlistChecker(userConfig.RepoAllowlist)
 	if err != nil {
@@ -817,6 +1025,8 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		WorkingDirLocker:   workingDirLocker,
 		Backend:            backend,
 		DeleteLockCommand:  deleteLockCommand,
+		AuditSink:          auditSink,
+		PullStatusNotifier: dashboardUpdates,
 	}
 
 	wsMux := websocket.NewMultiplexor(
@@ -836,10 +1046,51 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		WsMux:                    wsMux,
 		KeyGenerator:             controllers.JobIDKeyGenerator{},
 		StatsScope:               statsScope.SubScope("api"),
+		JobCanceller:             jobCanceller,
+	}
+	dashboardController := &controllers.DashboardController{
+		AtlantisVersion:   config.AtlantisVersion,
+		AtlantisURL:       parsedURL,
+		Logger:            logger,
+		DashboardTemplate: templates.DashboardTemplate,
+		Backend:           backend,
+		WsMux: websocket.NewMultiplexor(
+			logger,
+			controllers.DashboardKeyGenerator{},
+			dashboardUpdates,
+			userConfig.WebsocketCheckOrigin,
+		),
+		StatsScope: statsScope.SubScope("api"),
+	}
+	driftDetector := &events.DriftDetector{
+		VCSClient:             vcsClient,
+		Parser:                eventParser,
+		ProjectCommandBuilder: projectCommandBuilder,
+		ProjectCommandRunner:  projectCommandRunner,
+		Webhooks:              webhooksManager,
+		Scope:                 statsScope,
+		Logger:                logger,
+	}
+	if driftStore, ok := backend.(events.DriftStore); ok {
+		driftDetector.Store = driftStore
+	}
+	driftJobs, err := driftDetectionJobs(globalCfg, userConfig, supportedVCSHosts, driftDetector)
+	if err != nil {
+		return nil, errors.Wrap(err, "initializing drift detection")
 	}
+	for _, jd := range driftJobs {
+		scheduledExecutorService.AddJob(jd)
+	}
+
 	apiController := &controllers.APIController{
 		APISecret:                 []byte(userConfig.APISecret),
+		Drainer:                   drainer,
 		Locker:                    lockingClient,
+		ApplyLocker:               applyLockingClient,
+		Backend:                   backend,
+		DeleteLockCommand:         deleteLockCommand,
+		DriftDetector:             driftDetector,
+		ProjectCmdOutputHandler:   projectCmdOutputHandler,
 		Logger:                    logger,
 		Parser:                    eventParser,
 		ProjectCommandBuilder:     projectCommandBuilder,
@@ -848,10 +1099,14 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		RepoAllowlistChecker:      repoAllowlist,
 		Scope:                     statsScope.SubScope("api"),
 		VCSClient:                 vcsClient,
+		AuditSink:                 auditSink,
+		AuditStore:                auditStore,
+		AsyncJobRetention:         apiJobRetention,
 	}
 
 	eventsController := &events_controllers.VCSEventsController{
 		CommandRunner:                   commandRunner,
+		CommandQueue:                    commandQueue,
 		PullCleaner:                     pullClosedExecutor,
 		Parser:                          eventParser,
 		CommentParser:                   commentParser,
@@ -872,6 +1127,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		AzureDevopsWebhookBasicUser:     []byte(userConfig.AzureDevopsWebhookUser),
 		AzureDevopsWebhookBasicPassword: []byte(userConfig.AzureDevopsWebhookPassword),
 		AzureDevopsRequestValidator:     &events_controllers.DefaultAzureDevopsRequestValidator{},
+		GiteaWebhookSecret:              []byte(userConfig.GiteaWebhookSecret),
 	}
 	githubAppController := &controllers.GithubAppController{
 		AtlantisURL:         parsedURL,
@@ -881,6 +1137,14 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		GithubOrg:           userConfig.GithubOrg,
 	}
 
+	var webOIDC *webauth.OIDC
+	if userConfig.WebOIDCIssuerURL != "" {
+		webOIDC, err = webauth.NewOIDC(userConfig.ToWebOIDCConfig(parsedURL), &http.Client{Timeout: 30 * time.Second}, logger)
+		if err != nil {
+			return nil, errors.Wrap(err, "initializing OIDC login")
+		}
+	}
+
 	return &Server{
 		AtlantisVersion:                config.AtlantisVersion,
 		AtlantisURL:                    parsedURL,
@@ -899,6 +1163,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		GithubAppController:            githubAppController,
 		LocksController:                locksController,
 		JobsController:                 jobsController,
+		DashboardController:            dashboardController,
 		StatusController:               statusController,
 		APIController:                  apiController,
 		IndexTemplate:                  templates.IndexTemplate,
@@ -912,6 +1177,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		WebAuthentication:              userConfig.WebBasicAuth,
 		WebUsername:                    userConfig.WebUsername,
 		WebPassword:                    userConfig.WebPassword,
+		WebOIDC:                        webOIDC,
 		ScheduledExecutorService:       scheduledExecutorService,
 	}, nil
 }
@@ -927,6 +1193,20 @@ func (s *Server) Start() error {
 	s.Router.HandleFunc("/events", s.VCSEventsController.Post).Methods("POST")
 	s.Router.HandleFunc("/api/plan", s.APIController.Plan).Methods("POST")
 	s.Router.HandleFunc("/api/apply", s.APIController.Apply).Methods("POST")
+	s.Router.HandleFunc("/api/plan/async", s.APIController.PlanAsync).Methods("POST")
+	s.Router.HandleFunc("/api/apply/async", s.APIController.ApplyAsync).Methods("POST")
+	s.Router.HandleFunc("/api/async/{id}", s.APIController.GetAsyncJob).Methods("GET")
+	s.Router.HandleFunc("/api/async/{id}/result", s.APIController.GetAsyncJobResult).Methods("GET")
+	s.Router.HandleFunc("/api/locks", s.APIController.GetLock).Methods("GET").Queries("id", "{id:.*}")
+	s.Router.HandleFunc("/api/locks", s.APIController.DeleteLock).Methods("DELETE").Queries("id", "{id:.*}")
+	s.Router.HandleFunc("/api/locks", s.APIController.ListLocks).Methods("GET")
+	s.Router.HandleFunc("/api/pull", s.APIController.GetPullStatus).Methods("GET")
+	s.Router.HandleFunc("/api/jobs", s.APIController.ListJobs).Methods("GET")
+	s.Router.HandleFunc("/api/drift", s.APIController.ListDrift).Methods("GET")
+	s.Router.HandleFunc("/api/audit", s.APIController.ListAuditEvents).Methods("GET")
+	s.Router.HandleFunc("/api/apply/lock", s.APIController.GetApplyLock).Methods("GET")
+	s.Router.HandleFunc("/api/apply/lock", s.APIController.LockApply).Methods("POST")
+	s.Router.HandleFunc("/api/apply/lock", s.APIController.UnlockApply).Methods("DELETE")
 	s.Router.HandleFunc("/github-app/exchange-code", s.GithubAppController.ExchangeCode).Methods("GET")
 	s.Router.HandleFunc("/github-app/setup", s.GithubAppController.New).Methods("GET")
 	s.Router.HandleFunc("/apply/lock", s.LocksController.LockApply).Methods("POST").Queries()
@@ -936,6 +1216,14 @@ func (s *Server) Start() error {
 		Queries(LockViewRouteIDQueryParam, fmt.Sprintf("{%s}", LockViewRouteIDQueryParam)).Name(LockViewRouteName)
 	s.Router.HandleFunc("/jobs/{job-id}", s.JobsController.GetProjectJobs).Methods("GET").Name(ProjectJobsViewRouteName)
 	s.Router.HandleFunc("/jobs/{job-id}/ws", s.JobsController.GetProjectJobsWS).Methods("GET")
+	s.Router.HandleFunc("/jobs/{job-id}/cancel", s.JobsController.CancelProjectJob).Methods("POST")
+	s.Router.HandleFunc("/dashboard", s.DashboardController.Get).Methods("GET")
+	s.Router.HandleFunc("/dashboard/ws", s.DashboardController.GetUpdatesWS).Methods("GET")
+	if s.WebOIDC != nil {
+		s.Router.HandleFunc(webauth.LoginPath, s.WebOIDC.Login).Methods("GET")
+		s.Router.HandleFunc(webauth.CallbackPath, s.WebOIDC.Callback).Methods("GET")
+		s.Router.HandleFunc(webauth.LogoutPath, s.WebOIDC.Logout).Methods("GET")
+	}
 
 	r, ok := s.StatsReporter.(prometheus.Reporter)
 	if ok {
@@ -957,6 +1245,14 @@ func (s *Server) Start() error {
 	// Stop on SIGINTs and SIGTERMs.
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
+	// Handle the events left over from the last run before accepting new ones.
+	if err := s.CommandRunner.StartEventQueue(); err != nil {
+		return err
+	}
+	if err := s.APIController.RecoverAsyncJobs(); err != nil {
+		return err
+	}
+
 	go s.ScheduledExecutorService.Run()
 
 	go func() {
@@ -984,6 +1280,7 @@ func (s *Server) Start() error {
 
 	s.Logger.Warn("Received interrupt. Waiting for in-progress operations to complete")
 	s.waitForDrain()
+	s.CommandRunner.StopEventQueue()
 
 	// flush stats before shutdown
 	if err := s.StatsCloser.Close(); err != nil {
@@ -997,6 +1294,88 @@ func (s *Server) Start() error {
 	return nil
 }
 
+// driftDetectionJobs returns a scheduled job for each repo in globalCfg that
+// has drift detection enabled.
+func driftDetectionJobs(globalCfg valid.GlobalCfg, userConfig UserConfig, supportedVCSHosts []models.VCSHostType, detector *events.DriftDetector) ([]scheduled.JobDefinition, error) {
+	// Repo ids are prefixed with the hostname of their VCS host.
+	hostTypes := make(map[string]models.VCSHostType)
+	for _, hostType := range supportedVCSHosts {
+		switch hostType {
+		case models.Github:
+			hostTypes[userConfig.GithubHostname] = hostType
+		case models.Gitlab:
+			hostTypes[userConfig.GitlabHostname] = hostType
+		case models.Gitea:
+			giteaURL, err := url.Parse(userConfig.GiteaBaseURL)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing Gitea base URL %q", userConfig.GiteaBaseURL)
+			}
+			hostTypes[giteaURL.Host] = hostType
+		}
+	}
+
+	var jobs []scheduled.JobDefinition
+	for _, repo := range globalCfg.Repos {
+		if repo.DriftDetection == nil {
+			continue
+		}
+		hostname, repoFullName, found := strings.Cut(repo.ID, "/")
+		if !found {
+			return nil, fmt.Errorf("invalid repo id %q", repo.ID)
+		}
+		hostType, ok := hostTypes[hostname]
+		if !ok {
+			return nil, fmt.Errorf("drift detection for repo %q is only supported for GitHub, GitLab and Gitea repos", repo.ID)
+		}
+		jobs = append(jobs, scheduled.JobDefinition{
+			Job: &events.DriftDetectionJob{
+				Detector:     detector,
+				VCSHostType:  hostType,
+				RepoFullName: repoFullName,
+				Branch:       repo.DriftDetection.Branch,
+			},
+			Period: repo.DriftDetection.Interval,
+		})
+	}
+	return jobs, nil
+}
+
+// remotePolicySets returns the resolver for git and http policy sets and a
+// scheduled job to refresh each of them. The resolver is nil if policy checks
+// are disabled or there are no remote policy sets.
+func remotePolicySets(globalCfg valid.GlobalCfg, userConfig UserConfig, logger logging.SimpleLogging) (*policy.RemoteSourceResolver, []scheduled.JobDefinition, error) {
+	if !userConfig.EnablePolicyChecksFlag {
+		return nil, nil, nil
+	}
+	var remotePolicySets []valid.PolicySet
+	for _, policySet := range globalCfg.PolicySets.PolicySets {
+		if policySet.IsRemote() {
+			remotePolicySets = append(remotePolicySets, policySet)
+		}
+	}
+	if len(remotePolicySets) == 0 {
+		return nil, nil, nil
+	}
+
+	cacheDir, err := mkSubDir(userConfig.DataDir, PolicySetsDirName)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolver := policy.NewRemoteSourceResolver(cacheDir, &terraform.DefaultDownloader{})
+	var jobs []scheduled.JobDefinition
+	for _, policySet := range remotePolicySets {
+		jobs = append(jobs, scheduled.JobDefinition{
+			Job: &policy.PolicySetRefreshJob{
+				Resolver:  resolver,
+				PolicySet: policySet,
+				Logger:    logger,
+			},
+			Period: policySet.RefreshInterval,
+		})
+	}
+	return resolver, jobs, nil
+}
+
 // waitForDrain blocks until draining is complete.
 func (s *Server) waitForDrain() {
 	drainComplete := make(chan bool, 1)
@@ -1077,6 +1456,79 @@ func mkSubDir(parentDir string, subDir string) (string, error) {
 	return fullDir, nil
 }
 
+// newJobStore returns the store for the output of completed jobs configured
+// by --job-output-store.
+func newJobStore(userConfig UserConfig, logger logging.SimpleLogging) (jobs.JobStore, error) {
+	retention := jobs.Retention{
+		MaxAge:  time.Duration(userConfig.JobOutputRetentionDays) * 24 * time.Hour,
+		MaxSize: int64(userConfig.JobOutputMaxSizeMB) * 1024 * 1024,
+	}
+	switch userConfig.JobOutputStore {
+	case "disk":
+		logger.Info("Storing job output on disk")
+		return jobs.NewLocalJobStore(filepath.Join(userConfig.DataDir, JobsDirName), retention)
+	case "redis":
+		logger.Info("Storing job output in Redis")
+		db, err := redis.New(userConfig.RedisHost, userConfig.RedisPort, userConfig.RedisPassword, userConfig.RedisTLSEnabled, userConfig.RedisInsecureSkipVerify, userConfig.RedisDB)
+		if err != nil {
+			return nil, err
+		}
+		return db.NewJobStore(retention), nil
+	default:
+		return &jobs.NoopJobStore{}, nil
+	}
+}
+
+// newPlanStore returns the store that plan files are uploaded to, configured
+// by --plan-store. It's nil if plans are only kept in the working dirs.
+func newPlanStore(userConfig UserConfig, logger logging.SimpleLogging) (planstore.Store, error) {
+	switch userConfig.PlanStore {
+	case "filesystem":
+		logger.Info("Storing plans in %s", userConfig.PlanStoreDir)
+		return &planstore.FileStore{Dir: userConfig.PlanStoreDir}, nil
+	case "s3":
+		logger.Info("Storing plans in S3 bucket %s", userConfig.PlanStoreS3Bucket)
+		return planstore.NewS3Store(planstore.S3Config{
+			Bucket:          userConfig.PlanStoreS3Bucket,
+			Prefix:          userConfig.PlanStoreS3Prefix,
+			Region:          userConfig.PlanStoreS3Region,
+			Endpoint:        userConfig.PlanStoreS3Endpoint,
+			UsePathStyle:    userConfig.PlanStoreS3UsePathStyle,
+			AccessKeyID:     userConfig.PlanStoreS3AccessKeyID,
+			SecretAccessKey: userConfig.PlanStoreS3SecretAccessKey,
+		})
+	default:
+		return nil, nil
+	}
+}
+
+// newAuditSink returns the sink that audit events are recorded with and the
+// store that's queried by /api/audit. Both are nil if the audit log isn't
+// enabled. The store is only set if --audit-log-file is.
+func newAuditSink(userConfig UserConfig, webhooksManager *webhooks.MultiWebhookSender, logger logging.SimpleLogging) (audit.Sink, audit.Store, error) {
+	var sinks []audit.Sink
+	var store audit.Store
+	if userConfig.AuditLogFile != "" {
+		logger.Info("Recording audit events in %s", userConfig.AuditLogFile)
+		fileSink, err := audit.NewFileSink(userConfig.AuditLogFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, fileSink)
+		store = fileSink
+	}
+	if len(webhooksManager.AuditWebhooks) > 0 {
+		sinks = append(sinks, &audit.WebhookSink{
+			Webhooks: webhooksManager.AuditWebhooks,
+			Logger:   logger,
+		})
+	}
+	if len(sinks) == 0 {
+		return nil, nil, nil
+	}
+	return &audit.MultiSink{Sinks: sinks}, store, nil
+}
+
 // Healthz returns the health check response. It always returns a 200 currently.
 func (s *Server) Healthz(w http.ResponseWriter, _ *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -1,10 +1,12 @@
 package server
 
 import (
+	"net/url"
 	"strings"
 
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/webauth"
 )
 
 // UserConfig holds config values passed in by the user.
@@ -14,6 +16,7 @@ type UserConfig struct {
 	AllowForkPRs                    bool   `mapstructure:"allow-fork-prs"`
 	AllowRepoConfig                 bool   `mapstructure:"allow-repo-config"`
 	AllowCommands                   string `mapstructure:"allow-commands"`
+	AuditLogFile                    string `mapstructure:"audit-log-file"`
 	AtlantisURL                     string `mapstructure:"atlantis-url"`
 	Automerge                       bool   `mapstructure:"automerge"`
 	AutoplanFileList                string `mapstructure:"autoplan-file-list"`
@@ -30,6 +33,7 @@ type UserConfig struct {
 	BitbucketWebhookSecret          string `mapstructure:"bitbucket-webhook-secret"`
 	CheckoutDepth                   int    `mapstructure:"checkout-depth"`
 	CheckoutStrategy                string `mapstructure:"checkout-strategy"`
+	CostEstimateCommand             string `mapstructure:"cost-estimate-command"`
 	DataDir                         string `mapstructure:"data-dir"`
 	DisableApplyAll                 bool   `mapstructure:"disable-apply-all"`
 	DisableApply                    bool   `mapstructure:"disable-apply"`
@@ -41,9 +45,17 @@ type UserConfig struct {
 	EnablePolicyChecksFlag          bool   `mapstructure:"enable-policy-checks"`
 	EnableRegExpCmd                 bool   `mapstructure:"enable-regexp-cmd"`
 	EnableDiffMarkdownFormat        bool   `mapstructure:"enable-diff-markdown-format"`
+	EnableEventQueue                bool   `mapstructure:"enable-event-queue"`
+	EventQueueRecovery              string `mapstructure:"event-queue-recovery"`
+	EventQueueWorkers               int    `mapstructure:"event-queue-workers"`
 	ExecutableName                  string `mapstructure:"executable-name"`
 	HideUnchangedPlanComments       bool   `mapstructure:"hide-unchanged-plan-comments"`
+	GiteaBaseURL                    string `mapstructure:"gitea-base-url"`
+	GiteaToken                      string `mapstructure:"gitea-token"`
+	GiteaUser                       string `mapstructure:"gitea-user"`
+	GiteaWebhookSecret              string `mapstructure:"gitea-webhook-secret"`
 	GithubAllowMergeableBypassApply bool   `mapstructure:"gh-allow-mergeable-bypass-apply"`
+	GithubChecks                    bool   `mapstructure:"gh-checks"`
 	GithubHostname                  string `mapstructure:"gh-hostname"`
 	GithubToken                     string `mapstructure:"gh-token"`
 	GithubUser                      string `mapstructure:"gh-user"`
@@ -58,15 +70,33 @@ type UserConfig struct {
 	GitlabToken                     string `mapstructure:"gitlab-token"`
 	GitlabUser                      string `mapstructure:"gitlab-user"`
 	GitlabWebhookSecret             string `mapstructure:"gitlab-webhook-secret"`
+	APIJobRetention                 string `mapstructure:"api-job-retention"`
 	APISecret                       string `mapstructure:"api-secret"`
 	HidePrevPlanComments            bool   `mapstructure:"hide-prev-plan-comments"`
+	JobOutputMaxSizeMB              int    `mapstructure:"job-output-max-size-mb"`
+	JobOutputRetentionDays          int    `mapstructure:"job-output-retention-days"`
+	JobOutputStore                  string `mapstructure:"job-output-store"`
 	LockingDBType                   string `mapstructure:"locking-db-type"`
 	LogLevel                        string `mapstructure:"log-level"`
 	MarkdownTemplateOverridesDir    string `mapstructure:"markdown-template-overrides-dir"`
+	MaxConcurrentRuns               int    `mapstructure:"max-concurrent-runs"`
+	MaxConcurrentRunsPerRepo        int    `mapstructure:"max-concurrent-runs-per-repo"`
 	ParallelPoolSize                int    `mapstructure:"parallel-pool-size"`
 	StatsNamespace                  string `mapstructure:"stats-namespace"`
 	PlanDrafts                      bool   `mapstructure:"allow-draft-prs"`
+	PlanTTL                         string `mapstructure:"plan-ttl"`
+	PlanStore                       string `mapstructure:"plan-store"`
+	PlanStoreDir                    string `mapstructure:"plan-store-dir"`
+	PlanStoreS3Bucket               string `mapstructure:"plan-store-s3-bucket"`
+	PlanStoreS3Prefix               string `mapstructure:"plan-store-s3-prefix"`
+	PlanStoreS3Region               string `mapstructure:"plan-store-s3-region"`
+	PlanStoreS3Endpoint             string `mapstructure:"plan-store-s3-endpoint"`
+	PlanStoreS3AccessKeyID          string `mapstructure:"plan-store-s3-access-key-id"`
+	PlanStoreS3SecretAccessKey      string `mapstructure:"plan-store-s3-secret-access-key"`
+	PlanStoreS3UsePathStyle         bool   `mapstructure:"plan-store-s3-use-path-style"`
+	PolicyExecutor                  string `mapstructure:"policy-executor"`
 	Port                            int    `mapstructure:"port"`
+	PostgresDSN                     string `mapstructure:"postgres-dsn"`
 	QuietPolicyChecks               bool   `mapstructure:"quiet-policy-checks"`
 	RedisDB                         int    `mapstructure:"redis-db"`
 	RedisHost                       string `mapstructure:"redis-host"`
@@ -77,6 +107,8 @@ type UserConfig struct {
 	RepoConfig                      string `mapstructure:"repo-config"`
 	RepoConfigJSON                  string `mapstructure:"repo-config-json"`
 	RepoAllowlist                   string `mapstructure:"repo-allowlist"`
+	RestrictTargetedApplies         bool   `mapstructure:"restrict-targeted-applies"`
+	TargetedApplyAllowlist          string `mapstructure:"targeted-apply-allowlist"`
 	// RepoWhitelist is deprecated in favour of RepoAllowlist.
 	RepoWhitelist string `mapstructure:"repo-whitelist"`
 
@@ -118,8 +150,20 @@ type UserConfig struct {
 	WebBasicAuth           bool            `mapstructure:"web-basic-auth"`
 	WebUsername            string          `mapstructure:"web-username"`
 	WebPassword            string          `mapstructure:"web-password"`
-	WriteGitCreds          bool            `mapstructure:"write-git-creds"`
-	WebsocketCheckOrigin   bool            `mapstructure:"websocket-check-origin"`
+	// WebOIDCIssuerURL, if set, makes users log in to the UI with this OIDC
+	// provider.
+	WebOIDCIssuerURL         string `mapstructure:"web-oidc-issuer-url"`
+	WebOIDCClientID          string `mapstructure:"web-oidc-client-id"`
+	WebOIDCClientSecret      string `mapstructure:"web-oidc-client-secret"`
+	WebOIDCScopes            string `mapstructure:"web-oidc-scopes"`
+	WebOIDCUsernameClaim     string `mapstructure:"web-oidc-username-claim"`
+	WebOIDCGroupsClaim       string `mapstructure:"web-oidc-groups-claim"`
+	WebOIDCViewGroups        string `mapstructure:"web-oidc-view-groups"`
+	WebOIDCDiscardLockGroups string `mapstructure:"web-oidc-discard-lock-groups"`
+	WebOIDCApplyLockGroups   string `mapstructure:"web-oidc-apply-lock-groups"`
+	WebOIDCSessionSecret     string `mapstructure:"web-oidc-session-secret"`
+	WriteGitCreds            bool   `mapstructure:"write-git-creds"`
+	WebsocketCheckOrigin     bool   `mapstructure:"websocket-check-origin"`
 }
 
 // ToAllowCommandNames parse AllowCommands into a slice of CommandName
@@ -146,6 +190,43 @@ func (u UserConfig) ToAllowCommandNames() ([]command.Name, error) {
 	return allowCommands, nil
 }
 
+// ToTargetedApplyAllowlist parses TargetedApplyAllowlist into the list of
+// usernames that can apply plans made with --target or --replace.
+func (u UserConfig) ToTargetedApplyAllowlist() []string {
+	return splitList(u.TargetedApplyAllowlist)
+}
+
+// ToWebOIDCConfig returns the config for logging in to the UI with OIDC.
+// atlantisURL is the URL Atlantis is served at.
+func (u UserConfig) ToWebOIDCConfig(atlantisURL *url.URL) webauth.OIDCConfig {
+	return webauth.OIDCConfig{
+		IssuerURL:     u.WebOIDCIssuerURL,
+		ClientID:      u.WebOIDCClientID,
+		ClientSecret:  u.WebOIDCClientSecret,
+		BaseURL:       atlantisURL,
+		Scopes:        splitList(u.WebOIDCScopes),
+		UsernameClaim: u.WebOIDCUsernameClaim,
+		GroupsClaim:   u.WebOIDCGroupsClaim,
+		Rules: webauth.GroupRules{
+			View:        splitList(u.WebOIDCViewGroups),
+			DiscardLock: splitList(u.WebOIDCDiscardLockGroups),
+			ApplyLock:   splitList(u.WebOIDCApplyLockGroups),
+		},
+		SessionSecret: u.WebOIDCSessionSecret,
+	}
+}
+
+// splitList splits a comma-separated list, ignoring empty items.
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // ToLogLevel returns the LogLevel object corresponding to the user-passed
 // log level.
 func (u UserConfig) ToLogLevel() logging.LogLevel {
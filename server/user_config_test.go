@@ -1,11 +1,13 @@
 package server_test
 
 import (
+	"net/url"
 	"testing"
 
 	"github.com/runatlantis/atlantis/server"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/webauth"
 	. "github.com/runatlantis/atlantis/testing"
 	"github.com/stretchr/testify/assert"
 )
@@ -28,14 +30,14 @@ func TestUserConfig_ToAllowCommandNames(t *testing.T) {
 			name:          "all",
 			allowCommands: "all",
 			want: []command.Name{
-				command.Version, command.Plan, command.Apply, command.Unlock, command.ApprovePolicies, command.Import, command.State,
+				command.Version, command.Plan, command.Apply, command.Unlock, command.ApprovePolicies, command.ApproveDestroy, command.Import, command.State, command.Cancel,
 			},
 		},
 		{
 			name:          "all with others returns same with all result",
 			allowCommands: "all,plan",
 			want: []command.Name{
-				command.Version, command.Plan, command.Apply, command.Unlock, command.ApprovePolicies, command.Import, command.State,
+				command.Version, command.Plan, command.Apply, command.Unlock, command.ApprovePolicies, command.ApproveDestroy, command.Import, command.State, command.Cancel,
 			},
 		},
 		{
@@ -104,3 +106,62 @@ func TestUserConfig_ToLogLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestUserConfig_ToTargetedApplyAllowlist(t *testing.T) {
+	cases := []struct {
+		allowlist string
+		exp       []string
+	}{
+		{
+			"",
+			nil,
+		},
+		{
+			"alice",
+			[]string{"alice"},
+		},
+		{
+			"alice, bob,,",
+			[]string{"alice", "bob"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.allowlist, func(t *testing.T) {
+			u := server.UserConfig{
+				TargetedApplyAllowlist: c.allowlist,
+			}
+			Equals(t, c.exp, u.ToTargetedApplyAllowlist())
+		})
+	}
+}
+
+func TestUserConfig_ToWebOIDCConfig(t *testing.T) {
+	atlantisURL, err := url.Parse("https://example.com/basepath")
+	Ok(t, err)
+	u := server.UserConfig{
+		WebOIDCIssuerURL:         "https://accounts.example.com",
+		WebOIDCClientID:          "atlantis",
+		WebOIDCClientSecret:      "secret",
+		WebOIDCScopes:            "openid, email,groups",
+		WebOIDCUsernameClaim:     "email",
+		WebOIDCGroupsClaim:       "groups",
+		WebOIDCViewGroups:        "engineering",
+		WebOIDCDiscardLockGroups: "platform,sre",
+		WebOIDCSessionSecret:     "session-secret",
+	}
+	Equals(t, webauth.OIDCConfig{
+		IssuerURL:     "https://accounts.example.com",
+		ClientID:      "atlantis",
+		ClientSecret:  "secret",
+		BaseURL:       atlantisURL,
+		Scopes:        []string{"openid", "email", "groups"},
+		UsernameClaim: "email",
+		GroupsClaim:   "groups",
+		Rules: webauth.GroupRules{
+			View:        []string{"engineering"},
+			DiscardLock: []string{"platform", "sre"},
+		},
+		SessionSecret: "session-secret",
+	}, u.ToWebOIDCConfig(atlantisURL))
+}
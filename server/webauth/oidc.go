@@ -0,0 +1,521 @@
+package webauth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
+	"golang.org/x/oauth2"
+)
+
+// Routes of the OIDC login flow. They must be reachable without a session.
+const (
+	RoutePrefix  = "/auth/"
+	LoginPath    = "/auth/login"
+	CallbackPath = "/auth/callback"
+	LogoutPath   = "/auth/logout"
+)
+
+const (
+	// SessionCookieName is the cookie holding the session of a logged in user.
+	SessionCookieName = "atlantis_session"
+	// stateCookieName is the cookie holding the state of a login in progress.
+	stateCookieName = "atlantis_oidc_state"
+
+	// DefaultSessionDuration is how long users stay logged in.
+	DefaultSessionDuration = 12 * time.Hour
+	// loginTimeout is how long users have to log in with the provider.
+	loginTimeout = 10 * time.Minute
+
+	// The audiences of the tokens Atlantis signs so that a state token can't
+	// be used as a session and vice versa.
+	sessionAudience = "atlantis-session"
+	stateAudience   = "atlantis-oidc-state"
+)
+
+// idTokenMethods are the algorithms ID tokens can be signed with.
+var idTokenMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+// OIDCConfig configures logging in to the UI with an OpenID Connect provider.
+type OIDCConfig struct {
+	// IssuerURL is the URL of the provider. Its endpoints are discovered from
+	// IssuerURL/.well-known/openid-configuration.
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	// BaseURL is the URL of Atlantis, ex. https://example.com/atlantis. The
+	// provider redirects users to BaseURL + CallbackPath once they've logged
+	// in.
+	BaseURL *url.URL
+	Scopes  []string
+	// UsernameClaim is the ID token claim that's the username, ex. email.
+	UsernameClaim string
+	// GroupsClaim is the ID token claim listing the user's groups.
+	GroupsClaim string
+	Rules       GroupRules
+	// SessionSecret signs the session cookies. If empty, a random secret is
+	// used and users have to log in again after Atlantis restarts.
+	SessionSecret   string
+	SessionDuration time.Duration
+}
+
+// OIDC logs users in to the UI with an OpenID Connect provider using the
+// authorization code flow. Logged in users are given a session cookie signed
+// by Atlantis.
+type OIDC struct {
+	cfg        OIDCConfig
+	oauth      oauth2.Config
+	issuer     string
+	jwksURL    string
+	secret     []byte
+	httpClient *http.Client
+	logger     logging.SimpleLogging
+
+	// keysMu guards keys, the provider's signing keys by key ID.
+	keysMu sync.Mutex
+	keys   map[string]interface{}
+}
+
+// providerMetadata is the part of the provider's discovery document that
+// Atlantis uses.
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewOIDC discovers the provider's endpoints. httpClient is used for all
+// requests to the provider.
+func NewOIDC(cfg OIDCConfig, httpClient *http.Client, logger logging.SimpleLogging) (*OIDC, error) {
+	if cfg.SessionDuration == 0 {
+		cfg.SessionDuration = DefaultSessionDuration
+	}
+	secret := []byte(cfg.SessionSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, errors.Wrap(err, "generating session secret")
+		}
+	}
+
+	discoveryURL := strings.TrimSuffix(cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+	var metadata providerMetadata
+	if err := getJSON(httpClient, discoveryURL, &metadata); err != nil {
+		return nil, errors.Wrap(err, "discovering OIDC provider")
+	}
+	if metadata.Issuer != cfg.IssuerURL {
+		return nil, fmt.Errorf("OIDC provider's issuer %q doesn't match %q", metadata.Issuer, cfg.IssuerURL)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("OIDC provider's discovery document at %s is missing endpoints", discoveryURL)
+	}
+
+	redirectURL := *cfg.BaseURL
+	redirectURL.Path = strings.TrimSuffix(redirectURL.Path, "/") + CallbackPath
+	return &OIDC{
+		cfg: cfg,
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			Endpoint: oauth2.Endpoint{
+				AuthURL:  metadata.AuthorizationEndpoint,
+				TokenURL: metadata.TokenEndpoint,
+			},
+			RedirectURL: redirectURL.String(),
+			Scopes:      cfg.Scopes,
+		},
+		issuer:     metadata.Issuer,
+		jwksURL:    metadata.JWKSURI,
+		secret:     secret,
+		httpClient: httpClient,
+		logger:     logger,
+		keys:       make(map[string]interface{}),
+	}, nil
+}
+
+// Allows returns true if user has perm.
+func (o *OIDC) Allows(user User, perm Permission) bool {
+	return o.cfg.Rules.Allows(user, perm)
+}
+
+// LoginURL returns the URL that logs users in and then sends them back to
+// requestURI.
+func (o *OIDC) LoginURL(requestURI string) string {
+	return o.basePath() + LoginPath + "?redirect=" + url.QueryEscape(requestURI)
+}
+
+// sessionClaims are the claims of the session cookie. The subject is the
+// username.
+type sessionClaims struct {
+	Groups []string `json:"groups,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// stateClaims are the claims of the state cookie. The ID is the state
+// parameter sent to the provider.
+type stateClaims struct {
+	Nonce    string `json:"nonce"`
+	Redirect string `json:"redirect"`
+	jwt.RegisteredClaims
+}
+
+// Authenticate returns the user whose session cookie is on r. It returns
+// false if there isn't a valid session.
+func (o *OIDC) Authenticate(r *http.Request) (User, bool) {
+	cookie, err := r.Cookie(SessionCookieName)
+	if err != nil {
+		return User{}, false
+	}
+	var claims sessionClaims
+	if err := o.parse(cookie.Value, sessionAudience, &claims); err != nil {
+		o.logger.Debug("invalid session: %s", err)
+		return User{}, false
+	}
+	return User{Name: claims.Subject, Groups: claims.Groups}, true
+}
+
+// Login is the GET /auth/login route. It redirects to the provider's login
+// page.
+func (o *OIDC) Login(w http.ResponseWriter, r *http.Request) {
+	state, err := randomString()
+	if err != nil {
+		o.respond(w, logging.Error, http.StatusInternalServerError, "Failed to start login: %s", err)
+		return
+	}
+	nonce, err := randomString()
+	if err != nil {
+		o.respond(w, logging.Error, http.StatusInternalServerError, "Failed to start login: %s", err)
+		return
+	}
+	redirect := r.URL.Query().Get("redirect")
+	if !isLocalPath(redirect) {
+		redirect = "/"
+	}
+
+	now := time.Now()
+	token, err := o.sign(stateClaims{
+		Nonce:    nonce,
+		Redirect: redirect,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        state,
+			Audience:  jwt.ClaimStrings{stateAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(loginTimeout)),
+		},
+	})
+	if err != nil {
+		o.respond(w, logging.Error, http.StatusInternalServerError, "Failed to start login: %s", err)
+		return
+	}
+	o.setCookie(w, stateCookieName, token, loginTimeout)
+	http.Redirect(w, r, o.oauth.AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce)), http.StatusFound)
+}
+
+// Callback is the GET /auth/callback route the provider redirects users to
+// once they've logged in. It verifies their ID token and starts their
+// session.
+func (o *OIDC) Callback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if errCode := query.Get("error"); errCode != "" {
+		o.respond(w, logging.Warn, http.StatusUnauthorized, "Login failed: %s %s", errCode, query.Get("error_description"))
+		return
+	}
+	cookie, err := r.Cookie(stateCookieName)
+	if err != nil {
+		o.respond(w, logging.Warn, http.StatusBadRequest, "Login failed: no login in progress")
+		return
+	}
+	var state stateClaims
+	if err := o.parse(cookie.Value, stateAudience, &state); err != nil {
+		o.respond(w, logging.Warn, http.StatusBadRequest, "Login failed: invalid login state: %s", err)
+		return
+	}
+	if query.Get("state") != state.ID {
+		o.respond(w, logging.Warn, http.StatusBadRequest, "Login failed: state doesn't match")
+		return
+	}
+	o.setCookie(w, stateCookieName, "", -1)
+
+	ctx := context.WithValue(r.Context(), oauth2.HTTPClient, o.httpClient)
+	token, err := o.oauth.Exchange(ctx, query.Get("code"))
+	if err != nil {
+		o.respond(w, logging.Warn, http.StatusUnauthorized, "Login failed: exchanging code: %s", err)
+		return
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		o.respond(w, logging.Warn, http.StatusUnauthorized, "Login failed: provider didn't return an ID token")
+		return
+	}
+	user, err := o.verifyIDToken(rawIDToken, state.Nonce)
+	if err != nil {
+		o.respond(w, logging.Warn, http.StatusUnauthorized, "Login failed: %s", err)
+		return
+	}
+
+	now := time.Now()
+	session, err := o.sign(sessionClaims{
+		Groups: user.Groups,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.Name,
+			Audience:  jwt.ClaimStrings{sessionAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(o.cfg.SessionDuration)),
+		},
+	})
+	if err != nil {
+		o.respond(w, logging.Error, http.StatusInternalServerError, "Login failed: %s", err)
+		return
+	}
+	o.setCookie(w, SessionCookieName, session, o.cfg.SessionDuration)
+	o.logger.Info("%s logged in to the UI", user.Name)
+	http.Redirect(w, r, o.basePath()+state.Redirect, http.StatusFound)
+}
+
+// Logout is the GET /auth/logout route. It ends the user's session.
+func (o *OIDC) Logout(w http.ResponseWriter, _ *http.Request) {
+	o.setCookie(w, SessionCookieName, "", -1)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "Logged out of Atlantis.")
+}
+
+// verifyIDToken verifies the ID token's signature, issuer, audience, expiry
+// and nonce and returns the user it's for.
+func (o *OIDC) verifyIDToken(raw string, nonce string) (User, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, o.idTokenKey,
+		jwt.WithValidMethods(idTokenMethods),
+		jwt.WithIssuer(o.issuer),
+		jwt.WithAudience(o.cfg.ClientID))
+	if err != nil {
+		return User{}, errors.Wrap(err, "invalid ID token")
+	}
+	if _, ok := claims["exp"]; !ok {
+		return User{}, errors.New("invalid ID token: no expiry")
+	}
+	if claims["nonce"] != nonce {
+		return User{}, errors.New("invalid ID token: nonce doesn't match")
+	}
+
+	name, _ := claims[o.cfg.UsernameClaim].(string)
+	if name == "" {
+		return User{}, fmt.Errorf("ID token has no %q claim", o.cfg.UsernameClaim)
+	}
+	user := User{Name: name}
+	switch groups := claims[o.cfg.GroupsClaim].(type) {
+	case string:
+		user.Groups = []string{groups}
+	case []interface{}:
+		for _, group := range groups {
+			if g, ok := group.(string); ok {
+				user.Groups = append(user.Groups, g)
+			}
+		}
+	}
+	return user, nil
+}
+
+// idTokenKey returns the provider's key that signed token. The provider's
+// keys are fetched again if it's not known, in case they were rotated.
+func (o *OIDC) idTokenKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	o.keysMu.Lock()
+	defer o.keysMu.Unlock()
+
+	if key := o.findKey(kid); key != nil {
+		return key, nil
+	}
+	keys, err := o.fetchKeys()
+	if err != nil {
+		return nil, err
+	}
+	o.keys = keys
+	if key := o.findKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("no signing key %q", kid)
+}
+
+// findKey returns the key with ID kid. Tokens without a key ID can only be
+// verified if the provider has a single key.
+func (o *OIDC) findKey(kid string) interface{} {
+	if kid == "" && len(o.keys) == 1 {
+		for _, key := range o.keys {
+			return key
+		}
+	}
+	return o.keys[kid]
+}
+
+// jsonWebKey is a public key in the provider's JSON Web Key Set.
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA keys.
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC keys.
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetchKeys returns the provider's signing keys by key ID. Keys of types
+// that aren't supported are skipped.
+func (o *OIDC) fetchKeys() (map[string]interface{}, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(o.httpClient, o.jwksURL, &jwks); err != nil {
+		return nil, errors.Wrap(err, "fetching OIDC provider's keys")
+	}
+	keys := make(map[string]interface{})
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			o.logger.Warn("skipping OIDC provider's key %q: %s", k.Kid, err)
+			continue
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// publicKey returns the RSA or ECDSA key k is. It returns nil if k is of
+// another type.
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (o *OIDC) sign(claims jwt.Claims) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(o.secret)
+}
+
+// parse verifies a token signed by sign.
+func (o *OIDC) parse(token string, audience string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return o.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(audience))
+	return err
+}
+
+// setCookie sets the cookie name on w. If maxAge is negative, the cookie is
+// deleted.
+func (o *OIDC) setCookie(w http.ResponseWriter, name string, value string, maxAge time.Duration) {
+	maxAgeSecs := int(maxAge.Seconds())
+	if maxAge < 0 {
+		maxAgeSecs = -1
+	}
+	path := o.basePath()
+	if path == "" {
+		path = "/"
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		MaxAge:   maxAgeSecs,
+		Secure:   o.cfg.BaseURL.Scheme == "https",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (o *OIDC) basePath() string {
+	return strings.TrimSuffix(o.cfg.BaseURL.Path, "/")
+}
+
+func (o *OIDC) respond(w http.ResponseWriter, lvl logging.LogLevel, responseCode int, format string, args ...interface{}) {
+	response := fmt.Sprintf(format, args...)
+	o.logger.Log(lvl, response)
+	w.WriteHeader(responseCode)
+	fmt.Fprintln(w, response)
+}
+
+// isLocalPath returns true if path is a path on Atlantis, and not a URL
+// that could redirect users to another site.
+func isLocalPath(path string) bool {
+	return strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "//") && !strings.HasPrefix(path, "/\\")
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func getJSON(client *http.Client, endpoint string, v interface{}) error {
+	resp, err := client.Get(endpoint) // nolint: gosec
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint: errcheck
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", endpoint, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
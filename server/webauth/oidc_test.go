@@ -0,0 +1,317 @@
+package webauth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/webauth"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+// stubProvider is a local OIDC provider. It issues ID tokens with the claims
+// registered for each authorization code.
+type stubProvider struct {
+	*httptest.Server
+	key   *rsa.PrivateKey
+	codes map[string]jwt.MapClaims
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Ok(t, err)
+	p := &stubProvider{key: key, codes: make(map[string]jwt.MapClaims)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{ // nolint: errcheck
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{ // nolint: errcheck
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		claims, ok := p.codes[r.FormValue("code")]
+		if clientID != "atlantis" || clientSecret != "secret" || !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"}) // nolint: errcheck
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{ // nolint: errcheck
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"id_token":     p.sign(t, p.key, claims),
+		})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *stubProvider) sign(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "key1"
+	signed, err := token.SignedString(key)
+	Ok(t, err)
+	return signed
+}
+
+func newOIDC(t *testing.T, p *stubProvider) *webauth.OIDC {
+	baseURL, err := url.Parse("https://atlantis.example.com/basepath")
+	Ok(t, err)
+	o, err := webauth.NewOIDC(webauth.OIDCConfig{
+		IssuerURL:     p.URL,
+		ClientID:      "atlantis",
+		ClientSecret:  "secret",
+		BaseURL:       baseURL,
+		Scopes:        []string{"openid", "email", "groups"},
+		UsernameClaim: "email",
+		GroupsClaim:   "groups",
+	}, p.Client(), logging.NewNoopLogger(t))
+	Ok(t, err)
+	return o
+}
+
+// login logs in to o with the provider. The ID token has the claims returned
+// by claims, which is passed the nonce of the login.
+func login(t *testing.T, o *webauth.OIDC, p *stubProvider, redirect string, claims func(nonce string) jwt.MapClaims) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	o.Login(w, httptest.NewRequest("GET", "/auth/login?redirect="+url.QueryEscape(redirect), nil))
+	Equals(t, http.StatusFound, w.Code)
+	authURL, err := url.Parse(w.Header().Get("Location"))
+	Ok(t, err)
+	Equals(t, p.URL+"/authorize", authURL.Scheme+"://"+authURL.Host+authURL.Path)
+	Equals(t, "https://atlantis.example.com/basepath/auth/callback", authURL.Query().Get("redirect_uri"))
+	p.codes["code"] = claims(authURL.Query().Get("nonce"))
+
+	callback := httptest.NewRequest("GET", "/auth/callback?code=code&state="+authURL.Query().Get("state"), nil)
+	for _, c := range w.Result().Cookies() {
+		callback.AddCookie(c)
+	}
+	w = httptest.NewRecorder()
+	o.Callback(w, callback)
+	return w
+}
+
+func sessionCookie(w *httptest.ResponseRecorder) *http.Cookie {
+	for _, c := range w.Result().Cookies() {
+		if c.Name == webauth.SessionCookieName {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestOIDC_Login(t *testing.T) {
+	p := newStubProvider(t)
+	validClaims := func(nonce string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":    p.URL,
+			"aud":    "atlantis",
+			"sub":    "1234",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"nonce":  nonce,
+			"email":  "alice@example.com",
+			"groups": []string{"platform", "admins"},
+		}
+	}
+	withClaim := func(name string, value interface{}) func(string) jwt.MapClaims {
+		return func(nonce string) jwt.MapClaims {
+			claims := validClaims(nonce)
+			if value == nil {
+				delete(claims, name)
+			} else {
+				claims[name] = value
+			}
+			return claims
+		}
+	}
+
+	cases := []struct {
+		description string
+		claims      func(nonce string) jwt.MapClaims
+		expErr      string
+	}{
+		{
+			description: "valid",
+			claims:      validClaims,
+		},
+		{
+			description: "wrong issuer",
+			claims:      withClaim("iss", "https://other.example.com"),
+			expErr:      "invalid ID token: token has invalid claims: token has invalid issuer",
+		},
+		{
+			description: "wrong audience",
+			claims:      withClaim("aud", "other"),
+			expErr:      "invalid ID token: token has invalid claims: token has invalid audience",
+		},
+		{
+			description: "expired",
+			claims:      withClaim("exp", time.Now().Add(-time.Hour).Unix()),
+			expErr:      "invalid ID token: token has invalid claims: token is expired",
+		},
+		{
+			description: "no expiry",
+			claims:      withClaim("exp", nil),
+			expErr:      "invalid ID token: no expiry",
+		},
+		{
+			description: "wrong nonce",
+			claims:      withClaim("nonce", "other"),
+			expErr:      "invalid ID token: nonce doesn't match",
+		},
+		{
+			description: "no username",
+			claims:      withClaim("email", nil),
+			expErr:      `ID token has no "email" claim`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			o := newOIDC(t, p)
+			w := login(t, o, p, "/lock?id=1", c.claims)
+			if c.expErr != "" {
+				ResponseContains(t, w, http.StatusUnauthorized, c.expErr)
+				Assert(t, sessionCookie(w) == nil, "exp no session")
+				return
+			}
+			Equals(t, http.StatusFound, w.Code)
+			Equals(t, "/basepath/lock?id=1", w.Header().Get("Location"))
+
+			cookie := sessionCookie(w)
+			Assert(t, cookie != nil, "exp session")
+			Equals(t, "/basepath", cookie.Path)
+			Equals(t, true, cookie.Secure)
+			Equals(t, true, cookie.HttpOnly)
+			r := httptest.NewRequest("GET", "/", nil)
+			r.AddCookie(cookie)
+			user, ok := o.Authenticate(r)
+			Equals(t, true, ok)
+			Equals(t, webauth.User{Name: "alice@example.com", Groups: []string{"platform", "admins"}}, user)
+		})
+	}
+}
+
+func TestOIDC_LoginRedirectsToAtlantisOnly(t *testing.T) {
+	p := newStubProvider(t)
+	o := newOIDC(t, p)
+	w := login(t, o, p, "//evil.example.com", func(nonce string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   p.URL,
+			"aud":   "atlantis",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": nonce,
+			"email": "alice@example.com",
+		}
+	})
+	Equals(t, http.StatusFound, w.Code)
+	Equals(t, "/basepath/", w.Header().Get("Location"))
+}
+
+func TestOIDC_CallbackErrors(t *testing.T) {
+	p := newStubProvider(t)
+	o := newOIDC(t, p)
+
+	t.Run("provider error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		o.Callback(w, httptest.NewRequest("GET", "/auth/callback?error=access_denied&error_description=denied", nil))
+		ResponseContains(t, w, http.StatusUnauthorized, "Login failed: access_denied denied")
+	})
+	t.Run("no login in progress", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		o.Callback(w, httptest.NewRequest("GET", "/auth/callback?code=code&state=state", nil))
+		ResponseContains(t, w, http.StatusBadRequest, "Login failed: no login in progress")
+	})
+	t.Run("state doesn't match", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		o.Login(w, httptest.NewRequest("GET", "/auth/login", nil))
+		r := httptest.NewRequest("GET", "/auth/callback?code=code&state=other", nil)
+		for _, c := range w.Result().Cookies() {
+			r.AddCookie(c)
+		}
+		w = httptest.NewRecorder()
+		o.Callback(w, r)
+		ResponseContains(t, w, http.StatusBadRequest, "Login failed: state doesn't match")
+	})
+	t.Run("signed by another key", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		Ok(t, err)
+		w := httptest.NewRecorder()
+		o.Login(w, httptest.NewRequest("GET", "/auth/login", nil))
+		authURL, err := url.Parse(w.Header().Get("Location"))
+		Ok(t, err)
+		// Swap the provider's key only while signing.
+		key := p.key
+		p.key = other
+		defer func() { p.key = key }()
+		p.codes["code"] = jwt.MapClaims{
+			"iss":   p.URL,
+			"aud":   "atlantis",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": authURL.Query().Get("nonce"),
+			"email": "alice@example.com",
+		}
+		r := httptest.NewRequest("GET", "/auth/callback?code=code&state="+authURL.Query().Get("state"), nil)
+		for _, c := range w.Result().Cookies() {
+			r.AddCookie(c)
+		}
+		w = httptest.NewRecorder()
+		o.Callback(w, r)
+		ResponseContains(t, w, http.StatusUnauthorized, "invalid ID token: token signature is invalid")
+	})
+}
+
+func TestOIDC_Authenticate(t *testing.T) {
+	p := newStubProvider(t)
+	o := newOIDC(t, p)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	_, ok := o.Authenticate(r)
+	Equals(t, false, ok)
+
+	// Sessions signed by another Atlantis aren't valid.
+	other := newOIDC(t, p)
+	w := login(t, other, p, "/", func(nonce string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   p.URL,
+			"aud":   "atlantis",
+			"exp":   time.Now().Add(time.Hour).Unix(),
+			"nonce": nonce,
+			"email": "alice@example.com",
+		}
+	})
+	r.AddCookie(sessionCookie(w))
+	_, ok = other.Authenticate(r)
+	Equals(t, true, ok)
+	_, ok = o.Authenticate(r)
+	Equals(t, false, ok)
+
+	// Logging out clears the session.
+	w = httptest.NewRecorder()
+	o.Logout(w, httptest.NewRequest("GET", "/auth/logout", nil))
+	Equals(t, -1, sessionCookie(w).MaxAge)
+}
@@ -0,0 +1,98 @@
+// Package webauth authenticates and authorizes the users of the Atlantis UI.
+package webauth
+
+import (
+	"context"
+	"net/http"
+)
+
+// Permission is an action in the UI that can be restricted to some groups.
+type Permission int
+
+const (
+	// ViewPermission allows viewing the UI: locks, jobs and the dashboard.
+	ViewPermission Permission = iota
+	// DiscardLockPermission allows discarding locks, and with them their
+	// plans, and cancelling jobs.
+	DiscardLockPermission
+	// ApplyLockPermission allows creating and deleting the global apply lock.
+	ApplyLockPermission
+)
+
+func (p Permission) String() string {
+	switch p {
+	case DiscardLockPermission:
+		return "discard locks or cancel jobs"
+	case ApplyLockPermission:
+		return "toggle the apply lock"
+	}
+	return "view"
+}
+
+// User is a user logged in to the UI.
+type User struct {
+	Name   string
+	Groups []string
+}
+
+type userKey struct{}
+
+// WithUser returns a copy of ctx carrying user.
+func WithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// Username returns the name of the user making r. It's the OIDC user if
+// they logged in with OIDC, otherwise the basic auth username. It's empty if
+// the UI doesn't require logging in.
+func Username(r *http.Request) string {
+	if user, ok := r.Context().Value(userKey{}).(User); ok {
+		return user.Name
+	}
+	user, _, _ := r.BasicAuth()
+	return user
+}
+
+// GroupRules are the groups whose users have each permission.
+type GroupRules struct {
+	// View are the groups that can view the UI. If empty, every user can.
+	View []string
+	// DiscardLock are the groups that can discard locks and cancel jobs. If
+	// empty, every user that can view the UI can.
+	DiscardLock []string
+	// ApplyLock are the groups that can toggle the apply lock. If empty,
+	// every user that can view the UI can.
+	ApplyLock []string
+}
+
+// Allows returns true if user has perm. Users that can discard locks or toggle
+// the apply lock can also view the UI.
+func (g GroupRules) Allows(user User, perm Permission) bool {
+	switch perm {
+	case DiscardLockPermission:
+		if len(g.DiscardLock) == 0 {
+			return g.Allows(user, ViewPermission)
+		}
+		return isMember(user, g.DiscardLock)
+	case ApplyLockPermission:
+		if len(g.ApplyLock) == 0 {
+			return g.Allows(user, ViewPermission)
+		}
+		return isMember(user, g.ApplyLock)
+	}
+	return len(g.View) == 0 ||
+		isMember(user, g.View) ||
+		isMember(user, g.DiscardLock) ||
+		isMember(user, g.ApplyLock)
+}
+
+func isMember(user User, groups []string) bool {
+	for _, group := range groups {
+		for _, userGroup := range user.Groups {
+			if group == userGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package webauth_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/webauth"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestGroupRules_Allows(t *testing.T) {
+	viewer := webauth.User{Name: "viewer", Groups: []string{"viewers"}}
+	admin := webauth.User{Name: "admin", Groups: []string{"admins"}}
+	other := webauth.User{Name: "other", Groups: []string{"other"}}
+	perms := []webauth.Permission{webauth.ViewPermission, webauth.DiscardLockPermission, webauth.ApplyLockPermission}
+
+	cases := []struct {
+		description string
+		rules       webauth.GroupRules
+		user        webauth.User
+		exp         []bool
+	}{
+		{
+			description: "no rules",
+			user:        other,
+			exp:         []bool{true, true, true},
+		},
+		{
+			description: "viewer",
+			rules:       webauth.GroupRules{View: []string{"viewers"}, DiscardLock: []string{"admins"}},
+			user:        viewer,
+			exp:         []bool{true, false, true},
+		},
+		{
+			description: "admin can also view",
+			rules:       webauth.GroupRules{View: []string{"viewers"}, DiscardLock: []string{"admins"}, ApplyLock: []string{"admins"}},
+			user:        admin,
+			exp:         []bool{true, true, true},
+		},
+		{
+			description: "not in any group",
+			rules:       webauth.GroupRules{View: []string{"viewers"}},
+			user:        other,
+			exp:         []bool{false, false, false},
+		},
+		{
+			description: "only mutations restricted",
+			rules:       webauth.GroupRules{ApplyLock: []string{"admins"}},
+			user:        other,
+			exp:         []bool{true, true, false},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			for i, perm := range perms {
+				Equals(t, c.exp[i], c.rules.Allows(c.user, perm))
+			}
+		})
+	}
+}
+
+func TestUsername(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	Equals(t, "", webauth.Username(r))
+
+	r.SetBasicAuth("atlantis", "password")
+	Equals(t, "atlantis", webauth.Username(r))
+
+	r = r.WithContext(webauth.WithUser(r.Context(), webauth.User{Name: "alice@example.com"}))
+	Equals(t, "alice@example.com", webauth.Username(r))
+}